@@ -77,7 +77,7 @@ class Settings(BaseSettings):
     cache_ttl_transcription: int = 1800
     cache_ttl_vqa: int = 86400  # 24 hours for VQA responses
 
-    # Gemini API (VQA)
+    # Gemini API (VQA, text simplification)
     gemini_api_key: str = ""
     enable_vqa: bool = True
 
@@ -91,6 +91,7 @@ class Settings(BaseSettings):
         "ocr": "paddleocr-2.7.0.3",
         "whisper": "base-20231117",
         "vqa": "gemini-1.5-flash",
+        "simplify": "gemini-1.5-flash",
     }
 
     @cached_property
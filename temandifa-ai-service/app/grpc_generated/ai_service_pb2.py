@@ -14,7 +14,7 @@ _sym_db = _symbol_database.Default()
 
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x10\x61i_service.proto\x12\taiservice\"4\n\x0cImageRequest\x12\x12\n\nimage_data\x18\x01 \x01(\x0c\x12\x10\n\x08\x66ilename\x18\x02 \x01(\t\"F\n\x0c\x41udioRequest\x12\x12\n\naudio_data\x18\x01 \x01(\x0c\x12\x10\n\x08\x66ilename\x18\x02 \x01(\t\x12\x10\n\x08language\x18\x03 \x01(\t\"D\n\nVQARequest\x12\x12\n\nimage_data\x18\x01 \x01(\x0c\x12\x10\n\x08\x66ilename\x18\x02 \x01(\t\x12\x10\n\x08question\x18\x03 \x01(\t\"?\n\x0bVQAResponse\x12\x0f\n\x07success\x18\x01 \x01(\x08\x12\x0f\n\x07message\x18\x02 \x01(\t\x12\x0e\n\x06\x61nswer\x18\x03 \x01(\t\"a\n\x11\x44\x65tectionResponse\x12\x0f\n\x07success\x18\x01 \x01(\x08\x12\x0f\n\x07message\x18\x02 \x01(\t\x12*\n\x07objects\x18\x03 \x03(\x0b\x32\x19.aiservice.DetectedObject\"A\n\x0e\x44\x65tectedObject\x12\r\n\x05label\x18\x01 \x01(\t\x12\x12\n\nconfidence\x18\x02 \x01(\x02\x12\x0c\n\x04\x62\x62ox\x18\x03 \x03(\x02\"D\n\nOCRRequest\x12\x12\n\nimage_data\x18\x01 \x01(\x0c\x12\x10\n\x08\x66ilename\x18\x02 \x01(\t\x12\x10\n\x08language\x18\x03 \x01(\t\"e\n\x0bOCRResponse\x12\x0f\n\x07success\x18\x01 \x01(\x08\x12\x0f\n\x07message\x18\x02 \x01(\t\x12\x11\n\tfull_text\x18\x03 \x01(\t\x12!\n\x05lines\x18\x04 \x03(\x0b\x32\x12.aiservice.OCRLine\"9\n\x07OCRLine\x12\x0c\n\x04text\x18\x01 \x01(\t\x12\x12\n\nconfidence\x18\x02 \x01(\x02\x12\x0c\n\x04\x62\x62ox\x18\x03 \x03(\x02\"Z\n\x15transcriptionResponse\x12\x0f\n\x07success\x18\x01 \x01(\x08\x12\x0c\n\x04text\x18\x02 \x01(\t\x12\x10\n\x08language\x18\x03 \x01(\t\x12\x10\n\x08\x64uration\x18\x04 \x01(\x02\x32\xa9\x02\n\tAIService\x12\x46\n\rDetectObjects\x12\x17.aiservice.ImageRequest\x1a\x1c.aiservice.DetectionResponse\x12<\n\x0b\x45xtractText\x12\x15.aiservice.OCRRequest\x1a\x16.aiservice.OCRResponse\x12L\n\x0fTranscribeAudio\x12\x17.aiservice.AudioRequest\x1a .aiservice.transcriptionResponse\x12H\n\x17VisualQuestionAnswering\x12\x15.aiservice.VQARequest\x1a\x16.aiservice.VQAResponseB+Z)temandifa-backend/internal/grpc/aiserviceb\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x10\x61i_service.proto\x12\taiservice\"4\n\x0cImageRequest\x12\x12\n\nimage_data\x18\x01 \x01(\x0c\x12\x10\n\x08\x66ilename\x18\x02 \x01(\t\"F\n\x0c\x41udioRequest\x12\x12\n\naudio_data\x18\x01 \x01(\x0c\x12\x10\n\x08\x66ilename\x18\x02 \x01(\t\x12\x10\n\x08language\x18\x03 \x01(\t\"D\n\nVQARequest\x12\x12\n\nimage_data\x18\x01 \x01(\x0c\x12\x10\n\x08\x66ilename\x18\x02 \x01(\t\x12\x10\n\x08question\x18\x03 \x01(\t\"?\n\x0bVQAResponse\x12\x0f\n\x07success\x18\x01 \x01(\x08\x12\x0f\n\x07message\x18\x02 \x01(\t\x12\x0e\n\x06\x61nswer\x18\x03 \x01(\t\"a\n\x11\x44\x65tectionResponse\x12\x0f\n\x07success\x18\x01 \x01(\x08\x12\x0f\n\x07message\x18\x02 \x01(\t\x12*\n\x07objects\x18\x03 \x03(\x0b\x32\x19.aiservice.DetectedObject\"A\n\x0e\x44\x65tectedObject\x12\r\n\x05label\x18\x01 \x01(\t\x12\x12\n\nconfidence\x18\x02 \x01(\x02\x12\x0c\n\x04\x62\x62ox\x18\x03 \x03(\x02\"D\n\nOCRRequest\x12\x12\n\nimage_data\x18\x01 \x01(\x0c\x12\x10\n\x08\x66ilename\x18\x02 \x01(\t\x12\x10\n\x08language\x18\x03 \x01(\t\"e\n\x0bOCRResponse\x12\x0f\n\x07success\x18\x01 \x01(\x08\x12\x0f\n\x07message\x18\x02 \x01(\t\x12\x11\n\tfull_text\x18\x03 \x01(\t\x12!\n\x05lines\x18\x04 \x03(\x0b\x32\x12.aiservice.OCRLine\"9\n\x07OCRLine\x12\x0c\n\x04text\x18\x01 \x01(\t\x12\x12\n\nconfidence\x18\x02 \x01(\x02\x12\x0c\n\x04\x62\x62ox\x18\x03 \x03(\x02\"Z\n\x15transcriptionResponse\x12\x0f\n\x07success\x18\x01 \x01(\x08\x12\x0c\n\x04text\x18\x02 \x01(\t\x12\x10\n\x08language\x18\x03 \x01(\t\x12\x10\n\x08\x64uration\x18\x04 \x01(\x02\"Y\n\x0fSimplifyRequest\x12\x0c\n\x04text\x18\x01 \x01(\t\x12\x10\n\x08language\x18\x02 \x01(\t\x12\r\n\x05level\x18\x03 \x01(\t\x12\x17\n\x0fmax_chunk_chars\x18\x04 \x01(\x05\"f\n\x10SimplifyResponse\x12\x0f\n\x07success\x18\x01 \x01(\x08\x12\x0f\n\x07message\x18\x02 \x01(\t\x12\x0c\n\x04text\x18\x03 \x01(\t\x12\x0e\n\x06\x63hunks\x18\x04 \x03(\t\x12\x12\n\nsimplified\x18\x05 \x01(\x08\x32\xf2\x02\n\tAIService\x12\x46\n\rDetectObjects\x12\x17.aiservice.ImageRequest\x1a\x1c.aiservice.DetectionResponse\x12<\n\x0b\x45xtractText\x12\x15.aiservice.OCRRequest\x1a\x16.aiservice.OCRResponse\x12L\n\x0fTranscribeAudio\x12\x17.aiservice.AudioRequest\x1a .aiservice.transcriptionResponse\x12H\n\x17VisualQuestionAnswering\x12\x15.aiservice.VQARequest\x1a\x16.aiservice.VQAResponse\x12G\n\x0cSimplifyText\x12\x1a.aiservice.SimplifyRequest\x1a\x1b.aiservice.SimplifyResponseB+Z)temandifa-backend/internal/grpc/aiserviceb\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
@@ -42,6 +42,10 @@ if _descriptor._USE_C_DESCRIPTORS == False:
   _globals['_OCRLINE']._serialized_end=688
   _globals['_TRANSCRIPTIONRESPONSE']._serialized_start=690
   _globals['_TRANSCRIPTIONRESPONSE']._serialized_end=780
-  _globals['_AISERVICE']._serialized_start=783
-  _globals['_AISERVICE']._serialized_end=1080
+  _globals['_SIMPLIFYREQUEST']._serialized_start=782
+  _globals['_SIMPLIFYREQUEST']._serialized_end=871
+  _globals['_SIMPLIFYRESPONSE']._serialized_start=873
+  _globals['_SIMPLIFYRESPONSE']._serialized_end=975
+  _globals['_AISERVICE']._serialized_start=978
+  _globals['_AISERVICE']._serialized_end=1348
 # @@protoc_insertion_point(module_scope)
@@ -34,6 +34,11 @@ class AIServiceStub(object):
                 request_serializer=ai__service__pb2.VQARequest.SerializeToString,
                 response_deserializer=ai__service__pb2.VQAResponse.FromString,
                 )
+        self.SimplifyText = channel.unary_unary(
+                '/aiservice.AIService/SimplifyText',
+                request_serializer=ai__service__pb2.SimplifyRequest.SerializeToString,
+                response_deserializer=ai__service__pb2.SimplifyResponse.FromString,
+                )
 
 
 class AIServiceServicer(object):
@@ -67,6 +72,13 @@ class AIServiceServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def SimplifyText(self, request, context):
+        """Simplifies text to an easy reading level and chunks it for TTS playback
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
 
 def add_AIServiceServicer_to_server(servicer, server):
     rpc_method_handlers = {
@@ -90,6 +102,11 @@ def add_AIServiceServicer_to_server(servicer, server):
                     request_deserializer=ai__service__pb2.VQARequest.FromString,
                     response_serializer=ai__service__pb2.VQAResponse.SerializeToString,
             ),
+            'SimplifyText': grpc.unary_unary_rpc_method_handler(
+                    servicer.SimplifyText,
+                    request_deserializer=ai__service__pb2.SimplifyRequest.FromString,
+                    response_serializer=ai__service__pb2.SimplifyResponse.SerializeToString,
+            ),
     }
     generic_handler = grpc.method_handlers_generic_handler(
             'aiservice.AIService', rpc_method_handlers)
@@ -167,3 +184,20 @@ class AIService(object):
             ai__service__pb2.VQAResponse.FromString,
             options, channel_credentials,
             insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def SimplifyText(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/aiservice.AIService/SimplifyText',
+            ai__service__pb2.SimplifyRequest.SerializeToString,
+            ai__service__pb2.SimplifyResponse.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
@@ -0,0 +1,205 @@
+"""
+Text Simplification Service using Google Gemini.
+Rewrites extracted text at an easy reading level and chunks it into
+sentence-aligned pieces that are comfortable to listen to with TTS.
+"""
+
+import asyncio
+import logging
+import re
+from concurrent.futures import ThreadPoolExecutor
+
+import google.generativeai as genai
+from tenacity import before_log, retry, stop_after_attempt, wait_exponential
+
+from app.core import logger
+from app.core.config import settings
+from app.core.metrics import track_inference
+from app.core.performance.circuit_breaker import CircuitBreakerOpenError
+from app.services.vqa_service import gemini_circuit_breaker
+
+DEFAULT_MAX_CHUNK_CHARS = 300
+
+# Sentence ends: terminal punctuation (with closing quotes/brackets) before whitespace
+_SENTENCE_END = re.compile(r"(?<=[.!?…])[\"')\]]*\s+")
+# Clause boundaries used to split sentences longer than a chunk
+_CLAUSE_END = re.compile(r"(?<=[,;:])\s+")
+
+_LEVEL_INSTRUCTIONS = {
+    "easy": "Use short sentences and common words, as for a 10 year old reader.",
+    "very_easy": "Use very short sentences of at most 12 words and only everyday words, as for a 7 year old reader.",
+}
+
+_LANGUAGE_NAMES = {"id": "Indonesian", "en": "English"}
+
+
+def chunk_text(text: str, max_chars: int = DEFAULT_MAX_CHUNK_CHARS) -> list[str]:
+    """
+    Split text into chunks of whole sentences of at most max_chars.
+
+    Sentences longer than a chunk are split at clause boundaries, then at
+    word boundaries, so TTS playback never stops mid-word.
+    """
+    max_chars = max(max_chars, 40)
+    pieces: list[str] = []
+    for paragraph in re.split(r"\n\s*\n|\n", text):
+        for sentence in _SENTENCE_END.split(paragraph.strip()):
+            sentence = " ".join(sentence.split())
+            if sentence:
+                pieces.extend(_split_long(sentence, max_chars))
+
+    chunks: list[str] = []
+    current = ""
+    for piece in pieces:
+        if current and len(current) + 1 + len(piece) > max_chars:
+            chunks.append(current)
+            current = piece
+        else:
+            current = f"{current} {piece}" if current else piece
+    if current:
+        chunks.append(current)
+    return chunks
+
+
+def _split_long(sentence: str, max_chars: int) -> list[str]:
+    """Split a sentence longer than max_chars at clauses, then words."""
+    if len(sentence) <= max_chars:
+        return [sentence]
+
+    parts: list[str] = []
+    for clause in _CLAUSE_END.split(sentence):
+        if len(clause) <= max_chars:
+            parts.append(clause)
+            continue
+        current = ""
+        for word in clause.split():
+            if current and len(current) + 1 + len(word) > max_chars:
+                parts.append(current)
+                current = word
+            else:
+                current = f"{current} {word}" if current else word
+        if current:
+            parts.append(current)
+    return parts
+
+
+class SimplificationService:
+    """
+    Text simplification for TTS playback.
+    Features:
+    - Gemini rewrite at a chosen reading level
+    - Shares the Gemini circuit breaker with VQA (same API quota)
+    - Falls back to chunking the original text when Gemini is unavailable
+    """
+
+    def __init__(self):
+        self.model = None
+        self.is_ready = False
+        self.executor = ThreadPoolExecutor(max_workers=settings.ai_worker_threads)
+
+    def load(self):
+        """Initialize the Gemini model; without an API key text is only chunked."""
+        api_key = settings.gemini_api_key
+
+        if not api_key:
+            logger.warning(
+                "GEMINI_API_KEY not configured, text will be chunked without simplification"
+            )
+            self.is_ready = False
+            return
+
+        genai.configure(api_key=api_key)
+        self.model = genai.GenerativeModel("gemini-1.5-flash")
+        self.is_ready = True
+        logger.info("SimplificationService initialized with Gemini")
+
+    def _prompt(self, text: str, language: str, level: str) -> str:
+        instruction = _LEVEL_INSTRUCTIONS.get(level, _LEVEL_INSTRUCTIONS["easy"])
+        target = _LANGUAGE_NAMES.get(language, "the same language as the text")
+        return (
+            "Rewrite the following text so it is easy to understand when read aloud "
+            f"to a blind or low-vision listener. {instruction} "
+            f"Write in {target}. Keep every fact, number, name and date; drop nothing "
+            "important and add nothing. Spell out symbols and abbreviations the way "
+            "they are spoken. Return only the rewritten text as plain sentences, "
+            "without markdown, lists or headings.\n\n"
+            f"Text:\n{text}"
+        )
+
+    @retry(
+        stop=stop_after_attempt(3),
+        wait=wait_exponential(multiplier=1, min=2, max=10),
+        before=before_log(logging.getLogger("temandifa-ai"), logging.WARNING),
+        reraise=True,
+    )
+    def _call_gemini_api(self, prompt: str) -> str:
+        """Call Gemini API with retry and circuit breaker."""
+
+        def _api_call():
+            response = self.model.generate_content(prompt)
+            return response.text
+
+        return gemini_circuit_breaker.execute(_api_call)
+
+    def simplify(
+        self, text: str, language: str = "", level: str = "easy", max_chunk_chars: int = 0
+    ) -> dict:
+        """
+        Simplify text and chunk it for TTS playback.
+
+        Args:
+            text: Text to simplify, e.g. OCR output
+            language: Optional language code of the text
+            level: Reading level, easy or very_easy
+            max_chunk_chars: Upper bound of a chunk's length
+
+        Returns:
+            Dict with the text, its chunks and whether it was simplified
+        """
+        max_chars = max_chunk_chars or DEFAULT_MAX_CHUNK_CHARS
+        simplified_text = None
+
+        if self.is_ready and self.model:
+            try:
+                simplified_text = self._call_gemini_api(
+                    self._prompt(text, language, level)
+                ).strip()
+            except CircuitBreakerOpenError:
+                logger.warning("Simplification skipped: Circuit breaker open")
+            except Exception as e:
+                error_msg = str(e).lower()
+                if "429" in error_msg or "quota" in error_msg or "rate" in error_msg:
+                    logger.error("Gemini API rate limit hit", error=str(e))
+                    gemini_circuit_breaker.record_failure()
+                logger.warning("Simplification failed, chunking original text", error=str(e))
+
+        # Listening to the original in chunks still beats a failed request
+        if not simplified_text:
+            return {
+                "text": text,
+                "chunks": chunk_text(text, max_chars),
+                "simplified": False,
+            }
+        return {
+            "text": simplified_text,
+            "chunks": chunk_text(simplified_text, max_chars),
+            "simplified": True,
+        }
+
+    @track_inference("simplify")
+    async def simplify_async(
+        self, text: str, language: str = "", level: str = "easy", max_chunk_chars: int = 0
+    ) -> dict:
+        """Async version of simplify."""
+        loop = asyncio.get_event_loop()
+        return await loop.run_in_executor(
+            self.executor, self.simplify, text, language, level, max_chunk_chars
+        )
+
+    def get_status(self) -> dict:
+        """Get service status for health checks."""
+        return {
+            "ready": self.is_ready,
+            "api_key_configured": bool(settings.gemini_api_key),
+            "circuit_breaker": gemini_circuit_breaker.get_status(),
+        }
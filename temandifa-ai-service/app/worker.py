@@ -15,6 +15,7 @@ import grpc
 from app.core import logger
 from app.grpc_generated import ai_service_pb2, ai_service_pb2_grpc
 from app.services.ocr_service import OCRService
+from app.services.simplification_service import SimplificationService
 from app.services.transcription_service import TranscriptionService
 from app.services.vqa_service import VQAService
 from app.services.yolo_service import YoloService
@@ -29,13 +30,14 @@ model_status = {
     "ocr": {"ready": False, "error": None},
     "whisper": {"ready": False, "error": None},
     "vqa": {"ready": False, "error": None},
+    "simplify": {"ready": False, "error": None},
 }
 
 
 class AIService(ai_service_pb2_grpc.AIServiceServicer):
     """
     gRPC Implementation of AI Service.
-    Wraps existing services: YOLO, OCR, Whisper, VQA.
+    Wraps existing services: YOLO, OCR, Whisper, VQA, simplification.
     Supports request ID tracing via gRPC metadata.
     """
 
@@ -45,11 +47,13 @@ class AIService(ai_service_pb2_grpc.AIServiceServicer):
         ocr_service: OCRService,
         transcription_service: TranscriptionService,
         vqa_service: VQAService,
+        simplification_service: SimplificationService,
     ):
         self.yolo_service = yolo_service
         self.ocr_service = ocr_service
         self.transcription_service = transcription_service
         self.vqa_service = vqa_service
+        self.simplification_service = simplification_service
 
     def _get_request_id(self, context: grpc.aio.ServicerContext) -> str:
         """Extract request ID from gRPC metadata for tracing."""
@@ -170,6 +174,36 @@ class AIService(ai_service_pb2_grpc.AIServiceServicer):
             logger.error(f"gRPC VQA failed: {e}", request_id=request_id)
             return ai_service_pb2.VQAResponse(success=False, message=str(e), answer="")
 
+    async def SimplifyText(
+        self, request: ai_service_pb2.SimplifyRequest, context: grpc.aio.ServicerContext
+    ) -> ai_service_pb2.SimplifyResponse:
+        request_id = self._get_request_id(context)
+        try:
+            result = await self.simplification_service.simplify_async(
+                request.text,
+                request.language,
+                request.level or "easy",
+                request.max_chunk_chars,
+            )
+
+            logger.debug(
+                "Simplification completed",
+                request_id=request_id,
+                simplified=result["simplified"],
+                chunks=len(result["chunks"]),
+            )
+
+            return ai_service_pb2.SimplifyResponse(
+                success=True,
+                message="Text simplified" if result["simplified"] else "Text chunked",
+                text=result["text"],
+                chunks=result["chunks"],
+                simplified=result["simplified"],
+            )
+        except Exception as e:
+            logger.error(f"gRPC Simplification failed: {e}", request_id=request_id)
+            return ai_service_pb2.SimplifyResponse(success=False, message=str(e))
+
 
 def cleanup_temp_files():
     """Clean up temporary files created during processing."""
@@ -199,6 +233,7 @@ async def serve():
     ocr_service = OCRService()
     transcription_service = TranscriptionService()
     vqa_service = VQAService()
+    simplification_service = SimplificationService()
 
     # Warm up models with status tracking
     logger.info("Warming up models in gRPC process...")
@@ -235,10 +270,24 @@ async def serve():
         model_status["vqa"]["error"] = str(e)
         logger.error(f"VQA load failed: {e}")
 
+    try:
+        simplification_service.load()
+        model_status["simplify"]["ready"] = True
+        logger.info("Simplification Loaded.")
+    except Exception as e:
+        model_status["simplify"]["error"] = str(e)
+        logger.error(f"Simplification load failed: {e}")
+
     # Create gRPC server
     _server = grpc.aio.server()
     ai_service_pb2_grpc.add_AIServiceServicer_to_server(
-        AIService(yolo_service, ocr_service, transcription_service, vqa_service),
+        AIService(
+            yolo_service,
+            ocr_service,
+            transcription_service,
+            vqa_service,
+            simplification_service,
+        ),
         _server,
     )
 
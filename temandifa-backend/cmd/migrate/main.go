@@ -0,0 +1,205 @@
+// Command migrate manages database migrations independently of server startup.
+//
+// Usage:
+//
+//	go run ./cmd/migrate [-driver postgres|sqlite] [-dir DIR] [-dsn DSN] [-lock-timeout D] <command> [arg]
+//
+// Commands:
+//
+//	up [N]          Apply all (or the next N) pending migrations
+//	down [N|all]    Roll back the last N migrations (default 1) or all of them
+//	status          List migrations and whether they are applied
+//	force VERSION   Set the version without running migrations (clears the dirty flag)
+//	create NAME     Create empty up/down files with the next version number
+//
+// The driver and DSN default to DB_DRIVER and DB_DSN, read like the server
+// reads them (APP_ENV config files, then the environment); the directory defaults to migrations (migrations/sqlite for
+// SQLite, which needs -tags sqlite). up, down and force take the same
+// advisory lock as server startup, so they never race a starting replica.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/spf13/viper"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/database"
+)
+
+func main() {
+	driver := flag.String("driver", "", "database driver: postgres or sqlite (defaults to DB_DRIVER)")
+	dir := flag.String("dir", "", "directory containing migration files (defaults to the driver's)")
+	dsn := flag.String("dsn", "", "database DSN (defaults to DB_DSN)")
+	lockTimeout := flag.Duration("lock-timeout", 5*time.Minute, "how long to wait for the migration lock")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		usage()
+		os.Exit(2)
+	}
+
+	if err := run(*driver, *dir, *dsn, *lockTimeout, flag.Arg(0), flag.Arg(1)); err != nil {
+		fmt.Fprintln(os.Stderr, "migrate:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `Usage: migrate [-driver postgres|sqlite] [-dir DIR] [-dsn DSN] [-lock-timeout D] <command> [arg]
+
+Commands:
+  up [N]          Apply all (or the next N) pending migrations
+  down [N|all]    Roll back the last N migrations (default 1) or all of them
+  status          List migrations and whether they are applied
+  force VERSION   Set the version without running migrations (clears the dirty flag)
+  create NAME     Create empty up/down files with the next version number
+
+Flags:`)
+	flag.PrintDefaults()
+}
+
+func run(driver, dir, dsn string, lockTimeout time.Duration, command, arg string) error {
+	if _, _, err := config.LoadEnv(); err != nil {
+		return err
+	}
+	if driver == "" {
+		driver = viper.GetString("DB_DRIVER")
+	}
+	if driver == "" {
+		driver = database.DriverPostgres
+	}
+	if dir == "" {
+		dir = database.MigrationsDirFor(driver)
+	}
+
+	// create doesn't need a database connection
+	if command == "create" {
+		if arg == "" {
+			return errors.New("create requires a migration name")
+		}
+		up, down, err := database.CreateMigration(dir, arg)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Created", up)
+		fmt.Println("Created", down)
+		return nil
+	}
+
+	if dsn == "" {
+		dsn = viper.GetString("DB_DSN")
+	}
+	if dsn == "" {
+		return errors.New("DB_DSN is not set (use -dsn or the environment)")
+	}
+
+	db, err := database.OpenSQL(driver, dsn)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	m, err := database.NewMigrator(db, driver, dir)
+	if err != nil {
+		return err
+	}
+	m.Log = stdoutLogger{}
+
+	if command == "status" {
+		return printStatus(m, dir)
+	}
+	return database.WithMigrationLock(context.Background(), db, driver, lockTimeout, func() error {
+		return apply(m, command, arg)
+	})
+}
+
+// apply runs a command that changes the schema version
+func apply(m *migrate.Migrate, command, arg string) error {
+	switch command {
+	case "up":
+		if arg == "" {
+			return ignoreNoChange(m.Up())
+		}
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid step count %q", arg)
+		}
+		return ignoreNoChange(m.Steps(n))
+
+	case "down":
+		if arg == "all" {
+			return ignoreNoChange(m.Down())
+		}
+		n := 1
+		if arg != "" {
+			var err error
+			if n, err = strconv.Atoi(arg); err != nil || n < 1 {
+				return fmt.Errorf("invalid step count %q", arg)
+			}
+		}
+		return ignoreNoChange(m.Steps(-n))
+
+	case "force":
+		version, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Errorf("force requires a numeric version, got %q", arg)
+		}
+		if err := m.Force(version); err != nil {
+			return err
+		}
+		fmt.Println("Forced version", version)
+		return nil
+
+	default:
+		usage()
+		return fmt.Errorf("unknown command %q", command)
+	}
+}
+
+// printStatus prints the current version and each migration's state
+func printStatus(m *migrate.Migrate, dir string) error {
+	infos, version, dirty, err := database.MigrationStatus(m, dir)
+	if err != nil {
+		return err
+	}
+
+	state := "clean"
+	if dirty {
+		state = "DIRTY (fix the schema, then run: migrate force <version>)"
+	}
+	fmt.Printf("Current version: %d (%s)\n\n", version, state)
+
+	for _, info := range infos {
+		status := "pending"
+		if info.Applied {
+			status = "applied"
+		}
+		fmt.Printf("  %06d  %-8s  %s\n", info.Version, status, info.Name)
+	}
+	return nil
+}
+
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		fmt.Println("No change")
+		return nil
+	}
+	return err
+}
+
+// stdoutLogger prints golang-migrate progress
+type stdoutLogger struct{}
+
+func (stdoutLogger) Printf(format string, v ...any) { fmt.Printf(format, v...) }
+func (stdoutLogger) Verbose() bool                  { return true }
@@ -0,0 +1,181 @@
+// Command seed fills a local or staging database with deterministic demo data:
+// an admin, regular users, and a fixed set of history entries per user.
+//
+// Usage:
+//
+//	go run ./cmd/seed [-dsn DSN] [-force]
+//
+// DB_DRIVER selects the database (build with -tags sqlite for SQLite).
+//
+// Running it again resets the demo accounts and their history to the same
+// state. It refuses to run when APP_ENV=prod (or GIN_MODE=release without
+// APP_ENV) unless -force is given.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	gormlogger "gorm.io/gorm/logger"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/models"
+)
+
+// seedPassword is shared by all demo accounts
+const seedPassword = "Temandifa#123"
+
+// seedBaseTime anchors history timestamps so every run produces identical data
+var seedBaseTime = time.Date(2025, time.January, 6, 8, 0, 0, 0, time.UTC)
+
+type seedUser struct {
+	Email    string
+	FullName string
+	Role     string
+	History  []seedHistory
+}
+
+type seedHistory struct {
+	Feature models.FeatureType
+	Source  string
+	Result  string
+}
+
+var seedUsers = []seedUser{
+	{
+		Email:    "admin@temandifa.local",
+		FullName: "Admin TemanDifa",
+		Role:     models.RoleAdmin,
+	},
+	{
+		Email:    "budi@temandifa.local",
+		FullName: "Budi Santoso",
+		Role:     models.RoleUser,
+		History: []seedHistory{
+			{models.FeatureObject, "camera_capture", "Detected: person, chair, table"},
+			{models.FeatureOCR, "camera_capture", "Apotek Sehat - Buka 24 Jam"},
+			{models.FeatureVoice, "microphone", "Tolong bacakan jadwal kereta hari ini"},
+			{models.FeatureObject, "gallery", "Detected: car, traffic light, bicycle"},
+		},
+	},
+	{
+		Email:    "siti@temandifa.local",
+		FullName: "Siti Rahmawati",
+		Role:     models.RoleUser,
+		History: []seedHistory{
+			{models.FeatureOCR, "camera_capture", "Paracetamol 500 mg - 3x sehari setelah makan"},
+			{models.FeatureOCR, "gallery", "Total: Rp 125.000"},
+			{models.FeatureObject, "camera_capture", "Detected: door, stairs"},
+		},
+	},
+	{
+		Email:    "andi@temandifa.local",
+		FullName: "Andi Pratama",
+		Role:     models.RoleUser,
+	},
+}
+
+func main() {
+	dsn := flag.String("dsn", "", "database DSN (defaults to DB_DSN)")
+	force := flag.Bool("force", false, "allow seeding when GIN_MODE=release")
+	flag.Parse()
+
+	if err := run(*dsn, *force); err != nil {
+		fmt.Fprintln(os.Stderr, "seed:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dsn string, force bool) error {
+	env, _, err := config.LoadEnv()
+	if err != nil {
+		return err
+	}
+
+	// Deployments without APP_ENV are recognized by GIN_MODE=release
+	production := env == config.EnvProd || (env == config.EnvDev && viper.GetString("GIN_MODE") == "release")
+	if production && !force {
+		return errors.New("refusing to seed a production environment (use -force to override)")
+	}
+	if dsn == "" {
+		dsn = viper.GetString("DB_DSN")
+	}
+	if dsn == "" {
+		return errors.New("DB_DSN is not set (use -dsn or the environment)")
+	}
+
+	driver := viper.GetString("DB_DRIVER")
+	if driver == "" {
+		driver = database.DriverPostgres
+	}
+	dialector, err := database.Dialector(driver, dsn)
+	if err != nil {
+		return err
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Warn),
+	})
+	if err != nil {
+		return err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(seedPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, su := range seedUsers {
+			user := models.User{
+				Email:    su.Email,
+				FullName: su.FullName,
+				Role:     su.Role,
+				Password: string(hash),
+			}
+			// Upsert by email so re-running resets the account
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "email"}},
+				DoUpdates: clause.AssignmentColumns([]string{"full_name", "role", "password", "deleted_at", "updated_at"}),
+			}).Create(&user).Error
+			if err != nil {
+				return fmt.Errorf("seed user %s: %w", su.Email, err)
+			}
+			if err := tx.Where("email = ?", su.Email).First(&user).Error; err != nil {
+				return err
+			}
+
+			// Replace history with the fixed set
+			if err := tx.Unscoped().Where("user_id = ?", user.ID).Delete(&models.History{}).Error; err != nil {
+				return err
+			}
+			for i, h := range su.History {
+				createdAt := seedBaseTime.Add(time.Duration(i) * time.Hour)
+				history := models.History{
+					CreatedAt:   createdAt,
+					UpdatedAt:   createdAt,
+					UserID:      user.ID,
+					FeatureType: h.Feature,
+					InputSource: h.Source,
+					ResultText:  h.Result,
+				}
+				if err := tx.Create(&history).Error; err != nil {
+					return fmt.Errorf("seed history for %s: %w", su.Email, err)
+				}
+			}
+
+			fmt.Printf("Seeded %-24s role=%-5s history=%d\n", su.Email, su.Role, len(su.History))
+		}
+
+		fmt.Printf("\nAll demo accounts use the password %q\n", seedPassword)
+		return nil
+	})
+}
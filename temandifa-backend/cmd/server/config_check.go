@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/logger"
+)
+
+// configCheckTimeout bounds each reachability probe
+const configCheckTimeout = 5 * time.Second
+
+// Config check outcomes; only failures make the check exit non-zero
+const (
+	checkOK   = "OK"
+	checkWarn = "WARN"
+	checkFail = "FAIL"
+)
+
+// placeholderSecrets are example values that must never reach a deployment
+var placeholderSecrets = []string{
+	"your_secure_randomly_generated_secret_key_here_32chars",
+	"changeme",
+	"secret",
+}
+
+type checkResult struct {
+	name   string
+	status string
+	detail string
+}
+
+// configChecker collects the results of a config check run
+type configChecker struct {
+	results []checkResult
+}
+
+func (cc *configChecker) add(name, status, detail string) {
+	// Keep one line per check; driver errors can span several
+	detail = strings.Join(strings.Fields(detail), " ")
+	cc.results = append(cc.results, checkResult{name: name, status: status, detail: detail})
+}
+
+func (cc *configChecker) failed() bool {
+	for _, r := range cc.results {
+		if r.status == checkFail {
+			return true
+		}
+	}
+	return false
+}
+
+// runConfigCheck loads the configuration, checks it without starting the
+// server and prints a report. It returns the process exit code: 1 when any
+// check failed, so it can gate a rolling deploy.
+func runConfigCheck() int {
+	logger.InitFromEnv()
+	defer logger.Sync()
+
+	cc := &configChecker{}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		cc.add("config", checkFail, err.Error())
+	} else {
+		cc.add("config", checkOK, fmt.Sprintf("driver=%s mode=%s", cfg.DBDriver, cfg.GinMode))
+		if cfg.JWTSecret != "" {
+			cc.checkJWTSecret("jwt_secret", "JWT_SECRET", cfg.JWTSecret)
+		}
+		for _, key := range cfg.JWTSigningKeys {
+			cc.checkJWTSecret("jwt_signing_key."+key.ID, "JWT_SIGNING_KEYS key "+key.ID, key.Secret)
+		}
+		cc.checkTimeouts(cfg)
+		cc.checkDatabase(cfg)
+		cc.checkRedis(cfg)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, r := range cc.results {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", r.status, r.name, r.detail)
+	}
+	_ = w.Flush()
+
+	if cc.failed() {
+		fmt.Println("\nConfiguration check failed")
+		return 1
+	}
+	fmt.Println("\nConfiguration check passed")
+	return 0
+}
+
+// checkJWTSecret rejects placeholders and low-variety secrets that pass the
+// length check but are easy to guess; name and setting label the result
+func (cc *configChecker) checkJWTSecret(name, setting, secret string) {
+	for _, placeholder := range placeholderSecrets {
+		if strings.EqualFold(secret, placeholder) {
+			cc.add(name, checkFail, setting+" is a placeholder value")
+			return
+		}
+	}
+
+	distinct := make(map[rune]struct{})
+	for _, r := range secret {
+		distinct[r] = struct{}{}
+	}
+	if len(distinct) < 16 {
+		cc.add(name, checkFail, fmt.Sprintf("%s uses only %d distinct characters", setting, len(distinct)))
+		return
+	}
+	cc.add(name, checkOK, fmt.Sprintf("%d characters", len(secret)))
+}
+
+// checkTimeouts flags timeouts that contradict each other
+func (cc *configChecker) checkTimeouts(cfg *config.Config) {
+	if cfg.ReadTimeout <= 0 || cfg.WriteTimeout <= 0 {
+		cc.add("timeouts", checkFail, "READ_TIMEOUT and WRITE_TIMEOUT must be positive")
+		return
+	}
+
+	var problems []string
+	aiTimeouts := []struct {
+		name    string
+		timeout time.Duration
+	}{
+		{"AI_DETECT_TIMEOUT", cfg.AIDetectTimeout},
+		{"AI_OCR_TIMEOUT", cfg.AIOCRTimeout},
+		{"AI_TRANSCRIBE_TIMEOUT", cfg.AITranscribeTimeout},
+		{"AI_VQA_TIMEOUT", cfg.AIVQATimeout},
+	}
+	for _, ai := range aiTimeouts {
+		if ai.timeout <= 0 {
+			cc.add("timeouts", checkFail, ai.name+" must be positive")
+			return
+		}
+		// The response can't be written after the server's write deadline
+		if ai.timeout > cfg.WriteTimeout {
+			problems = append(problems, fmt.Sprintf("%s (%s) exceeds WRITE_TIMEOUT (%s)", ai.name, ai.timeout, cfg.WriteTimeout))
+		}
+	}
+	if cfg.DBStatementTimeout > cfg.WriteTimeout {
+		problems = append(problems, fmt.Sprintf("DB_STATEMENT_TIMEOUT (%s) exceeds WRITE_TIMEOUT (%s)", cfg.DBStatementTimeout, cfg.WriteTimeout))
+	}
+	if cfg.DBConnMaxLifetime > 0 && cfg.DBConnMaxIdleTime > cfg.DBConnMaxLifetime {
+		problems = append(problems, "DB_CONN_MAX_IDLE_TIME exceeds DB_CONN_MAX_LIFETIME")
+	}
+
+	if len(problems) > 0 {
+		cc.add("timeouts", checkWarn, strings.Join(problems, "; "))
+		return
+	}
+	cc.add("timeouts", checkOK, fmt.Sprintf("read=%s write=%s", cfg.ReadTimeout, cfg.WriteTimeout))
+}
+
+// checkDatabase pings the primary and every replica
+func (cc *configChecker) checkDatabase(cfg *config.Config) {
+	status, detail := pingDatabase(cfg.DBDriver, cfg.DatabaseDSN)
+	cc.add("database", status, detail)
+	for i, dsn := range cfg.DatabaseReplicaDSNs {
+		status, detail := pingDatabase(cfg.DBDriver, dsn)
+		cc.add(fmt.Sprintf("replica_%d", i+1), status, detail)
+	}
+}
+
+func pingDatabase(driver, dsn string) (string, string) {
+	db, err := database.OpenSQL(driver, dsn)
+	if err != nil {
+		return checkFail, err.Error()
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), configCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	if err := db.PingContext(ctx); err != nil {
+		return checkFail, err.Error()
+	}
+	return checkOK, fmt.Sprintf("reachable in %s", time.Since(start).Round(time.Millisecond))
+}
+
+// checkRedis pings Redis. The API runs without it (degraded), so an
+// unreachable Redis is a warning rather than a failure.
+func (cc *configChecker) checkRedis(cfg *config.Config) {
+	client := redis.NewClient(&redis.Options{
+		Addr:        cfg.RedisAddr,
+		Password:    cfg.RedisPassword,
+		DialTimeout: configCheckTimeout,
+		MaxRetries:  -1,
+	})
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), configCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	if err := client.Ping(ctx).Err(); err != nil {
+		cc.add("redis", checkWarn, err.Error())
+		return
+	}
+	cc.add("redis", checkOK, fmt.Sprintf("reachable in %s", time.Since(start).Round(time.Millisecond)))
+}
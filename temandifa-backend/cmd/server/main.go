@@ -2,7 +2,10 @@ package main
 
 import (
 	"context"
+	"flag"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/gzip"
@@ -13,18 +16,24 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
-	"gorm.io/gorm"
 
 	_ "temandifa-backend/docs"
-	"temandifa-backend/internal/cache"
 	"temandifa-backend/internal/clients"
 	"temandifa-backend/internal/config"
 	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/graphqlapi"
+	"temandifa-backend/internal/grpcapi"
 	"temandifa-backend/internal/handlers"
 	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/metrics"
 	"temandifa-backend/internal/middleware"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/negotiate"
 	"temandifa-backend/internal/repositories"
+	"temandifa-backend/internal/scanner"
 	"temandifa-backend/internal/services"
+	"temandifa-backend/internal/storage"
+	"temandifa-backend/internal/tlscert"
 )
 
 //	@title			TemanDifa API
@@ -45,22 +54,31 @@ import (
 //	@name						Authorization
 //	@description				JWT Authorization header using the Bearer scheme. Example: "Bearer {token}"
 
+//	@securityDefinitions.apikey	ApiKeyAuth
+//	@in							header
+//	@name						X-API-Key
+//	@description				Partner API key issued through /admin/api-keys, accepted by the AI endpoints of its scopes
+
 func main() {
-	fx.New(
-		// Set graceful shutdown timeout to 45s (must be > srv.Shutdown timeout of 30s + cache wait 5s)
-		fx.StopTimeout(45*time.Second),
-
-		// Provide basic infrastructure
-		fx.Provide(
-			// Config
-			config.LoadConfig,
-			// Logger (Already init in init() or similar, but good to have as dependency if wrapper exists)
-			// For now we rely on global logger.InitFromEnv() called at start
-		),
+	validateConfig := flag.Bool("validate-config", false, "check configuration and dependencies, print a report and exit (also CONFIG_CHECK=true)")
+	flag.Parse()
+
+	if *validateConfig || strings.EqualFold(os.Getenv("CONFIG_CHECK"), "true") {
+		os.Exit(runConfigCheck())
+	}
+
+	var serverCfg *config.ServerConfig
+	app := fx.New(
+		// Config (*config.Config, its per-module slices and runtime settings)
+		config.Module,
+		// Logger (*zap.Logger, initialized from LOG_* environment variables)
+		logger.Module,
 
 		// Infrastructure Layer
 		database.Module,
 		database.RedisModule,
+		storage.Module,
+		scanner.Module,
 
 		// Repository Layer
 		repositories.Module,
@@ -72,10 +90,10 @@ func main() {
 		handlers.Module,
 
 		// External Clients
-		fx.Provide(func(lc fx.Lifecycle, cfg *config.Config) (*clients.AIClient, error) {
+		fx.Provide(func(lc fx.Lifecycle, cfg *config.AIConfig, log *zap.Logger) (*clients.AIClient, error) {
 			client, cleanup, err := clients.NewAIClient(cfg.AIServiceGRPCAddr)
 			if err != nil {
-				logger.Warn("Failed to connect to AI Service via gRPC (Initial)", zap.Error(err))
+				log.Warn("Failed to connect to AI Service via gRPC (Initial)", zap.Error(err))
 				// Return nil, nil to allow app to start, circuit breaker will handle it
 				return nil, nil
 			}
@@ -85,124 +103,465 @@ func main() {
 					return nil
 				},
 			})
-			logger.Info("AI Service configured",
+			log.Info("AI Service configured",
 				zap.String("http_url", cfg.AIServiceURL),
 				zap.String("grpc_addr", cfg.AIServiceGRPCAddr),
 			)
 			return client, nil
 		}),
 
-		// Specific Providers for values or simple structs
-		fx.Provide(func(cfg *config.Config, db *gorm.DB, rdb *redis.Client) *handlers.HealthHandler {
-			return handlers.NewHealthHandler(db, rdb, cfg.AIServiceURL)
-		}),
-
 		// HTTP Server (Gin)
 		fx.Provide(NewHTTPServer),
+		fx.Provide(newTLSProvider),
+
+		// gRPC API for the mobile app (GRPC_PORT)
+		graphqlapi.Module,
+		grpcapi.Module,
 
 		// Invocation (Entry Point)
 		fx.Invoke(
 			initInfrastructure,
 			registerRoutes,
-			services.RegisterTokenCleanupJob, // Token cleanup background job
+			services.RegisterTokenCleanupJob,          // Token cleanup background job
+			services.RegisterHealthChecker,            // Background dependency health checks
+			services.RegisterEventService,             // Analytics event pipeline (flushed after the server stops)
+			services.RegisterOutboxDispatcher,         // Forwards transactional outbox events
+			services.RegisterWebhookDeliveryPurge,     // Deletes webhook delivery logs past WEBHOOK_DELIVERY_RETENTION
+			services.RegisterUserPurgeJob,             // Hard-deletes users past the deletion grace period
+			services.RegisterUserCacheInvalidation,    // Drops cached users on database change notifications
+			services.RegisterTokenEpochSync,           // Loads the token epoch bumped by forced logouts
+			services.RegisterBackupJob,                // Scheduled database backups (BACKUP_ENABLED)
+			services.RegisterFeatureFlagRefresh,       // Reloads persisted feature flags and overrides
+			services.RegisterRateLimitOverrideRefresh, // Reloads per-user rate limit overrides
+			services.RegisterAnnouncementRefresh,      // Reloads in-app announcements
+			services.RegisterPlanRefresh,              // Reloads subscription plans
+			services.RegisterKillSwitchRefresh,        // Reloads AI operation kill switches
+			services.RegisterAppVersionRefresh,        // Reloads the app version manifests
+			services.RegisterTranslationRefresh,       // Reloads changed translation bundles
+			services.RegisterJobService,               // Lets manually triggered jobs finish on shutdown
+			services.RegisterDataExportJobs,           // Deletes expired data export archives
+			services.RegisterUploadJobs,               // Deletes expired resumable uploads
+			services.RegisterInboxPurge,               // Deletes notifications past NOTIFICATION_RETENTION
+			services.RegisterUsageReportJob,           // Generates last month's usage reports
+			storage.RegisterBucketLifecycle,           // Creates the bucket and its lifecycle rules (OBJECT_STORAGE_MANAGE_BUCKET)
+			scanner.RegisterUploadGuard,               // Scans uploads for malware (MALWARE_SCAN_DRIVER); stops after the servers
+			services.RegisterNotificationService,      // Lets push notifications in progress finish on shutdown
+			services.RegisterTokenBlacklistStats,      // Exports token blacklist size metrics
+			grpcapi.Start,                             // Stops after the HTTP server has drained
 			startServer,
+			services.RegisterNarrationService, // Ends narration streams before the servers stop
 		),
-	).Run()
+		fx.Populate(&serverCfg),
+	)
+
+	// Like app.Run, but the stop timeout comes from the SHUTDOWN_* settings
+	startCtx, cancel := context.WithTimeout(context.Background(), app.StartTimeout())
+	defer cancel()
+	if err := app.Start(startCtx); err != nil {
+		os.Exit(1) // fx has logged the error
+	}
+
+	sig := <-app.Wait()
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), serverCfg.StopTimeout())
+	defer cancel()
+	if err := app.Stop(stopCtx); err != nil {
+		os.Exit(1)
+	}
+	os.Exit(sig.ExitCode)
 }
 
-// initInfrastructure handles global/static initializations
-func initInfrastructure() {
-	// Initialize structured logger
-	logger.InitFromEnv()
-	// defer logger.Sync() // Fx handles graceful shutdown
+// initInfrastructure handles global/static initializations.
+// It depends on the logger so the global logger is initialized before anything
+// else (including config loading) logs.
+func initInfrastructure(_ *zap.Logger) {
+	// Export Go runtime metrics (GC, heap, scheduler, fds) on /metrics
+	metrics.RegisterRuntimeCollectors()
 }
 
-func NewHTTPServer(cfg *config.Config) *gin.Engine {
+func NewHTTPServer(cfg *config.Config, alertNotifier services.AlertNotifier, log *zap.Logger) (*gin.Engine, error) {
 	if cfg.GinMode == "release" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	r := gin.New()
 
+	// Client IP resolution: forwarding headers count only from trusted
+	// proxies (gin trusts every proxy by default)
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		return nil, err
+	}
+	r.RemoteIPHeaders = cfg.ClientIPHeaders
+	r.ForwardedByClientIP = len(cfg.TrustedProxies) > 0 && len(cfg.ClientIPHeaders) > 0
+	log.Info("Client IP resolution",
+		zap.Strings("trusted_proxies", cfg.TrustedProxies),
+		zap.Strings("headers", cfg.ClientIPHeaders),
+	)
+
 	// Global middleware
 	r.Use(middleware.CORSMiddleware()) // Add CORS first to handle preflight requests
 	r.Use(middleware.SecurityHeaders())
 	r.Use(middleware.MaxBodySize(cfg.MaxBodySize))
 	r.Use(gzip.Gzip(gzip.DefaultCompression))
-	r.Use(middleware.RequestID())
+	r.Use(middleware.RequestID(log))
+	if cfg.TracingEnabled {
+		r.Use(middleware.TraceContext())
+	}
+	r.Use(middleware.ContextLogger(log))  // Request-scoped logger for logger.Ctx
+	r.Use(middleware.MsgPackBody())       // MessagePack request bodies reach handlers as JSON
 	r.Use(middleware.VersionMiddleware()) // API versioning
 	r.Use(middleware.RequestLogger())
-	r.Use(logger.GinRecovery())
+	var panicReporters []logger.PanicReporter
+	if cfg.PanicAlertsEnabled {
+		panicReporters = append(panicReporters, services.NewPanicReporter(alertNotifier))
+	}
+	r.Use(logger.GinRecovery(panicReporters...))
 
-	return r
+	return r, nil
 }
 
 func registerRoutes(
 	r *gin.Engine,
 	cfg *config.Config,
+	aiCfg *config.AIConfig,
+	dynamic *config.Dynamic,
+	log *zap.Logger,
 	rdb *redis.Client,
 	userRepo repositories.UserRepository,
+	tokenService services.TokenService,
 	userCache services.UserCacheService,
+	rateLimitOverrides services.RateLimitOverrideService,
+	usage services.UsageService,
+	killSwitches services.KillSwitchService,
+	appVersions services.AppVersionService,
+	plans services.PlanService,
+	permissions services.PermissionService,
 	tokenBlacklist *services.TokenBlacklist,
+	captureService services.RequestCaptureService,
 	health *handlers.HealthHandler,
 	auth *handlers.AuthHandler,
 	ai *handlers.AIProxyHandler,
 	history *handlers.HistoryHandler,
 	cacheH *handlers.CacheHandler,
+	capture *handlers.CaptureHandler,
+	admin *handlers.AdminHandler,
+	configH *handlers.ConfigHandler,
+	flags *handlers.FeatureFlagHandler,
+	analytics *handlers.AnalyticsHandler,
+	announcements *handlers.AnnouncementHandler,
+	system *handlers.SystemHandler,
+	blacklist *handlers.TokenBlacklistHandler,
+	roles *handlers.RoleHandler,
+	killSwitchH *handlers.KillSwitchHandler,
+	jobs *handlers.JobHandler,
+	exports *handlers.DataExportHandler,
+	audit *handlers.AuditHandler,
+	planH *handlers.PlanHandler,
+	notifications *handlers.NotificationHandler,
+	narration *handlers.NarrationHandler,
+	gql *handlers.GraphQLHandler,
+	uploads *handlers.UploadHandler,
+	attachments *handlers.HistoryAttachmentHandler,
+	media *handlers.MediaHandler,
+	appVersionH *handlers.AppVersionHandler,
+	devices *handlers.DeviceHandler,
+	settingsSync *handlers.SettingsSyncHandler,
+	preferences *handlers.PreferencesHandler,
+	emergency *handlers.EmergencyHandler,
+	translations *handlers.TranslationHandler,
+	rateLimits *handlers.RateLimitHandler,
+	batch *handlers.BatchHandler,
+	webhooks *handlers.WebhookHandler,
+	statusPage *handlers.StatusHandler,
+	usageReports *handlers.UsageReportHandler,
+	emailVerification *handlers.EmailVerificationHandler,
+	passwordResets *handlers.PasswordResetHandler,
+	oauth *handlers.OAuthHandler,
+	accounts *handlers.AccountHandler,
+	users *handlers.UserHandler,
+	sessions *handlers.SessionHandler,
+	magicLinks *handlers.MagicLinkHandler,
+	apiKeys services.APIKeyService,
+	apiKeyH *handlers.APIKeyHandler,
+	emailChanges *handlers.EmailChangeHandler,
+	jwks *handlers.JWKSHandler,
 ) {
 	// Routes
 	api := r.Group("/api/v1")
+	// Maintenance mode (MAINTENANCE_MODE, switchable through the remote config store)
+	api.Use(middleware.Maintenance(dynamic.Maintenance))
+	// Turns away app versions below the platform's minimum (X-App-Version)
+	api.Use(middleware.AppVersionGate(appVersions))
 	// Use sliding window rate limiter for more accurate rate limiting
-	api.Use(middleware.SlidingWindowRateLimiter(rdb, cfg.RateLimitRequests, time.Duration(cfg.RateLimitWindow)*time.Second))
+	api.Use(middleware.SlidingWindowRateLimiter(rdb, "general", dynamic.RateLimit))
+	// Targeted request capture for debugging (no-op unless an admin enables a rule)
+	api.Use(middleware.RequestCapture(captureService))
 	{
 		api.GET("/health", health.CheckHealth)
+		api.GET("/status", statusPage.GetStatus)
 		api.POST("/register", auth.Register)
 		api.POST("/login", auth.Login)
+		api.POST("/auth/google", oauth.SignInWithGoogle)
+		api.POST("/auth/apple", oauth.SignInWithApple)
+		api.GET("/auth/oidc/:provider", oauth.StartOIDCSignIn)
+		api.GET("/auth/oidc/:provider/callback", oauth.OIDCCallback)
+		api.POST("/auth/magic-link", magicLinks.RequestMagicLink)
+		api.GET("/auth/magic-link/verify", magicLinks.VerifyMagicLink)
+		// Links emailed to both addresses of an email change; the token stands in for authentication
+		api.GET("/auth/change-email/confirm", emailChanges.ConfirmEmailChange)
 		api.POST("/refresh", auth.Refresh)
 		api.POST("/logout", auth.Logout)
+		// Links emailed to new users; the token stands in for authentication
+		api.GET("/verify-email", emailVerification.VerifyEmail)
+		api.POST("/forgot-password", passwordResets.ForgotPassword)
+		api.POST("/reset-password", passwordResets.ResetPassword)
+		api.GET("/announcements", announcements.GetAnnouncements)
+		api.GET("/app/version", appVersionH.GetAppVersion)
+		api.GET("/i18n/:locale", translations.GetBundle)
+		// Sub-requests are authenticated and rate limited one by one
+		api.POST("/batch", batch.ServeBatch)
+		api.OPTIONS("/uploads", uploads.Options)
+		// Signed media URLs; the signature stands in for authentication
+		api.GET("/media/attachments/:attachment_id", media.ServeAttachment)
+		api.GET("/media/attachments/:attachment_id/thumbnail", media.ServeThumbnail)
+		// Consent links sent to emergency contacts; the token stands in for authentication
+		api.GET("/emergency-contacts/consent/:token", emergency.GetConsent)
+		api.POST("/emergency-contacts/consent/:token", emergency.AnswerConsent)
+	}
+
+	authMiddleware := middleware.Auth(tokenService, userRepo, userCache, tokenBlacklist)
+	// can guards a route with a permission of the caller's role
+	can := func(permission string) gin.HandlerFunc {
+		return middleware.RequirePermission(permissions, permission)
+	}
+
+	aiRateLimit := middleware.SlidingWindowRateLimiterByUser(rdb, "ai", dynamic.AIRateLimit, plans, rateLimitOverrides)
+
+	// AI Routes with email verification (when required), stricter rate limiting, per-operation kill switches, plan quotas and timeouts.
+	// Partner apps may call them with an API key (X-API-Key) of the operation's scope instead of an access token.
+	aiRoutes := api.Group("/")
+	aiRoutes.Use(middleware.APIKeyAuth(apiKeys, userRepo, userCache, authMiddleware))
+	aiRoutes.Use(middleware.RequireVerifiedEmail(cfg))
+	aiRoutes.Use(aiRateLimit)
+	{
+		aiRoutes.POST("/detect", middleware.APIKeyScope(config.UploadOpDetect), middleware.KillSwitch(killSwitches, config.UploadOpDetect), middleware.Quota(plans), middleware.DetectSLO(aiCfg), middleware.Usage(usage, config.UploadOpDetect), middleware.DetectTimeout(aiCfg), ai.DetectObjects)
+		aiRoutes.POST("/ocr", middleware.APIKeyScope(config.UploadOpOCR), middleware.KillSwitch(killSwitches, config.UploadOpOCR), middleware.Quota(plans), middleware.OCRSLO(aiCfg), middleware.Usage(usage, config.UploadOpOCR), middleware.OCRTimeout(aiCfg), ai.ExtractText)
+		aiRoutes.POST("/transcribe", middleware.APIKeyScope(config.UploadOpTranscribe), middleware.KillSwitch(killSwitches, config.UploadOpTranscribe), middleware.Quota(plans), middleware.TranscribeSLO(aiCfg), middleware.Usage(usage, config.UploadOpTranscribe), middleware.TranscribeTimeout(aiCfg), ai.TranscribeAudio)
+		aiRoutes.POST("/ask", middleware.APIKeyScope(config.UploadOpAsk), middleware.KillSwitch(killSwitches, config.UploadOpAsk), middleware.Quota(plans), middleware.VQASLO(aiCfg), middleware.Usage(usage, config.UploadOpAsk), middleware.VQATimeout(aiCfg), ai.AskQuestion)
+		aiRoutes.POST("/simplify", middleware.APIKeyScope(config.OpSimplify), middleware.KillSwitch(killSwitches, config.OpSimplify), middleware.Quota(plans), middleware.SimplifySLO(aiCfg), middleware.Usage(usage, config.OpSimplify), middleware.SimplifyTimeout(aiCfg), ai.SimplifyText)
 	}
 
 	protected := api.Group("/")
-	protected.Use(middleware.Auth(cfg.JWTSecret, userRepo, userCache, tokenBlacklist))
+	protected.Use(authMiddleware)
 	{
-		// AI Routes with stricter rate limiting and per-operation timeouts
-		aiRoutes := protected.Group("/")
-		aiRoutes.Use(middleware.SlidingWindowRateLimiterByUser(rdb, cfg.AIRateLimitRequests, time.Duration(cfg.AIRateLimitWindow)*time.Second))
-		{
-			aiRoutes.POST("/detect", middleware.DetectTimeout(cfg), ai.DetectObjects)
-			aiRoutes.POST("/ocr", middleware.OCRTimeout(cfg), ai.ExtractText)
-			aiRoutes.POST("/transcribe", middleware.TranscribeTimeout(cfg), ai.TranscribeAudio)
-			aiRoutes.POST("/ask", middleware.VQATimeout(cfg), ai.AskQuestion)
-		}
+		// Continuous scene narration; quota and kill switches are checked per frame
+		protected.GET("/ws/narrate", middleware.RequireVerifiedEmail(cfg), aiRateLimit, narration.Narrate)
 
 		protected.GET("/history", history.GetUserHistory)
 		protected.POST("/history", history.CreateHistory)
 		protected.DELETE("/history/:id", history.DeleteHistory)
 		protected.DELETE("/history", history.ClearUserHistory)
+		protected.POST("/history/:id/attachments", attachments.AttachToHistory)
+		protected.GET("/history/:id/attachments", attachments.ListHistoryAttachments)
+		protected.DELETE("/history/:id/attachments/:attachment_id", attachments.DeleteHistoryAttachment)
+
+		protected.GET("/features", flags.GetFeatures)
+		protected.POST("/verify-email/resend", emailVerification.ResendVerification)
+		protected.GET("/auth/sessions", sessions.ListSessions)
+		protected.DELETE("/auth/sessions/:id", sessions.RevokeSession)
+		protected.POST("/auth/change-password", accounts.ChangePassword)
+		protected.POST("/auth/change-email", emailChanges.RequestEmailChange)
+		protected.DELETE("/account", accounts.DeleteAccount)
+
+		protected.GET("/me", users.GetMe)
+		protected.PATCH("/me", users.UpdateMe)
+		protected.GET("/me/limits", rateLimits.GetMyLimits)
+		protected.GET("/me/notifications", notifications.ListNotifications)
+		protected.GET("/me/notifications/unread-count", notifications.GetUnreadNotificationCount)
+		protected.POST("/me/notifications/read-all", notifications.MarkAllNotificationsRead)
+		protected.POST("/me/notifications/:notification_id/read", notifications.MarkNotificationRead)
+		protected.GET("/me/preferences", preferences.GetPreferences)
+		protected.PATCH("/me/preferences", preferences.UpdatePreferences)
+		protected.GET("/me/settings/sync", settingsSync.GetSettingsSync)
+		protected.PUT("/me/settings/sync", settingsSync.PutSettingsSync)
+		protected.GET("/me/emergency-contacts", emergency.ListContacts)
+		protected.POST("/me/emergency-contacts", emergency.CreateContact)
+		protected.PUT("/me/emergency-contacts/:contact_id", emergency.UpdateContact)
+		protected.DELETE("/me/emergency-contacts/:contact_id", emergency.DeleteContact)
+		protected.POST("/me/emergency-contacts/:contact_id/consent-request", emergency.RequestConsent)
+		protected.POST("/emergency", emergency.SendAlert)
+		protected.POST("/me/devices", devices.RegisterDevice)
+		protected.DELETE("/me/devices/:device_id", devices.UnregisterDevice)
+		// Deprecated: push tokens without a device, superseded by /me/devices
+		protected.POST("/me/push-tokens", notifications.RegisterPushToken)
+		protected.DELETE("/me/push-tokens", notifications.UnregisterPushToken)
+		protected.POST("/me/export", exports.RequestMyExport)
+		protected.GET("/me/exports/:export_id", exports.GetMyExport)
+		protected.GET("/me/exports/:export_id/download", exports.DownloadMyExport)
+		protected.GET("/me/reports", usageReports.ListMyReports)
+		protected.GET("/me/reports/:month", usageReports.GetMyReport)
+
+		// Resumable uploads (tus), for recordings too long for one request
+		protected.POST("/uploads", uploads.Create)
+		protected.HEAD("/uploads/:upload_id", uploads.Head)
+		protected.PATCH("/uploads/:upload_id", uploads.Patch)
+		protected.DELETE("/uploads/:upload_id", uploads.Delete)
+
+		protected.POST("/graphql", gql.Query)
+		protected.GET("/graphql/schema", gql.Schema)
 
 		cacheGroup := protected.Group("/cache")
-		cacheGroup.Use(middleware.AdminOnly())
+		cacheGroup.Use(can(models.PermCacheManage))
 		{
 			cacheGroup.GET("/stats", cacheH.GetCacheStats)
 			cacheGroup.DELETE("/detection", cacheH.ClearDetectionCache)
 			cacheGroup.DELETE("/ocr", cacheH.ClearOCRCache)
 			cacheGroup.DELETE("/transcription", cacheH.ClearTranscriptionCache)
+			cacheGroup.POST("/invalidate", cacheH.InvalidateCache)
 			cacheGroup.DELETE("/", cacheH.ClearAllCache)
 		}
+
+		// Every admin route requires its own permission
+		adminGroup := protected.Group("/admin")
+		{
+			adminGroup.GET("/capture", can(models.PermCaptureManage), capture.GetCaptureRule)
+			adminGroup.PUT("/capture", can(models.PermCaptureManage), capture.SetCaptureRule)
+			adminGroup.DELETE("/capture", can(models.PermCaptureManage), capture.DeleteCaptureRule)
+			adminGroup.GET("/capture/records", can(models.PermCaptureManage), capture.ListCapturedRequests)
+			adminGroup.DELETE("/capture/records", can(models.PermCaptureManage), capture.ClearCapturedRequests)
+
+			adminGroup.GET("/users", can(models.PermUsersRead), admin.SearchUsers)
+			adminGroup.GET("/users/purge-preview", can(models.PermUsersRead), admin.PreviewUserPurge)
+			adminGroup.PUT("/users/:id/rate-limit", can(models.PermUsersManage), admin.SetRateLimitOverride)
+			adminGroup.DELETE("/users/:id/rate-limit", can(models.PermUsersManage), admin.DeleteRateLimitOverride)
+			adminGroup.GET("/rate-limit-overrides", can(models.PermUsersRead), admin.ListRateLimitOverrides)
+			adminGroup.PUT("/users/:id/role", can(models.PermUsersManage), roles.AssignUserRole)
+			adminGroup.PUT("/users/:id/plan", can(models.PermUsersManage), planH.AssignUserPlan)
+			adminGroup.POST("/users/:id/export", can(models.PermUsersManage), exports.RequestUserExport)
+			adminGroup.GET("/users/:id/exports/:export_id", can(models.PermUsersManage), exports.GetUserExport)
+			adminGroup.GET("/users/:id/exports/:export_id/download", can(models.PermUsersManage), exports.DownloadUserExport)
+
+			adminGroup.GET("/kill-switches", can(models.PermFlagsManage), killSwitchH.ListKillSwitches)
+			adminGroup.PUT("/kill-switches/:operation", can(models.PermFlagsManage), killSwitchH.DisableOperation)
+			adminGroup.DELETE("/kill-switches/:operation", can(models.PermFlagsManage), killSwitchH.EnableOperation)
+
+			adminGroup.GET("/permissions", can(models.PermRolesManage), roles.ListPermissions)
+			adminGroup.GET("/roles", can(models.PermRolesManage), roles.ListRoles)
+			adminGroup.POST("/roles", can(models.PermRolesManage), roles.CreateRole)
+			adminGroup.PUT("/roles/:name", can(models.PermRolesManage), roles.UpdateRole)
+			adminGroup.DELETE("/roles/:name", can(models.PermRolesManage), roles.DeleteRole)
+			adminGroup.POST("/roles/:name/permissions", can(models.PermRolesManage), roles.AddRolePermissions)
+			adminGroup.DELETE("/roles/:name/permissions/:permission", can(models.PermRolesManage), roles.RemoveRolePermission)
+
+			adminGroup.GET("/audit/:id", can(models.PermAuditRead), audit.GetAuditEntry)
+
+			adminGroup.GET("/plans", can(models.PermPlansManage), planH.ListPlans)
+			adminGroup.PUT("/plans/:name", can(models.PermPlansManage), planH.UpdatePlan)
+
+			adminGroup.GET("/sessions", can(models.PermSessionsManage), admin.ListSessions)
+			adminGroup.POST("/sessions/revoke", can(models.PermSessionsManage), admin.RevokeSessions)
+			adminGroup.POST("/force-logout", can(models.PermSessionsManage), admin.ForceLogoutAll)
+
+			adminGroup.GET("/token-blacklist", can(models.PermBlacklistManage), blacklist.ListBlacklistEntries)
+			adminGroup.GET("/token-blacklist/stats", can(models.PermBlacklistManage), blacklist.GetBlacklistStats)
+			adminGroup.DELETE("/token-blacklist/expired", can(models.PermBlacklistManage), blacklist.ClearExpiredBlacklistEntries)
+
+			adminGroup.GET("/jobs", can(models.PermJobsRun), jobs.ListJobs)
+			adminGroup.POST("/jobs/:name/run", can(models.PermJobsRun), jobs.RunJob)
+			adminGroup.GET("/jobs/runs/:id", can(models.PermJobsRun), jobs.GetJobRun)
+
+			adminGroup.GET("/config", can(models.PermConfigRead), configH.GetEffectiveConfig)
+			adminGroup.GET("/system", can(models.PermSystemRead), system.GetSystemInfo)
+
+			adminGroup.GET("/analytics/daily", can(models.PermAnalyticsRead), analytics.GetDailyUsage)
+			adminGroup.GET("/analytics/operations", can(models.PermAnalyticsRead), analytics.GetOperationUsage)
+			adminGroup.GET("/analytics/top-users", can(models.PermAnalyticsRead), analytics.GetTopUsers)
+			adminGroup.GET("/reports/usage/:month", can(models.PermAnalyticsRead), usageReports.ExportUsageReports)
+
+			adminGroup.GET("/announcements", can(models.PermAnnouncementsManage), announcements.ListAnnouncements)
+			adminGroup.POST("/announcements", can(models.PermAnnouncementsManage), announcements.CreateAnnouncement)
+			adminGroup.PUT("/announcements/:id", can(models.PermAnnouncementsManage), announcements.UpdateAnnouncement)
+			adminGroup.DELETE("/announcements/:id", can(models.PermAnnouncementsManage), announcements.DeleteAnnouncement)
+
+			adminGroup.GET("/app-versions", can(models.PermAppVersionsManage), appVersionH.ListAppVersions)
+			adminGroup.PUT("/app-versions/:platform", can(models.PermAppVersionsManage), appVersionH.SaveAppVersion)
+			adminGroup.DELETE("/app-versions/:platform", can(models.PermAppVersionsManage), appVersionH.DeleteAppVersion)
+
+			adminGroup.GET("/i18n", can(models.PermTranslationsManage), translations.ListTranslationLocales)
+			adminGroup.GET("/i18n/:locale/versions", can(models.PermTranslationsManage), translations.ListTranslationVersions)
+			adminGroup.PUT("/i18n/:locale", can(models.PermTranslationsManage), translations.UploadTranslationBundle)
+			adminGroup.DELETE("/i18n/:locale", can(models.PermTranslationsManage), translations.DeleteTranslationLocale)
+			adminGroup.POST("/i18n/:locale/versions/:version/restore", can(models.PermTranslationsManage), translations.RestoreTranslationVersion)
+
+			adminGroup.GET("/webhooks", can(models.PermWebhooksManage), webhooks.ListWebhooks)
+			adminGroup.POST("/webhooks", can(models.PermWebhooksManage), webhooks.CreateWebhook)
+			adminGroup.GET("/webhooks/:id", can(models.PermWebhooksManage), webhooks.GetWebhook)
+			adminGroup.PUT("/webhooks/:id", can(models.PermWebhooksManage), webhooks.UpdateWebhook)
+			adminGroup.DELETE("/webhooks/:id", can(models.PermWebhooksManage), webhooks.DeleteWebhook)
+			adminGroup.GET("/webhooks/:id/deliveries", can(models.PermWebhooksManage), webhooks.ListWebhookDeliveries)
+			adminGroup.POST("/webhooks/:id/test", can(models.PermWebhooksManage), webhooks.TestWebhook)
+
+			adminGroup.GET("/api-keys", can(models.PermAPIKeysManage), apiKeyH.ListAPIKeys)
+			adminGroup.POST("/api-keys", can(models.PermAPIKeysManage), apiKeyH.CreateAPIKey)
+			adminGroup.GET("/api-keys/:id", can(models.PermAPIKeysManage), apiKeyH.GetAPIKey)
+			adminGroup.PUT("/api-keys/:id", can(models.PermAPIKeysManage), apiKeyH.UpdateAPIKey)
+			adminGroup.DELETE("/api-keys/:id", can(models.PermAPIKeysManage), apiKeyH.DeleteAPIKey)
+
+			adminGroup.GET("/feature-flags", can(models.PermFlagsManage), flags.ListFeatureFlags)
+			adminGroup.PUT("/feature-flags/:name", can(models.PermFlagsManage), flags.SaveFeatureFlag)
+			adminGroup.DELETE("/feature-flags/:name", can(models.PermFlagsManage), flags.DeleteFeatureFlag)
+			adminGroup.PUT("/feature-flags/:name/overrides", can(models.PermFlagsManage), flags.SetFeatureFlagOverride)
+			adminGroup.DELETE("/feature-flags/:name/overrides/:subject_type/:subject", can(models.PermFlagsManage), flags.DeleteFeatureFlagOverride)
+		}
 	}
 
+	// Kubernetes probes (outside /api/v1 so they skip rate limiting)
+	r.GET("/healthz", health.Liveness)
+	r.GET("/readyz", health.Readiness)
+
+	// Public keys verifying access tokens, for other services
+	r.GET("/.well-known/jwks.json", jwks.GetJWKS)
+
 	// Docs & Metrics
 	r.GET("/", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
+		negotiate.Render(c, http.StatusOK, gin.H{
 			"message": "TemanDifa Backend Gateway is Online",
 			"version": "1.0.0",
 		})
 	})
 	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	// Runtime profiling (opt-in, debug:access permission)
+	if cfg.PprofEnabled {
+		debugGroup := r.Group("/debug")
+		debugGroup.Use(authMiddleware, can(models.PermDebug))
+		{
+			debugGroup.GET("/pprof/*profile", handlers.Pprof)
+			debugGroup.POST("/pprof/*profile", handlers.Pprof) // pprof symbol lookups use POST
+			debugGroup.GET("/vars", handlers.Expvar)
+		}
+		log.Warn("Profiling endpoints enabled at /debug/pprof and /debug/vars")
+	}
+}
+
+// newTLSProvider sets up native TLS (TLS_MODE) for the HTTP and gRPC
+// servers; nil when a load balancer terminates TLS
+func newTLSProvider(cfg *config.ServerConfig, log *zap.Logger) (*tlscert.Provider, error) {
+	return tlscert.New(tlscert.Options{
+		Mode:           cfg.TLSMode,
+		CertFile:       cfg.TLSCertFile,
+		KeyFile:        cfg.TLSKeyFile,
+		ReloadInterval: cfg.TLSReloadInterval,
+		ACMEDomains:    cfg.ACMEDomains,
+		ACMEEmail:      cfg.ACMEEmail,
+		ACMECacheDir:   cfg.ACMECacheDir,
+		ACMEDirectory:  cfg.ACMEDirectoryURL,
+		HTTPSPort:      cfg.Port,
+	}, log)
 }
 
-func startServer(lc fx.Lifecycle, r *gin.Engine, cfg *config.Config) {
+func startServer(lc fx.Lifecycle, r *gin.Engine, cfg *config.ServerConfig, certs *tlscert.Provider, checker *services.HealthChecker, cacheService services.CacheService, log *zap.Logger) error {
 	srv := &http.Server{
 		Addr:         ":" + cfg.Port,
 		Handler:      r,
@@ -211,38 +570,85 @@ func startServer(lc fx.Lifecycle, r *gin.Engine, cfg *config.Config) {
 		IdleTimeout:  120 * time.Second,
 	}
 
+	// Plain HTTP listener redirecting to HTTPS (TLS_HTTP_PORT)
+	var redirectSrv *http.Server
+	if certs != nil {
+		srv.TLSConfig = certs.TLSConfig()
+		if cfg.TLSHTTPPort != "" {
+			redirectSrv = &http.Server{
+				Addr:              ":" + cfg.TLSHTTPPort,
+				Handler:           certs.HTTPHandler(),
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+		}
+	}
+
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
-			logger.Info("Server starting",
+			log.Info("Server starting",
 				zap.String("port", cfg.Port),
 				zap.String("mode", gin.Mode()),
+				zap.String("tls", cfg.TLSMode),
 			)
 
 			go func() {
-				if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-					logger.Fatal("Failed to start server", zap.Error(err))
+				var err error
+				if certs != nil {
+					certs.Start()
+					err = srv.ListenAndServeTLS("", "") // certificates come from TLSConfig
+				} else {
+					err = srv.ListenAndServe()
+				}
+				if err != nil && err != http.ErrServerClosed {
+					log.Fatal("Failed to start server", zap.Error(err))
 				}
 			}()
+
+			if redirectSrv != nil {
+				go func() {
+					if err := redirectSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+						log.Fatal("Failed to start HTTP redirect server", zap.Error(err))
+					}
+				}()
+			}
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
-			logger.Info("Shutting down server...")
-
-			// Give outstanding requests 30 seconds to complete
-			shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-			defer cancel()
+			log.Info("Shutting down server...",
+				zap.Duration("drain_delay", cfg.ShutdownDrainDelay),
+				zap.Duration("timeout", cfg.ShutdownTimeout),
+			)
 
-			// Wait for background cache operations
-			cache.WaitForCompletion()
+			// Fail readiness and keep serving until load balancers have
+			// stopped routing here; closing keep-alive connections moves
+			// clients to other instances
+			checker.StartDraining()
+			srv.SetKeepAlivesEnabled(false)
+			if cfg.ShutdownDrainDelay > 0 {
+				time.Sleep(cfg.ShutdownDrainDelay)
+			}
 
-			// Shutdown HTTP server
+			// Stop accepting connections and let in-flight requests (AI
+			// calls included) finish
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+			defer cancel()
 			if err := srv.Shutdown(shutdownCtx); err != nil {
-				logger.Error("Server forced to shutdown", zap.Error(err))
+				log.Error("Server forced to shutdown", zap.Error(err))
+				_ = srv.Close()
+			}
+			if redirectSrv != nil {
+				_ = redirectSrv.Close()
+			}
+
+			// Requests may have queued async cache writes until the end
+			cacheService.WaitForCompletion(cfg.ShutdownCacheWait)
+			if certs != nil {
+				certs.Stop()
 			}
 
-			logger.Info("Server exited gracefully")
-			logger.Sync() // Ensure all buffered logs are written
+			log.Info("Server exited gracefully")
 			return nil
 		},
 	})
+	return nil
 }
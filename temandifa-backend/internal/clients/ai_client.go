@@ -8,16 +8,12 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/keepalive"
-	"google.golang.org/grpc/metadata"
 
 	pb "temandifa-backend/internal/grpc/aiservice" //nolint:typecheck
 	"temandifa-backend/internal/helpers"
 	"temandifa-backend/internal/logger"
 )
 
-// RequestIDKey is the context key for request ID
-type RequestIDKey struct{}
-
 type AIClient struct {
 	conn   *grpc.ClientConn
 	client pb.AIServiceClient
@@ -38,9 +34,21 @@ func NewAIClient(address string) (*AIClient, func(), error) {
 		PermitWithoutStream: true,             // send pings even without active streams
 	}
 
+	// Interceptors handle request ID propagation, retries, logging, and metrics
+	// for every call, so the methods below stay thin wrappers around the stub.
+	// Retry runs outside metrics so each attempt is recorded individually.
 	conn, err := grpc.NewClient(address,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 		grpc.WithKeepaliveParams(kacp),
+		grpc.WithChainUnaryInterceptor(
+			requestIDUnaryInterceptor,
+			retryUnaryInterceptor(helpers.DefaultRetryConfig),
+			metricsUnaryInterceptor,
+		),
+		grpc.WithChainStreamInterceptor(
+			requestIDStreamInterceptor,
+			metricsStreamInterceptor,
+		),
 	)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create gRPC client: %w", err)
@@ -65,85 +73,56 @@ func NewAIClient(address string) (*AIClient, func(), error) {
 	}, cleanup, nil
 }
 
-// withRequestID adds the request ID from context to gRPC metadata
-func withRequestID(ctx context.Context) context.Context {
-	// Try to get request ID from context
-	if requestID, ok := ctx.Value("request_id").(string); ok && requestID != "" {
-		ctx = metadata.AppendToOutgoingContext(ctx, "x-request-id", requestID)
-	}
-	return ctx
-}
-
 // DetectObjects calls the DetectObjects gRPC method
 func (c *AIClient) DetectObjects(ctx context.Context, imageData []byte, filename string) (*pb.DetectionResponse, error) {
-	ctx = withRequestID(ctx)
 	req := &pb.ImageRequest{
 		ImageData: imageData,
 		Filename:  filename,
 	}
 
-	var resp *pb.DetectionResponse
-	err := helpers.WithRetry(ctx, helpers.DefaultRetryConfig, "DetectObjects", func() error {
-		var err error
-		resp, err = c.client.DetectObjects(ctx, req)
-		return err
-	})
-
-	return resp, err
+	return c.client.DetectObjects(ctx, req)
 }
 
 // ExtractText calls the ExtractText gRPC method
 func (c *AIClient) ExtractText(ctx context.Context, imageData []byte, filename, language string) (*pb.OCRResponse, error) {
-	ctx = withRequestID(ctx)
 	req := &pb.OCRRequest{
 		ImageData: imageData,
 		Filename:  filename,
 		Language:  language,
 	}
 
-	var resp *pb.OCRResponse
-	err := helpers.WithRetry(ctx, helpers.DefaultRetryConfig, "ExtractText", func() error {
-		var err error
-		resp, err = c.client.ExtractText(ctx, req)
-		return err
-	})
-
-	return resp, err
+	return c.client.ExtractText(ctx, req)
 }
 
 // TranscribeAudio calls the TranscribeAudio gRPC method
 func (c *AIClient) TranscribeAudio(ctx context.Context, audioData []byte, filename string) (*pb.TranscriptionResponse, error) {
-	ctx = withRequestID(ctx)
 	req := &pb.AudioRequest{
 		AudioData: audioData,
 		Filename:  filename,
 	}
 
-	var resp *pb.TranscriptionResponse
-	err := helpers.WithRetry(ctx, helpers.DefaultRetryConfig, "TranscribeAudio", func() error {
-		var err error
-		resp, err = c.client.TranscribeAudio(ctx, req)
-		return err
-	})
-
-	return resp, err
+	return c.client.TranscribeAudio(ctx, req)
 }
 
 // VisualQuestionAnswering calls the VQA gRPC method
 func (c *AIClient) VisualQuestionAnswering(ctx context.Context, imageData []byte, filename, question string) (*pb.VQAResponse, error) {
-	ctx = withRequestID(ctx)
 	req := &pb.VQARequest{
 		ImageData: imageData,
 		Filename:  filename,
 		Question:  question,
 	}
 
-	var resp *pb.VQAResponse
-	err := helpers.WithRetry(ctx, helpers.DefaultRetryConfig, "VisualQuestionAnswering", func() error {
-		var err error
-		resp, err = c.client.VisualQuestionAnswering(ctx, req)
-		return err
-	})
+	return c.client.VisualQuestionAnswering(ctx, req)
+}
+
+// SimplifyText calls the SimplifyText gRPC method
+func (c *AIClient) SimplifyText(ctx context.Context, text, language, level string, maxChunkChars int) (*pb.SimplifyResponse, error) {
+	req := &pb.SimplifyRequest{
+		Text:          text,
+		Language:      language,
+		Level:         level,
+		MaxChunkChars: int32(maxChunkChars),
+	}
 
-	return resp, err
+	return c.client.SimplifyText(ctx, req)
 }
@@ -0,0 +1,244 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// fcmScope is the OAuth2 scope of the FCM HTTP v1 API
+	fcmScope = "https://www.googleapis.com/auth/firebase.messaging"
+	// fcmEndpoint is the FCM HTTP v1 send endpoint, formatted with the project ID
+	fcmEndpoint = "https://fcm.googleapis.com/v1/projects/%s/messages:send"
+	// fcmRequestTimeout bounds a single token exchange or send
+	fcmRequestTimeout = 10 * time.Second
+	// fcmTokenRefreshMargin renews access tokens this long before they expire
+	fcmTokenRefreshMargin = time.Minute
+)
+
+var (
+	// ErrFCMTokenInvalid means the device token is unregistered or malformed
+	// and should be forgotten
+	ErrFCMTokenInvalid = errors.New("fcm: device token is no longer valid")
+	// ErrFCMRejected means FCM refused the message for a reason retrying won't fix
+	ErrFCMRejected = errors.New("fcm: message rejected")
+)
+
+// FCMMessage is a push notification addressed to one device token or to a topic
+type FCMMessage struct {
+	Token string
+	Topic string
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// serviceAccount is the subset of a Google service account JSON key used to
+// obtain access tokens
+type serviceAccount struct {
+	ProjectID    string `json:"project_id"`
+	ClientEmail  string `json:"client_email"`
+	PrivateKey   string `json:"private_key"`
+	PrivateKeyID string `json:"private_key_id"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// FCMClient sends push notifications through the Firebase Cloud Messaging
+// HTTP v1 API, authenticating as a service account
+type FCMClient struct {
+	account   serviceAccount
+	projectID string
+	client    *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// NewFCMClient creates a client from a service account JSON key file;
+// projectID defaults to the service account's project
+func NewFCMClient(credentialsFile, projectID string) (*FCMClient, error) {
+	data, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read FCM credentials: %w", err)
+	}
+
+	var account serviceAccount
+	if err := json.Unmarshal(data, &account); err != nil {
+		return nil, fmt.Errorf("failed to parse FCM credentials: %w", err)
+	}
+	if account.ClientEmail == "" || account.PrivateKey == "" {
+		return nil, errors.New("FCM credentials are not a service account key")
+	}
+	if account.TokenURI == "" {
+		account.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+	if projectID == "" {
+		projectID = account.ProjectID
+	}
+	if projectID == "" {
+		return nil, errors.New("FCM project ID is not set and missing from the credentials")
+	}
+
+	return &FCMClient{
+		account:   account,
+		projectID: projectID,
+		client:    &http.Client{Timeout: fcmRequestTimeout},
+	}, nil
+}
+
+// Send delivers a message. Errors wrapping ErrFCMTokenInvalid or
+// ErrFCMRejected are permanent; others (network, quota, server errors) may
+// succeed when retried.
+func (c *FCMClient) Send(ctx context.Context, msg FCMMessage) error {
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	message := map[string]any{
+		"notification": map[string]string{"title": msg.Title, "body": msg.Body},
+		"android":      map[string]string{"priority": "high"},
+	}
+	if msg.Token != "" {
+		message["token"] = msg.Token
+	} else {
+		message["topic"] = msg.Topic
+	}
+	if len(msg.Data) > 0 {
+		message["data"] = msg.Data
+	}
+	body, err := json.Marshal(map[string]any{"message": message})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf(fcmEndpoint, c.projectID), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 300 {
+		return nil
+	}
+	return fcmError(resp)
+}
+
+// fcmError classifies an FCM error response
+// (https://firebase.google.com/docs/reference/fcm/rest/v1/ErrorCode)
+func fcmError(resp *http.Response) error {
+	var payload struct {
+		Error struct {
+			Message string `json:"message"`
+			Status  string `json:"status"`
+			Details []struct {
+				ErrorCode string `json:"errorCode"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&payload)
+
+	code := payload.Error.Status
+	for _, detail := range payload.Error.Details {
+		if detail.ErrorCode != "" {
+			code = detail.ErrorCode
+		}
+	}
+
+	switch {
+	case code == "UNREGISTERED" || resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%w: %s", ErrFCMTokenInvalid, payload.Error.Message)
+	case code == "INVALID_ARGUMENT" && strings.Contains(strings.ToLower(payload.Error.Message), "registration token"):
+		return fmt.Errorf("%w: %s", ErrFCMTokenInvalid, payload.Error.Message)
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("fcm: %s %s", resp.Status, code)
+	case resp.StatusCode == http.StatusUnauthorized:
+		// The access token may have been revoked early; retry with a new one
+		return fmt.Errorf("fcm: %s", resp.Status)
+	default:
+		return fmt.Errorf("%w: %s %s: %s", ErrFCMRejected, resp.Status, code, payload.Error.Message)
+	}
+}
+
+// token returns a cached access token, exchanging a signed service account
+// assertion for a new one when it is about to expire
+func (c *FCMClient) token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.accessToken != "" && time.Until(c.expiresAt) > fcmTokenRefreshMargin {
+		return c.accessToken, nil
+	}
+
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(c.account.PrivateKey))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse FCM private key: %w", err)
+	}
+	now := time.Now()
+	assertion := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   c.account.ClientEmail,
+		"scope": fcmScope,
+		"aud":   c.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	assertion.Header["kid"] = c.account.PrivateKeyID
+	signed, err := assertion.SignedString(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign FCM assertion: %w", err)
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {signed},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("FCM token exchange failed: %s", resp.Status)
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode FCM access token: %w", err)
+	}
+
+	c.accessToken = result.AccessToken
+	c.expiresAt = now.Add(time.Duration(result.ExpiresIn) * time.Second)
+	return c.accessToken, nil
+}
@@ -0,0 +1,204 @@
+package clients
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	googleJWKSURL = "https://www.googleapis.com/oauth2/v3/certs"
+	appleJWKSURL  = "https://appleid.apple.com/auth/keys"
+
+	// idTokenRequestTimeout bounds a signing key download
+	idTokenRequestTimeout = 10 * time.Second
+	// jwksTTL is how long downloaded signing keys are trusted; providers
+	// rotate theirs every few days
+	jwksTTL = time.Hour
+	// jwksRefetchInterval limits downloads for tokens naming unknown keys
+	jwksRefetchInterval = time.Minute
+)
+
+// ErrIDTokenInvalid means an ID token isn't a valid, unexpired token of the
+// provider for one of the app's client IDs
+var ErrIDTokenInvalid = errors.New("id token: invalid")
+
+// IDTokenClaims are the claims of a verified ID token
+type IDTokenClaims struct {
+	Subject       string // The provider's stable user ID
+	Email         string
+	EmailVerified bool // The provider confirmed the user owns Email
+	Name          string
+	Nonce         string // Echoes the sign-in request's nonce, for ID tokens of code exchanges
+}
+
+// IDTokenVerifier verifies the RS256 ID tokens an OpenID Connect provider
+// issues to the app against the provider's published signing keys
+type IDTokenVerifier struct {
+	issuers   []string
+	audiences []string
+	jwksURL   string
+	client    *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+// NewGoogleIDTokenVerifier verifies Google ID tokens issued to clientIDs
+func NewGoogleIDTokenVerifier(clientIDs []string) *IDTokenVerifier {
+	return NewIDTokenVerifier([]string{"https://accounts.google.com", "accounts.google.com"}, clientIDs, googleJWKSURL)
+}
+
+// NewAppleIDTokenVerifier verifies Sign in with Apple ID tokens issued to
+// clientIDs (the app's bundle ID or services IDs)
+func NewAppleIDTokenVerifier(clientIDs []string) *IDTokenVerifier {
+	return NewIDTokenVerifier([]string{"https://appleid.apple.com"}, clientIDs, appleJWKSURL)
+}
+
+// NewIDTokenVerifier verifies the ID tokens of the issuers for audiences,
+// signed with the keys published at jwksURL
+func NewIDTokenVerifier(issuers, audiences []string, jwksURL string) *IDTokenVerifier {
+	return &IDTokenVerifier{
+		issuers:   issuers,
+		audiences: audiences,
+		jwksURL:   jwksURL,
+		client:    &http.Client{Timeout: idTokenRequestTimeout},
+	}
+}
+
+// idTokenClaims are the registered claims plus the profile ones; Apple sends
+// email_verified as a string
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Email         string `json:"email"`
+	EmailVerified any    `json:"email_verified"`
+	Name          string `json:"name"`
+	Nonce         string `json:"nonce"`
+}
+
+// Verify checks the token's signature, issuer, audience and expiry. Errors
+// wrapping ErrIDTokenInvalid reject the token; others mean the signing keys
+// couldn't be downloaded.
+func (v *IDTokenVerifier) Verify(ctx context.Context, rawToken string) (*IDTokenClaims, error) {
+	var keyErr error
+	var claims idTokenClaims
+	_, err := jwt.ParseWithClaims(rawToken, &claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, err := v.key(ctx, kid)
+		if err != nil {
+			keyErr = err
+		}
+		return key, err
+	},
+		jwt.WithValidMethods([]string{"RS256"}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if keyErr != nil && !errors.Is(keyErr, ErrIDTokenInvalid) {
+		return nil, keyErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrIDTokenInvalid, err)
+	}
+
+	if !slices.Contains(v.issuers, claims.Issuer) {
+		return nil, fmt.Errorf("%w: unexpected issuer %q", ErrIDTokenInvalid, claims.Issuer)
+	}
+	if !slices.ContainsFunc(claims.Audience, func(aud string) bool { return slices.Contains(v.audiences, aud) }) {
+		return nil, fmt.Errorf("%w: issued to another client", ErrIDTokenInvalid)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: no subject", ErrIDTokenInvalid)
+	}
+
+	verified, _ := claims.EmailVerified.(bool)
+	if s, ok := claims.EmailVerified.(string); ok {
+		verified = s == "true"
+	}
+	return &IDTokenClaims{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: verified,
+		Name:          claims.Name,
+		Nonce:         claims.Nonce,
+	}, nil
+}
+
+// key returns the signing key kid, downloading the provider's keys when they
+// are stale or don't include it
+func (v *IDTokenVerifier) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	age := time.Since(v.fetchedAt)
+	if key, ok := v.keys[kid]; ok && age < jwksTTL {
+		return key, nil
+	}
+	if v.keys == nil || age >= jwksRefetchInterval {
+		keys, err := v.fetchKeys(ctx)
+		if err != nil {
+			return nil, err
+		}
+		v.keys = keys
+		v.fetchedAt = time.Now()
+	}
+	if key, ok := v.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: unknown signing key %q", ErrIDTokenInvalid, kid)
+}
+
+// fetchKeys downloads the provider's JSON Web Key Set
+func (v *IDTokenVerifier) fetchKeys(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.jwksURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("id token: failed to download signing keys: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("id token: failed to download signing keys: %s", resp.Status)
+	}
+
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("id token: failed to parse signing keys: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	return keys, nil
+}
@@ -0,0 +1,172 @@
+package clients
+
+import (
+	"context"
+	"io"
+	"path"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/metrics"
+)
+
+// methodName reduces "/aiservice.AIService/DetectObjects" to "DetectObjects"
+func methodName(fullMethod string) string {
+	return path.Base(fullMethod)
+}
+
+// messageSize returns the encoded size of a protobuf message, or 0 for non-proto values
+func messageSize(msg any) int {
+	if m, ok := msg.(proto.Message); ok {
+		return proto.Size(m)
+	}
+	return 0
+}
+
+// requestIDUnaryInterceptor propagates the request ID to the AI Service as x-request-id
+func requestIDUnaryInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return invoker(withRequestID(ctx), method, req, reply, cc, opts...)
+}
+
+// requestIDStreamInterceptor propagates the request ID on streaming calls
+func requestIDStreamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return streamer(withRequestID(ctx), desc, cc, method, opts...)
+}
+
+// retryUnaryInterceptor retries failed unary calls with exponential backoff,
+// counting every retried attempt per method
+func retryUnaryInterceptor(config helpers.RetryConfig) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		name := methodName(method)
+		attempt := 0
+
+		return helpers.WithRetry(ctx, config, name, func() error {
+			if attempt > 0 {
+				metrics.GRPCClientRetries.WithLabelValues(name).Inc()
+			}
+			attempt++
+			return invoker(ctx, method, req, reply, cc, opts...)
+		})
+	}
+}
+
+// metricsUnaryInterceptor records latency, status code, and payload sizes of
+// every unary call attempt and logs failures with the request ID
+func metricsUnaryInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	duration := time.Since(start)
+
+	received := 0
+	if err == nil {
+		received = messageSize(reply)
+	}
+	code := status.Code(err)
+	name := methodName(method)
+
+	metrics.RecordGRPCClientCall(name, code.String(), duration.Seconds(), messageSize(req), received)
+	logCall(ctx, name, code.String(), duration, err)
+
+	return err
+}
+
+// metricsStreamInterceptor records stream lifetime, final status code, and
+// total bytes sent and received once the stream finishes
+func metricsStreamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	start := time.Now()
+	name := methodName(method)
+
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		duration := time.Since(start)
+		metrics.RecordGRPCClientCall(name, status.Code(err).String(), duration.Seconds(), 0, 0)
+		logCall(ctx, name, status.Code(err).String(), duration, err)
+		return nil, err
+	}
+
+	return &monitoredClientStream{ClientStream: stream, ctx: ctx, method: name, start: start}, nil
+}
+
+// monitoredClientStream wraps a grpc.ClientStream to track message sizes
+type monitoredClientStream struct {
+	grpc.ClientStream
+	ctx      context.Context
+	method   string
+	start    time.Time
+	sent     int
+	received int
+	done     bool
+}
+
+func (s *monitoredClientStream) SendMsg(m any) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil {
+		s.sent += messageSize(m)
+	} else if err != io.EOF {
+		s.finish(err)
+	}
+	return err
+}
+
+func (s *monitoredClientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case err == nil:
+		s.received += messageSize(m)
+	case err == io.EOF:
+		s.finish(nil)
+	default:
+		s.finish(err)
+	}
+	return err
+}
+
+// finish records the stream once; later errors are ignored
+func (s *monitoredClientStream) finish(err error) {
+	if s.done {
+		return
+	}
+	s.done = true
+
+	duration := time.Since(s.start)
+	code := status.Code(err).String()
+	metrics.RecordGRPCClientCall(s.method, code, duration.Seconds(), s.sent, s.received)
+	logCall(s.ctx, s.method, code, duration, err)
+}
+
+// logCall logs a finished gRPC call: failures at warn, successes at debug
+func logCall(ctx context.Context, method, code string, duration time.Duration, err error) {
+	fields := []zap.Field{
+		zap.String("grpc_method", method),
+		zap.String("grpc_code", code),
+		zap.Duration("latency", duration),
+	}
+
+	if err != nil {
+		logger.Ctx(ctx).Warn("AI Service gRPC call failed",
+			append(fields, zap.String("message", status.Convert(err).Message()))...,
+		)
+		return
+	}
+	logger.Ctx(ctx).Debug("AI Service gRPC call completed", fields...)
+}
+
+// withRequestID adds the request ID (and trace ID, if any) from context to gRPC metadata
+func withRequestID(ctx context.Context) context.Context {
+	// Request ID is placed on the request context by middleware.RequestID
+	if requestID := logger.RequestIDFromContext(ctx); requestID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-request-id", requestID)
+	}
+	// Trace ID is placed on the request context by middleware.TraceContext
+	if traceID := logger.TraceIDFromContext(ctx); traceID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-trace-id", traceID)
+	}
+	return ctx
+}
@@ -0,0 +1,169 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// oidcMaxResponseSize bounds the discovery and token responses read
+const oidcMaxResponseSize = 1 << 20
+
+// OIDCClient signs users in at an OpenID Connect provider with the
+// authorization code flow and PKCE, finding the provider's endpoints in its
+// discovery document
+type OIDCClient struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	client       *http.Client
+
+	mu       sync.Mutex
+	metadata *oidcMetadata
+	verifier *IDTokenVerifier
+}
+
+// oidcMetadata is the part of a discovery document the client uses
+type oidcMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// NewOIDCClient signs users in at issuer as clientID; an empty clientSecret
+// makes it a public client
+func NewOIDCClient(issuer, clientID, clientSecret string) *OIDCClient {
+	return &OIDCClient{
+		issuer:       strings.TrimRight(issuer, "/"),
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		client:       &http.Client{Timeout: idTokenRequestTimeout},
+	}
+}
+
+// AuthCodeURL returns the provider's sign-in page, which sends the user back
+// to redirectURI with a code and state. codeChallenge is the S256 PKCE
+// challenge of the verifier Exchange is given.
+func (c *OIDCClient) AuthCodeURL(ctx context.Context, redirectURI, state, nonce, codeChallenge string) (string, error) {
+	metadata, _, err := c.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {c.clientID},
+		"redirect_uri":          {redirectURI},
+		"scope":                 {"openid email profile"},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+	separator := "?"
+	if strings.Contains(metadata.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return metadata.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange redeems an authorization code for the claims of the user's
+// verified ID token. Errors wrapping ErrIDTokenInvalid reject the code or
+// token; others mean the provider couldn't be reached.
+func (c *OIDCClient) Exchange(ctx context.Context, code, redirectURI, codeVerifier string) (*IDTokenClaims, error) {
+	metadata, verifier, err := c.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+		"code_verifier": {codeVerifier},
+		"client_id":     {c.clientID},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if c.clientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(c.clientID), url.QueryEscape(c.clientSecret))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: token request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, oidcMaxResponseSize)).Decode(&body); err != nil && resp.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("oidc: failed to parse token response: %w", err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusBadRequest:
+		// invalid_grant and the like: the code is used up, expired or forged
+		return nil, fmt.Errorf("%w: code rejected: %s %s", ErrIDTokenInvalid, body.Error, body.ErrorDescription)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("oidc: token request failed: %s", resp.Status)
+	case body.IDToken == "":
+		return nil, fmt.Errorf("%w: no ID token in the token response", ErrIDTokenInvalid)
+	}
+	return verifier.Verify(ctx, body.IDToken)
+}
+
+// discover returns the provider's endpoints and ID token verifier, reading
+// its discovery document the first time
+func (c *OIDCClient) discover(ctx context.Context) (*oidcMetadata, *IDTokenVerifier, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.metadata != nil {
+		return c.metadata, c.verifier, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("oidc: failed to download discovery document: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("oidc: failed to download discovery document: %s", resp.Status)
+	}
+
+	var metadata oidcMetadata
+	if err := json.NewDecoder(io.LimitReader(resp.Body, oidcMaxResponseSize)).Decode(&metadata); err != nil {
+		return nil, nil, fmt.Errorf("oidc: failed to parse discovery document: %w", err)
+	}
+	// The document must be the issuer's own, or its tokens can't be trusted
+	if strings.TrimRight(metadata.Issuer, "/") != c.issuer {
+		return nil, nil, fmt.Errorf("oidc: discovery document is for issuer %q", metadata.Issuer)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, nil, fmt.Errorf("oidc: discovery document lacks endpoints")
+	}
+
+	c.metadata = &metadata
+	c.verifier = NewIDTokenVerifier([]string{metadata.Issuer}, []string{c.clientID}, metadata.JWKSURI)
+	return c.metadata, c.verifier, nil
+}
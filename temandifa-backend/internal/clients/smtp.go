@@ -0,0 +1,134 @@
+package clients
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// smtpTimeout bounds a whole delivery when the caller's context has no
+// deadline
+const smtpTimeout = 30 * time.Second
+
+// ErrEmailRejected means the mail server refused the recipient or the
+// message; retrying won't help
+var ErrEmailRejected = errors.New("email: message rejected")
+
+// SMTPClient sends plain text email through an SMTP relay. Port 465 uses
+// implicit TLS; on other ports the connection is upgraded with STARTTLS when
+// the server offers it, which is required before authenticating.
+type SMTPClient struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     *mail.Address
+}
+
+// NewSMTPClient creates a client sending as from, e.g.
+// "TemanDifa <no-reply@example.com>"
+func NewSMTPClient(host string, port int, username, password, from string) (*SMTPClient, error) {
+	address, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address: %w", err)
+	}
+	return &SMTPClient{host: host, port: port, username: username, password: password, from: address}, nil
+}
+
+// Send delivers a plain text message. Errors wrapping ErrEmailRejected are
+// permanent; others may succeed when retried.
+func (c *SMTPClient) Send(ctx context.Context, to, subject, body string) error {
+	recipient, err := mail.ParseAddress(to)
+	if err != nil {
+		return fmt.Errorf("%w: invalid recipient: %v", ErrEmailRejected, err)
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, smtpTimeout)
+		defer cancel()
+	}
+	deadline, _ := ctx.Deadline()
+
+	addr := net.JoinHostPort(c.host, strconv.Itoa(c.port))
+	tlsConfig := &tls.Config{ServerName: c.host, MinVersion: tls.VersionTLS12}
+	var conn net.Conn
+	if c.port == 465 {
+		conn, err = (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	_ = conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, c.host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("email: %w", err)
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && c.port != 465 {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("email: %w", err)
+		}
+	}
+	if c.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", c.username, c.password, c.host)); err != nil {
+			return fmt.Errorf("email: %w", err)
+		}
+	}
+
+	if err := client.Mail(c.from.Address); err != nil {
+		return smtpError(err)
+	}
+	if err := client.Rcpt(recipient.Address); err != nil {
+		return smtpError(err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return smtpError(err)
+	}
+	if _, err := w.Write(c.message(recipient, subject, body)); err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return smtpError(err)
+	}
+	return client.Quit()
+}
+
+// message formats the headers and body of a UTF-8 plain text message
+func (c *SMTPClient) message(to *mail.Address, subject, body string) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + c.from.String() + "\r\n")
+	b.WriteString("To: " + to.String() + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// smtpError treats permanent (5xx) replies as rejections
+func smtpError(err error) error {
+	var reply *textproto.Error
+	if errors.As(err, &reply) && reply.Code >= 500 {
+		return fmt.Errorf("%w: %v", ErrEmailRejected, err)
+	}
+	return fmt.Errorf("email: %w", err)
+}
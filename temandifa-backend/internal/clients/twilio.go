@@ -0,0 +1,76 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// twilioEndpoint is the Messages resource, formatted with the account SID
+	twilioEndpoint = "https://api.twilio.com/2010-04-01/Accounts/%s/Messages.json"
+	// twilioRequestTimeout bounds a single send
+	twilioRequestTimeout = 10 * time.Second
+)
+
+// ErrSMSRejected means the SMS provider refused the message for a reason
+// retrying won't fix (e.g. an invalid or unreachable number)
+var ErrSMSRejected = errors.New("sms: message rejected")
+
+// TwilioClient sends SMS through the Twilio Messages API
+type TwilioClient struct {
+	accountSID string
+	authToken  string
+	from       string
+	client     *http.Client
+}
+
+// NewTwilioClient creates a client sending from the given E.164 number
+func NewTwilioClient(accountSID, authToken, from string) *TwilioClient {
+	return &TwilioClient{
+		accountSID: accountSID,
+		authToken:  authToken,
+		from:       from,
+		client:     &http.Client{Timeout: twilioRequestTimeout},
+	}
+}
+
+// Send delivers an SMS to an E.164 number. Errors wrapping ErrSMSRejected
+// are permanent; others (network, rate limits, server errors) may succeed
+// when retried.
+func (c *TwilioClient) Send(ctx context.Context, to, body string) error {
+	form := url.Values{"To": {to}, "From": {c.from}, "Body": {body}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf(twilioEndpoint, c.accountSID), strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.accountSID, c.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 300 {
+		return nil
+	}
+
+	// https://www.twilio.com/docs/usage/twilios-response#response-formats-exceptions
+	var payload struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&payload)
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return fmt.Errorf("sms: %s %d", resp.Status, payload.Code)
+	}
+	return fmt.Errorf("%w: %s %d: %s", ErrSMSRejected, resp.Status, payload.Code, payload.Message)
+}
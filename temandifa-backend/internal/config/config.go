@@ -1,43 +1,61 @@
 package config
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
 
 	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/secrets"
 
-	"github.com/fsnotify/fsnotify"
+	"github.com/robfig/cron/v3"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 )
 
+// oidcProviderName matches the names in OIDC_PROVIDERS
+var oidcProviderName = regexp.MustCompile(`^[a-z0-9-]{1,32}$`)
+
 // Config holds all configuration values
 type Config struct {
-	// Server
-	Port         string
-	GinMode      string
-	ReadTimeout  time.Duration
-	WriteTimeout time.Duration
+	AppEnv string // dev, staging or prod (APP_ENV)
+
+	// Per-module slices, provided to fx separately (see Module); their
+	// fields are promoted so cfg.Port etc. keep working
+	ServerConfig
+	AuthConfig
+	AIConfig
+	CacheConfig
+	UploadConfig
 
 	// Database
+	DBDriver    string // postgres, or sqlite for local development (build with -tags sqlite)
 	DatabaseDSN string
 
 	// Database Connection Pool
-	DBMaxOpenConns    int
-	DBMaxIdleConns    int
-	DBConnMaxLifetime time.Duration
-	DBConnMaxIdleTime time.Duration
+	DBMaxOpenConns         int
+	DBMaxIdleConns         int
+	DBConnMaxLifetime      time.Duration
+	DBConnMaxIdleTime      time.Duration
+	DBAutoMigrate          bool          // Apply pending migrations on startup
+	DBMigrationLockTimeout time.Duration // How long startup waits for another instance's migrations
+	DBStatementTimeout     time.Duration // Server-side statement_timeout for every query (0 disables)
+	DBListenEnabled        bool          // LISTEN for change notifications to invalidate caches across instances
 
-	// Redis
-	RedisAddr     string
-	RedisPassword string
+	// Deleted User Purge
+	UserPurgeGracePeriod time.Duration // How long soft-deleted users are kept before hard deletion
+	UserPurgeInterval    time.Duration // How often the purge job runs
+	UserPurgeBatchSize   int           // Users selected per purge query
 
-	// JWT
-	JWTSecret string
-
-	// AI Service
-	AIServiceURL      string
-	AIServiceGRPCAddr string
+	// Read Replicas
+	DatabaseReplicaDSNs    []string      // Reads are routed here; writes and transactions stay on DB_DSN
+	DBReplicaCheckInterval time.Duration // How often replica health is probed for failback
 
 	// Rate Limiting (General API)
 	RateLimitRequests int
@@ -47,14 +65,137 @@ type Config struct {
 	AIRateLimitRequests int
 	AIRateLimitWindow   int
 
-	// AI Operation Timeouts
-	AIDetectTimeout     time.Duration
-	AIOCRTimeout        time.Duration
-	AITranscribeTimeout time.Duration
-	AIVQATimeout        time.Duration
+	// Per-user overrides of the AI rate limit are reloaded at this interval
+	RateLimitOverridesRefreshInterval time.Duration
+
+	// Maintenance Mode (API answers 503 except health and admin routes)
+	MaintenanceMode    bool
+	MaintenanceMessage string
+
+	// Feature Flags (FEATURE_<NAME>=true|false), keyed by lower-case name;
+	// persisted flags are reloaded every FeatureFlagsRefreshInterval
+	Features                    map[string]bool
+	FeatureFlagsRefreshInterval time.Duration
+
+	// In-app announcements are reloaded at this interval
+	AnnouncementsRefreshInterval time.Duration
+
+	// Remote Dynamic Config (rate limits, maintenance mode, feature flags)
+	RemoteConfigProvider     string // none, consul or etcd
+	RemoteConfigAddr         string
+	RemoteConfigPrefix       string
+	RemoteConfigToken        string
+	RemoteConfigPollInterval time.Duration // Retry delay for Consul, poll interval for etcd
+
+	// Health Checks
+	HealthCheckInterval time.Duration // How often dependencies are checked in the background
+	HealthHistorySize   int           // Number of recent results kept per component
+
+	// Alerting
+	AlertWebhookURLs     []string      // Generic webhooks receiving signed JSON alerts
+	AlertSlackWebhookURL string        // Slack incoming webhook
+	AlertWebhookSecret   string        // HMAC-SHA256 signing secret for generic webhooks
+	AlertCooldown        time.Duration // Minimum time between identical alerts
+	PanicAlertsEnabled   bool          // Forward recovered panics (grouped by fingerprint) as alerts
+
+	// Analytics Events
+	EventsSink          string        // none, log, or http
+	EventsHTTPURL       string        // Collector endpoint for the http sink
+	EventsHTTPToken     string        // Optional bearer token for the http sink
+	EventsBufferSize    int           // Buffered events before new ones are dropped
+	EventsBatchSize     int           // Max events per published batch
+	EventsFlushInterval time.Duration // Max time an event waits before publication
+
+	// Transactional Outbox
+	OutboxWebhookURLs   []string      // Endpoints receiving integration events (at-least-once)
+	OutboxWebhookSecret string        // HMAC-SHA256 signing secret for outbox webhooks
+	OutboxPollInterval  time.Duration // How often pending events are dispatched
+	OutboxBatchSize     int           // Max events claimed per poll
+	OutboxMaxAttempts   int           // Attempts before an event is marked failed
+	OutboxRetention     time.Duration // How long dispatched events are kept
+
+	// Webhook subscriptions of third parties (managed under /admin/webhooks)
+	WebhookDeliveryRetention time.Duration // How long delivery logs are kept
+
+	// Object Storage
+	ObjectStorageDriver    string // none, filesystem, or s3
+	ObjectStorageDir       string // Root directory for the filesystem driver
+	ObjectStorageEndpoint  string // S3-compatible endpoint host (e.g. s3.amazonaws.com, minio:9000)
+	ObjectStorageBucket    string
+	ObjectStorageRegion    string
+	ObjectStorageAccessKey string
+	ObjectStorageSecretKey string
+	ObjectStorageUseSSL    bool
+	// Create the bucket and install its lifecycle rules at startup
+	// (replacing any existing rules)
+	ObjectStorageManageBucket bool
+
+	// Database Backups
+	BackupEnabled        bool
+	BackupSchedule       string        // Standard 5-field cron expression (UTC)
+	BackupMethod         string        // pg_dump (uploaded to object storage) or snapshot (managed-DB API)
+	BackupPrefix         string        // Object key prefix for pg_dump archives
+	BackupRetentionCount int           // Most recent pg_dump archives kept; older ones are deleted
+	BackupMaxAge         time.Duration // Backup health turns unhealthy when the last success is older
+	BackupTimeout        time.Duration // Upper bound for a single backup run
+	BackupPgDumpPath     string
+	BackupSnapshotURL    string // Endpoint that triggers a managed-DB snapshot (POST)
+	BackupSnapshotToken  string // Optional bearer token for the snapshot endpoint
+
+	// Personal Data Exports (archives kept in object storage)
+	DataExportPrefix string        // Object key prefix for export archives
+	DataExportTTL    time.Duration // How long an archive can be downloaded before it is deleted
+
+	// Resumable Uploads (tus, received bytes kept in object storage)
+	ResumableUploadPrefix string        // Object key prefix for received bytes
+	ResumableUploadTTL    time.Duration // How long an upload can be resumed and used after it is created
 
-	// File Limits
-	MaxBodySize int64 // in bytes
+	// History Media Attachments (kept in object storage)
+	HistoryAttachmentPrefix      string // Object key prefix for attached images and recordings
+	HistoryAttachmentMaxPerEntry int    // Most attachments a history entry can have
+
+	// Signed Media URLs (presigned by S3, otherwise served by the API)
+	MediaURLTTL     time.Duration // How long a signed URL works
+	MediaURLSecret  string        // HMAC key of API-served URLs; with HS256, derived from JWT_SECRET (or the oldest JWT_SIGNING_KEYS key) when empty
+	MediaURLBaseURL string        // Public origin prefixed to API-served URLs (e.g. https://api.example.com); empty for relative URLs
+
+	// Push Notifications (Firebase Cloud Messaging)
+	FCMCredentialsFile string // Service account JSON key; empty disables push notifications
+	FCMProjectID       string // Defaults to the service account's project
+
+	// Notification Inbox
+	NotificationRetention time.Duration // How long notifications stay in the users' inbox
+
+	// SMS and Email (messages to people outside the app, e.g. emergency contacts)
+	SMSDriver        string // none, log (development) or twilio
+	TwilioAccountSID string
+	TwilioAuthToken  string
+	TwilioFromNumber string // Sender number in E.164 format
+	SMTPHost         string // Empty disables email
+	SMTPPort         int    // 465 uses implicit TLS, other ports STARTTLS when offered
+	SMTPUsername     string
+	SMTPPassword     string
+	SMTPFrom         string // Sender address, e.g. "TemanDifa <no-reply@example.com>"
+	PublicBaseURL    string // Public origin of the API for links in messages (e.g. https://api.example.com)
+
+	// Emergency Alerts
+	EmergencyMaxContacts int // Most emergency contacts a user can have
+	EmergencyAlertLimit  int // Alerts a user can send per EMERGENCY_ALERT_WINDOW
+	EmergencyAlertWindow time.Duration
+
+	// Tracing
+	TracingEnabled bool // Accept/generate W3C trace IDs and surface them in logs and errors
+
+	// Debug
+	PprofEnabled bool // Expose /debug/pprof and /debug/vars (admin only)
+
+	// Secret Managers
+	SecretsRefreshInterval time.Duration // How often secret references are re-resolved (0 disables)
+
+	secretOptions secrets.Options
+	secretRefs    map[string]string // config key -> secret reference
+	secretValues  map[string]string // config key -> resolved value at startup
+	settings      []Setting         // effective values (secrets masked) and their sources
 }
 
 // LoadConfig loads and validates configuration using Viper
@@ -70,75 +211,331 @@ func LoadConfig() (*Config, error) {
 	viper.SetDefault("RATE_LIMIT_REQUESTS", 60)
 	viper.SetDefault("RATE_LIMIT_WINDOW", 60)
 	viper.SetDefault("MAX_BODY_SIZE", 50*1024*1024)
+	viper.SetDefault("CLIENT_IP_HEADERS", "X-Forwarded-For,X-Real-IP")
+	viper.SetDefault("SHUTDOWN_DRAIN_DELAY", "0s")
+	viper.SetDefault("SHUTDOWN_TIMEOUT", "30s")
+	viper.SetDefault("SHUTDOWN_CACHE_WAIT", "5s")
+	viper.SetDefault("TLS_MODE", "off")
+	viper.SetDefault("TLS_RELOAD_INTERVAL", "1m")
+	viper.SetDefault("ACME_CACHE_DIR", "./data/acme")
+	viper.SetDefault("BATCH_MAX_REQUESTS", 20)
+	viper.SetDefault("BATCH_CONCURRENCY", 4)
+	setUploadDefaults()
+	viper.SetDefault("PPROF_ENABLED", false)
+
+	viper.SetDefault("DB_DRIVER", "postgres")
 
 	// Database Connection Pool defaults (optimized for production)
 	viper.SetDefault("DB_MAX_OPEN_CONNS", 25)
 	viper.SetDefault("DB_MAX_IDLE_CONNS", 25)
 	viper.SetDefault("DB_CONN_MAX_LIFETIME", "5m")
 	viper.SetDefault("DB_CONN_MAX_IDLE_TIME", "5m")
+	viper.SetDefault("DB_REPLICA_CHECK_INTERVAL", "5s")
+	viper.SetDefault("DB_AUTO_MIGRATE", true)
+	viper.SetDefault("DB_MIGRATION_LOCK_TIMEOUT", "5m")
+	viper.SetDefault("DB_STATEMENT_TIMEOUT", "15s")
+	viper.SetDefault("DB_LISTEN_ENABLED", true)
+
+	// Deleted user purge defaults
+	viper.SetDefault("USER_PURGE_GRACE_PERIOD", "720h") // 30 days
+	viper.SetDefault("USER_PURGE_INTERVAL", "24h")
+	viper.SetDefault("USER_PURGE_BATCH_SIZE", 100)
+	viper.SetDefault("TOKEN_CLEANUP_RETENTION", "168h") // 7 days
+	viper.SetDefault("TOKEN_CLEANUP_BATCH_SIZE", 1000)
+	viper.SetDefault("REFRESH_TOKEN_REUSE_GRACE", "10s")
+	viper.SetDefault("BLACKLIST_STATS_INTERVAL", "5m")
+	viper.SetDefault("TOKEN_EPOCH_REFRESH_INTERVAL", "30s")
+	viper.SetDefault("JWT_ALGORITHM", JWTAlgHS256)
+	viper.SetDefault("EMAIL_VERIFICATION_TTL", "48h")
+	viper.SetDefault("EMAIL_VERIFICATION_REQUIRED", false)
+	viper.SetDefault("PASSWORD_RESET_TTL", "30m")
+	viper.SetDefault("PASSWORD_RESET_URL", "")
+	viper.SetDefault("PASSWORD_RESET_LIMIT", 3)
+	viper.SetDefault("PASSWORD_RESET_WINDOW", "1h")
+	viper.SetDefault("MAGIC_LINK_TTL", "15m")
+	viper.SetDefault("MAGIC_LINK_URL", "")
+	viper.SetDefault("MAGIC_LINK_LIMIT", 3)
+	viper.SetDefault("MAGIC_LINK_WINDOW", "1h")
+	viper.SetDefault("EMAIL_CHANGE_TTL", "24h")
+	viper.SetDefault("EMAIL_CHANGE_LIMIT", 3)
+	viper.SetDefault("EMAIL_CHANGE_WINDOW", "24h")
+	viper.SetDefault("LOGIN_LOCKOUT_THRESHOLD", 5)
+	viper.SetDefault("LOGIN_LOCKOUT_IP_THRESHOLD", 20)
+	viper.SetDefault("LOGIN_LOCKOUT_WINDOW", "15m")
+	viper.SetDefault("LOGIN_LOCKOUT_DURATION", "15m")
+	viper.SetDefault("GOOGLE_CLIENT_IDS", "")
+	viper.SetDefault("APPLE_CLIENT_IDS", "")
+	viper.SetDefault("OIDC_PROVIDERS", "")
 
 	// AI Rate Limiting (stricter for resource-intensive endpoints)
 	viper.SetDefault("AI_RATE_LIMIT_REQUESTS", 10) // 10 requests per window
 	viper.SetDefault("AI_RATE_LIMIT_WINDOW", 60)   // 60 seconds
+	viper.SetDefault("RATE_LIMIT_OVERRIDES_REFRESH_INTERVAL", "30s")
+
+	// Maintenance mode and remote config defaults
+	viper.SetDefault("MAINTENANCE_MODE", false)
+	viper.SetDefault("MAINTENANCE_MESSAGE", "The service is under maintenance. Please try again later.")
+	viper.SetDefault("FLAGS_REFRESH_INTERVAL", "30s")
+	viper.SetDefault("ANNOUNCEMENTS_REFRESH_INTERVAL", "30s")
+	viper.SetDefault("REMOTE_CONFIG_PROVIDER", "none")
+	viper.SetDefault("REMOTE_CONFIG_PREFIX", "temandifa/config/")
+	viper.SetDefault("REMOTE_CONFIG_POLL_INTERVAL", "5s")
 
 	// AI Operation Timeouts (per operation type)
 	viper.SetDefault("AI_DETECT_TIMEOUT", "30s")
 	viper.SetDefault("AI_OCR_TIMEOUT", "45s")
 	viper.SetDefault("AI_TRANSCRIBE_TIMEOUT", "60s")
 	viper.SetDefault("AI_VQA_TIMEOUT", "90s")
+	viper.SetDefault("AI_SIMPLIFY_TIMEOUT", "60s")
 
-	// 2. Load from .env file directly if exists
-	viper.SetConfigFile(".env")
-	viper.SetConfigType("env")
+	// Text simplification limits
+	viper.SetDefault("SIMPLIFY_MAX_CHARS", 20000)
+	viper.SetDefault("SIMPLIFY_CHUNK_CHARS", 300)
 
-	// Try to read config file, ignore if not found (will rely on env vars)
-	if err := viper.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			logger.Info("No .env file found, relying on environment variables")
-		}
-	} else {
-		logger.Info("Configuration loaded from .env file")
+	// Scene narration defaults
+	viper.SetDefault("NARRATE_MIN_FRAME_INTERVAL", "1s")
+	viper.SetDefault("NARRATE_MAX_FRAMES", 900)
+	viper.SetDefault("NARRATE_MAX_DURATION", "15m")
+	viper.SetDefault("NARRATE_IDLE_TIMEOUT", "30s")
+
+	// AI result and user cache TTLs
+	viper.SetDefault("CACHE_DETECTION_TTL", "1h")
+	viper.SetDefault("CACHE_OCR_TTL", "2h")
+	viper.SetDefault("CACHE_TRANSCRIPTION_TTL", "30m")
+	viper.SetDefault("CACHE_VQA_TTL", "24h")
+	viper.SetDefault("CACHE_SIMPLIFY_TTL", "24h")
+	viper.SetDefault("CACHE_USER_TTL", "5m")
+	viper.SetDefault("CACHE_ANALYTICS_TTL", "5m")
 
-		// Enable config hot reload
-		viper.WatchConfig()
-		viper.OnConfigChange(func(e fsnotify.Event) {
-			logger.Info("Config file changed, reloading...",
-				zap.String("file", e.Name),
-			)
-			// Note: Most config changes require restart for full effect
-			// But some values can be hot-reloaded
-		})
+	// SLO defaults
+	viper.SetDefault("SLO_AVAILABILITY_TARGET", 0.995)
+	viper.SetDefault("SLO_LATENCY_TARGET", 0.95)
+	viper.SetDefault("SLO_DETECT_LATENCY", "2s")
+	viper.SetDefault("SLO_OCR_LATENCY", "3s")
+	viper.SetDefault("SLO_TRANSCRIBE_LATENCY", "10s")
+	viper.SetDefault("SLO_VQA_LATENCY", "10s")
+	viper.SetDefault("SLO_SIMPLIFY_LATENCY", "10s")
+
+	// Alerting defaults
+	viper.SetDefault("ALERT_COOLDOWN", "5m")
+
+	// Analytics event defaults
+	viper.SetDefault("EVENTS_SINK", "none")
+	viper.SetDefault("EVENTS_BUFFER_SIZE", 10000)
+	viper.SetDefault("EVENTS_BATCH_SIZE", 100)
+	viper.SetDefault("EVENTS_FLUSH_INTERVAL", "5s")
+
+	// Outbox defaults
+	viper.SetDefault("OUTBOX_POLL_INTERVAL", "2s")
+	viper.SetDefault("OUTBOX_BATCH_SIZE", 50)
+	viper.SetDefault("OUTBOX_MAX_ATTEMPTS", 12)
+	viper.SetDefault("OUTBOX_RETENTION", "168h")
+	viper.SetDefault("WEBHOOK_DELIVERY_RETENTION", "720h") // 30 days
+
+	// Object storage defaults
+	viper.SetDefault("OBJECT_STORAGE_DRIVER", "none")
+	viper.SetDefault("OBJECT_STORAGE_DIR", "./data/objects")
+	viper.SetDefault("OBJECT_STORAGE_USE_SSL", true)
+	viper.SetDefault("OBJECT_STORAGE_MANAGE_BUCKET", false)
+
+	// Backup defaults
+	viper.SetDefault("BACKUP_ENABLED", false)
+	viper.SetDefault("BACKUP_SCHEDULE", "0 2 * * *") // Daily at 02:00 UTC
+	viper.SetDefault("BACKUP_METHOD", "pg_dump")
+	viper.SetDefault("BACKUP_PREFIX", "backups/")
+	viper.SetDefault("BACKUP_RETENTION_COUNT", 7)
+	viper.SetDefault("BACKUP_MAX_AGE", "26h")
+	viper.SetDefault("BACKUP_TIMEOUT", "1h")
+	viper.SetDefault("BACKUP_PG_DUMP_PATH", "pg_dump")
+
+	// Data export defaults
+	viper.SetDefault("DATA_EXPORT_PREFIX", "exports/")
+	viper.SetDefault("DATA_EXPORT_TTL", "168h") // 7 days
+	viper.SetDefault("RESUMABLE_UPLOAD_PREFIX", "uploads/")
+	viper.SetDefault("RESUMABLE_UPLOAD_TTL", "24h")
+	viper.SetDefault("HISTORY_ATTACHMENT_PREFIX", "attachments/")
+	viper.SetDefault("HISTORY_ATTACHMENT_MAX_PER_ENTRY", 5)
+	viper.SetDefault("MEDIA_URL_TTL", "15m")
+
+	// Notification inbox defaults
+	viper.SetDefault("NOTIFICATION_RETENTION", "2160h") // 90 days
+
+	// SMS and email defaults
+	viper.SetDefault("SMS_DRIVER", "none")
+	viper.SetDefault("SMTP_PORT", 587)
+	viper.SetDefault("PUBLIC_BASE_URL", "http://localhost:8080")
+
+	// Emergency alert defaults
+	viper.SetDefault("EMERGENCY_MAX_CONTACTS", 5)
+	viper.SetDefault("EMERGENCY_ALERT_LIMIT", 3)
+	viper.SetDefault("EMERGENCY_ALERT_WINDOW", "1h")
+
+	// Secret manager defaults
+	viper.SetDefault("SECRETS_REFRESH_INTERVAL", "15m")
+
+	// Health check defaults
+	viper.SetDefault("HEALTH_CHECK_INTERVAL", "10s")
+	viper.SetDefault("HEALTH_HISTORY_SIZE", 30)
+
+	// 2. Layer APP_ENV profile defaults, then .env.base, .env.<APP_ENV> and
+	// .env (each overriding the previous), then environment variables
+	env, files, err := LoadEnv()
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		logger.Info("No config files found, relying on environment variables", zap.String("app_env", env))
+	} else {
+		logger.Info("Configuration files loaded", zap.String("app_env", env), zap.Strings("files", files))
 	}
 
-	// 3. Auto-read Environment Variables
-	// This makes viper read variables directly from OS environment
-	viper.AutomaticEnv()
+	// 3. Replace secret references with values from the secret managers
+	secretOpts := secretOptions()
+	secretRefs, secretValues, err := resolveSecrets(context.Background(), secrets.NewResolver(secretOpts))
+	if err != nil {
+		return nil, err
+	}
+	if len(secretRefs) > 0 {
+		logger.Info("Secret references resolved", zap.Int("count", len(secretRefs)))
+	}
 
 	// 4. Bind values to struct
 	cfg := &Config{
-		// Server
-		Port:         viper.GetString("PORT"),
-		GinMode:      viper.GetString("GIN_MODE"),
-		ReadTimeout:  viper.GetDuration("READ_TIMEOUT"),
-		WriteTimeout: viper.GetDuration("WRITE_TIMEOUT"),
+		AppEnv: env,
 
-		// Database
-		DatabaseDSN:       viper.GetString("DB_DSN"),
-		DBMaxOpenConns:    viper.GetInt("DB_MAX_OPEN_CONNS"),
-		DBMaxIdleConns:    viper.GetInt("DB_MAX_IDLE_CONNS"),
-		DBConnMaxLifetime: viper.GetDuration("DB_CONN_MAX_LIFETIME"),
-		DBConnMaxIdleTime: viper.GetDuration("DB_CONN_MAX_IDLE_TIME"),
+		ServerConfig: ServerConfig{
+			Port:         viper.GetString("PORT"),
+			InstanceID:   instanceID(),
+			GinMode:      viper.GetString("GIN_MODE"),
+			ReadTimeout:  viper.GetDuration("READ_TIMEOUT"),
+			WriteTimeout: viper.GetDuration("WRITE_TIMEOUT"),
+			MaxBodySize:  viper.GetInt64("MAX_BODY_SIZE"),
+
+			TrustedProxies:  splitList(viper.GetString("TRUSTED_PROXIES")),
+			ClientIPHeaders: splitList(viper.GetString("CLIENT_IP_HEADERS")),
+
+			ShutdownDrainDelay: viper.GetDuration("SHUTDOWN_DRAIN_DELAY"),
+			ShutdownTimeout:    viper.GetDuration("SHUTDOWN_TIMEOUT"),
+			ShutdownCacheWait:  viper.GetDuration("SHUTDOWN_CACHE_WAIT"),
+
+			TLSMode:           strings.ToLower(viper.GetString("TLS_MODE")),
+			TLSCertFile:       viper.GetString("TLS_CERT_FILE"),
+			TLSKeyFile:        viper.GetString("TLS_KEY_FILE"),
+			TLSReloadInterval: viper.GetDuration("TLS_RELOAD_INTERVAL"),
+			TLSHTTPPort:       viper.GetString("TLS_HTTP_PORT"),
+			ACMEDomains:       splitList(viper.GetString("ACME_DOMAINS")),
+			ACMEEmail:         viper.GetString("ACME_EMAIL"),
+			ACMECacheDir:      viper.GetString("ACME_CACHE_DIR"),
+			ACMEDirectoryURL:  viper.GetString("ACME_DIRECTORY_URL"),
+
+			GRPCPort: viper.GetString("GRPC_PORT"),
+
+			BatchMaxRequests: viper.GetInt("BATCH_MAX_REQUESTS"),
+			BatchConcurrency: viper.GetInt("BATCH_CONCURRENCY"),
+		},
+
+		AuthConfig: AuthConfig{
+			JWTSecret:             viper.GetString("JWT_SECRET"),
+			JWTSigningKeys:        ParseJWTKeys(viper.GetString("JWT_SIGNING_KEYS")),
+			JWTSigningKeyID:       strings.TrimSpace(viper.GetString("JWT_SIGNING_KEY_ID")),
+			JWTAlgorithm:          strings.TrimSpace(viper.GetString("JWT_ALGORITHM")),
+			JWTPrivateKeys:        jwtPrivateKeys(viper.GetString("JWT_PRIVATE_KEYS")),
+			TokenCleanupRetention: viper.GetDuration("TOKEN_CLEANUP_RETENTION"),
+			TokenCleanupBatchSize: viper.GetInt("TOKEN_CLEANUP_BATCH_SIZE"),
+
+			RefreshTokenReuseGrace: viper.GetDuration("REFRESH_TOKEN_REUSE_GRACE"),
+
+			BlacklistStatsInterval: viper.GetDuration("BLACKLIST_STATS_INTERVAL"),
+			EpochRefreshInterval:   viper.GetDuration("TOKEN_EPOCH_REFRESH_INTERVAL"),
+
+			EmailVerificationTTL:      viper.GetDuration("EMAIL_VERIFICATION_TTL"),
+			EmailVerificationRequired: viper.GetBool("EMAIL_VERIFICATION_REQUIRED"),
+
+			PasswordResetTTL:    viper.GetDuration("PASSWORD_RESET_TTL"),
+			PasswordResetURL:    viper.GetString("PASSWORD_RESET_URL"),
+			PasswordResetLimit:  viper.GetInt("PASSWORD_RESET_LIMIT"),
+			PasswordResetWindow: viper.GetDuration("PASSWORD_RESET_WINDOW"),
+
+			MagicLinkTTL:    viper.GetDuration("MAGIC_LINK_TTL"),
+			MagicLinkURL:    viper.GetString("MAGIC_LINK_URL"),
+			MagicLinkLimit:  viper.GetInt("MAGIC_LINK_LIMIT"),
+			MagicLinkWindow: viper.GetDuration("MAGIC_LINK_WINDOW"),
 
-		// Redis
-		RedisAddr:     viper.GetString("REDIS_ADDR"),
-		RedisPassword: viper.GetString("REDIS_PASSWORD"),
+			EmailChangeTTL:    viper.GetDuration("EMAIL_CHANGE_TTL"),
+			EmailChangeLimit:  viper.GetInt("EMAIL_CHANGE_LIMIT"),
+			EmailChangeWindow: viper.GetDuration("EMAIL_CHANGE_WINDOW"),
 
-		// JWT
-		JWTSecret: viper.GetString("JWT_SECRET"),
+			LoginLockoutThreshold:   viper.GetInt("LOGIN_LOCKOUT_THRESHOLD"),
+			LoginLockoutIPThreshold: viper.GetInt("LOGIN_LOCKOUT_IP_THRESHOLD"),
+			LoginLockoutWindow:      viper.GetDuration("LOGIN_LOCKOUT_WINDOW"),
+			LoginLockoutDuration:    viper.GetDuration("LOGIN_LOCKOUT_DURATION"),
 
-		// AI Service
-		AIServiceURL:      viper.GetString("AI_SERVICE_URL"),
-		AIServiceGRPCAddr: viper.GetString("AI_SERVICE_GRPC_ADDR"),
+			GoogleClientIDs: splitList(viper.GetString("GOOGLE_CLIENT_IDS")),
+			AppleClientIDs:  splitList(viper.GetString("APPLE_CLIENT_IDS")),
+			OIDCProviders:   oidcProviders(),
+		},
+
+		AIConfig: AIConfig{
+			AIServiceURL:      viper.GetString("AI_SERVICE_URL"),
+			AIServiceGRPCAddr: viper.GetString("AI_SERVICE_GRPC_ADDR"),
+
+			AIDetectTimeout:     viper.GetDuration("AI_DETECT_TIMEOUT"),
+			AIOCRTimeout:        viper.GetDuration("AI_OCR_TIMEOUT"),
+			AITranscribeTimeout: viper.GetDuration("AI_TRANSCRIBE_TIMEOUT"),
+			AIVQATimeout:        viper.GetDuration("AI_VQA_TIMEOUT"),
+			AISimplifyTimeout:   viper.GetDuration("AI_SIMPLIFY_TIMEOUT"),
+
+			SimplifyMaxChars:   viper.GetInt("SIMPLIFY_MAX_CHARS"),
+			SimplifyChunkChars: viper.GetInt("SIMPLIFY_CHUNK_CHARS"),
+
+			SLOAvailabilityTarget: viper.GetFloat64("SLO_AVAILABILITY_TARGET"),
+			SLOLatencyTarget:      viper.GetFloat64("SLO_LATENCY_TARGET"),
+			SLODetectLatency:      viper.GetDuration("SLO_DETECT_LATENCY"),
+			SLOOCRLatency:         viper.GetDuration("SLO_OCR_LATENCY"),
+			SLOTranscribeLatency:  viper.GetDuration("SLO_TRANSCRIBE_LATENCY"),
+			SLOVQALatency:         viper.GetDuration("SLO_VQA_LATENCY"),
+			SLOSimplifyLatency:    viper.GetDuration("SLO_SIMPLIFY_LATENCY"),
+
+			NarrateMinFrameInterval: viper.GetDuration("NARRATE_MIN_FRAME_INTERVAL"),
+			NarrateMaxFrames:        viper.GetInt("NARRATE_MAX_FRAMES"),
+			NarrateMaxDuration:      viper.GetDuration("NARRATE_MAX_DURATION"),
+			NarrateIdleTimeout:      viper.GetDuration("NARRATE_IDLE_TIMEOUT"),
+		},
+
+		CacheConfig: CacheConfig{
+			RedisAddr:        viper.GetString("REDIS_ADDR"),
+			RedisPassword:    viper.GetString("REDIS_PASSWORD"),
+			DetectionTTL:     viper.GetDuration("CACHE_DETECTION_TTL"),
+			OCRTTL:           viper.GetDuration("CACHE_OCR_TTL"),
+			TranscriptionTTL: viper.GetDuration("CACHE_TRANSCRIPTION_TTL"),
+			VQATTL:           viper.GetDuration("CACHE_VQA_TTL"),
+			SimplifyTTL:      viper.GetDuration("CACHE_SIMPLIFY_TTL"),
+			UserTTL:          viper.GetDuration("CACHE_USER_TTL"),
+			AnalyticsTTL:     viper.GetDuration("CACHE_ANALYTICS_TTL"),
+		},
+
+		UploadConfig: loadUploadConfig(),
+
+		// Database
+		DBDriver:               strings.ToLower(viper.GetString("DB_DRIVER")),
+		DatabaseDSN:            viper.GetString("DB_DSN"),
+		DBMaxOpenConns:         viper.GetInt("DB_MAX_OPEN_CONNS"),
+		DBMaxIdleConns:         viper.GetInt("DB_MAX_IDLE_CONNS"),
+		DBConnMaxLifetime:      viper.GetDuration("DB_CONN_MAX_LIFETIME"),
+		DBConnMaxIdleTime:      viper.GetDuration("DB_CONN_MAX_IDLE_TIME"),
+		DBAutoMigrate:          viper.GetBool("DB_AUTO_MIGRATE"),
+		DBMigrationLockTimeout: viper.GetDuration("DB_MIGRATION_LOCK_TIMEOUT"),
+		DBStatementTimeout:     viper.GetDuration("DB_STATEMENT_TIMEOUT"),
+		DBListenEnabled:        viper.GetBool("DB_LISTEN_ENABLED"),
+
+		// Deleted User Purge
+		UserPurgeGracePeriod: viper.GetDuration("USER_PURGE_GRACE_PERIOD"),
+		UserPurgeInterval:    viper.GetDuration("USER_PURGE_INTERVAL"),
+		UserPurgeBatchSize:   viper.GetInt("USER_PURGE_BATCH_SIZE"),
+
+		// Read Replicas
+		DatabaseReplicaDSNs:    splitList(viper.GetString("DB_REPLICA_DSNS")),
+		DBReplicaCheckInterval: viper.GetDuration("DB_REPLICA_CHECK_INTERVAL"),
 
 		// Rate Limiting
 		RateLimitRequests:   viper.GetInt("RATE_LIMIT_REQUESTS"),
@@ -146,14 +543,132 @@ func LoadConfig() (*Config, error) {
 		AIRateLimitRequests: viper.GetInt("AI_RATE_LIMIT_REQUESTS"),
 		AIRateLimitWindow:   viper.GetInt("AI_RATE_LIMIT_WINDOW"),
 
-		// AI Timeouts
-		AIDetectTimeout:     viper.GetDuration("AI_DETECT_TIMEOUT"),
-		AIOCRTimeout:        viper.GetDuration("AI_OCR_TIMEOUT"),
-		AITranscribeTimeout: viper.GetDuration("AI_TRANSCRIBE_TIMEOUT"),
-		AIVQATimeout:        viper.GetDuration("AI_VQA_TIMEOUT"),
+		RateLimitOverridesRefreshInterval: viper.GetDuration("RATE_LIMIT_OVERRIDES_REFRESH_INTERVAL"),
+
+		// Maintenance Mode
+		MaintenanceMode:    viper.GetBool("MAINTENANCE_MODE"),
+		MaintenanceMessage: viper.GetString("MAINTENANCE_MESSAGE"),
+
+		// Feature Flags
+		Features:                    featureFlags(),
+		FeatureFlagsRefreshInterval: viper.GetDuration("FLAGS_REFRESH_INTERVAL"),
+
+		// Announcements
+		AnnouncementsRefreshInterval: viper.GetDuration("ANNOUNCEMENTS_REFRESH_INTERVAL"),
+
+		// Remote Dynamic Config
+		RemoteConfigProvider:     strings.ToLower(viper.GetString("REMOTE_CONFIG_PROVIDER")),
+		RemoteConfigAddr:         viper.GetString("REMOTE_CONFIG_ADDR"),
+		RemoteConfigPrefix:       viper.GetString("REMOTE_CONFIG_PREFIX"),
+		RemoteConfigToken:        viper.GetString("REMOTE_CONFIG_TOKEN"),
+		RemoteConfigPollInterval: viper.GetDuration("REMOTE_CONFIG_POLL_INTERVAL"),
+
+		// Health Checks
+		HealthCheckInterval: viper.GetDuration("HEALTH_CHECK_INTERVAL"),
+		HealthHistorySize:   viper.GetInt("HEALTH_HISTORY_SIZE"),
+
+		// Alerting
+		AlertWebhookURLs:     splitList(viper.GetString("ALERT_WEBHOOK_URLS")),
+		AlertSlackWebhookURL: viper.GetString("ALERT_SLACK_WEBHOOK_URL"),
+		AlertWebhookSecret:   viper.GetString("ALERT_WEBHOOK_SECRET"),
+		AlertCooldown:        viper.GetDuration("ALERT_COOLDOWN"),
+		PanicAlertsEnabled:   viper.GetBool("PANIC_ALERTS_ENABLED"),
+
+		// Analytics Events
+		EventsSink:          strings.ToLower(viper.GetString("EVENTS_SINK")),
+		EventsHTTPURL:       viper.GetString("EVENTS_HTTP_URL"),
+		EventsHTTPToken:     viper.GetString("EVENTS_HTTP_TOKEN"),
+		EventsBufferSize:    viper.GetInt("EVENTS_BUFFER_SIZE"),
+		EventsBatchSize:     viper.GetInt("EVENTS_BATCH_SIZE"),
+		EventsFlushInterval: viper.GetDuration("EVENTS_FLUSH_INTERVAL"),
+
+		// Transactional Outbox
+		OutboxWebhookURLs:   splitList(viper.GetString("OUTBOX_WEBHOOK_URLS")),
+		OutboxWebhookSecret: viper.GetString("OUTBOX_WEBHOOK_SECRET"),
+		OutboxPollInterval:  viper.GetDuration("OUTBOX_POLL_INTERVAL"),
+		OutboxBatchSize:     viper.GetInt("OUTBOX_BATCH_SIZE"),
+		OutboxMaxAttempts:   viper.GetInt("OUTBOX_MAX_ATTEMPTS"),
+		OutboxRetention:     viper.GetDuration("OUTBOX_RETENTION"),
+
+		WebhookDeliveryRetention: viper.GetDuration("WEBHOOK_DELIVERY_RETENTION"),
+
+		// Object Storage
+		ObjectStorageDriver:    strings.ToLower(viper.GetString("OBJECT_STORAGE_DRIVER")),
+		ObjectStorageDir:       viper.GetString("OBJECT_STORAGE_DIR"),
+		ObjectStorageEndpoint:  viper.GetString("OBJECT_STORAGE_ENDPOINT"),
+		ObjectStorageBucket:    viper.GetString("OBJECT_STORAGE_BUCKET"),
+		ObjectStorageRegion:    viper.GetString("OBJECT_STORAGE_REGION"),
+		ObjectStorageAccessKey: viper.GetString("OBJECT_STORAGE_ACCESS_KEY"),
+		ObjectStorageSecretKey: viper.GetString("OBJECT_STORAGE_SECRET_KEY"),
+		ObjectStorageUseSSL:    viper.GetBool("OBJECT_STORAGE_USE_SSL"),
+
+		ObjectStorageManageBucket: viper.GetBool("OBJECT_STORAGE_MANAGE_BUCKET"),
+
+		// Database Backups
+		BackupEnabled:        viper.GetBool("BACKUP_ENABLED"),
+		BackupSchedule:       viper.GetString("BACKUP_SCHEDULE"),
+		BackupMethod:         strings.ToLower(viper.GetString("BACKUP_METHOD")),
+		BackupPrefix:         viper.GetString("BACKUP_PREFIX"),
+		BackupRetentionCount: viper.GetInt("BACKUP_RETENTION_COUNT"),
+		BackupMaxAge:         viper.GetDuration("BACKUP_MAX_AGE"),
+		BackupTimeout:        viper.GetDuration("BACKUP_TIMEOUT"),
+		BackupPgDumpPath:     viper.GetString("BACKUP_PG_DUMP_PATH"),
+		BackupSnapshotURL:    viper.GetString("BACKUP_SNAPSHOT_URL"),
+		BackupSnapshotToken:  viper.GetString("BACKUP_SNAPSHOT_TOKEN"),
+
+		// Personal Data Exports
+		DataExportPrefix: viper.GetString("DATA_EXPORT_PREFIX"),
+		DataExportTTL:    viper.GetDuration("DATA_EXPORT_TTL"),
+
+		// Resumable Uploads
+		ResumableUploadPrefix: viper.GetString("RESUMABLE_UPLOAD_PREFIX"),
+		ResumableUploadTTL:    viper.GetDuration("RESUMABLE_UPLOAD_TTL"),
+
+		// History Media Attachments
+		HistoryAttachmentPrefix:      viper.GetString("HISTORY_ATTACHMENT_PREFIX"),
+		HistoryAttachmentMaxPerEntry: viper.GetInt("HISTORY_ATTACHMENT_MAX_PER_ENTRY"),
+
+		// Signed Media URLs
+		MediaURLTTL:     viper.GetDuration("MEDIA_URL_TTL"),
+		MediaURLSecret:  viper.GetString("MEDIA_URL_SECRET"),
+		MediaURLBaseURL: strings.TrimRight(viper.GetString("MEDIA_URL_BASE_URL"), "/"),
+
+		// Push Notifications
+		FCMCredentialsFile: viper.GetString("FCM_CREDENTIALS_FILE"),
+		FCMProjectID:       viper.GetString("FCM_PROJECT_ID"),
+
+		// Notification Inbox
+		NotificationRetention: viper.GetDuration("NOTIFICATION_RETENTION"),
+
+		// SMS and Email
+		SMSDriver:        strings.ToLower(viper.GetString("SMS_DRIVER")),
+		TwilioAccountSID: viper.GetString("TWILIO_ACCOUNT_SID"),
+		TwilioAuthToken:  viper.GetString("TWILIO_AUTH_TOKEN"),
+		TwilioFromNumber: viper.GetString("TWILIO_FROM_NUMBER"),
+		SMTPHost:         viper.GetString("SMTP_HOST"),
+		SMTPPort:         viper.GetInt("SMTP_PORT"),
+		SMTPUsername:     viper.GetString("SMTP_USERNAME"),
+		SMTPPassword:     viper.GetString("SMTP_PASSWORD"),
+		SMTPFrom:         viper.GetString("SMTP_FROM"),
+		PublicBaseURL:    strings.TrimRight(viper.GetString("PUBLIC_BASE_URL"), "/"),
 
-		// File Limits
-		MaxBodySize: viper.GetInt64("MAX_BODY_SIZE"),
+		// Emergency Alerts
+		EmergencyMaxContacts: viper.GetInt("EMERGENCY_MAX_CONTACTS"),
+		EmergencyAlertLimit:  viper.GetInt("EMERGENCY_ALERT_LIMIT"),
+		EmergencyAlertWindow: viper.GetDuration("EMERGENCY_ALERT_WINDOW"),
+
+		// Tracing
+		TracingEnabled: viper.GetBool("TRACING_ENABLED"),
+
+		// Debug
+		PprofEnabled: viper.GetBool("PPROF_ENABLED"),
+
+		// Secret Managers
+		SecretsRefreshInterval: viper.GetDuration("SECRETS_REFRESH_INTERVAL"),
+		secretOptions:          secretOpts,
+		secretRefs:             secretRefs,
+		secretValues:           secretValues,
+		settings:               snapshotSettings(env, secretRefs),
 	}
 
 	if err := cfg.Validate(); err != nil {
@@ -163,21 +678,449 @@ func LoadConfig() (*Config, error) {
 	return cfg, nil
 }
 
+// splitList parses a comma-separated config value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// ParseJWTKeys parses JWT_SIGNING_KEYS, a comma-separated list of
+// <kid>:<secret> pairs; ValidateJWTKeys rejects malformed entries
+func ParseJWTKeys(value string) []JWTKey {
+	var keys []JWTKey
+	for _, item := range splitList(value) {
+		id, secret, _ := strings.Cut(item, ":")
+		keys = append(keys, JWTKey{ID: strings.TrimSpace(id), Secret: strings.TrimSpace(secret)})
+	}
+	return keys
+}
+
+// jwtPrivateKeys parses JWT_PRIVATE_KEYS, a comma-separated list of
+// <kid>:<PEM file> pairs
+func jwtPrivateKeys(value string) []JWTKey {
+	var keys []JWTKey
+	for _, item := range splitList(value) {
+		id, file, _ := strings.Cut(item, ":")
+		keys = append(keys, JWTKey{ID: strings.TrimSpace(id), File: strings.TrimSpace(file)})
+	}
+	return keys
+}
+
+// validateJWTPrivateKeys checks the asymmetric signing keys; their kids
+// can't be those of HMAC keys, or tokens could be checked against the wrong
+// key. The files are read by the token service.
+func (c *Config) validateJWTPrivateKeys() error {
+	if len(c.JWTPrivateKeys) == 0 {
+		return fmt.Errorf("JWT_ALGORITHM %s needs JWT_PRIVATE_KEYS", c.JWTAlgorithm)
+	}
+	seen := make(map[string]bool, len(c.JWTSigningKeys)+len(c.JWTPrivateKeys))
+	for _, key := range c.JWTSigningKeys {
+		seen[key.ID] = true
+	}
+	for _, key := range c.JWTPrivateKeys {
+		if key.ID == "" || key.File == "" {
+			return fmt.Errorf("JWT_PRIVATE_KEYS entries must be <kid>:<PEM file>")
+		}
+		if seen[key.ID] {
+			return fmt.Errorf("JWT_PRIVATE_KEYS key %s is already a JWT key ID", key.ID)
+		}
+		seen[key.ID] = true
+	}
+	if c.JWTSigningKeyID != "" && !slices.ContainsFunc(c.JWTPrivateKeys, func(key JWTKey) bool { return key.ID == c.JWTSigningKeyID }) {
+		return fmt.Errorf("JWT_SIGNING_KEY_ID %s is not in JWT_PRIVATE_KEYS", c.JWTSigningKeyID)
+	}
+	return nil
+}
+
+// ValidateJWTKeys checks the JWT signing keys and that signingID, when set,
+// names one of them
+func ValidateJWTKeys(keys []JWTKey, signingID string) error {
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if key.ID == "" || len(key.Secret) < 32 {
+			return fmt.Errorf("JWT_SIGNING_KEYS entries must be <kid>:<secret> with secrets of at least 32 characters")
+		}
+		if seen[key.ID] {
+			return fmt.Errorf("JWT_SIGNING_KEYS has key %s twice", key.ID)
+		}
+		seen[key.ID] = true
+	}
+	if signingID != "" && !seen[signingID] {
+		return fmt.Errorf("JWT_SIGNING_KEY_ID %s is not in JWT_SIGNING_KEYS", signingID)
+	}
+	return nil
+}
+
+// oidcProviders reads the OIDC_<NAME>_* settings of the providers named in
+// OIDC_PROVIDERS
+func oidcProviders() []OIDCProvider {
+	var providers []OIDCProvider
+	for _, name := range splitList(viper.GetString("OIDC_PROVIDERS")) {
+		name = strings.ToLower(name)
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		provider := OIDCProvider{
+			Name:         name,
+			Issuer:       strings.TrimRight(viper.GetString(prefix+"ISSUER"), "/"),
+			ClientID:     viper.GetString(prefix + "CLIENT_ID"),
+			ClientSecret: viper.GetString(prefix + "CLIENT_SECRET"),
+			Organization: strings.TrimSpace(viper.GetString(prefix + "ORGANIZATION")),
+			Plan:         strings.ToLower(strings.TrimSpace(viper.GetString(prefix + "PLAN"))),
+			EmailDomains: splitList(strings.ToLower(viper.GetString(prefix + "EMAIL_DOMAINS"))),
+		}
+		if provider.Organization == "" {
+			provider.Organization = name
+		}
+		providers = append(providers, provider)
+	}
+	return providers
+}
+
+// instanceID returns INSTANCE_ID, or the host name when it is unset
+func instanceID() string {
+	if id := strings.TrimSpace(viper.GetString("INSTANCE_ID")); id != "" {
+		return id
+	}
+	if host, err := os.Hostname(); err == nil {
+		return host
+	}
+	return "unknown"
+}
+
 // Validate checks required configuration
 func (c *Config) Validate() error {
+	switch c.AppEnv {
+	case EnvDev, EnvStaging, EnvProd:
+	default:
+		return fmt.Errorf("APP_ENV must be one of dev, staging, prod")
+	}
+	if c.AppEnv == EnvProd && c.GinMode != "release" {
+		logger.Warn("GIN_MODE is not release in prod", zap.String("gin_mode", c.GinMode))
+	}
+
 	// Database DSN is required
 	if c.DatabaseDSN == "" {
 		return fmt.Errorf("DB_DSN is required")
 	}
 
+	switch c.DBDriver {
+	case "postgres":
+	case "sqlite":
+		// Replication, LISTEN/NOTIFY and backups rely on Postgres
+		if len(c.DatabaseReplicaDSNs) > 0 {
+			return fmt.Errorf("DB_REPLICA_DSNS is not supported with DB_DRIVER=sqlite")
+		}
+		if c.BackupEnabled {
+			return fmt.Errorf("BACKUP_ENABLED is not supported with DB_DRIVER=sqlite")
+		}
+	default:
+		return fmt.Errorf("DB_DRIVER must be one of postgres, sqlite")
+	}
+
 	// JWT Secret validation
-	if c.JWTSecret == "" {
-		return fmt.Errorf("JWT_SECRET is required")
-	} else if len(c.JWTSecret) < 32 {
+	if c.JWTSecret != "" && len(c.JWTSecret) < 32 {
 		return fmt.Errorf("JWT_SECRET must be at least 32 characters for security")
 	}
+	switch c.JWTAlgorithm {
+	case JWTAlgHS256:
+		if c.JWTSecret == "" && len(c.JWTSigningKeys) == 0 {
+			return fmt.Errorf("JWT_SECRET or JWT_SIGNING_KEYS is required")
+		}
+		if len(c.JWTPrivateKeys) > 0 {
+			return fmt.Errorf("JWT_PRIVATE_KEYS needs JWT_ALGORITHM %s or %s", JWTAlgRS256, JWTAlgEdDSA)
+		}
+		if err := ValidateJWTKeys(c.JWTSigningKeys, c.JWTSigningKeyID); err != nil {
+			return err
+		}
+	case JWTAlgRS256, JWTAlgEdDSA:
+		// The HMAC keys only verify tokens issued before the switch
+		if err := ValidateJWTKeys(c.JWTSigningKeys, ""); err != nil {
+			return err
+		}
+		if err := c.validateJWTPrivateKeys(); err != nil {
+			return err
+		}
+		// Media URLs are signed with an HMAC key, which can't come from the
+		// HMAC secrets once they are retired
+		if c.MediaURLSecret == "" {
+			return fmt.Errorf("JWT_ALGORITHM %s needs MEDIA_URL_SECRET", c.JWTAlgorithm)
+		}
+	default:
+		return fmt.Errorf("JWT_ALGORITHM must be one of %s, %s, %s", JWTAlgHS256, JWTAlgRS256, JWTAlgEdDSA)
+	}
+
+	if c.SLOAvailabilityTarget <= 0 || c.SLOAvailabilityTarget >= 1 ||
+		c.SLOLatencyTarget <= 0 || c.SLOLatencyTarget >= 1 {
+		return fmt.Errorf("SLO_AVAILABILITY_TARGET and SLO_LATENCY_TARGET must be between 0 and 1")
+	}
+
+	if c.NarrateMinFrameInterval < 0 || c.NarrateMaxFrames <= 0 || c.NarrateMaxDuration <= 0 || c.NarrateIdleTimeout <= 0 {
+		return fmt.Errorf("NARRATE_MAX_FRAMES, NARRATE_MAX_DURATION and NARRATE_IDLE_TIMEOUT must be positive")
+	}
+
+	if len(c.DatabaseReplicaDSNs) > 0 && c.DBReplicaCheckInterval <= 0 {
+		return fmt.Errorf("DB_REPLICA_CHECK_INTERVAL must be positive")
+	}
+
+	if c.UserPurgeGracePeriod <= 0 || c.UserPurgeInterval <= 0 || c.UserPurgeBatchSize <= 0 {
+		return fmt.Errorf("USER_PURGE_GRACE_PERIOD, USER_PURGE_INTERVAL and USER_PURGE_BATCH_SIZE must be positive")
+	}
+
+	if c.TokenCleanupRetention <= 0 || c.TokenCleanupBatchSize <= 0 {
+		return fmt.Errorf("TOKEN_CLEANUP_RETENTION and TOKEN_CLEANUP_BATCH_SIZE must be positive")
+	}
+
+	if c.RefreshTokenReuseGrace < 0 {
+		return fmt.Errorf("REFRESH_TOKEN_REUSE_GRACE must not be negative")
+	}
+
+	if c.BlacklistStatsInterval <= 0 {
+		return fmt.Errorf("BLACKLIST_STATS_INTERVAL must be positive")
+	}
+
+	if c.EpochRefreshInterval <= 0 {
+		return fmt.Errorf("TOKEN_EPOCH_REFRESH_INTERVAL must be positive")
+	}
+
+	if c.EmailVerificationTTL <= 0 {
+		return fmt.Errorf("EMAIL_VERIFICATION_TTL must be positive")
+	}
+	if c.EmailVerificationRequired && c.SMTPHost == "" {
+		logger.Warn("EMAIL_VERIFICATION_REQUIRED is set without SMTP_HOST; new users can't receive verification links")
+	}
+	if c.PasswordResetTTL <= 0 || c.PasswordResetLimit <= 0 || c.PasswordResetWindow <= 0 {
+		return fmt.Errorf("PASSWORD_RESET_TTL, PASSWORD_RESET_LIMIT and PASSWORD_RESET_WINDOW must be positive")
+	}
+	if c.PasswordResetURL != "" {
+		if u, err := url.Parse(c.PasswordResetURL); err != nil || u.Scheme == "" {
+			return fmt.Errorf("PASSWORD_RESET_URL must be an absolute URL")
+		}
+	}
+	if c.MagicLinkTTL <= 0 || c.MagicLinkLimit <= 0 || c.MagicLinkWindow <= 0 {
+		return fmt.Errorf("MAGIC_LINK_TTL, MAGIC_LINK_LIMIT and MAGIC_LINK_WINDOW must be positive")
+	}
+	if c.MagicLinkURL != "" {
+		if u, err := url.Parse(c.MagicLinkURL); err != nil || u.Scheme == "" {
+			return fmt.Errorf("MAGIC_LINK_URL must be an absolute URL")
+		}
+	}
+	if c.EmailChangeTTL <= 0 || c.EmailChangeLimit <= 0 || c.EmailChangeWindow <= 0 {
+		return fmt.Errorf("EMAIL_CHANGE_TTL, EMAIL_CHANGE_LIMIT and EMAIL_CHANGE_WINDOW must be positive")
+	}
+	if c.LoginLockoutThreshold < 0 || c.LoginLockoutIPThreshold < 0 {
+		return fmt.Errorf("LOGIN_LOCKOUT_THRESHOLD and LOGIN_LOCKOUT_IP_THRESHOLD must not be negative")
+	}
+	if c.LoginLockoutWindow <= 0 || c.LoginLockoutDuration <= 0 {
+		return fmt.Errorf("LOGIN_LOCKOUT_WINDOW and LOGIN_LOCKOUT_DURATION must be positive")
+	}
+
+	oidcNames := make(map[string]bool)
+	for _, provider := range c.OIDCProviders {
+		if !oidcProviderName.MatchString(provider.Name) || oidcNames[provider.Name] {
+			return fmt.Errorf("OIDC_PROVIDERS must be distinct names of lower-case letters, digits and dashes (at most 32)")
+		}
+		oidcNames[provider.Name] = true
+		if u, err := url.Parse(provider.Issuer); err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("OIDC provider %s: ISSUER must be an https URL", provider.Name)
+		}
+		if provider.ClientID == "" {
+			return fmt.Errorf("OIDC provider %s: CLIENT_ID is required", provider.Name)
+		}
+		if len(provider.Organization) > 64 || len(provider.Plan) > 32 {
+			return fmt.Errorf("OIDC provider %s: ORGANIZATION must be at most 64 and PLAN at most 32 characters", provider.Name)
+		}
+	}
+
+	switch c.RemoteConfigProvider {
+	case "none":
+	case "consul", "etcd":
+		if c.RemoteConfigAddr == "" {
+			return fmt.Errorf("REMOTE_CONFIG_ADDR is required when REMOTE_CONFIG_PROVIDER=%s", c.RemoteConfigProvider)
+		}
+		if c.RemoteConfigPollInterval <= 0 {
+			return fmt.Errorf("REMOTE_CONFIG_POLL_INTERVAL must be positive")
+		}
+	default:
+		return fmt.Errorf("REMOTE_CONFIG_PROVIDER must be one of none, consul, etcd")
+	}
+
+	for _, proxy := range c.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			return fmt.Errorf("TRUSTED_PROXIES: %q is not an IP address or CIDR", proxy)
+		}
+	}
+
+	if c.ShutdownDrainDelay < 0 || c.ShutdownCacheWait < 0 {
+		return fmt.Errorf("SHUTDOWN_DRAIN_DELAY and SHUTDOWN_CACHE_WAIT must not be negative")
+	}
+	if c.ShutdownTimeout <= 0 {
+		return fmt.Errorf("SHUTDOWN_TIMEOUT must be positive")
+	}
+	if c.BatchMaxRequests <= 0 || c.BatchConcurrency <= 0 {
+		return fmt.Errorf("BATCH_MAX_REQUESTS and BATCH_CONCURRENCY must be positive")
+	}
+	if c.ShutdownTimeout < c.AITranscribeTimeout {
+		logger.Warn("SHUTDOWN_TIMEOUT is shorter than AI_TRANSCRIBE_TIMEOUT; transcriptions may be cut off on shutdown",
+			zap.Duration("shutdown_timeout", c.ShutdownTimeout),
+			zap.Duration("transcribe_timeout", c.AITranscribeTimeout),
+		)
+	}
+
+	switch c.TLSMode {
+	case "off":
+	case "file":
+		if c.TLSCertFile == "" || c.TLSKeyFile == "" {
+			return fmt.Errorf("TLS_CERT_FILE and TLS_KEY_FILE are required when TLS_MODE=file")
+		}
+		if c.TLSReloadInterval <= 0 {
+			return fmt.Errorf("TLS_RELOAD_INTERVAL must be positive")
+		}
+	case "acme":
+		if len(c.ACMEDomains) == 0 {
+			return fmt.Errorf("ACME_DOMAINS is required when TLS_MODE=acme")
+		}
+		if c.ACMECacheDir == "" {
+			return fmt.Errorf("ACME_CACHE_DIR is required when TLS_MODE=acme")
+		}
+	default:
+		return fmt.Errorf("TLS_MODE must be one of off, file, acme")
+	}
+	if c.TLSMode != "off" && c.TLSHTTPPort == c.Port {
+		return fmt.Errorf("TLS_HTTP_PORT must differ from PORT")
+	}
+	if c.GRPCPort != "" && (c.GRPCPort == c.Port || (c.TLSMode != "off" && c.GRPCPort == c.TLSHTTPPort)) {
+		return fmt.Errorf("GRPC_PORT must differ from PORT and TLS_HTTP_PORT")
+	}
+
+	if err := c.validateUploads(); err != nil {
+		return err
+	}
+
+	if c.DetectionTTL <= 0 || c.OCRTTL <= 0 || c.TranscriptionTTL <= 0 || c.VQATTL <= 0 || c.SimplifyTTL <= 0 || c.UserTTL <= 0 || c.AnalyticsTTL <= 0 {
+		return fmt.Errorf("CACHE_*_TTL values must be positive")
+	}
+
+	if c.SimplifyMaxChars <= 0 {
+		return fmt.Errorf("SIMPLIFY_MAX_CHARS must be positive")
+	}
+	if c.SimplifyChunkChars < 40 {
+		return fmt.Errorf("SIMPLIFY_CHUNK_CHARS must be at least 40")
+	}
+
+	if c.RateLimitOverridesRefreshInterval <= 0 {
+		return fmt.Errorf("RATE_LIMIT_OVERRIDES_REFRESH_INTERVAL must be positive")
+	}
+
+	if c.FeatureFlagsRefreshInterval <= 0 {
+		return fmt.Errorf("FLAGS_REFRESH_INTERVAL must be positive")
+	}
+
+	if c.AnnouncementsRefreshInterval <= 0 {
+		return fmt.Errorf("ANNOUNCEMENTS_REFRESH_INTERVAL must be positive")
+	}
+
+	if c.HealthCheckInterval <= 0 {
+		return fmt.Errorf("HEALTH_CHECK_INTERVAL must be positive")
+	}
+
+	switch c.EventsSink {
+	case "none", "log":
+	case "http":
+		if c.EventsHTTPURL == "" {
+			return fmt.Errorf("EVENTS_HTTP_URL is required when EVENTS_SINK=http")
+		}
+	default:
+		return fmt.Errorf("EVENTS_SINK must be one of none, log, http")
+	}
+	if c.EventsBufferSize <= 0 || c.EventsBatchSize <= 0 || c.EventsFlushInterval <= 0 {
+		return fmt.Errorf("EVENTS_BUFFER_SIZE, EVENTS_BATCH_SIZE and EVENTS_FLUSH_INTERVAL must be positive")
+	}
+
+	if c.OutboxPollInterval <= 0 || c.OutboxBatchSize <= 0 || c.OutboxMaxAttempts <= 0 {
+		return fmt.Errorf("OUTBOX_POLL_INTERVAL, OUTBOX_BATCH_SIZE and OUTBOX_MAX_ATTEMPTS must be positive")
+	}
+	if c.WebhookDeliveryRetention <= 0 {
+		return fmt.Errorf("WEBHOOK_DELIVERY_RETENTION must be positive")
+	}
+
+	switch c.ObjectStorageDriver {
+	case "none", "filesystem":
+	case "s3":
+		if c.ObjectStorageEndpoint == "" || c.ObjectStorageBucket == "" {
+			return fmt.Errorf("OBJECT_STORAGE_ENDPOINT and OBJECT_STORAGE_BUCKET are required when OBJECT_STORAGE_DRIVER=s3")
+		}
+	default:
+		return fmt.Errorf("OBJECT_STORAGE_DRIVER must be one of none, filesystem, s3")
+	}
+
+	if c.BackupEnabled {
+		if _, err := cron.ParseStandard(c.BackupSchedule); err != nil {
+			return fmt.Errorf("BACKUP_SCHEDULE is not a valid cron expression: %w", err)
+		}
+		switch c.BackupMethod {
+		case "pg_dump":
+			if c.ObjectStorageDriver == "none" {
+				return fmt.Errorf("BACKUP_METHOD=pg_dump requires OBJECT_STORAGE_DRIVER")
+			}
+		case "snapshot":
+			if c.BackupSnapshotURL == "" {
+				return fmt.Errorf("BACKUP_SNAPSHOT_URL is required when BACKUP_METHOD=snapshot")
+			}
+		default:
+			return fmt.Errorf("BACKUP_METHOD must be one of pg_dump, snapshot")
+		}
+		if c.BackupRetentionCount <= 0 || c.BackupMaxAge <= 0 || c.BackupTimeout <= 0 {
+			return fmt.Errorf("BACKUP_RETENTION_COUNT, BACKUP_MAX_AGE and BACKUP_TIMEOUT must be positive")
+		}
+	}
+
+	if c.DataExportTTL <= 0 {
+		return fmt.Errorf("DATA_EXPORT_TTL must be positive")
+	}
+	if c.ResumableUploadTTL <= 0 {
+		return fmt.Errorf("RESUMABLE_UPLOAD_TTL must be positive")
+	}
+	if c.HistoryAttachmentPrefix == "" || c.HistoryAttachmentMaxPerEntry <= 0 {
+		return fmt.Errorf("HISTORY_ATTACHMENT_PREFIX must be set and HISTORY_ATTACHMENT_MAX_PER_ENTRY positive")
+	}
+	// S3 refuses presigned URLs valid for more than 7 days
+	if c.MediaURLTTL <= 0 || c.MediaURLTTL > 7*24*time.Hour {
+		return fmt.Errorf("MEDIA_URL_TTL must be positive and at most 168h")
+	}
+	if c.NotificationRetention <= 0 {
+		return fmt.Errorf("NOTIFICATION_RETENTION must be positive")
+	}
+
+	switch c.SMSDriver {
+	case "none", "log":
+	case "twilio":
+		if c.TwilioAccountSID == "" || c.TwilioAuthToken == "" || c.TwilioFromNumber == "" {
+			return fmt.Errorf("TWILIO_ACCOUNT_SID, TWILIO_AUTH_TOKEN and TWILIO_FROM_NUMBER are required when SMS_DRIVER=twilio")
+		}
+	default:
+		return fmt.Errorf("SMS_DRIVER must be one of none, log, twilio")
+	}
+	if c.SMTPHost != "" {
+		if c.SMTPFrom == "" {
+			return fmt.Errorf("SMTP_FROM is required when SMTP_HOST is set")
+		}
+		if c.SMTPPort <= 0 || c.SMTPPort > 65535 {
+			return fmt.Errorf("SMTP_PORT must be a valid port")
+		}
+	}
+	if u, err := url.Parse(c.PublicBaseURL); err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("PUBLIC_BASE_URL must be an absolute URL")
+	}
+	if c.EmergencyMaxContacts <= 0 || c.EmergencyAlertLimit <= 0 || c.EmergencyAlertWindow <= 0 {
+		return fmt.Errorf("EMERGENCY_MAX_CONTACTS, EMERGENCY_ALERT_LIMIT and EMERGENCY_ALERT_WINDOW must be positive")
+	}
 
 	logger.Info("Configuration loaded successfully",
+		zap.String("app_env", c.AppEnv),
 		zap.String("port", c.Port),
 		zap.String("mode", c.GinMode),
 		zap.String("ai_service", c.AIServiceURL),
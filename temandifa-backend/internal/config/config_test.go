@@ -0,0 +1,66 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/logger"
+)
+
+func TestLoadConfigJWTAlgorithms(t *testing.T) {
+	logger.Log = zap.NewNop()
+	const secret = "0123456789abcdef0123456789abcdef"
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string // empty when the config is valid
+	}{
+		{
+			name: "HS256 with a secret",
+			env:  map[string]string{"JWT_SECRET": secret},
+		},
+		{
+			name:    "HS256 without a secret",
+			env:     map[string]string{},
+			wantErr: "JWT_SECRET or JWT_SIGNING_KEYS is required",
+		},
+		{
+			name:    "RS256 without HMAC keys or media secret",
+			env:     map[string]string{"JWT_ALGORITHM": "RS256", "JWT_PRIVATE_KEYS": "rsa-1:/keys/rsa-1.pem"},
+			wantErr: "JWT_ALGORITHM RS256 needs MEDIA_URL_SECRET",
+		},
+		{
+			name:    "EdDSA with HMAC keys but no media secret",
+			env:     map[string]string{"JWT_ALGORITHM": "EdDSA", "JWT_PRIVATE_KEYS": "ed-1:/keys/ed-1.pem", "JWT_SECRET": secret},
+			wantErr: "JWT_ALGORITHM EdDSA needs MEDIA_URL_SECRET",
+		},
+		{
+			name: "RS256 with a media secret",
+			env:  map[string]string{"JWT_ALGORITHM": "RS256", "JWT_PRIVATE_KEYS": "rsa-1:/keys/rsa-1.pem", "MEDIA_URL_SECRET": secret},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+			t.Setenv("APP_ENV", "dev")
+			t.Setenv("DB_DSN", "file::memory:")
+			t.Setenv("DB_DRIVER", "sqlite")
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			_, err := LoadConfig()
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("LoadConfig() error = %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("LoadConfig() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package config
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/remoteconfig"
+)
+
+// featurePrefix names feature flag keys (FEATURE_<NAME>)
+const featurePrefix = "FEATURE_"
+
+// dynamicKeys are the keys the remote config store may override, with their
+// validators; FEATURE_<NAME> flags are accepted as booleans too
+var dynamicKeys = map[string]func(string) error{
+	"RATE_LIMIT_REQUESTS":    positiveInt,
+	"RATE_LIMIT_WINDOW":      positiveInt,
+	"AI_RATE_LIMIT_REQUESTS": positiveInt,
+	"AI_RATE_LIMIT_WINDOW":   positiveInt,
+	"MAINTENANCE_MODE":       parseBool,
+	"MAINTENANCE_MESSAGE":    func(string) error { return nil },
+}
+
+func positiveInt(value string) error {
+	n, err := strconv.Atoi(value)
+	if err == nil && n <= 0 {
+		return strconv.ErrRange
+	}
+	return err
+}
+
+func parseBool(value string) error {
+	_, err := strconv.ParseBool(value)
+	return err
+}
+
+// acceptDynamic reports whether the remote store may set key to value
+func acceptDynamic(key, value string) bool {
+	if name, ok := strings.CutPrefix(key, featurePrefix); ok && name != "" {
+		return parseBool(value) == nil
+	}
+	validate, ok := dynamicKeys[key]
+	return ok && validate(value) == nil
+}
+
+// featureFlags collects FEATURE_<NAME> values from the environment and config
+// files, keyed by lower-case name
+func featureFlags() map[string]bool {
+	keys := viper.AllKeys()
+	for _, env := range os.Environ() {
+		if key, _, ok := strings.Cut(env, "="); ok && strings.HasPrefix(key, featurePrefix) {
+			keys = append(keys, key) // AutomaticEnv keys aren't listed by AllKeys
+		}
+	}
+
+	flags := make(map[string]bool)
+	for _, key := range keys {
+		key = strings.ToUpper(key)
+		if name, ok := strings.CutPrefix(key, featurePrefix); ok && name != "" {
+			flags[strings.ToLower(name)] = viper.GetBool(key)
+		}
+	}
+	return flags
+}
+
+// Dynamic serves settings that can change at runtime: remote config store
+// values when present, otherwise the values loaded at startup
+type Dynamic struct {
+	cfg    *Config
+	remote atomic.Pointer[map[string]string]
+}
+
+// NewDynamic provides the runtime settings and, with REMOTE_CONFIG_PROVIDER
+// set, keeps them in sync with the remote store. An unreachable store at
+// startup is logged and the local values are used until it answers.
+func NewDynamic(lc fx.Lifecycle, cfg *Config, log *zap.Logger) (*Dynamic, error) {
+	d := &Dynamic{cfg: cfg}
+	d.remote.Store(&map[string]string{})
+
+	watcher, err := remoteconfig.NewWatcher(remoteconfig.Options{
+		Provider:     cfg.RemoteConfigProvider,
+		Addr:         cfg.RemoteConfigAddr,
+		Prefix:       cfg.RemoteConfigPrefix,
+		Token:        cfg.RemoteConfigToken,
+		PollInterval: cfg.RemoteConfigPollInterval,
+	}, acceptDynamic, log)
+	if err != nil || watcher == nil {
+		return d, err
+	}
+	watcher.OnChange(func(values map[string]string) {
+		d.remote.Store(&values)
+	})
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if err := watcher.Load(ctx); err != nil {
+				log.Warn("Remote config unavailable, using local values", zap.Error(err))
+			}
+			watcher.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			watcher.Stop()
+			return nil
+		},
+	})
+	return d, nil
+}
+
+func (d *Dynamic) lookup(key string) (string, bool) {
+	value, ok := (*d.remote.Load())[key]
+	return value, ok
+}
+
+func (d *Dynamic) int(key string, fallback int) int {
+	if value, ok := d.lookup(key); ok {
+		n, _ := strconv.Atoi(value) // validated by acceptDynamic
+		return n
+	}
+	return fallback
+}
+
+// RateLimit returns the general API rate limit
+func (d *Dynamic) RateLimit() (int, time.Duration) {
+	return d.int("RATE_LIMIT_REQUESTS", d.cfg.RateLimitRequests),
+		time.Duration(d.int("RATE_LIMIT_WINDOW", d.cfg.RateLimitWindow)) * time.Second
+}
+
+// AIRateLimit returns the per-user rate limit for AI endpoints
+func (d *Dynamic) AIRateLimit() (int, time.Duration) {
+	return d.int("AI_RATE_LIMIT_REQUESTS", d.cfg.AIRateLimitRequests),
+		time.Duration(d.int("AI_RATE_LIMIT_WINDOW", d.cfg.AIRateLimitWindow)) * time.Second
+}
+
+// Maintenance reports whether maintenance mode is on and the message to show
+func (d *Dynamic) Maintenance() (bool, string) {
+	enabled := d.cfg.MaintenanceMode
+	if value, ok := d.lookup("MAINTENANCE_MODE"); ok {
+		enabled, _ = strconv.ParseBool(value)
+	}
+	message := d.cfg.MaintenanceMessage
+	if value, ok := d.lookup("MAINTENANCE_MESSAGE"); ok && value != "" {
+		message = value
+	}
+	return enabled, message
+}
+
+// Feature reports whether the FEATURE_<NAME> flag is on; unknown flags are off
+func (d *Dynamic) Feature(name string) bool {
+	enabled, _ := d.FeatureOverride(name)
+	return enabled
+}
+
+// FeatureOverride returns the FEATURE_<NAME> value from the remote store or
+// the local config, and whether either sets it
+func (d *Dynamic) FeatureOverride(name string) (enabled, ok bool) {
+	if value, ok := d.lookup(featurePrefix + strings.ToUpper(name)); ok {
+		enabled, _ = strconv.ParseBool(value)
+		return enabled, true
+	}
+	enabled, ok = d.cfg.Features[strings.ToLower(name)]
+	return enabled, ok
+}
+
+// FeatureNames lists the flags set through FEATURE_<NAME> (lower-case)
+func (d *Dynamic) FeatureNames() []string {
+	names := make([]string, 0, len(d.cfg.Features))
+	for name := range d.cfg.Features {
+		names = append(names, name)
+	}
+	for key := range *d.remote.Load() {
+		if name, ok := strings.CutPrefix(key, featurePrefix); ok {
+			if _, local := d.cfg.Features[strings.ToLower(name)]; !local {
+				names = append(names, strings.ToLower(name))
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,171 @@
+package config
+
+import (
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Where an effective setting's value came from
+const (
+	SourceDefault = "default" // built-in default
+	SourceProfile = "profile" // APP_ENV profile default
+	SourceFile    = "file"    // .env.base, .env.<APP_ENV> or .env
+	SourceEnv     = "env"     // environment variable
+	SourceSecret  = "secret"  // resolved from a secret manager reference
+	SourceRemote  = "remote"  // remote config store override
+	SourceUnset   = "unset"
+)
+
+// unsetKeys are read by LoadConfig without a default; they are listed so the
+// effective config shows them even when nothing sets them
+var unsetKeys = []string{
+	"DB_DSN", "DB_REPLICA_DSNS", "REDIS_PASSWORD", "JWT_SECRET", "JWT_SIGNING_KEYS", "JWT_SIGNING_KEY_ID", "JWT_PRIVATE_KEYS",
+	"ALERT_WEBHOOK_URLS", "ALERT_WEBHOOK_SECRET", "ALERT_SLACK_WEBHOOK_URL", "PANIC_ALERTS_ENABLED",
+	"EVENTS_HTTP_URL", "EVENTS_HTTP_TOKEN", "OUTBOX_WEBHOOK_URLS", "OUTBOX_WEBHOOK_SECRET",
+	"OBJECT_STORAGE_ENDPOINT", "OBJECT_STORAGE_BUCKET", "OBJECT_STORAGE_REGION",
+	"OBJECT_STORAGE_ACCESS_KEY", "OBJECT_STORAGE_SECRET_KEY",
+	"BACKUP_SNAPSHOT_URL", "BACKUP_SNAPSHOT_TOKEN", "FCM_CREDENTIALS_FILE", "FCM_PROJECT_ID", "TRACING_ENABLED",
+	"TWILIO_ACCOUNT_SID", "TWILIO_AUTH_TOKEN", "TWILIO_FROM_NUMBER", "SMTP_HOST", "SMTP_USERNAME", "SMTP_PASSWORD", "SMTP_FROM",
+	"VAULT_ADDR", "VAULT_TOKEN", "VAULT_NAMESPACE",
+	"AWS_REGION", "AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN", "GCP_ACCESS_TOKEN",
+	"REMOTE_CONFIG_ADDR", "REMOTE_CONFIG_TOKEN",
+	"INSTANCE_ID", "TRUSTED_PROXIES", "TLS_CERT_FILE", "TLS_KEY_FILE", "TLS_HTTP_PORT", "ACME_DOMAINS", "ACME_EMAIL", "ACME_DIRECTORY_URL", "GRPC_PORT",
+	"UPLOAD_DETECT_MAX_SIZE", "UPLOAD_DETECT_TYPES", "UPLOAD_OCR_MAX_SIZE", "UPLOAD_OCR_TYPES",
+	"UPLOAD_ASK_MAX_SIZE", "UPLOAD_ASK_TYPES", "UPLOAD_TRANSCRIBE_MAX_SIZE", "UPLOAD_TRANSCRIBE_TYPES",
+}
+
+// sensitiveKey matches keys whose values are always masked
+var sensitiveKey = regexp.MustCompile(`(^|_)(SECRET|TOKEN|PASSWORD|ACCESS_KEY|SIGNING_KEYS|WEBHOOK_URLS?)($|_)`)
+
+// dsnPassword matches password=... in key/value DSNs
+var dsnPassword = regexp.MustCompile(`(?i)(password=)(\S+)`)
+
+// maskedValue replaces sensitive values in the effective config
+const maskedValue = "********"
+
+// Setting is one effective configuration value
+type Setting struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+	Masked bool   `json:"masked,omitempty"`
+}
+
+// snapshotSettings records every known key's value and source after loading;
+// secrets are masked here so raw values never leave LoadConfig
+func snapshotSettings(env string, secretRefs map[string]string) []Setting {
+	keys := make(map[string]bool)
+	for _, key := range viper.AllKeys() {
+		keys[strings.ToUpper(key)] = true
+	}
+	for _, key := range unsetKeys {
+		keys[key] = true
+	}
+	for _, key := range secretKeys {
+		keys[key] = true
+	}
+	for _, entry := range os.Environ() {
+		if key, _, ok := strings.Cut(entry, "="); ok && strings.HasPrefix(key, featurePrefix) {
+			keys[key] = true
+		}
+	}
+
+	settings := make([]Setting, 0, len(keys))
+	for key := range keys {
+		setting := Setting{Key: key, Value: viper.GetString(key), Source: settingSource(key, env, secretRefs)}
+		setting.Value, setting.Masked = maskSetting(key, setting.Value)
+		settings = append(settings, setting)
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Key < settings[j].Key })
+	return settings
+}
+
+// settingSource reports the highest-precedence layer that set key
+func settingSource(key, env string, secretRefs map[string]string) string {
+	if ref, ok := secretRefs[key]; ok {
+		scheme, _, _ := strings.Cut(ref, ":")
+		return SourceSecret + ":" + scheme
+	}
+	if _, ok := os.LookupEnv(key); ok {
+		return SourceEnv
+	}
+	if viper.InConfig(key) {
+		return SourceFile
+	}
+	if _, ok := profileDefaults[env][key]; ok {
+		return SourceProfile
+	}
+	if viper.IsSet(key) {
+		return SourceDefault
+	}
+	return SourceUnset
+}
+
+// maskSetting hides secret values; DSNs keep everything but the password
+func maskSetting(key, value string) (string, bool) {
+	if value == "" {
+		return value, false
+	}
+	if strings.Contains(key, "DSN") {
+		masked := maskDSNs(value)
+		return masked, masked != value
+	}
+	if sensitiveKey.MatchString(key) {
+		return maskedValue, true
+	}
+	return value, false
+}
+
+// maskDSNs masks the password of each comma-separated DSN
+func maskDSNs(value string) string {
+	dsns := strings.Split(value, ",")
+	for i, dsn := range dsns {
+		dsn = strings.TrimSpace(dsn)
+		if u, err := url.Parse(dsn); err == nil && u.User != nil {
+			if _, ok := u.User.Password(); ok {
+				u.User = url.UserPassword(u.User.Username(), maskedValue)
+			}
+			dsns[i] = u.String()
+			continue
+		}
+		dsns[i] = dsnPassword.ReplaceAllString(dsn, "${1}"+maskedValue)
+	}
+	return strings.Join(dsns, ",")
+}
+
+// Settings returns the effective configuration as loaded at startup
+func (c *Config) Settings() []Setting {
+	return append([]Setting(nil), c.settings...)
+}
+
+// Settings returns the effective configuration with the remote config
+// store's current overrides applied
+func (d *Dynamic) Settings() []Setting {
+	settings := d.cfg.Settings()
+	remote := *d.remote.Load()
+
+	seen := make(map[string]bool, len(remote))
+	for i, setting := range settings {
+		if value, ok := remote[setting.Key]; ok {
+			settings[i].Value, settings[i].Source = value, SourceRemote
+			seen[setting.Key] = true
+		}
+	}
+	for key, value := range remote {
+		if !seen[key] {
+			settings = append(settings, Setting{Key: key, Value: value, Source: SourceRemote})
+		}
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Key < settings[j].Key })
+	return settings
+}
+
+// AppEnv returns the environment the configuration was loaded for
+func (d *Dynamic) AppEnv() string {
+	return d.cfg.AppEnv
+}
@@ -0,0 +1,259 @@
+package config
+
+import (
+	"time"
+
+	"go.uber.org/fx"
+)
+
+// Module provides the loaded *Config, its per-module slices, the secret
+// watcher and the runtime (remote) settings. Components that only need one
+// area depend on its slice, which tests can build as a plain struct literal.
+var Module = fx.Options(
+	fx.Provide(
+		LoadConfig,
+		// Re-resolves secret manager references (SECRETS_REFRESH_INTERVAL)
+		NewSecretWatcher,
+		// Runtime settings, synced from REMOTE_CONFIG_PROVIDER when set
+		NewDynamic,
+		func(c *Config) *ServerConfig { return &c.ServerConfig },
+		func(c *Config) *AuthConfig { return &c.AuthConfig },
+		func(c *Config) *AIConfig { return &c.AIConfig },
+		func(c *Config) *CacheConfig { return &c.CacheConfig },
+		func(c *Config) *UploadConfig { return &c.UploadConfig },
+	),
+)
+
+// ServerConfig holds the HTTP server settings
+type ServerConfig struct {
+	Port         string
+	InstanceID   string // identifies this replica in logs and /admin/system (INSTANCE_ID, default the host name)
+	GinMode      string
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	MaxBodySize  int64 // in bytes
+
+	// Client IP resolution. Forwarding headers are only honoured on requests
+	// from a trusted proxy; otherwise the connection's address is used.
+	TrustedProxies  []string // IPs or CIDRs of load balancers / reverse proxies
+	ClientIPHeaders []string // checked in order, e.g. X-Forwarded-For, X-Real-IP
+
+	// Graceful shutdown
+	ShutdownDrainDelay time.Duration // readiness fails while requests are still served, so load balancers stop routing here
+	ShutdownTimeout    time.Duration // how long in-flight requests (e.g. long transcriptions) may take to finish
+	ShutdownCacheWait  time.Duration // how long pending async cache writes may take after the server stops
+
+	// Native TLS (for deployments without a TLS-terminating load balancer)
+	TLSMode           string        // off, file or acme
+	TLSCertFile       string        // file mode: PEM certificate chain
+	TLSKeyFile        string        // file mode: PEM private key
+	TLSReloadInterval time.Duration // file mode: how often the pair is checked for changes
+	TLSHTTPPort       string        // plain HTTP port redirecting to HTTPS (and serving ACME challenges); empty disables
+	ACMEDomains       []string
+	ACMEEmail         string
+	ACMECacheDir      string
+	ACMEDirectoryURL  string // empty means Let's Encrypt production
+
+	// gRPC API for the mobile app, served next to the REST API with the same
+	// TLS settings
+	GRPCPort string // empty disables it
+
+	// POST /batch
+	BatchMaxRequests int // sub-requests per batch
+	BatchConcurrency int // sub-requests of one batch served at a time
+}
+
+// StopTimeout is the time the application gets to shut down: the drain
+// delay, in-flight requests and cache writes plus headroom for the remaining
+// stop hooks (event flush, connection pools)
+func (c *ServerConfig) StopTimeout() time.Duration {
+	return c.ShutdownDrainDelay + c.ShutdownTimeout + c.ShutdownCacheWait + 15*time.Second
+}
+
+// AuthConfig holds token signing and cleanup settings
+type AuthConfig struct {
+	// JWTSecret verifies access tokens without a kid header and, without
+	// JWTSigningKeys, signs them
+	JWTSecret string
+	// JWTSigningKeys sign and verify access tokens, which name theirs in the
+	// kid header, so keys can be rotated without signing everyone out
+	// (JWT_SIGNING_KEYS, oldest first)
+	JWTSigningKeys  []JWTKey
+	JWTSigningKeyID string // Key new tokens are signed with; the newest when empty
+	// JWTAlgorithm signs new access tokens: JWTAlgHS256 with the secrets
+	// above, or JWTAlgRS256 or JWTAlgEdDSA with JWTPrivateKeys, whose public
+	// keys other services verify tokens with (/.well-known/jwks.json)
+	JWTAlgorithm string
+	// JWTPrivateKeys are the asymmetric signing keys by kid, oldest first
+	// (JWT_PRIVATE_KEYS); JWTSigningKeyID names one of them
+	JWTPrivateKeys []JWTKey
+
+	// Refresh Token Cleanup
+	TokenCleanupRetention time.Duration // How long expired or revoked refresh tokens are kept
+	TokenCleanupBatchSize int           // Rows deleted per cleanup statement
+
+	// A rotated refresh token presented again more than this long after its
+	// rotation was copied; its whole session is revoked. Within it the refresh
+	// is only refused, as the app may have retried a refresh it lost the
+	// answer to.
+	RefreshTokenReuseGrace time.Duration
+
+	// Access token blacklist gauges are refreshed (by SCAN) at this interval
+	BlacklistStatsInterval time.Duration
+
+	// The token epoch (bumped by a forced logout) is reloaded at this
+	// interval; with DB_LISTEN_ENABLED it also propagates immediately
+	EpochRefreshInterval time.Duration
+
+	// Email verification of new accounts
+	EmailVerificationTTL      time.Duration // How long a verification link stays valid
+	EmailVerificationRequired bool          // Unverified users can't use the AI endpoints
+
+	// Password reset through emailed single-use tokens
+	PasswordResetTTL    time.Duration // How long a reset token stays valid
+	PasswordResetURL    string        // Link in reset emails with the token appended; empty sends the token alone
+	PasswordResetLimit  int           // Reset requests per email address and window
+	PasswordResetWindow time.Duration
+
+	// Passwordless sign-in through emailed single-use links
+	MagicLinkTTL    time.Duration // How long a sign-in link stays valid
+	MagicLinkURL    string        // Link in sign-in emails with the token appended; empty links the API's verify endpoint
+	MagicLinkLimit  int           // Sign-in links per email address and window
+	MagicLinkWindow time.Duration
+
+	// Email address changes, confirmed through links to both addresses
+	EmailChangeTTL    time.Duration // How long the confirmation links stay valid
+	EmailChangeLimit  int           // Change requests per user and window
+	EmailChangeWindow time.Duration
+
+	// Login lockout: after this many failed logins within the window, an
+	// email address (or a client IP) can't log in for the duration; 0
+	// disables the check
+	LoginLockoutThreshold   int
+	LoginLockoutIPThreshold int
+	LoginLockoutWindow      time.Duration
+	LoginLockoutDuration    time.Duration
+
+	// Sign-in with provider ID tokens; empty disables the provider
+	GoogleClientIDs []string // OAuth client IDs of the apps (Android, iOS, web)
+	AppleClientIDs  []string // Bundle ID and services IDs
+
+	// Organizations' OpenID Connect identity providers, for institutional
+	// sign-in (OIDC_PROVIDERS)
+	OIDCProviders []OIDCProvider
+}
+
+// Access token signing algorithms (JWT_ALGORITHM)
+const (
+	JWTAlgHS256 = "HS256" // HMAC with JWT_SECRET or JWT_SIGNING_KEYS
+	JWTAlgRS256 = "RS256" // RSA with JWT_PRIVATE_KEYS
+	JWTAlgEdDSA = "EdDSA" // Ed25519 with JWT_PRIVATE_KEYS
+)
+
+// JWTKey is an access token signing key, named by ID in the tokens' kid
+// header: an HMAC secret, or the PEM file of a private key
+type JWTKey struct {
+	ID     string
+	Secret string
+	File   string
+}
+
+// OIDCProvider is an organization's OpenID Connect identity provider, set with
+// OIDC_<NAME>_* variables
+type OIDCProvider struct {
+	Name         string   // Lower-case name, in the sign-in URLs
+	Issuer       string   // Issuer URL; its discovery document is read from /.well-known/openid-configuration
+	ClientID     string   // Client registered at the provider for the API
+	ClientSecret string   // Empty for public clients, which rely on PKCE alone
+	Organization string   // Organization of the users it signs in; defaults to Name
+	Plan         string   // Plan given to its users on the free plan; empty keeps theirs
+	EmailDomains []string // Domains whose addresses the provider vouches for; empty requires email_verified
+}
+
+// AIConfig holds the AI service endpoints, per-operation timeouts and SLOs
+type AIConfig struct {
+	AIServiceURL      string
+	AIServiceGRPCAddr string
+
+	// AI Operation Timeouts
+	AIDetectTimeout     time.Duration
+	AIOCRTimeout        time.Duration
+	AITranscribeTimeout time.Duration
+	AIVQATimeout        time.Duration
+	AISimplifyTimeout   time.Duration
+
+	// Text simplification (/simplify)
+	SimplifyMaxChars   int // Longest text accepted, in characters
+	SimplifyChunkChars int // Upper bound of a TTS chunk's length, in characters
+
+	// AI Endpoint SLOs
+	SLOAvailabilityTarget float64       // Target ratio of non-5xx responses (e.g. 0.995)
+	SLOLatencyTarget      float64       // Target ratio of responses under the latency threshold
+	SLODetectLatency      time.Duration // Latency thresholds per operation
+	SLOOCRLatency         time.Duration
+	SLOTranscribeLatency  time.Duration
+	SLOVQALatency         time.Duration
+	SLOSimplifyLatency    time.Duration
+
+	// Scene narration over WebSocket (/ws/narrate), limits per connection
+	NarrateMinFrameInterval time.Duration // Frames arriving sooner after the last narrated one are dropped
+	NarrateMaxFrames        int           // Narrated frames before the connection is closed
+	NarrateMaxDuration      time.Duration // Connection lifetime
+	NarrateIdleTimeout      time.Duration // Closed when no message arrives for this long
+}
+
+// CacheConfig holds the Redis connection and cache TTLs
+type CacheConfig struct {
+	RedisAddr     string
+	RedisPassword string
+
+	// AI result TTLs per operation
+	DetectionTTL     time.Duration
+	OCRTTL           time.Duration
+	TranscriptionTTL time.Duration
+	VQATTL           time.Duration
+	SimplifyTTL      time.Duration
+
+	UserTTL      time.Duration // Cached user records (auth middleware)
+	AnalyticsTTL time.Duration // Cached admin usage analytics
+}
+
+// AI operations whose uploads can have their own limits
+const (
+	UploadOpDetect     = "detect"
+	UploadOpOCR        = "ocr"
+	UploadOpAsk        = "ask"
+	UploadOpTranscribe = "transcribe"
+)
+
+// OpSimplify is the AI operation simplifying text; it has no upload
+const OpSimplify = "simplify"
+
+// AIOperations lists the AI operations, e.g. for kill switches
+var AIOperations = []string{UploadOpDetect, UploadOpOCR, UploadOpTranscribe, UploadOpAsk, OpSimplify}
+
+// UploadLimit bounds one operation's uploaded file
+type UploadLimit struct {
+	MaxSize      int64           // in bytes
+	AllowedTypes map[string]bool // MIME types detected from the content
+}
+
+// UploadConfig holds upload limits: image operations (detect, ocr, ask)
+// default to the image limit, transcribe to the audio limit, and each may be
+// overridden with UPLOAD_<OPERATION>_MAX_SIZE / UPLOAD_<OPERATION>_TYPES
+type UploadConfig struct {
+	Operations map[string]UploadLimit
+
+	// Malware scanning of uploaded files
+	ScanDriver       string        // none or clamav
+	ScanMode         string        // inline (infected uploads are rejected) or async (scanned after the upload is used)
+	ScanTimeout      time.Duration // Upper bound for scanning one file
+	ScanFailOpen     bool          // inline: accept uploads while the scanner is unreachable
+	QuarantinePrefix string        // Object key prefix for infected files (with object storage)
+	ClamAVAddress    string        // clamd socket: tcp://host:port or unix:///path
+}
+
+// For returns the limit of an operation (one of the UploadOp constants)
+func (u *UploadConfig) For(operation string) UploadLimit {
+	return u.Operations[operation]
+}
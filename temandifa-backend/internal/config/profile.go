@@ -0,0 +1,81 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Application environments selected by APP_ENV
+const (
+	EnvDev     = "dev"
+	EnvStaging = "staging"
+	EnvProd    = "prod"
+)
+
+// profileDefaults override the built-in defaults per environment. Config
+// files and environment variables still take precedence.
+var profileDefaults = map[string]map[string]any{
+	EnvDev: {
+		"GIN_MODE": "debug",
+	},
+	EnvStaging: {
+		"GIN_MODE":      "release",
+		"PPROF_ENABLED": false,
+	},
+	EnvProd: {
+		"GIN_MODE":      "release",
+		"PPROF_ENABLED": false,
+	},
+}
+
+// AppEnv returns the environment named by APP_ENV (dev when unset). It is
+// read from the process environment because it decides which files load.
+func AppEnv() string {
+	switch env := strings.ToLower(strings.TrimSpace(os.Getenv("APP_ENV"))); env {
+	case "", "dev", "development", "local":
+		return EnvDev
+	case "stage", "staging":
+		return EnvStaging
+	case "prod", "production":
+		return EnvProd
+	default:
+		return env // rejected by Validate
+	}
+}
+
+// EnvFiles lists the config files for env in load order; later files
+// override earlier ones. A plain .env stays last for local overrides.
+func EnvFiles(env string) []string {
+	return []string{".env.base", ".env." + env, ".env"}
+}
+
+// LoadEnv applies the APP_ENV profile defaults, merges the environment's
+// config files into viper and enables environment variable lookup, so
+// commands that don't build a full Config (cmd/migrate, cmd/seed) read
+// settings exactly like the server. Missing files are skipped.
+func LoadEnv() (env string, loaded []string, err error) {
+	env = AppEnv()
+	for key, value := range profileDefaults[env] {
+		viper.SetDefault(key, value)
+	}
+
+	viper.SetConfigType("env")
+	for _, file := range EnvFiles(env) {
+		if _, err := os.Stat(file); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		viper.SetConfigFile(file)
+		if err := viper.MergeInConfig(); err != nil {
+			return env, loaded, fmt.Errorf("read %s: %w", file, err)
+		}
+		loaded = append(loaded, file)
+	}
+
+	viper.AutomaticEnv()
+	return env, loaded, nil
+}
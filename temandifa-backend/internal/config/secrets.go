@@ -0,0 +1,82 @@
+package config
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/viper"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/secrets"
+)
+
+// secretKeys may hold a secret reference (e.g. JWT_SECRET=vault:kv/temandifa#jwt)
+// instead of a literal value; see package secrets for the syntax
+var secretKeys = []string{
+	"DB_DSN",
+	"DB_REPLICA_DSNS",
+	"REDIS_PASSWORD",
+	"JWT_SECRET",
+	"JWT_SIGNING_KEYS",
+	"ALERT_WEBHOOK_SECRET",
+	"EVENTS_HTTP_TOKEN",
+	"OUTBOX_WEBHOOK_SECRET",
+	"OBJECT_STORAGE_ACCESS_KEY",
+	"OBJECT_STORAGE_SECRET_KEY",
+	"BACKUP_SNAPSHOT_TOKEN",
+	"REMOTE_CONFIG_TOKEN",
+}
+
+// secretOptions reads the secret manager settings from viper
+func secretOptions() secrets.Options {
+	return secrets.Options{
+		VaultAddr:          viper.GetString("VAULT_ADDR"),
+		VaultToken:         viper.GetString("VAULT_TOKEN"),
+		VaultNamespace:     viper.GetString("VAULT_NAMESPACE"),
+		AWSRegion:          viper.GetString("AWS_REGION"),
+		AWSAccessKeyID:     viper.GetString("AWS_ACCESS_KEY_ID"),
+		AWSSecretAccessKey: viper.GetString("AWS_SECRET_ACCESS_KEY"),
+		AWSSessionToken:    viper.GetString("AWS_SESSION_TOKEN"),
+		GCPAccessToken:     viper.GetString("GCP_ACCESS_TOKEN"),
+	}
+}
+
+// resolveSecrets replaces secret references among secretKeys with their
+// values in viper, returning the references and resolved values by key
+func resolveSecrets(ctx context.Context, resolver *secrets.Resolver) (refs, values map[string]string, err error) {
+	refs = make(map[string]string)
+	values = make(map[string]string)
+	for _, key := range secretKeys {
+		ref := viper.GetString(key)
+		if !secrets.IsRef(ref) {
+			continue
+		}
+		value, err := resolver.Resolve(ctx, ref)
+		if err != nil {
+			return nil, nil, fmt.Errorf("resolve %s: %w", key, err)
+		}
+		viper.Set(key, value)
+		refs[key] = ref
+		values[key] = value
+	}
+	return refs, values, nil
+}
+
+// NewSecretWatcher provides the watcher that re-resolves secret references
+// every SECRETS_REFRESH_INTERVAL; components register rotation hooks on it
+func NewSecretWatcher(lc fx.Lifecycle, cfg *Config, log *zap.Logger) *secrets.Watcher {
+	watcher := secrets.NewWatcher(secrets.NewResolver(cfg.secretOptions), cfg.secretRefs, cfg.secretValues, cfg.SecretsRefreshInterval, log)
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			watcher.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			watcher.Stop()
+			return nil
+		},
+	})
+	return watcher
+}
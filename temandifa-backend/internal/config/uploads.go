@@ -0,0 +1,103 @@
+package config
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// uploadOperations maps each operation to the default limit it inherits
+var uploadOperations = map[string]string{
+	UploadOpDetect:     "IMAGE",
+	UploadOpOCR:        "IMAGE",
+	UploadOpAsk:        "IMAGE",
+	UploadOpTranscribe: "AUDIO",
+}
+
+func setUploadDefaults() {
+	viper.SetDefault("UPLOAD_MAX_IMAGE_SIZE", 10*1024*1024) // 10MB
+	viper.SetDefault("UPLOAD_MAX_AUDIO_SIZE", 25*1024*1024) // 25MB
+	viper.SetDefault("UPLOAD_IMAGE_TYPES", "image/jpeg,image/png,image/webp,image/gif")
+	// video/webm is often used for audio-only recordings
+	viper.SetDefault("UPLOAD_AUDIO_TYPES", "audio/mpeg,audio/wav,audio/x-wav,audio/webm,audio/ogg,audio/mp4,audio/m4a,video/webm")
+
+	viper.SetDefault("MALWARE_SCAN_DRIVER", "none")
+	viper.SetDefault("MALWARE_SCAN_MODE", "inline")
+	viper.SetDefault("MALWARE_SCAN_TIMEOUT", "30s")
+	viper.SetDefault("MALWARE_SCAN_FAIL_OPEN", false)
+	viper.SetDefault("MALWARE_QUARANTINE_PREFIX", "quarantine/")
+	viper.SetDefault("CLAMAV_ADDRESS", "tcp://localhost:3310")
+}
+
+// loadUploadConfig resolves every operation's limit, applying the
+// per-operation overrides over the image/audio defaults
+func loadUploadConfig() UploadConfig {
+	uploads := UploadConfig{Operations: make(map[string]UploadLimit, len(uploadOperations))}
+	for op, kind := range uploadOperations {
+		prefix := "UPLOAD_" + strings.ToUpper(op)
+
+		maxSize := viper.GetInt64("UPLOAD_MAX_" + kind + "_SIZE")
+		if viper.IsSet(prefix + "_MAX_SIZE") {
+			maxSize = viper.GetInt64(prefix + "_MAX_SIZE")
+		}
+		types := viper.GetString("UPLOAD_" + kind + "_TYPES")
+		if override := viper.GetString(prefix + "_TYPES"); override != "" {
+			types = override
+		}
+
+		allowed := make(map[string]bool)
+		for _, t := range splitList(types) {
+			allowed[strings.ToLower(t)] = true
+		}
+		uploads.Operations[op] = UploadLimit{MaxSize: maxSize, AllowedTypes: allowed}
+	}
+
+	uploads.ScanDriver = strings.ToLower(viper.GetString("MALWARE_SCAN_DRIVER"))
+	uploads.ScanMode = strings.ToLower(viper.GetString("MALWARE_SCAN_MODE"))
+	uploads.ScanTimeout = viper.GetDuration("MALWARE_SCAN_TIMEOUT")
+	uploads.ScanFailOpen = viper.GetBool("MALWARE_SCAN_FAIL_OPEN")
+	uploads.QuarantinePrefix = viper.GetString("MALWARE_QUARANTINE_PREFIX")
+	uploads.ClamAVAddress = viper.GetString("CLAMAV_ADDRESS")
+	return uploads
+}
+
+// validateUploads rejects limits that can never accept a file and invalid
+// malware scan settings
+func (c *Config) validateUploads() error {
+	for op, limit := range c.UploadConfig.Operations {
+		name := "UPLOAD_" + strings.ToUpper(op)
+		if limit.MaxSize <= 0 {
+			return fmt.Errorf("%s_MAX_SIZE must be positive", name)
+		}
+		if limit.MaxSize > c.MaxBodySize {
+			return fmt.Errorf("%s_MAX_SIZE (%d) exceeds MAX_BODY_SIZE (%d)", name, limit.MaxSize, c.MaxBodySize)
+		}
+		if len(limit.AllowedTypes) == 0 {
+			return fmt.Errorf("%s_TYPES must list at least one MIME type", name)
+		}
+		for t := range limit.AllowedTypes {
+			if _, _, err := mime.ParseMediaType(t); err != nil || !strings.Contains(t, "/") {
+				return fmt.Errorf("%s_TYPES: invalid MIME type %q", name, t)
+			}
+		}
+	}
+
+	switch c.ScanDriver {
+	case "none":
+	case "clamav":
+		if !strings.HasPrefix(c.ClamAVAddress, "tcp://") && !strings.HasPrefix(c.ClamAVAddress, "unix://") {
+			return fmt.Errorf("CLAMAV_ADDRESS must be tcp://host:port or unix:///path")
+		}
+	default:
+		return fmt.Errorf("MALWARE_SCAN_DRIVER must be one of none, clamav")
+	}
+	if c.ScanMode != "inline" && c.ScanMode != "async" {
+		return fmt.Errorf("MALWARE_SCAN_MODE must be one of inline, async")
+	}
+	if c.ScanTimeout <= 0 || c.QuarantinePrefix == "" {
+		return fmt.Errorf("MALWARE_SCAN_TIMEOUT must be positive and MALWARE_QUARANTINE_PREFIX set")
+	}
+	return nil
+}
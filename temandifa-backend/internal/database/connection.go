@@ -0,0 +1,188 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/logger"
+)
+
+// Module exports dependency for Fx
+var Module = fx.Options(
+	fx.Provide(NewConnection),
+	fx.Provide(NewTxManager),
+	fx.Provide(NewChangeListener),
+)
+
+// NewConnection initializes the database connection for DB_DRIVER (PostgreSQL,
+// or SQLite for local development) and returns the *gorm.DB instance for
+// dependency injection. Includes retry logic with exponential backoff for
+// resilient startup.
+func NewConnection(lc fx.Lifecycle, cfg *config.Config) (*gorm.DB, error) {
+	dsn := cfg.DatabaseDSN
+	if cfg.DBDriver == DriverPostgres {
+		dsn = withStatementTimeout(dsn, cfg.DBStatementTimeout)
+	}
+	dialector, err := Dialector(cfg.DBDriver, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	// Configure GORM logger
+	gormConfig := &gorm.Config{
+		Logger:                 gormlogger.Default.LogMode(gormlogger.Warn),
+		PrepareStmt:            true, // Cache prepared statements
+		SkipDefaultTransaction: true, // Disable default transaction for single creates/updates
+	}
+
+	// Retry configuration
+	maxRetries := 5
+	retryDelay := 2 * time.Second
+
+	var db *gorm.DB
+
+	// Attempt connection with retry
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+		db, err = gorm.Open(dialector, gormConfig)
+		if err == nil {
+			break
+		}
+
+		if attempt < maxRetries {
+			logger.Warn("Failed to connect to database, retrying...",
+				zap.Int("attempt", attempt),
+				zap.Int("max_retries", maxRetries),
+				zap.Duration("retry_delay", retryDelay),
+				zap.Error(err),
+			)
+			time.Sleep(retryDelay)
+			retryDelay *= 2 // Exponential backoff
+			if retryDelay > 30*time.Second {
+				retryDelay = 30 * time.Second
+			}
+		}
+	}
+
+	if err != nil {
+		logger.Error("Failed to connect to database after all retries",
+			zap.Int("attempts", maxRetries),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	// Configure connection pool from config
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	// SetMaxIdleConns sets the maximum number of connections in the idle connection pool
+	sqlDB.SetMaxIdleConns(cfg.DBMaxIdleConns)
+
+	// SetMaxOpenConns sets the maximum number of open connections to the database
+	sqlDB.SetMaxOpenConns(cfg.DBMaxOpenConns)
+
+	// SetConnMaxLifetime sets the maximum amount of time a connection may be reused
+	sqlDB.SetConnMaxLifetime(cfg.DBConnMaxLifetime)
+
+	// SetConnMaxIdleTime sets the maximum amount of time a connection may be idle
+	sqlDB.SetConnMaxIdleTime(cfg.DBConnMaxIdleTime)
+
+	logger.Info("Database connection established with connection pooling",
+		zap.String("driver", cfg.DBDriver),
+		zap.Int("max_idle_conns", cfg.DBMaxIdleConns),
+		zap.Int("max_open_conns", cfg.DBMaxOpenConns),
+		zap.Duration("conn_max_lifetime", cfg.DBConnMaxLifetime),
+		zap.Duration("conn_max_idle_time", cfg.DBConnMaxIdleTime),
+	)
+
+	// Run Database Migrations (disable with DB_AUTO_MIGRATE=false and use cmd/migrate)
+	if cfg.DBAutoMigrate {
+		if err := RunMigrations(sqlDB, cfg.DBDriver, MigrationsDirFor(cfg.DBDriver), cfg.DBMigrationLockTimeout); err != nil {
+			logger.Error("Database migrations failed", zap.Error(err))
+			_ = sqlDB.Close()
+			return nil, err
+		}
+		logger.Info("Database migrations ran successfully")
+	}
+
+	// Route reads to replicas (if configured) after migrations ran on the primary
+	replicas, err := registerReplicas(db, sqlDB, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Register Lifecycle hooks
+	var stopPoolStats, stopReplicaChecks func()
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			// Export pool statistics to verify the pool size is adequate
+			stopPoolStats = startPoolStatsReporter(poolStatsInterval, func() {
+				reportDBPoolStats(sqlDB)
+			})
+			if replicas != nil {
+				stopReplicaChecks = replicas.start()
+			}
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			if stopPoolStats != nil {
+				stopPoolStats()
+			}
+			if stopReplicaChecks != nil {
+				stopReplicaChecks()
+			}
+			if replicas != nil {
+				replicas.close()
+			}
+
+			logger.Info("Closing database connection...")
+			return sqlDB.Close()
+		},
+	})
+
+	return db, nil
+}
+
+// ResetPool closes idle pooled connections and dials a new one. database/sql
+// only discards a broken connection after a query fails on it, so after a
+// database restart every idle connection would otherwise fail one request.
+func ResetPool(ctx context.Context, sqlDB *sql.DB, maxIdleConns int) error {
+	sqlDB.SetMaxIdleConns(0)
+	sqlDB.SetMaxIdleConns(maxIdleConns)
+	return sqlDB.PingContext(ctx)
+}
+
+// withStatementTimeout sets Postgres' statement_timeout as a connection runtime
+// parameter, so the server aborts runaway queries even if the client never
+// cancels them. Request contexts (see Conn) cancel queries earlier when the
+// HTTP request is abandoned or times out.
+func withStatementTimeout(dsn string, timeout time.Duration) string {
+	if timeout <= 0 || strings.Contains(dsn, "statement_timeout") {
+		return dsn
+	}
+	ms := timeout.Milliseconds()
+
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return dsn
+		}
+		query := u.Query()
+		query.Set("statement_timeout", fmt.Sprint(ms))
+		u.RawQuery = query.Encode()
+		return u.String()
+	}
+	return fmt.Sprintf("%s statement_timeout=%d", dsn, ms)
+}
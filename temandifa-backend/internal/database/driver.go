@@ -0,0 +1,54 @@
+package database
+
+import (
+	"database/sql"
+	"path/filepath"
+
+	migratedb "github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/jackc/pgx/v5/stdlib" // database/sql driver "pgx"
+	gorm_postgres "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// Database drivers (DB_DRIVER)
+const (
+	DriverPostgres = "postgres"
+	// DriverSQLite is for local development and tests only; it requires a
+	// binary built with -tags sqlite
+	DriverSQLite = "sqlite"
+)
+
+// Dialector returns the GORM dialector for driver
+func Dialector(driver, dsn string) (gorm.Dialector, error) {
+	if driver == DriverSQLite {
+		return sqliteDialector(dsn)
+	}
+	return gorm_postgres.Open(dsn), nil
+}
+
+// OpenSQL opens a plain database/sql connection for driver, e.g. for
+// migrations outside the server
+func OpenSQL(driver, dsn string) (*sql.DB, error) {
+	if driver == DriverSQLite {
+		return openSQLite(dsn)
+	}
+	return sql.Open("pgx", dsn)
+}
+
+// MigrationsDirFor returns the default migrations directory for driver.
+// SQLite migrations mirror the Postgres ones under migrations/sqlite.
+func MigrationsDirFor(driver string) string {
+	if driver == DriverSQLite {
+		return filepath.Join(MigrationsDir, "sqlite")
+	}
+	return MigrationsDir
+}
+
+// migrationDriver wraps db in the golang-migrate driver for driver
+func migrationDriver(db *sql.DB, driver string) (migratedb.Driver, error) {
+	if driver == DriverSQLite {
+		return sqliteMigrationDriver(db)
+	}
+	return postgres.WithInstance(db, &postgres.Config{})
+}
@@ -0,0 +1,207 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/metrics"
+)
+
+// ChangeChannel is the NOTIFY channel written by the change triggers
+const ChangeChannel = "temandifa_changes"
+
+// Change notification entities and operations
+const (
+	EntityUser       = "user"
+	EntityTokenEpoch = "token_epoch"
+
+	OpUpdate = "update"
+	OpDelete = "delete"
+	// OpResync is delivered to every subscriber after the listener reconnects:
+	// notifications sent while it was disconnected are lost, so cached state
+	// for the entity should be dropped wholesale
+	OpResync = "resync"
+)
+
+const (
+	listenerMinBackoff = time.Second
+	listenerMaxBackoff = 30 * time.Second
+)
+
+// Notification is a change published by a database trigger
+type Notification struct {
+	Entity string `json:"entity"`
+	ID     uint   `json:"id"`
+	Op     string `json:"op"`
+}
+
+// ChangeHandler reacts to a change notification
+type ChangeHandler func(ctx context.Context, n Notification)
+
+// ChangeListener holds a dedicated connection that LISTENs for change
+// notifications and fans them out to subscribers, so caches on every instance
+// are invalidated as soon as the row changes instead of when their TTL expires
+type ChangeListener interface {
+	// Subscribe registers fn for changes of entity. Call it before the app starts.
+	Subscribe(entity string, fn ChangeHandler)
+}
+
+type changeListener struct {
+	dsn      string
+	enabled  bool
+	mu       sync.RWMutex
+	handlers map[string][]ChangeHandler
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// NewChangeListener creates the listener and ties it to the app lifecycle
+func NewChangeListener(lc fx.Lifecycle, cfg *config.Config) ChangeListener {
+	l := &changeListener{
+		dsn:      cfg.DatabaseDSN,
+		enabled:  cfg.DBListenEnabled && cfg.DBDriver == DriverPostgres,
+		handlers: make(map[string][]ChangeHandler),
+		done:     make(chan struct{}),
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			l.start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			l.stop()
+			return nil
+		},
+	})
+	return l
+}
+
+func (l *changeListener) Subscribe(entity string, fn ChangeHandler) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.handlers[entity] = append(l.handlers[entity], fn)
+}
+
+func (l *changeListener) start() {
+	if !l.enabled {
+		logger.Info("Database change listener disabled")
+		close(l.done)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	l.cancel = cancel
+	go l.run(ctx)
+	logger.Info("Database change listener started", zap.String("channel", ChangeChannel))
+}
+
+func (l *changeListener) stop() {
+	if l.cancel != nil {
+		l.cancel()
+	}
+	<-l.done
+}
+
+// run keeps a LISTEN connection open, reconnecting with backoff
+func (l *changeListener) run(ctx context.Context) {
+	defer close(l.done)
+
+	backoff := listenerMinBackoff
+	connectedBefore := false
+	for {
+		err := l.listen(ctx, func() {
+			backoff = listenerMinBackoff
+			if connectedBefore {
+				l.resync(ctx)
+			}
+			connectedBefore = true
+		})
+		metrics.DBListenerConnected.Set(0)
+		if ctx.Err() != nil {
+			logger.Info("Database change listener stopped")
+			return
+		}
+
+		logger.Warn("Database change listener disconnected, reconnecting",
+			zap.Duration("retry_in", backoff),
+			zap.Error(err),
+		)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return
+		}
+		backoff = min(backoff*2, listenerMaxBackoff)
+	}
+}
+
+// listen connects, subscribes and dispatches notifications until an error
+func (l *changeListener) listen(ctx context.Context, onConnected func()) error {
+	conn, err := pgx.Connect(ctx, l.dsn)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = conn.Close(closeCtx)
+	}()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{ChangeChannel}.Sanitize()); err != nil {
+		return err
+	}
+	metrics.DBListenerConnected.Set(1)
+	onConnected()
+
+	for {
+		pgNotification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var n Notification
+		if err := json.Unmarshal([]byte(pgNotification.Payload), &n); err != nil {
+			logger.Warn("Ignoring malformed change notification",
+				zap.String("payload", pgNotification.Payload),
+				zap.Error(err),
+			)
+			continue
+		}
+		metrics.DBNotifications.WithLabelValues(n.Entity, n.Op).Inc()
+		l.dispatch(ctx, n)
+	}
+}
+
+// resync tells every subscriber that notifications may have been missed
+func (l *changeListener) resync(ctx context.Context) {
+	l.mu.RLock()
+	entities := make([]string, 0, len(l.handlers))
+	for entity := range l.handlers {
+		entities = append(entities, entity)
+	}
+	l.mu.RUnlock()
+
+	logger.Info("Database change listener reconnected, resyncing subscribers")
+	for _, entity := range entities {
+		l.dispatch(ctx, Notification{Entity: entity, Op: OpResync})
+	}
+}
+
+func (l *changeListener) dispatch(ctx context.Context, n Notification) {
+	l.mu.RLock()
+	handlers := l.handlers[n.Entity]
+	l.mu.RUnlock()
+
+	for _, fn := range handlers {
+		fn(ctx, n)
+	}
+}
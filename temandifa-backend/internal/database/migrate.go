@@ -0,0 +1,205 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/source"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/logger"
+)
+
+// MigrationsDir is the default location of SQL migration files
+const MigrationsDir = "migrations"
+
+// migrationLockKey is the Postgres advisory lock serializing migrations
+// across instances that start at the same time
+const migrationLockKey = 72_616_000
+
+// migrationLockPoll is how often a waiting instance retries the lock
+const migrationLockPoll = 500 * time.Millisecond
+
+// migrationNamePattern restricts names used by CreateMigration
+var migrationNamePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// MigrationInfo describes a migration file and whether it has been applied
+type MigrationInfo struct {
+	Version uint
+	Name    string
+	Applied bool
+}
+
+// NewMigrator creates a golang-migrate instance for the given connection and DB_DRIVER
+func NewMigrator(db *sql.DB, driver, dir string) (*migrate.Migrate, error) {
+	instance, err := migrationDriver(db, driver)
+	if err != nil {
+		return nil, fmt.Errorf("create %s migration driver: %w", driver, err)
+	}
+
+	m, err := migrate.NewWithDatabaseInstance("file://"+dir, driver, instance)
+	if err != nil {
+		return nil, fmt.Errorf("create migration instance: %w", err)
+	}
+	return m, nil
+}
+
+// WithMigrationLock runs fn while holding the migration advisory lock, waiting
+// up to timeout for another instance (or cmd/migrate) to release it. The lock
+// lives on a dedicated connection, so it is released even if this process
+// dies mid-migration. SQLite has a single writer and needs no lock.
+func WithMigrationLock(ctx context.Context, db *sql.DB, driver string, timeout time.Duration, fn func() error) error {
+	if driver != DriverPostgres {
+		return fn()
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	deadline := time.Now().Add(timeout)
+	waiting := false
+	for {
+		var locked bool
+		if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", migrationLockKey).Scan(&locked); err != nil {
+			return fmt.Errorf("acquire migration lock: %w", err)
+		}
+		if locked {
+			break
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %s waiting for another instance to finish migrations", timeout)
+		}
+		if !waiting {
+			logger.Info("Waiting for another instance to finish migrations", zap.Duration("timeout", timeout))
+			waiting = true
+		}
+
+		select {
+		case <-time.After(migrationLockPoll):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	defer func() {
+		_, _ = conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", migrationLockKey)
+	}()
+
+	return fn()
+}
+
+// RunMigrations applies all pending migrations. Instances starting together
+// take turns; the ones that get the lock later find nothing left to apply.
+func RunMigrations(db *sql.DB, driver, dir string, lockTimeout time.Duration) error {
+	m, err := NewMigrator(db, driver, dir)
+	if err != nil {
+		return err
+	}
+
+	err = WithMigrationLock(context.Background(), db, driver, lockTimeout, m.Up)
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		var dirty migrate.ErrDirty
+		if errors.As(err, &dirty) {
+			return fmt.Errorf("database is dirty at version %d; fix it and run `go run ./cmd/migrate force <version>`: %w", dirty.Version, err)
+		}
+		return fmt.Errorf("run migrations: %w", err)
+	}
+	return nil
+}
+
+// LatestMigration returns the highest migration version found in dir
+func LatestMigration(dir string) (uint, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.up.sql"))
+	if err != nil {
+		return 0, err
+	}
+
+	var latest uint
+	for _, file := range files {
+		if parsed, err := source.Parse(filepath.Base(file)); err == nil && parsed.Version > latest {
+			latest = parsed.Version
+		}
+	}
+	return latest, nil
+}
+
+// SchemaVersion reads the applied migration version recorded by golang-migrate.
+// A database that was never migrated reports version 0.
+func SchemaVersion(ctx context.Context, db *sql.DB) (version uint, dirty bool, err error) {
+	err = db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+// MigrationStatus lists migrations found in dir along with the current version
+func MigrationStatus(m *migrate.Migrate, dir string) (infos []MigrationInfo, version uint, dirty bool, err error) {
+	version, dirty, err = m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return nil, 0, false, err
+	}
+	applied := err == nil
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.up.sql"))
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	for _, file := range files {
+		parsed, err := source.Parse(filepath.Base(file))
+		if err != nil {
+			continue
+		}
+		infos = append(infos, MigrationInfo{
+			Version: parsed.Version,
+			Name:    parsed.Identifier,
+			Applied: applied && parsed.Version <= version,
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Version < infos[j].Version })
+
+	return infos, version, dirty, nil
+}
+
+// CreateMigration writes empty up/down files using the next sequential version
+func CreateMigration(dir, name string) (up, down string, err error) {
+	if !migrationNamePattern.MatchString(name) {
+		return "", "", fmt.Errorf("migration name must match %s", migrationNamePattern)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return "", "", err
+	}
+
+	var next uint = 1
+	for _, file := range files {
+		if parsed, err := source.Parse(filepath.Base(file)); err == nil && parsed.Version >= next {
+			next = parsed.Version + 1
+		}
+	}
+
+	base := filepath.Join(dir, fmt.Sprintf("%06d_%s", next, name))
+	up, down = base+".up.sql", base+".down.sql"
+	if err := os.WriteFile(up, []byte("-- "+name+"\n"), 0o644); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(down, []byte("-- Revert "+name+"\n"), 0o644); err != nil {
+		return "", "", err
+	}
+	return up, down, nil
+}
@@ -0,0 +1,60 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"temandifa-backend/internal/metrics"
+)
+
+// poolStatsInterval controls how often connection pool statistics are exported
+const poolStatsInterval = 15 * time.Second
+
+// startPoolStatsReporter calls report immediately and then on every tick until
+// the returned stop function is called
+func startPoolStatsReporter(interval time.Duration, report func()) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	report()
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				report()
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+// reportRedisPoolStats exports go-redis PoolStats as Prometheus gauges
+func reportRedisPoolStats(client *redis.Client) {
+	stats := client.PoolStats()
+	metrics.RedisPoolStats.WithLabelValues("hits").Set(float64(stats.Hits))
+	metrics.RedisPoolStats.WithLabelValues("misses").Set(float64(stats.Misses))
+	metrics.RedisPoolStats.WithLabelValues("timeouts").Set(float64(stats.Timeouts))
+	metrics.RedisPoolStats.WithLabelValues("total_conns").Set(float64(stats.TotalConns))
+	metrics.RedisPoolStats.WithLabelValues("idle_conns").Set(float64(stats.IdleConns))
+	metrics.RedisPoolStats.WithLabelValues("stale_conns").Set(float64(stats.StaleConns))
+}
+
+// reportDBPoolStats exports sql.DBStats as Prometheus gauges
+func reportDBPoolStats(db *sql.DB) {
+	stats := db.Stats()
+	metrics.DBPoolStats.WithLabelValues("max_open").Set(float64(stats.MaxOpenConnections))
+	metrics.DBPoolStats.WithLabelValues("open").Set(float64(stats.OpenConnections))
+	metrics.DBPoolStats.WithLabelValues("in_use").Set(float64(stats.InUse))
+	metrics.DBPoolStats.WithLabelValues("idle").Set(float64(stats.Idle))
+	metrics.DBPoolStats.WithLabelValues("wait_count").Set(float64(stats.WaitCount))
+	metrics.DBPoolStats.WithLabelValues("wait_duration_seconds").Set(stats.WaitDuration.Seconds())
+	metrics.DBPoolStats.WithLabelValues("max_idle_closed").Set(float64(stats.MaxIdleClosed))
+	metrics.DBPoolStats.WithLabelValues("max_idle_time_closed").Set(float64(stats.MaxIdleTimeClosed))
+	metrics.DBPoolStats.WithLabelValues("max_lifetime_closed").Set(float64(stats.MaxLifetimeClosed))
+}
@@ -18,7 +18,7 @@ var RedisModule = fx.Options(
 )
 
 // NewRedisConnection creates a new Redis client with FX lifecycle management
-func NewRedisConnection(lc fx.Lifecycle, cfg *config.Config) *redis.Client {
+func NewRedisConnection(lc fx.Lifecycle, cfg *config.CacheConfig) *redis.Client {
 	client := redis.NewClient(&redis.Options{
 		Addr:         cfg.RedisAddr,
 		Password:     cfg.RedisPassword,
@@ -30,8 +30,15 @@ func NewRedisConnection(lc fx.Lifecycle, cfg *config.Config) *redis.Client {
 		WriteTimeout: 3 * time.Second,
 	})
 
+	var stopPoolStats func()
+
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
+			// Export pool statistics so exhaustion is visible before requests fail
+			stopPoolStats = startPoolStatsReporter(poolStatsInterval, func() {
+				reportRedisPoolStats(client)
+			})
+
 			_, err := client.Ping(ctx).Result()
 			if err != nil {
 				logger.Warn("Failed to connect to Redis - some features may be disabled",
@@ -48,6 +55,10 @@ func NewRedisConnection(lc fx.Lifecycle, cfg *config.Config) *redis.Client {
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
+			if stopPoolStats != nil {
+				stopPoolStats()
+			}
+
 			logger.Info("Closing Redis connection...")
 			if err := client.Close(); err != nil {
 				logger.Error("Error closing Redis connection", zap.Error(err))
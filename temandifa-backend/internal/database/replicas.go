@@ -0,0 +1,139 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	gorm_postgres "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/metrics"
+)
+
+// replicaPingTimeout bounds a single replica health probe
+const replicaPingTimeout = 2 * time.Second
+
+// replicaSet tracks read replicas and their health so reads fail back to the
+// primary while no replica is reachable and return once one recovers
+type replicaSet struct {
+	dbs      []*sql.DB
+	healthy  []atomic.Bool
+	next     atomic.Uint64
+	interval time.Duration
+}
+
+// registerReplicas opens the configured replicas and installs the dbresolver
+// plugin: writes and transactions stay on the primary, while plain reads
+// (history listings, stats, user lookups) are spread across healthy replicas.
+// Returns nil when no replicas are configured.
+func registerReplicas(db *gorm.DB, primary *sql.DB, cfg *config.Config) (*replicaSet, error) {
+	if len(cfg.DatabaseReplicaDSNs) == 0 {
+		return nil, nil
+	}
+
+	rs := &replicaSet{
+		healthy:  make([]atomic.Bool, len(cfg.DatabaseReplicaDSNs)),
+		interval: cfg.DBReplicaCheckInterval,
+	}
+
+	// The primary is listed first so the policy is always consulted (dbresolver
+	// skips it for a single replica) and can fall back to it
+	dialectors := []gorm.Dialector{gorm_postgres.New(gorm_postgres.Config{Conn: primary})}
+	for i, dsn := range cfg.DatabaseReplicaDSNs {
+		// Skip the startup ping: an unreachable replica starts out unhealthy instead of failing boot
+		replicaDB, err := gorm.Open(gorm_postgres.Open(withStatementTimeout(dsn, cfg.DBStatementTimeout)), &gorm.Config{DisableAutomaticPing: true})
+		if err != nil {
+			rs.close()
+			return nil, fmt.Errorf("open replica %d: %w", i, err)
+		}
+		sqlDB, err := replicaDB.DB()
+		if err != nil {
+			rs.close()
+			return nil, err
+		}
+
+		sqlDB.SetMaxIdleConns(cfg.DBMaxIdleConns)
+		sqlDB.SetMaxOpenConns(cfg.DBMaxOpenConns)
+		sqlDB.SetConnMaxLifetime(cfg.DBConnMaxLifetime)
+		sqlDB.SetConnMaxIdleTime(cfg.DBConnMaxIdleTime)
+
+		rs.dbs = append(rs.dbs, sqlDB)
+		dialectors = append(dialectors, gorm_postgres.New(gorm_postgres.Config{Conn: sqlDB}))
+	}
+
+	// Probe once up front so the first reads don't go to a dead replica
+	rs.check()
+
+	err := db.Use(dbresolver.Register(dbresolver.Config{
+		Replicas: dialectors,
+		Policy:   dbresolver.PolicyFunc(rs.resolve),
+	}))
+	if err != nil {
+		rs.close()
+		return nil, err
+	}
+
+	logger.Info("Read replicas configured", zap.Int("replicas", len(rs.dbs)))
+	return rs, nil
+}
+
+// resolve picks the next healthy replica round-robin, or the primary
+// (connPools[0]) when every replica is unhealthy
+func (rs *replicaSet) resolve(connPools []gorm.ConnPool) gorm.ConnPool {
+	n := len(rs.dbs)
+	start := int(rs.next.Add(1) % uint64(n))
+	for i := 0; i < n; i++ {
+		idx := (start + i) % n
+		if rs.healthy[idx].Load() {
+			return connPools[idx+1]
+		}
+	}
+	return connPools[0]
+}
+
+// check pings every replica and records health transitions
+func (rs *replicaSet) check() {
+	for i, sqlDB := range rs.dbs {
+		ctx, cancel := context.WithTimeout(context.Background(), replicaPingTimeout)
+		err := sqlDB.PingContext(ctx)
+		cancel()
+
+		healthy := err == nil
+		if was := rs.healthy[i].Swap(healthy); was != healthy {
+			if healthy {
+				logger.Info("Read replica recovered, routing reads to it", zap.Int("replica", i))
+			} else {
+				logger.Warn("Read replica unhealthy, routing reads elsewhere",
+					zap.Int("replica", i),
+					zap.Error(err),
+				)
+			}
+		}
+
+		value := 0.0
+		if healthy {
+			value = 1
+		}
+		metrics.DBReplicaHealthy.WithLabelValues(strconv.Itoa(i)).Set(value)
+	}
+}
+
+// start probes replicas on every interval until the returned stop function is called
+func (rs *replicaSet) start() (stop func()) {
+	return startPoolStatsReporter(rs.interval, rs.check)
+}
+
+// close closes all replica connections
+func (rs *replicaSet) close() {
+	for _, sqlDB := range rs.dbs {
+		_ = sqlDB.Close()
+	}
+}
@@ -0,0 +1,25 @@
+//go:build sqlite
+
+package database
+
+import (
+	"database/sql"
+
+	migratedb "github.com/golang-migrate/migrate/v4/database"
+	migratesqlite "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	_ "modernc.org/sqlite" // Pure-Go database/sql driver "sqlite" (no cgo)
+)
+
+func sqliteDialector(dsn string) (gorm.Dialector, error) {
+	return sqlite.Dialector{DriverName: "sqlite", DSN: dsn}, nil
+}
+
+func openSQLite(dsn string) (*sql.DB, error) {
+	return sql.Open("sqlite", dsn)
+}
+
+func sqliteMigrationDriver(db *sql.DB) (migratedb.Driver, error) {
+	return migratesqlite.WithInstance(db, &migratesqlite.Config{})
+}
@@ -0,0 +1,27 @@
+//go:build !sqlite
+
+package database
+
+import (
+	"database/sql"
+	"errors"
+
+	migratedb "github.com/golang-migrate/migrate/v4/database"
+	"gorm.io/gorm"
+)
+
+// errSQLiteUnavailable keeps SQLite (and its dependencies) out of production
+// builds; local builds opt in with -tags sqlite
+var errSQLiteUnavailable = errors.New("DB_DRIVER=sqlite requires a binary built with -tags sqlite")
+
+func sqliteDialector(dsn string) (gorm.Dialector, error) {
+	return nil, errSQLiteUnavailable
+}
+
+func openSQLite(dsn string) (*sql.DB, error) {
+	return nil, errSQLiteUnavailable
+}
+
+func sqliteMigrationDriver(db *sql.DB) (migratedb.Driver, error) {
+	return nil, errSQLiteUnavailable
+}
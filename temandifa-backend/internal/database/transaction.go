@@ -6,34 +6,44 @@ import (
 	"gorm.io/gorm"
 )
 
-// TransactionManager interface defines how to run a function within a transaction
-type TransactionManager interface {
-	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+// txKey is the context key holding the active transaction
+type txKey struct{}
+
+// TxManager runs multi-step operations atomically. The transaction travels in
+// the context, so repositories and services that resolve their connection
+// through Conn join it automatically.
+type TxManager interface {
+	// WithinTransaction runs fn in a transaction that is committed when fn
+	// returns nil and rolled back on error or panic. Nested calls join the
+	// outer transaction.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
-type transactionManager struct {
+type txManager struct {
 	db *gorm.DB
 }
 
-// NewTransactionManager creates a new instance of TransactionManager
-func NewTransactionManager(db *gorm.DB) TransactionManager {
-	return &transactionManager{db: db}
+// NewTxManager creates a new TxManager
+func NewTxManager(db *gorm.DB) TxManager {
+	return &txManager{db: db}
 }
 
-// WithTransaction executes the given function within a database transaction.
-// If the function returns an error, the transaction is rolled back.
-// If the function panics, the transaction is rolled back.
-// If the function returns nil, the transaction is committed.
-func (tm *transactionManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
-	return tm.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// Create a new context with the transaction db
-		// Assuming we have a way to inject tx into repositories via context or other means.
-		// Since repositories usually hold *gorm.DB, we need a way to pass the TX-aware DB to them.
-
-		// For clean architecture, repositories should probably accept context
-		// and checks if a tx is present in context, OR, we just pass control.
-
-		// GORM's .Transaction method takes care of panic recovery and rollback.
+func (tm *txManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
 		return fn(ctx)
+	}
+
+	// GORM's Transaction takes care of panic recovery and rollback
+	return tm.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
 	})
 }
+
+// Conn returns the transaction bound to ctx, or db scoped to ctx when no
+// transaction is active
+func Conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx
+	}
+	return db.WithContext(ctx)
+}
@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+// DeleteAccountRequest confirms an account deletion. Accounts with a password
+// must send it; those that only sign in with Google or Apple can't.
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"max=72"`
+}
+
+// ChangePasswordRequest replaces the caller's password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required,max=72"`
+	NewPassword     string `json:"new_password" binding:"required,min=8,max=72"`
+}
+
+// ChangeEmailRequest asks to change the caller's email address. Accounts
+// with a password must confirm with it.
+type ChangeEmailRequest struct {
+	NewEmail string `json:"new_email" binding:"required,email,max=255" example:"budi@example.com"`
+	Password string `json:"password" binding:"max=72"`
+}
+
+// EmailChangeConfirmation is the state of an email change after one of its
+// links was opened
+type EmailChangeConfirmation struct {
+	UserID       uint   `json:"user_id"`
+	Email        string `json:"email"`                   // The account's address, the new one once changed
+	PendingEmail string `json:"pending_email,omitempty"` // The new address while the other link isn't opened yet
+	Changed      bool   `json:"changed"`
+}
+
+// AccountDeletion tells when a deleted account's remaining data is purged
+type AccountDeletion struct {
+	DeletedAt  time.Time `json:"deleted_at"`
+	PurgeAfter time.Time `json:"purge_after"` // deleted_at + USER_PURGE_GRACE_PERIOD
+}
@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"time"
+
+	"temandifa-backend/internal/config"
+)
+
+// UserPurgeCandidate is a soft-deleted user due for hard deletion
+type UserPurgeCandidate struct {
+	UserID             uint      `json:"user_id"`
+	Email              string    `json:"email"`
+	DeletedAt          time.Time `json:"deleted_at"`
+	Histories          int64     `json:"histories"`
+	RefreshTokens      int64     `json:"refresh_tokens"`
+	EmergencyContacts  int64     `json:"emergency_contacts"`
+	VerificationTokens int64     `json:"verification_tokens"`
+	UserIdentities     int64     `json:"user_identities"` // Linked sign-in providers
+}
+
+// UserPurgePreview describes what the next purge run would delete (dry run)
+type UserPurgePreview struct {
+	GracePeriod string               `json:"grace_period"`
+	Cutoff      time.Time            `json:"cutoff"` // Users deleted before this are purged
+	Total       int64                `json:"total"`
+	Candidates  []UserPurgeCandidate `json:"candidates"`
+}
+
+// User search status filters
+const (
+	UserStatusActive  = "active"
+	UserStatusDeleted = "deleted"
+	UserStatusAll     = "all"
+)
+
+// UserSearchQuery holds the filters of the admin user search
+type UserSearchQuery struct {
+	Query  string `form:"q" binding:"max=100" example:"budi"`                                   // Substring of email or full name
+	Role   string `form:"role" binding:"omitempty,oneof=user admin" example:"user"`             // Exact role
+	Status string `form:"status" binding:"omitempty,oneof=active deleted all" example:"active"` // Defaults to active
+	Sort   string `form:"sort" binding:"max=100" example:"-created_at"`                         // Comma-separated fields, "-" for descending
+}
+
+// EffectiveConfig is the live configuration with secrets masked
+type EffectiveConfig struct {
+	AppEnv   string           `json:"app_env"`
+	Settings []config.Setting `json:"settings"`
+}
+
+// SetRateLimitOverrideRequest adjusts one user's per-user (AI) rate limit
+type SetRateLimitOverrideRequest struct {
+	Multiplier float64    `json:"multiplier" example:"5"`                             // Limit is multiplied by this (default 1)
+	Exempt     bool       `json:"exempt" example:"false"`                             // Skip the per-user rate limit entirely
+	Reason     string     `json:"reason" binding:"max=255" example:"QA test account"` // Why the override exists
+	ExpiresAt  *time.Time `json:"expires_at" example:"2026-12-31T00:00:00Z"`          // Optional end of the override
+}
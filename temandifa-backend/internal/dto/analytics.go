@@ -0,0 +1,36 @@
+package dto
+
+// UsageStats are request counters for one bucket of usage analytics
+type UsageStats struct {
+	Requests     int64   `json:"requests"`
+	CacheHits    int64   `json:"cache_hits"`
+	CacheHitRate float64 `json:"cache_hit_rate"` // cache_hits / requests
+	Errors       int64   `json:"errors"`         // 5xx responses
+}
+
+// DailyUsage is the AI usage of one UTC day
+type DailyUsage struct {
+	Day string `json:"day" example:"2026-10-01"`
+	UsageStats
+}
+
+// OperationUsage is the AI usage of one operation (detect, ocr, ask, transcribe, simplify)
+type OperationUsage struct {
+	Operation string `json:"operation" example:"ocr"`
+	UsageStats
+}
+
+// UserUsage is the AI usage of one user; email is empty for purged users
+type UserUsage struct {
+	UserID uint   `json:"user_id"`
+	Email  string `json:"email"`
+	UsageStats
+}
+
+// AnalyticsQuery selects the UTC days usage analytics cover; without from
+// and to the last 30 days (including today) are reported
+type AnalyticsQuery struct {
+	From  string `form:"from" binding:"omitempty,datetime=2006-01-02" example:"2026-09-01"` // First day, inclusive
+	To    string `form:"to" binding:"omitempty,datetime=2006-01-02" example:"2026-09-30"`   // Last day, inclusive
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=100" example:"10"`              // Top users only
+}
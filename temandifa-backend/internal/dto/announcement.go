@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+// AnnouncementTranslationRequest is an announcement's content in one locale
+type AnnouncementTranslationRequest struct {
+	Locale string `json:"locale" binding:"required,max=16" example:"id"` // BCP 47 tag, e.g. "id" or "en-US"
+	Title  string `json:"title" binding:"required,max=200" example:"Pemeliharaan terjadwal"`
+	Body   string `json:"body" binding:"required,max=4000" example:"Layanan tidak tersedia Minggu pukul 01.00-03.00 WIB."`
+}
+
+// SaveAnnouncementRequest creates or replaces an announcement
+type SaveAnnouncementRequest struct {
+	Kind         string                           `json:"kind" binding:"omitempty,oneof=info feature maintenance" example:"maintenance"` // Defaults to info
+	StartsAt     *time.Time                       `json:"starts_at" example:"2026-10-18T00:00:00Z"`                                      // Defaults to now
+	EndsAt       *time.Time                       `json:"ends_at" example:"2026-10-19T00:00:00Z"`                                        // Shown until deleted when unset
+	Translations []AnnouncementTranslationRequest `json:"translations" binding:"required,min=1,max=20,dive"`
+}
+
+// Announcement is an active announcement in the caller's locale
+type Announcement struct {
+	ID       uint       `json:"id"`
+	Kind     string     `json:"kind" example:"maintenance"`
+	Locale   string     `json:"locale" example:"id"` // Locale of title and body
+	Title    string     `json:"title"`
+	Body     string     `json:"body"`
+	StartsAt time.Time  `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+}
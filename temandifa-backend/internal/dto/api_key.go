@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+// CreateAPIKeyRequest issues a partner API key acting as a user
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required,max=100" example:"Acme reader app"`
+	UserID    uint       `json:"user_id" binding:"required"`                                   // The user the key acts as, e.g. the partner's service account
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,required" example:"ocr"`  // AI operations the key may use
+	RateLimit int        `json:"rate_limit" binding:"omitempty,min=0,max=100000" example:"60"` // Requests per minute; 0 applies the user's AI rate limit
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`                                         // Never expires when omitted
+}
+
+// UpdateAPIKeyRequest replaces a key's settings; the owner and the key
+// itself don't change
+type UpdateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required,max=100" example:"Acme reader app"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,required" example:"ocr"`
+	RateLimit int        `json:"rate_limit" binding:"omitempty,min=0,max=100000" example:"60"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// APIKey is a partner API key as administrators see it
+type APIKey struct {
+	ID         uint64     `json:"id"`
+	Name       string     `json:"name" example:"Acme reader app"`
+	UserID     uint       `json:"user_id"`
+	Prefix     string     `json:"prefix" example:"tdk_3fA9xQ2b"` // Start of the key, to tell keys apart
+	Scopes     []string   `json:"scopes" example:"ocr"`
+	RateLimit  int        `json:"rate_limit" example:"60"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	Key        string     `json:"api_key,omitempty" example:"tdk_3fA9xQ2b..."` // Only when created
+	CreatedBy  *uint      `json:"created_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+// SaveAppVersionRequest sets a platform's minimum and latest app version
+type SaveAppVersionRequest struct {
+	MinVersion    string `json:"min_version" binding:"required,max=32" example:"2.1.0"`
+	LatestVersion string `json:"latest_version" binding:"required,max=32" example:"2.4.0"`
+	StoreURL      string `json:"store_url" binding:"omitempty,url,max=500" example:"https://play.google.com/store/apps/details?id=com.temandifa"`
+	Changelog     string `json:"changelog" binding:"max=5000" example:"Faster text recognition"`
+}
+
+// AppVersionStatus is a platform's version manifest. With the caller's
+// version (X-App-Version), it also says whether the app must or may update.
+type AppVersionStatus struct {
+	Platform        string    `json:"platform" example:"android"`
+	MinVersion      string    `json:"min_version" example:"2.1.0"`
+	LatestVersion   string    `json:"latest_version" example:"2.4.0"`
+	StoreURL        string    `json:"store_url"`
+	Changelog       string    `json:"changelog"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	CurrentVersion  string    `json:"current_version,omitempty" example:"2.0.3"`
+	UpdateRequired  bool      `json:"update_required"`
+	UpdateAvailable bool      `json:"update_available"`
+}
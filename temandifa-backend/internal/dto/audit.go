@@ -0,0 +1,35 @@
+package dto
+
+import "time"
+
+// Kinds of field change in an audit diff
+const (
+	AuditChangeAdded   = "added"
+	AuditChangeRemoved = "removed"
+	AuditChangeChanged = "changed"
+)
+
+// AuditChange is one field that differs between the before and after
+// snapshots. Path uses dots for object keys and [i] for array elements.
+type AuditChange struct {
+	Path   string `json:"path" example:"permissions[2]"`
+	Kind   string `json:"kind" enums:"added,removed,changed"`
+	Before any    `json:"before" swaggertype:"object"` // null when added
+	After  any    `json:"after" swaggertype:"object"`  // null when removed
+}
+
+// AuditEntry is an audit log entry with the snapshots it recorded and
+// the changes between them
+type AuditEntry struct {
+	ID         uint          `json:"id"`
+	ActorID    *uint         `json:"actor_id,omitempty"`
+	Action     string        `json:"action" example:"feature_flag.saved"`
+	TargetType string        `json:"target_type" example:"feature_flag"`
+	TargetID   string        `json:"target_id" example:"dark_mode"`
+	Details    any           `json:"details" swaggertype:"object"`
+	RequestID  string        `json:"request_id"`
+	CreatedAt  time.Time     `json:"created_at"`
+	Before     any           `json:"before,omitempty" swaggertype:"object"`
+	After      any           `json:"after,omitempty" swaggertype:"object"`
+	Changes    []AuditChange `json:"changes"`
+}
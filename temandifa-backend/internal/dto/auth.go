@@ -17,6 +17,28 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required,max=72"`
 }
 
+// ForgotPasswordRequest asks for a password reset token by email
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email,max=255"`
+}
+
+// ResetPasswordRequest sets a new password with an emailed reset token
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required,max=128"`
+	Password string `json:"password" binding:"required,min=8,max=72"`
+}
+
+// MagicLinkRequest asks for a sign-in link by email
+type MagicLinkRequest struct {
+	Email string `json:"email" binding:"required,email,max=255"`
+}
+
+// OAuthSignInRequest signs in with a sign-in provider's ID token
+type OAuthSignInRequest struct {
+	IDToken  string `json:"id_token" binding:"required,max=8192"`
+	FullName string `json:"full_name" binding:"omitempty,max=100"` // Apple only shares it with the app, on first sign-in
+}
+
 // RefreshTokenRequest represents the token refresh request
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
@@ -35,15 +57,18 @@ type TokenResponse struct {
 // LoginResponse represents the full login response with user info
 type LoginResponse struct {
 	TokenResponse
-	User UserInfo `json:"user"`
+	User     UserInfo        `json:"user"`
+	Features map[string]bool `json:"features"`           // Feature flags evaluated for the user
+	NewUser  bool            `json:"new_user,omitempty"` // The sign-in created the account
 }
 
 // UserInfo represents public user information
 type UserInfo struct {
-	ID       uint   `json:"id"`
-	FullName string `json:"full_name"`
-	Email    string `json:"email"`
-	Role     string `json:"role,omitempty"`
+	ID            uint   `json:"id"`
+	FullName      string `json:"full_name"`
+	Email         string `json:"email"`
+	Role          string `json:"role,omitempty"`
+	EmailVerified bool   `json:"email_verified"`
 }
 
 // UserResponse represents a user in API responses
@@ -55,3 +80,21 @@ type UserResponse struct {
 	Role           string    `json:"role"`
 	CreatedAt      time.Time `json:"created_at"`
 }
+
+// JWKS is the JSON Web Key Set (RFC 7517) of the public keys access tokens
+// are signed with
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK is an RSA or Ed25519 public key, named by its kid
+type JWK struct {
+	KeyType   string `json:"kty"` // RSA or OKP
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`   // RSA modulus
+	E         string `json:"e,omitempty"`   // RSA exponent
+	Curve     string `json:"crv,omitempty"` // Ed25519
+	X         string `json:"x,omitempty"`   // Ed25519 public key
+}
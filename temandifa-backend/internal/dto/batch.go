@@ -0,0 +1,33 @@
+package dto
+
+import "encoding/json"
+
+// BatchRequest is a list of API requests served in one round trip
+type BatchRequest struct {
+	Requests []BatchRequestItem `json:"requests" binding:"required,min=1,dive"` // At most BATCH_MAX_REQUESTS
+}
+
+// BatchRequestItem is one request of a batch. It is served like a separate
+// request with the batch's Authorization, Accept-Language and app headers.
+type BatchRequestItem struct {
+	ID      string            `json:"id,omitempty" binding:"max=64" example:"history"` // Echoed in the response
+	Method  string            `json:"method" binding:"required,oneof=GET POST PUT PATCH DELETE" example:"GET"`
+	Path    string            `json:"path" binding:"required,startswith=/,max=2048" example:"/history?limit=20"` // Relative to /api/v1
+	Headers map[string]string `json:"headers,omitempty"`                                                         // Only If-None-Match, If-Match and Accept-Language
+	Body    json.RawMessage   `json:"body,omitempty" swaggertype:"object"`                                       // JSON request body
+}
+
+// BatchResponseItem is the response to one request of a batch
+type BatchResponseItem struct {
+	ID         string            `json:"id,omitempty" example:"history"`
+	Status     int               `json:"status" example:"200"`
+	Headers    map[string]string `json:"headers,omitempty"`                   // ETag, Location, Retry-After and the rate limit headers
+	Body       json.RawMessage   `json:"body,omitempty" swaggertype:"object"` // JSON responses
+	BodyBase64 []byte            `json:"body_base64,omitempty"`               // Anything else
+	Error      string            `json:"error,omitempty"`                     // Set when the request couldn't be served
+}
+
+// BatchResponse holds the responses in the order of the requests
+type BatchResponse struct {
+	Responses []BatchResponseItem `json:"responses"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+// InvalidateCacheRequest deletes the AI cache entries matching a glob
+// pattern. Without a confirm token nothing is deleted: the response is a
+// preview carrying the token to send back.
+type InvalidateCacheRequest struct {
+	Pattern      string `json:"pattern" binding:"required,max=200" example:"detect:ab12*"`
+	ConfirmToken string `json:"confirm_token" binding:"max=64"`
+}
+
+// CacheInvalidationPreview is the dry run of a pattern invalidation
+type CacheInvalidationPreview struct {
+	Pattern      string    `json:"pattern" example:"detect:ab12*"`
+	Matched      int64     `json:"matched" example:"42"` // Entries that would be deleted now
+	ConfirmToken string    `json:"confirm_token"`        // Single use, bound to the pattern
+	ExpiresAt    time.Time `json:"expires_at"`
+}
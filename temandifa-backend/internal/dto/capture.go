@@ -0,0 +1,38 @@
+package dto
+
+import "time"
+
+// Request capture DTOs (admin debugging)
+
+// CaptureRuleRequest enables targeted request capture
+type CaptureRuleRequest struct {
+	UserID           uint    `json:"user_id"`
+	RequestIDPattern string  `json:"request_id_pattern" binding:"max=256"`
+	SampleRate       float64 `json:"sample_rate" binding:"omitempty,gt=0,lte=1"`
+	DurationMinutes  int     `json:"duration_minutes" binding:"omitempty,min=1,max=1440"`
+}
+
+// CaptureRule is the active capture configuration
+type CaptureRule struct {
+	UserID           uint      `json:"user_id,omitempty"`
+	RequestIDPattern string    `json:"request_id_pattern,omitempty"`
+	SampleRate       float64   `json:"sample_rate"`
+	CreatedBy        uint      `json:"created_by"`
+	ExpiresAt        time.Time `json:"expires_at"`
+}
+
+// CapturedExchange is a redacted request/response pair
+type CapturedExchange struct {
+	RequestID       string            `json:"request_id"`
+	UserID          uint              `json:"user_id,omitempty"`
+	Method          string            `json:"method"`
+	Path            string            `json:"path"`
+	Query           string            `json:"query,omitempty"`
+	RequestHeaders  map[string]string `json:"request_headers"`
+	RequestBody     string            `json:"request_body,omitempty"`
+	Status          int               `json:"status"`
+	ResponseHeaders map[string]string `json:"response_headers"`
+	ResponseBody    string            `json:"response_body,omitempty"`
+	LatencyMs       int64             `json:"latency_ms"`
+	CapturedAt      time.Time         `json:"captured_at"`
+}
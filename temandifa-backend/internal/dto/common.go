@@ -2,9 +2,20 @@ package dto
 
 // HealthCheck represents a single service health check result
 type HealthCheck struct {
+	Status         string         `json:"status"`
+	LatencyMs      int64          `json:"latency_ms,omitempty"`
+	Message        string         `json:"message,omitempty"`
+	Critical       bool           `json:"critical"`
+	LastChecked    string         `json:"last_checked,omitempty"`
+	LastTransition string         `json:"last_transition,omitempty"`
+	History        []HealthSample `json:"history,omitempty"`
+}
+
+// HealthSample is a single recorded health check result
+type HealthSample struct {
 	Status    string `json:"status"`
-	LatencyMs int64  `json:"latency_ms,omitempty"`
-	Message   string `json:"message,omitempty"`
+	LatencyMs int64  `json:"latency_ms"`
+	CheckedAt string `json:"checked_at"`
 }
 
 // HealthResponse is the structured health check response
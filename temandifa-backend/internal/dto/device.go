@@ -0,0 +1,11 @@
+package dto
+
+// RegisterDeviceRequest registers the caller's app installation. Without a
+// push token the device gets no push notifications; without an app version
+// X-App-Version is recorded.
+type RegisterDeviceRequest struct {
+	DeviceID   string `json:"device_id" binding:"required,max=128" example:"3f2a9c4e-7b1d-4e8a-9f60-2c5d8e1b7a43"`
+	Platform   string `json:"platform" binding:"required,oneof=android ios web" example:"android"`
+	PushToken  string `json:"push_token" binding:"omitempty,max=512"`
+	AppVersion string `json:"app_version" binding:"max=32" example:"2.4.0"`
+}
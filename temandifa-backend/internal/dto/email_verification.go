@@ -0,0 +1,11 @@
+package dto
+
+import "time"
+
+// EmailVerificationResponse is the account whose email a verification link
+// verified
+type EmailVerificationResponse struct {
+	UserID          uint      `json:"user_id"`
+	Email           string    `json:"email"`
+	EmailVerifiedAt time.Time `json:"email_verified_at"`
+}
@@ -0,0 +1,56 @@
+package dto
+
+import "temandifa-backend/internal/models"
+
+// EmergencyContactRequest adds or replaces an emergency contact, who needs a
+// phone number (E.164) or an email address to be reached
+type EmergencyContactRequest struct {
+	Name        string `json:"name" binding:"required,max=100" example:"Siti"`
+	PhoneNumber string `json:"phone_number" binding:"omitempty,e164" example:"+6281234567890"`
+	Email       string `json:"email" binding:"omitempty,email,max=255" example:"siti@example.com"`
+	Relation    string `json:"relation" binding:"max=50" example:"Saudara"`
+	IsPrimary   bool   `json:"is_primary" example:"true"`
+}
+
+// EmergencyContactResult is a contact and the channels a consent request was
+// just sent on; empty when none was sent or it could not be delivered
+type EmergencyContactResult struct {
+	models.EmergencyContact
+	ConsentRequestChannels []string `json:"consent_request_channels"`
+}
+
+// EmergencyConsent is what a contact sees when answering a consent request
+type EmergencyConsent struct {
+	RequestedBy   string `json:"requested_by" example:"Budi"`
+	ContactName   string `json:"contact_name" example:"Siti"`
+	ConsentStatus string `json:"consent_status" example:"pending"`
+}
+
+// AnswerEmergencyConsentRequest is a contact's answer to a consent request,
+// sent as JSON or as a form
+type AnswerEmergencyConsentRequest struct {
+	Decision string `json:"decision" form:"decision" binding:"required,oneof=accept decline" example:"accept"`
+}
+
+// EmergencyAlertRequest is the context shared with the contacts; a missing
+// scene description defaults to the user's latest object detection
+type EmergencyAlertRequest struct {
+	Latitude         *float64 `json:"latitude" binding:"required_with=Longitude,omitempty,min=-90,max=90" example:"-6.2088"`
+	Longitude        *float64 `json:"longitude" binding:"required_with=Latitude,omitempty,min=-180,max=180" example:"106.8456"`
+	SceneDescription string   `json:"scene_description" binding:"max=1000" example:"A crossing with heavy traffic"`
+	Message          string   `json:"message" binding:"max=500" example:"I fell and need help getting home"`
+}
+
+// EmergencyAlertDelivery is the channels an alert reached a contact on
+type EmergencyAlertDelivery struct {
+	ContactID uint     `json:"contact_id" example:"3"`
+	Name      string   `json:"name" example:"Siti"`
+	Channels  []string `json:"channels" example:"sms,email"`
+}
+
+// EmergencyAlertResult reports who an alert reached
+type EmergencyAlertResult struct {
+	AlertID          uint64                   `json:"alert_id" example:"12"`
+	ContactsNotified int                      `json:"contacts_notified" example:"1"`
+	Deliveries       []EmergencyAlertDelivery `json:"deliveries"`
+}
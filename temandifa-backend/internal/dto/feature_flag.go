@@ -0,0 +1,16 @@
+package dto
+
+// SaveFeatureFlagRequest creates or updates a feature flag
+type SaveFeatureFlagRequest struct {
+	Description       string `json:"description" binding:"max=500"`
+	Enabled           bool   `json:"enabled"`
+	RolloutPercentage *int   `json:"rollout_percentage" binding:"omitempty,min=0,max=100"` // Defaults to 100
+}
+
+// SetFeatureFlagOverrideRequest forces a flag on or off for a user (subject
+// is the user ID) or an organization (subject is the email domain)
+type SetFeatureFlagOverrideRequest struct {
+	SubjectType string `json:"subject_type" binding:"required,oneof=user organization"`
+	Subject     string `json:"subject" binding:"required,max=255"`
+	Enabled     bool   `json:"enabled"`
+}
@@ -0,0 +1,17 @@
+package dto
+
+import "time"
+
+// HistoryFilter selects a user's history entries
+type HistoryFilter struct {
+	FeatureType   string     // OBJECT, OCR or VOICE
+	Search        string     // Substring of the result text or input source
+	CreatedAfter  *time.Time // Entries recorded at or after
+	CreatedBefore *time.Time // Entries recorded before
+}
+
+// SignedURL is a short-lived link to stored media
+type SignedURL struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
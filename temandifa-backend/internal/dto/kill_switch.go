@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+// DisableOperationRequest switches an AI operation off; the reason is shown
+// to clients in the FEATURE_DISABLED response
+type DisableOperationRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// KillSwitchStatus is one AI operation's kill switch
+type KillSwitchStatus struct {
+	Operation  string     `json:"operation"`
+	Disabled   bool       `json:"disabled"`
+	Reason     string     `json:"reason,omitempty"`
+	DisabledBy *uint      `json:"disabled_by,omitempty"`
+	DisabledAt *time.Time `json:"disabled_at,omitempty"`
+}
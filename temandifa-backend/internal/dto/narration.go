@@ -0,0 +1,52 @@
+package dto
+
+// Narration event types sent over /ws/narrate
+const (
+	NarrationEventReady     = "ready"     // Session limits, sent once after connecting
+	NarrationEventObjects   = "objects"   // Objects that came into view
+	NarrationEventText      = "text"      // Text that came into view
+	NarrationEventThrottled = "throttled" // Frames are arriving faster than they are narrated
+	NarrationEventError     = "error"     // The session ends after most errors
+)
+
+// Narration priorities; clients speak higher priorities first and may
+// interrupt lower ones
+const (
+	NarrationPriorityHigh   = "high"   // Hazards such as vehicles
+	NarrationPriorityNormal = "normal" // Text
+	NarrationPriorityLow    = "low"    // Other objects
+)
+
+// NarrationConfig is a text message changing how the following frames are
+// narrated
+type NarrationConfig struct {
+	Type     string `json:"type" example:"config"`
+	Language string `json:"lang" example:"id"` // OCR language
+	Text     *bool  `json:"text,omitempty"`    // Read text in frames (default true)
+}
+
+// NarrationObject is a detected object
+type NarrationObject struct {
+	Label      string  `json:"label" example:"car"`
+	Confidence float32 `json:"confidence" example:"0.91"`
+}
+
+// NarrationEvent is a message pushed to the client
+type NarrationEvent struct {
+	Type     string            `json:"type"`
+	Priority string            `json:"priority,omitempty"`
+	Frame    int               `json:"frame,omitempty"` // Sequence number of the narrated frame
+	Objects  []NarrationObject `json:"objects,omitempty"`
+	Text     string            `json:"text,omitempty"`
+	Code     string            `json:"code,omitempty"` // Error code
+	Message  string            `json:"message,omitempty"`
+
+	// Ready only
+	MinFrameIntervalMs int64 `json:"min_frame_interval_ms,omitempty"`
+	MaxFrames          int   `json:"max_frames,omitempty"`
+	MaxDurationSeconds int64 `json:"max_duration_seconds,omitempty"`
+	MaxFrameBytes      int64 `json:"max_frame_bytes,omitempty"`
+
+	// Throttled only
+	RetryAfterMs int64 `json:"retry_after_ms,omitempty"`
+}
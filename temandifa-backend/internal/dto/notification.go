@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+// RegisterPushTokenRequest registers a device's Firebase Cloud Messaging token
+type RegisterPushTokenRequest struct {
+	Token    string `json:"token" binding:"required,max=512"`
+	Platform string `json:"platform" binding:"required,oneof=android ios web" example:"android"`
+}
+
+// UnregisterPushTokenRequest stops pushes to a device, e.g. on sign-out
+type UnregisterPushTokenRequest struct {
+	Token string `json:"token" binding:"required,max=512"`
+}
+
+// InboxNotification is a notification in the user's in-app inbox, with the
+// data fields its push carried
+type InboxNotification struct {
+	ID        uint64            `json:"id" example:"42"`
+	Type      string            `json:"type" example:"data_export_ready"`
+	Title     string            `json:"title" example:"Your data export is ready"`
+	Body      string            `json:"body"`
+	Data      map[string]string `json:"data"`
+	CreatedAt time.Time         `json:"created_at"`
+	ReadAt    *time.Time        `json:"read_at"`
+}
+
+// UnreadNotifications is the number of unread notifications in the inbox
+type UnreadNotifications struct {
+	UnreadCount int64 `json:"unread_count" example:"3"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+// UpdatePlanRequest changes a plan's description and allowances
+type UpdatePlanRequest struct {
+	Description         string  `json:"description" binding:"max=500"`
+	DailyAIQuota        int     `json:"daily_ai_quota" binding:"min=0" example:"100"`              // 0 for unlimited
+	RateLimitMultiplier float64 `json:"rate_limit_multiplier" binding:"required,gt=0" example:"2"` // At most 100
+	CacheTTLMultiplier  float64 `json:"cache_ttl_multiplier" binding:"required,gt=0" example:"2"`  // At most 100
+}
+
+// AssignPlanRequest sets a user's plan
+type AssignPlanRequest struct {
+	Plan string `json:"plan" binding:"required,max=32" example:"plus"`
+}
+
+// PlanAllowance is a user's plan and what is left of its daily AI quota
+type PlanAllowance struct {
+	Plan         string    `json:"plan" example:"free"`
+	DailyAIQuota int       `json:"daily_ai_quota" example:"100"`     // 0 for unlimited
+	Used         int64     `json:"used" example:"12"`                // AI requests today (UTC)
+	Remaining    *int64    `json:"remaining,omitempty" example:"88"` // Omitted when unlimited
+	ResetsAt     time.Time `json:"resets_at"`                        // Next UTC midnight
+}
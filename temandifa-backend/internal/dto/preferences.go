@@ -0,0 +1,11 @@
+package dto
+
+// UpdatePreferencesRequest changes some of the user's accessibility
+// preferences; omitted fields keep their value
+type UpdatePreferencesRequest struct {
+	TTSVoice    *string  `json:"tts_voice" binding:"omitempty,max=64" example:"id-ID-Standard-A"`
+	TTSSpeed    *float64 `json:"tts_speed" binding:"omitempty,min=0.5,max=2" example:"1.25"`
+	OCRLanguage *string  `json:"ocr_language" binding:"omitempty,oneof=en id ch" example:"id"`
+	Verbosity   *string  `json:"verbosity" binding:"omitempty,oneof=brief normal detailed" example:"brief"`
+	Haptics     *bool    `json:"haptics" example:"true"`
+}
@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+// Limiter and quota names of LimitStatus
+const (
+	LimitGeneral = "general"  // Every API request, per IP address
+	LimitAI      = "ai"       // AI requests, per user
+	LimitAIQuota = "ai_quota" // The plan's daily AI quota
+)
+
+// LimitStatus is where the caller stands with one rate limiter or quota
+type LimitStatus struct {
+	Name          string     `json:"name" example:"ai"`                // general, ai or ai_quota
+	Scope         string     `json:"scope" example:"user"`             // ip or user
+	Limit         int        `json:"limit" example:"20"`               // 0 when exempt or unlimited
+	WindowSeconds int        `json:"window_seconds" example:"60"`      // Sliding window; a day for the quota
+	Used          int64      `json:"used" example:"3"`                 // Requests counted in the window
+	Remaining     *int64     `json:"remaining,omitempty" example:"17"` // Omitted when exempt or unlimited
+	Exempt        bool       `json:"exempt,omitempty"`                 // Not rate limited at all
+	ResetsAt      *time.Time `json:"resets_at,omitempty"`              // When every counted request has left the window
+	RetryAt       *time.Time `json:"retry_at,omitempty"`               // When a request is allowed again; only when none remain
+}
+
+// RateLimits is the caller's standing with every limiter and quota
+type RateLimits struct {
+	Enforced bool          `json:"enforced"` // False when limits aren't counted (no Redis)
+	Limits   []LimitStatus `json:"limits"`
+}
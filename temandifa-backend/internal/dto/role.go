@@ -0,0 +1,29 @@
+package dto
+
+// Permission is a permission that can be attached to a role
+type Permission struct {
+	Name        string `json:"name" example:"users:read"`
+	Description string `json:"description"`
+}
+
+// CreateRoleRequest creates a role with an initial set of permissions
+type CreateRoleRequest struct {
+	Name        string   `json:"name" binding:"required,max=32" example:"support"` // Lower-case letters, digits, - and _
+	Description string   `json:"description" binding:"max=500" example:"Customer support staff"`
+	Permissions []string `json:"permissions" binding:"max=50,dive,max=64" example:"users:read,sessions:manage"`
+}
+
+// UpdateRoleRequest changes a role's description
+type UpdateRoleRequest struct {
+	Description string `json:"description" binding:"max=500"`
+}
+
+// AddRolePermissionsRequest attaches permissions to a role
+type AddRolePermissionsRequest struct {
+	Permissions []string `json:"permissions" binding:"required,min=1,max=50,dive,max=64" example:"analytics:read"`
+}
+
+// AssignRoleRequest sets a user's role
+type AssignRoleRequest struct {
+	Role string `json:"role" binding:"required,max=32" example:"support"`
+}
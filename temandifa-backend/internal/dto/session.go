@@ -0,0 +1,66 @@
+package dto
+
+import "time"
+
+// SessionFilter selects active sessions (unrevoked, unexpired refresh
+// tokens). It is read from the query string by GET /admin/sessions and from
+// the body by the bulk revoke.
+type SessionFilter struct {
+	UserID        uint       `form:"user_id" json:"user_id" example:"42"`                                                                         // Sessions of this user
+	IP            string     `form:"ip" json:"ip" binding:"max=45" example:"203.0.113."`                                                          // IP address or prefix (e.g. "203.0.113.")
+	UserAgent     string     `form:"user_agent" json:"user_agent" binding:"max=200" example:"python-requests"`                                    // Substring of the user agent
+	CreatedAfter  *time.Time `form:"created_after" json:"created_after" time_format:"2006-01-02T15:04:05Z07:00" example:"2026-10-01T00:00:00Z"`   // Sessions started at or after
+	CreatedBefore *time.Time `form:"created_before" json:"created_before" time_format:"2006-01-02T15:04:05Z07:00" example:"2026-10-02T00:00:00Z"` // Sessions started before
+}
+
+// Empty reports whether no criterion is set
+func (f SessionFilter) Empty() bool {
+	return f.UserID == 0 && f.IP == "" && f.UserAgent == "" && f.CreatedAfter == nil && f.CreatedBefore == nil
+}
+
+// AdminSession is an active login session as listed to administrators
+type AdminSession struct {
+	ID        uint      `json:"id"`
+	UserID    uint      `json:"user_id"`
+	IPAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Session is one of the caller's active login sessions
+type Session struct {
+	ID         uint      `json:"id"`
+	DeviceID   string    `json:"device_id,omitempty"` // Set when the session is bound to an app installation
+	IPAddress  string    `json:"ip_address"`          // Of the last sign-in or refresh
+	UserAgent  string    `json:"user_agent"`
+	CreatedAt  time.Time `json:"created_at"`   // Sign-in
+	LastUsedAt time.Time `json:"last_used_at"` // Last sign-in or token refresh
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"` // The session of the request's access token
+}
+
+// RevokeSessionsRequest revokes the listed sessions and/or every active
+// session matching the filter; at least one criterion is required so a bare
+// request can't log out every user
+type RevokeSessionsRequest struct {
+	SessionFilter
+	IDs []uint `json:"ids" binding:"max=1000" example:"12,15"` // Specific session IDs
+}
+
+// RevokeSessionsResponse reports how many sessions were revoked
+type RevokeSessionsResponse struct {
+	Revoked int64 `json:"revoked"`
+}
+
+// ForceLogoutRequest logs every user out, e.g. after a JWT secret leak; the
+// reason is kept in the audit log
+type ForceLogoutRequest struct {
+	Reason string `json:"reason" binding:"required,max=500" example:"JWT secret leaked in a build log"`
+}
+
+// ForceLogoutResponse reports the new token epoch and the revoked sessions
+type ForceLogoutResponse struct {
+	Epoch   int64 `json:"epoch"`
+	Revoked int64 `json:"revoked"`
+}
@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Outcomes of a pushed setting change
+const (
+	SettingApplied = "applied" // the change is now the setting
+	SettingMerged  = "merged"  // the change was concurrent with another; Setting is the winner
+	SettingStale   = "stale"   // the server has a newer value, returned in Setting
+)
+
+// SyncedSetting is a setting as stored, with the version vector of the
+// edits it reflects
+type SyncedSetting struct {
+	Key       string           `json:"key" example:"tts.speed"`
+	Value     json.RawMessage  `json:"value" swaggertype:"object"`
+	Vector    map[string]int64 `json:"vector"`
+	Deleted   bool             `json:"deleted"`
+	Seq       int64            `json:"seq" example:"17"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// SettingsSync is a delta of the user's settings: those changed after the
+// requested cursor, and the cursor to request next
+type SettingsSync struct {
+	Settings []SyncedSetting `json:"settings"`
+	Cursor   int64           `json:"cursor" example:"17"`
+}
+
+// SettingChange is a device's edit of a setting. The device increments its
+// own entry of the vector it last synced the setting with.
+type SettingChange struct {
+	Key     string           `json:"key" binding:"required,max=64" example:"tts.speed"`
+	Value   json.RawMessage  `json:"value" swaggertype:"object"`
+	Vector  map[string]int64 `json:"vector" binding:"required"`
+	Deleted bool             `json:"deleted"`
+}
+
+// SettingsSyncRequest pushes a device's edits, e.g. those made offline, and
+// asks for the settings changed after its last cursor
+type SettingsSyncRequest struct {
+	Since   int64           `json:"since" binding:"min=0" example:"12"`
+	Changes []SettingChange `json:"changes" binding:"max=100,dive"`
+}
+
+// SettingChangeResult is the outcome of one pushed change
+type SettingChangeResult struct {
+	Key     string        `json:"key" example:"tts.speed"`
+	Status  string        `json:"status" example:"applied"`
+	Setting SyncedSetting `json:"setting"`
+}
+
+// SettingsSyncResult is the outcome of each pushed change, and the delta
+// since the pushing device's cursor
+type SettingsSyncResult struct {
+	Results []SettingChangeResult `json:"results"`
+	SettingsSync
+}
@@ -0,0 +1,8 @@
+package dto
+
+// SimplifyRequest is text to make easier to listen to, e.g. OCR output
+type SimplifyRequest struct {
+	Text     string `json:"text" binding:"required"`
+	Language string `json:"language" binding:"omitempty,max=8" example:"id"`               // Language of the text (default: detected)
+	Level    string `json:"level" binding:"omitempty,oneof=easy very_easy" example:"easy"` // Reading level (default: easy)
+}
@@ -0,0 +1,34 @@
+package dto
+
+import "time"
+
+// ServiceStatus is the public status page: the current health of every
+// component, ongoing incidents and uptime over the last days
+type ServiceStatus struct {
+	Status     string            `json:"status" example:"operational"` // operational, degraded, major_outage or maintenance
+	UpdatedAt  time.Time         `json:"updated_at"`
+	Components []ComponentStatus `json:"components"`
+	Incidents  []StatusIncident  `json:"incidents"`
+}
+
+// ComponentStatus is the current status and uptime of one component
+type ComponentStatus struct {
+	Name          string      `json:"name" example:"database"`        // api, database, redis or ai_service
+	Status        string      `json:"status" example:"operational"`   // operational, outage, not_configured or unknown
+	UptimePercent *float64    `json:"uptime_percent" example:"99.98"` // Over the whole window; null without samples
+	Days          []UptimeDay `json:"days"`                           // Every day of the window, oldest first
+}
+
+// UptimeDay is a component's uptime on one UTC day
+type UptimeDay struct {
+	Day           string   `json:"day" example:"2026-10-16"`
+	UptimePercent *float64 `json:"uptime_percent" example:"100"` // Null without samples that day
+}
+
+// StatusIncident is an ongoing outage of a component or maintenance
+type StatusIncident struct {
+	Component string     `json:"component" example:"ai_service"`
+	Kind      string     `json:"kind" example:"outage"` // outage or maintenance
+	Message   string     `json:"message,omitempty"`
+	Since     *time.Time `json:"since,omitempty"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+import "time"
+
+// BuildInfo identifies the running build
+type BuildInfo struct {
+	Version string `json:"version" example:"v1.4.2"`
+	Commit  string `json:"commit" example:"3f2c1ab"`
+	Time    string `json:"time" example:"2026-10-01T08:00:00Z"`
+}
+
+// SystemInfo describes the instance answering the request, for incident triage
+type SystemInfo struct {
+	Build         BuildInfo         `json:"build"`
+	GoVersion     string            `json:"go_version" example:"go1.25.1"`
+	InstanceID    string            `json:"instance_id" example:"temandifa-backend-7d9f8-abcde"`
+	Profile       string            `json:"profile" example:"prod"` // APP_ENV
+	StartedAt     time.Time         `json:"started_at"`
+	Uptime        string            `json:"uptime" example:"72h3m12s"`
+	UptimeSeconds int64             `json:"uptime_seconds"`
+	Goroutines    int               `json:"goroutines"`
+	Services      map[string]string `json:"services"` // Server versions of backing services ("unavailable" when unreachable)
+	Modules       map[string]string `json:"modules"`  // Go module versions compiled in
+}
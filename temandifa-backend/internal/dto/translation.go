@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+// UploadTranslationBundleRequest is a new version of a locale's
+// translations; it replaces the previous one entirely
+type UploadTranslationBundleRequest struct {
+	App    map[string]string `json:"app" binding:"required_without=Server"` // App strings, e.g. {"home.title": "Beranda"}
+	Server map[string]string `json:"server" binding:"required_without=App"` // Server messages by error code or notification type, e.g. {"RATE_LIMITED": "Terlalu banyak permintaan"}
+}
+
+// TranslationBundle is a version of a locale's translations
+type TranslationBundle struct {
+	Locale    string            `json:"locale" example:"id"`
+	Version   int               `json:"version" example:"7"`
+	App       map[string]string `json:"app"`
+	Server    map[string]string `json:"server"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	ETag      string            `json:"-"`
+}
+
+// TranslationBundleVersion describes a version of a locale's translations
+type TranslationBundleVersion struct {
+	Locale        string    `json:"locale" example:"id"`
+	Version       int       `json:"version" example:"7"`
+	AppStrings    int       `json:"app_strings" example:"412"`   // Number of app strings
+	ServerStrings int       `json:"server_strings" example:"58"` // Number of server messages
+	Checksum      string    `json:"checksum"`
+	CreatedBy     *uint     `json:"created_by,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+// UsageReport is a user's usage over one UTC month. Cache hits are AI
+// calls answered from the cache instead of the AI Service.
+type UsageReport struct {
+	Month  string `json:"month" example:"2026-09"`
+	UserID uint   `json:"user_id"`
+	Email  string `json:"email,omitempty"` // Billing exports only; empty for purged users
+	Plan   string `json:"plan" example:"free"`
+	UsageStats
+	Operations   []OperationUsage `json:"operations"`                       // AI calls by operation
+	StorageBytes int64            `json:"storage_bytes" example:"10485760"` // Attachments created by the month's end, still stored when generated
+	Attachments  int64            `json:"attachments" example:"12"`
+	GeneratedAt  time.Time        `json:"generated_at"`
+}
+
+// UsageReportQuery selects the format of a report download
+type UsageReportQuery struct {
+	Format string `form:"format" binding:"omitempty,oneof=json csv" example:"csv"` // Defaults to json
+}
@@ -0,0 +1,27 @@
+package dto
+
+import "time"
+
+// Me is the authenticated user's profile with their plan allowance
+type Me struct {
+	ID                uint          `json:"id"`
+	Email             string        `json:"email"`
+	FullName          string        `json:"full_name"`
+	ProfilePicture    string        `json:"profile_picture"`
+	PreferredLanguage string        `json:"preferred_language" example:"id"` // Empty follows the device's language
+	Role              string        `json:"role"`
+	Organization      string        `json:"organization" example:"Universitas Indonesia"` // Set by institutional sign-in; empty otherwise
+	CreatedAt         time.Time     `json:"created_at"`
+	EmailVerifiedAt   *time.Time    `json:"email_verified_at"`
+	PendingEmail      string        `json:"pending_email,omitempty"` // Address a change waits to be confirmed for
+	Plan              PlanAllowance `json:"plan"`
+}
+
+// UpdateProfileRequest changes some of the user's profile; omitted fields
+// keep their value, and an empty profile_picture or preferred_language
+// clears it
+type UpdateProfileRequest struct {
+	FullName          *string `json:"full_name" binding:"omitempty,min=2,max=100" example:"Budi Santoso"`
+	PreferredLanguage *string `json:"preferred_language" binding:"omitempty,max=16" example:"id"`                           // BCP 47 tag
+	ProfilePicture    *string `json:"profile_picture" binding:"omitempty,max=2048" example:"https://cdn.example.com/a.png"` // Avatar URL, https
+}
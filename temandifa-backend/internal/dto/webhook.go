@@ -0,0 +1,33 @@
+package dto
+
+import "time"
+
+// CreateWebhookRequest subscribes a third party's endpoint to event types
+type CreateWebhookRequest struct {
+	Name       string   `json:"name" binding:"required,max=100" example:"CRM sync"`
+	URL        string   `json:"url" binding:"required,url,max=2048" example:"https://partner.example.com/hooks/temandifa"`
+	EventTypes []string `json:"event_types" binding:"required,min=1,dive,required" example:"user.registered"`
+	Active     *bool    `json:"active,omitempty"` // Defaults to true
+}
+
+// UpdateWebhookRequest replaces a subscription's settings
+type UpdateWebhookRequest struct {
+	Name         string   `json:"name" binding:"required,max=100" example:"CRM sync"`
+	URL          string   `json:"url" binding:"required,url,max=2048" example:"https://partner.example.com/hooks/temandifa"`
+	EventTypes   []string `json:"event_types" binding:"required,min=1,dive,required" example:"user.registered"`
+	Active       bool     `json:"active"`
+	RotateSecret bool     `json:"rotate_secret,omitempty"` // Issue a new signing secret
+}
+
+// WebhookSubscription is a webhook subscription as administrators see it
+type WebhookSubscription struct {
+	ID         uint64    `json:"id"`
+	Name       string    `json:"name" example:"CRM sync"`
+	URL        string    `json:"url" example:"https://partner.example.com/hooks/temandifa"`
+	EventTypes []string  `json:"event_types" example:"user.registered"`
+	Active     bool      `json:"active"`
+	Secret     string    `json:"secret,omitempty" example:"whsec_3f1c..."` // Only when created or rotated
+	CreatedBy  *uint     `json:"created_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
@@ -14,6 +14,8 @@ const (
 	ErrCodeTokenInvalid       ErrorCode = "TOKEN_INVALID"
 	ErrCodeTokenRevoked       ErrorCode = "TOKEN_REVOKED"
 	ErrCodeForbidden          ErrorCode = "FORBIDDEN"
+	ErrCodeEmailNotVerified   ErrorCode = "EMAIL_NOT_VERIFIED"
+	ErrCodeAccountLocked      ErrorCode = "ACCOUNT_LOCKED"
 
 	// Validation errors
 	ErrCodeValidation    ErrorCode = "VALIDATION_ERROR"
@@ -27,7 +29,8 @@ const (
 	ErrCodeAlreadyExist ErrorCode = "ALREADY_EXISTS"
 
 	// Rate Limiting
-	ErrCodeRateLimited ErrorCode = "RATE_LIMITED"
+	ErrCodeRateLimited   ErrorCode = "RATE_LIMITED"
+	ErrCodeQuotaExceeded ErrorCode = "QUOTA_EXCEEDED"
 
 	// External Services
 	ErrCodeAIServiceDown   ErrorCode = "AI_SERVICE_UNAVAILABLE"
@@ -40,8 +43,11 @@ const (
 	ErrCodeInternal           ErrorCode = "INTERNAL_ERROR"
 	ErrCodeTimeout            ErrorCode = "TIMEOUT"
 	ErrCodeServiceUnavailable ErrorCode = "SERVICE_UNAVAILABLE"
+	ErrCodeFeatureDisabled    ErrorCode = "FEATURE_DISABLED"
+	ErrCodeUpgradeRequired    ErrorCode = "UPGRADE_REQUIRED"
 
 	// File errors
 	ErrCodeFileTooLarge    ErrorCode = "FILE_TOO_LARGE"
 	ErrCodeInvalidFileType ErrorCode = "INVALID_FILE_TYPE"
+	ErrCodeMalwareDetected ErrorCode = "MALWARE_DETECTED"
 )
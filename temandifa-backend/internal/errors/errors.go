@@ -3,9 +3,14 @@ package errors
 import (
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/negotiate"
 )
 
 // Note: Error codes are defined in codes.go
@@ -108,6 +113,27 @@ func AlreadyExists(resource string) *AppError {
 	return NewAppError(ErrCodeAlreadyExist, fmt.Sprintf("%s already exists", resource), http.StatusConflict)
 }
 
+// VersionConflict reports an optimistic locking failure along with the
+// version currently stored, so clients can refetch and retry
+func VersionConflict(resource string, currentVersion uint) *AppError {
+	return NewAppError(ErrCodeConflict, fmt.Sprintf("%s was modified by another request", resource), http.StatusConflict).
+		WithDetails(map[string]uint{"current_version": currentVersion})
+}
+
+// LockoutDetails are the details of an ACCOUNT_LOCKED error
+type LockoutDetails struct {
+	UnlockAfter       time.Time `json:"unlock_after"`
+	RetryAfterSeconds int       `json:"retry_after_seconds"`
+}
+
+// AccountLocked reports logins refused until unlockAfter after too many
+// failed attempts
+func AccountLocked(unlockAfter time.Time) *AppError {
+	retryAfter := int(math.Ceil(time.Until(unlockAfter).Seconds()))
+	return NewAppError(ErrCodeAccountLocked, "Too many failed login attempts, please try again later", http.StatusTooManyRequests).
+		WithDetails(LockoutDetails{UnlockAfter: unlockAfter.UTC(), RetryAfterSeconds: max(retryAfter, 1)})
+}
+
 // Internal creates an internal error wrapping the original error
 func Internal(err error) *AppError {
 	return ErrInternal.Wrap(err)
@@ -128,13 +154,20 @@ func AsAppError(err error) (*AppError, bool) {
 }
 
 // RespondError sends an error response to the client
+// The request ID (and trace ID when tracing is enabled) is always included so
+// users can quote it in support tickets.
 func RespondError(c *gin.Context, err *AppError) {
-	c.JSON(err.StatusCode, gin.H{
+	body := gin.H{
 		"success": false,
 		"error": gin.H{
 			"code":    err.Code,
 			"message": err.Message,
 			"details": err.Details,
 		},
-	})
+		"request_id": logger.RequestIDFromContext(c),
+	}
+	if traceID := logger.TraceIDFromContext(c); traceID != "" {
+		body["trace_id"] = traceID
+	}
+	negotiate.Render(c, err.StatusCode, body)
 }
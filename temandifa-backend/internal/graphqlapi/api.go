@@ -0,0 +1,144 @@
+// Package graphqlapi is the mobile app's GraphQL API (schema.graphql): the
+// user's profile, history and usage in one request. The executable schema is
+// generated by gqlgen (gqlgen.yml); the resolvers are in resolvers.go.
+package graphqlapi
+
+import (
+	"context"
+	_ "embed"
+	"runtime/debug"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/executor"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+	"temandifa-backend/internal/services"
+)
+
+const (
+	// maxComplexity limits a query's cost; selecting every field of the
+	// schema once costs 91. The schema isn't recursive, so it also bounds
+	// the nesting depth.
+	maxComplexity = 200
+	// queryCost is the cost of fields resolved with a database query, so
+	// that aliases can't run many of them
+	queryCost = 10
+)
+
+// codeBadUserInput is the extensions code of errors about argument values
+const codeBadUserInput = "BAD_USER_INPUT"
+
+//go:embed schema.graphql
+var sdl string
+
+var Module = fx.Options(
+	fx.Provide(NewAPI),
+)
+
+// API executes GraphQL queries for an authenticated user
+type API struct {
+	exec        *executor.Executor
+	historyRepo repositories.HistoryRepository
+	usageRepo   repositories.UsageRepository
+}
+
+// NewAPI creates the GraphQL API on the services and repositories the REST
+// handlers use
+func NewAPI(users repositories.UserRepository, history services.HistoryService, historyRepo repositories.HistoryRepository, usageRepo repositories.UsageRepository, plans services.PlanService) *API {
+	cfg := Config{Resolvers: &resolver{users: users, history: history, plans: plans}}
+	cfg.Complexity.Query.Me = databaseQueryCost
+	cfg.Complexity.Query.History = func(childComplexity int, _ *HistoryFilter, _ *int, _ *int) int {
+		return databaseQueryCost(childComplexity)
+	}
+	cfg.Complexity.Query.Usage = func(childComplexity int, _ *int) int {
+		return databaseQueryCost(childComplexity)
+	}
+	cfg.Complexity.User.Plan = databaseQueryCost
+	cfg.Complexity.User.HistoryCount = func(childComplexity int, _ *models.FeatureType) int {
+		return databaseQueryCost(childComplexity)
+	}
+
+	exec := executor.New(NewExecutableSchema(cfg))
+	exec.Use(extension.FixedComplexityLimit(maxComplexity))
+	exec.SetErrorPresenter(presentError)
+	exec.SetRecoverFunc(func(ctx context.Context, recovered any) error {
+		logger.Ctx(ctx).Error("Panic in GraphQL resolver", zap.Any("panic", recovered), zap.String("stack", string(debug.Stack())))
+		return internalError()
+	})
+	return &API{exec: exec, historyRepo: historyRepo, usageRepo: usageRepo}
+}
+
+// databaseQueryCost is the complexity of a field resolved with a database
+// query
+func databaseQueryCost(childComplexity int) int {
+	return queryCost + childComplexity
+}
+
+// SDL returns the schema in the GraphQL schema definition language
+func SDL() string {
+	return sdl
+}
+
+// Execute runs a query as user
+func (a *API) Execute(ctx context.Context, user models.User, params *graphql.RawParams) *graphql.Response {
+	ctx = context.WithValue(ctx, requestKey{}, &request{
+		user:    user,
+		loaders: newLoaders(user.ID, a.historyRepo, a.usageRepo),
+	})
+	ctx = graphql.StartOperationTrace(ctx)
+
+	opCtx, errs := a.exec.CreateOperationContext(ctx, params)
+	if errs != nil {
+		return a.exec.DispatchError(graphql.WithOperationContext(ctx, opCtx), errs)
+	}
+	responses, ctx := a.exec.DispatchOperation(ctx, opCtx)
+	return responses(ctx)
+}
+
+type requestKey struct{}
+
+// request is the state of one GraphQL request
+type request struct {
+	user    models.User
+	loaders *loaders
+}
+
+func requestFrom(ctx context.Context) *request {
+	return ctx.Value(requestKey{}).(*request)
+}
+
+// inputError reports an invalid argument value
+func inputError(message string) *gqlerror.Error {
+	return &gqlerror.Error{Message: message, Extensions: map[string]any{"code": codeBadUserInput}}
+}
+
+func internalError() *gqlerror.Error {
+	return &gqlerror.Error{Message: "Internal server error", Extensions: map[string]any{"code": string(apperrors.ErrCodeInternal)}}
+}
+
+// presentError reports app errors with their REST error code and errors
+// raised as GraphQL errors (invalid arguments) as they are; other errors are
+// logged and reported as internal
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+	if appErr, ok := apperrors.AsAppError(err); ok {
+		gqlErr.Message = appErr.Message
+		gqlErr.Extensions = map[string]any{"code": string(appErr.Code)}
+		return gqlErr
+	}
+	if gqlErr.Err == nil {
+		return gqlErr
+	}
+
+	logger.Ctx(ctx).Error("GraphQL resolver failed", zap.Error(err))
+	internal := internalError()
+	internal.Path = gqlErr.Path
+	return internal
+}
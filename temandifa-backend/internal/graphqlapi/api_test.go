@@ -0,0 +1,179 @@
+package graphqlapi
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+	"temandifa-backend/internal/services"
+)
+
+var testTime = time.Date(2026, 5, 1, 8, 30, 0, 0, time.UTC)
+
+// The fakes implement what the API calls; other methods panic
+
+type fakeUsers struct {
+	repositories.UserRepository
+}
+
+func (fakeUsers) FindByID(_ context.Context, id uint) (*models.User, error) {
+	if id != 7 {
+		return nil, nil
+	}
+	user := &models.User{Email: "rani@example.com", FullName: "Rani", Role: "user"}
+	user.ID, user.CreatedAt = id, testTime
+	return user, nil
+}
+
+type fakeHistory struct {
+	services.HistoryService
+	repositories.HistoryRepository
+	counts atomic.Int32
+}
+
+func (*fakeHistory) FindUserHistory(_ context.Context, _ uint, filter dto.HistoryFilter, page, limit int) ([]models.History, int64, error) {
+	entry := models.History{FeatureType: models.FeatureOCR, InputSource: "camera", ResultText: "Jalan " + filter.Search}
+	entry.ID, entry.CreatedAt = 3, testTime
+	return []models.History{entry}, 21, nil
+}
+
+func (h *fakeHistory) CountByFeature(context.Context, uint) (map[models.FeatureType]int64, error) {
+	h.counts.Add(1)
+	return map[models.FeatureType]int64{models.FeatureOCR: 4, models.FeatureVoice: 2}, nil
+}
+
+type fakeUsage struct {
+	repositories.UsageRepository
+}
+
+func (fakeUsage) UserDaily(_ context.Context, _ uint, _, to time.Time) ([]repositories.UsageSummary, error) {
+	return []repositories.UsageSummary{{Day: to.AddDate(0, 0, -1), Operation: "ocr", Requests: 4, CacheHits: 1}}, nil
+}
+
+type fakePlans struct {
+	services.PlanService
+}
+
+func (fakePlans) Allowance(context.Context, *models.User) (*dto.PlanAllowance, error) {
+	remaining := int64(88)
+	return &dto.PlanAllowance{Plan: "free", DailyAIQuota: 100, Used: 12, Remaining: &remaining, ResetsAt: testTime}, nil
+}
+
+func newTestAPI(t *testing.T) (*API, *fakeHistory) {
+	t.Helper()
+	logger.Log = zap.NewNop()
+	history := &fakeHistory{}
+	return NewAPI(fakeUsers{}, history, history, fakeUsage{}, fakePlans{}), history
+}
+
+func execute(t *testing.T, api *API, userID uint, query string, variables map[string]any) string {
+	t.Helper()
+	user := models.User{}
+	user.ID = userID
+	resp := api.Execute(context.Background(), user, &graphql.RawParams{Query: query, Variables: variables})
+	b, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	return string(b)
+}
+
+func TestExecute(t *testing.T) {
+	api, history := newTestAPI(t)
+
+	tests := []struct {
+		name      string
+		userID    uint
+		query     string
+		variables map[string]any
+		want      string
+	}{
+		{
+			name:   "profile with batched counts",
+			userID: 7,
+			query:  `{ me { id fullName profilePicture createdAt plan { used remaining resetsAt } all: historyCount ocr: historyCount(featureType: OCR) } }`,
+			want:   `{"data":{"me":{"id":"7","fullName":"Rani","profilePicture":null,"createdAt":"2026-05-01T08:30:00Z","plan":{"used":12,"remaining":88,"resetsAt":"2026-05-01T08:30:00Z"},"all":6,"ocr":4}}}`,
+		},
+		{
+			name:      "history with variables",
+			userID:    7,
+			query:     `query ($search: String) { history(filter: {featureType: OCR, search: $search}, limit: 1) { entries { id featureType resultText } totalCount page limit } }`,
+			variables: map[string]any{"search": "Sudirman"},
+			want:      `{"data":{"history":{"entries":[{"id":"3","featureType":"OCR","resultText":"Jalan Sudirman"}],"totalCount":21,"page":1,"limit":1}}}`,
+		},
+		{
+			name:   "usage",
+			userID: 7,
+			query:  `{ usage(days: 2) { days total { requests cacheHitRate } daily { stats { cacheHits } } } }`,
+			want:   `{"data":{"usage":{"days":2,"total":{"requests":4,"cacheHitRate":0.25},"daily":[{"stats":{"cacheHits":1}}]}}}`,
+		},
+		{
+			name:   "invalid argument",
+			userID: 7,
+			query:  `{ usage(days: 91) { days } }`,
+			want:   `{"errors":[{"message":"days must be between 1 and 90","path":["usage"],"extensions":{"code":"BAD_USER_INPUT"}}],"data":null}`,
+		},
+		{
+			name:   "invalid time in the filter",
+			userID: 7,
+			query:  `{ history(filter: {createdAfter: "yesterday"}) { totalCount } }`,
+			want:   `{"errors":[{"message":"createdAfter must be an RFC 3339 time","path":["history"],"extensions":{"code":"BAD_USER_INPUT"}}],"data":null}`,
+		},
+		{
+			name:   "app error",
+			userID: 8,
+			query:  `{ me { id } }`,
+			want:   `{"errors":[{"message":"Resource not found","path":["me"],"extensions":{"code":"` + string(apperrors.ErrCodeNotFound) + `"}}],"data":null}`,
+		},
+		{
+			name:   "validation error",
+			userID: 7,
+			query:  `{ me { password } }`,
+			want:   `{"errors":[{"message":"Cannot query field \"password\" on type \"User\".","locations":[{"line":1,"column":8}],"extensions":{"code":"GRAPHQL_VALIDATION_FAILED"}}],"data":null}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := execute(t, api, tt.userID, tt.query, tt.variables)
+			if got != tt.want {
+				t.Errorf("Execute() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if n := history.counts.Load(); n != 1 {
+		t.Errorf("CountByFeature() called %d times, want 1", n)
+	}
+}
+
+func TestExecuteComplexityLimit(t *testing.T) {
+	api, _ := newTestAPI(t)
+
+	full := `{
+		me { id email fullName profilePicture role createdAt plan { plan dailyAIQuota used remaining resetsAt } historyCount }
+		history { entries { id featureType inputSource resultText createdAt clientId } totalCount page limit }
+		usage { days total { requests cacheHits cacheHitRate errors } byOperation { operation stats { requests cacheHits cacheHitRate errors } } daily { day stats { requests cacheHits cacheHitRate errors } } }
+	}`
+	if got := execute(t, api, 7, full, nil); strings.Contains(got, `"errors":[`) {
+		t.Errorf("Execute() of every field = %s, want data only", got)
+	}
+
+	aliased := `{ a: me { plan { used } } b: me { plan { used } } c: me { plan { used } } d: me { plan { used } } e: me { plan { used } }
+		f: me { plan { used } } g: me { plan { used } } h: me { plan { used } } i: me { plan { used } } j: me { plan { used } } }`
+	want := `{"errors":[{"message":"operation has complexity 210, which exceeds the limit of 200","extensions":{"code":"COMPLEXITY_LIMIT_EXCEEDED"}}],"data":null}`
+	if got := execute(t, api, 7, aliased, nil); got != want {
+		t.Errorf("Execute() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,82 @@
+package graphqlapi
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// defaultLoaderWait is how long a loader collects keys before fetching
+const defaultLoaderWait = 2 * time.Millisecond
+
+// batchFunc fetches the values of keys in one call; keys missing from the
+// result load as the zero value
+type batchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// loader batches the loads of concurrently resolved fields into one fetch
+// and caches the results, so a query asking for the same data in several
+// places hits the repository once. Create one per request.
+type loader[K comparable, V any] struct {
+	fetch batchFunc[K, V]
+	wait  time.Duration
+
+	mu      sync.Mutex
+	results map[K]*loaderResult[V]
+	pending []K
+}
+
+type loaderResult[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// newLoader creates a loader collecting keys for wait (0: 2ms) before
+// fetching them
+func newLoader[K comparable, V any](fetch batchFunc[K, V], wait time.Duration) *loader[K, V] {
+	if wait <= 0 {
+		wait = defaultLoaderWait
+	}
+	return &loader[K, V]{fetch: fetch, wait: wait, results: map[K]*loaderResult[V]{}}
+}
+
+// Load returns the value of key, joining the batch being collected
+func (l *loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	result, ok := l.results[key]
+	if !ok {
+		result = &loaderResult[V]{done: make(chan struct{})}
+		l.results[key] = result
+		l.pending = append(l.pending, key)
+		if len(l.pending) == 1 {
+			time.AfterFunc(l.wait, func() { l.dispatch(context.WithoutCancel(ctx)) })
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-result.done:
+		return result.value, result.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// dispatch fetches the collected keys
+func (l *loader[K, V]) dispatch(ctx context.Context) {
+	l.mu.Lock()
+	keys := l.pending
+	l.pending = nil
+	batch := make([]*loaderResult[V], len(keys))
+	for i, key := range keys {
+		batch[i] = l.results[key]
+	}
+	l.mu.Unlock()
+
+	values, err := l.fetch(ctx, keys)
+	for i, key := range keys {
+		batch[i].value, batch[i].err = values[key], err
+		close(batch[i].done)
+	}
+}
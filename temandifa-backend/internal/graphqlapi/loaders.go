@@ -0,0 +1,107 @@
+package graphqlapi
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+)
+
+const dayLayout = "2006-01-02"
+
+// loaders batch a request's repository reads: every historyCount is served
+// by one GROUP BY query and every usage window by one query of the widest
+type loaders struct {
+	historyCounts *loader[models.FeatureType, int]
+	usage         *loader[int, *Usage]
+}
+
+func newLoaders(userID uint, historyRepo repositories.HistoryRepository, usageRepo repositories.UsageRepository) *loaders {
+	return &loaders{
+		historyCounts: newLoader(func(ctx context.Context, featureTypes []models.FeatureType) (map[models.FeatureType]int, error) {
+			counts, err := historyRepo.CountByFeature(ctx, userID)
+			if err != nil {
+				return nil, err
+			}
+			result := make(map[models.FeatureType]int, len(featureTypes))
+			for _, featureType := range featureTypes {
+				if featureType != "" {
+					result[featureType] = int(counts[featureType])
+					continue
+				}
+				for _, count := range counts {
+					result[featureType] += int(count)
+				}
+			}
+			return result, nil
+		}, 0),
+
+		usage: newLoader(func(ctx context.Context, windows []int) (map[int]*Usage, error) {
+			widest := windows[0]
+			for _, days := range windows {
+				widest = max(widest, days)
+			}
+			to := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+			rows, err := usageRepo.UserDaily(ctx, userID, to.AddDate(0, 0, -widest), to)
+			if err != nil {
+				return nil, err
+			}
+
+			result := make(map[int]*Usage, len(windows))
+			for _, days := range windows {
+				result[days] = usageReport(rows, to.AddDate(0, 0, -days), days)
+			}
+			return result, nil
+		}, 0),
+	}
+}
+
+// usageReport sums the per day and operation rows from the day from onwards
+func usageReport(rows []repositories.UsageSummary, from time.Time, days int) *Usage {
+	report := &Usage{Days: days, Total: &UsageStats{}, ByOperation: []OperationUsage{}, Daily: []DailyUsage{}}
+	operations := map[string]*UsageStats{}
+	for _, row := range rows {
+		if row.Day.Before(from) {
+			continue
+		}
+		report.Total.add(row)
+
+		op, ok := operations[row.Operation]
+		if !ok {
+			op = &UsageStats{}
+			operations[row.Operation] = op
+		}
+		op.add(row)
+
+		// Rows are ordered by day
+		day := row.Day.UTC().Format(dayLayout)
+		if n := len(report.Daily); n == 0 || report.Daily[n-1].Day != day {
+			report.Daily = append(report.Daily, DailyUsage{Day: day, Stats: &UsageStats{}})
+		}
+		report.Daily[len(report.Daily)-1].Stats.add(row)
+	}
+
+	for operation, stats := range operations {
+		report.ByOperation = append(report.ByOperation, OperationUsage{Operation: operation, Stats: stats})
+	}
+	sort.Slice(report.ByOperation, func(i, j int) bool {
+		a, b := report.ByOperation[i], report.ByOperation[j]
+		if a.Stats.Requests != b.Stats.Requests {
+			return a.Stats.Requests > b.Stats.Requests
+		}
+		return a.Operation < b.Operation
+	})
+	return report
+}
+
+func (s *UsageStats) add(row repositories.UsageSummary) {
+	s.Requests += int(row.Requests)
+	s.CacheHits += int(row.CacheHits)
+	s.Errors += int(row.Errors)
+	s.CacheHitRate = 0
+	if s.Requests > 0 {
+		s.CacheHitRate = float64(s.CacheHits) / float64(s.Requests)
+	}
+}
@@ -0,0 +1,180 @@
+package graphqlapi
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+	"temandifa-backend/internal/services"
+)
+
+const (
+	maxHistoryLimit = 100
+	maxUsageDays    = 90
+)
+
+// User is the User type; plan and historyCount have resolvers
+type User struct {
+	ID             string  `json:"id"`
+	Email          string  `json:"email"`
+	FullName       string  `json:"fullName"`
+	ProfilePicture *string `json:"profilePicture"`
+	Role           string  `json:"role"`
+	CreatedAt      string  `json:"createdAt"`
+
+	model *models.User
+}
+
+// resolver is the ResolverRoot of the generated schema
+type resolver struct {
+	users   repositories.UserRepository
+	history services.HistoryService
+	plans   services.PlanService
+}
+
+func (r *resolver) Query() QueryResolver {
+	return &queryResolver{r}
+}
+
+func (r *resolver) User() UserResolver {
+	return &userResolver{r}
+}
+
+type queryResolver struct{ *resolver }
+
+func (r *queryResolver) Me(ctx context.Context) (*User, error) {
+	// Read the user again: the authenticated one may be cached
+	found, err := r.users.FindByID(ctx, requestFrom(ctx).user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, apperrors.ErrNotFound
+	}
+	return newUser(found), nil
+}
+
+func (r *queryResolver) History(ctx context.Context, filter *HistoryFilter, page *int, limit *int) (*HistoryPage, error) {
+	if page == nil || *page < 1 {
+		return nil, inputError("page must be at least 1")
+	}
+	if limit == nil || *limit < 1 || *limit > maxHistoryLimit {
+		return nil, inputError(fmt.Sprintf("limit must be between 1 and %d", maxHistoryLimit))
+	}
+
+	query, err := historyFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, total, err := r.history.FindUserHistory(ctx, requestFrom(ctx).user.ID, query, *page, *limit)
+	if err != nil {
+		return nil, err
+	}
+	result := &HistoryPage{Entries: make([]HistoryEntry, len(entries)), TotalCount: int(total), Page: *page, Limit: *limit}
+	for i, entry := range entries {
+		result.Entries[i] = HistoryEntry{
+			ID:          strconv.FormatUint(uint64(entry.ID), 10),
+			FeatureType: entry.FeatureType,
+			InputSource: entry.InputSource,
+			ResultText:  entry.ResultText,
+			CreatedAt:   formatTime(entry.CreatedAt),
+			ClientID:    entry.ClientID,
+		}
+	}
+	return result, nil
+}
+
+func (r *queryResolver) Usage(ctx context.Context, days *int) (*Usage, error) {
+	if days == nil || *days < 1 || *days > maxUsageDays {
+		return nil, inputError(fmt.Sprintf("days must be between 1 and %d", maxUsageDays))
+	}
+	return requestFrom(ctx).loaders.usage.Load(ctx, *days)
+}
+
+type userResolver struct{ *resolver }
+
+func (r *userResolver) Plan(ctx context.Context, obj *User) (*PlanAllowance, error) {
+	allowance, err := r.plans.Allowance(ctx, obj.model)
+	if err != nil {
+		return nil, err
+	}
+	result := &PlanAllowance{
+		Plan:         allowance.Plan,
+		DailyAIQuota: allowance.DailyAIQuota,
+		Used:         int(allowance.Used),
+		ResetsAt:     formatTime(allowance.ResetsAt),
+	}
+	if allowance.Remaining != nil {
+		remaining := int(*allowance.Remaining)
+		result.Remaining = &remaining
+	}
+	return result, nil
+}
+
+func (r *userResolver) HistoryCount(ctx context.Context, _ *User, featureType *models.FeatureType) (int, error) {
+	var key models.FeatureType
+	if featureType != nil {
+		key = *featureType
+	}
+	return requestFrom(ctx).loaders.historyCounts.Load(ctx, key)
+}
+
+func historyFilter(input *HistoryFilter) (dto.HistoryFilter, error) {
+	var filter dto.HistoryFilter
+	if input == nil {
+		return filter, nil
+	}
+
+	if input.FeatureType != nil {
+		filter.FeatureType = string(*input.FeatureType)
+	}
+	if input.Search != nil {
+		filter.Search = *input.Search
+	}
+	var err error
+	if filter.CreatedAfter, err = timeArg(input.CreatedAfter, "createdAfter"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedBefore, err = timeArg(input.CreatedBefore, "createdBefore"); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+// timeArg parses an RFC 3339 time argument, nil when not given
+func timeArg(value *string, name string) (*time.Time, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, *value)
+	if err != nil {
+		return nil, inputError(fmt.Sprintf("%s must be an RFC 3339 time", name))
+	}
+	return &parsed, nil
+}
+
+// formatTime formats times as encoding/json does
+func formatTime(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+func newUser(model *models.User) *User {
+	u := &User{
+		ID:        strconv.FormatUint(uint64(model.ID), 10),
+		Email:     model.Email,
+		FullName:  model.FullName,
+		Role:      model.Role,
+		CreatedAt: formatTime(model.CreatedAt),
+		model:     model,
+	}
+	if model.ProfilePicture != "" {
+		u.ProfilePicture = &model.ProfilePicture
+	}
+	return u
+}
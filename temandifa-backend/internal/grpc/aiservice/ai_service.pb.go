@@ -629,6 +629,150 @@ func (x *TranscriptionResponse) GetDuration() float32 {
 	return 0
 }
 
+type SimplifyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Language      string                 `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`                                   // Optional language code, e.g. id, en
+	Level         string                 `protobuf:"bytes,3,opt,name=level,proto3" json:"level,omitempty"`                                         // easy or very_easy
+	MaxChunkChars int32                  `protobuf:"varint,4,opt,name=max_chunk_chars,json=maxChunkChars,proto3" json:"max_chunk_chars,omitempty"` // Upper bound of a chunk's length
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SimplifyRequest) Reset() {
+	*x = SimplifyRequest{}
+	mi := &file_proto_ai_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SimplifyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimplifyRequest) ProtoMessage() {}
+
+func (x *SimplifyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ai_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimplifyRequest.ProtoReflect.Descriptor instead.
+func (*SimplifyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ai_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SimplifyRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *SimplifyRequest) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *SimplifyRequest) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *SimplifyRequest) GetMaxChunkChars() int32 {
+	if x != nil {
+		return x.MaxChunkChars
+	}
+	return 0
+}
+
+type SimplifyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Text          string                 `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`              // Simplified text
+	Chunks        []string               `protobuf:"bytes,4,rep,name=chunks,proto3" json:"chunks,omitempty"`          // Sentence-aligned chunks to play back one by one
+	Simplified    bool                   `protobuf:"varint,5,opt,name=simplified,proto3" json:"simplified,omitempty"` // False when the text was only chunked
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SimplifyResponse) Reset() {
+	*x = SimplifyResponse{}
+	mi := &file_proto_ai_service_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SimplifyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimplifyResponse) ProtoMessage() {}
+
+func (x *SimplifyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ai_service_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimplifyResponse.ProtoReflect.Descriptor instead.
+func (*SimplifyResponse) Descriptor() ([]byte, []int) {
+	return file_proto_ai_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SimplifyResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SimplifyResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SimplifyResponse) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *SimplifyResponse) GetChunks() []string {
+	if x != nil {
+		return x.Chunks
+	}
+	return nil
+}
+
+func (x *SimplifyResponse) GetSimplified() bool {
+	if x != nil {
+		return x.Simplified
+	}
+	return false
+}
+
 var File_proto_ai_service_proto protoreflect.FileDescriptor
 
 const file_proto_ai_service_proto_rawDesc = "" +
@@ -684,12 +828,26 @@ const file_proto_ai_service_proto_rawDesc = "" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x12\n" +
 	"\x04text\x18\x02 \x01(\tR\x04text\x12\x1a\n" +
 	"\blanguage\x18\x03 \x01(\tR\blanguage\x12\x1a\n" +
-	"\bduration\x18\x04 \x01(\x02R\bduration2\xa9\x02\n" +
+	"\bduration\x18\x04 \x01(\x02R\bduration\"\x7f\n" +
+	"\x0fSimplifyRequest\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\x1a\n" +
+	"\blanguage\x18\x02 \x01(\tR\blanguage\x12\x14\n" +
+	"\x05level\x18\x03 \x01(\tR\x05level\x12&\n" +
+	"\x0fmax_chunk_chars\x18\x04 \x01(\x05R\rmaxChunkChars\"\x92\x01\n" +
+	"\x10SimplifyResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x12\n" +
+	"\x04text\x18\x03 \x01(\tR\x04text\x12\x16\n" +
+	"\x06chunks\x18\x04 \x03(\tR\x06chunks\x12\x1e\n" +
+	"\n" +
+	"simplified\x18\x05 \x01(\bR\n" +
+	"simplified2\xf2\x02\n" +
 	"\tAIService\x12F\n" +
 	"\rDetectObjects\x12\x17.aiservice.ImageRequest\x1a\x1c.aiservice.DetectionResponse\x12<\n" +
 	"\vExtractText\x12\x15.aiservice.OCRRequest\x1a\x16.aiservice.OCRResponse\x12L\n" +
 	"\x0fTranscribeAudio\x12\x17.aiservice.AudioRequest\x1a .aiservice.transcriptionResponse\x12H\n" +
-	"\x17VisualQuestionAnswering\x12\x15.aiservice.VQARequest\x1a\x16.aiservice.VQAResponseB+Z)temandifa-backend/internal/grpc/aiserviceb\x06proto3"
+	"\x17VisualQuestionAnswering\x12\x15.aiservice.VQARequest\x1a\x16.aiservice.VQAResponse\x12G\n" +
+	"\fSimplifyText\x12\x1a.aiservice.SimplifyRequest\x1a\x1b.aiservice.SimplifyResponseB+Z)temandifa-backend/internal/grpc/aiserviceb\x06proto3"
 
 var (
 	file_proto_ai_service_proto_rawDescOnce sync.Once
@@ -703,7 +861,7 @@ func file_proto_ai_service_proto_rawDescGZIP() []byte {
 	return file_proto_ai_service_proto_rawDescData
 }
 
-var file_proto_ai_service_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_proto_ai_service_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
 var file_proto_ai_service_proto_goTypes = []any{
 	(*ImageRequest)(nil),          // 0: aiservice.ImageRequest
 	(*AudioRequest)(nil),          // 1: aiservice.AudioRequest
@@ -715,23 +873,27 @@ var file_proto_ai_service_proto_goTypes = []any{
 	(*OCRResponse)(nil),           // 7: aiservice.OCRResponse
 	(*OCRLine)(nil),               // 8: aiservice.OCRLine
 	(*TranscriptionResponse)(nil), // 9: aiservice.transcriptionResponse
+	(*SimplifyRequest)(nil),       // 10: aiservice.SimplifyRequest
+	(*SimplifyResponse)(nil),      // 11: aiservice.SimplifyResponse
 }
 var file_proto_ai_service_proto_depIdxs = []int32{
-	5, // 0: aiservice.DetectionResponse.objects:type_name -> aiservice.DetectedObject
-	8, // 1: aiservice.OCRResponse.lines:type_name -> aiservice.OCRLine
-	0, // 2: aiservice.AIService.DetectObjects:input_type -> aiservice.ImageRequest
-	6, // 3: aiservice.AIService.ExtractText:input_type -> aiservice.OCRRequest
-	1, // 4: aiservice.AIService.TranscribeAudio:input_type -> aiservice.AudioRequest
-	2, // 5: aiservice.AIService.VisualQuestionAnswering:input_type -> aiservice.VQARequest
-	4, // 6: aiservice.AIService.DetectObjects:output_type -> aiservice.DetectionResponse
-	7, // 7: aiservice.AIService.ExtractText:output_type -> aiservice.OCRResponse
-	9, // 8: aiservice.AIService.TranscribeAudio:output_type -> aiservice.transcriptionResponse
-	3, // 9: aiservice.AIService.VisualQuestionAnswering:output_type -> aiservice.VQAResponse
-	6, // [6:10] is the sub-list for method output_type
-	2, // [2:6] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	5,  // 0: aiservice.DetectionResponse.objects:type_name -> aiservice.DetectedObject
+	8,  // 1: aiservice.OCRResponse.lines:type_name -> aiservice.OCRLine
+	0,  // 2: aiservice.AIService.DetectObjects:input_type -> aiservice.ImageRequest
+	6,  // 3: aiservice.AIService.ExtractText:input_type -> aiservice.OCRRequest
+	1,  // 4: aiservice.AIService.TranscribeAudio:input_type -> aiservice.AudioRequest
+	2,  // 5: aiservice.AIService.VisualQuestionAnswering:input_type -> aiservice.VQARequest
+	10, // 6: aiservice.AIService.SimplifyText:input_type -> aiservice.SimplifyRequest
+	4,  // 7: aiservice.AIService.DetectObjects:output_type -> aiservice.DetectionResponse
+	7,  // 8: aiservice.AIService.ExtractText:output_type -> aiservice.OCRResponse
+	9,  // 9: aiservice.AIService.TranscribeAudio:output_type -> aiservice.transcriptionResponse
+	3,  // 10: aiservice.AIService.VisualQuestionAnswering:output_type -> aiservice.VQAResponse
+	11, // 11: aiservice.AIService.SimplifyText:output_type -> aiservice.SimplifyResponse
+	7,  // [7:12] is the sub-list for method output_type
+	2,  // [2:7] is the sub-list for method input_type
+	2,  // [2:2] is the sub-list for extension type_name
+	2,  // [2:2] is the sub-list for extension extendee
+	0,  // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_proto_ai_service_proto_init() }
@@ -745,7 +907,7 @@ func file_proto_ai_service_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_ai_service_proto_rawDesc), len(file_proto_ai_service_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   10,
+			NumMessages:   12,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
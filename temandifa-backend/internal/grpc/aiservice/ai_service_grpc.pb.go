@@ -23,6 +23,7 @@ const (
 	AIService_ExtractText_FullMethodName             = "/aiservice.AIService/ExtractText"
 	AIService_TranscribeAudio_FullMethodName         = "/aiservice.AIService/TranscribeAudio"
 	AIService_VisualQuestionAnswering_FullMethodName = "/aiservice.AIService/VisualQuestionAnswering"
+	AIService_SimplifyText_FullMethodName            = "/aiservice.AIService/SimplifyText"
 )
 
 // AIServiceClient is the client API for AIService service.
@@ -37,6 +38,8 @@ type AIServiceClient interface {
 	TranscribeAudio(ctx context.Context, in *AudioRequest, opts ...grpc.CallOption) (*TranscriptionResponse, error)
 	// Visual Question Answering (VQA)
 	VisualQuestionAnswering(ctx context.Context, in *VQARequest, opts ...grpc.CallOption) (*VQAResponse, error)
+	// Simplifies text to an easy reading level and chunks it for TTS playback
+	SimplifyText(ctx context.Context, in *SimplifyRequest, opts ...grpc.CallOption) (*SimplifyResponse, error)
 }
 
 type aIServiceClient struct {
@@ -87,6 +90,16 @@ func (c *aIServiceClient) VisualQuestionAnswering(ctx context.Context, in *VQARe
 	return out, nil
 }
 
+func (c *aIServiceClient) SimplifyText(ctx context.Context, in *SimplifyRequest, opts ...grpc.CallOption) (*SimplifyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SimplifyResponse)
+	err := c.cc.Invoke(ctx, AIService_SimplifyText_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AIServiceServer is the server API for AIService service.
 // All implementations must embed UnimplementedAIServiceServer
 // for forward compatibility.
@@ -99,6 +112,8 @@ type AIServiceServer interface {
 	TranscribeAudio(context.Context, *AudioRequest) (*TranscriptionResponse, error)
 	// Visual Question Answering (VQA)
 	VisualQuestionAnswering(context.Context, *VQARequest) (*VQAResponse, error)
+	// Simplifies text to an easy reading level and chunks it for TTS playback
+	SimplifyText(context.Context, *SimplifyRequest) (*SimplifyResponse, error)
 	mustEmbedUnimplementedAIServiceServer()
 }
 
@@ -121,6 +136,9 @@ func (UnimplementedAIServiceServer) TranscribeAudio(context.Context, *AudioReque
 func (UnimplementedAIServiceServer) VisualQuestionAnswering(context.Context, *VQARequest) (*VQAResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method VisualQuestionAnswering not implemented")
 }
+func (UnimplementedAIServiceServer) SimplifyText(context.Context, *SimplifyRequest) (*SimplifyResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SimplifyText not implemented")
+}
 func (UnimplementedAIServiceServer) mustEmbedUnimplementedAIServiceServer() {}
 func (UnimplementedAIServiceServer) testEmbeddedByValue()                   {}
 
@@ -214,6 +232,24 @@ func _AIService_VisualQuestionAnswering_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AIService_SimplifyText_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SimplifyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AIServiceServer).SimplifyText(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AIService_SimplifyText_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AIServiceServer).SimplifyText(ctx, req.(*SimplifyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AIService_ServiceDesc is the grpc.ServiceDesc for AIService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -237,6 +273,10 @@ var AIService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "VisualQuestionAnswering",
 			Handler:    _AIService_VisualQuestionAnswering_Handler,
 		},
+		{
+			MethodName: "SimplifyText",
+			Handler:    _AIService_SimplifyText_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/ai_service.proto",
@@ -0,0 +1,247 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/goccy/go-json"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	pb "temandifa-backend/internal/grpc/mobileapi"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/metrics"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/services"
+)
+
+// aiServer implements pb.AIServiceServer like handlers.AIProxyHandler
+// behind the email verification, kill switch, quota, usage and timeout
+// middleware of its routes
+type aiServer struct {
+	pb.UnimplementedAIServiceServer
+
+	ai           services.AIService
+	narration    services.NarrationService
+	plans        services.PlanService
+	killSwitches services.KillSwitchService
+	usage        services.UsageService
+	events       services.EventService
+	preferences  services.PreferencesService
+	cfg          *config.AIConfig
+	authCfg      *config.AuthConfig
+	uploads      *config.UploadConfig
+}
+
+// aiCall is one AI service call of an operation
+type aiCall func(ctx context.Context, content []byte, filename string) (interface{}, bool, error)
+
+func (s *aiServer) DetectObjects(ctx context.Context, req *pb.ImageRequest) (*pb.DetectionResult, error) {
+	result := &pb.DetectionResult{}
+	fromCache, err := s.run(ctx, config.UploadOpDetect, "detection", "image", req.GetImageData(), req.GetFilename(), s.ai.DetectObjects, result)
+	if err != nil {
+		return nil, err
+	}
+	result.FromCache = fromCache
+	return result, nil
+}
+
+func (s *aiServer) ExtractText(ctx context.Context, req *pb.OCRRequest) (*pb.OCRResult, error) {
+	lang := req.GetLanguage()
+	if lang == "" {
+		lang = s.preferences.OCRLanguage(ctx, userFromContext(ctx).ID)
+	}
+	call := func(ctx context.Context, content []byte, filename string) (interface{}, bool, error) {
+		return s.ai.ExtractText(ctx, content, filename, lang)
+	}
+
+	result := &pb.OCRResult{}
+	fromCache, err := s.run(ctx, config.UploadOpOCR, "ocr", "image", req.GetImageData(), req.GetFilename(), call, result)
+	if err != nil {
+		return nil, err
+	}
+	result.FromCache = fromCache
+	return result, nil
+}
+
+func (s *aiServer) TranscribeAudio(ctx context.Context, req *pb.AudioRequest) (*pb.TranscriptionResult, error) {
+	result := &pb.TranscriptionResult{}
+	fromCache, err := s.run(ctx, config.UploadOpTranscribe, "transcription", "audio", req.GetAudioData(), req.GetFilename(), s.ai.TranscribeAudio, result)
+	if err != nil {
+		return nil, err
+	}
+	result.FromCache = fromCache
+	return result, nil
+}
+
+func (s *aiServer) AskQuestion(ctx context.Context, req *pb.AskRequest) (*pb.AskResult, error) {
+	question := req.GetQuestion()
+	if question == "" {
+		return nil, withReason(codes.InvalidArgument, apperrors.ErrCodeValidation, "Question is required")
+	}
+	call := func(ctx context.Context, content []byte, filename string) (interface{}, bool, error) {
+		return s.ai.VisualQuestionAnswering(ctx, content, filename, question)
+	}
+
+	result := &pb.AskResult{}
+	fromCache, err := s.run(ctx, config.UploadOpAsk, "vqa", "image", req.GetImageData(), req.GetFilename(), call, result)
+	if err != nil {
+		return nil, err
+	}
+	result.FromCache = fromCache
+	return result, nil
+}
+
+func (s *aiServer) Narrate(stream pb.AIService_NarrateServer) error {
+	ctx := stream.Context()
+	user := userFromContext(ctx)
+	if err := s.requireVerifiedEmail(user); err != nil {
+		return err
+	}
+	s.narration.Serve(ctx, user, &narrationStream{stream: stream})
+	return nil
+}
+
+// requireVerifiedEmail turns away users who haven't verified their email
+// address when EMAIL_VERIFICATION_REQUIRED is set
+func (s *aiServer) requireVerifiedEmail(user models.User) error {
+	if !s.authCfg.EmailVerificationRequired || user.EmailVerifiedAt != nil {
+		return nil
+	}
+	return withReason(codes.PermissionDenied, apperrors.ErrCodeEmailNotVerified, "Please verify your email address first")
+}
+
+// run validates the upload, applies the operation's kill switch, quota and
+// timeout, calls the AI service and decodes its result into out, whose
+// fields share the AI service's JSON names
+func (s *aiServer) run(ctx context.Context, operation, feature, fileType string, content []byte, filename string, call aiCall, out any) (bool, error) {
+	start := time.Now()
+	user := userFromContext(ctx)
+	if err := s.requireVerifiedEmail(user); err != nil {
+		return false, err
+	}
+
+	limit := s.uploads.For(operation)
+	upload, err := helpers.ValidateUploadContent(ctx, content, filename, limit.MaxSize, limit.AllowedTypes, fileType)
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			return false, appStatus(appErr)
+		}
+		return false, withReason(codes.InvalidArgument, apperrors.ErrCodeValidation, err.Error())
+	}
+
+	if sw := s.killSwitches.Disabled(operation); sw != nil {
+		message := "This feature is temporarily disabled"
+		if sw.Reason != "" {
+			message = sw.Reason
+		}
+		return false, withReason(codes.Unavailable, apperrors.ErrCodeFeatureDisabled, message)
+	}
+
+	allowance, allowed, err := s.plans.ConsumeQuota(ctx, &user)
+	if err != nil {
+		logger.Ctx(ctx).Warn("Failed to count AI quota", zap.Error(err))
+	} else if !allowed {
+		logger.Ctx(ctx).Info("Daily AI quota exceeded",
+			zap.String("plan", allowance.Plan),
+			zap.Int("quota", allowance.DailyAIQuota),
+		)
+		return false, withReason(codes.ResourceExhausted, apperrors.ErrCodeQuotaExceeded, "Daily AI quota used up")
+	}
+
+	opCtx, cancel := context.WithTimeout(ctx, s.timeout(operation))
+	defer cancel()
+
+	result, fromCache, err := call(opCtx, upload.Content, upload.Filename)
+	s.usage.Record(user.ID, operation, fromCache, err != nil)
+	if err != nil {
+		if errors.Is(opCtx.Err(), context.DeadlineExceeded) {
+			return false, withReason(codes.DeadlineExceeded, apperrors.ErrCodeTimeout, "Request timed out")
+		}
+		logger.Ctx(ctx).Error("AI Service error", zap.String("service", feature), zap.Error(err))
+		return false, aiStatus(err)
+	}
+
+	data, err := json.Marshal(result)
+	if err == nil {
+		err = json.Unmarshal(data, out)
+	}
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to convert AI result", zap.String("service", feature), zap.Error(err))
+		return false, withReason(codes.Internal, apperrors.ErrCodeInternal, "Failed to process AI result")
+	}
+
+	logger.Ctx(ctx).Info("AI request completed",
+		zap.String("service", feature),
+		zap.Duration("latency", time.Since(start)),
+		zap.Bool("from_cache", fromCache),
+	)
+	metrics.RecordAIRequest(feature, time.Since(start).Seconds(), "success", fromCache)
+	s.events.Emit(ctx, services.Event{
+		Type: services.EventAIRequestCompleted,
+		Properties: map[string]any{
+			"feature":    feature,
+			"latency_ms": time.Since(start).Milliseconds(),
+			"cache_hit":  fromCache,
+		},
+	})
+	return fromCache, nil
+}
+
+// timeout returns the AI_*_TIMEOUT of an operation
+func (s *aiServer) timeout(operation string) time.Duration {
+	switch operation {
+	case config.UploadOpOCR:
+		return s.cfg.AIOCRTimeout
+	case config.UploadOpTranscribe:
+		return s.cfg.AITranscribeTimeout
+	case config.UploadOpAsk:
+		return s.cfg.AIVQATimeout
+	default:
+		return s.cfg.AIDetectTimeout
+	}
+}
+
+// narrationStream adapts a Narrate call to services.NarrationStream; every
+// request carries the settings for its frame
+type narrationStream struct {
+	stream pb.AIService_NarrateServer
+}
+
+func (n *narrationStream) Receive() (services.NarrationInput, error) {
+	req, err := n.stream.Recv()
+	if err != nil {
+		return services.NarrationInput{}, err
+	}
+	readText := !req.GetSkipText()
+	return services.NarrationInput{
+		Config: &dto.NarrationConfig{Language: req.GetLanguage(), Text: &readText},
+		Frame:  req.GetFrame(),
+	}, nil
+}
+
+func (n *narrationStream) Send(event dto.NarrationEvent) error {
+	objects := make([]*pb.NarrationObject, len(event.Objects))
+	for i, object := range event.Objects {
+		objects[i] = &pb.NarrationObject{Label: object.Label, Confidence: object.Confidence}
+	}
+	return n.stream.Send(&pb.NarrationEvent{
+		Type:               event.Type,
+		Priority:           event.Priority,
+		Frame:              int32(event.Frame),
+		Objects:            objects,
+		Text:               event.Text,
+		Code:               event.Code,
+		Message:            event.Message,
+		RetryAfterMs:       event.RetryAfterMs,
+		MinFrameIntervalMs: event.MinFrameIntervalMs,
+		MaxFrames:          int32(event.MaxFrames),
+		MaxDurationSeconds: event.MaxDurationSeconds,
+		MaxFrameBytes:      event.MaxFrameBytes,
+	})
+}
@@ -0,0 +1,138 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	pb "temandifa-backend/internal/grpc/mobileapi"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/services"
+)
+
+// authServer implements pb.AuthServiceServer like handlers.AuthHandler
+type authServer struct {
+	pb.UnimplementedAuthServiceServer
+
+	auth      services.AuthService
+	tokens    services.TokenService
+	blacklist *services.TokenBlacklist
+	events    services.EventService
+}
+
+func (s *authServer) Register(ctx context.Context, req *pb.RegisterRequest) (*pb.User, error) {
+	input := dto.RegisterRequest{Email: req.GetEmail(), Password: req.GetPassword(), FullName: req.GetFullName()}
+	if err := validate(&input); err != nil {
+		return nil, err
+	}
+
+	user, err := s.auth.Register(ctx, input)
+	if err != nil {
+		if errors.Is(err, apperrors.AlreadyExists("email")) {
+			logger.Ctx(ctx).Warn("Registration failed - email may exist", zap.String("email", input.Email))
+		} else if _, ok := apperrors.AsAppError(err); !ok {
+			logger.Ctx(ctx).Error("Failed to register user", zap.Error(err))
+		}
+		return nil, toStatus(err, "Failed to process registration")
+	}
+
+	logger.Ctx(ctx).Info("User registered successfully", zap.Uint("user_id", user.ID), zap.String("email", user.Email))
+	return userMessage(user), nil
+}
+
+func (s *authServer) Login(ctx context.Context, req *pb.LoginRequest) (*pb.TokenPair, error) {
+	input := dto.LoginRequest{Email: req.GetEmail(), Password: req.GetPassword()}
+	if err := validate(&input); err != nil {
+		return nil, err
+	}
+
+	userAgent, ipAddress, deviceID := clientInfo(ctx)
+	login, err := s.auth.Login(ctx, input, userAgent, ipAddress, deviceID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrInvalidCredentials) {
+			logger.Ctx(ctx).Debug("Login failed - invalid credentials", zap.String("email", input.Email))
+		} else if _, ok := apperrors.AsAppError(err); !ok {
+			logger.Ctx(ctx).Error("Login failed", zap.Error(err))
+		}
+		return nil, toStatus(err, "Login failed")
+	}
+
+	logger.Ctx(ctx).Info("User logged in successfully", zap.Uint("user_id", login.User.ID), zap.String("email", login.User.Email))
+	s.events.Emit(ctx, services.Event{
+		Type:       services.EventLogin,
+		UserID:     login.User.ID,
+		Properties: map[string]any{"method": "password", "transport": "grpc"},
+	})
+
+	return &pb.TokenPair{
+		AccessToken:  login.AccessToken,
+		RefreshToken: login.RefreshToken,
+		ExpiresAt:    login.ExpiresAt.Unix(),
+		TokenType:    login.TokenType,
+		User: &pb.User{
+			Id:       uint64(login.User.ID),
+			FullName: login.User.FullName,
+			Email:    login.User.Email,
+			Role:     login.User.Role,
+		},
+	}, nil
+}
+
+func (s *authServer) Refresh(ctx context.Context, req *pb.RefreshRequest) (*pb.TokenPair, error) {
+	if req.GetRefreshToken() == "" {
+		return nil, withReason(codes.InvalidArgument, apperrors.ErrCodeValidation, "Refresh token is required")
+	}
+
+	userAgent, ipAddress, deviceID := clientInfo(ctx)
+	pair, err := s.tokens.RefreshAccessToken(ctx, req.GetRefreshToken(), userAgent, ipAddress, deviceID)
+	if err != nil {
+		logger.Ctx(ctx).Debug("Token refresh failed", zap.Error(err))
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			return nil, appStatus(appErr)
+		}
+		return nil, withReason(codes.Unauthenticated, apperrors.ErrCodeTokenExpired, "Token refresh failed")
+	}
+
+	return &pb.TokenPair{
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		ExpiresAt:    pair.ExpiresAt.Unix(),
+		TokenType:    pair.TokenType,
+	}, nil
+}
+
+func (s *authServer) Logout(ctx context.Context, req *pb.LogoutRequest) (*pb.Empty, error) {
+	if req.GetRefreshToken() == "" {
+		return nil, withReason(codes.InvalidArgument, apperrors.ErrCodeValidation, "Refresh token is required")
+	}
+
+	if err := s.tokens.RevokeRefreshToken(req.GetRefreshToken()); err != nil {
+		logger.Ctx(ctx).Debug("Logout failed - refresh token not found", zap.Error(err))
+	}
+
+	// Blacklist the access token to prevent reuse
+	if accessToken := bearerToken(ctx); accessToken != "" && s.blacklist != nil {
+		if err := s.blacklist.Add(ctx, accessToken, time.Hour); err != nil {
+			logger.Ctx(ctx).Warn("Failed to blacklist access token", zap.Error(err))
+		}
+	}
+
+	logger.Ctx(ctx).Debug("User logged out")
+	return &pb.Empty{}, nil
+}
+
+// userMessage converts a user to its public message
+func userMessage(user *models.User) *pb.User {
+	return &pb.User{
+		Id:       uint64(user.ID),
+		FullName: user.FullName,
+		Email:    user.Email,
+		Role:     user.Role,
+	}
+}
@@ -0,0 +1,84 @@
+package grpcapi
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/sony/gobreaker"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+)
+
+// errorDomain identifies this API in the google.rpc.ErrorInfo of errors
+const errorDomain = "temandifa.com"
+
+// statusCodes maps the HTTP status of app errors to gRPC codes
+var statusCodes = map[int]codes.Code{
+	http.StatusBadRequest:            codes.InvalidArgument,
+	http.StatusUnauthorized:          codes.Unauthenticated,
+	http.StatusForbidden:             codes.PermissionDenied,
+	http.StatusNotFound:              codes.NotFound,
+	http.StatusConflict:              codes.AlreadyExists,
+	http.StatusRequestEntityTooLarge: codes.InvalidArgument,
+	http.StatusUnsupportedMediaType:  codes.InvalidArgument,
+	http.StatusUnprocessableEntity:   codes.InvalidArgument,
+	http.StatusTooManyRequests:       codes.ResourceExhausted,
+	http.StatusBadGateway:            codes.Unavailable,
+	http.StatusServiceUnavailable:    codes.Unavailable,
+	http.StatusGatewayTimeout:        codes.DeadlineExceeded,
+}
+
+// appStatus converts an app error to a status whose ErrorInfo reason is the
+// REST error code, so clients handle both APIs alike
+func appStatus(appErr *apperrors.AppError) error {
+	code, ok := statusCodes[appErr.StatusCode]
+	if !ok {
+		code = codes.Internal
+	}
+	return withReason(code, appErr.Code, appErr.Message)
+}
+
+// withReason builds a status carrying the error code as ErrorInfo reason
+func withReason(code codes.Code, reason apperrors.ErrorCode, message string) error {
+	st := status.New(code, message)
+	if detailed, err := st.WithDetails(&errdetails.ErrorInfo{Reason: string(reason), Domain: errorDomain}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// toStatus converts a service error; errors that aren't app errors are
+// reported as internal without their text
+func toStatus(err error, message string) error {
+	if appErr, ok := apperrors.AsAppError(err); ok {
+		return appStatus(appErr)
+	}
+	return withReason(codes.Internal, apperrors.ErrCodeInternal, message)
+}
+
+// aiStatus converts an AI service failure like handleAIServiceError does for REST
+func aiStatus(err error) error {
+	if errors.Is(err, gobreaker.ErrOpenState) {
+		return withReason(codes.Unavailable, apperrors.ErrCodeAIServiceError, "AI Service is temporarily unavailable. Please try again in a few moments.")
+	}
+	if appErr, ok := apperrors.AsAppError(err); ok {
+		return appStatus(appErr)
+	}
+	return withReason(codes.Unavailable, apperrors.ErrCodeAIServiceError, "AI Service unavailable")
+}
+
+// validate checks a request against its binding tags like gin's binding,
+// reporting every failed rule in the message
+func validate(input any) error {
+	if err := binding.Validator.ValidateStruct(input); err != nil {
+		return withReason(codes.InvalidArgument, apperrors.ErrCodeValidation,
+			"Validation failed: "+strings.Join(helpers.FormatValidationError(err), "; "))
+	}
+	return nil
+}
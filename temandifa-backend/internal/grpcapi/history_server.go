@@ -0,0 +1,126 @@
+package grpcapi
+
+import (
+	"context"
+	"strconv"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	apperrors "temandifa-backend/internal/errors"
+	pb "temandifa-backend/internal/grpc/mobileapi"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/services"
+)
+
+// Pagination of ListHistory, like helpers.NewPagination
+const (
+	defaultHistoryLimit = 20
+	maxHistoryLimit     = 100
+)
+
+// historyInput carries the binding rules of handlers.CreateHistoryInput
+type historyInput struct {
+	FeatureType string `binding:"required,oneof=OBJECT OCR VOICE"`
+	InputSource string `binding:"max=500"`
+	ResultText  string `binding:"max=10000"`
+}
+
+// historyServer implements pb.HistoryServiceServer like handlers.HistoryHandler
+type historyServer struct {
+	pb.UnimplementedHistoryServiceServer
+
+	history services.HistoryService
+	events  services.EventService
+}
+
+func (s *historyServer) ListHistory(ctx context.Context, req *pb.ListHistoryRequest) (*pb.ListHistoryResponse, error) {
+	user := userFromContext(ctx)
+
+	page := int(req.GetPage())
+	if page < 1 {
+		page = 1
+	}
+	limit := int(req.GetLimit())
+	if limit < 1 {
+		limit = defaultHistoryLimit
+	}
+	limit = min(limit, maxHistoryLimit)
+
+	histories, total, err := s.history.GetUserHistory(ctx, user.ID, page, limit)
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to fetch history", zap.Error(err))
+		return nil, withReason(codes.Internal, apperrors.ErrCodeInternal, "Failed to fetch history")
+	}
+
+	entries := make([]*pb.HistoryEntry, len(histories))
+	for i := range histories {
+		entries[i] = historyMessage(&histories[i])
+	}
+	return &pb.ListHistoryResponse{Entries: entries, Total: total}, nil
+}
+
+func (s *historyServer) CreateHistory(ctx context.Context, req *pb.CreateHistoryRequest) (*pb.HistoryEntry, error) {
+	user := userFromContext(ctx)
+
+	input := historyInput{FeatureType: req.GetFeatureType(), InputSource: req.GetInputSource(), ResultText: req.GetResultText()}
+	if err := validate(&input); err != nil {
+		return nil, err
+	}
+
+	created, err := s.history.CreateHistory(ctx, models.History{
+		UserID:      user.ID,
+		FeatureType: models.FeatureType(input.FeatureType),
+		InputSource: input.InputSource,
+		ResultText:  input.ResultText,
+	})
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to save history", zap.Error(err))
+		return nil, withReason(codes.Internal, apperrors.ErrCodeInternal, "Failed to save history")
+	}
+
+	logger.Ctx(ctx).Info("History saved", zap.Uint("history_id", created.ID), zap.String("feature_type", input.FeatureType))
+	s.events.Emit(ctx, services.Event{
+		Type:   services.EventHistoryCreated,
+		UserID: user.ID,
+		Properties: map[string]any{
+			"history_id":   created.ID,
+			"feature_type": input.FeatureType,
+		},
+	})
+
+	return historyMessage(&created), nil
+}
+
+func (s *historyServer) DeleteHistory(ctx context.Context, req *pb.DeleteHistoryRequest) (*pb.Empty, error) {
+	user := userFromContext(ctx)
+	historyID := strconv.FormatUint(req.GetId(), 10)
+
+	found, err := s.history.DeleteHistory(ctx, user.ID, historyID)
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to delete history", zap.Error(err))
+		return nil, withReason(codes.Internal, apperrors.ErrCodeInternal, "Failed to delete history")
+	}
+	if !found {
+		return nil, withReason(codes.NotFound, apperrors.ErrCodeNotFound, "History item not found")
+	}
+
+	logger.Ctx(ctx).Info("History deleted", zap.String("history_id", historyID))
+	return &pb.Empty{}, nil
+}
+
+// historyMessage converts a history entry to its message
+func historyMessage(history *models.History) *pb.HistoryEntry {
+	entry := &pb.HistoryEntry{
+		Id:          uint64(history.ID),
+		FeatureType: string(history.FeatureType),
+		InputSource: history.InputSource,
+		ResultText:  history.ResultText,
+		CreatedAt:   history.CreatedAt.Unix(),
+	}
+	if history.ClientID != nil {
+		entry.ClientId = *history.ClientID
+	}
+	return entry
+}
@@ -0,0 +1,245 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"path"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	apperrors "temandifa-backend/internal/errors"
+	pb "temandifa-backend/internal/grpc/mobileapi"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/metrics"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+	"temandifa-backend/internal/services"
+)
+
+// maxRequestIDLength caps client-supplied request IDs, like the REST API
+const maxRequestIDLength = 128
+
+// publicMethods can be called without an access token
+var publicMethods = map[string]bool{
+	pb.AuthService_Register_FullMethodName: true,
+	pb.AuthService_Login_FullMethodName:    true,
+	pb.AuthService_Refresh_FullMethodName:  true,
+	pb.AuthService_Logout_FullMethodName:   true,
+}
+
+type userKey struct{}
+
+// userFromContext returns the caller authenticated by the interceptors
+func userFromContext(ctx context.Context) models.User {
+	user, _ := ctx.Value(userKey{}).(models.User)
+	return user
+}
+
+// interceptors give every call what the REST middleware gives requests: a
+// request ID and request-scoped logger, panic recovery, metrics, rate limits
+// and, except for public methods, an authenticated user
+type interceptors struct {
+	tokens    services.TokenService
+	users     repositories.UserRepository
+	userCache services.UserCacheService
+	blacklist *services.TokenBlacklist
+	limits    *rateLimiter
+	log       *zap.Logger
+}
+
+func (i *interceptors) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	ctx, log := i.begin(ctx, info.FullMethod)
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			err = i.recovered(log, r)
+		}
+		i.finish(log, info.FullMethod, start, err)
+	}()
+
+	if ctx, err = i.admit(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (i *interceptors) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	ctx, log := i.begin(ss.Context(), info.FullMethod)
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			err = i.recovered(log, r)
+		}
+		i.finish(log, info.FullMethod, start, err)
+	}()
+
+	if ctx, err = i.admit(ctx, info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
+
+// begin attaches the request ID (x-request-id metadata or a new one) and a
+// request-scoped logger to the call's context
+func (i *interceptors) begin(ctx context.Context, fullMethod string) (context.Context, *zap.Logger) {
+	requestID := metadataValue(ctx, "x-request-id")
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		requestID = fmt.Sprintf("%d-%04x", time.Now().UnixNano(), rand.Intn(0xFFFF))
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs("x-request-id", requestID))
+
+	log := i.log.With(zap.String("request_id", requestID), zap.String("grpc_method", path.Base(fullMethod)))
+	ctx = logger.ContextWithRequestID(ctx, requestID)
+	return logger.WithLogger(ctx, log), log
+}
+
+// finish records the call's latency and status code and logs failures
+func (i *interceptors) finish(log *zap.Logger, fullMethod string, start time.Time, err error) {
+	code := status.Code(err)
+	duration := time.Since(start)
+	metrics.GRPCServerDuration.WithLabelValues(path.Base(fullMethod), code.String()).Observe(duration.Seconds())
+
+	switch code {
+	case codes.OK:
+		log.Debug("gRPC call completed", zap.Duration("latency", duration))
+	case codes.Internal, codes.Unknown, codes.DataLoss:
+		log.Error("gRPC call failed", zap.String("code", code.String()), zap.Duration("latency", duration), zap.Error(err))
+	default:
+		log.Info("gRPC call failed", zap.String("code", code.String()), zap.Duration("latency", duration), zap.Error(err))
+	}
+}
+
+// recovered logs a handler panic and reports it as an internal error
+func (i *interceptors) recovered(log *zap.Logger, r any) error {
+	log.Error("Panic in gRPC handler", zap.Any("panic", r), zap.String("stack", string(debug.Stack())))
+	return withReason(codes.Internal, apperrors.ErrCodeInternal, "Internal server error")
+}
+
+// admit applies the general rate limit, then authenticates calls of methods
+// that aren't public and applies the AI rate limit to them
+func (i *interceptors) admit(ctx context.Context, fullMethod string) (context.Context, error) {
+	header, err := i.limits.general(ctx)
+	if err != nil {
+		return ctx, err
+	}
+	if !publicMethods[fullMethod] {
+		if ctx, err = i.authenticate(ctx); err != nil {
+			return ctx, err
+		}
+		// The AI limit's metadata replaces the general one's, as its headers
+		// do on REST routes
+		aiHeader, err := i.limits.ai(ctx, fullMethod)
+		if err != nil {
+			return ctx, err
+		}
+		if aiHeader != nil {
+			header = aiHeader
+		}
+	}
+	_ = grpc.SetHeader(ctx, header)
+	return ctx, nil
+}
+
+// authenticate validates the bearer token in the authorization metadata like
+// middleware.Auth and attaches the user to the context
+func (i *interceptors) authenticate(ctx context.Context) (context.Context, error) {
+	token := bearerToken(ctx)
+	if token == "" {
+		return ctx, withReason(codes.Unauthenticated, apperrors.ErrCodeUnauthorized, "Authorization metadata required")
+	}
+	if i.blacklist != nil && i.blacklist.IsBlacklisted(ctx, token) {
+		return ctx, withReason(codes.Unauthenticated, apperrors.ErrCodeTokenRevoked, "Token has been revoked")
+	}
+
+	claims, err := i.tokens.ValidateAccessToken(token)
+	if errors.Is(err, services.ErrTokenEpochRevoked) {
+		return ctx, withReason(codes.Unauthenticated, apperrors.ErrCodeTokenRevoked, "Token has been revoked")
+	}
+	if err != nil {
+		return ctx, withReason(codes.Unauthenticated, apperrors.ErrCodeTokenInvalid, "Invalid token")
+	}
+
+	var user *models.User
+	if i.userCache != nil {
+		if cached, err := i.userCache.GetCachedUser(ctx, claims.UserID); err == nil && cached != nil {
+			user = &models.User{
+				Email:             cached.Email,
+				FullName:          cached.FullName,
+				ProfilePicture:    cached.ProfilePicture,
+				PreferredLanguage: cached.PreferredLanguage,
+				Role:              cached.Role,
+				Plan:              cached.Plan,
+				EmailVerifiedAt:   cached.EmailVerifiedAt,
+			}
+			user.ID = cached.ID
+		}
+	}
+	if user == nil {
+		user, err = i.users.FindByID(ctx, claims.UserID)
+		if err != nil || user == nil {
+			return ctx, withReason(codes.Unauthenticated, apperrors.ErrCodeNotFound, "User not found")
+		}
+		if i.userCache != nil {
+			if err := i.userCache.SetCachedUser(ctx, user); err != nil {
+				logger.Ctx(ctx).Debug("Failed to cache user", zap.Error(err))
+			}
+		}
+	}
+
+	ctx = context.WithValue(ctx, userKey{}, *user)
+	ctx = logger.ContextWithUserID(ctx, user.ID)
+	ctx = logger.WithLogger(ctx, logger.Ctx(ctx).With(zap.Uint("user_id", user.ID)))
+	return services.ContextWithPlan(ctx, user.Plan), nil
+}
+
+// contextStream is a server stream with the interceptors' context
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// metadataValue returns the first value of an incoming metadata key
+func metadataValue(ctx context.Context, key string) string {
+	if values := metadata.ValueFromIncomingContext(ctx, key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// bearerToken returns the access token of the authorization metadata
+func bearerToken(ctx context.Context) string {
+	return strings.TrimPrefix(metadataValue(ctx, "authorization"), "Bearer ")
+}
+
+// clientInfo returns the caller's user agent and IP for session records, and
+// the device ID (x-device-id metadata, like X-Device-ID) refresh tokens are
+// bound to
+func clientInfo(ctx context.Context) (userAgent, ipAddress, deviceID string) {
+	userAgent = metadataValue(ctx, "user-agent")
+	if id := metadataValue(ctx, "x-device-id"); helpers.ValidDeviceID(id) {
+		deviceID = id
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ipAddress = p.Addr.String()
+		if host, _, err := net.SplitHostPort(ipAddress); err == nil {
+			ipAddress = host
+		}
+	}
+	return userAgent, ipAddress, deviceID
+}
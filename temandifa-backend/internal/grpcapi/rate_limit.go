@@ -0,0 +1,95 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+
+	"temandifa-backend/internal/config"
+	apperrors "temandifa-backend/internal/errors"
+	pb "temandifa-backend/internal/grpc/mobileapi"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/metrics"
+	"temandifa-backend/internal/services"
+)
+
+// rateLimiter applies the REST API's sliding window rate limiters to calls:
+// the general one per IP to every call, and the AI one per user, scaled by
+// plan and overrides, to the AI service. Calls count in the same Redis sets
+// as requests, so clients can't double their limits by switching APIs.
+type rateLimiter struct {
+	rdb       *redis.Client
+	dynamic   *config.Dynamic
+	plans     services.PlanService
+	overrides services.RateLimitOverrideService
+}
+
+// aiServicePrefix starts the full method names of the AI service
+var aiServicePrefix = "/" + pb.AIService_ServiceDesc.ServiceName + "/"
+
+// general limits the caller's IP like middleware.SlidingWindowRateLimiter
+func (l *rateLimiter) general(ctx context.Context) (metadata.MD, error) {
+	if l.rdb == nil {
+		return nil, nil // Redis not connected, skip rate limiting
+	}
+	_, ip, _ := clientInfo(ctx)
+	limit, window := l.dynamic.RateLimit()
+	return l.limit(ctx, "general", "ip", services.IPRateLimitKey(ip), "ip:"+ip, limit, window)
+}
+
+// ai limits the authenticated caller of AI service methods like
+// middleware.SlidingWindowRateLimiterByUser
+func (l *rateLimiter) ai(ctx context.Context, fullMethod string) (metadata.MD, error) {
+	if l.rdb == nil || !strings.HasPrefix(fullMethod, aiServicePrefix) {
+		return nil, nil
+	}
+	user := userFromContext(ctx)
+	limit, window := l.dynamic.AIRateLimit()
+	limit, exempt := l.overrides.Adjust(user.ID, l.plans.RateLimit(user.Plan, limit))
+	if exempt {
+		return nil, nil
+	}
+	return l.limit(ctx, "ai", "user", services.UserRateLimitKey(user.ID), fmt.Sprintf("user:%d", user.ID), limit, window)
+}
+
+// limit counts the call in key's window, returning the x-ratelimit-*
+// metadata of the REST headers. Calls over limit are rejected, with the
+// metadata sent.
+func (l *rateLimiter) limit(ctx context.Context, name, scope, key, identifier string, limit int, window time.Duration) (metadata.MD, error) {
+	now := time.Now()
+	count, err := services.CountSlidingWindow(ctx, l.rdb, key, window, now)
+	if err != nil {
+		logger.Ctx(ctx).Warn("Sliding rate limit Redis error", zap.Error(err), zap.String("identifier", identifier))
+		return nil, nil
+	}
+
+	remaining := max(int64(limit)-count, 0)
+	header := metadata.Pairs(
+		"x-ratelimit-limit", strconv.Itoa(limit),
+		"x-ratelimit-remaining", strconv.FormatInt(remaining, 10),
+		"x-ratelimit-reset", strconv.FormatInt(now.Add(window).Unix(), 10),
+	)
+
+	metrics.RecordRateLimit(name, scope, count, limit, count <= int64(limit))
+
+	if count > int64(limit) {
+		logger.Ctx(ctx).Warn("Sliding rate limit exceeded",
+			zap.String("limiter", name),
+			zap.String("identifier", identifier),
+			zap.Int64("count", count),
+			zap.Int("limit", limit),
+		)
+		header.Append("retry-after", strconv.Itoa(int(window.Seconds())))
+		_ = grpc.SetHeader(ctx, header)
+		return nil, withReason(codes.ResourceExhausted, apperrors.ErrCodeRateLimited, "Too many requests. Please try again later.")
+	}
+	return header, nil
+}
@@ -0,0 +1,150 @@
+// Package grpcapi serves the mobile app's gRPC API (proto/mobile_api.proto)
+// next to the REST API, on the same services.
+package grpcapi
+
+import (
+	"context"
+	"net"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"temandifa-backend/internal/config"
+	pb "temandifa-backend/internal/grpc/mobileapi"
+	"temandifa-backend/internal/repositories"
+	"temandifa-backend/internal/services"
+	"temandifa-backend/internal/tlscert"
+)
+
+var Module = fx.Options(
+	fx.Provide(NewServer),
+)
+
+// Server is the gRPC API server; it listens only when GRPC_PORT is set
+type Server struct {
+	server *grpc.Server
+	cfg    *config.ServerConfig
+	log    *zap.Logger
+}
+
+// NewServer creates the gRPC API server on the services the REST handlers
+// use. certs is the HTTP server's native TLS (TLS_MODE), nil when off.
+func NewServer(
+	cfg *config.ServerConfig,
+	aiCfg *config.AIConfig,
+	authCfg *config.AuthConfig,
+	uploads *config.UploadConfig,
+	certs *tlscert.Provider,
+	auth services.AuthService,
+	tokens services.TokenService,
+	blacklist *services.TokenBlacklist,
+	userCache services.UserCacheService,
+	users repositories.UserRepository,
+	history services.HistoryService,
+	ai services.AIService,
+	narration services.NarrationService,
+	plans services.PlanService,
+	killSwitches services.KillSwitchService,
+	usage services.UsageService,
+	events services.EventService,
+	preferences services.PreferencesService,
+	rdb *redis.Client,
+	dynamic *config.Dynamic,
+	rateLimitOverrides services.RateLimitOverrideService,
+	log *zap.Logger,
+) *Server {
+	i := &interceptors{
+		tokens:    tokens,
+		users:     users,
+		userCache: userCache,
+		blacklist: blacklist,
+		limits: &rateLimiter{
+			rdb:       rdb,
+			dynamic:   dynamic,
+			plans:     plans,
+			overrides: rateLimitOverrides,
+		},
+		log: log,
+	}
+	opts := []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(int(cfg.MaxBodySize)),
+		grpc.ChainUnaryInterceptor(i.unary),
+		grpc.ChainStreamInterceptor(i.stream),
+	}
+	if certs != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(certs.TLSConfig())))
+	}
+
+	server := grpc.NewServer(opts...)
+	pb.RegisterAuthServiceServer(server, &authServer{
+		auth:      auth,
+		tokens:    tokens,
+		blacklist: blacklist,
+		events:    events,
+	})
+	pb.RegisterHistoryServiceServer(server, &historyServer{
+		history: history,
+		events:  events,
+	})
+	pb.RegisterAIServiceServer(server, &aiServer{
+		ai:           ai,
+		narration:    narration,
+		plans:        plans,
+		killSwitches: killSwitches,
+		usage:        usage,
+		events:       events,
+		preferences:  preferences,
+		cfg:          aiCfg,
+		authCfg:      authCfg,
+		uploads:      uploads,
+	})
+
+	return &Server{server: server, cfg: cfg, log: log}
+}
+
+// Start serves the gRPC API on GRPC_PORT. On shutdown in-flight calls get
+// SHUTDOWN_TIMEOUT to finish; narration streams are ended by
+// services.RegisterNarrationService, which must be invoked after it.
+func Start(lc fx.Lifecycle, s *Server) {
+	if s.cfg.GRPCPort == "" {
+		return
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			listener, err := net.Listen("tcp", ":"+s.cfg.GRPCPort)
+			if err != nil {
+				return err
+			}
+			s.log.Info("gRPC API starting", zap.String("port", s.cfg.GRPCPort))
+
+			go func() {
+				if err := s.server.Serve(listener); err != nil {
+					s.log.Fatal("Failed to serve gRPC API", zap.Error(err))
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			stopped := make(chan struct{})
+			go func() {
+				s.server.GracefulStop()
+				close(stopped)
+			}()
+
+			shutdownCtx, cancel := context.WithTimeout(ctx, s.cfg.ShutdownTimeout)
+			defer cancel()
+			select {
+			case <-stopped:
+				s.log.Info("gRPC API stopped")
+			case <-shutdownCtx.Done():
+				s.log.Error("gRPC API forced to stop")
+				s.server.Stop()
+			}
+			return nil
+		},
+	})
+}
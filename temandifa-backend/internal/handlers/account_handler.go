@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/middleware"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// AccountHandler lets users change their password and delete their account
+type AccountHandler struct {
+	accounts services.AccountService
+}
+
+func NewAccountHandler(accounts services.AccountService) *AccountHandler {
+	return &AccountHandler{accounts: accounts}
+}
+
+// ChangePassword godoc
+//
+//	@Summary		Change my password
+//	@Description	Replace the caller's password, confirming with the current one. Every other session is signed out and its access token revoked; the caller's stays signed in. Accounts without a password (Google, Apple or sign-in link only) set one with /forgot-password instead.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			input	body		dto.ChangePasswordRequest	true	"Current and new password"
+//	@Success		200		{object}	response.SuccessResponse
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed, weak or unchanged password, or no password set"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized or wrong current password"
+//	@Router			/auth/change-password [post]
+func (h *AccountHandler) ChangePassword(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	var input dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	err := h.accounts.ChangePassword(c.Request.Context(), user.ID, middleware.SessionID(c), input.CurrentPassword, input.NewPassword)
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			apperrors.RespondError(c, appErr)
+			return
+		}
+		logger.Ctx(c).Error("Failed to change password", zap.Uint("user_id", user.ID), zap.Error(err))
+		response.InternalError(c, "Failed to change password")
+		return
+	}
+
+	response.Success(c, nil, "Password changed")
+}
+
+// DeleteAccount godoc
+//
+//	@Summary		Delete my account
+//	@Description	Delete the caller's account (right to erasure). Accounts with a password must confirm with it. Every session is signed out, the current access token included, and history is deleted right away; the account and the rest of its data are purged after USER_PURGE_GRACE_PERIOD (purge_after). Webhook subscribers get a user.deleted event.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			input	body		dto.DeleteAccountRequest	false	"Password confirmation"
+//	@Success		200		{object}	response.SuccessResponse{data=dto.AccountDeletion}
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized or wrong password"
+//	@Router			/account [delete]
+func (h *AccountHandler) DeleteAccount(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	// The body is optional for accounts without a password
+	var input dto.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	accessToken, _ := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	deletion, err := h.accounts.Delete(c.Request.Context(), user.ID, input.Password, accessToken)
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			apperrors.RespondError(c, appErr)
+			return
+		}
+		logger.Ctx(c).Error("Failed to delete account", zap.Uint("user_id", user.ID), zap.Error(err))
+		response.InternalError(c, "Failed to delete account")
+		return
+	}
+
+	response.Success(c, deletion, "Account deleted")
+}
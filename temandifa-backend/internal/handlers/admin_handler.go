@@ -0,0 +1,334 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// maxPurgePreviewLimit bounds how many purge candidates are listed at once
+const maxPurgePreviewLimit = 500
+
+// AdminHandler serves user-management operations for administrators
+type AdminHandler struct {
+	userAdmin          services.UserAdminService
+	purgeService       services.UserPurgeService
+	rateLimitOverrides services.RateLimitOverrideService
+	sessions           services.SessionAdminService
+}
+
+func NewAdminHandler(userAdmin services.UserAdminService, purgeService services.UserPurgeService, rateLimitOverrides services.RateLimitOverrideService, sessions services.SessionAdminService) *AdminHandler {
+	return &AdminHandler{
+		userAdmin:          userAdmin,
+		purgeService:       purgeService,
+		rateLimitOverrides: rateLimitOverrides,
+		sessions:           sessions,
+	}
+}
+
+// SearchUsers godoc
+//
+//	@Summary		Search users
+//	@Description	Paginated user search by email or name, filterable by role and status
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			q		query		string	false	"Substring of email or full name"
+//	@Param			role	query		string	false	"Role"	Enums(user, admin)
+//	@Param			status	query		string	false	"Account status"	Enums(active, deleted, all)	default(active)
+//	@Param			sort	query		string	false	"Sort fields (id, email, full_name, role, created_at, deleted_at), prefix - for descending"	default(-created_at)
+//	@Param			page	query		int		false	"Page number"	default(1)
+//	@Param			limit	query		int		false	"Items per page (max 100)"	default(20)
+//	@Success		200		{object}	response.SuccessResponse{data=[]models.User,meta=helpers.Pagination}
+//	@Failure		400		{object}	response.ErrorResponse	"Invalid filters"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/users [get]
+func (h *AdminHandler) SearchUsers(c *gin.Context) {
+	var query dto.UserSearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	pagination := helpers.NewPagination(c)
+
+	users, total, err := h.userAdmin.SearchUsers(c.Request.Context(), query, pagination.Page, pagination.Limit)
+	if err != nil {
+		logger.Ctx(c).Error("Failed to search users", zap.Error(err))
+		response.InternalError(c, "Failed to search users")
+		return
+	}
+
+	pagination.SetTotal(total)
+	response.SuccessWithMeta(c, users, pagination.ToMeta())
+}
+
+// PreviewUserPurge godoc
+//
+//	@Summary		Preview user purge
+//	@Description	Dry run: list soft-deleted users past the grace period that the next purge would hard-delete, with counts of their owned data
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			limit	query		int	false	"Max candidates (default 100, max 500)"
+//	@Success		200		{object}	dto.UserPurgePreview
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/users/purge-preview [get]
+func (h *AdminHandler) PreviewUserPurge(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit < 1 {
+		limit = 100
+	}
+	if limit > maxPurgePreviewLimit {
+		limit = maxPurgePreviewLimit
+	}
+
+	preview, err := h.purgeService.Preview(c.Request.Context(), limit)
+	if err != nil {
+		logger.Ctx(c).Error("Failed to preview user purge", zap.Error(err))
+		response.InternalError(c, "Failed to preview user purge")
+		return
+	}
+	response.Success(c, preview)
+}
+
+// ListRateLimitOverrides godoc
+//
+//	@Summary		List rate limit overrides
+//	@Description	Per-user multipliers and exemptions of the AI rate limit, expired ones included
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=[]models.RateLimitOverride}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/rate-limit-overrides [get]
+func (h *AdminHandler) ListRateLimitOverrides(c *gin.Context) {
+	overrides, err := h.rateLimitOverrides.List(c.Request.Context())
+	if err != nil {
+		logger.Ctx(c).Error("Failed to list rate limit overrides", zap.Error(err))
+		response.InternalError(c, "Failed to list rate limit overrides")
+		return
+	}
+	response.Success(c, overrides)
+}
+
+// SetRateLimitOverride godoc
+//
+//	@Summary		Override a user's rate limit
+//	@Description	Multiply the user's AI rate limit or exempt the user from it, optionally until expires_at
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int									true	"User ID"
+//	@Param			request	body		dto.SetRateLimitOverrideRequest	true	"Override"
+//	@Success		200		{object}	response.SuccessResponse{data=models.RateLimitOverride}
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404		{object}	response.ErrorResponse	"User not found"
+//	@Router			/admin/users/{id}/rate-limit [put]
+func (h *AdminHandler) SetRateLimitOverride(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.NotFound(c, "User")
+		return
+	}
+
+	var input dto.SetRateLimitOverrideRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	override := models.RateLimitOverride{
+		UserID:     uint(userID),
+		Multiplier: input.Multiplier,
+		Exempt:     input.Exempt,
+		Reason:     input.Reason,
+		ExpiresAt:  input.ExpiresAt,
+	}
+	if override.Multiplier == 0 {
+		override.Multiplier = 1
+	}
+
+	found, err := h.rateLimitOverrides.Set(c.Request.Context(), &override)
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			apperrors.RespondError(c, appErr)
+			return
+		}
+		logger.Ctx(c).Error("Failed to save rate limit override", zap.Error(err))
+		response.InternalError(c, "Failed to save rate limit override")
+		return
+	}
+	if !found {
+		response.NotFound(c, "User")
+		return
+	}
+
+	logger.Ctx(c).Info("Rate limit override saved",
+		zap.Uint("target_user_id", override.UserID),
+		zap.Float64("multiplier", override.Multiplier),
+		zap.Bool("exempt", override.Exempt),
+		zap.String("reason", override.Reason),
+	)
+	response.Success(c, override, "Rate limit override saved")
+}
+
+// DeleteRateLimitOverride godoc
+//
+//	@Summary		Remove a user's rate limit override
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"User ID"
+//	@Success		200	{object}	response.SuccessResponse
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404	{object}	response.ErrorResponse	"Override not found"
+//	@Router			/admin/users/{id}/rate-limit [delete]
+func (h *AdminHandler) DeleteRateLimitOverride(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.NotFound(c, "Override")
+		return
+	}
+
+	found, err := h.rateLimitOverrides.Delete(c.Request.Context(), uint(userID))
+	if err != nil {
+		logger.Ctx(c).Error("Failed to delete rate limit override", zap.Error(err))
+		response.InternalError(c, "Failed to delete rate limit override")
+		return
+	}
+	if !found {
+		response.NotFound(c, "Override")
+		return
+	}
+
+	logger.Ctx(c).Info("Rate limit override removed", zap.Uint64("target_user_id", userID))
+	response.Success(c, nil, "Rate limit override removed")
+}
+
+// ListSessions godoc
+//
+//	@Summary		List active sessions
+//	@Description	Active login sessions (unrevoked, unexpired refresh tokens) across all users, newest first
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			user_id			query		int		false	"User ID"
+//	@Param			ip				query		string	false	"IP address or prefix"
+//	@Param			user_agent		query		string	false	"Substring of the user agent"
+//	@Param			created_after	query		string	false	"RFC 3339 time"
+//	@Param			created_before	query		string	false	"RFC 3339 time"
+//	@Param			page			query		int		false	"Page number"	default(1)
+//	@Param			limit			query		int		false	"Items per page (max 100)"	default(20)
+//	@Success		200				{object}	response.SuccessResponse{data=[]dto.AdminSession,meta=helpers.Pagination}
+//	@Failure		400				{object}	response.ErrorResponse	"Invalid filters"
+//	@Failure		401				{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403				{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/sessions [get]
+func (h *AdminHandler) ListSessions(c *gin.Context) {
+	var filter dto.SessionFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	pagination := helpers.NewPagination(c)
+
+	sessions, total, err := h.sessions.ListSessions(c.Request.Context(), filter, pagination.Page, pagination.Limit)
+	if err != nil {
+		logger.Ctx(c).Error("Failed to list sessions", zap.Error(err))
+		response.InternalError(c, "Failed to list sessions")
+		return
+	}
+
+	pagination.SetTotal(total)
+	response.SuccessWithMeta(c, sessions, pagination.ToMeta())
+}
+
+// RevokeSessions godoc
+//
+//	@Summary		Revoke sessions in bulk
+//	@Description	Revoke the listed sessions and/or every active session matching the filters (same as GET /admin/sessions). At least one criterion is required. Access tokens already issued stay valid until they expire.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.RevokeSessionsRequest	true	"Sessions to revoke"
+//	@Success		200		{object}	response.SuccessResponse{data=dto.RevokeSessionsResponse}
+//	@Failure		400		{object}	response.ErrorResponse	"No criteria or invalid filters"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/sessions/revoke [post]
+func (h *AdminHandler) RevokeSessions(c *gin.Context) {
+	var input dto.RevokeSessionsRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	revoked, err := h.sessions.RevokeSessions(c.Request.Context(), input)
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			apperrors.RespondError(c, appErr)
+			return
+		}
+		logger.Ctx(c).Error("Failed to revoke sessions", zap.Error(err))
+		response.InternalError(c, "Failed to revoke sessions")
+		return
+	}
+
+	response.Success(c, dto.RevokeSessionsResponse{Revoked: revoked}, "Sessions revoked")
+}
+
+// ForceLogoutAll godoc
+//
+//	@Summary		Log every user out
+//	@Description	Bump the token epoch so every access token issued so far is rejected (TOKEN_REVOKED), and revoke every refresh token. Use after a confirmed JWT secret leak, together with rotating JWT_SECRET: a leaked secret can still sign new tokens.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.ForceLogoutRequest	true	"Reason"
+//	@Success		200		{object}	response.SuccessResponse{data=dto.ForceLogoutResponse}
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/force-logout [post]
+func (h *AdminHandler) ForceLogoutAll(c *gin.Context) {
+	var input dto.ForceLogoutRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	result, err := h.sessions.ForceLogoutAll(c.Request.Context(), input.Reason)
+	if err != nil {
+		logger.Ctx(c).Error("Failed to force logout", zap.Error(err))
+		response.InternalError(c, "Failed to force logout")
+		return
+	}
+
+	response.Success(c, result, "All users logged out")
+}
@@ -5,28 +5,51 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/goccy/go-json"
 	"github.com/sony/gobreaker"
 	"go.uber.org/zap"
 
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
 	"temandifa-backend/internal/helpers"
 	"temandifa-backend/internal/logger"
 	"temandifa-backend/internal/metrics"
+	"temandifa-backend/internal/models"
 	"temandifa-backend/internal/response"
 	"temandifa-backend/internal/services"
 )
 
 // AIProxyHandler handles requests that need to be forwarded to the Python AI Service via gRPC
 type AIProxyHandler struct {
-	aiService services.AIService
+	aiService   services.AIService
+	events      services.EventService
+	uploads     *config.UploadConfig
+	resumable   services.UploadService
+	preferences services.PreferencesService
 }
 
-func NewAIProxyHandler(aiService services.AIService) *AIProxyHandler {
+func NewAIProxyHandler(aiService services.AIService, events services.EventService, uploads *config.UploadConfig, resumable services.UploadService, preferences services.PreferencesService) *AIProxyHandler {
 	return &AIProxyHandler{
-		aiService: aiService,
+		aiService:   aiService,
+		events:      events,
+		uploads:     uploads,
+		resumable:   resumable,
+		preferences: preferences,
 	}
 }
 
+// emitCompleted records a successful AI request in the analytics event pipeline
+func (h *AIProxyHandler) emitCompleted(c *gin.Context, feature string, start time.Time, fromCache bool) {
+	h.events.Emit(c.Request.Context(), services.Event{
+		Type: services.EventAIRequestCompleted,
+		Properties: map[string]any{
+			"feature":    feature,
+			"latency_ms": time.Since(start).Milliseconds(),
+			"cache_hit":  fromCache,
+		},
+	})
+}
+
 // handleAIServiceError provides consistent error handling for AI Service failures
 // with graceful degradation support (Retry-After headers, circuit breaker info)
 func handleAIServiceError(c *gin.Context, err error, serviceName string) {
@@ -42,7 +65,7 @@ func handleAIServiceError(c *gin.Context, err error, serviceName string) {
 				"retry_after": "30s",
 				"fallback":    true,
 			})
-		logger.Warn("AI Service circuit breaker open",
+		logger.Ctx(c).Warn("AI Service circuit breaker open",
 			zap.String("service", serviceName),
 		)
 		return
@@ -56,7 +79,7 @@ func handleAIServiceError(c *gin.Context, err error, serviceName string) {
 			"service": serviceName,
 			"error":   err.Error(),
 		})
-	logger.Error("AI Service error",
+	logger.Ctx(c).Error("AI Service error",
 		zap.String("service", serviceName),
 		zap.Error(err),
 	)
@@ -70,9 +93,11 @@ func handleAIServiceError(c *gin.Context, err error, serviceName string) {
 //	@Accept			multipart/form-data
 //	@Produce		json
 //	@Security		BearerAuth
+//	@Security		ApiKeyAuth
 //	@Param			file	formData	file				true	"Image file (jpg, png, webp)"
 //	@Success		200		{object}	map[string]interface{}	"Detection results"
 //	@Failure		400		{object}	response.ErrorResponse	"No file uploaded"
+//	@Failure		422		{object}	response.ErrorResponse	"Malware detected (MALWARE_SCAN_DRIVER)"
 //	@Failure		502		{object}	response.ErrorResponse	"AI Service unavailable"
 //	@Router			/detect [post]
 func (h *AIProxyHandler) DetectObjects(c *gin.Context) {
@@ -80,20 +105,21 @@ func (h *AIProxyHandler) DetectObjects(c *gin.Context) {
 
 	file, header, err := c.Request.FormFile("file")
 	if err != nil {
-		logger.Debug("Detection request missing file", zap.Error(err))
+		logger.Ctx(c).Debug("Detection request missing file", zap.Error(err))
 		response.BadRequest(c, "No file uploaded")
 		return
 	}
 	defer func() { _ = file.Close() }()
 
 	// Validate and read file
-	uploadedFile, err := helpers.ValidateImageUpload(header, file)
+	limit := h.uploads.For(config.UploadOpDetect)
+	uploadedFile, err := helpers.ValidateImageUpload(c.Request.Context(), header, file, limit.MaxSize, limit.AllowedTypes)
 	if err != nil {
-		response.BadRequest(c, err.Error())
+		respondInvalidUpload(c, err)
 		return
 	}
 
-	logger.Debug("Processing detection request",
+	logger.Ctx(c).Debug("Processing detection request",
 		zap.String("filename", uploadedFile.Filename),
 		zap.Int64("size", uploadedFile.Size),
 		zap.String("mime", uploadedFile.MimeType),
@@ -113,15 +139,9 @@ func (h *AIProxyHandler) DetectObjects(c *gin.Context) {
 	c.Header("X-Cache", cacheStatus)
 
 	// Fast JSON serialization
-	c.Header("Content-Type", "application/json")
-	jsonBytes, err := json.Marshal(result)
-	if err != nil {
-		c.JSON(http.StatusOK, result)
-	} else {
-		c.Data(http.StatusOK, "application/json", jsonBytes)
-	}
+	response.Render(c, http.StatusOK, result)
 
-	logger.InfoCtx(c.Request.Context(), "AI proxy request completed",
+	logger.Ctx(c).Info("AI proxy request completed",
 		zap.String("service", "detection"),
 		zap.Int("status", http.StatusOK),
 		zap.Duration("latency", time.Since(start)),
@@ -129,6 +149,7 @@ func (h *AIProxyHandler) DetectObjects(c *gin.Context) {
 	)
 
 	metrics.RecordAIRequest("detection", time.Since(start).Seconds(), "success", fromCache)
+	h.emitCompleted(c, "detection", start, fromCache)
 }
 
 // ExtractText godoc
@@ -139,8 +160,9 @@ func (h *AIProxyHandler) DetectObjects(c *gin.Context) {
 //	@Accept			multipart/form-data
 //	@Produce		json
 //	@Security		BearerAuth
+//	@Security		ApiKeyAuth
 //	@Param			file	formData	file				true	"Image file"
-//	@Param			lang	query		string				false	"Language: en, id, ch"	default(en)
+//	@Param			lang	query		string				false	"Language: en, id, ch (default: the user's ocr_language preference)"
 //	@Router			/ocr [post]
 func (h *AIProxyHandler) ExtractText(c *gin.Context) {
 	start := time.Now()
@@ -153,13 +175,18 @@ func (h *AIProxyHandler) ExtractText(c *gin.Context) {
 	defer func() { _ = file.Close() }()
 
 	// Validate and read file
-	uploadedFile, err := helpers.ValidateImageUpload(header, file)
+	limit := h.uploads.For(config.UploadOpOCR)
+	uploadedFile, err := helpers.ValidateImageUpload(c.Request.Context(), header, file, limit.MaxSize, limit.AllowedTypes)
 	if err != nil {
-		response.BadRequest(c, err.Error())
+		respondInvalidUpload(c, err)
 		return
 	}
 
-	lang := c.DefaultQuery("lang", "en")
+	lang := c.Query("lang")
+	if lang == "" {
+		user := c.MustGet("user").(models.User)
+		lang = h.preferences.OCRLanguage(c.Request.Context(), user.ID)
+	}
 
 	result, fromCache, err := h.aiService.ExtractText(c.Request.Context(), uploadedFile.Content, uploadedFile.Filename, lang)
 	if err != nil {
@@ -173,42 +200,30 @@ func (h *AIProxyHandler) ExtractText(c *gin.Context) {
 	}
 	c.Header("X-Cache", cacheStatus)
 
-	c.Header("Content-Type", "application/json")
-	jsonBytes, err := json.Marshal(result)
-	if err != nil {
-		c.JSON(http.StatusOK, result)
-	} else {
-		c.Data(http.StatusOK, "application/json", jsonBytes)
-	}
+	response.Render(c, http.StatusOK, result)
 
-	logger.InfoCtx(c.Request.Context(), "OCR request completed", zap.Duration("latency", time.Since(start)))
+	logger.Ctx(c).Info("OCR request completed", zap.Duration("latency", time.Since(start)))
 	metrics.RecordAIRequest("ocr", time.Since(start).Seconds(), "success", fromCache)
+	h.emitCompleted(c, "ocr", start, fromCache)
 }
 
 // TranscribeAudio godoc
 //
 //	@Summary		Transcribe audio to text
-//	@Description	Convert audio to text using Whisper via gRPC
+//	@Description	Convert audio to text using Whisper via gRPC. Send the file, or the upload_id of a completed resumable upload (POST /uploads) for long recordings.
 //	@Tags			AI
 //	@Accept			multipart/form-data
 //	@Produce		json
 //	@Security		BearerAuth
-//	@Param			file	formData	file				true	"Audio file"
+//	@Security		ApiKeyAuth
+//	@Param			file		formData	file				false	"Audio file"
+//	@Param			upload_id	formData	string				false	"ID of a completed resumable upload, instead of file"
 //	@Router			/transcribe [post]
 func (h *AIProxyHandler) TranscribeAudio(c *gin.Context) {
 	start := time.Now()
 
-	file, header, err := c.Request.FormFile("file")
-	if err != nil {
-		response.BadRequest(c, "No file uploaded")
-		return
-	}
-	defer func() { _ = file.Close() }()
-
-	// Validate and read file
-	uploadedFile, err := helpers.ValidateAudioUpload(header, file)
-	if err != nil {
-		response.BadRequest(c, err.Error())
+	uploadedFile, ok := h.audioUpload(c)
+	if !ok {
 		return
 	}
 
@@ -224,16 +239,55 @@ func (h *AIProxyHandler) TranscribeAudio(c *gin.Context) {
 	}
 	c.Header("X-Cache", cacheStatus)
 
-	c.Header("Content-Type", "application/json")
-	jsonBytes, err := json.Marshal(result)
+	response.Render(c, http.StatusOK, result)
+
+	logger.Ctx(c).Info("Transcription request completed", zap.Duration("latency", time.Since(start)))
+	metrics.RecordAIRequest("transcription", time.Since(start).Seconds(), "success", fromCache)
+	h.emitCompleted(c, "transcription", start, fromCache)
+}
+
+// audioUpload reads and validates the audio to transcribe: the file part,
+// or the completed resumable upload named by upload_id
+func (h *AIProxyHandler) audioUpload(c *gin.Context) (*helpers.UploadedFile, bool) {
+	limit := h.uploads.For(config.UploadOpTranscribe)
+
+	if uploadID := c.PostForm("upload_id"); uploadID != "" {
+		user := c.MustGet("user").(models.User)
+		upload, content, err := h.resumable.Read(c.Request.Context(), user.ID, uploadID)
+		if err != nil {
+			if appErr, ok := apperrors.AsAppError(err); ok {
+				apperrors.RespondError(c, appErr)
+				return nil, false
+			}
+			logger.Ctx(c).Error("Failed to read upload", zap.String("upload_id", uploadID), zap.Error(err))
+			response.InternalError(c, "Failed to read upload")
+			return nil, false
+		}
+		if upload == nil {
+			response.NotFound(c, "Upload")
+			return nil, false
+		}
+		uploadedFile, err := helpers.ValidateUploadContent(c.Request.Context(), content, upload.Filename, limit.MaxSize, limit.AllowedTypes, "audio")
+		if err != nil {
+			respondInvalidUpload(c, err)
+			return nil, false
+		}
+		return uploadedFile, true
+	}
+
+	file, header, err := c.Request.FormFile("file")
 	if err != nil {
-		c.JSON(http.StatusOK, result)
-	} else {
-		c.Data(http.StatusOK, "application/json", jsonBytes)
+		response.BadRequest(c, "No file uploaded")
+		return nil, false
 	}
+	defer func() { _ = file.Close() }()
 
-	logger.InfoCtx(c.Request.Context(), "Transcription request completed", zap.Duration("latency", time.Since(start)))
-	metrics.RecordAIRequest("transcription", time.Since(start).Seconds(), "success", fromCache)
+	uploadedFile, err := helpers.ValidateAudioUpload(c.Request.Context(), header, file, limit.MaxSize, limit.AllowedTypes)
+	if err != nil {
+		respondInvalidUpload(c, err)
+		return nil, false
+	}
+	return uploadedFile, true
 }
 
 // AskQuestion godoc
@@ -244,6 +298,7 @@ func (h *AIProxyHandler) TranscribeAudio(c *gin.Context) {
 //	@Accept			multipart/form-data
 //	@Produce		json
 //	@Security		BearerAuth
+//	@Security		ApiKeyAuth
 //	@Param			file		formData	file				true	"Image file"
 //	@Param			question	formData	string				true	"Question about the image"
 //	@Router			/ask [post]
@@ -264,9 +319,10 @@ func (h *AIProxyHandler) AskQuestion(c *gin.Context) {
 	}
 
 	// Validate and read file
-	uploadedFile, err := helpers.ValidateImageUpload(header, file)
+	limit := h.uploads.For(config.UploadOpAsk)
+	uploadedFile, err := helpers.ValidateImageUpload(c.Request.Context(), header, file, limit.MaxSize, limit.AllowedTypes)
 	if err != nil {
-		response.BadRequest(c, err.Error())
+		respondInvalidUpload(c, err)
 		return
 	}
 
@@ -282,14 +338,62 @@ func (h *AIProxyHandler) AskQuestion(c *gin.Context) {
 	}
 	c.Header("X-Cache", cacheStatus)
 
-	c.Header("Content-Type", "application/json")
-	jsonBytes, err := json.Marshal(result)
+	response.Render(c, http.StatusOK, result)
+
+	logger.Ctx(c).Info("VQA request completed", zap.Duration("latency", time.Since(start)))
+	metrics.RecordAIRequest("vqa", time.Since(start).Seconds(), "success", fromCache)
+	h.emitCompleted(c, "vqa", start, fromCache)
+}
+
+// SimplifyText godoc
+//
+//	@Summary		Simplify text for listening
+//	@Description	Rewrite text, e.g. OCR output, at an easy reading level using Google Gemini via gRPC, split into sentence-aligned chunks for TTS playback. When the text can't be simplified it is returned chunked as is, with simplified false. Text is limited to SIMPLIFY_MAX_CHARS characters.
+//	@Tags			AI
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			request	body	dto.SimplifyRequest	true	"Text to simplify"
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed or text too long"
+//	@Failure		503		{object}	response.ErrorResponse	"Circuit breaker open"
+//	@Router			/simplify [post]
+func (h *AIProxyHandler) SimplifyText(c *gin.Context) {
+	start := time.Now()
+
+	var input dto.SimplifyRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+	level := input.Level
+	if level == "" {
+		level = "easy"
+	}
+
+	result, fromCache, err := h.aiService.SimplifyText(c.Request.Context(), input.Text, input.Language, level)
 	if err != nil {
-		c.JSON(http.StatusOK, result)
-	} else {
-		c.Data(http.StatusOK, "application/json", jsonBytes)
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			apperrors.RespondError(c, appErr)
+			return
+		}
+		handleAIServiceError(c, err, "simplify")
+		return
 	}
 
-	logger.InfoCtx(c.Request.Context(), "VQA request completed", zap.Duration("latency", time.Since(start)))
-	metrics.RecordAIRequest("vqa", time.Since(start).Seconds(), "success", fromCache)
+	cacheStatus := "MISS"
+	if fromCache {
+		cacheStatus = "HIT"
+	}
+	c.Header("X-Cache", cacheStatus)
+
+	response.Render(c, http.StatusOK, result)
+
+	logger.Ctx(c).Info("Simplification request completed",
+		zap.Duration("latency", time.Since(start)),
+		zap.Int("text_bytes", len(input.Text)),
+	)
+	metrics.RecordAIRequest("simplify", time.Since(start).Seconds(), "success", fromCache)
+	h.emitCompleted(c, "simplify", start, fromCache)
 }
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// AnalyticsHandler serves AI usage analytics to operators
+type AnalyticsHandler struct {
+	usage services.UsageService
+}
+
+func NewAnalyticsHandler(usage services.UsageService) *AnalyticsHandler {
+	return &AnalyticsHandler{usage: usage}
+}
+
+// bindAnalyticsQuery binds the date range, answering 400 when it is invalid
+func bindAnalyticsQuery(c *gin.Context) (dto.AnalyticsQuery, bool) {
+	var query dto.AnalyticsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return query, false
+	}
+	return query, true
+}
+
+// respondAnalytics answers with report or the error that prevented it
+func respondAnalytics(c *gin.Context, report any, err error) {
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			apperrors.RespondError(c, appErr)
+			return
+		}
+		logger.Ctx(c).Error("Failed to load usage analytics", zap.Error(err))
+		response.InternalError(c, "Failed to load usage analytics")
+		return
+	}
+	response.Success(c, report)
+}
+
+// GetDailyUsage godoc
+//
+//	@Summary		Daily AI usage
+//	@Description	Requests, cache hit rate and errors per UTC day. Results are cached for CACHE_ANALYTICS_TTL.
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			from	query		string	false	"First day (YYYY-MM-DD), default 29 days before to"
+//	@Param			to		query		string	false	"Last day (YYYY-MM-DD), default today; at most 366 days after from"
+//	@Success		200		{object}	response.SuccessResponse{data=[]dto.DailyUsage}
+//	@Failure		400		{object}	response.ErrorResponse	"Invalid date range"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/analytics/daily [get]
+func (h *AnalyticsHandler) GetDailyUsage(c *gin.Context) {
+	query, ok := bindAnalyticsQuery(c)
+	if !ok {
+		return
+	}
+	report, err := h.usage.Daily(c.Request.Context(), query)
+	respondAnalytics(c, report, err)
+}
+
+// GetOperationUsage godoc
+//
+//	@Summary		AI usage per operation
+//	@Description	Requests, cache hit rate and errors per AI operation (detect, ocr, ask, transcribe, simplify), busiest first
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			from	query		string	false	"First day (YYYY-MM-DD), default 29 days before to"
+//	@Param			to		query		string	false	"Last day (YYYY-MM-DD), default today; at most 366 days after from"
+//	@Success		200		{object}	response.SuccessResponse{data=[]dto.OperationUsage}
+//	@Failure		400		{object}	response.ErrorResponse	"Invalid date range"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/analytics/operations [get]
+func (h *AnalyticsHandler) GetOperationUsage(c *gin.Context) {
+	query, ok := bindAnalyticsQuery(c)
+	if !ok {
+		return
+	}
+	report, err := h.usage.Operations(c.Request.Context(), query)
+	respondAnalytics(c, report, err)
+}
+
+// GetTopUsers godoc
+//
+//	@Summary		Top AI users
+//	@Description	The users with the most AI requests in the range
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			from	query		string	false	"First day (YYYY-MM-DD), default 29 days before to"
+//	@Param			to		query		string	false	"Last day (YYYY-MM-DD), default today; at most 366 days after from"
+//	@Param			limit	query		int		false	"Number of users (max 100)"	default(10)
+//	@Success		200		{object}	response.SuccessResponse{data=[]dto.UserUsage}
+//	@Failure		400		{object}	response.ErrorResponse	"Invalid date range"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/analytics/top-users [get]
+func (h *AnalyticsHandler) GetTopUsers(c *gin.Context) {
+	query, ok := bindAnalyticsQuery(c)
+	if !ok {
+		return
+	}
+	report, err := h.usage.TopUsers(c.Request.Context(), query)
+	respondAnalytics(c, report, err)
+}
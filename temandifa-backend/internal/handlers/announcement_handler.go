@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"golang.org/x/text/language"
+
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// AnnouncementHandler serves active announcements to the app and their
+// management to administrators
+type AnnouncementHandler struct {
+	announcements services.AnnouncementService
+}
+
+func NewAnnouncementHandler(announcements services.AnnouncementService) *AnnouncementHandler {
+	return &AnnouncementHandler{announcements: announcements}
+}
+
+// respondAnnouncementError answers validation errors as such and anything
+// else as 500
+func respondAnnouncementError(c *gin.Context, err error, message string) {
+	if appErr, ok := apperrors.AsAppError(err); ok {
+		apperrors.RespondError(c, appErr)
+		return
+	}
+	logger.Ctx(c).Error(message, zap.Error(err))
+	response.InternalError(c, message)
+}
+
+// GetAnnouncements godoc
+//
+//	@Summary		Get announcements
+//	@Description	Announcements shown now, each in the best match for the locale parameter or Accept-Language (Indonesian when none matches). Public and served during maintenance, so the app can poll it.
+//	@Tags			Announcements
+//	@Produce		json
+//	@Param			locale	query		string	false	"Preferred locale, e.g. en (overrides Accept-Language)"
+//	@Success		200		{object}	response.SuccessResponse{data=[]dto.Announcement}
+//	@Router			/announcements [get]
+func (h *AnnouncementHandler) GetAnnouncements(c *gin.Context) {
+	var preferred []language.Tag
+	if locale := c.Query("locale"); locale != "" {
+		if tag, err := language.Parse(locale); err == nil {
+			preferred = []language.Tag{tag}
+		}
+	}
+	if preferred == nil {
+		// Malformed headers just mean no preference
+		preferred, _, _ = language.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+	}
+
+	c.Header("Vary", "Accept-Language")
+	response.Success(c, h.announcements.Active(preferred))
+}
+
+// ListAnnouncements godoc
+//
+//	@Summary		List announcements
+//	@Description	Every announcement, including scheduled and expired ones, with all translations
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=[]models.Announcement}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/announcements [get]
+func (h *AnnouncementHandler) ListAnnouncements(c *gin.Context) {
+	announcements, err := h.announcements.List(c.Request.Context())
+	if err != nil {
+		logger.Ctx(c).Error("Failed to list announcements", zap.Error(err))
+		response.InternalError(c, "Failed to list announcements")
+		return
+	}
+	response.Success(c, announcements)
+}
+
+// bindAnnouncement reads the request body into an announcement, answering
+// 400 when it is invalid
+func bindAnnouncement(c *gin.Context) (models.Announcement, bool) {
+	var input dto.SaveAnnouncementRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return models.Announcement{}, false
+	}
+
+	announcement := models.Announcement{
+		Kind:         input.Kind,
+		EndsAt:       input.EndsAt,
+		Translations: make([]models.AnnouncementTranslation, len(input.Translations)),
+	}
+	if input.StartsAt != nil {
+		announcement.StartsAt = *input.StartsAt
+	}
+	for i, translation := range input.Translations {
+		announcement.Translations[i] = models.AnnouncementTranslation{
+			Locale: translation.Locale,
+			Title:  translation.Title,
+			Body:   translation.Body,
+		}
+	}
+	return announcement, true
+}
+
+// CreateAnnouncement godoc
+//
+//	@Summary		Create an announcement
+//	@Description	Schedule an announcement with one title and body per locale
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.SaveAnnouncementRequest	true	"Announcement"
+//	@Success		201		{object}	response.SuccessResponse{data=models.Announcement}
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/announcements [post]
+func (h *AnnouncementHandler) CreateAnnouncement(c *gin.Context) {
+	announcement, ok := bindAnnouncement(c)
+	if !ok {
+		return
+	}
+
+	if err := h.announcements.Create(c.Request.Context(), &announcement); err != nil {
+		respondAnnouncementError(c, err, "Failed to create announcement")
+		return
+	}
+
+	logger.Ctx(c).Info("Announcement created",
+		zap.Uint("announcement_id", announcement.ID),
+		zap.String("kind", announcement.Kind),
+	)
+	response.Created(c, announcement, "Announcement created")
+}
+
+// UpdateAnnouncement godoc
+//
+//	@Summary		Update an announcement
+//	@Description	Replace an announcement's schedule and translations
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int							true	"Announcement ID"
+//	@Param			request	body		dto.SaveAnnouncementRequest	true	"Announcement"
+//	@Success		200		{object}	response.SuccessResponse{data=models.Announcement}
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404		{object}	response.ErrorResponse	"Announcement not found"
+//	@Router			/admin/announcements/{id} [put]
+func (h *AnnouncementHandler) UpdateAnnouncement(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.NotFound(c, "Announcement")
+		return
+	}
+
+	announcement, ok := bindAnnouncement(c)
+	if !ok {
+		return
+	}
+	announcement.ID = uint(id)
+
+	found, err := h.announcements.Update(c.Request.Context(), &announcement)
+	if err != nil {
+		respondAnnouncementError(c, err, "Failed to update announcement")
+		return
+	}
+	if !found {
+		response.NotFound(c, "Announcement")
+		return
+	}
+
+	logger.Ctx(c).Info("Announcement updated", zap.Uint("announcement_id", announcement.ID))
+	response.Success(c, announcement, "Announcement updated")
+}
+
+// DeleteAnnouncement godoc
+//
+//	@Summary		Delete an announcement
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Announcement ID"
+//	@Success		200	{object}	response.SuccessResponse
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404	{object}	response.ErrorResponse	"Announcement not found"
+//	@Router			/admin/announcements/{id} [delete]
+func (h *AnnouncementHandler) DeleteAnnouncement(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.NotFound(c, "Announcement")
+		return
+	}
+
+	found, err := h.announcements.Delete(c.Request.Context(), uint(id))
+	if err != nil {
+		respondAnnouncementError(c, err, "Failed to delete announcement")
+		return
+	}
+	if !found {
+		response.NotFound(c, "Announcement")
+		return
+	}
+
+	logger.Ctx(c).Info("Announcement deleted", zap.Uint("announcement_id", uint(id)))
+	response.Success(c, nil, "Announcement deleted")
+}
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// APIKeyHandler lets administrators manage partner API keys
+type APIKeyHandler struct {
+	apiKeys services.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeys services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{apiKeys: apiKeys}
+}
+
+// respondAPIKeyError answers validation errors as such and anything else as 500
+func respondAPIKeyError(c *gin.Context, err error, message string) {
+	if appErr, ok := apperrors.AsAppError(err); ok {
+		apperrors.RespondError(c, appErr)
+		return
+	}
+	logger.Ctx(c).Error(message, zap.Error(err))
+	response.InternalError(c, message)
+}
+
+// apiKeyID parses the key ID of the route; false when it isn't one
+func apiKeyID(c *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.NotFound(c, "API key")
+		return 0, false
+	}
+	return id, true
+}
+
+// ListAPIKeys godoc
+//
+//	@Summary		List API keys
+//	@Description	Every partner API key, without the keys themselves
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=[]dto.APIKey}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.apiKeys.List(c.Request.Context())
+	if err != nil {
+		logger.Ctx(c).Error("Failed to list API keys", zap.Error(err))
+		response.InternalError(c, "Failed to list API keys")
+		return
+	}
+	response.Success(c, keys)
+}
+
+// GetAPIKey godoc
+//
+//	@Summary		Get an API key
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"API key ID"
+//	@Success		200	{object}	response.SuccessResponse{data=dto.APIKey}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404	{object}	response.ErrorResponse	"API key not found"
+//	@Router			/admin/api-keys/{id} [get]
+func (h *APIKeyHandler) GetAPIKey(c *gin.Context) {
+	id, ok := apiKeyID(c)
+	if !ok {
+		return
+	}
+
+	key, err := h.apiKeys.Get(c.Request.Context(), id)
+	if err != nil {
+		logger.Ctx(c).Error("Failed to load API key", zap.Error(err))
+		response.InternalError(c, "Failed to load API key")
+		return
+	}
+	if key == nil {
+		response.NotFound(c, "API key")
+		return
+	}
+	response.Success(c, key)
+}
+
+// CreateAPIKey godoc
+//
+//	@Summary		Create an API key
+//	@Description	Issue a key a partner app calls the AI endpoints with (X-API-Key header) instead of an access token. The key acts as user_id, whose plan quotas apply, may only use the AI operations in scopes (detect, ocr, transcribe, ask, simplify) and, with rate_limit, is limited to that many requests per minute. The key is only returned here.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.CreateAPIKeyRequest	true	"API key"
+//	@Success		201		{object}	response.SuccessResponse{data=dto.APIKey}
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed, unknown scope or user"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var input dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	key, err := h.apiKeys.Create(c.Request.Context(), input)
+	if err != nil {
+		respondAPIKeyError(c, err, "Failed to create API key")
+		return
+	}
+
+	logger.Ctx(c).Info("API key created",
+		zap.Uint64("api_key_id", key.ID),
+		zap.Uint("owner_id", key.UserID),
+		zap.Strings("scopes", key.Scopes),
+	)
+	response.Created(c, key, "API key created")
+}
+
+// UpdateAPIKey godoc
+//
+//	@Summary		Update an API key
+//	@Description	Replace a key's name, scopes, rate limit and expiry; the key and its user stay the same
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int						true	"API key ID"
+//	@Param			request	body		dto.UpdateAPIKeyRequest	true	"API key"
+//	@Success		200		{object}	response.SuccessResponse{data=dto.APIKey}
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed or unknown scope"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404		{object}	response.ErrorResponse	"API key not found"
+//	@Router			/admin/api-keys/{id} [put]
+func (h *APIKeyHandler) UpdateAPIKey(c *gin.Context) {
+	id, ok := apiKeyID(c)
+	if !ok {
+		return
+	}
+
+	var input dto.UpdateAPIKeyRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	key, err := h.apiKeys.Update(c.Request.Context(), id, input)
+	if err != nil {
+		respondAPIKeyError(c, err, "Failed to update API key")
+		return
+	}
+	if key == nil {
+		response.NotFound(c, "API key")
+		return
+	}
+
+	logger.Ctx(c).Info("API key updated", zap.Uint64("api_key_id", id), zap.Strings("scopes", key.Scopes))
+	response.Success(c, key, "API key updated")
+}
+
+// DeleteAPIKey godoc
+//
+//	@Summary		Revoke an API key
+//	@Description	Delete a key; other servers may accept it for up to 30 more seconds
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"API key ID"
+//	@Success		200	{object}	response.SuccessResponse
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404	{object}	response.ErrorResponse	"API key not found"
+//	@Router			/admin/api-keys/{id} [delete]
+func (h *APIKeyHandler) DeleteAPIKey(c *gin.Context) {
+	id, ok := apiKeyID(c)
+	if !ok {
+		return
+	}
+
+	deleted, err := h.apiKeys.Delete(c.Request.Context(), id)
+	if err != nil {
+		logger.Ctx(c).Error("Failed to delete API key", zap.Error(err))
+		response.InternalError(c, "Failed to delete API key")
+		return
+	}
+	if !deleted {
+		response.NotFound(c, "API key")
+		return
+	}
+
+	logger.Ctx(c).Info("API key revoked", zap.Uint64("api_key_id", id))
+	response.Success(c, nil, "API key revoked")
+}
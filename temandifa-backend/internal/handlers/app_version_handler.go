@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/middleware"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// AppVersionHandler serves the app version manifest to the app and its
+// management to administrators
+type AppVersionHandler struct {
+	versions services.AppVersionService
+}
+
+func NewAppVersionHandler(versions services.AppVersionService) *AppVersionHandler {
+	return &AppVersionHandler{versions: versions}
+}
+
+// respondAppVersionError answers validation errors as such and anything else as 500
+func respondAppVersionError(c *gin.Context, err error, message string) {
+	if appErr, ok := apperrors.AsAppError(err); ok {
+		apperrors.RespondError(c, appErr)
+		return
+	}
+	logger.Ctx(c).Error(message, zap.Error(err))
+	response.InternalError(c, message)
+}
+
+// GetAppVersion godoc
+//
+//	@Summary		Get the app version manifest
+//	@Description	Minimum and latest app version, store link and changelog per platform. Given the caller's platform and version (parameters or the X-App-Platform and X-App-Version headers), returns only that platform and whether the app must update (below the minimum; other requests then get 426 UPGRADE_REQUIRED) or may update. Public and served during maintenance.
+//	@Tags			App
+//	@Produce		json
+//	@Param			platform	query		string	false	"android or ios (overrides X-App-Platform)"
+//	@Param			version		query		string	false	"App version, e.g. 2.0.3 (overrides X-App-Version)"
+//	@Success		200			{object}	response.SuccessResponse{data=[]dto.AppVersionStatus}
+//	@Router			/app/version [get]
+func (h *AppVersionHandler) GetAppVersion(c *gin.Context) {
+	platform := c.DefaultQuery("platform", c.GetHeader(middleware.AppPlatformHeader))
+	version := c.DefaultQuery("version", c.GetHeader(middleware.AppVersionHeader))
+
+	platforms := services.AppPlatforms
+	if platform != "" {
+		platforms = []string{strings.ToLower(platform)}
+	}
+	statuses := make([]dto.AppVersionStatus, 0, len(platforms))
+	for _, p := range platforms {
+		if status := h.versions.Status(p, version); status != nil {
+			statuses = append(statuses, *status)
+		}
+	}
+
+	c.Header("Vary", middleware.AppPlatformHeader+", "+middleware.AppVersionHeader)
+	response.Success(c, statuses)
+}
+
+// ListAppVersions godoc
+//
+//	@Summary		List app version manifests
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=[]models.AppVersion}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/app-versions [get]
+func (h *AppVersionHandler) ListAppVersions(c *gin.Context) {
+	response.Success(c, h.versions.List())
+}
+
+// SaveAppVersion godoc
+//
+//	@Summary		Set a platform's app versions
+//	@Description	Create or replace the platform's manifest. Raising min_version forces older apps to update: their requests get 426 UPGRADE_REQUIRED.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			platform	path		string						true	"Platform (android, ios)"
+//	@Param			request		body		dto.SaveAppVersionRequest	true	"Manifest"
+//	@Success		200			{object}	response.SuccessResponse{data=models.AppVersion}
+//	@Failure		400			{object}	response.ErrorResponse	"Validation failed, unknown platform or invalid version"
+//	@Failure		401			{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403			{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/app-versions/{platform} [put]
+func (h *AppVersionHandler) SaveAppVersion(c *gin.Context) {
+	var input dto.SaveAppVersionRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	version, err := h.versions.Save(c.Request.Context(), &models.AppVersion{
+		Platform:      c.Param("platform"),
+		MinVersion:    input.MinVersion,
+		LatestVersion: input.LatestVersion,
+		StoreURL:      input.StoreURL,
+		Changelog:     input.Changelog,
+	})
+	if err != nil {
+		respondAppVersionError(c, err, "Failed to save app version")
+		return
+	}
+
+	logger.Ctx(c).Info("App version saved",
+		zap.String("platform", version.Platform),
+		zap.String("min_version", version.MinVersion),
+		zap.String("latest_version", version.LatestVersion),
+	)
+	response.Success(c, version, "App version saved")
+}
+
+// DeleteAppVersion godoc
+//
+//	@Summary		Delete a platform's app versions
+//	@Description	Without a manifest the platform gets no update prompts and no version gating
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			platform	path		string	true	"Platform (android, ios)"
+//	@Success		200			{object}	response.SuccessResponse
+//	@Failure		400			{object}	response.ErrorResponse	"Unknown platform"
+//	@Failure		401			{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403			{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404			{object}	response.ErrorResponse	"Platform has no manifest"
+//	@Router			/admin/app-versions/{platform} [delete]
+func (h *AppVersionHandler) DeleteAppVersion(c *gin.Context) {
+	deleted, err := h.versions.Delete(c.Request.Context(), c.Param("platform"))
+	if err != nil {
+		respondAppVersionError(c, err, "Failed to delete app version")
+		return
+	}
+	if !deleted {
+		response.NotFound(c, "App version")
+		return
+	}
+
+	logger.Ctx(c).Info("App version deleted", zap.String("platform", c.Param("platform")))
+	response.Success(c, nil, "App version deleted")
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// AuditHandler serves the admin audit log
+type AuditHandler struct {
+	audit services.AuditService
+}
+
+func NewAuditHandler(audit services.AuditService) *AuditHandler {
+	return &AuditHandler{audit: audit}
+}
+
+// GetAuditEntry godoc
+//
+//	@Summary		Get an audit log entry
+//	@Description	One administrative change with the target's JSON snapshots before and after it and the field changes between them. before is absent for creations, after for deletions, and both for actions that don't change a stored target (changes is then empty).
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Audit entry ID"
+//	@Success		200	{object}	response.SuccessResponse{data=dto.AuditEntry}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404	{object}	response.ErrorResponse	"Audit entry not found"
+//	@Router			/admin/audit/{id} [get]
+func (h *AuditHandler) GetAuditEntry(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.NotFound(c, "Audit entry")
+		return
+	}
+
+	entry, err := h.audit.Find(c.Request.Context(), uint(id))
+	if err != nil {
+		logger.Ctx(c).Error("Failed to load audit entry", zap.Error(err))
+		response.InternalError(c, "Failed to load audit entry")
+		return
+	}
+	if entry == nil {
+		response.NotFound(c, "Audit entry")
+		return
+	}
+	response.Success(c, entry)
+}
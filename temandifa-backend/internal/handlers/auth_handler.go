@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"errors"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -11,6 +12,7 @@ import (
 	apperrors "temandifa-backend/internal/errors"
 	"temandifa-backend/internal/helpers"
 	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/middleware"
 	"temandifa-backend/internal/models"
 	"temandifa-backend/internal/response"
 	"temandifa-backend/internal/services"
@@ -20,14 +22,18 @@ type AuthHandler struct {
 	AuthService    services.AuthService
 	TokenService   services.TokenService
 	TokenBlacklist *services.TokenBlacklist
+	Events         services.EventService
+	Flags          services.FeatureFlagService
 }
 
 // NewAuthHandler creates a new AuthHandler
-func NewAuthHandler(authService services.AuthService, tokenService services.TokenService, tokenBlacklist *services.TokenBlacklist) *AuthHandler {
+func NewAuthHandler(authService services.AuthService, tokenService services.TokenService, tokenBlacklist *services.TokenBlacklist, events services.EventService, flags services.FeatureFlagService) *AuthHandler {
 	return &AuthHandler{
 		AuthService:    authService,
 		TokenService:   tokenService,
 		TokenBlacklist: tokenBlacklist,
+		Events:         events,
+		Flags:          flags,
 	}
 }
 
@@ -51,23 +57,23 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	user, err := h.AuthService.Register(input)
+	user, err := h.AuthService.Register(c.Request.Context(), input)
 	if err != nil {
 		if appErr, ok := apperrors.AsAppError(err); ok {
 			if errors.Is(err, apperrors.AlreadyExists("email")) {
-				logger.Warn("Registration failed - email may exist",
+				logger.Ctx(c).Warn("Registration failed - email may exist",
 					zap.String("email", input.Email),
 				)
 			}
 			apperrors.RespondError(c, appErr)
 			return
 		}
-		logger.Error("Failed to register user", zap.Error(err))
+		logger.Ctx(c).Error("Failed to register user", zap.Error(err))
 		response.InternalError(c, "Failed to process registration")
 		return
 	}
 
-	logger.Info("User registered successfully",
+	logger.Ctx(c).Info("User registered successfully",
 		zap.Uint("user_id", user.ID),
 		zap.String("email", user.Email),
 	)
@@ -78,14 +84,16 @@ func (h *AuthHandler) Register(c *gin.Context) {
 // Login godoc
 //
 //	@Summary		Login user
-//	@Description	Authenticate user and return access/refresh token pair
+//	@Description	Authenticate user and return access/refresh token pair. With X-Device-ID the refresh token is bound to the device: it replaces the device's previous session and only refreshes with the same header. Too many failed logins of an email address or client IP lock them out for a while (429 ACCOUNT_LOCKED).
 //	@Tags			Auth
 //	@Accept			json
 //	@Produce		json
-//	@Param			input	body		dto.LoginRequest	true	"Login credentials"
-//	@Success		200		{object}	response.SuccessResponse{data=dto.LoginResponse}
-//	@Failure		400		{object}	response.ErrorResponse
-//	@Failure		401		{object}	response.ErrorResponse
+//	@Param			X-Device-ID	header		string				false	"App installation ID"
+//	@Param			input		body		dto.LoginRequest	true	"Login credentials"
+//	@Success		200			{object}	response.SuccessResponse{data=dto.LoginResponse}
+//	@Failure		400			{object}	response.ErrorResponse
+//	@Failure		401			{object}	response.ErrorResponse
+//	@Failure		429			{object}	response.ErrorResponse	"Too many failed logins (ACCOUNT_LOCKED, details hold the unlock time)"
 //	@Router			/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var input dto.LoginRequest
@@ -95,39 +103,63 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	tokenResponse, err := h.AuthService.Login(input, c.GetHeader("User-Agent"), c.ClientIP())
+	tokenResponse, err := h.AuthService.Login(c.Request.Context(), input, c.GetHeader("User-Agent"), c.ClientIP(), requestDeviceID(c))
 	if err != nil {
 		if appErr, ok := apperrors.AsAppError(err); ok {
 			if errors.Is(err, apperrors.ErrInvalidCredentials) {
-				logger.Debug("Login failed - invalid credentials", zap.String("email", input.Email))
+				logger.Ctx(c).Debug("Login failed - invalid credentials", zap.String("email", input.Email))
+			}
+			if details, ok := appErr.Details.(apperrors.LockoutDetails); ok {
+				c.Header("Retry-After", strconv.Itoa(details.RetryAfterSeconds))
 			}
 			apperrors.RespondError(c, appErr)
 			return
 		}
-		logger.Error("Login failed", zap.Error(err))
+		logger.Ctx(c).Error("Login failed", zap.Error(err))
 		response.InternalError(c, "Login failed")
 		return
 	}
 
-	logger.Info("User logged in successfully",
+	logger.Ctx(c).Info("User logged in successfully",
 		zap.Uint("user_id", tokenResponse.User.ID),
 		zap.String("email", tokenResponse.User.Email),
 	)
 
+	h.Events.Emit(c.Request.Context(), services.Event{
+		Type:       services.EventLogin,
+		UserID:     tokenResponse.User.ID,
+		Properties: map[string]any{"method": "password"},
+	})
+
+	tokenResponse.Features = h.Flags.Evaluate(c.Request.Context(), services.FlagSubject{
+		UserID: tokenResponse.User.ID,
+		Email:  tokenResponse.User.Email,
+	})
+
 	response.Success(c, tokenResponse)
 }
 
+// requestDeviceID returns the device ID the app sent (X-Device-ID), or ""
+// when it sent none or an invalid one
+func requestDeviceID(c *gin.Context) string {
+	if deviceID := c.GetHeader(middleware.DeviceIDHeader); helpers.ValidDeviceID(deviceID) {
+		return deviceID
+	}
+	return ""
+}
+
 // Refresh godoc
 //
 //	@Summary		Refresh access token
-//	@Description	Exchange refresh token for new access/refresh token pair
+//	@Description	Exchange refresh token for new access/refresh token pair. A token bound to a device (see login) is revoked when refreshed without its X-Device-ID.
 //	@Tags			Auth
 //	@Accept			json
 //	@Produce		json
-//	@Param			input	body		dto.RefreshTokenRequest	true	"Refresh token"
-//	@Success		200		{object}	response.SuccessResponse{data=dto.TokenResponse}
-//	@Failure		400		{object}	response.ErrorResponse
-//	@Failure		401		{object}	response.ErrorResponse
+//	@Param			X-Device-ID	header		string					false	"App installation ID"
+//	@Param			input		body		dto.RefreshTokenRequest	true	"Refresh token"
+//	@Success		200			{object}	response.SuccessResponse{data=dto.TokenResponse}
+//	@Failure		400			{object}	response.ErrorResponse
+//	@Failure		401			{object}	response.ErrorResponse
 //	@Router			/refresh [post]
 func (h *AuthHandler) Refresh(c *gin.Context) {
 	var input dto.RefreshTokenRequest
@@ -137,12 +169,14 @@ func (h *AuthHandler) Refresh(c *gin.Context) {
 	}
 
 	tokenPair, err := h.TokenService.RefreshAccessToken(
+		c.Request.Context(),
 		input.RefreshToken,
 		c.GetHeader("User-Agent"),
 		c.ClientIP(),
+		requestDeviceID(c),
 	)
 	if err != nil {
-		logger.Debug("Token refresh failed", zap.Error(err))
+		logger.Ctx(c).Debug("Token refresh failed", zap.Error(err))
 		if appErr, ok := apperrors.AsAppError(err); ok {
 			apperrors.RespondError(c, appErr)
 			return
@@ -152,11 +186,11 @@ func (h *AuthHandler) Refresh(c *gin.Context) {
 	}
 
 	// Get user info (TokenService already validated token)
-	userID, _ := h.TokenService.ValidateAccessToken(tokenPair.AccessToken)
-
-	logger.Debug("Token refreshed",
-		zap.Uint("user_id", userID),
-	)
+	if claims, err := h.TokenService.ValidateAccessToken(tokenPair.AccessToken); err == nil {
+		logger.Ctx(c).Debug("Token refreshed",
+			zap.Uint("user_id", claims.UserID),
+		)
+	}
 
 	// Construct response
 
@@ -189,7 +223,7 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 
 	// Revoke refresh token
 	if err := h.TokenService.RevokeRefreshToken(input.RefreshToken); err != nil {
-		logger.Debug("Logout failed - refresh token not found", zap.Error(err))
+		logger.Ctx(c).Debug("Logout failed - refresh token not found", zap.Error(err))
 	}
 
 	// Blacklist the access token to prevent reuse
@@ -198,12 +232,12 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 		accessToken := authHeader[7:]
 		if h.TokenBlacklist != nil {
 			if err := h.TokenBlacklist.Add(c, accessToken, time.Hour); err != nil {
-				logger.Warn("Failed to blacklist access token", zap.Error(err))
+				logger.Ctx(c).Warn("Failed to blacklist access token", zap.Error(err))
 			}
 		}
 	}
 
-	logger.Debug("User logged out")
+	logger.Ctx(c).Debug("User logged out")
 	response.Success(c, nil, "Logged out successfully")
 }
 
@@ -222,14 +256,12 @@ func (h *AuthHandler) LogoutAll(c *gin.Context) {
 	user := c.MustGet("user").(models.User)
 
 	if err := h.TokenService.RevokeAllUserTokens(user.ID); err != nil {
-		logger.Error("Failed to revoke all tokens", zap.Error(err))
+		logger.Ctx(c).Error("Failed to revoke all tokens", zap.Error(err))
 		response.InternalError(c, "Failed to logout from all devices")
 		return
 	}
 
-	logger.Info("User logged out from all devices",
-		zap.Uint("user_id", user.ID),
-	)
+	logger.Ctx(c).Info("User logged out from all devices")
 
 	response.Success(c, nil, "Logged out from all devices")
 }
@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/dto"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/response"
+)
+
+const (
+	// batchBasePath is where the paths of batched requests are relative to
+	batchBasePath = "/api/v1"
+	// maxBatchResponseSize caps the body of one batched response; larger
+	// ones (e.g. downloads) have to be requested separately
+	maxBatchResponseSize = 1 << 20
+)
+
+// batchForwardedHeaders are copied from the batch to each of its requests,
+// besides the client IP headers
+var batchForwardedHeaders = []string{
+	"Authorization", "Accept-Language", "Accept-Version", "User-Agent",
+	"X-App-Platform", "X-App-Version", "traceparent",
+}
+
+// batchItemHeaders may be set per batched request
+var batchItemHeaders = map[string]bool{
+	"If-None-Match":   true,
+	"If-Match":        true,
+	"Accept-Language": true,
+}
+
+// batchResponseHeaders are returned with each batched response
+var batchResponseHeaders = []string{
+	"ETag", "Location", "Retry-After",
+	"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset",
+	"X-Quota-Limit", "X-Quota-Remaining", "X-Quota-Reset",
+}
+
+// BatchHandler serves several API requests in one round trip, for slow
+// mobile connections syncing several resources at once
+type BatchHandler struct {
+	router *gin.Engine
+	cfg    *config.ServerConfig
+}
+
+func NewBatchHandler(router *gin.Engine, cfg *config.ServerConfig) *BatchHandler {
+	return &BatchHandler{router: router, cfg: cfg}
+}
+
+// ServeBatch godoc
+//
+//	@Summary		Batch requests
+//	@Description	Serve up to BATCH_MAX_REQUESTS API requests (BATCH_CONCURRENCY at a time) and return their responses in order. Each passes the router like a separate request with the batch's Authorization, Accept-Language and app headers, so it is authenticated, rate limited and counted on its own. Requests can't be batches or WebSockets; responses over 1 MiB are left out.
+//	@Tags			Batch
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		dto.BatchRequest	true	"Requests"
+//	@Success		200		{object}	response.SuccessResponse{data=dto.BatchResponse}
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed"
+//	@Router			/batch [post]
+func (h *BatchHandler) ServeBatch(c *gin.Context) {
+	var input dto.BatchRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+	if len(input.Requests) > h.cfg.BatchMaxRequests {
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed",
+			map[string]string{"requests": fmt.Sprintf("at most %d requests per batch", h.cfg.BatchMaxRequests)})
+		return
+	}
+
+	requestID := c.GetString(string(logger.RequestIDKey))
+	responses := make([]dto.BatchResponseItem, len(input.Requests))
+	slots := make(chan struct{}, h.cfg.BatchConcurrency)
+	var wg sync.WaitGroup
+	for i, item := range input.Requests {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			responses[i] = h.serve(c.Request, fmt.Sprintf("%s-%d", requestID, i+1), item)
+		}()
+	}
+	wg.Wait()
+
+	response.Success(c, dto.BatchResponse{Responses: responses})
+}
+
+// serve passes one batched request through the router
+func (h *BatchHandler) serve(batch *http.Request, requestID string, item dto.BatchRequestItem) dto.BatchResponseItem {
+	result := dto.BatchResponseItem{ID: item.ID}
+	fail := func(status int, err error) dto.BatchResponseItem {
+		result.Status, result.Error = status, err.Error()
+		return result
+	}
+
+	target, err := batchTarget(item.Path)
+	if err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+	var body io.Reader = http.NoBody
+	if len(item.Body) > 0 {
+		body = bytes.NewReader(item.Body)
+	}
+	req, err := http.NewRequestWithContext(batch.Context(), item.Method, target, body)
+	if err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	// Same client as the batch, also behind trusted proxies
+	req.RemoteAddr = batch.RemoteAddr
+	req.Host = batch.Host
+	for _, names := range [][]string{batchForwardedHeaders, h.cfg.ClientIPHeaders} {
+		for _, name := range names {
+			if values := batch.Header.Values(name); len(values) > 0 {
+				req.Header[http.CanonicalHeaderKey(name)] = values
+			}
+		}
+	}
+	for name, value := range item.Headers {
+		if !batchItemHeaders[http.CanonicalHeaderKey(name)] {
+			return fail(http.StatusBadRequest, fmt.Errorf("header %s can't be set in a batch", name))
+		}
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Accept", "application/json")
+	if len(item.Body) > 0 {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("X-Request-ID", requestID)
+
+	recorder := &batchRecorder{header: make(http.Header)}
+	h.router.ServeHTTP(recorder, req)
+
+	result.Status = recorder.status
+	if result.Status == 0 {
+		result.Status = http.StatusOK
+	}
+	for _, name := range batchResponseHeaders {
+		if value := recorder.header.Get(name); value != "" {
+			if result.Headers == nil {
+				result.Headers = make(map[string]string)
+			}
+			result.Headers[name] = value
+		}
+	}
+	if recorder.overflow {
+		result.Error = "Response too large for a batch; request it separately"
+		return result
+	}
+	if recorder.body.Len() > 0 {
+		mediaType, _, _ := mime.ParseMediaType(recorder.header.Get("Content-Type"))
+		if mediaType == "application/json" && json.Valid(recorder.body.Bytes()) {
+			result.Body = recorder.body.Bytes()
+		} else {
+			result.BodyBase64 = recorder.body.Bytes()
+		}
+	}
+	return result
+}
+
+// batchTarget resolves a batched request's path against the API
+func batchTarget(raw string) (string, error) {
+	target, err := url.Parse(raw)
+	if err != nil || target.Scheme != "" || target.Host != "" || !strings.HasPrefix(target.Path, "/") {
+		return "", errors.New("path must be an API path like /history")
+	}
+
+	clean := path.Clean(target.Path)
+	if strings.HasSuffix(target.Path, "/") && clean != "/" {
+		clean += "/"
+	}
+	if clean == "/batch" || strings.HasPrefix(clean, "/ws/") {
+		return "", fmt.Errorf("%s can't be batched", clean)
+	}
+	target.Path, target.RawPath = batchBasePath+clean, ""
+	return target.String(), nil
+}
+
+// batchRecorder captures a batched response, keeping at most
+// maxBatchResponseSize bytes of the body
+type batchRecorder struct {
+	header   http.Header
+	status   int
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (r *batchRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *batchRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *batchRecorder) Write(p []byte) (int, error) {
+	r.WriteHeader(http.StatusOK)
+	if r.overflow || r.body.Len()+len(p) > maxBatchResponseSize {
+		r.overflow = true
+		r.body.Reset()
+		return len(p), nil
+	}
+	return r.body.Write(p)
+}
+
+// Flush is a no-op; streamed responses are recorded as a whole
+func (r *batchRecorder) Flush() {}
@@ -1,9 +1,14 @@
 package handlers
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
 	"temandifa-backend/internal/logger"
 	"temandifa-backend/internal/response"
 	"temandifa-backend/internal/services"
@@ -50,12 +55,12 @@ func (h *CacheHandler) GetCacheStats(c *gin.Context) {
 func (h *CacheHandler) ClearDetectionCache(c *gin.Context) {
 	deleted, err := h.cacheService.ClearByPrefix(c.Request.Context(), "detect")
 	if err != nil {
-		logger.Error("Failed to clear detection cache", zap.Error(err))
+		logger.Ctx(c).Error("Failed to clear detection cache", zap.Error(err))
 		response.InternalError(c, "Failed to clear cache")
 		return
 	}
 
-	logger.Info("Detection cache cleared", zap.Int64("deleted", deleted))
+	logger.Ctx(c).Info("Detection cache cleared", zap.Int64("deleted", deleted))
 	response.Success(c, gin.H{"deleted": deleted}, "Detection cache cleared")
 }
 
@@ -74,12 +79,12 @@ func (h *CacheHandler) ClearDetectionCache(c *gin.Context) {
 func (h *CacheHandler) ClearOCRCache(c *gin.Context) {
 	deleted, err := h.cacheService.ClearByPrefix(c.Request.Context(), "ocr")
 	if err != nil {
-		logger.Error("Failed to clear OCR cache", zap.Error(err))
+		logger.Ctx(c).Error("Failed to clear OCR cache", zap.Error(err))
 		response.InternalError(c, "Failed to clear cache")
 		return
 	}
 
-	logger.Info("OCR cache cleared", zap.Int64("deleted", deleted))
+	logger.Ctx(c).Info("OCR cache cleared", zap.Int64("deleted", deleted))
 	response.Success(c, gin.H{"deleted": deleted}, "OCR cache cleared")
 }
 
@@ -98,12 +103,12 @@ func (h *CacheHandler) ClearOCRCache(c *gin.Context) {
 func (h *CacheHandler) ClearTranscriptionCache(c *gin.Context) {
 	deleted, err := h.cacheService.ClearByPrefix(c.Request.Context(), "transcribe")
 	if err != nil {
-		logger.Error("Failed to clear transcription cache", zap.Error(err))
+		logger.Ctx(c).Error("Failed to clear transcription cache", zap.Error(err))
 		response.InternalError(c, "Failed to clear cache")
 		return
 	}
 
-	logger.Info("Transcription cache cleared", zap.Int64("deleted", deleted))
+	logger.Ctx(c).Info("Transcription cache cleared", zap.Int64("deleted", deleted))
 	response.Success(c, gin.H{"deleted": deleted}, "Transcription cache cleared")
 }
 
@@ -131,6 +136,60 @@ func (h *CacheHandler) ClearAllCache(c *gin.Context) {
 	d3, _ := h.cacheService.ClearByPrefix(ctx, "transcribe")
 	totalDeleted += d3
 
-	logger.Info("All AI cache cleared", zap.Int64("deleted", totalDeleted))
+	logger.Ctx(c).Info("All AI cache cleared", zap.Int64("deleted", totalDeleted))
 	response.Success(c, gin.H{"deleted": totalDeleted}, "All cache cleared")
 }
+
+// InvalidateCache godoc
+//
+//	@Summary		Invalidate cache entries by pattern
+//	@Description	Two steps. Without confirm_token this is a dry run: it counts the AI cache entries matching the glob pattern and returns a confirm token valid for 5 minutes. Sending the same pattern with that token deletes the entries. Patterns must start with detect:, ocr:, transcribe: or vqa:.
+//	@Tags			Cache
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.InvalidateCacheRequest	true	"Pattern and, to execute, the confirm token"
+//	@Success		200		{object}	response.SuccessResponse{data=dto.CacheInvalidationPreview}	"Preview, or the deleted count once confirmed"
+//	@Failure		400		{object}	response.ErrorResponse	"Invalid pattern or confirm token"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		503		{object}	response.ErrorResponse	"Redis not configured"
+//	@Router			/cache/invalidate [post]
+func (h *CacheHandler) InvalidateCache(c *gin.Context) {
+	var input dto.InvalidateCacheRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+	ctx := c.Request.Context()
+
+	if input.ConfirmToken == "" {
+		preview, err := h.cacheService.PreviewInvalidation(ctx, input.Pattern)
+		if err != nil {
+			respondCacheError(c, err, "Failed to preview cache invalidation")
+			return
+		}
+		response.Success(c, preview, "Dry run: send the confirm token to delete these entries")
+		return
+	}
+
+	deleted, err := h.cacheService.Invalidate(ctx, input.Pattern, input.ConfirmToken)
+	if err != nil {
+		respondCacheError(c, err, "Failed to invalidate cache")
+		return
+	}
+
+	logger.Ctx(c).Info("Cache invalidated by pattern", zap.String("pattern", input.Pattern), zap.Int64("deleted", deleted))
+	response.Success(c, gin.H{"deleted": deleted}, "Cache invalidated")
+}
+
+// respondCacheError answers validation errors as such and anything else as 500
+func respondCacheError(c *gin.Context, err error, message string) {
+	if appErr, ok := apperrors.AsAppError(err); ok {
+		apperrors.RespondError(c, appErr)
+		return
+	}
+	logger.Ctx(c).Error(message, zap.Error(err))
+	response.InternalError(c, message)
+}
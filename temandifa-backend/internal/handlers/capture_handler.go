@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/dto"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// maxCaptureListLimit bounds how many captured exchanges can be fetched at once
+const maxCaptureListLimit = 200
+
+type CaptureHandler struct {
+	captureService services.RequestCaptureService
+}
+
+func NewCaptureHandler(captureService services.RequestCaptureService) *CaptureHandler {
+	return &CaptureHandler{
+		captureService: captureService,
+	}
+}
+
+// GetCaptureRule godoc
+//
+//	@Summary		Get request capture rule
+//	@Description	Get the active targeted request capture rule, if any
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	dto.CaptureRule
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404	{object}	response.ErrorResponse	"No active rule"
+//	@Router			/admin/capture [get]
+func (h *CaptureHandler) GetCaptureRule(c *gin.Context) {
+	rule, err := h.captureService.GetRule(c.Request.Context())
+	if err != nil {
+		logger.Ctx(c).Error("Failed to load capture rule", zap.Error(err))
+		response.InternalError(c, "Failed to load capture rule")
+		return
+	}
+	if rule == nil {
+		response.NotFound(c, "Capture rule")
+		return
+	}
+	response.Success(c, rule)
+}
+
+// SetCaptureRule godoc
+//
+//	@Summary		Enable request capture
+//	@Description	Capture redacted request/response pairs for a user ID or request ID pattern (sampled, expires automatically)
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.CaptureRuleRequest	true	"Capture target"
+//	@Success		200		{object}	dto.CaptureRule
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/capture [put]
+func (h *CaptureHandler) SetCaptureRule(c *gin.Context) {
+	var input dto.CaptureRuleRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	duration := services.DefaultCaptureDuration
+	if input.DurationMinutes > 0 {
+		duration = time.Duration(input.DurationMinutes) * time.Minute
+	}
+	sampleRate := input.SampleRate
+	if sampleRate == 0 {
+		sampleRate = 1
+	}
+
+	rule := dto.CaptureRule{
+		UserID:           input.UserID,
+		RequestIDPattern: input.RequestIDPattern,
+		SampleRate:       sampleRate,
+		CreatedBy:        c.GetUint(string(logger.UserIDKey)),
+		ExpiresAt:        time.Now().Add(duration).UTC(),
+	}
+
+	if err := h.captureService.SetRule(c.Request.Context(), rule); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	response.Success(c, rule, "Request capture enabled")
+}
+
+// DeleteCaptureRule godoc
+//
+//	@Summary		Disable request capture
+//	@Description	Remove the active capture rule (captured records are kept until they expire)
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/capture [delete]
+func (h *CaptureHandler) DeleteCaptureRule(c *gin.Context) {
+	if err := h.captureService.ClearRule(c.Request.Context()); err != nil {
+		logger.Ctx(c).Error("Failed to clear capture rule", zap.Error(err))
+		response.InternalError(c, "Failed to disable request capture")
+		return
+	}
+	response.Success(c, nil, "Request capture disabled")
+}
+
+// ListCapturedRequests godoc
+//
+//	@Summary		List captured requests
+//	@Description	Get the most recent captured request/response pairs (newest first)
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			limit	query		int	false	"Max records (default 50, max 200)"
+//	@Success		200		{array}		dto.CapturedExchange
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/capture/records [get]
+func (h *CaptureHandler) ListCapturedRequests(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 {
+		limit = 50
+	}
+	if limit > maxCaptureListLimit {
+		limit = maxCaptureListLimit
+	}
+
+	records, err := h.captureService.ListRecords(c.Request.Context(), limit)
+	if err != nil {
+		logger.Ctx(c).Error("Failed to list captured requests", zap.Error(err))
+		response.InternalError(c, "Failed to list captured requests")
+		return
+	}
+	response.Success(c, records)
+}
+
+// ClearCapturedRequests godoc
+//
+//	@Summary		Clear captured requests
+//	@Description	Delete all captured request/response pairs
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/capture/records [delete]
+func (h *CaptureHandler) ClearCapturedRequests(c *gin.Context) {
+	if err := h.captureService.ClearRecords(c.Request.Context()); err != nil {
+		logger.Ctx(c).Error("Failed to clear captured requests", zap.Error(err))
+		response.InternalError(c, "Failed to clear captured requests")
+		return
+	}
+	response.Success(c, nil, "Captured requests cleared")
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/dto"
+	"temandifa-backend/internal/response"
+)
+
+// ConfigHandler exposes the live configuration to administrators
+type ConfigHandler struct {
+	dynamic *config.Dynamic
+}
+
+func NewConfigHandler(dynamic *config.Dynamic) *ConfigHandler {
+	return &ConfigHandler{
+		dynamic: dynamic,
+	}
+}
+
+// GetEffectiveConfig godoc
+//
+//	@Summary		Get effective configuration
+//	@Description	Every configuration key with its live value and source (default, profile, file, env, secret:<manager>, remote or unset). Secrets are masked and DSNs have their passwords masked.
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=dto.EffectiveConfig}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/config [get]
+func (h *ConfigHandler) GetEffectiveConfig(c *gin.Context) {
+	response.Success(c, dto.EffectiveConfig{
+		AppEnv:   h.dynamic.AppEnv(),
+		Settings: h.dynamic.Settings(),
+	})
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// DataExportHandler serves personal data exports, to users for their own
+// data and to administrators for any user's
+type DataExportHandler struct {
+	exports services.DataExportService
+}
+
+func NewDataExportHandler(exports services.DataExportService) *DataExportHandler {
+	return &DataExportHandler{exports: exports}
+}
+
+// exportSubject resolves whose data a request is about: the :id user on
+// admin routes, otherwise the caller
+func exportSubject(c *gin.Context) (uint, bool) {
+	if param := c.Param("id"); param != "" {
+		id, err := strconv.ParseUint(param, 10, 32)
+		return uint(id), err == nil
+	}
+	return c.MustGet("user").(models.User).ID, true
+}
+
+func (h *DataExportHandler) request(c *gin.Context) {
+	userID, ok := exportSubject(c)
+	if !ok {
+		response.NotFound(c, "User")
+		return
+	}
+
+	export, err := h.exports.Request(c.Request.Context(), userID)
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			apperrors.RespondError(c, appErr)
+			return
+		}
+		logger.Ctx(c).Error("Failed to start data export", zap.Error(err))
+		response.InternalError(c, "Failed to start data export")
+		return
+	}
+	if export == nil {
+		response.NotFound(c, "User")
+		return
+	}
+
+	logger.Ctx(c).Info("Data export requested", zap.Uint("target_user_id", userID), zap.Uint64("export_id", export.ID))
+	response.Accepted(c, export, "Export started")
+}
+
+func (h *DataExportHandler) get(c *gin.Context) {
+	userID, ok := exportSubject(c)
+	exportID, err := strconv.ParseUint(c.Param("export_id"), 10, 64)
+	if !ok || err != nil {
+		response.NotFound(c, "Export")
+		return
+	}
+
+	export, err := h.exports.Find(c.Request.Context(), userID, exportID)
+	if err != nil {
+		logger.Ctx(c).Error("Failed to load data export", zap.Error(err))
+		response.InternalError(c, "Failed to load data export")
+		return
+	}
+	if export == nil {
+		response.NotFound(c, "Export")
+		return
+	}
+	response.Success(c, export)
+}
+
+func (h *DataExportHandler) download(c *gin.Context) {
+	userID, ok := exportSubject(c)
+	exportID, err := strconv.ParseUint(c.Param("export_id"), 10, 64)
+	if !ok || err != nil {
+		response.NotFound(c, "Export")
+		return
+	}
+
+	archive, export, err := h.exports.Open(c.Request.Context(), userID, exportID)
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			apperrors.RespondError(c, appErr)
+			return
+		}
+		logger.Ctx(c).Error("Failed to open data export", zap.Error(err))
+		response.InternalError(c, "Failed to open data export")
+		return
+	}
+	if export == nil {
+		response.NotFound(c, "Export")
+		return
+	}
+	defer func() {
+		_ = archive.Close()
+	}()
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="temandifa-export-%d.zip"`, export.ID))
+	c.Header("Content-Length", strconv.FormatInt(export.SizeBytes, 10))
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+	c.Writer.Header().Set("Content-Type", "application/zip")
+	if _, err := io.Copy(c.Writer, archive); err != nil {
+		logger.Ctx(c).Warn("Data export download interrupted", zap.Uint64("export_id", export.ID), zap.Error(err))
+	}
+}
+
+// RequestMyExport godoc
+//
+//	@Summary		Export my data
+//	@Description	Start building an archive of the caller's personal data (profile, history, sessions, usage, emergency contacts and alerts, calls, preferences, synced settings). Poll the export until it is ready; a user.data_export_ready integration event is sent too.
+//	@Tags			Account
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		202	{object}	response.SuccessResponse{data=models.DataExport}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		409	{object}	response.ErrorResponse	"An export is already in progress"
+//	@Failure		503	{object}	response.ErrorResponse	"Object storage not configured"
+//	@Router			/me/export [post]
+func (h *DataExportHandler) RequestMyExport(c *gin.Context) {
+	h.request(c)
+}
+
+// GetMyExport godoc
+//
+//	@Summary		Get one of my data exports
+//	@Tags			Account
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			export_id	path		int	true	"Export ID"
+//	@Success		200			{object}	response.SuccessResponse{data=models.DataExport}
+//	@Failure		401			{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		404			{object}	response.ErrorResponse	"Export not found"
+//	@Router			/me/exports/{export_id} [get]
+func (h *DataExportHandler) GetMyExport(c *gin.Context) {
+	h.get(c)
+}
+
+// DownloadMyExport godoc
+//
+//	@Summary		Download one of my data exports
+//	@Tags			Account
+//	@Produce		application/zip
+//	@Security		BearerAuth
+//	@Param			export_id	path		int		true	"Export ID"
+//	@Success		200			{file}		binary	"Zip archive of JSON files"
+//	@Failure		401			{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		404			{object}	response.ErrorResponse	"Export not found"
+//	@Failure		409			{object}	response.ErrorResponse	"Export is pending, failed or expired"
+//	@Router			/me/exports/{export_id}/download [get]
+func (h *DataExportHandler) DownloadMyExport(c *gin.Context) {
+	h.download(c)
+}
+
+// RequestUserExport godoc
+//
+//	@Summary		Export a user's data
+//	@Description	Start building an archive of the user's personal data for a subject access request; soft-deleted users can be exported until purged
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"User ID"
+//	@Success		202	{object}	response.SuccessResponse{data=models.DataExport}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404	{object}	response.ErrorResponse	"User not found"
+//	@Failure		409	{object}	response.ErrorResponse	"An export is already in progress"
+//	@Failure		503	{object}	response.ErrorResponse	"Object storage not configured"
+//	@Router			/admin/users/{id}/export [post]
+func (h *DataExportHandler) RequestUserExport(c *gin.Context) {
+	h.request(c)
+}
+
+// GetUserExport godoc
+//
+//	@Summary		Get a user's data export
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id			path		int	true	"User ID"
+//	@Param			export_id	path		int	true	"Export ID"
+//	@Success		200			{object}	response.SuccessResponse{data=models.DataExport}
+//	@Failure		401			{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403			{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404			{object}	response.ErrorResponse	"Export not found"
+//	@Router			/admin/users/{id}/exports/{export_id} [get]
+func (h *DataExportHandler) GetUserExport(c *gin.Context) {
+	h.get(c)
+}
+
+// DownloadUserExport godoc
+//
+//	@Summary		Download a user's data export
+//	@Tags			Admin
+//	@Produce		application/zip
+//	@Security		BearerAuth
+//	@Param			id			path		int		true	"User ID"
+//	@Param			export_id	path		int		true	"Export ID"
+//	@Success		200			{file}		binary	"Zip archive of JSON files"
+//	@Failure		401			{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403			{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404			{object}	response.ErrorResponse	"Export not found"
+//	@Failure		409			{object}	response.ErrorResponse	"Export is pending, failed or expired"
+//	@Router			/admin/users/{id}/exports/{export_id}/download [get]
+func (h *DataExportHandler) DownloadUserExport(c *gin.Context) {
+	h.download(c)
+}
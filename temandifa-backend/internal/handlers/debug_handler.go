@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"expvar"
+	"net/http/pprof"
+	"runtime"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	// Expose goroutine count alongside the default cmdline/memstats vars
+	expvar.Publish("goroutines", expvar.Func(func() any {
+		return runtime.NumGoroutine()
+	}))
+}
+
+// Pprof serves the net/http/pprof endpoints under /debug/pprof/*profile.
+// Named profiles (heap, goroutine, allocs, ...) are resolved by pprof.Index.
+func Pprof(c *gin.Context) {
+	switch strings.TrimPrefix(c.Param("profile"), "/") {
+	case "cmdline":
+		pprof.Cmdline(c.Writer, c.Request)
+	case "profile":
+		pprof.Profile(c.Writer, c.Request)
+	case "symbol":
+		pprof.Symbol(c.Writer, c.Request)
+	case "trace":
+		pprof.Trace(c.Writer, c.Request)
+	default:
+		pprof.Index(c.Writer, c.Request)
+	}
+}
+
+// Expvar serves the expvar JSON document (memstats, cmdline, goroutines)
+func Expvar(c *gin.Context) {
+	expvar.Handler().ServeHTTP(c.Writer, c.Request)
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/middleware"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// DeviceHandler lets users register the devices they sign in on
+type DeviceHandler struct {
+	devices services.DeviceService
+}
+
+func NewDeviceHandler(devices services.DeviceService) *DeviceHandler {
+	return &DeviceHandler{devices: devices}
+}
+
+// RegisterDevice godoc
+//
+//	@Summary		Register a device
+//	@Description	Register the app installation, or update it: its platform, Firebase Cloud Messaging token (push notifications; a token registered by another device moves to this one, an omitted one stops pushes) and app version (default X-App-Version). Sign in and refresh with the same ID in X-Device-ID to bind the session to the device.
+//	@Tags			Devices
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.RegisterDeviceRequest	true	"Device"
+//	@Success		200		{object}	response.SuccessResponse{data=models.Device}
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Router			/me/devices [post]
+func (h *DeviceHandler) RegisterDevice(c *gin.Context) {
+	var input dto.RegisterDeviceRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+	if input.AppVersion == "" {
+		if version := c.GetHeader(middleware.AppVersionHeader); len(version) <= 32 {
+			input.AppVersion = version
+		}
+	}
+
+	user := c.MustGet("user").(models.User)
+	device, err := h.devices.Register(c.Request.Context(), user.ID, input)
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			apperrors.RespondError(c, appErr)
+			return
+		}
+		logger.Ctx(c).Error("Failed to register device", zap.Error(err))
+		response.InternalError(c, "Failed to register device")
+		return
+	}
+	response.Success(c, device, "Device registered")
+}
+
+// UnregisterDevice godoc
+//
+//	@Summary		Unregister a device
+//	@Description	Remove the device, stopping its push notifications, and revoke the refresh tokens bound to it, e.g. on sign-out or to sign a lost device out
+//	@Tags			Devices
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			device_id	path		string	true	"Device ID"
+//	@Success		200			{object}	response.SuccessResponse
+//	@Failure		401			{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		404			{object}	response.ErrorResponse	"Device not found"
+//	@Router			/me/devices/{device_id} [delete]
+func (h *DeviceHandler) UnregisterDevice(c *gin.Context) {
+	deviceID := c.Param("device_id")
+	if !helpers.ValidDeviceID(deviceID) {
+		response.NotFound(c, "Device")
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+	found, err := h.devices.Unregister(c.Request.Context(), user.ID, deviceID)
+	if err != nil {
+		logger.Ctx(c).Error("Failed to unregister device", zap.Error(err))
+		response.InternalError(c, "Failed to unregister device")
+		return
+	}
+	if !found {
+		response.NotFound(c, "Device")
+		return
+	}
+	response.Success(c, nil, "Device unregistered")
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"html/template"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// emailChangePage is shown to users opening an email change link in a
+// browser
+var emailChangePage = template.Must(template.New("email_change").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>TemanDifa email change</title></head>
+<body>
+<h1>Email change</h1>
+{{with .Confirmation}}{{if .Changed}}<p>Thank you, your email address is now {{.Email}}. Sign in to the TemanDifa app again with it.</p>
+{{else}}<p>Thank you. The change to {{.PendingEmail}} is made once the link sent to the other address is opened too.</p>{{end}}
+{{else}}<p>This link is invalid or has expired. You can ask for the change again in the TemanDifa app.</p>{{end}}
+</body>
+</html>
+`))
+
+// EmailChangeHandler lets users change their email address, confirmed from
+// both the current and the new address
+type EmailChangeHandler struct {
+	changes services.EmailChangeService
+}
+
+func NewEmailChangeHandler(changes services.EmailChangeService) *EmailChangeHandler {
+	return &EmailChangeHandler{changes: changes}
+}
+
+// RequestEmailChange godoc
+//
+//	@Summary		Change my email address
+//	@Description	Email confirmation links (valid for EMAIL_CHANGE_TTL) to both the current and the new address; the address changes once both were opened, signing out every session. Until then the new address is the account's pending_email. Accounts with a password confirm with it. A new request replaces the pending one. Limited to EMAIL_CHANGE_LIMIT requests per EMAIL_CHANGE_WINDOW.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			input	body		dto.ChangeEmailRequest	true	"New address and password confirmation"
+//	@Success		200		{object}	response.SuccessResponse
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed or unchanged address"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized or wrong password"
+//	@Failure		409		{object}	response.ErrorResponse	"Address already in use"
+//	@Failure		429		{object}	response.ErrorResponse	"Too many requests"
+//	@Router			/auth/change-email [post]
+func (h *EmailChangeHandler) RequestEmailChange(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	var input dto.ChangeEmailRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	if err := h.changes.Request(c.Request.Context(), user.ID, input.NewEmail, input.Password); err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			apperrors.RespondError(c, appErr)
+			return
+		}
+		logger.Ctx(c).Error("Failed to request email change", zap.Uint("user_id", user.ID), zap.Error(err))
+		response.InternalError(c, "Failed to request email change")
+		return
+	}
+	response.Success(c, nil, "Confirmation links sent to both addresses")
+}
+
+// ConfirmEmailChange godoc
+//
+//	@Summary		Confirm an email change
+//	@Description	The links emailed to the current and the new address (usable once). changed tells whether this was the second one, which changed the address. Browsers get an HTML page.
+//	@Tags			Auth
+//	@Produce		json,html
+//	@Param			token	query		string	true	"Confirmation token"
+//	@Success		200		{object}	response.SuccessResponse{data=dto.EmailChangeConfirmation}
+//	@Failure		400		{object}	response.ErrorResponse	"Invalid or expired link"
+//	@Failure		409		{object}	response.ErrorResponse	"Address taken since the request"
+//	@Router			/auth/change-email/confirm [get]
+func (h *EmailChangeHandler) ConfirmEmailChange(c *gin.Context) {
+	confirmation, err := h.changes.Confirm(c.Request.Context(), c.Query("token"))
+	appErr, invalid := apperrors.AsAppError(err)
+	if err != nil && !invalid {
+		logger.Ctx(c).Error("Failed to confirm email change", zap.Error(err))
+		response.InternalError(c, "Failed to confirm email change")
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	if c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) == gin.MIMEHTML {
+		status := http.StatusOK
+		if err != nil {
+			status = appErr.StatusCode
+		}
+		c.Status(status)
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		if err := emailChangePage.Execute(c.Writer, gin.H{"Confirmation": confirmation}); err != nil {
+			logger.Ctx(c).Error("Failed to render email change page", zap.Error(err))
+		}
+		return
+	}
+
+	if err != nil {
+		apperrors.RespondError(c, appErr)
+		return
+	}
+	message := "Email change confirmed"
+	if confirmation.Changed {
+		message = "Email changed"
+	}
+	response.Success(c, confirmation, message)
+}
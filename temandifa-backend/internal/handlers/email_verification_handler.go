@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"html/template"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// verificationPage is shown to users opening their verification link in a
+// browser
+var verificationPage = template.Must(template.New("verification").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>TemanDifa email verification</title></head>
+<body>
+<h1>Email verification</h1>
+{{if .Verification}}<p>Thank you, {{.Verification.Email}} is verified. You can go back to the TemanDifa app.</p>
+{{else}}<p>This link is invalid or has expired. You can ask for a new one in the TemanDifa app.</p>{{end}}
+</body>
+</html>
+`))
+
+// EmailVerificationHandler serves the links that verify users' email
+// addresses
+type EmailVerificationHandler struct {
+	verification services.EmailVerificationService
+}
+
+func NewEmailVerificationHandler(verification services.EmailVerificationService) *EmailVerificationHandler {
+	return &EmailVerificationHandler{verification: verification}
+}
+
+// VerifyEmail godoc
+//
+//	@Summary		Verify an email address
+//	@Description	The link emailed to new users (valid for EMAIL_VERIFICATION_TTL, usable once). Browsers get an HTML page.
+//	@Tags			Auth
+//	@Produce		json,html
+//	@Param			token	query		string	true	"Verification token"
+//	@Success		200		{object}	response.SuccessResponse{data=dto.EmailVerificationResponse}
+//	@Failure		400		{object}	response.ErrorResponse	"Invalid or expired link"
+//	@Router			/verify-email [get]
+func (h *EmailVerificationHandler) VerifyEmail(c *gin.Context) {
+	verification, err := h.verification.Verify(c.Request.Context(), c.Query("token"))
+	appErr, invalid := apperrors.AsAppError(err)
+	if err != nil && !invalid {
+		logger.Ctx(c).Error("Failed to verify email", zap.Error(err))
+		response.InternalError(c, "Failed to verify email")
+		return
+	}
+	if err == nil {
+		logger.Ctx(c).Info("Email verified", zap.Uint("user_id", verification.UserID))
+	}
+
+	c.Header("Cache-Control", "no-store")
+	if c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) == gin.MIMEHTML {
+		status := http.StatusOK
+		if err != nil {
+			status = appErr.StatusCode
+		}
+		c.Status(status)
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		if err := verificationPage.Execute(c.Writer, gin.H{"Verification": verification}); err != nil {
+			logger.Ctx(c).Error("Failed to render verification page", zap.Error(err))
+		}
+		return
+	}
+
+	if err != nil {
+		apperrors.RespondError(c, appErr)
+		return
+	}
+	response.Success(c, verification, "Email verified")
+}
+
+// ResendVerification godoc
+//
+//	@Summary		Resend the verification email
+//	@Description	Email the caller a new verification link; earlier links stay valid until they expire. Limited to one a minute and five a day.
+//	@Tags			Auth
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		409	{object}	response.ErrorResponse	"Email already verified"
+//	@Failure		429	{object}	response.ErrorResponse	"Sent too recently"
+//	@Failure		503	{object}	response.ErrorResponse	"Email could not be sent"
+//	@Router			/verify-email/resend [post]
+func (h *EmailVerificationHandler) ResendVerification(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+	if err := h.verification.Resend(c.Request.Context(), user); err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			apperrors.RespondError(c, appErr)
+			return
+		}
+		logger.Ctx(c).Error("Failed to resend verification email", zap.Error(err))
+		response.InternalError(c, "Failed to resend verification email")
+		return
+	}
+	response.Success(c, nil, "Verification email sent")
+}
@@ -0,0 +1,340 @@
+package handlers
+
+import (
+	"html/template"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// consentPage is shown to contacts opening their consent link in a browser;
+// it posts the answer back to the same URL
+var consentPage = template.Must(template.New("consent").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>TemanDifa emergency contact</title></head>
+<body>
+<h1>Emergency contact</h1>
+{{if .Consent}}<p>Hi {{.Consent.ContactName}}, {{.Consent.RequestedBy}} added you as an emergency contact on TemanDifa.</p>
+<p>If you agree, you will receive their emergency alerts by SMS or email, with their location and surroundings when they share them. You can come back to this page to change your answer.</p>
+{{if eq .Consent.ConsentStatus "accepted"}}<p><strong>You agreed to receive alerts.</strong></p>{{else if eq .Consent.ConsentStatus "declined"}}<p><strong>You declined to receive alerts.</strong></p>{{end}}
+<form method="post">
+<button type="submit" name="decision" value="accept">I agree to receive alerts</button>
+<button type="submit" name="decision" value="decline">No, thanks</button>
+</form>
+{{else}}<p>This link is no longer valid.</p>{{end}}
+</body>
+</html>
+`))
+
+// EmergencyHandler serves the users' emergency contacts, their consent
+// requests and emergency alerts
+type EmergencyHandler struct {
+	emergency services.EmergencyService
+}
+
+func NewEmergencyHandler(emergency services.EmergencyService) *EmergencyHandler {
+	return &EmergencyHandler{emergency: emergency}
+}
+
+// ListContacts godoc
+//
+//	@Summary		List emergency contacts
+//	@Description	The caller's emergency contacts, the primary one first, with whether they agreed to receive alerts (consent_status pending, accepted or declined)
+//	@Tags			Emergency
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=[]models.EmergencyContact}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Router			/me/emergency-contacts [get]
+func (h *EmergencyHandler) ListContacts(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+	contacts, err := h.emergency.ListContacts(c.Request.Context(), user.ID)
+	if err != nil {
+		logger.Ctx(c).Error("Failed to list emergency contacts", zap.Error(err))
+		response.InternalError(c, "Failed to list emergency contacts")
+		return
+	}
+	response.Success(c, contacts)
+}
+
+// CreateContact godoc
+//
+//	@Summary		Add an emergency contact
+//	@Description	Add a contact reached by SMS (phone_number, E.164) and/or email. The contact is sent a link to agree to receive the caller's alerts and gets none until they do; consent_request_channels lists where the request went (empty if it could not be sent, see POST /me/emergency-contacts/{contact_id}/consent-request). Making a contact primary makes the others not.
+//	@Tags			Emergency
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.EmergencyContactRequest	true	"Contact"
+//	@Success		201		{object}	response.SuccessResponse{data=dto.EmergencyContactResult}
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		409		{object}	response.ErrorResponse	"Too many contacts (EMERGENCY_MAX_CONTACTS)"
+//	@Router			/me/emergency-contacts [post]
+func (h *EmergencyHandler) CreateContact(c *gin.Context) {
+	var input dto.EmergencyContactRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+	contact, err := h.emergency.CreateContact(c.Request.Context(), user, input)
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			apperrors.RespondError(c, appErr)
+			return
+		}
+		logger.Ctx(c).Error("Failed to add emergency contact", zap.Error(err))
+		response.InternalError(c, "Failed to add emergency contact")
+		return
+	}
+	response.Created(c, contact, "Emergency contact added")
+}
+
+// UpdateContact godoc
+//
+//	@Summary		Replace an emergency contact
+//	@Description	Replace the contact's details. A changed phone number or email address needs the contact's consent again: they are sent a new consent request and get no alerts until they agree.
+//	@Tags			Emergency
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			contact_id	path		int							true	"Contact ID"
+//	@Param			request		body		dto.EmergencyContactRequest	true	"Contact"
+//	@Success		200			{object}	response.SuccessResponse{data=dto.EmergencyContactResult}
+//	@Failure		400			{object}	response.ErrorResponse	"Validation failed"
+//	@Failure		401			{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		404			{object}	response.ErrorResponse	"Contact not found"
+//	@Router			/me/emergency-contacts/{contact_id} [put]
+func (h *EmergencyHandler) UpdateContact(c *gin.Context) {
+	contactID, err := strconv.ParseUint(c.Param("contact_id"), 10, 32)
+	if err != nil {
+		response.NotFound(c, "Emergency contact")
+		return
+	}
+	var input dto.EmergencyContactRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+	contact, err := h.emergency.UpdateContact(c.Request.Context(), user, uint(contactID), input)
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			apperrors.RespondError(c, appErr)
+			return
+		}
+		logger.Ctx(c).Error("Failed to update emergency contact", zap.Error(err))
+		response.InternalError(c, "Failed to update emergency contact")
+		return
+	}
+	if contact == nil {
+		response.NotFound(c, "Emergency contact")
+		return
+	}
+	response.Success(c, contact, "Emergency contact updated")
+}
+
+// DeleteContact godoc
+//
+//	@Summary		Delete an emergency contact
+//	@Description	Remove the contact; they stop receiving the caller's alerts
+//	@Tags			Emergency
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			contact_id	path		int	true	"Contact ID"
+//	@Success		200			{object}	response.SuccessResponse
+//	@Failure		401			{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		404			{object}	response.ErrorResponse	"Contact not found"
+//	@Router			/me/emergency-contacts/{contact_id} [delete]
+func (h *EmergencyHandler) DeleteContact(c *gin.Context) {
+	contactID, err := strconv.ParseUint(c.Param("contact_id"), 10, 32)
+	if err != nil {
+		response.NotFound(c, "Emergency contact")
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+	found, err := h.emergency.DeleteContact(c.Request.Context(), user.ID, uint(contactID))
+	if err != nil {
+		logger.Ctx(c).Error("Failed to delete emergency contact", zap.Error(err))
+		response.InternalError(c, "Failed to delete emergency contact")
+		return
+	}
+	if !found {
+		response.NotFound(c, "Emergency contact")
+		return
+	}
+	response.Success(c, nil, "Emergency contact deleted")
+}
+
+// RequestConsent godoc
+//
+//	@Summary		Ask an emergency contact for consent again
+//	@Description	Send a new consent request to a contact who hasn't answered, at most once a day. Contacts who agreed or declined are not asked again.
+//	@Tags			Emergency
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			contact_id	path		int	true	"Contact ID"
+//	@Success		200			{object}	response.SuccessResponse{data=dto.EmergencyContactResult}
+//	@Failure		401			{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		404			{object}	response.ErrorResponse	"Contact not found"
+//	@Failure		409			{object}	response.ErrorResponse	"The contact already answered"
+//	@Failure		429			{object}	response.ErrorResponse	"The contact was asked less than a day ago"
+//	@Router			/me/emergency-contacts/{contact_id}/consent-request [post]
+func (h *EmergencyHandler) RequestConsent(c *gin.Context) {
+	contactID, err := strconv.ParseUint(c.Param("contact_id"), 10, 32)
+	if err != nil {
+		response.NotFound(c, "Emergency contact")
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+	contact, err := h.emergency.RequestConsent(c.Request.Context(), user, uint(contactID))
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			apperrors.RespondError(c, appErr)
+			return
+		}
+		logger.Ctx(c).Error("Failed to send consent request", zap.Error(err))
+		response.InternalError(c, "Failed to send consent request")
+		return
+	}
+	if contact == nil {
+		response.NotFound(c, "Emergency contact")
+		return
+	}
+	response.Success(c, contact, "Consent request sent")
+}
+
+// GetConsent godoc
+//
+//	@Summary		Show an emergency contact consent request
+//	@Description	The page of the link sent to a contact: who asked them and their current answer. Browsers get an HTML page with the answer buttons.
+//	@Tags			Emergency
+//	@Produce		json,html
+//	@Param			token	path		string	true	"Consent token"
+//	@Success		200		{object}	response.SuccessResponse{data=dto.EmergencyConsent}
+//	@Failure		404		{object}	response.ErrorResponse	"Consent request not found"
+//	@Router			/emergency-contacts/consent/{token} [get]
+func (h *EmergencyHandler) GetConsent(c *gin.Context) {
+	consent, err := h.emergency.Consent(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		logger.Ctx(c).Error("Failed to load consent request", zap.Error(err))
+		response.InternalError(c, "Failed to load consent request")
+		return
+	}
+	h.renderConsent(c, consent, "")
+}
+
+// AnswerConsent godoc
+//
+//	@Summary		Answer an emergency contact consent request
+//	@Description	Agree or decline to receive the user's emergency alerts, as JSON or a form. The link stays valid, so contacts can withdraw their consent later.
+//	@Tags			Emergency
+//	@Accept			json,x-www-form-urlencoded
+//	@Produce		json,html
+//	@Param			token	path		string								true	"Consent token"
+//	@Param			request	body		dto.AnswerEmergencyConsentRequest	true	"Answer"
+//	@Success		200		{object}	response.SuccessResponse{data=dto.EmergencyConsent}
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed"
+//	@Failure		404		{object}	response.ErrorResponse	"Consent request not found"
+//	@Router			/emergency-contacts/consent/{token} [post]
+func (h *EmergencyHandler) AnswerConsent(c *gin.Context) {
+	var input dto.AnswerEmergencyConsentRequest
+	if err := c.ShouldBind(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	consent, err := h.emergency.AnswerConsent(c.Request.Context(), c.Param("token"), input.Decision == "accept")
+	if err != nil {
+		logger.Ctx(c).Error("Failed to answer consent request", zap.Error(err))
+		response.InternalError(c, "Failed to answer consent request")
+		return
+	}
+	h.renderConsent(c, consent, "Answer recorded")
+}
+
+// renderConsent answers browsers with the consent page, others with JSON
+func (h *EmergencyHandler) renderConsent(c *gin.Context, consent *dto.EmergencyConsent, message string) {
+	c.Header("Cache-Control", "no-store")
+	if c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) == gin.MIMEHTML {
+		status := http.StatusOK
+		if consent == nil {
+			status = http.StatusNotFound
+		}
+		c.Status(status)
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		if err := consentPage.Execute(c.Writer, gin.H{"Consent": consent}); err != nil {
+			logger.Ctx(c).Error("Failed to render consent page", zap.Error(err))
+		}
+		return
+	}
+
+	if consent == nil {
+		response.NotFound(c, "Consent request")
+		return
+	}
+	if message != "" {
+		response.Success(c, consent, message)
+		return
+	}
+	response.Success(c, consent)
+}
+
+// SendAlert godoc
+//
+//	@Summary		Send an emergency alert
+//	@Description	Send an alert by SMS and email to the caller's contacts who agreed to receive alerts, with the location and scene description if given. Without a scene description the latest object detection of the last 15 minutes is used. Limited to EMERGENCY_ALERT_LIMIT alerts per EMERGENCY_ALERT_WINDOW; the response lists the channels each contact was reached on.
+//	@Tags			Emergency
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.EmergencyAlertRequest	true	"Context to share"
+//	@Success		200		{object}	response.SuccessResponse{data=dto.EmergencyAlertResult}
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		409		{object}	response.ErrorResponse	"No contact agreed to receive alerts"
+//	@Failure		429		{object}	response.ErrorResponse	"Too many alerts"
+//	@Router			/emergency [post]
+func (h *EmergencyHandler) SendAlert(c *gin.Context) {
+	var input dto.EmergencyAlertRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+	result, err := h.emergency.SendAlert(c.Request.Context(), user, input)
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			apperrors.RespondError(c, appErr)
+			return
+		}
+		logger.Ctx(c).Error("Failed to send emergency alert", zap.Error(err))
+		response.InternalError(c, "Failed to send emergency alert")
+		return
+	}
+	if result.ContactsNotified == 0 {
+		response.Success(c, result, "The emergency alert could not be delivered")
+		return
+	}
+	response.Success(c, result, "Emergency alert sent")
+}
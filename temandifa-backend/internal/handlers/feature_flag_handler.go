@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// FeatureFlagHandler serves flag evaluation to the app and flag management
+// to administrators
+type FeatureFlagHandler struct {
+	flags services.FeatureFlagService
+}
+
+func NewFeatureFlagHandler(flags services.FeatureFlagService) *FeatureFlagHandler {
+	return &FeatureFlagHandler{
+		flags: flags,
+	}
+}
+
+// respondFlagError answers validation errors as such and anything else as 500
+func respondFlagError(c *gin.Context, err error, message string) {
+	if appErr, ok := apperrors.AsAppError(err); ok {
+		apperrors.RespondError(c, appErr)
+		return
+	}
+	logger.Ctx(c).Error(message, zap.Error(err))
+	response.InternalError(c, message)
+}
+
+// GetFeatures godoc
+//
+//	@Summary		Get feature flags
+//	@Description	Feature flags evaluated for the authenticated user (also returned on login)
+//	@Tags			Features
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=map[string]bool}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Router			/features [get]
+func (h *FeatureFlagHandler) GetFeatures(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+	response.Success(c, h.flags.Evaluate(c.Request.Context(), services.FlagSubject{UserID: user.ID, Email: user.Email}))
+}
+
+// ListFeatureFlags godoc
+//
+//	@Summary		List feature flags
+//	@Description	Persisted feature flags with their overrides
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=[]models.FeatureFlag}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/feature-flags [get]
+func (h *FeatureFlagHandler) ListFeatureFlags(c *gin.Context) {
+	flags, err := h.flags.List(c.Request.Context())
+	if err != nil {
+		logger.Ctx(c).Error("Failed to list feature flags", zap.Error(err))
+		response.InternalError(c, "Failed to list feature flags")
+		return
+	}
+	response.Success(c, flags)
+}
+
+// SaveFeatureFlag godoc
+//
+//	@Summary		Create or update a feature flag
+//	@Description	Set a flag's switch and rollout percentage (stable per user). FEATURE_<NAME> config values take precedence over it.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			name	path		string						true	"Flag name (lower-case letters, digits, underscores)"
+//	@Param			request	body		dto.SaveFeatureFlagRequest	true	"Flag settings"
+//	@Success		200		{object}	response.SuccessResponse{data=models.FeatureFlag}
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/feature-flags/{name} [put]
+func (h *FeatureFlagHandler) SaveFeatureFlag(c *gin.Context) {
+	var input dto.SaveFeatureFlagRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	flag := models.FeatureFlag{
+		Name:              c.Param("name"),
+		Description:       input.Description,
+		Enabled:           input.Enabled,
+		RolloutPercentage: 100,
+	}
+	if input.RolloutPercentage != nil {
+		flag.RolloutPercentage = *input.RolloutPercentage
+	}
+
+	if err := h.flags.Save(c.Request.Context(), &flag); err != nil {
+		respondFlagError(c, err, "Failed to save feature flag")
+		return
+	}
+
+	logger.Ctx(c).Info("Feature flag saved",
+		zap.String("flag", flag.Name),
+		zap.Bool("enabled", flag.Enabled),
+		zap.Int("rollout_percentage", flag.RolloutPercentage),
+	)
+	response.Success(c, flag, "Feature flag saved")
+}
+
+// DeleteFeatureFlag godoc
+//
+//	@Summary		Delete a feature flag
+//	@Description	Remove a flag and its overrides
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			name	path		string	true	"Flag name"
+//	@Success		200		{object}	response.SuccessResponse
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404		{object}	response.ErrorResponse	"Flag not found"
+//	@Router			/admin/feature-flags/{name} [delete]
+func (h *FeatureFlagHandler) DeleteFeatureFlag(c *gin.Context) {
+	found, err := h.flags.Delete(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondFlagError(c, err, "Failed to delete feature flag")
+		return
+	}
+	if !found {
+		response.NotFound(c, "Feature flag")
+		return
+	}
+
+	logger.Ctx(c).Info("Feature flag deleted", zap.String("flag", c.Param("name")))
+	response.Success(c, nil, "Feature flag deleted")
+}
+
+// SetFeatureFlagOverride godoc
+//
+//	@Summary		Override a feature flag
+//	@Description	Force a flag on or off for a user ID or an organization (email domain); overrides win over config and rollout
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			name	path		string								true	"Flag name"
+//	@Param			request	body		dto.SetFeatureFlagOverrideRequest	true	"Override"
+//	@Success		200		{object}	response.SuccessResponse{data=models.FeatureFlagOverride}
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404		{object}	response.ErrorResponse	"Flag not found"
+//	@Router			/admin/feature-flags/{name}/overrides [put]
+func (h *FeatureFlagHandler) SetFeatureFlagOverride(c *gin.Context) {
+	var input dto.SetFeatureFlagOverrideRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	override := models.FeatureFlagOverride{
+		FlagName:    c.Param("name"),
+		SubjectType: input.SubjectType,
+		Subject:     input.Subject,
+		Enabled:     input.Enabled,
+	}
+	found, err := h.flags.SetOverride(c.Request.Context(), &override)
+	if err != nil {
+		respondFlagError(c, err, "Failed to save feature flag override")
+		return
+	}
+	if !found {
+		response.NotFound(c, "Feature flag")
+		return
+	}
+
+	logger.Ctx(c).Info("Feature flag override saved",
+		zap.String("flag", override.FlagName),
+		zap.String("subject_type", override.SubjectType),
+		zap.String("subject", override.Subject),
+		zap.Bool("enabled", override.Enabled),
+	)
+	response.Success(c, override, "Override saved")
+}
+
+// DeleteFeatureFlagOverride godoc
+//
+//	@Summary		Remove a feature flag override
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			name			path		string	true	"Flag name"
+//	@Param			subject_type	path		string	true	"Subject type"	Enums(user, organization)
+//	@Param			subject			path		string	true	"User ID or email domain"
+//	@Success		200				{object}	response.SuccessResponse
+//	@Failure		401				{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403				{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404				{object}	response.ErrorResponse	"Override not found"
+//	@Router			/admin/feature-flags/{name}/overrides/{subject_type}/{subject} [delete]
+func (h *FeatureFlagHandler) DeleteFeatureFlagOverride(c *gin.Context) {
+	found, err := h.flags.DeleteOverride(c.Request.Context(), c.Param("name"), c.Param("subject_type"), c.Param("subject"))
+	if err != nil {
+		respondFlagError(c, err, "Failed to delete feature flag override")
+		return
+	}
+	if !found {
+		response.NotFound(c, "Override")
+		return
+	}
+	response.Success(c, nil, "Override removed")
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/gin-gonic/gin"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"temandifa-backend/internal/graphqlapi"
+	"temandifa-backend/internal/models"
+)
+
+// maxGraphQLQueryLength bounds the query text; the home screen query is well
+// under 2 KB
+const maxGraphQLQueryLength = 10000
+
+// GraphQLHandler serves the GraphQL API
+type GraphQLHandler struct {
+	api *graphqlapi.API
+}
+
+func NewGraphQLHandler(api *graphqlapi.API) *GraphQLHandler {
+	return &GraphQLHandler{api: api}
+}
+
+// Query godoc
+//
+//	@Summary		Run a GraphQL query
+//	@Description	Runs a query of the schema at GET /graphql/schema: the caller's profile, history with filters and usage in one request. Field errors are reported in errors with the REST error code in extensions.code, next to the data that could be resolved. Mutations and introspection are not supported.
+//	@Tags			GraphQL
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		graphql.RawParams	true	"Query, operation name and variables"
+//	@Success		200		{object}	graphql.Response	"Data and field errors"
+//	@Failure		400		{object}	graphql.Response	"Malformed request"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Router			/graphql [post]
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	var req graphql.RawParams
+	if err := c.ShouldBindJSON(&req); err != nil || req.Query == "" {
+		c.JSON(http.StatusBadRequest, graphqlError("Request body must be a JSON object with a query"))
+		return
+	}
+	if len(req.Query) > maxGraphQLQueryLength {
+		c.JSON(http.StatusBadRequest, graphqlError("Query is too long"))
+		return
+	}
+	req.Headers = c.Request.Header
+
+	user := c.MustGet("user").(models.User)
+	c.JSON(http.StatusOK, h.api.Execute(c.Request.Context(), user, &req))
+}
+
+// Schema godoc
+//
+//	@Summary		Get the GraphQL schema
+//	@Description	The schema in the GraphQL schema definition language, for client code generation
+//	@Tags			GraphQL
+//	@Produce		plain
+//	@Security		BearerAuth
+//	@Success		200	{string}	string	"Schema"
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Router			/graphql/schema [get]
+func (h *GraphQLHandler) Schema(c *gin.Context) {
+	c.String(http.StatusOK, graphqlapi.SDL())
+}
+
+func graphqlError(message string) graphql.Response {
+	return graphql.Response{Errors: gqlerror.List{{Message: message}}}
+}
@@ -5,146 +5,99 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/redis/go-redis/v9"
-	"gorm.io/gorm"
 
 	"temandifa-backend/internal/dto"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
 )
 
-// HealthHandler handles health check requests
+// HealthHandler handles health check requests.
+// Dependency status comes from the background HealthChecker, so probes
+// respond instantly and never hammer Postgres, Redis, or the AI Service.
 type HealthHandler struct {
-	db           *gorm.DB
-	redis        *redis.Client
-	AIServiceURL string
+	checker *services.HealthChecker
 }
 
-// Version info - can be set via ldflags at build time
+// Version info - set via ldflags at build time (see Makefile and Dockerfile)
 var (
 	AppVersion = "1.0.0"
 	BuildTime  = "unknown"
 	GitCommit  = "unknown"
 )
 
-func NewHealthHandler(db *gorm.DB, redis *redis.Client, aiServiceURL string) *HealthHandler {
-	return &HealthHandler{
-		db:           db,
-		redis:        redis,
-		AIServiceURL: aiServiceURL,
-	}
+func NewHealthHandler(checker *services.HealthChecker) *HealthHandler {
+	return &HealthHandler{checker: checker}
 }
 
 // CheckHealth handles health check requests
 //
 //	@Summary		Health Check
-//	@Description	Get comprehensive health status of all services with latency info
+//	@Description	Get cached health status of all components with latency, last transition, and optional history
 //	@Tags			Health
 //	@Produce		json
-//	@Success		200	{object}	dto.HealthResponse
-//	@Failure		503	{object}	dto.HealthResponse
+//	@Param			history	query		bool	false	"Include recent check history per component"
+//	@Success		200		{object}	dto.HealthResponse
+//	@Failure		503		{object}	dto.HealthResponse
 //	@Router			/health [get]
 func (h *HealthHandler) CheckHealth(c *gin.Context) {
-	response := dto.HealthResponse{
+	health := dto.HealthResponse{
 		Status:    "healthy",
 		Timestamp: time.Now().Format(time.RFC3339),
 		Version:   AppVersion,
-		Checks:    make(map[string]dto.HealthCheck),
+		Checks:    h.checker.Snapshot(c.Query("history") == "true"),
 	}
 
 	statusCode := http.StatusOK
-
-	// Check PostgreSQL with latency
-	pgStart := time.Now()
-	if h.db != nil {
-		sqlDB, err := h.db.DB()
-		if err != nil {
-			response.Checks["postgres"] = dto.HealthCheck{
-				Status:    "unhealthy",
-				LatencyMs: time.Since(pgStart).Milliseconds(),
-				Message:   err.Error(),
-			}
-			response.Status = "degraded"
-			statusCode = http.StatusServiceUnavailable
-		} else if err := sqlDB.Ping(); err != nil {
-			response.Checks["postgres"] = dto.HealthCheck{
-				Status:    "unhealthy",
-				LatencyMs: time.Since(pgStart).Milliseconds(),
-				Message:   err.Error(),
-			}
-			response.Status = "degraded"
-			statusCode = http.StatusServiceUnavailable
-		} else {
-			response.Checks["postgres"] = dto.HealthCheck{
-				Status:    "healthy",
-				LatencyMs: time.Since(pgStart).Milliseconds(),
-			}
-		}
-	} else {
-		response.Checks["postgres"] = dto.HealthCheck{
-			Status:  "unhealthy",
-			Message: "connection not initialized",
-		}
-		response.Status = "degraded"
+	if !h.checker.Ready() {
+		// A critical component (PostgreSQL) is down - the service is unavailable
+		health.Status = "degraded"
 		statusCode = http.StatusServiceUnavailable
+	} else if h.checker.Degraded() {
+		health.Status = "degraded"
 	}
 
-	// Check Redis with latency
-	redisStart := time.Now()
-	if h.redis != nil {
-		if _, err := h.redis.Ping(c).Result(); err != nil {
-			response.Checks["redis"] = dto.HealthCheck{
-				Status:    "unhealthy",
-				LatencyMs: time.Since(redisStart).Milliseconds(),
-				Message:   err.Error(),
-			}
-			if response.Status != "degraded" {
-				response.Status = "degraded"
-			}
-		} else {
-			response.Checks["redis"] = dto.HealthCheck{
-				Status:    "healthy",
-				LatencyMs: time.Since(redisStart).Milliseconds(),
-			}
-		}
-	} else {
-		response.Checks["redis"] = dto.HealthCheck{
-			Status:  "not_configured",
-			Message: "Redis not connected",
-		}
+	response.Render(c, statusCode, health)
+}
+
+// Liveness reports whether the process is alive
+//
+//	@Summary		Liveness probe
+//	@Description	Cheap check that the process is running; never touches dependencies
+//	@Tags			Health
+//	@Produce		json
+//	@Success		200	{object}	map[string]string
+//	@Router			/healthz [get]
+func (h *HealthHandler) Liveness(c *gin.Context) {
+	response.Render(c, http.StatusOK, gin.H{"status": "alive"})
+}
+
+// Readiness reports whether the instance can serve traffic.
+// Only critical components (PostgreSQL) are required; Redis and the AI Service
+// are reported but do not fail the probe, so a degraded AI Service never takes
+// pods out of rotation.
+//
+//	@Summary		Readiness probe
+//	@Description	Check required dependencies (served from the background health checker)
+//	@Tags			Health
+//	@Produce		json
+//	@Success		200	{object}	dto.HealthResponse
+//	@Failure		503	{object}	dto.HealthResponse
+//	@Router			/readyz [get]
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	result := dto.HealthResponse{
+		Status:    "ready",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Version:   AppVersion,
+		Checks:    h.checker.Snapshot(false),
 	}
 
-	// Check AI Service with latency
-	aiStart := time.Now()
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Get(h.AIServiceURL + "/health")
-	if err != nil {
-		response.Checks["ai_service"] = dto.HealthCheck{
-			Status:    "unhealthy",
-			LatencyMs: time.Since(aiStart).Milliseconds(),
-			Message:   err.Error(),
-		}
-		if response.Status != "degraded" {
-			response.Status = "degraded"
-		}
-	} else {
-		defer func() {
-			_ = resp.Body.Close()
-		}()
-		if resp.StatusCode == http.StatusOK {
-			response.Checks["ai_service"] = dto.HealthCheck{
-				Status:    "healthy",
-				LatencyMs: time.Since(aiStart).Milliseconds(),
-			}
-		} else {
-			response.Checks["ai_service"] = dto.HealthCheck{
-				Status:    "unhealthy",
-				LatencyMs: time.Since(aiStart).Milliseconds(),
-				Message:   "HTTP " + resp.Status,
-			}
-			if response.Status != "degraded" {
-				response.Status = "degraded"
-			}
+	if !h.checker.Ready() {
+		result.Status = "not_ready"
+		if h.checker.Draining() {
+			result.Status = "draining"
 		}
+		response.Render(c, http.StatusServiceUnavailable, result)
+		return
 	}
-
-	c.JSON(statusCode, response)
+	response.Render(c, http.StatusOK, result)
 }
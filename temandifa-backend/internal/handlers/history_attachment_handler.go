@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// HistoryAttachmentHandler serves the images and recordings attached to
+// history entries
+type HistoryAttachmentHandler struct {
+	attachments services.AttachmentService
+	media       services.MediaService
+	uploads     *config.UploadConfig
+}
+
+func NewHistoryAttachmentHandler(attachments services.AttachmentService, media services.MediaService, uploads *config.UploadConfig) *HistoryAttachmentHandler {
+	return &HistoryAttachmentHandler{attachments: attachments, media: media, uploads: uploads}
+}
+
+// HistoryAttachmentResponse is an attachment with short-lived URLs that
+// download it and, for images, its thumbnail. Recordings in WAV carry their
+// waveform instead: peak levels 0-100 across the recording.
+//
+//	@Description	History attachment with signed download URLs
+type HistoryAttachmentResponse struct {
+	models.HistoryAttachment
+	Download  dto.SignedURL  `json:"download"`
+	Thumbnail *dto.SignedURL `json:"thumbnail,omitempty"`
+	Waveform  []int          `json:"waveform,omitempty" example:"0,12,57,100,43"`
+}
+
+// attachmentViews signs the URLs of attachments
+func attachmentViews(ctx context.Context, media services.MediaService, attachments []models.HistoryAttachment) ([]HistoryAttachmentResponse, error) {
+	results := make([]HistoryAttachmentResponse, 0, len(attachments))
+	for i := range attachments {
+		attachment := &attachments[i]
+		download, err := media.AttachmentURL(ctx, attachment)
+		if err != nil {
+			return nil, err
+		}
+		thumbnail, err := media.ThumbnailURL(ctx, attachment)
+		if err != nil {
+			return nil, err
+		}
+		result := HistoryAttachmentResponse{HistoryAttachment: *attachment, Download: download, Thumbnail: thumbnail}
+		if attachment.Waveform != "" {
+			_ = json.Unmarshal([]byte(attachment.Waveform), &result.Waveform)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// withURL signs the URLs of an attachment
+func (h *HistoryAttachmentHandler) withURL(c *gin.Context, attachment *models.HistoryAttachment) (HistoryAttachmentResponse, bool) {
+	results, err := attachmentViews(c.Request.Context(), h.media, []models.HistoryAttachment{*attachment})
+	if err != nil {
+		respondAttachmentError(c, err, "Failed to sign attachment URL")
+		return HistoryAttachmentResponse{}, false
+	}
+	return results[0], true
+}
+
+func respondAttachmentError(c *gin.Context, err error, message string) {
+	if appErr, ok := apperrors.AsAppError(err); ok {
+		apperrors.RespondError(c, appErr)
+		return
+	}
+	logger.Ctx(c).Error(message, zap.Error(err))
+	response.InternalError(c, message)
+}
+
+// attachmentUpload reads and validates the file part. Its detected type picks
+// the limits: images those of detect, recordings those of transcribe.
+func (h *HistoryAttachmentHandler) attachmentUpload(c *gin.Context) (string, *helpers.UploadedFile, bool) {
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "No file uploaded")
+		return "", nil, false
+	}
+	defer func() { _ = file.Close() }()
+
+	sniff := make([]byte, 512)
+	n, _ := io.ReadFull(file, sniff)
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		response.BadRequest(c, "Failed to read file")
+		return "", nil, false
+	}
+	mimeType := http.DetectContentType(sniff[:n])
+
+	kind, operation := models.AttachmentImage, config.UploadOpDetect
+	if h.uploads.For(config.UploadOpTranscribe).AllowedTypes[mimeType] {
+		kind, operation = models.AttachmentAudio, config.UploadOpTranscribe
+	}
+	limit := h.uploads.For(operation)
+	validate := helpers.ValidateImageUpload
+	if kind == models.AttachmentAudio {
+		validate = helpers.ValidateAudioUpload
+	}
+	uploadedFile, err := validate(c.Request.Context(), header, file, limit.MaxSize, limit.AllowedTypes)
+	if err != nil {
+		respondInvalidUpload(c, err)
+		return "", nil, false
+	}
+	return kind, uploadedFile, true
+}
+
+// AttachToHistory godoc
+//
+//	@Summary		Attach a file to a history entry
+//	@Description	Store an image (UPLOAD_DETECT_* limits) or recording (UPLOAD_TRANSCRIBE_* limits) with a history entry, at most HISTORY_ATTACHMENT_MAX_PER_ENTRY each. Attachments are deleted with their entry. Needs object storage.
+//	@Tags			History
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int		true	"History ID"
+//	@Param			file	formData	file	true	"Image or audio file"
+//	@Success		201		{object}	response.SuccessResponse{data=HistoryAttachmentResponse}
+//	@Failure		400		{object}	response.ErrorResponse	"Missing, too large or unsupported file"
+//	@Failure		404		{object}	response.ErrorResponse	"History entry not found"
+//	@Failure		409		{object}	response.ErrorResponse	"The entry has the maximum number of attachments"
+//	@Failure		422		{object}	response.ErrorResponse	"Malware detected (MALWARE_SCAN_DRIVER)"
+//	@Failure		503		{object}	response.ErrorResponse	"Object storage not configured"
+//	@Router			/history/{id}/attachments [post]
+func (h *HistoryAttachmentHandler) AttachToHistory(c *gin.Context) {
+	kind, file, ok := h.attachmentUpload(c)
+	if !ok {
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+	attachment, err := h.attachments.Attach(c.Request.Context(), user.ID, c.Param("id"), kind, file)
+	if err != nil {
+		respondAttachmentError(c, err, "Failed to store attachment")
+		return
+	}
+	result, ok := h.withURL(c, attachment)
+	if !ok {
+		return
+	}
+	response.Created(c, result, "Attachment stored")
+}
+
+// ListHistoryAttachments godoc
+//
+//	@Summary		List the attachments of a history entry
+//	@Description	The attachments, oldest first, each with a URL that downloads it for MEDIA_URL_TTL, a thumbnail URL (images) or a waveform (WAV recordings)
+//	@Tags			History
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"History ID"
+//	@Success		200	{object}	response.SuccessResponse{data=[]HistoryAttachmentResponse}
+//	@Failure		404	{object}	response.ErrorResponse	"History entry not found"
+//	@Router			/history/{id}/attachments [get]
+func (h *HistoryAttachmentHandler) ListHistoryAttachments(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+	attachments, err := h.attachments.List(c.Request.Context(), user.ID, c.Param("id"))
+	if err != nil {
+		respondAttachmentError(c, err, "Failed to list attachments")
+		return
+	}
+	results, err := attachmentViews(c.Request.Context(), h.media, attachments)
+	if err != nil {
+		respondAttachmentError(c, err, "Failed to sign attachment URL")
+		return
+	}
+	response.Success(c, results)
+}
+
+// DeleteHistoryAttachment godoc
+//
+//	@Summary		Delete an attachment
+//	@Tags			History
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id				path		int	true	"History ID"
+//	@Param			attachment_id	path		int	true	"Attachment ID"
+//	@Success		200				{object}	response.SuccessResponse
+//	@Failure		404				{object}	response.ErrorResponse	"History entry or attachment not found"
+//	@Router			/history/{id}/attachments/{attachment_id} [delete]
+func (h *HistoryAttachmentHandler) DeleteHistoryAttachment(c *gin.Context) {
+	attachmentID, err := strconv.ParseUint(c.Param("attachment_id"), 10, 64)
+	if err != nil {
+		response.NotFound(c, "Attachment")
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+	deleted, err := h.attachments.Delete(c.Request.Context(), user.ID, c.Param("id"), attachmentID)
+	if err != nil {
+		respondAttachmentError(c, err, "Failed to delete attachment")
+		return
+	}
+	if !deleted {
+		response.NotFound(c, "Attachment")
+		return
+	}
+	response.Success(c, nil, "Attachment deleted")
+}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
@@ -13,14 +15,29 @@ import (
 
 type HistoryHandler struct {
 	historyService services.HistoryService
+	attachments    services.AttachmentService
+	media          services.MediaService
+	events         services.EventService
 }
 
-func NewHistoryHandler(historyService services.HistoryService) *HistoryHandler {
+func NewHistoryHandler(historyService services.HistoryService, attachments services.AttachmentService, media services.MediaService, events services.EventService) *HistoryHandler {
 	return &HistoryHandler{
 		historyService: historyService,
+		attachments:    attachments,
+		media:          media,
+		events:         events,
 	}
 }
 
+// HistoryEntryResponse is a history entry with its attachments, whose
+// thumbnails and waveforms let the list show previews
+//
+//	@Description	History entry with its attachments
+type HistoryEntryResponse struct {
+	models.History
+	Attachments []HistoryAttachmentResponse `json:"attachments"`
+}
+
 // CreateHistoryInput represents history creation request
 //
 //	@Description	History creation input
@@ -33,14 +50,14 @@ type CreateHistoryInput struct {
 // GetUserHistory godoc
 //
 //	@Summary		Get user history
-//	@Description	Get paginated history for authenticated user
+//	@Description	Get paginated history for authenticated user. Entries include their attachments with signed URLs (see GET /history/{id}/attachments).
 //	@Tags			History
 //	@Accept			json
 //	@Produce		json
 //	@Security		BearerAuth
 //	@Param			page	query		int	false	"Page number"			default(1)
 //	@Param			limit	query		int	false	"Items per page (max 100)"	default(20)
-//	@Success		200		{object}	response.SuccessResponse{data=[]models.History,meta=helpers.Pagination}
+//	@Success		200		{object}	response.SuccessResponse{data=[]HistoryEntryResponse,meta=helpers.Pagination}
 //	@Failure		401		{object}	response.ErrorResponse
 //	@Failure		500		{object}	response.ErrorResponse
 //	@Router			/history [get]
@@ -50,25 +67,52 @@ func (h *HistoryHandler) GetUserHistory(c *gin.Context) {
 	// Use pagination helper
 	pagination := helpers.NewPagination(c)
 
-	histories, total, err := h.historyService.GetUserHistory(user.ID, pagination.Page, pagination.Limit)
+	histories, total, err := h.historyService.GetUserHistory(c.Request.Context(), user.ID, pagination.Page, pagination.Limit)
 	if err != nil {
-		logger.Error("Failed to fetch history",
+		logger.Ctx(c).Error("Failed to fetch history",
 			zap.Error(err),
-			zap.Uint("user_id", user.ID),
 		)
 		response.InternalError(c, "Failed to fetch history")
 		return
 	}
 
+	entries, err := h.withAttachments(c.Request.Context(), user.ID, histories)
+	if err != nil {
+		logger.Ctx(c).Error("Failed to fetch history attachments", zap.Error(err))
+		response.InternalError(c, "Failed to fetch history")
+		return
+	}
+
 	pagination.SetTotal(total)
 
-	logger.Debug("History fetched",
-		zap.Uint("user_id", user.ID),
+	logger.Ctx(c).Debug("History fetched",
 		zap.Int("count", len(histories)),
 		zap.Int("page", pagination.Page),
 	)
 
-	response.SuccessWithMeta(c, histories, pagination.ToMeta())
+	response.SuccessWithMeta(c, entries, pagination.ToMeta())
+}
+
+// withAttachments adds their attachments to a page of entries
+func (h *HistoryHandler) withAttachments(ctx context.Context, userID uint, histories []models.History) ([]HistoryEntryResponse, error) {
+	ids := make([]uint, len(histories))
+	for i, history := range histories {
+		ids[i] = history.ID
+	}
+	byEntry, err := h.attachments.ListForEntries(ctx, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]HistoryEntryResponse, 0, len(histories))
+	for _, history := range histories {
+		attachments, err := attachmentViews(ctx, h.media, byEntry[history.ID])
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, HistoryEntryResponse{History: history, Attachments: attachments})
+	}
+	return entries, nil
 }
 
 // CreateHistory godoc
@@ -90,7 +134,7 @@ func (h *HistoryHandler) CreateHistory(c *gin.Context) {
 
 	var input CreateHistoryInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		logger.Debug("History creation validation failed", zap.Error(err))
+		logger.Ctx(c).Debug("History creation validation failed", zap.Error(err))
 		response.BadRequest(c, "Validation failed", err.Error())
 		return
 	}
@@ -102,22 +146,29 @@ func (h *HistoryHandler) CreateHistory(c *gin.Context) {
 		ResultText:  input.ResultText,
 	}
 
-	createdHistory, err := h.historyService.CreateHistory(history)
+	createdHistory, err := h.historyService.CreateHistory(c.Request.Context(), history)
 	if err != nil {
-		logger.Error("Failed to save history",
+		logger.Ctx(c).Error("Failed to save history",
 			zap.Error(err),
-			zap.Uint("user_id", user.ID),
 		)
 		response.InternalError(c, "Failed to save history")
 		return
 	}
 
-	logger.Info("History saved",
-		zap.Uint("user_id", user.ID),
+	logger.Ctx(c).Info("History saved",
 		zap.Uint("history_id", createdHistory.ID),
 		zap.String("feature_type", input.FeatureType),
 	)
 
+	h.events.Emit(c.Request.Context(), services.Event{
+		Type:   services.EventHistoryCreated,
+		UserID: user.ID,
+		Properties: map[string]any{
+			"history_id":   createdHistory.ID,
+			"feature_type": input.FeatureType,
+		},
+	})
+
 	response.Created(c, createdHistory, "History saved successfully")
 }
 
@@ -138,9 +189,9 @@ func (h *HistoryHandler) DeleteHistory(c *gin.Context) {
 	user := c.MustGet("user").(models.User)
 	historyID := c.Param("id")
 
-	found, err := h.historyService.DeleteHistory(user.ID, historyID)
+	found, err := h.historyService.DeleteHistory(c.Request.Context(), user.ID, historyID)
 	if err != nil {
-		logger.Error("Failed to delete history", zap.Error(err))
+		logger.Ctx(c).Error("Failed to delete history", zap.Error(err))
 		response.InternalError(c, "Failed to delete history")
 		return
 	}
@@ -150,9 +201,8 @@ func (h *HistoryHandler) DeleteHistory(c *gin.Context) {
 		return
 	}
 
-	logger.Info("History deleted",
+	logger.Ctx(c).Info("History deleted",
 		zap.String("history_id", historyID),
-		zap.Uint("user_id", user.ID),
 	)
 
 	response.Success(c, nil, "History deleted successfully")
@@ -172,15 +222,14 @@ func (h *HistoryHandler) DeleteHistory(c *gin.Context) {
 func (h *HistoryHandler) ClearUserHistory(c *gin.Context) {
 	user := c.MustGet("user").(models.User)
 
-	deletedCount, err := h.historyService.ClearUserHistory(user.ID)
+	deletedCount, err := h.historyService.ClearUserHistory(c.Request.Context(), user.ID)
 	if err != nil {
-		logger.Error("Failed to clear history", zap.Error(err))
+		logger.Ctx(c).Error("Failed to clear history", zap.Error(err))
 		response.InternalError(c, "Failed to clear history")
 		return
 	}
 
-	logger.Info("All history cleared",
-		zap.Uint("user_id", user.ID),
+	logger.Ctx(c).Info("All history cleared",
 		zap.Int64("deleted", deletedCount),
 	)
 
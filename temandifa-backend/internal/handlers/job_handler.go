@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// JobHandler lets administrators run background jobs without waiting for
+// their schedule
+type JobHandler struct {
+	jobs services.JobService
+}
+
+func NewJobHandler(jobs services.JobService) *JobHandler {
+	return &JobHandler{jobs: jobs}
+}
+
+// ListJobs godoc
+//
+//	@Summary		List manual jobs
+//	@Description	Background jobs that can be triggered manually (backup only when BACKUP_ENABLED)
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=[]string}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/jobs [get]
+func (h *JobHandler) ListJobs(c *gin.Context) {
+	response.Success(c, h.jobs.Names())
+}
+
+// RunJob godoc
+//
+//	@Summary		Run a background job now
+//	@Description	Start token_cleanup, user_purge, inbox_purge, usage_reports (regenerates last month's) or backup in the background; poll the returned run for its outcome
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			name	path		string	true	"Job name"
+//	@Success		202		{object}	response.SuccessResponse{data=models.JobRun}
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404		{object}	response.ErrorResponse	"Job not found"
+//	@Failure		409		{object}	response.ErrorResponse	"Job is already running"
+//	@Router			/admin/jobs/{name}/run [post]
+func (h *JobHandler) RunJob(c *gin.Context) {
+	run, err := h.jobs.Run(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			apperrors.RespondError(c, appErr)
+			return
+		}
+		logger.Ctx(c).Error("Failed to start job", zap.String("job", c.Param("name")), zap.Error(err))
+		response.InternalError(c, "Failed to start job")
+		return
+	}
+	if run == nil {
+		response.NotFound(c, "Job")
+		return
+	}
+
+	logger.Ctx(c).Info("Job triggered", zap.String("job", run.Name), zap.Uint64("run_id", run.ID))
+	response.Accepted(c, run, "Job started")
+}
+
+// GetJobRun godoc
+//
+//	@Summary		Get a job run
+//	@Description	Status, result and error of a manually triggered job run
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Run ID"
+//	@Success		200	{object}	response.SuccessResponse{data=models.JobRun}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404	{object}	response.ErrorResponse	"Job run not found"
+//	@Router			/admin/jobs/runs/{id} [get]
+func (h *JobHandler) GetJobRun(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.NotFound(c, "Job run")
+		return
+	}
+
+	run, err := h.jobs.Find(c.Request.Context(), id)
+	if err != nil {
+		logger.Ctx(c).Error("Failed to load job run", zap.Error(err))
+		response.InternalError(c, "Failed to load job run")
+		return
+	}
+	if run == nil {
+		response.NotFound(c, "Job run")
+		return
+	}
+	response.Success(c, run)
+}
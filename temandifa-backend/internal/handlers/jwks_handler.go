@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"temandifa-backend/internal/services"
+)
+
+// JWKSHandler publishes the public keys access tokens are signed with, so
+// other services (the AI service) can verify them on their own
+type JWKSHandler struct {
+	tokens services.TokenService
+}
+
+func NewJWKSHandler(tokens services.TokenService) *JWKSHandler {
+	return &JWKSHandler{tokens: tokens}
+}
+
+// GetJWKS godoc
+//
+//	@Summary		Access token signing keys
+//	@Description	The JSON Web Key Set of the public keys access tokens are signed with (JWT_ALGORITHM RS256 or EdDSA), by kid; empty with HS256. Services verifying tokens on their own don't see forced logouts or signed-out sessions, which only the API checks.
+//	@Tags			Auth
+//	@Produce		json
+//	@Success		200	{object}	dto.JWKS
+//	@Router			/.well-known/jwks.json [get]
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	// Short enough for consumers to pick up a new signing key before it is
+	// switched to
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.tokens.JWKS())
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// KillSwitchHandler lets administrators switch AI operations off and on
+type KillSwitchHandler struct {
+	switches services.KillSwitchService
+}
+
+func NewKillSwitchHandler(switches services.KillSwitchService) *KillSwitchHandler {
+	return &KillSwitchHandler{switches: switches}
+}
+
+// respondKillSwitchError answers validation errors as such and anything else as 500
+func respondKillSwitchError(c *gin.Context, err error, message string) {
+	if appErr, ok := apperrors.AsAppError(err); ok {
+		apperrors.RespondError(c, appErr)
+		return
+	}
+	logger.Ctx(c).Error(message, zap.Error(err))
+	response.InternalError(c, message)
+}
+
+// ListKillSwitches godoc
+//
+//	@Summary		List AI kill switches
+//	@Description	Every AI operation; disabled ones carry the reason, who disabled them and when
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=[]dto.KillSwitchStatus}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/kill-switches [get]
+func (h *KillSwitchHandler) ListKillSwitches(c *gin.Context) {
+	response.Success(c, h.switches.List())
+}
+
+// DisableOperation godoc
+//
+//	@Summary		Disable an AI operation
+//	@Description	Requests to the operation get 503 FEATURE_DISABLED with the reason until it is enabled again
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			operation	path		string						true	"Operation (detect, ocr, transcribe, ask, simplify)"
+//	@Param			request		body		dto.DisableOperationRequest	true	"Reason"
+//	@Success		200			{object}	response.SuccessResponse{data=models.KillSwitch}
+//	@Failure		400			{object}	response.ErrorResponse	"Validation failed or unknown operation"
+//	@Failure		401			{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403			{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/kill-switches/{operation} [put]
+func (h *KillSwitchHandler) DisableOperation(c *gin.Context) {
+	var input dto.DisableOperationRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	sw, err := h.switches.Disable(c.Request.Context(), c.Param("operation"), input.Reason)
+	if err != nil {
+		respondKillSwitchError(c, err, "Failed to disable operation")
+		return
+	}
+
+	logger.Ctx(c).Warn("AI operation disabled", zap.String("operation", sw.Operation), zap.String("reason", sw.Reason))
+	response.Success(c, sw, "Operation disabled")
+}
+
+// EnableOperation godoc
+//
+//	@Summary		Enable an AI operation
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			operation	path		string	true	"Operation (detect, ocr, transcribe, ask, simplify)"
+//	@Success		200			{object}	response.SuccessResponse
+//	@Failure		400			{object}	response.ErrorResponse	"Unknown operation"
+//	@Failure		401			{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403			{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404			{object}	response.ErrorResponse	"Operation isn't disabled"
+//	@Router			/admin/kill-switches/{operation} [delete]
+func (h *KillSwitchHandler) EnableOperation(c *gin.Context) {
+	enabled, err := h.switches.Enable(c.Request.Context(), c.Param("operation"))
+	if err != nil {
+		respondKillSwitchError(c, err, "Failed to enable operation")
+		return
+	}
+	if !enabled {
+		response.NotFound(c, "Kill switch")
+		return
+	}
+
+	logger.Ctx(c).Info("AI operation enabled", zap.String("operation", c.Param("operation")))
+	response.Success(c, nil, "Operation enabled")
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// MagicLinkHandler serves passwordless sign-in through emailed links
+type MagicLinkHandler struct {
+	links  services.MagicLinkService
+	events services.EventService
+	flags  services.FeatureFlagService
+}
+
+func NewMagicLinkHandler(links services.MagicLinkService, events services.EventService, flags services.FeatureFlagService) *MagicLinkHandler {
+	return &MagicLinkHandler{links: links, events: events, flags: flags}
+}
+
+// RequestMagicLink godoc
+//
+//	@Summary		Ask for a sign-in link
+//	@Description	Email a single-use sign-in link (valid for MAGIC_LINK_TTL) if an account has the address, for signing in without a password. The answer is the same either way. Each address may ask MAGIC_LINK_LIMIT times per MAGIC_LINK_WINDOW.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			input	body		dto.MagicLinkRequest	true	"Account email"
+//	@Success		200		{object}	response.SuccessResponse
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed"
+//	@Failure		429		{object}	response.ErrorResponse	"Too many requests for the address"
+//	@Router			/auth/magic-link [post]
+func (h *MagicLinkHandler) RequestMagicLink(c *gin.Context) {
+	var input dto.MagicLinkRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	if err := h.links.Request(c.Request.Context(), input.Email); err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			apperrors.RespondError(c, appErr)
+			return
+		}
+		logger.Ctx(c).Error("Failed to process sign-in link request", zap.Error(err))
+		response.InternalError(c, "Failed to process sign-in link request")
+		return
+	}
+	response.Success(c, nil, "If an account uses this email, a sign-in link was sent to it")
+}
+
+// VerifyMagicLink godoc
+//
+//	@Summary		Sign in with a sign-in link
+//	@Description	Exchange the token of an emailed sign-in link for a token pair. The link is used up, along with the account's other sign-in links, and verifies the account's email. With X-Device-ID the refresh token is bound to the device, like /login.
+//	@Tags			Auth
+//	@Produce		json
+//	@Param			X-Device-ID	header		string	false	"App installation ID"
+//	@Param			token		query		string	true	"Sign-in token"
+//	@Success		200			{object}	response.SuccessResponse{data=dto.LoginResponse}
+//	@Failure		400			{object}	response.ErrorResponse	"Invalid or expired link"
+//	@Router			/auth/magic-link/verify [get]
+func (h *MagicLinkHandler) VerifyMagicLink(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+
+	tokenResponse, err := h.links.SignIn(c.Request.Context(), c.Query("token"), c.GetHeader("User-Agent"), c.ClientIP(), requestDeviceID(c))
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			apperrors.RespondError(c, appErr)
+			return
+		}
+		logger.Ctx(c).Error("Sign-in link failed", zap.Error(err))
+		response.InternalError(c, "Sign-in failed")
+		return
+	}
+
+	logger.Ctx(c).Info("User signed in with a sign-in link", zap.Uint("user_id", tokenResponse.User.ID))
+
+	h.events.Emit(c.Request.Context(), services.Event{
+		Type:       services.EventLogin,
+		UserID:     tokenResponse.User.ID,
+		Properties: map[string]any{"method": "magic_link"},
+	})
+
+	tokenResponse.Features = h.flags.Evaluate(c.Request.Context(), services.FlagSubject{
+		UserID: tokenResponse.User.ID,
+		Email:  tokenResponse.User.Email,
+	})
+
+	response.Success(c, tokenResponse)
+}
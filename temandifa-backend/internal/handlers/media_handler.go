@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// MediaHandler serves stored media through signed URLs, for object stores
+// that can't presign their own
+type MediaHandler struct {
+	media services.MediaService
+}
+
+func NewMediaHandler(media services.MediaService) *MediaHandler {
+	return &MediaHandler{media: media}
+}
+
+// ServeAttachment godoc
+//
+//	@Summary		Download an attachment through a signed URL
+//	@Description	Serves the download URL of a history attachment; the signature authenticates the request. The URL stops working at expires, or when the history entry is deleted.
+//	@Tags			History
+//	@Produce		octet-stream
+//	@Param			attachment_id	path	int		true	"Attachment ID"
+//	@Param			expires			query	int		true	"Unix time the URL expires"
+//	@Param			signature		query	string	true	"URL signature"
+//	@Success		200
+//	@Failure		403	{object}	response.ErrorResponse	"Invalid or expired signature"
+//	@Failure		404	{object}	response.ErrorResponse	"Attachment deleted"
+//	@Router			/media/attachments/{attachment_id} [get]
+func (h *MediaHandler) ServeAttachment(c *gin.Context) {
+	attachmentID, err := strconv.ParseUint(c.Param("attachment_id"), 10, 64)
+	if err != nil {
+		response.NotFound(c, "Attachment")
+		return
+	}
+
+	attachment, content, err := h.media.OpenAttachment(c.Request.Context(), attachmentID, c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			apperrors.RespondError(c, appErr)
+			return
+		}
+		logger.Ctx(c).Error("Failed to open attachment", zap.Error(err))
+		response.InternalError(c, "Failed to open attachment")
+		return
+	}
+	if attachment == nil {
+		response.NotFound(c, "Attachment")
+		return
+	}
+	defer func() { _ = content.Close() }()
+
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s"`, attachment.Filename))
+	c.Header("Content-Length", strconv.FormatInt(attachment.SizeBytes, 10))
+	c.Header("Cache-Control", "private, no-store")
+	c.Status(http.StatusOK)
+	c.Writer.Header().Set("Content-Type", attachment.ContentType)
+	if _, err := io.Copy(c.Writer, content); err != nil {
+		logger.Ctx(c).Warn("Attachment download interrupted", zap.Uint64("attachment_id", attachment.ID), zap.Error(err))
+	}
+}
+
+// ServeThumbnail godoc
+//
+//	@Summary		Download an attachment thumbnail through a signed URL
+//	@Description	Serves the thumbnail URL of an image attachment, a JPEG at most 256 pixels on its longer side. Signed and revoked like attachment URLs.
+//	@Tags			History
+//	@Produce		jpeg
+//	@Param			attachment_id	path	int		true	"Attachment ID"
+//	@Param			expires			query	int		true	"Unix time the URL expires"
+//	@Param			signature		query	string	true	"URL signature"
+//	@Success		200
+//	@Failure		403	{object}	response.ErrorResponse	"Invalid or expired signature"
+//	@Failure		404	{object}	response.ErrorResponse	"Attachment deleted or without thumbnail"
+//	@Router			/media/attachments/{attachment_id}/thumbnail [get]
+func (h *MediaHandler) ServeThumbnail(c *gin.Context) {
+	attachmentID, err := strconv.ParseUint(c.Param("attachment_id"), 10, 64)
+	if err != nil {
+		response.NotFound(c, "Thumbnail")
+		return
+	}
+
+	content, err := h.media.OpenThumbnail(c.Request.Context(), attachmentID, c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			apperrors.RespondError(c, appErr)
+			return
+		}
+		logger.Ctx(c).Error("Failed to open thumbnail", zap.Error(err))
+		response.InternalError(c, "Failed to open thumbnail")
+		return
+	}
+	if content == nil {
+		response.NotFound(c, "Thumbnail")
+		return
+	}
+	defer func() { _ = content.Close() }()
+
+	c.Header("Cache-Control", "private, no-store")
+	c.Status(http.StatusOK)
+	c.Writer.Header().Set("Content-Type", "image/jpeg")
+	if _, err := io.Copy(c.Writer, content); err != nil {
+		logger.Ctx(c).Warn("Thumbnail download interrupted", zap.Uint64("attachment_id", attachmentID), zap.Error(err))
+	}
+}
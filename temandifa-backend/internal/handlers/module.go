@@ -8,6 +8,47 @@ var Module = fx.Options(
 	fx.Provide(NewAuthHandler),
 	fx.Provide(NewHistoryHandler),
 	fx.Provide(NewAIProxyHandler),
-	fx.Provide(NewHealthHandler),
 	fx.Provide(NewCacheHandler),
+	fx.Provide(NewHealthHandler),
+	fx.Provide(NewCaptureHandler),
+	fx.Provide(NewAdminHandler),
+	fx.Provide(NewConfigHandler),
+	fx.Provide(NewFeatureFlagHandler),
+	fx.Provide(NewAnalyticsHandler),
+	fx.Provide(NewAnnouncementHandler),
+	fx.Provide(NewSystemHandler),
+	fx.Provide(NewTokenBlacklistHandler),
+	fx.Provide(NewRoleHandler),
+	fx.Provide(NewKillSwitchHandler),
+	fx.Provide(NewJobHandler),
+	fx.Provide(NewDataExportHandler),
+	fx.Provide(NewAuditHandler),
+	fx.Provide(NewPlanHandler),
+	fx.Provide(NewNotificationHandler),
+	fx.Provide(NewNarrationHandler),
+	fx.Provide(NewGraphQLHandler),
+	fx.Provide(NewUploadHandler),
+	fx.Provide(NewHistoryAttachmentHandler),
+	fx.Provide(NewMediaHandler),
+	fx.Provide(NewAppVersionHandler),
+	fx.Provide(NewDeviceHandler),
+	fx.Provide(NewSettingsSyncHandler),
+	fx.Provide(NewPreferencesHandler),
+	fx.Provide(NewEmergencyHandler),
+	fx.Provide(NewTranslationHandler),
+	fx.Provide(NewRateLimitHandler),
+	fx.Provide(NewBatchHandler),
+	fx.Provide(NewWebhookHandler),
+	fx.Provide(NewStatusHandler),
+	fx.Provide(NewUsageReportHandler),
+	fx.Provide(NewEmailVerificationHandler),
+	fx.Provide(NewPasswordResetHandler),
+	fx.Provide(NewOAuthHandler),
+	fx.Provide(NewAccountHandler),
+	fx.Provide(NewUserHandler),
+	fx.Provide(NewSessionHandler),
+	fx.Provide(NewMagicLinkHandler),
+	fx.Provide(NewAPIKeyHandler),
+	fx.Provide(NewEmailChangeHandler),
+	fx.Provide(NewJWKSHandler),
 )
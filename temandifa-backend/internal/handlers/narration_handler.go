@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/dto"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/services"
+)
+
+// narrationWriteTimeout bounds sending one event to a slow client
+const narrationWriteTimeout = 10 * time.Second
+
+// narrationMessage is a received WebSocket message
+type narrationMessage struct {
+	data []byte
+	text bool
+}
+
+// narrationCodec receives frames keeping their type: binary messages are
+// camera frames, text messages are JSON config
+var narrationCodec = websocket.Codec{
+	Marshal: func(v any) ([]byte, byte, error) {
+		data, err := json.Marshal(v)
+		return data, websocket.TextFrame, err
+	},
+	Unmarshal: func(data []byte, payloadType byte, v any) error {
+		msg := v.(*narrationMessage)
+		msg.data = data
+		msg.text = payloadType == websocket.TextFrame
+		return nil
+	},
+}
+
+// NarrationHandler serves continuous scene narration over WebSocket
+type NarrationHandler struct {
+	narration services.NarrationService
+	uploads   *config.UploadConfig
+}
+
+func NewNarrationHandler(narration services.NarrationService, uploads *config.UploadConfig) *NarrationHandler {
+	return &NarrationHandler{narration: narration, uploads: uploads}
+}
+
+// Narrate godoc
+//
+//	@Summary		Narrate the scene (WebSocket)
+//	@Description	Upgrades to a WebSocket. The client sends camera frames as binary messages (image types allowed for /detect) and optionally a text message {"type":"config","lang":"id","text":true}; without lang, text is read in the user's ocr_language preference. The server answers with a "ready" event carrying the connection's limits, then pushes "objects" and "text" events for what came into view, as detection and OCR finish, each with a priority (high for hazards such as vehicles, normal for text, low for other objects, left out at the user's "brief" verbosity preference). Frames sent within min_frame_interval_ms of the last narrated one, or while it is still processed, are dropped and answered with a "throttled" event at most once per interval. Each narrated frame counts against the plan's daily AI quota. The connection closes after an "error" event when the quota, max_frames or max_duration_seconds is used up, or when no message arrives within NARRATE_IDLE_TIMEOUT.
+//	@Tags			AI
+//	@Security		BearerAuth
+//	@Success		101	{object}	dto.NarrationEvent	"Switching protocols; events are dto.NarrationEvent"
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		429	{object}	response.ErrorResponse	"Rate limit exceeded"
+//	@Router			/ws/narrate [get]
+func (h *NarrationHandler) Narrate(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+	ctx := c.Request.Context()
+
+	server := websocket.Server{
+		// Native clients send no Origin. Cross-site browser pages can't
+		// connect anyway: they can't set the Authorization header.
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			defer func() { _ = ws.Close() }()
+			ws.MaxPayloadBytes = int(h.uploads.For(config.UploadOpDetect).MaxSize)
+			h.narration.Serve(ctx, user, &narrationConn{ws: ws})
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// narrationConn adapts a WebSocket connection to services.NarrationStream:
+// binary messages are frames, text messages are JSON config
+type narrationConn struct {
+	ws *websocket.Conn
+}
+
+func (n *narrationConn) Receive() (services.NarrationInput, error) {
+	var msg narrationMessage
+	if err := narrationCodec.Receive(n.ws, &msg); err != nil {
+		if errors.Is(err, websocket.ErrFrameTooLarge) {
+			return services.NarrationInput{}, services.ErrNarrationFrameTooLarge
+		}
+		return services.NarrationInput{}, err
+	}
+	if !msg.text {
+		return services.NarrationInput{Frame: msg.data}, nil
+	}
+
+	var cfg dto.NarrationConfig
+	if err := json.Unmarshal(msg.data, &cfg); err != nil || cfg.Type != "config" {
+		return services.NarrationInput{}, services.ErrNarrationInvalidMessage
+	}
+	return services.NarrationInput{Config: &cfg}, nil
+}
+
+func (n *narrationConn) Send(event dto.NarrationEvent) error {
+	_ = n.ws.SetWriteDeadline(time.Now().Add(narrationWriteTimeout))
+	return narrationCodec.Send(n.ws, event)
+}
@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/dto"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// NotificationHandler serves the in-app notification inbox and lets devices
+// register for push notifications without registering the device
+// (superseded by DeviceHandler)
+type NotificationHandler struct {
+	notifications services.NotificationService
+	inbox         services.InboxService
+}
+
+func NewNotificationHandler(notifications services.NotificationService, inbox services.InboxService) *NotificationHandler {
+	return &NotificationHandler{notifications: notifications, inbox: inbox}
+}
+
+// RegisterPushToken godoc
+//
+//	@Summary		Register a push token
+//	@Description	Register the device's Firebase Cloud Messaging token so the caller receives push notifications (finished data exports, new sign-ins). A token registered by another user moves to the caller. Announcements are pushed to the "announcements" topic instead. Deprecated: register the device with POST /me/devices.
+//	@Tags			Notifications
+//	@Deprecated
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.RegisterPushTokenRequest	true	"Device token"
+//	@Success		200		{object}	response.SuccessResponse
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Router			/me/push-tokens [post]
+func (h *NotificationHandler) RegisterPushToken(c *gin.Context) {
+	var input dto.RegisterPushTokenRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+	if err := h.notifications.RegisterToken(c.Request.Context(), user.ID, input.Token, input.Platform); err != nil {
+		logger.Ctx(c).Error("Failed to register push token", zap.Error(err))
+		response.InternalError(c, "Failed to register push token")
+		return
+	}
+	response.Success(c, nil, "Push token registered")
+}
+
+// UnregisterPushToken godoc
+//
+//	@Summary		Unregister a push token
+//	@Description	Stop push notifications to the device, e.g. when the user signs out. Deprecated: unregister the device with DELETE /me/devices/{device_id}.
+//	@Tags			Notifications
+//	@Deprecated
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.UnregisterPushTokenRequest	true	"Device token"
+//	@Success		200		{object}	response.SuccessResponse
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		404		{object}	response.ErrorResponse	"Push token not found"
+//	@Router			/me/push-tokens [delete]
+func (h *NotificationHandler) UnregisterPushToken(c *gin.Context) {
+	var input dto.UnregisterPushTokenRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+	deleted, err := h.notifications.UnregisterToken(c.Request.Context(), user.ID, input.Token)
+	if err != nil {
+		logger.Ctx(c).Error("Failed to unregister push token", zap.Error(err))
+		response.InternalError(c, "Failed to unregister push token")
+		return
+	}
+	if !deleted {
+		response.NotFound(c, "Push token")
+		return
+	}
+	response.Success(c, nil, "Push token unregistered")
+}
+
+// ListNotifications godoc
+//
+//	@Summary		List notifications
+//	@Description	The caller's in-app inbox, newest first: everything pushed to them (finished data exports, new sign-ins) and the announcements since they signed up, whether or not the push reached a device. Kept for NOTIFICATION_RETENTION. The meta carries the unread count.
+//	@Tags			Notifications
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			unread	query		bool	false	"Only unread notifications"
+//	@Param			page	query		int		false	"Page number"				default(1)
+//	@Param			limit	query		int		false	"Items per page (max 100)"	default(20)
+//	@Success		200		{object}	response.SuccessResponse{data=[]dto.InboxNotification,meta=helpers.Pagination}
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Router			/me/notifications [get]
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+	pagination := helpers.NewPagination(c)
+	unreadOnly, _ := strconv.ParseBool(c.Query("unread"))
+
+	notifications, total, err := h.inbox.List(c.Request.Context(), user.ID, unreadOnly, pagination.Page, pagination.Limit)
+	if err != nil {
+		logger.Ctx(c).Error("Failed to list notifications", zap.Error(err))
+		response.InternalError(c, "Failed to list notifications")
+		return
+	}
+	unread, err := h.inbox.UnreadCount(c.Request.Context(), user.ID)
+	if err != nil {
+		logger.Ctx(c).Error("Failed to count unread notifications", zap.Error(err))
+		response.InternalError(c, "Failed to list notifications")
+		return
+	}
+
+	pagination.SetTotal(total)
+	meta := pagination.ToMeta()
+	meta["unread_count"] = unread
+	response.SuccessWithMeta(c, notifications, meta)
+}
+
+// GetUnreadNotificationCount godoc
+//
+//	@Summary		Count unread notifications
+//	@Description	The number of unread notifications in the caller's inbox, e.g. for a badge
+//	@Tags			Notifications
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=dto.UnreadNotifications}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Router			/me/notifications/unread-count [get]
+func (h *NotificationHandler) GetUnreadNotificationCount(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+	unread, err := h.inbox.UnreadCount(c.Request.Context(), user.ID)
+	if err != nil {
+		logger.Ctx(c).Error("Failed to count unread notifications", zap.Error(err))
+		response.InternalError(c, "Failed to count unread notifications")
+		return
+	}
+	response.Success(c, dto.UnreadNotifications{UnreadCount: unread})
+}
+
+// MarkNotificationRead godoc
+//
+//	@Summary		Mark a notification read
+//	@Tags			Notifications
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			notification_id	path		int	true	"Notification ID"
+//	@Success		200				{object}	response.SuccessResponse
+//	@Failure		401				{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		404				{object}	response.ErrorResponse	"Notification not found"
+//	@Router			/me/notifications/{notification_id}/read [post]
+func (h *NotificationHandler) MarkNotificationRead(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("notification_id"), 10, 64)
+	if err != nil {
+		response.NotFound(c, "Notification")
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+	found, err := h.inbox.MarkRead(c.Request.Context(), user.ID, id)
+	if err != nil {
+		logger.Ctx(c).Error("Failed to mark notification read", zap.Error(err))
+		response.InternalError(c, "Failed to mark notification read")
+		return
+	}
+	if !found {
+		response.NotFound(c, "Notification")
+		return
+	}
+	response.Success(c, nil, "Notification marked read")
+}
+
+// MarkAllNotificationsRead godoc
+//
+//	@Summary		Mark all notifications read
+//	@Tags			Notifications
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Router			/me/notifications/read-all [post]
+func (h *NotificationHandler) MarkAllNotificationsRead(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+	if _, err := h.inbox.MarkAllRead(c.Request.Context(), user.ID); err != nil {
+		logger.Ctx(c).Error("Failed to mark notifications read", zap.Error(err))
+		response.InternalError(c, "Failed to mark notifications read")
+		return
+	}
+	response.Success(c, nil, "All notifications marked read")
+}
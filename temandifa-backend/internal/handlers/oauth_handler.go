@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+const (
+	// oidcBindingCookie keeps an institutional sign-in's binding in the
+	// browser between the redirect to the provider and the callback
+	oidcBindingCookie = "oidc_binding"
+	// oidcBindingTTL is how long a user has to sign in at the provider
+	oidcBindingTTL = 10 * time.Minute
+)
+
+// OAuthHandler serves sign-in with Google, Apple and organizations' OpenID
+// Connect providers
+type OAuthHandler struct {
+	oauth         services.OAuthService
+	events        services.EventService
+	flags         services.FeatureFlagService
+	secureCookies bool
+}
+
+func NewOAuthHandler(oauth services.OAuthService, events services.EventService, flags services.FeatureFlagService, cfg *config.Config) *OAuthHandler {
+	return &OAuthHandler{
+		oauth:         oauth,
+		events:        events,
+		flags:         flags,
+		secureCookies: strings.HasPrefix(cfg.PublicBaseURL, "https://"),
+	}
+}
+
+// SignInWithGoogle godoc
+//
+//	@Summary		Sign in with Google
+//	@Description	Exchange a Google ID token (issued to one of GOOGLE_CLIENT_IDS) for a token pair. The first sign-in creates the account, or links Google to the account with the same verified email; new_user tells which. With X-Device-ID the refresh token is bound to the device, like /login.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Device-ID	header		string					false	"App installation ID"
+//	@Param			input		body		dto.OAuthSignInRequest	true	"Google ID token"
+//	@Success		200			{object}	response.SuccessResponse{data=dto.LoginResponse}
+//	@Failure		400			{object}	response.ErrorResponse	"Validation failed"
+//	@Failure		401			{object}	response.ErrorResponse	"Invalid ID token"
+//	@Failure		403			{object}	response.ErrorResponse	"Email not verified by Google"
+//	@Failure		503			{object}	response.ErrorResponse	"Google sign-in not configured or unavailable"
+//	@Router			/auth/google [post]
+func (h *OAuthHandler) SignInWithGoogle(c *gin.Context) {
+	h.signIn(c, models.ProviderGoogle)
+}
+
+// SignInWithApple godoc
+//
+//	@Summary		Sign in with Apple
+//	@Description	Exchange a Sign in with Apple ID token (issued to one of APPLE_CLIENT_IDS) for a token pair. Apple shares the user's name with the app only on the first sign-in; send it as full_name then. The first sign-in creates the account, or links Apple to the account with the same verified email; new_user tells which.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Device-ID	header		string					false	"App installation ID"
+//	@Param			input		body		dto.OAuthSignInRequest	true	"Apple ID token"
+//	@Success		200			{object}	response.SuccessResponse{data=dto.LoginResponse}
+//	@Failure		400			{object}	response.ErrorResponse	"Validation failed"
+//	@Failure		401			{object}	response.ErrorResponse	"Invalid ID token"
+//	@Failure		403			{object}	response.ErrorResponse	"Email not verified by Apple"
+//	@Failure		503			{object}	response.ErrorResponse	"Apple sign-in not configured or unavailable"
+//	@Router			/auth/apple [post]
+func (h *OAuthHandler) SignInWithApple(c *gin.Context) {
+	h.signIn(c, models.ProviderApple)
+}
+
+func (h *OAuthHandler) signIn(c *gin.Context, provider string) {
+	var input dto.OAuthSignInRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	tokenResponse, err := h.oauth.SignIn(c.Request.Context(), provider, input, c.GetHeader("User-Agent"), c.ClientIP(), requestDeviceID(c))
+	h.respondSignIn(c, provider, tokenResponse, err)
+}
+
+// StartOIDCSignIn godoc
+//
+//	@Summary		Start an institutional sign-in
+//	@Description	Redirect to the sign-in page of an organization's OpenID Connect provider (one of OIDC_PROVIDERS), which sends the user back to /auth/oidc/{provider}/callback. Open it in a browser; a cookie ties the sign-in to it.
+//	@Tags			Auth
+//	@Param			provider	path		string	true	"Provider name"	example(ui)
+//	@Success		302			{string}	string	"Redirect to the provider's sign-in page"
+//	@Failure		404			{object}	response.ErrorResponse	"Unknown provider"
+//	@Failure		503			{object}	response.ErrorResponse	"Provider unavailable"
+//	@Router			/auth/oidc/{provider} [get]
+func (h *OAuthHandler) StartOIDCSignIn(c *gin.Context) {
+	provider := c.Param("provider")
+	authURL, binding, err := h.oauth.OIDCAuthURL(c.Request.Context(), provider)
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			apperrors.RespondError(c, appErr)
+			return
+		}
+		logger.Ctx(c).Error("Failed to start sign-in", zap.String("provider", provider), zap.Error(err))
+		response.InternalError(c, "Failed to start sign-in")
+		return
+	}
+
+	// Lax, since the provider sends the browser back with a top-level GET
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcBindingCookie, binding, int(oidcBindingTTL.Seconds()), oidcCookiePath(provider), "", h.secureCookies, true)
+	c.Header("Cache-Control", "no-store")
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// OIDCCallback godoc
+//
+//	@Summary		Finish an institutional sign-in
+//	@Description	Where the organization's provider sends the user back after signing in; exchanges its code for a token pair. The first sign-in creates the account in the provider's organization (with its plan), or links the provider to the account with the same email; new_user tells which. Returning users join the organization too, and move to its plan from the free plan.
+//	@Tags			Auth
+//	@Produce		json
+//	@Param			X-Device-ID	header		string	false	"App installation ID"
+//	@Param			provider	path		string	true	"Provider name"	example(ui)
+//	@Param			code		query		string	true	"Authorization code"
+//	@Param			state		query		string	true	"Sign-in state"
+//	@Success		200			{object}	response.SuccessResponse{data=dto.LoginResponse}
+//	@Failure		400			{object}	response.ErrorResponse	"Invalid or expired sign-in attempt"
+//	@Failure		401			{object}	response.ErrorResponse	"Sign-in refused by the provider, or invalid ID token"
+//	@Failure		403			{object}	response.ErrorResponse	"Email not verified, or outside the organization's domains"
+//	@Failure		404			{object}	response.ErrorResponse	"Unknown provider"
+//	@Failure		503			{object}	response.ErrorResponse	"Provider unavailable"
+//	@Router			/auth/oidc/{provider}/callback [get]
+func (h *OAuthHandler) OIDCCallback(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	provider := c.Param("provider")
+
+	// The binding is used up whatever the outcome
+	binding, _ := c.Cookie(oidcBindingCookie)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcBindingCookie, "", -1, oidcCookiePath(provider), "", h.secureCookies, true)
+
+	if reason := c.Query("error"); reason != "" {
+		logger.Ctx(c).Info("Sign-in refused by provider", zap.String("provider", provider), zap.String("reason", reason))
+		apperrors.RespondError(c, apperrors.NewAppError(apperrors.ErrCodeUnauthorized, "Sign-in was cancelled or refused by the provider", http.StatusUnauthorized))
+		return
+	}
+
+	tokenResponse, err := h.oauth.OIDCSignIn(c.Request.Context(), provider, c.Query("code"), c.Query("state"), binding,
+		c.GetHeader("User-Agent"), c.ClientIP(), requestDeviceID(c))
+	h.respondSignIn(c, models.ProviderOIDCPrefix+provider, tokenResponse, err)
+}
+
+// oidcCookiePath scopes the binding cookie to a provider's sign-in
+func oidcCookiePath(provider string) string {
+	return "/api/v1/auth/oidc/" + provider
+}
+
+// respondSignIn answers a sign-in with provider, recording it when it worked
+func (h *OAuthHandler) respondSignIn(c *gin.Context, provider string, tokenResponse *dto.LoginResponse, err error) {
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			apperrors.RespondError(c, appErr)
+			return
+		}
+		logger.Ctx(c).Error("Sign-in failed", zap.String("provider", provider), zap.Error(err))
+		response.InternalError(c, "Sign-in failed")
+		return
+	}
+
+	logger.Ctx(c).Info("User signed in",
+		zap.Uint("user_id", tokenResponse.User.ID),
+		zap.String("provider", provider),
+		zap.Bool("new_user", tokenResponse.NewUser),
+	)
+
+	h.events.Emit(c.Request.Context(), services.Event{
+		Type:       services.EventLogin,
+		UserID:     tokenResponse.User.ID,
+		Properties: map[string]any{"method": provider, "new_user": tokenResponse.NewUser},
+	})
+
+	tokenResponse.Features = h.flags.Evaluate(c.Request.Context(), services.FlagSubject{
+		UserID: tokenResponse.User.ID,
+		Email:  tokenResponse.User.Email,
+	})
+
+	response.Success(c, tokenResponse)
+}
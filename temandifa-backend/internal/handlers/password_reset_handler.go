@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// PasswordResetHandler serves forgotten password resets
+type PasswordResetHandler struct {
+	resets services.PasswordResetService
+}
+
+func NewPasswordResetHandler(resets services.PasswordResetService) *PasswordResetHandler {
+	return &PasswordResetHandler{resets: resets}
+}
+
+// respondPasswordResetError answers app errors as such and anything else as 500
+func respondPasswordResetError(c *gin.Context, err error, message string) {
+	if appErr, ok := apperrors.AsAppError(err); ok {
+		apperrors.RespondError(c, appErr)
+		return
+	}
+	logger.Ctx(c).Error(message, zap.Error(err))
+	response.InternalError(c, message)
+}
+
+// ForgotPassword godoc
+//
+//	@Summary		Ask for a password reset
+//	@Description	Email a single-use reset token (valid for PASSWORD_RESET_TTL) if an account has the address. The answer is the same either way. Each address may ask PASSWORD_RESET_LIMIT times per PASSWORD_RESET_WINDOW.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			input	body		dto.ForgotPasswordRequest	true	"Account email"
+//	@Success		200		{object}	response.SuccessResponse
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed"
+//	@Failure		429		{object}	response.ErrorResponse	"Too many requests for the address"
+//	@Router			/forgot-password [post]
+func (h *PasswordResetHandler) ForgotPassword(c *gin.Context) {
+	var input dto.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	if err := h.resets.Request(c.Request.Context(), input.Email); err != nil {
+		respondPasswordResetError(c, err, "Failed to process password reset request")
+		return
+	}
+	response.Success(c, nil, "If an account uses this email, a reset code was sent to it")
+}
+
+// ResetPassword godoc
+//
+//	@Summary		Reset a forgotten password
+//	@Description	Set a new password with the emailed reset token. The token is used up, and every device is signed out.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			input	body		dto.ResetPasswordRequest	true	"Reset token and new password"
+//	@Success		200		{object}	response.SuccessResponse
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed, or invalid or expired token"
+//	@Router			/reset-password [post]
+func (h *PasswordResetHandler) ResetPassword(c *gin.Context) {
+	var input dto.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	if err := h.resets.Reset(c.Request.Context(), input.Token, input.Password); err != nil {
+		respondPasswordResetError(c, err, "Failed to reset password")
+		return
+	}
+	response.Success(c, nil, "Password reset, please sign in again")
+}
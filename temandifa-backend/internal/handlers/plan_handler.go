@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// PlanHandler lets administrators tune subscription plans and assign them
+type PlanHandler struct {
+	plans services.PlanService
+}
+
+func NewPlanHandler(plans services.PlanService) *PlanHandler {
+	return &PlanHandler{plans: plans}
+}
+
+// respondPlanError answers validation errors as such and anything else as 500
+func respondPlanError(c *gin.Context, err error, message string) {
+	if appErr, ok := apperrors.AsAppError(err); ok {
+		apperrors.RespondError(c, appErr)
+		return
+	}
+	logger.Ctx(c).Error(message, zap.Error(err))
+	response.InternalError(c, message)
+}
+
+// ListPlans godoc
+//
+//	@Summary		List plans
+//	@Description	Every subscription plan with its daily AI quota (0 for unlimited) and rate limit and cache TTL multipliers
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=[]models.Plan}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/plans [get]
+func (h *PlanHandler) ListPlans(c *gin.Context) {
+	plans, err := h.plans.List(c.Request.Context())
+	if err != nil {
+		respondPlanError(c, err, "Failed to list plans")
+		return
+	}
+	response.Success(c, plans)
+}
+
+// UpdatePlan godoc
+//
+//	@Summary		Update a plan
+//	@Description	Change a plan's description and allowances; other instances apply them within FLAGS_REFRESH_INTERVAL
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			name	path		string					true	"Plan name (free, plus, institution)"
+//	@Param			request	body		dto.UpdatePlanRequest	true	"Allowances"
+//	@Success		200		{object}	response.SuccessResponse{data=models.Plan}
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404		{object}	response.ErrorResponse	"Plan not found"
+//	@Router			/admin/plans/{name} [put]
+func (h *PlanHandler) UpdatePlan(c *gin.Context) {
+	var input dto.UpdatePlanRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	plan, err := h.plans.Update(c.Request.Context(), &models.Plan{
+		Name:                c.Param("name"),
+		Description:         input.Description,
+		DailyAIQuota:        input.DailyAIQuota,
+		RateLimitMultiplier: input.RateLimitMultiplier,
+		CacheTTLMultiplier:  input.CacheTTLMultiplier,
+	})
+	if err != nil {
+		respondPlanError(c, err, "Failed to update plan")
+		return
+	}
+	if plan == nil {
+		response.NotFound(c, "Plan")
+		return
+	}
+	response.Success(c, plan, "Plan updated")
+}
+
+// AssignUserPlan godoc
+//
+//	@Summary		Assign a plan to a user
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int						true	"User ID"
+//	@Param			request	body		dto.AssignPlanRequest	true	"Plan"
+//	@Success		200		{object}	response.SuccessResponse{data=models.User}
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed or unknown plan"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404		{object}	response.ErrorResponse	"User not found"
+//	@Failure		409		{object}	response.ErrorResponse	"Concurrent update"
+//	@Router			/admin/users/{id}/plan [put]
+func (h *PlanHandler) AssignUserPlan(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.NotFound(c, "User")
+		return
+	}
+
+	var input dto.AssignPlanRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	user, err := h.plans.AssignPlan(c.Request.Context(), uint(userID), input.Plan)
+	if err != nil {
+		respondPlanError(c, err, "Failed to assign plan")
+		return
+	}
+	if user == nil {
+		response.NotFound(c, "User")
+		return
+	}
+
+	logger.Ctx(c).Info("Plan assigned", zap.Uint("target_user_id", user.ID), zap.String("plan", user.Plan))
+	response.Success(c, user, "Plan assigned")
+}
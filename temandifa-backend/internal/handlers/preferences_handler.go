@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/dto"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// PreferencesHandler serves the user's accessibility preferences
+type PreferencesHandler struct {
+	preferences services.PreferencesService
+}
+
+func NewPreferencesHandler(preferences services.PreferencesService) *PreferencesHandler {
+	return &PreferencesHandler{preferences: preferences}
+}
+
+// GetPreferences godoc
+//
+//	@Summary		Get accessibility preferences
+//	@Description	The caller's TTS voice and speed, OCR language, narration verbosity and haptics, or the defaults if never changed. /ocr and narration use the OCR language when the request has none; the "brief" verbosity leaves objects other than hazards out of narration.
+//	@Tags			Preferences
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=models.UserPreferences}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Router			/me/preferences [get]
+func (h *PreferencesHandler) GetPreferences(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+	preferences, err := h.preferences.Get(c.Request.Context(), user.ID)
+	if err != nil {
+		logger.Ctx(c).Error("Failed to load preferences", zap.Error(err))
+		response.InternalError(c, "Failed to load preferences")
+		return
+	}
+	response.Success(c, preferences)
+}
+
+// UpdatePreferences godoc
+//
+//	@Summary		Update accessibility preferences
+//	@Description	Change the given preferences; omitted ones keep their value
+//	@Tags			Preferences
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.UpdatePreferencesRequest	true	"Preferences to change"
+//	@Success		200		{object}	response.SuccessResponse{data=models.UserPreferences}
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Router			/me/preferences [patch]
+func (h *PreferencesHandler) UpdatePreferences(c *gin.Context) {
+	var input dto.UpdatePreferencesRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+	preferences, err := h.preferences.Update(c.Request.Context(), user.ID, input)
+	if err != nil {
+		logger.Ctx(c).Error("Failed to update preferences", zap.Error(err))
+		response.InternalError(c, "Failed to update preferences")
+		return
+	}
+	response.Success(c, preferences, "Preferences updated")
+}
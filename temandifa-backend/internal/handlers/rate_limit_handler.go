@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// RateLimitHandler shows users where they stand with the rate limits
+type RateLimitHandler struct {
+	limits services.RateLimitStatusService
+}
+
+func NewRateLimitHandler(limits services.RateLimitStatusService) *RateLimitHandler {
+	return &RateLimitHandler{limits: limits}
+}
+
+// GetMyLimits godoc
+//
+//	@Summary		Get my rate limits
+//	@Description	What is left of every rate limiter and quota that applies to the caller and when it resets, so the app can pace expensive requests. This request counts against the general limit itself. Not enforced (and empty) without Redis.
+//	@Tags			Account
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=dto.RateLimits}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Router			/me/limits [get]
+func (h *RateLimitHandler) GetMyLimits(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	limits, err := h.limits.Status(c.Request.Context(), &user, c.ClientIP())
+	if err != nil {
+		logger.Ctx(c).Error("Failed to load rate limits", zap.Error(err))
+		response.InternalError(c, "Failed to load rate limits")
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	response.Success(c, limits)
+}
@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// RoleHandler serves role and permission management to administrators
+type RoleHandler struct {
+	roles services.RoleService
+}
+
+func NewRoleHandler(roles services.RoleService) *RoleHandler {
+	return &RoleHandler{roles: roles}
+}
+
+// respondRoleError answers validation and conflict errors as such and
+// anything else as 500
+func respondRoleError(c *gin.Context, err error, message string) {
+	if appErr, ok := apperrors.AsAppError(err); ok {
+		apperrors.RespondError(c, appErr)
+		return
+	}
+	logger.Ctx(c).Error(message, zap.Error(err))
+	response.InternalError(c, message)
+}
+
+// ListPermissions godoc
+//
+//	@Summary		List permissions
+//	@Description	Every permission that can be attached to a role
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=[]dto.Permission}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/permissions [get]
+func (h *RoleHandler) ListPermissions(c *gin.Context) {
+	permissions := make([]dto.Permission, 0, len(models.PermissionCatalog))
+	for name, description := range models.PermissionCatalog {
+		permissions = append(permissions, dto.Permission{Name: name, Description: description})
+	}
+	sort.Slice(permissions, func(i, j int) bool { return permissions[i].Name < permissions[j].Name })
+	response.Success(c, permissions)
+}
+
+// ListRoles godoc
+//
+//	@Summary		List roles
+//	@Description	Every role with its permissions
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=[]models.Role}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/roles [get]
+func (h *RoleHandler) ListRoles(c *gin.Context) {
+	roles, err := h.roles.ListRoles(c.Request.Context())
+	if err != nil {
+		logger.Ctx(c).Error("Failed to list roles", zap.Error(err))
+		response.InternalError(c, "Failed to list roles")
+		return
+	}
+	response.Success(c, roles)
+}
+
+// CreateRole godoc
+//
+//	@Summary		Create a role
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.CreateRoleRequest	true	"Role"
+//	@Success		201		{object}	response.SuccessResponse{data=models.Role}
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		409		{object}	response.ErrorResponse	"Role already exists"
+//	@Router			/admin/roles [post]
+func (h *RoleHandler) CreateRole(c *gin.Context) {
+	var input dto.CreateRoleRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	role := models.Role{
+		Name:        input.Name,
+		Description: input.Description,
+		Permissions: make([]models.RolePermission, len(input.Permissions)),
+	}
+	for i, permission := range input.Permissions {
+		role.Permissions[i] = models.RolePermission{RoleName: input.Name, Permission: permission}
+	}
+
+	if err := h.roles.CreateRole(c.Request.Context(), &role); err != nil {
+		respondRoleError(c, err, "Failed to create role")
+		return
+	}
+
+	logger.Ctx(c).Info("Role created", zap.String("role", role.Name), zap.Strings("permissions", input.Permissions))
+	response.Created(c, role, "Role created")
+}
+
+// UpdateRole godoc
+//
+//	@Summary		Update a role
+//	@Description	Change a role's description
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			name	path		string					true	"Role name"
+//	@Param			request	body		dto.UpdateRoleRequest	true	"Role"
+//	@Success		200		{object}	response.SuccessResponse{data=models.Role}
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404		{object}	response.ErrorResponse	"Role not found"
+//	@Router			/admin/roles/{name} [put]
+func (h *RoleHandler) UpdateRole(c *gin.Context) {
+	var input dto.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	role, err := h.roles.UpdateRole(c.Request.Context(), c.Param("name"), input.Description)
+	if err != nil {
+		respondRoleError(c, err, "Failed to update role")
+		return
+	}
+	if role == nil {
+		response.NotFound(c, "Role")
+		return
+	}
+	response.Success(c, role, "Role updated")
+}
+
+// DeleteRole godoc
+//
+//	@Summary		Delete a role
+//	@Description	Built-in roles and roles still assigned to users can't be deleted
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			name	path		string	true	"Role name"
+//	@Success		200		{object}	response.SuccessResponse
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404		{object}	response.ErrorResponse	"Role not found"
+//	@Failure		409		{object}	response.ErrorResponse	"Built-in or assigned role"
+//	@Router			/admin/roles/{name} [delete]
+func (h *RoleHandler) DeleteRole(c *gin.Context) {
+	found, err := h.roles.DeleteRole(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondRoleError(c, err, "Failed to delete role")
+		return
+	}
+	if !found {
+		response.NotFound(c, "Role")
+		return
+	}
+
+	logger.Ctx(c).Info("Role deleted", zap.String("role", c.Param("name")))
+	response.Success(c, nil, "Role deleted")
+}
+
+// AddRolePermissions godoc
+//
+//	@Summary		Attach permissions to a role
+//	@Description	Permissions already attached are left as they are; users with the role are dropped from the user cache
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			name	path		string							true	"Role name"
+//	@Param			request	body		dto.AddRolePermissionsRequest	true	"Permissions"
+//	@Success		200		{object}	response.SuccessResponse{data=models.Role}
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404		{object}	response.ErrorResponse	"Role not found"
+//	@Router			/admin/roles/{name}/permissions [post]
+func (h *RoleHandler) AddRolePermissions(c *gin.Context) {
+	var input dto.AddRolePermissionsRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	role, err := h.roles.AddPermissions(c.Request.Context(), c.Param("name"), input.Permissions)
+	if err != nil {
+		respondRoleError(c, err, "Failed to attach permissions")
+		return
+	}
+	if role == nil {
+		response.NotFound(c, "Role")
+		return
+	}
+
+	logger.Ctx(c).Info("Role permissions attached", zap.String("role", role.Name), zap.Strings("permissions", input.Permissions))
+	response.Success(c, role, "Permissions attached")
+}
+
+// RemoveRolePermission godoc
+//
+//	@Summary		Detach a permission from a role
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			name		path		string	true	"Role name"
+//	@Param			permission	path		string	true	"Permission"
+//	@Success		200			{object}	response.SuccessResponse
+//	@Failure		401			{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403			{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404			{object}	response.ErrorResponse	"Role or permission not found"
+//	@Failure		409			{object}	response.ErrorResponse	"The admin role keeps every permission"
+//	@Router			/admin/roles/{name}/permissions/{permission} [delete]
+func (h *RoleHandler) RemoveRolePermission(c *gin.Context) {
+	removed, err := h.roles.RemovePermission(c.Request.Context(), c.Param("name"), c.Param("permission"))
+	if err != nil {
+		respondRoleError(c, err, "Failed to detach permission")
+		return
+	}
+	if !removed {
+		response.NotFound(c, "Role permission")
+		return
+	}
+
+	logger.Ctx(c).Info("Role permission detached", zap.String("role", c.Param("name")), zap.String("permission", c.Param("permission")))
+	response.Success(c, nil, "Permission detached")
+}
+
+// AssignUserRole godoc
+//
+//	@Summary		Assign a role to a user
+//	@Description	Administrators can't change their own role
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int						true	"User ID"
+//	@Param			request	body		dto.AssignRoleRequest	true	"Role"
+//	@Success		200		{object}	response.SuccessResponse{data=models.User}
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed or unknown role"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404		{object}	response.ErrorResponse	"User not found"
+//	@Failure		409		{object}	response.ErrorResponse	"Own role or concurrent update"
+//	@Router			/admin/users/{id}/role [put]
+func (h *RoleHandler) AssignUserRole(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.NotFound(c, "User")
+		return
+	}
+
+	var input dto.AssignRoleRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	user, err := h.roles.AssignRole(c.Request.Context(), uint(userID), input.Role)
+	if err != nil {
+		respondRoleError(c, err, "Failed to assign role")
+		return
+	}
+	if user == nil {
+		response.NotFound(c, "User")
+		return
+	}
+
+	logger.Ctx(c).Info("Role assigned", zap.Uint("target_user_id", user.ID), zap.String("role", user.Role))
+	response.Success(c, user, "Role assigned")
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/middleware"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// SessionHandler lets users see where they are signed in
+type SessionHandler struct {
+	sessions services.SessionService
+}
+
+func NewSessionHandler(sessions services.SessionService) *SessionHandler {
+	return &SessionHandler{sessions: sessions}
+}
+
+// ListSessions godoc
+//
+//	@Summary		List my sessions
+//	@Description	The caller's active login sessions (unrevoked, unexpired refresh tokens), the most recently used first. created_at is the sign-in and last_used_at the last token refresh; current marks the session of the request's access token.
+//	@Tags			Auth
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=[]dto.Session}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Router			/auth/sessions [get]
+func (h *SessionHandler) ListSessions(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	sessions, err := h.sessions.ListSessions(c.Request.Context(), user.ID, middleware.SessionID(c))
+	if err != nil {
+		logger.Ctx(c).Error("Failed to list sessions", zap.Error(err))
+		response.InternalError(c, "Failed to list sessions")
+		return
+	}
+	response.Success(c, sessions)
+}
+
+// RevokeSession godoc
+//
+//	@Summary		Revoke one of my sessions
+//	@Description	Signs the caller out of one of their sessions, e.g. a lost device: its refresh token is revoked and the access token issued with it is blacklisted. Revoking the current session signs the caller out.
+//	@Tags			Auth
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Session ID"
+//	@Success		200	{object}	response.SuccessResponse
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		404	{object}	response.ErrorResponse	"Session not found"
+//	@Router			/auth/sessions/{id} [delete]
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.NotFound(c, "Session")
+		return
+	}
+
+	revoked, err := h.sessions.RevokeSession(c.Request.Context(), user.ID, uint(id))
+	if err != nil {
+		logger.Ctx(c).Error("Failed to revoke session", zap.Uint64("session_id", id), zap.Error(err))
+		response.InternalError(c, "Failed to revoke session")
+		return
+	}
+	if !revoked {
+		response.NotFound(c, "Session")
+		return
+	}
+	response.Success(c, nil, "Session revoked")
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// SettingsSyncHandler syncs app settings across the user's devices
+type SettingsSyncHandler struct {
+	settings services.SettingsSyncService
+}
+
+func NewSettingsSyncHandler(settings services.SettingsSyncService) *SettingsSyncHandler {
+	return &SettingsSyncHandler{settings: settings}
+}
+
+// GetSettingsSync godoc
+//
+//	@Summary		Fetch synced settings
+//	@Description	The caller's settings changed after the cursor, oldest change first, deleted ones as tombstones; 0 fetches them all. Pass the returned cursor on the next sync.
+//	@Tags			Settings
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			since	query		int	false	"Cursor of the last sync"	default(0)
+//	@Success		200		{object}	response.SuccessResponse{data=dto.SettingsSync}
+//	@Failure		400		{object}	response.ErrorResponse	"Invalid cursor"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Router			/me/settings/sync [get]
+func (h *SettingsSyncHandler) GetSettingsSync(c *gin.Context) {
+	since, err := strconv.ParseInt(c.DefaultQuery("since", "0"), 10, 64)
+	if err != nil || since < 0 {
+		response.BadRequest(c, "Invalid cursor")
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+	delta, err := h.settings.Pull(c.Request.Context(), user.ID, since)
+	if err != nil {
+		logger.Ctx(c).Error("Failed to fetch settings", zap.Error(err))
+		response.InternalError(c, "Failed to fetch settings")
+		return
+	}
+	response.Success(c, delta)
+}
+
+// PutSettingsSync godoc
+//
+//	@Summary		Sync settings
+//	@Description	Push the device's setting edits, e.g. those made offline, and fetch the settings changed after its cursor. Each edit carries the setting's version vector with the device's own entry incremented. An edit that supersedes the stored one is applied, one superseded by it is stale, and concurrent edits are merged deterministically (the greater vector total wins, then the greater value) into the returned setting. Keys are lower-case (e.g. tts.speed), values any JSON up to 4 KB, at most 200 settings.
+//	@Tags			Settings
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.SettingsSyncRequest	true	"Edits and cursor"
+//	@Success		200		{object}	response.SuccessResponse{data=dto.SettingsSyncResult}
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		409		{object}	response.ErrorResponse	"Too many settings"
+//	@Router			/me/settings/sync [put]
+func (h *SettingsSyncHandler) PutSettingsSync(c *gin.Context) {
+	var input dto.SettingsSyncRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+	result, err := h.settings.Push(c.Request.Context(), user.ID, input.Since, input.Changes)
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			apperrors.RespondError(c, appErr)
+			return
+		}
+		logger.Ctx(c).Error("Failed to sync settings", zap.Error(err))
+		response.InternalError(c, "Failed to sync settings")
+		return
+	}
+	response.Success(c, result)
+}
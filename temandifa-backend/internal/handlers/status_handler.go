@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// StatusHandler serves the public status page
+type StatusHandler struct {
+	status services.StatusService
+}
+
+func NewStatusHandler(status services.StatusService) *StatusHandler {
+	return &StatusHandler{status: status}
+}
+
+// GetStatus godoc
+//
+//	@Summary		Service status
+//	@Description	Public status page: the current status of the API, database, Redis and AI Service from the background health checks, ongoing outages and maintenance, and each component's uptime over the last 90 days (UTC days, from the checks of every instance). Served during maintenance and to outdated apps.
+//	@Tags			Health
+//	@Produce		json
+//	@Success		200	{object}	response.SuccessResponse{data=dto.ServiceStatus}
+//	@Router			/status [get]
+func (h *StatusHandler) GetStatus(c *gin.Context) {
+	// Component status is cached by the health checker, uptime for a minute
+	c.Header("Cache-Control", "public, max-age=15")
+	response.Success(c, h.status.Status(c.Request.Context()))
+}
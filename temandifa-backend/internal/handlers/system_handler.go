@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"temandifa-backend/internal/dto"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// SystemHandler describes the running instance to administrators
+type SystemHandler struct {
+	system services.SystemService
+}
+
+func NewSystemHandler(system services.SystemService) *SystemHandler {
+	return &SystemHandler{system: system}
+}
+
+// GetSystemInfo godoc
+//
+//	@Summary		Get system info
+//	@Description	Build version, commit and time, Go version, instance ID, APP_ENV profile, uptime and the versions of backing services and compiled-in modules of the instance that answers
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=dto.SystemInfo}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/system [get]
+func (h *SystemHandler) GetSystemInfo(c *gin.Context) {
+	response.Success(c, h.system.Info(c.Request.Context(), dto.BuildInfo{
+		Version: AppVersion,
+		Commit:  GitCommit,
+		Time:    BuildTime,
+	}))
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// maxBlacklistEntries bounds how many blacklist entries are listed at once
+const maxBlacklistEntries = 500
+
+// TokenBlacklistHandler lets administrators inspect and prune the access
+// token blacklist
+type TokenBlacklistHandler struct {
+	blacklist *services.TokenBlacklist
+}
+
+func NewTokenBlacklistHandler(blacklist *services.TokenBlacklist) *TokenBlacklistHandler {
+	return &TokenBlacklistHandler{blacklist: blacklist}
+}
+
+// GetBlacklistStats godoc
+//
+//	@Summary		Get token blacklist statistics
+//	@Description	Entry count, Redis memory, expired entries and entries without TTL, counted with SCAN
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=services.BlacklistStats}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		500	{object}	response.ErrorResponse	"Redis unavailable"
+//	@Router			/admin/token-blacklist/stats [get]
+func (h *TokenBlacklistHandler) GetBlacklistStats(c *gin.Context) {
+	stats, err := h.blacklist.Stats(c.Request.Context())
+	if err != nil {
+		logger.Ctx(c).Error("Failed to collect token blacklist stats", zap.Error(err))
+		response.InternalError(c, "Failed to collect token blacklist stats")
+		return
+	}
+	response.Success(c, stats)
+}
+
+// ListBlacklistEntries godoc
+//
+//	@Summary		List token blacklist entries
+//	@Description	Most recently revoked access tokens (by hash); entries written by older versions have no revocation time and are listed last
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			limit	query		int	false	"Max entries (default 50, max 500)"
+//	@Success		200		{object}	response.SuccessResponse{data=[]services.BlacklistEntry}
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		500		{object}	response.ErrorResponse	"Redis unavailable"
+//	@Router			/admin/token-blacklist [get]
+func (h *TokenBlacklistHandler) ListBlacklistEntries(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 {
+		limit = 50
+	}
+	if limit > maxBlacklistEntries {
+		limit = maxBlacklistEntries
+	}
+
+	entries, err := h.blacklist.Recent(c.Request.Context(), limit)
+	if err != nil {
+		logger.Ctx(c).Error("Failed to list token blacklist", zap.Error(err))
+		response.InternalError(c, "Failed to list token blacklist")
+		return
+	}
+	response.Success(c, entries)
+}
+
+// ClearExpiredBlacklistEntries godoc
+//
+//	@Summary		Clear expired token blacklist entries
+//	@Description	Delete entries whose access token has expired anyway, and give entries of unknown age without a TTL one
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	map[string]interface{}	"Deleted count"
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		500	{object}	response.ErrorResponse	"Redis unavailable"
+//	@Router			/admin/token-blacklist/expired [delete]
+func (h *TokenBlacklistHandler) ClearExpiredBlacklistEntries(c *gin.Context) {
+	deleted, err := h.blacklist.ClearExpired(c.Request.Context())
+	if err != nil {
+		logger.Ctx(c).Error("Failed to clear expired token blacklist entries", zap.Error(err))
+		response.InternalError(c, "Failed to clear expired token blacklist entries")
+		return
+	}
+
+	logger.Ctx(c).Info("Expired token blacklist entries cleared", zap.Int64("deleted", deleted))
+	response.Success(c, gin.H{"deleted": deleted}, "Expired entries cleared")
+}
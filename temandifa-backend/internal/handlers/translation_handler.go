@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"golang.org/x/text/language"
+
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// TranslationHandler serves translation bundles to the app and their
+// management to administrators
+type TranslationHandler struct {
+	translations services.TranslationService
+}
+
+func NewTranslationHandler(translations services.TranslationService) *TranslationHandler {
+	return &TranslationHandler{translations: translations}
+}
+
+// respondTranslationError answers validation errors as such and anything else as 500
+func respondTranslationError(c *gin.Context, err error, message string) {
+	if appErr, ok := apperrors.AsAppError(err); ok {
+		apperrors.RespondError(c, appErr)
+		return
+	}
+	logger.Ctx(c).Error(message, zap.Error(err))
+	response.InternalError(c, message)
+}
+
+// etagMatches reports whether an If-None-Match header lists etag (weak
+// comparison, as for GET)
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// GetBundle godoc
+//
+//	@Summary		Get a translation bundle
+//	@Description	The latest translations of the best match for the locale (e.g. en-US gets en): app strings and server messages keyed by error code or notification type. Send the ETag back in If-None-Match to get 304 when nothing changed. Public and served during maintenance and to outdated apps.
+//	@Tags			App
+//	@Produce		json
+//	@Param			locale			path		string	true	"BCP 47 locale, e.g. id or en-US"
+//	@Param			version			query		int		false	"A specific version instead of the latest"
+//	@Param			If-None-Match	header		string	false	"ETag of the bundle the app has"
+//	@Success		200				{object}	response.SuccessResponse{data=dto.TranslationBundle}
+//	@Success		304				"Not modified"
+//	@Failure		400				{object}	response.ErrorResponse	"Invalid locale or version"
+//	@Failure		404				{object}	response.ErrorResponse	"No bundle for the locale"
+//	@Router			/i18n/{locale} [get]
+func (h *TranslationHandler) GetBundle(c *gin.Context) {
+	tag, err := language.Parse(c.Param("locale"))
+	if err != nil {
+		response.BadRequest(c, "Invalid locale")
+		return
+	}
+
+	var bundle *dto.TranslationBundle
+	if raw := c.Query("version"); raw != "" {
+		version, err := strconv.Atoi(raw)
+		if err != nil || version <= 0 {
+			response.BadRequest(c, "Invalid version")
+			return
+		}
+		if bundle, err = h.translations.BundleVersion(c.Request.Context(), tag, version); err != nil {
+			logger.Ctx(c).Error("Failed to load translation bundle", zap.Error(err))
+			response.InternalError(c, "Failed to load translation bundle")
+			return
+		}
+	} else {
+		bundle = h.translations.Bundle(tag)
+	}
+	if bundle == nil {
+		response.NotFound(c, "Translation bundle")
+		return
+	}
+
+	// Always revalidate; unchanged bundles cost a 304
+	c.Header("Cache-Control", "no-cache")
+	c.Header("ETag", bundle.ETag)
+	if etagMatches(c.GetHeader("If-None-Match"), bundle.ETag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	response.Success(c, bundle)
+}
+
+// ListTranslationLocales godoc
+//
+//	@Summary		List translated locales
+//	@Description	The latest bundle version of every locale
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=[]dto.TranslationBundleVersion}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/i18n [get]
+func (h *TranslationHandler) ListTranslationLocales(c *gin.Context) {
+	response.Success(c, h.translations.Locales())
+}
+
+// ListTranslationVersions godoc
+//
+//	@Summary		List a locale's bundle versions
+//	@Description	The kept versions of the locale's bundle (the latest 20), the latest first
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			locale	path		string	true	"BCP 47 locale"
+//	@Success		200		{object}	response.SuccessResponse{data=[]dto.TranslationBundleVersion}
+//	@Failure		400		{object}	response.ErrorResponse	"Invalid locale"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/i18n/{locale}/versions [get]
+func (h *TranslationHandler) ListTranslationVersions(c *gin.Context) {
+	versions, err := h.translations.Versions(c.Request.Context(), c.Param("locale"))
+	if err != nil {
+		respondTranslationError(c, err, "Failed to list translation versions")
+		return
+	}
+	response.Success(c, versions)
+}
+
+// UploadTranslationBundle godoc
+//
+//	@Summary		Upload a translation bundle
+//	@Description	Store a new version of the locale's bundle, replacing the strings of the previous one entirely. Apps get it on their next fetch, other instances within FLAGS_REFRESH_INTERVAL.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			locale	path		string								true	"BCP 47 locale"
+//	@Param			request	body		dto.UploadTranslationBundleRequest	true	"Translations"
+//	@Success		200		{object}	response.SuccessResponse{data=dto.TranslationBundleVersion}
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed, invalid locale or key"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/i18n/{locale} [put]
+func (h *TranslationHandler) UploadTranslationBundle(c *gin.Context) {
+	var input dto.UploadTranslationBundleRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	version, err := h.translations.Upload(c.Request.Context(), c.Param("locale"), input)
+	if err != nil {
+		respondTranslationError(c, err, "Failed to upload translation bundle")
+		return
+	}
+
+	logger.Ctx(c).Info("Translation bundle uploaded",
+		zap.String("locale", version.Locale),
+		zap.Int("version", version.Version),
+	)
+	response.Success(c, version, "Translation bundle uploaded")
+}
+
+// RestoreTranslationVersion godoc
+//
+//	@Summary		Restore a bundle version
+//	@Description	Store a copy of a kept version as the locale's latest, e.g. to undo a bad upload
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			locale	path		string	true	"BCP 47 locale"
+//	@Param			version	path		int		true	"Version to restore"
+//	@Success		200		{object}	response.SuccessResponse{data=dto.TranslationBundleVersion}
+//	@Failure		400		{object}	response.ErrorResponse	"Invalid locale"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404		{object}	response.ErrorResponse	"Version not found"
+//	@Router			/admin/i18n/{locale}/versions/{version}/restore [post]
+func (h *TranslationHandler) RestoreTranslationVersion(c *gin.Context) {
+	restored, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		response.NotFound(c, "Translation bundle version")
+		return
+	}
+
+	version, err := h.translations.Restore(c.Request.Context(), c.Param("locale"), restored)
+	if err != nil {
+		respondTranslationError(c, err, "Failed to restore translation bundle")
+		return
+	}
+	if version == nil {
+		response.NotFound(c, "Translation bundle version")
+		return
+	}
+
+	logger.Ctx(c).Info("Translation bundle restored",
+		zap.String("locale", version.Locale),
+		zap.Int("restored_version", restored),
+		zap.Int("version", version.Version),
+	)
+	response.Success(c, version, "Translation bundle restored")
+}
+
+// DeleteTranslationLocale godoc
+//
+//	@Summary		Delete a locale's bundles
+//	@Description	Delete every version of the locale; the app falls back to its built-in strings
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			locale	path		string	true	"BCP 47 locale"
+//	@Success		200		{object}	response.SuccessResponse
+//	@Failure		400		{object}	response.ErrorResponse	"Invalid locale"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404		{object}	response.ErrorResponse	"Locale has no bundle"
+//	@Router			/admin/i18n/{locale} [delete]
+func (h *TranslationHandler) DeleteTranslationLocale(c *gin.Context) {
+	deleted, err := h.translations.Delete(c.Request.Context(), c.Param("locale"))
+	if err != nil {
+		respondTranslationError(c, err, "Failed to delete translation bundles")
+		return
+	}
+	if !deleted {
+		response.NotFound(c, "Translation bundle")
+		return
+	}
+
+	logger.Ctx(c).Info("Translation bundles deleted", zap.String("locale", c.Param("locale")))
+	response.Success(c, nil, "Translation bundles deleted")
+}
@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// tus protocol version and the extensions served
+const (
+	tusVersion    = "1.0.0"
+	tusExtensions = "creation,expiration,termination"
+)
+
+// tusContentType is the required Content-Type of PATCH requests
+const tusContentType = "application/offset+octet-stream"
+
+// UploadHandler serves resumable uploads over the tus 1.0.0 protocol
+// (https://tus.io/protocols/resumable-upload), so long recordings survive
+// network drops. Completed uploads are transcribed with POST /transcribe
+// upload_id=<id>.
+type UploadHandler struct {
+	uploads services.UploadService
+}
+
+func NewUploadHandler(uploads services.UploadService) *UploadHandler {
+	return &UploadHandler{uploads: uploads}
+}
+
+// tusRequest sets the Tus-Resumable response header and rejects requests of
+// another protocol version
+func tusRequest(c *gin.Context) bool {
+	c.Header("Tus-Resumable", tusVersion)
+	if c.GetHeader("Tus-Resumable") != tusVersion {
+		c.Header("Tus-Version", tusVersion)
+		response.Error(c, http.StatusPreconditionFailed, response.ErrCodeValidation, "Tus-Resumable must be "+tusVersion)
+		return false
+	}
+	return true
+}
+
+func respondUploadError(c *gin.Context, err error, message string) {
+	if appErr, ok := apperrors.AsAppError(err); ok {
+		apperrors.RespondError(c, appErr)
+		return
+	}
+	logger.Ctx(c).Error(message, zap.Error(err))
+	response.InternalError(c, message)
+}
+
+// respondInvalidUpload reports a file the upload validators refused: app
+// errors (e.g. MALWARE_DETECTED) as they are, others as bad requests
+func respondInvalidUpload(c *gin.Context, err error) {
+	if appErr, ok := apperrors.AsAppError(err); ok {
+		apperrors.RespondError(c, appErr)
+		return
+	}
+	response.BadRequest(c, err.Error())
+}
+
+// findUpload loads the :upload_id upload of the caller, answering 404 when
+// there is none
+func (h *UploadHandler) findUpload(c *gin.Context) (*models.Upload, bool) {
+	user := c.MustGet("user").(models.User)
+	upload, err := h.uploads.Find(c.Request.Context(), user.ID, c.Param("upload_id"))
+	if err != nil {
+		respondUploadError(c, err, "Failed to load upload")
+		return nil, false
+	}
+	if upload == nil {
+		response.NotFound(c, "Upload")
+		return nil, false
+	}
+	return upload, true
+}
+
+func setUploadHeaders(c *gin.Context, upload *models.Upload) {
+	c.Header("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	c.Header("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+}
+
+// Options godoc
+//
+//	@Summary		Discover the tus server
+//	@Description	tus 1.0.0 discovery: the supported version (Tus-Version), extensions (Tus-Extension) and maximum upload size (Tus-Max-Size)
+//	@Tags			Uploads
+//	@Success		204
+//	@Router			/uploads [options]
+func (h *UploadHandler) Options(c *gin.Context) {
+	c.Header("Tus-Resumable", tusVersion)
+	c.Header("Tus-Version", tusVersion)
+	c.Header("Tus-Extension", tusExtensions)
+	c.Header("Tus-Max-Size", strconv.FormatInt(h.uploads.MaxSize(), 10))
+	c.Status(http.StatusNoContent)
+}
+
+// Create godoc
+//
+//	@Summary		Start a resumable upload
+//	@Description	tus creation: starts an upload of Upload-Length bytes (at most Tus-Max-Size) and returns its URL in Location. Upload-Metadata may name the file (filename). Needs object storage.
+//	@Tags			Uploads
+//	@Security		BearerAuth
+//	@Param			Tus-Resumable	header	string	true	"1.0.0"
+//	@Param			Upload-Length	header	int		true	"Size of the file in bytes"
+//	@Param			Upload-Metadata	header	string	false	"tus metadata, e.g. filename <base64>"
+//	@Success		201
+//	@Failure		400	{object}	response.ErrorResponse	"Invalid Upload-Length or Upload-Metadata"
+//	@Failure		412	{object}	response.ErrorResponse	"Unsupported tus version"
+//	@Failure		413	{object}	response.ErrorResponse	"Upload-Length exceeds Tus-Max-Size"
+//	@Failure		503	{object}	response.ErrorResponse	"Object storage not configured"
+//	@Router			/uploads [post]
+func (h *UploadHandler) Create(c *gin.Context) {
+	if !tusRequest(c) {
+		return
+	}
+	length, err := strconv.ParseInt(c.GetHeader("Upload-Length"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Upload-Length is required")
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+	upload, err := h.uploads.Create(c.Request.Context(), user.ID, length, c.GetHeader("Upload-Metadata"))
+	if err != nil {
+		respondUploadError(c, err, "Failed to create upload")
+		return
+	}
+
+	c.Header("Location", c.Request.URL.Path+"/"+upload.ID)
+	c.Header("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	c.Status(http.StatusCreated)
+}
+
+// Head godoc
+//
+//	@Summary		Get the offset of a resumable upload
+//	@Description	tus: the bytes received so far (Upload-Offset), from which the client resumes
+//	@Tags			Uploads
+//	@Security		BearerAuth
+//	@Param			Tus-Resumable	header	string	true	"1.0.0"
+//	@Param			upload_id		path	string	true	"Upload ID"
+//	@Success		200
+//	@Failure		404	{object}	response.ErrorResponse	"Upload not found or expired"
+//	@Router			/uploads/{upload_id} [head]
+func (h *UploadHandler) Head(c *gin.Context) {
+	if !tusRequest(c) {
+		return
+	}
+	upload, ok := h.findUpload(c)
+	if !ok {
+		return
+	}
+
+	setUploadHeaders(c, upload)
+	c.Header("Upload-Length", strconv.FormatInt(upload.Length, 10))
+	if upload.Metadata != "" {
+		c.Header("Upload-Metadata", upload.Metadata)
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+}
+
+// Patch godoc
+//
+//	@Summary		Upload a chunk
+//	@Description	tus: appends the body (at most MAX_BODY_SIZE) at Upload-Offset, which must be the upload's current offset. A body cut off by the network is discarded; resume from the offset HEAD returns.
+//	@Tags			Uploads
+//	@Accept			application/offset+octet-stream
+//	@Security		BearerAuth
+//	@Param			Tus-Resumable	header	string	true	"1.0.0"
+//	@Param			Upload-Offset	header	int		true	"Offset of the chunk"
+//	@Param			upload_id		path	string	true	"Upload ID"
+//	@Success		204
+//	@Failure		404	{object}	response.ErrorResponse	"Upload not found or expired"
+//	@Failure		409	{object}	response.ErrorResponse	"Upload-Offset is not the upload's offset"
+//	@Failure		413	{object}	response.ErrorResponse	"The chunk exceeds Upload-Length"
+//	@Failure		415	{object}	response.ErrorResponse	"Content-Type is not application/offset+octet-stream"
+//	@Router			/uploads/{upload_id} [patch]
+func (h *UploadHandler) Patch(c *gin.Context) {
+	if !tusRequest(c) {
+		return
+	}
+	if c.ContentType() != tusContentType {
+		response.Error(c, http.StatusUnsupportedMediaType, response.ErrCodeInvalidInput, "Content-Type must be "+tusContentType)
+		return
+	}
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		response.BadRequest(c, "Upload-Offset is required")
+		return
+	}
+	upload, ok := h.findUpload(c)
+	if !ok {
+		return
+	}
+
+	upload, err = h.uploads.Append(c.Request.Context(), upload, offset, c.Request.Body, c.Request.ContentLength)
+	if err != nil {
+		respondUploadError(c, err, "Failed to store upload chunk")
+		return
+	}
+	setUploadHeaders(c, upload)
+	c.Status(http.StatusNoContent)
+}
+
+// Delete godoc
+//
+//	@Summary		Cancel a resumable upload
+//	@Description	tus termination: deletes the upload and the bytes received
+//	@Tags			Uploads
+//	@Security		BearerAuth
+//	@Param			Tus-Resumable	header	string	true	"1.0.0"
+//	@Param			upload_id		path	string	true	"Upload ID"
+//	@Success		204
+//	@Failure		404	{object}	response.ErrorResponse	"Upload not found or expired"
+//	@Router			/uploads/{upload_id} [delete]
+func (h *UploadHandler) Delete(c *gin.Context) {
+	if !tusRequest(c) {
+		return
+	}
+	user := c.MustGet("user").(models.User)
+	deleted, err := h.uploads.Delete(c.Request.Context(), user.ID, c.Param("upload_id"))
+	if err != nil {
+		respondUploadError(c, err, "Failed to delete upload")
+		return
+	}
+	if !deleted {
+		response.NotFound(c, "Upload")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
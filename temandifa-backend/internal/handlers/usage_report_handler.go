@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// UsageReportHandler serves monthly usage reports to their users and
+// billing exports to administrators
+type UsageReportHandler struct {
+	reports services.UsageReportService
+}
+
+func NewUsageReportHandler(reports services.UsageReportService) *UsageReportHandler {
+	return &UsageReportHandler{reports: reports}
+}
+
+// respondUsageReportError answers validation errors as such and anything else as 500
+func respondUsageReportError(c *gin.Context, err error, message string) {
+	if appErr, ok := apperrors.AsAppError(err); ok {
+		apperrors.RespondError(c, appErr)
+		return
+	}
+	logger.Ctx(c).Error(message, zap.Error(err))
+	response.InternalError(c, message)
+}
+
+// bindUsageReportQuery binds the download format, answering 400 when it is invalid
+func bindUsageReportQuery(c *gin.Context) (dto.UsageReportQuery, bool) {
+	var query dto.UsageReportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return query, false
+	}
+	return query, true
+}
+
+// startCSVDownload sets the headers of a CSV attachment named filename
+func startCSVDownload(c *gin.Context, filename string) {
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+	c.Writer.Header().Set("Content-Type", "text/csv; charset=utf-8")
+}
+
+// ListMyReports godoc
+//
+//	@Summary		List my usage reports
+//	@Description	The caller's monthly usage reports, the latest month first. A month's report is generated shortly after it ends (UTC).
+//	@Tags			Account
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=[]dto.UsageReport}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Router			/me/reports [get]
+func (h *UsageReportHandler) ListMyReports(c *gin.Context) {
+	reports, err := h.reports.List(c.Request.Context(), c.MustGet("user").(models.User).ID)
+	if err != nil {
+		logger.Ctx(c).Error("Failed to list usage reports", zap.Error(err))
+		response.InternalError(c, "Failed to list usage reports")
+		return
+	}
+	response.Success(c, reports)
+}
+
+// GetMyReport godoc
+//
+//	@Summary		Download my usage report
+//	@Description	The caller's usage over a UTC month: AI calls by operation, cache hits (calls answered without the AI Service) and attachment storage, as JSON or as a CSV download
+//	@Tags			Account
+//	@Produce		json,text/csv
+//	@Security		BearerAuth
+//	@Param			month	path		string	true	"Month (YYYY-MM)"
+//	@Param			format	query		string	false	"json or csv"	default(json)
+//	@Success		200		{object}	response.SuccessResponse{data=dto.UsageReport}
+//	@Failure		400		{object}	response.ErrorResponse	"Invalid month or format"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		404		{object}	response.ErrorResponse	"No report for the month (yet)"
+//	@Router			/me/reports/{month} [get]
+func (h *UsageReportHandler) GetMyReport(c *gin.Context) {
+	query, ok := bindUsageReportQuery(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.reports.Find(c.Request.Context(), c.MustGet("user").(models.User).ID, c.Param("month"))
+	if err != nil {
+		respondUsageReportError(c, err, "Failed to load usage report")
+		return
+	}
+	if report == nil {
+		response.NotFound(c, "Usage report")
+		return
+	}
+
+	if query.Format != "csv" {
+		response.Success(c, report)
+		return
+	}
+	startCSVDownload(c, fmt.Sprintf("temandifa-usage-%s.csv", report.Month))
+	if err := services.WriteUsageReportsCSV(c.Writer, []dto.UsageReport{*report}, false); err != nil {
+		logger.Ctx(c).Warn("Usage report download interrupted", zap.Error(err))
+	}
+}
+
+// ExportUsageReports godoc
+//
+//	@Summary		Export a month's usage reports
+//	@Description	Every user's usage report of a UTC month with their email, for billing: a page as JSON, or all of them as a CSV download. Reports are generated shortly after the month ends; run the usage_reports job to regenerate last month's.
+//	@Tags			Admin
+//	@Produce		json,text/csv
+//	@Security		BearerAuth
+//	@Param			month	path		string	true	"Month (YYYY-MM)"
+//	@Param			format	query		string	false	"json or csv"		default(json)
+//	@Param			page	query		int		false	"Page number (JSON)"	default(1)
+//	@Param			limit	query		int		false	"Items per page (JSON)"	default(20)
+//	@Success		200		{object}	response.SuccessResponse{data=[]dto.UsageReport,meta=helpers.Pagination}
+//	@Failure		400		{object}	response.ErrorResponse	"Invalid month or format"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/reports/usage/{month} [get]
+func (h *UsageReportHandler) ExportUsageReports(c *gin.Context) {
+	query, ok := bindUsageReportQuery(c)
+	if !ok {
+		return
+	}
+	month := c.Param("month")
+
+	if query.Format != "csv" {
+		pagination := helpers.NewPagination(c)
+		reports, total, err := h.reports.Month(c.Request.Context(), month, pagination.Page, pagination.Limit)
+		if err != nil {
+			respondUsageReportError(c, err, "Failed to list usage reports")
+			return
+		}
+		pagination.SetTotal(total)
+		response.SuccessWithMeta(c, reports, pagination.ToMeta())
+		return
+	}
+
+	// Validated before the response starts, so a bad month still gets a 400
+	if _, err := services.ParseMonth(month); err != nil {
+		respondUsageReportError(c, err, "Failed to export usage reports")
+		return
+	}
+	startCSVDownload(c, fmt.Sprintf("temandifa-usage-%s-all.csv", month))
+	if err := h.reports.ExportMonth(c.Request.Context(), month, c.Writer); err != nil {
+		logger.Ctx(c).Error("Usage report export interrupted", zap.String("month", month), zap.Error(err))
+		return
+	}
+	logger.Ctx(c).Info("Usage reports exported", zap.String("month", month))
+}
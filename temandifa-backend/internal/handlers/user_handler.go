@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// UserHandler serves users their own profile
+type UserHandler struct {
+	users services.UserService
+}
+
+func NewUserHandler(users services.UserService) *UserHandler {
+	return &UserHandler{users: users}
+}
+
+// respondUserError answers app errors as such and anything else as 500
+func respondUserError(c *gin.Context, err error, message string) {
+	if appErr, ok := apperrors.AsAppError(err); ok {
+		apperrors.RespondError(c, appErr)
+		return
+	}
+	logger.Ctx(c).Error(message, zap.Error(err))
+	response.InternalError(c, message)
+}
+
+// GetMe godoc
+//
+//	@Summary		Get my profile
+//	@Description	The caller's profile with their plan and what is left of its daily AI quota
+//	@Tags			Account
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=dto.Me}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Router			/me [get]
+func (h *UserHandler) GetMe(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	me, err := h.users.GetProfile(c.Request.Context(), user.ID)
+	if err != nil {
+		respondUserError(c, err, "Failed to load profile")
+		return
+	}
+	response.Success(c, me)
+}
+
+// UpdateMe godoc
+//
+//	@Summary		Update my profile
+//	@Description	Change the caller's name, preferred language (BCP 47, empty to follow the device) or avatar URL (https, empty to remove it). Omitted fields keep their value.
+//	@Tags			Account
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			input	body		dto.UpdateProfileRequest	true	"Profile fields to change"
+//	@Success		200		{object}	response.SuccessResponse{data=dto.Me}
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		409		{object}	response.ErrorResponse	"Profile changed concurrently"
+//	@Router			/me [patch]
+func (h *UserHandler) UpdateMe(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	var input dto.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	me, err := h.users.UpdateProfile(c.Request.Context(), user.ID, input)
+	if err != nil {
+		respondUserError(c, err, "Failed to update profile")
+		return
+	}
+	logger.Ctx(c).Info("Profile updated", zap.Uint("user_id", user.ID))
+	response.Success(c, me, "Profile updated")
+}
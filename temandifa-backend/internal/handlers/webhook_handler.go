@@ -0,0 +1,290 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// WebhookHandler lets administrators manage third parties' webhook
+// subscriptions
+type WebhookHandler struct {
+	webhooks services.WebhookService
+}
+
+func NewWebhookHandler(webhooks services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhooks: webhooks}
+}
+
+// respondWebhookError answers validation errors as such and anything else as 500
+func respondWebhookError(c *gin.Context, err error, message string) {
+	if appErr, ok := apperrors.AsAppError(err); ok {
+		apperrors.RespondError(c, appErr)
+		return
+	}
+	logger.Ctx(c).Error(message, zap.Error(err))
+	response.InternalError(c, message)
+}
+
+// webhookID parses the subscription ID of the route; false when it isn't one
+func webhookID(c *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.NotFound(c, "Webhook subscription")
+		return 0, false
+	}
+	return id, true
+}
+
+// ListWebhooks godoc
+//
+//	@Summary		List webhook subscriptions
+//	@Description	Every third-party webhook subscription, without secrets
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=[]dto.WebhookSubscription}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	subscriptions, err := h.webhooks.List(c.Request.Context())
+	if err != nil {
+		logger.Ctx(c).Error("Failed to list webhook subscriptions", zap.Error(err))
+		response.InternalError(c, "Failed to list webhook subscriptions")
+		return
+	}
+	response.Success(c, subscriptions)
+}
+
+// GetWebhook godoc
+//
+//	@Summary		Get a webhook subscription
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Subscription ID"
+//	@Success		200	{object}	response.SuccessResponse{data=dto.WebhookSubscription}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404	{object}	response.ErrorResponse	"Subscription not found"
+//	@Router			/admin/webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	subscription, err := h.webhooks.Get(c.Request.Context(), id)
+	if err != nil {
+		logger.Ctx(c).Error("Failed to load webhook subscription", zap.Error(err))
+		response.InternalError(c, "Failed to load webhook subscription")
+		return
+	}
+	if subscription == nil {
+		response.NotFound(c, "Webhook subscription")
+		return
+	}
+	response.Success(c, subscription)
+}
+
+// CreateWebhook godoc
+//
+//	@Summary		Create a webhook subscription
+//	@Description	Subscribe an endpoint to event types (user.registered, user.data_export_ready, user.deleted). Deliveries are signed with X-Temandifa-Signature, an HMAC-SHA256 over "<X-Temandifa-Timestamp>.<body>" with the subscription's secret, which is only returned here.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.CreateWebhookRequest	true	"Subscription"
+//	@Success		201		{object}	response.SuccessResponse{data=dto.WebhookSubscription}
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed, invalid URL or unknown event type"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Router			/admin/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var input dto.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	subscription, err := h.webhooks.Create(c.Request.Context(), input)
+	if err != nil {
+		respondWebhookError(c, err, "Failed to create webhook subscription")
+		return
+	}
+
+	logger.Ctx(c).Info("Webhook subscription created",
+		zap.Uint64("subscription_id", subscription.ID),
+		zap.Strings("event_types", subscription.EventTypes),
+	)
+	response.Created(c, subscription, "Webhook subscription created")
+}
+
+// UpdateWebhook godoc
+//
+//	@Summary		Update a webhook subscription
+//	@Description	Replace a subscription's name, URL, event types and active state; rotate_secret issues a new secret, returned in the response
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int							true	"Subscription ID"
+//	@Param			request	body		dto.UpdateWebhookRequest	true	"Subscription"
+//	@Success		200		{object}	response.SuccessResponse{data=dto.WebhookSubscription}
+//	@Failure		400		{object}	response.ErrorResponse	"Validation failed, invalid URL or unknown event type"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404		{object}	response.ErrorResponse	"Subscription not found"
+//	@Router			/admin/webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	var input dto.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		validationErrors := helpers.FormatValidationError(err)
+		response.Error(c, http.StatusBadRequest, response.ErrCodeValidation, "Validation failed", validationErrors)
+		return
+	}
+
+	subscription, err := h.webhooks.Update(c.Request.Context(), id, input)
+	if err != nil {
+		respondWebhookError(c, err, "Failed to update webhook subscription")
+		return
+	}
+	if subscription == nil {
+		response.NotFound(c, "Webhook subscription")
+		return
+	}
+
+	logger.Ctx(c).Info("Webhook subscription updated",
+		zap.Uint64("subscription_id", id),
+		zap.Bool("active", subscription.Active),
+		zap.Bool("secret_rotated", input.RotateSecret),
+	)
+	response.Success(c, subscription, "Webhook subscription updated")
+}
+
+// DeleteWebhook godoc
+//
+//	@Summary		Delete a webhook subscription
+//	@Description	Delete a subscription with its delivery log
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Subscription ID"
+//	@Success		200	{object}	response.SuccessResponse
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404	{object}	response.ErrorResponse	"Subscription not found"
+//	@Router			/admin/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	deleted, err := h.webhooks.Delete(c.Request.Context(), id)
+	if err != nil {
+		logger.Ctx(c).Error("Failed to delete webhook subscription", zap.Error(err))
+		response.InternalError(c, "Failed to delete webhook subscription")
+		return
+	}
+	if !deleted {
+		response.NotFound(c, "Webhook subscription")
+		return
+	}
+
+	logger.Ctx(c).Info("Webhook subscription deleted", zap.Uint64("subscription_id", id))
+	response.Success(c, nil, "Webhook subscription deleted")
+}
+
+// ListWebhookDeliveries godoc
+//
+//	@Summary		List webhook deliveries
+//	@Description	A subscription's delivery log, the latest first, kept for WEBHOOK_DELIVERY_RETENTION. Each entry is one delivery with the attempts made in a row; failed event deliveries are retried with the event.
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int	true	"Subscription ID"
+//	@Param			page	query		int	false	"Page number"		default(1)
+//	@Param			limit	query		int	false	"Items per page"	default(20)
+//	@Success		200		{object}	response.SuccessResponse{data=[]models.WebhookDelivery,meta=helpers.Pagination}
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404		{object}	response.ErrorResponse	"Subscription not found"
+//	@Router			/admin/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListWebhookDeliveries(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	subscription, err := h.webhooks.Get(c.Request.Context(), id)
+	if err != nil {
+		logger.Ctx(c).Error("Failed to load webhook subscription", zap.Error(err))
+		response.InternalError(c, "Failed to load webhook subscription")
+		return
+	}
+	if subscription == nil {
+		response.NotFound(c, "Webhook subscription")
+		return
+	}
+
+	pagination := helpers.NewPagination(c)
+	deliveries, total, err := h.webhooks.Deliveries(c.Request.Context(), id, pagination.Page, pagination.Limit)
+	if err != nil {
+		logger.Ctx(c).Error("Failed to list webhook deliveries", zap.Error(err))
+		response.InternalError(c, "Failed to list webhook deliveries")
+		return
+	}
+
+	pagination.SetTotal(total)
+	response.SuccessWithMeta(c, deliveries, pagination.ToMeta())
+}
+
+// TestWebhook godoc
+//
+//	@Summary		Send a test delivery
+//	@Description	Send a signed webhook.test event to the subscription (also when inactive) and return the logged delivery. It is not retried later.
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"Subscription ID"
+//	@Success		200	{object}	response.SuccessResponse{data=models.WebhookDelivery}
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden (Admin only)"
+//	@Failure		404	{object}	response.ErrorResponse	"Subscription not found"
+//	@Router			/admin/webhooks/{id}/test [post]
+func (h *WebhookHandler) TestWebhook(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhooks.Test(c.Request.Context(), id)
+	if err != nil {
+		logger.Ctx(c).Error("Failed to send test webhook", zap.Error(err))
+		response.InternalError(c, "Failed to send test webhook")
+		return
+	}
+	if delivery == nil {
+		response.NotFound(c, "Webhook subscription")
+		return
+	}
+	response.Success(c, delivery)
+}
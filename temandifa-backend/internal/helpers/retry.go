@@ -38,7 +38,7 @@ func WithRetry(ctx context.Context, config RetryConfig, operation string, fn Ret
 
 	for attempt := 0; attempt <= config.MaxRetries; attempt++ {
 		if attempt > 0 {
-			logger.Debug("Retrying operation",
+			logger.Ctx(ctx).Debug("Retrying operation",
 				zap.String("operation", operation),
 				zap.Int("attempt", attempt),
 				zap.Duration("backoff", backoff),
@@ -61,7 +61,7 @@ func WithRetry(ctx context.Context, config RetryConfig, operation string, fn Ret
 		lastErr = fn()
 		if lastErr == nil {
 			if attempt > 0 {
-				logger.Debug("Retry succeeded",
+				logger.Ctx(ctx).Debug("Retry succeeded",
 					zap.String("operation", operation),
 					zap.Int("attempt", attempt),
 				)
@@ -70,7 +70,7 @@ func WithRetry(ctx context.Context, config RetryConfig, operation string, fn Ret
 		}
 	}
 
-	logger.Error("All retries exhausted",
+	logger.Ctx(ctx).Error("All retries exhausted",
 		zap.String("operation", operation),
 		zap.Int("max_retries", config.MaxRetries),
 		zap.Error(lastErr),
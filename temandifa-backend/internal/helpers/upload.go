@@ -1,6 +1,7 @@
 package helpers
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -8,38 +9,13 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync/atomic"
 
 	"go.uber.org/zap"
 
 	"temandifa-backend/internal/logger"
 )
 
-// File size limits (in bytes)
-const (
-	MaxImageSize = 10 * 1024 * 1024 // 10MB
-	MaxAudioSize = 25 * 1024 * 1024 // 25MB
-)
-
-// Allowed MIME types
-var (
-	AllowedImageTypes = map[string]bool{
-		"image/jpeg": true,
-		"image/png":  true,
-		"image/webp": true,
-		"image/gif":  true,
-	}
-	AllowedAudioTypes = map[string]bool{
-		"audio/mpeg":  true,
-		"audio/wav":   true,
-		"audio/x-wav": true,
-		"audio/webm":  true,
-		"audio/ogg":   true,
-		"audio/mp4":   true,
-		"audio/m4a":   true,
-		"video/webm":  true, // Often used for audio
-	}
-)
-
 // UploadedFile contains validated file data
 type UploadedFile struct {
 	Content  []byte
@@ -48,18 +24,39 @@ type UploadedFile struct {
 	Size     int64
 }
 
-// ValidateImageUpload validates and reads an uploaded image file
-func ValidateImageUpload(header *multipart.FileHeader, file multipart.File) (*UploadedFile, error) {
-	return validateUpload(header, file, MaxImageSize, AllowedImageTypes, "image")
+// UploadScanner checks validated uploads for malware. Scan returns an error
+// to reject the upload; with no scanner installed uploads aren't scanned.
+type UploadScanner interface {
+	Scan(content []byte, filename, mimeType string) error
 }
 
-// ValidateAudioUpload validates and reads an uploaded audio file
-func ValidateAudioUpload(header *multipart.FileHeader, file multipart.File) (*UploadedFile, error) {
-	return validateUpload(header, file, MaxAudioSize, AllowedAudioTypes, "audio")
+var uploadScanner atomic.Pointer[UploadScanner]
+
+// SetUploadScanner installs the scanner run by the upload validators (nil
+// removes it)
+func SetUploadScanner(scanner UploadScanner) {
+	if scanner == nil {
+		uploadScanner.Store(nil)
+		return
+	}
+	uploadScanner.Store(&scanner)
+}
+
+// ValidateImageUpload validates and reads an uploaded image file against
+// the operation's limit (UPLOAD_* config)
+func ValidateImageUpload(ctx context.Context, header *multipart.FileHeader, file multipart.File, maxSize int64, allowedTypes map[string]bool) (*UploadedFile, error) {
+	return validateUpload(ctx, header, file, maxSize, allowedTypes, "image")
+}
+
+// ValidateAudioUpload validates and reads an uploaded audio file against
+// the operation's limit (UPLOAD_* config)
+func ValidateAudioUpload(ctx context.Context, header *multipart.FileHeader, file multipart.File, maxSize int64, allowedTypes map[string]bool) (*UploadedFile, error) {
+	return validateUpload(ctx, header, file, maxSize, allowedTypes, "audio")
 }
 
 // validateUpload is the generic validation function
 func validateUpload(
+	ctx context.Context,
 	header *multipart.FileHeader,
 	file multipart.File,
 	maxSize int64,
@@ -68,7 +65,7 @@ func validateUpload(
 ) (*UploadedFile, error) {
 	// Check file size
 	if header.Size > maxSize {
-		logger.Debug("File too large",
+		logger.Ctx(ctx).Debug("File too large",
 			zap.String("filename", header.Filename),
 			zap.Int64("size", header.Size),
 			zap.Int64("max", maxSize),
@@ -79,33 +76,61 @@ func validateUpload(
 	// Read file content
 	content, err := io.ReadAll(file)
 	if err != nil {
-		logger.Error("Failed to read file", zap.Error(err))
+		logger.Ctx(ctx).Error("Failed to read file", zap.Error(err))
 		return nil, fmt.Errorf("failed to read file")
 	}
 
-	// Detect MIME type from content (magic bytes)
+	return validateContent(ctx, content, header.Filename, allowedTypes, fileType)
+}
+
+// ValidateUploadContent validates file content received outside a multipart
+// form (e.g. over gRPC) against the operation's limit
+func ValidateUploadContent(ctx context.Context, content []byte, filename string, maxSize int64, allowedTypes map[string]bool, fileType string) (*UploadedFile, error) {
+	if int64(len(content)) > maxSize {
+		logger.Ctx(ctx).Debug("File too large",
+			zap.String("filename", filename),
+			zap.Int("size", len(content)),
+			zap.Int64("max", maxSize),
+		)
+		return nil, fmt.Errorf("file too large: max %d MB allowed", maxSize/(1024*1024))
+	}
+	if len(content) == 0 {
+		return nil, fmt.Errorf("empty %s file", fileType)
+	}
+	return validateContent(ctx, content, filename, allowedTypes, fileType)
+}
+
+// validateContent checks the content's MIME type, detected from its magic
+// bytes, and scans it when an UploadScanner is installed
+func validateContent(ctx context.Context, content []byte, filename string, allowedTypes map[string]bool, fileType string) (*UploadedFile, error) {
 	mimeType := http.DetectContentType(content)
 
 	// Check if MIME type is allowed
 	if !allowedTypes[mimeType] {
-		logger.Debug("Invalid file type",
-			zap.String("filename", header.Filename),
+		logger.Ctx(ctx).Debug("Invalid file type",
+			zap.String("filename", filename),
 			zap.String("detected_mime", mimeType),
 		)
 		return nil, fmt.Errorf("invalid %s format: %s not allowed", fileType, mimeType)
 	}
 
-	logger.Debug("File validated successfully",
-		zap.String("filename", header.Filename),
+	if scanner := uploadScanner.Load(); scanner != nil {
+		if err := (*scanner).Scan(content, SanitizeFilename(filename), mimeType); err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Ctx(ctx).Debug("File validated successfully",
+		zap.String("filename", filename),
 		zap.String("mime", mimeType),
-		zap.Int64("size", header.Size),
+		zap.Int("size", len(content)),
 	)
 
 	return &UploadedFile{
 		Content:  content,
-		Filename: SanitizeFilename(header.Filename),
+		Filename: SanitizeFilename(filename),
 		MimeType: mimeType,
-		Size:     header.Size,
+		Size:     int64(len(content)),
 	}, nil
 }
 
@@ -97,3 +97,12 @@ func ValidatePasswordStrength(password string) []string {
 
 	return issues
 }
+
+// deviceIDPattern matches the IDs apps generate for their installation
+// (UUIDs, Android IDs, identifierForVendor)
+var deviceIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// ValidDeviceID reports whether id can identify a device
+func ValidDeviceID(id string) bool {
+	return deviceIDPattern.MatchString(id)
+}
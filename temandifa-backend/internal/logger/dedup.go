@@ -0,0 +1,114 @@
+package logger
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// dedupMaxKeys bounds the number of distinct messages tracked at once
+const dedupMaxKeys = 1024
+
+// dedupCore wraps a zapcore.Core and suppresses identical messages
+// (same level + message) repeated within a time window.
+// When a message reappears after its window expired, the next entry carries
+// a "suppressed_duplicates" field with the number of entries dropped.
+type dedupCore struct {
+	zapcore.Core
+	window   time.Duration
+	minLevel zapcore.Level
+	state    *dedupState
+}
+
+type dedupState struct {
+	mu      sync.Mutex
+	entries map[string]*dedupEntry
+}
+
+type dedupEntry struct {
+	windowStart time.Time
+	suppressed  int
+}
+
+// newDedupCore creates a de-duplicating core. Entries below minLevel are
+// passed through untouched so regular request logs are never dropped.
+func newDedupCore(core zapcore.Core, window time.Duration, minLevel zapcore.Level) zapcore.Core {
+	return &dedupCore{
+		Core:     core,
+		window:   window,
+		minLevel: minLevel,
+		state:    &dedupState{entries: make(map[string]*dedupEntry)},
+	}
+}
+
+// With keeps the shared de-duplication state for child loggers
+func (c *dedupCore) With(fields []zapcore.Field) zapcore.Core {
+	return &dedupCore{
+		Core:     c.Core.With(fields),
+		window:   c.window,
+		minLevel: c.minLevel,
+		state:    c.state,
+	}
+}
+
+// Check decides whether the entry is logged, suppressed, or logged with a
+// summary of previously suppressed duplicates
+func (c *dedupCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) {
+		return ce
+	}
+	if ent.Level < c.minLevel {
+		return c.Core.Check(ent, ce)
+	}
+
+	suppressed, allow := c.state.observe(ent.Level.String()+"|"+ent.Message, ent.Time, c.window)
+	if !allow {
+		return ce
+	}
+	if suppressed > 0 {
+		return c.Core.With([]zapcore.Field{zap.Int("suppressed_duplicates", suppressed)}).Check(ent, ce)
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// observe records an occurrence of key and reports whether it should be logged,
+// along with how many duplicates were dropped in the previous window
+func (s *dedupState) observe(key string, now time.Time, window time.Duration) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, exists := s.entries[key]
+	if exists && now.Sub(entry.windowStart) < window {
+		entry.suppressed++
+		return 0, false
+	}
+
+	suppressed := 0
+	if exists {
+		suppressed = entry.suppressed
+		entry.windowStart = now
+		entry.suppressed = 0
+		return suppressed, true
+	}
+
+	if len(s.entries) >= dedupMaxKeys {
+		s.prune(now, window)
+	}
+	s.entries[key] = &dedupEntry{windowStart: now}
+	return suppressed, true
+}
+
+// prune drops expired entries; if everything is still active the map is reset
+// so memory stays bounded during noisy incidents
+func (s *dedupState) prune(now time.Time, window time.Duration) {
+	for key, entry := range s.entries {
+		if now.Sub(entry.windowStart) >= window {
+			delete(s.entries, key)
+		}
+	}
+	if len(s.entries) >= dedupMaxKeys {
+		s.entries = make(map[string]*dedupEntry)
+	}
+}
@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// FileConfig holds rotating log file configuration
+type FileConfig struct {
+	Path       string // Log file path; empty disables file logging
+	MaxSizeMB  int    // Rotate after this many megabytes
+	MaxBackups int    // Number of rotated files to keep
+	MaxAgeDays int    // Delete rotated files older than this
+	Compress   bool   // Gzip rotated files
+}
+
+// newFileCore creates a JSON core writing to a size-rotated file.
+// JSON is always used for files regardless of the console format so the
+// output can be shipped or grepped later without a collector.
+func newFileCore(cfg FileConfig, level zapcore.LevelEnabler) zapcore.Core {
+	writer := &lumberjack.Logger{
+		Filename:   cfg.Path,
+		MaxSize:    cfg.MaxSizeMB,
+		MaxBackups: cfg.MaxBackups,
+		MaxAge:     cfg.MaxAgeDays,
+		Compress:   cfg.Compress,
+	}
+
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	return zapcore.NewCore(
+		zapcore.NewJSONEncoder(encoderConfig),
+		zapcore.AddSync(writer),
+		level,
+	)
+}
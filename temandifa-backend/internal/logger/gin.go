@@ -1,31 +1,113 @@
 package logger
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+
+	"temandifa-backend/internal/metrics"
+	"temandifa-backend/internal/negotiate"
 )
 
-// GinRecovery returns a Gin middleware for panic recovery with logging
-func GinRecovery() gin.HandlerFunc {
+// fingerprintFrames is the number of application frames hashed into a panic fingerprint
+const fingerprintFrames = 5
+
+// PanicReport describes a recovered panic for forwarding to an error tracker
+type PanicReport struct {
+	Fingerprint string // Stable hash of panic type + top frames, for grouping
+	Value       string
+	Stack       string
+	RequestID   string
+	Method      string
+	Route       string
+	Path        string
+}
+
+// PanicReporter forwards recovered panics (e.g. to an error tracker or alert webhook)
+type PanicReporter func(report PanicReport)
+
+// GinRecovery returns a Gin middleware for panic recovery with logging.
+// The stack trace, request ID, and a fingerprint grouping identical panics are
+// logged; a panic counter is incremented and optional reporters are notified.
+func GinRecovery(reporters ...PanicReporter) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer func() {
 			if err := recover(); err != nil {
-				Log.Error("Panic recovered",
+				stack := string(debug.Stack())
+				fingerprint := panicFingerprint(err)
+				route := c.FullPath()
+				if route == "" {
+					route = "unmatched"
+				}
+
+				metrics.PanicsRecovered.WithLabelValues(route).Inc()
+
+				FromContext(c).Error("Panic recovered",
 					zap.Any("error", err),
+					zap.String("fingerprint", fingerprint),
+					zap.String("route", route),
 					zap.String("path", c.Request.URL.Path),
 					zap.String("method", c.Request.Method),
+					zap.String("stack", stack),
 				)
 
-				c.AbortWithStatusJSON(500, gin.H{
+				report := PanicReport{
+					Fingerprint: fingerprint,
+					Value:       fmt.Sprint(err),
+					Stack:       stack,
+					RequestID:   RequestIDFromContext(c),
+					Method:      c.Request.Method,
+					Route:       route,
+					Path:        c.Request.URL.Path,
+				}
+				for _, reporter := range reporters {
+					go reporter(report)
+				}
+
+				body := gin.H{
 					"success": false,
 					"error": gin.H{
 						"code":    "INTERNAL_ERROR",
 						"message": "Internal server error",
 					},
-				})
+					"request_id": RequestIDFromContext(c),
+				}
+				if traceID := TraceIDFromContext(c); traceID != "" {
+					body["trace_id"] = traceID
+				}
+				negotiate.Abort(c, 500, body)
 			}
 		}()
 
 		c.Next()
 	}
 }
+
+// panicFingerprint hashes the panic value's type and the top application
+// frames, so the same bug groups together regardless of request data
+func panicFingerprint(value any) string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	parts := []string{fmt.Sprintf("%T", value)}
+	for len(parts) <= fingerprintFrames {
+		frame, more := frames.Next()
+		// Skip runtime internals (gopanic, panicmem, ...) to start at the panic site
+		if !strings.HasPrefix(frame.Function, "runtime.") && frame.Function != "" {
+			parts = append(parts, frame.Function)
+		}
+		if !more {
+			break
+		}
+	}
+
+	hash := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(hash[:6])
+}
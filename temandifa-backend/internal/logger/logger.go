@@ -1,7 +1,11 @@
 package logger
 
 import (
+	"context"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -16,6 +20,19 @@ type Config struct {
 	Level      string // debug, info, warn, error
 	Format     string // json, console
 	TimeFormat string // ISO8601, epoch, etc.
+
+	// Sampling: per second, log the first SamplingInitial entries with the same
+	// level+message, then every SamplingThereafter-th. Zero disables sampling.
+	SamplingInitial    int
+	SamplingThereafter int
+
+	// De-duplication: identical messages at DedupLevel or above are logged once
+	// per DedupWindow, with a count of suppressed repeats. Zero disables it.
+	DedupWindow time.Duration
+	DedupLevel  string
+
+	// File: optional rotating JSON log file written in addition to stdout
+	File FileConfig
 }
 
 // DefaultConfig returns default logger configuration
@@ -24,6 +41,19 @@ func DefaultConfig() Config {
 		Level:      "info",
 		Format:     "console",
 		TimeFormat: "ISO8601",
+
+		SamplingInitial:    100,
+		SamplingThereafter: 100,
+
+		DedupWindow: 10 * time.Second,
+		DedupLevel:  "error",
+
+		File: FileConfig{
+			MaxSizeMB:  100,
+			MaxBackups: 5,
+			MaxAgeDays: 30,
+			Compress:   true,
+		},
 	}
 }
 
@@ -47,8 +77,32 @@ func Init(cfg Config) {
 		config.DisableCaller = true
 	}
 
+	// Sampling keeps log volume bounded under load
+	if cfg.SamplingInitial > 0 && cfg.SamplingThereafter > 0 {
+		config.Sampling = &zap.SamplingConfig{
+			Initial:    cfg.SamplingInitial,
+			Thereafter: cfg.SamplingThereafter,
+		}
+	} else {
+		config.Sampling = nil
+	}
+
+	var opts []zap.Option
+	if cfg.File.Path != "" {
+		fileCore := newFileCore(cfg.File, config.Level)
+		opts = append(opts, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return zapcore.NewTee(core, fileCore)
+		}))
+	}
+	if cfg.DedupWindow > 0 {
+		dedupLevel := parseLevel(cfg.DedupLevel)
+		opts = append(opts, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return newDedupCore(core, cfg.DedupWindow, dedupLevel)
+		}))
+	}
+
 	var err error
-	Log, err = config.Build()
+	Log, err = config.Build(opts...)
 	if err != nil {
 		panic("Failed to initialize logger: " + err.Error())
 	}
@@ -68,7 +122,47 @@ func InitFromEnv() {
 		cfg.Format = format
 	}
 
-	// Use JSON in production
+	if initial, err := strconv.Atoi(os.Getenv("LOG_SAMPLING_INITIAL")); err == nil {
+		cfg.SamplingInitial = initial
+	}
+
+	if thereafter, err := strconv.Atoi(os.Getenv("LOG_SAMPLING_THEREAFTER")); err == nil {
+		cfg.SamplingThereafter = thereafter
+	}
+
+	if window, err := time.ParseDuration(os.Getenv("LOG_DEDUP_WINDOW")); err == nil {
+		cfg.DedupWindow = window
+	}
+
+	if level := os.Getenv("LOG_DEDUP_LEVEL"); level != "" {
+		cfg.DedupLevel = level
+	}
+
+	cfg.File.Path = os.Getenv("LOG_FILE")
+
+	if maxSize, err := strconv.Atoi(os.Getenv("LOG_MAX_SIZE")); err == nil {
+		cfg.File.MaxSizeMB = maxSize
+	}
+
+	if maxBackups, err := strconv.Atoi(os.Getenv("LOG_MAX_BACKUPS")); err == nil {
+		cfg.File.MaxBackups = maxBackups
+	}
+
+	if maxAge, err := strconv.Atoi(os.Getenv("LOG_MAX_AGE")); err == nil {
+		cfg.File.MaxAgeDays = maxAge
+	}
+
+	if compress, err := strconv.ParseBool(os.Getenv("LOG_COMPRESS")); err == nil {
+		cfg.File.Compress = compress
+	}
+
+	// Use JSON (and no debug logs) in production. APP_ENV is checked directly
+	// because the logger is initialized before config is loaded.
+	switch strings.ToLower(os.Getenv("APP_ENV")) {
+	case "staging", "stage", "prod", "production":
+		cfg.Format = "json"
+		cfg.Level = "info"
+	}
 	if os.Getenv("GIN_MODE") == "release" {
 		cfg.Format = "json"
 		cfg.Level = "info"
@@ -132,24 +226,96 @@ const (
 	RequestIDKey contextKey = "request_id"
 	// UserIDKey is the context key for user ID
 	UserIDKey contextKey = "user_id"
+	// TraceIDKey is the context key for the distributed trace ID (when tracing is enabled)
+	TraceIDKey contextKey = "trace_id"
+	// LoggerKey is the context key for the request-scoped logger
+	LoggerKey contextKey = "logger"
 )
 
-// FromContext creates a logger with request_id and user_id fields from context
+// WithLogger returns a copy of ctx carrying a request-scoped logger
+func WithLogger(ctx context.Context, log *zap.Logger) context.Context {
+	return context.WithValue(ctx, LoggerKey, log)
+}
+
+// Ctx returns the request-scoped logger stored by middleware.ContextLogger
+// (request_id, trace_id, and user_id pre-attached). Outside a request it
+// falls back to a logger built from whatever IDs the context carries.
+func Ctx(ctx interface{ Value(any) any }) *zap.Logger {
+	if log, ok := ctx.Value(LoggerKey).(*zap.Logger); ok {
+		return log
+	}
+	// Fallback for gin context keys
+	if log, ok := ctx.Value(string(LoggerKey)).(*zap.Logger); ok {
+		return log
+	}
+	return FromContext(ctx)
+}
+
+// ContextWithRequestID returns a copy of ctx carrying the request ID
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, RequestIDKey, requestID)
+}
+
+// ContextWithUserID returns a copy of ctx carrying the authenticated user ID
+func ContextWithUserID(ctx context.Context, userID uint) context.Context {
+	return context.WithValue(ctx, UserIDKey, userID)
+}
+
+// ContextWithTraceID returns a copy of ctx carrying the trace ID
+func ContextWithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, TraceIDKey, traceID)
+}
+
+// RequestIDFromContext extracts the request ID from a request context or gin context
+func RequestIDFromContext(ctx interface{ Value(any) any }) string {
+	if requestID, ok := ctx.Value(RequestIDKey).(string); ok {
+		return requestID
+	}
+	// Fallback for gin context keys
+	if requestID, ok := ctx.Value(string(RequestIDKey)).(string); ok {
+		return requestID
+	}
+	return ""
+}
+
+// UserIDFromContext extracts the user ID from a request context or gin context
+func UserIDFromContext(ctx interface{ Value(any) any }) uint {
+	if userID, ok := ctx.Value(UserIDKey).(uint); ok {
+		return userID
+	}
+	// Fallback for gin context keys
+	if userID, ok := ctx.Value(string(UserIDKey)).(uint); ok {
+		return userID
+	}
+	return 0
+}
+
+// TraceIDFromContext extracts the trace ID from a request context or gin context
+func TraceIDFromContext(ctx interface{ Value(any) any }) string {
+	if traceID, ok := ctx.Value(TraceIDKey).(string); ok {
+		return traceID
+	}
+	// Fallback for gin context keys
+	if traceID, ok := ctx.Value(string(TraceIDKey)).(string); ok {
+		return traceID
+	}
+	return ""
+}
+
+// FromContext creates a logger with request_id, trace_id, and user_id fields from context
 // This enables automatic correlation of logs with their originating requests
 func FromContext(ctx interface{ Value(any) any }) *zap.Logger {
-	fields := make([]zap.Field, 0, 2)
+	fields := make([]zap.Field, 0, 3)
 
-	if requestID, ok := ctx.Value(RequestIDKey).(string); ok && requestID != "" {
-		fields = append(fields, zap.String("request_id", requestID))
-	} else if requestID, ok := ctx.Value("request_id").(string); ok && requestID != "" {
-		// Fallback for gin context
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
 		fields = append(fields, zap.String("request_id", requestID))
 	}
 
-	if userID, ok := ctx.Value(UserIDKey).(uint); ok && userID != 0 {
-		fields = append(fields, zap.Uint("user_id", userID))
-	} else if userID, ok := ctx.Value("user_id").(uint); ok && userID != 0 {
-		// Fallback for gin context
+	if traceID := TraceIDFromContext(ctx); traceID != "" {
+		fields = append(fields, zap.String("trace_id", traceID))
+	}
+
+	if userID := UserIDFromContext(ctx); userID != 0 {
 		fields = append(fields, zap.Uint("user_id", userID))
 	}
 
@@ -0,0 +1,30 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// Module provides the application *zap.Logger to the fx graph
+var Module = fx.Options(
+	fx.Provide(NewLogger),
+)
+
+// NewLogger initializes the logger from environment variables (once) and
+// returns it for injection. Buffered entries are flushed on shutdown.
+func NewLogger(lc fx.Lifecycle) *zap.Logger {
+	if Log == nil {
+		InitFromEnv()
+	}
+
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			Sync()
+			return nil
+		},
+	})
+
+	return Log
+}
@@ -66,7 +66,34 @@ var (
 			Name: "temandifa_auth_attempts_total",
 			Help: "Total number of authentication attempts",
 		},
-		[]string{"result"}, // success, failed, token_revoked
+		[]string{"result"}, // success, failed, locked
+	)
+
+	// APIKeyRequests counts requests authenticated with partner API keys
+	APIKeyRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "temandifa_api_key_requests_total",
+			Help: "Total requests with an API key by key ID and response status",
+		},
+		[]string{"key", "status"}, // key=invalid for unknown or expired keys
+	)
+
+	// LoginLockouts counts lockouts started by too many failed logins
+	LoginLockouts = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "temandifa_login_lockouts_total",
+			Help: "Total login lockouts by what was locked",
+		},
+		[]string{"scope"}, // scope=email/ip
+	)
+
+	// RefreshTokenReuse counts rotated refresh tokens presented again, each
+	// of which revoked its session
+	RefreshTokenReuse = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "temandifa_refresh_token_reuse_total",
+			Help: "Total rotated refresh tokens presented again, revoking their session",
+		},
 	)
 
 	// CircuitBreakerRequests tracks requests per circuit breaker state
@@ -106,3 +133,429 @@ func RecordAIRequest(service string, durationSeconds float64, status string, cac
 func UpdateCircuitBreakerState(name string, state int) {
 	CircuitBreakerState.WithLabelValues(name).Set(float64(state))
 }
+
+var (
+	// RateLimitDecisions tracks allowed vs rejected requests per limiter
+	RateLimitDecisions = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "temandifa_rate_limit_decisions_total",
+			Help: "Total rate limiter decisions by limiter, key scope, and result",
+		},
+		[]string{"limiter", "scope", "result"}, // limiter=general/ai, scope=ip/user/api_key, result=allowed/rejected
+	)
+
+	// RateLimitUtilization tracks how much of the window quota a caller has used
+	RateLimitUtilization = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "temandifa_rate_limit_window_utilization_ratio",
+			Help:    "Requests in the current window divided by the limit (1.0 = at limit)",
+			Buckets: []float64{0.1, 0.25, 0.5, 0.75, 0.9, 1, 1.5, 2},
+		},
+		[]string{"limiter"},
+	)
+)
+
+// RecordRateLimit records a rate limiter decision and window utilization
+func RecordRateLimit(limiter, scope string, count int64, limit int, allowed bool) {
+	result := "allowed"
+	if !allowed {
+		result = "rejected"
+	}
+	RateLimitDecisions.WithLabelValues(limiter, scope, result).Inc()
+
+	if limit > 0 {
+		RateLimitUtilization.WithLabelValues(limiter).Observe(float64(count) / float64(limit))
+	}
+}
+
+var (
+	// RedisPoolStats exposes go-redis connection pool statistics
+	RedisPoolStats = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "temandifa_redis_pool_stats",
+			Help: "Redis connection pool statistics (hits/misses/timeouts are cumulative)",
+		},
+		[]string{"stat"}, // hits, misses, timeouts, total_conns, idle_conns, stale_conns
+	)
+)
+
+var (
+	// DBPoolStats exposes database/sql connection pool statistics
+	DBPoolStats = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "temandifa_db_pool_stats",
+			Help: "Database connection pool statistics (wait and *_closed stats are cumulative)",
+		},
+		[]string{"stat"}, // max_open, open, in_use, idle, wait_count, wait_duration_seconds, max_idle_closed, max_idle_time_closed, max_lifetime_closed
+	)
+)
+
+var (
+	// ComponentHealth reports the last background health check result per component
+	ComponentHealth = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "temandifa_component_healthy",
+			Help: "Whether a dependency passed its last health check (1 = healthy, 0 = not)",
+		},
+		[]string{"component"}, // postgres, redis, ai_service
+	)
+
+	// HealthRecoveryAttempts counts reconnect attempts made while a component is unhealthy
+	HealthRecoveryAttempts = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "temandifa_health_recovery_attempts_total",
+			Help: "Total recovery attempts for unhealthy components by outcome",
+		},
+		[]string{"component", "result"}, // result: recovered, failed
+	)
+)
+
+var (
+	// GRPCClientDuration tracks latency of outgoing gRPC calls per attempt
+	GRPCClientDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "temandifa_grpc_client_duration_seconds",
+			Help:    "Duration of outgoing gRPC calls in seconds (per attempt)",
+			Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+		},
+		[]string{"method", "code"},
+	)
+
+	// GRPCClientMessageBytes tracks payload sizes of outgoing gRPC calls
+	GRPCClientMessageBytes = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "temandifa_grpc_client_message_bytes",
+			Help:    "Size of gRPC messages sent and received in bytes",
+			Buckets: prometheus.ExponentialBuckets(256, 4, 10), // 256B .. 64MB
+		},
+		[]string{"method", "direction"}, // sent, received
+	)
+
+	// GRPCClientRetries tracks retried gRPC call attempts
+	GRPCClientRetries = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "temandifa_grpc_client_retries_total",
+			Help: "Total number of retried outgoing gRPC call attempts",
+		},
+		[]string{"method"},
+	)
+)
+
+// RecordGRPCClientCall records latency, status code, and payload sizes of a gRPC call
+func RecordGRPCClientCall(method, code string, durationSeconds float64, sentBytes, receivedBytes int) {
+	GRPCClientDuration.WithLabelValues(method, code).Observe(durationSeconds)
+	GRPCClientMessageBytes.WithLabelValues(method, "sent").Observe(float64(sentBytes))
+	if receivedBytes > 0 {
+		GRPCClientMessageBytes.WithLabelValues(method, "received").Observe(float64(receivedBytes))
+	}
+}
+
+var (
+	// SLIEvents counts good/bad events per endpoint for availability and latency SLIs.
+	// Error budget burn = (1 - good/total) / (1 - objective).
+	SLIEvents = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "temandifa_sli_events_total",
+			Help: "Total SLI events by endpoint, indicator, and result",
+		},
+		[]string{"endpoint", "sli", "result"}, // sli=availability/latency, result=good/bad
+	)
+
+	// SLOObjective exposes the configured target ratio for each endpoint SLI
+	SLOObjective = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "temandifa_slo_objective_ratio",
+			Help: "Configured SLO target ratio of good events (e.g. 0.995)",
+		},
+		[]string{"endpoint", "sli"},
+	)
+
+	// SLOLatencyThreshold exposes the latency threshold used by each endpoint's latency SLI
+	SLOLatencyThreshold = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "temandifa_slo_latency_threshold_seconds",
+			Help: "Latency threshold below which a request counts as good for the latency SLI",
+		},
+		[]string{"endpoint"},
+	)
+)
+
+// RecordSLI records availability and latency SLI events for a finished request.
+// Server errors (5xx) are bad availability events; client errors are not the
+// service's fault and count as good. Latency is only judged for non-5xx requests.
+func RecordSLI(endpoint string, status int, durationSeconds, thresholdSeconds float64) {
+	if status >= 500 {
+		SLIEvents.WithLabelValues(endpoint, "availability", "bad").Inc()
+		return
+	}
+	SLIEvents.WithLabelValues(endpoint, "availability", "good").Inc()
+
+	if durationSeconds <= thresholdSeconds {
+		SLIEvents.WithLabelValues(endpoint, "latency", "good").Inc()
+	} else {
+		SLIEvents.WithLabelValues(endpoint, "latency", "bad").Inc()
+	}
+}
+
+var (
+	// PanicsRecovered tracks panics caught by the recovery middleware
+	PanicsRecovered = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "temandifa_panics_recovered_total",
+			Help: "Total number of panics recovered in HTTP handlers",
+		},
+		[]string{"route"},
+	)
+)
+
+var (
+	// EventsEmitted counts analytics events offered to the event pipeline
+	EventsEmitted = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "temandifa_events_emitted_total",
+			Help: "Total analytics events emitted by type and result",
+		},
+		[]string{"type", "result"}, // result=queued/dropped
+	)
+
+	// EventsPublished counts analytics events delivered (or lost) per sink
+	EventsPublished = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "temandifa_events_published_total",
+			Help: "Total analytics events published by sink and result",
+		},
+		[]string{"sink", "result"}, // result=success/failure
+	)
+
+	// EventQueueDepth tracks buffered events awaiting publication
+	EventQueueDepth = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "temandifa_events_queue_depth",
+			Help: "Number of analytics events buffered for publication",
+		},
+	)
+)
+
+var (
+	// DBReplicaHealthy reports whether each read replica currently receives reads (1) or not (0)
+	DBReplicaHealthy = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "temandifa_db_replica_healthy",
+			Help: "Read replica health (1 = receiving reads, 0 = failed back to other replicas/primary)",
+		},
+		[]string{"replica"},
+	)
+)
+
+var (
+	// OutboxEvents counts outbox delivery outcomes by event type
+	OutboxEvents = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "temandifa_outbox_events_total",
+			Help: "Total outbox delivery outcomes by event type",
+		},
+		[]string{"event_type", "result"}, // result=dispatched/retry/failed
+	)
+
+	// OutboxPending tracks events waiting to be dispatched
+	OutboxPending = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "temandifa_outbox_pending_events",
+			Help: "Number of outbox events waiting to be dispatched",
+		},
+	)
+)
+
+var (
+	// UsersPurged counts users hard-deleted after the deletion grace period
+	UsersPurged = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "temandifa_users_purged_total",
+			Help: "Total number of soft-deleted users hard-deleted by the purge job",
+		},
+	)
+
+	// PurgedRows counts user-owned rows removed along with purged users
+	PurgedRows = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "temandifa_purged_rows_total",
+			Help: "Total rows deleted alongside purged users by table",
+		},
+		[]string{"table"},
+	)
+)
+
+var (
+	// DBNotifications counts change notifications received via LISTEN
+	DBNotifications = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "temandifa_db_notifications_total",
+			Help: "Total database change notifications received by entity and operation",
+		},
+		[]string{"entity", "op"},
+	)
+
+	// DBListenerConnected reports whether the LISTEN connection is up (1) or not (0)
+	DBListenerConnected = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "temandifa_db_listener_connected",
+			Help: "Whether the database change listener connection is established",
+		},
+	)
+)
+
+var (
+	// BackupRuns counts database backup runs by method and result
+	BackupRuns = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "temandifa_backup_runs_total",
+			Help: "Total database backup runs by method and result",
+		},
+		[]string{"method", "result"},
+	)
+
+	// BackupDuration tracks how long backups take
+	BackupDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "temandifa_backup_duration_seconds",
+			Help:    "Database backup duration in seconds",
+			Buckets: []float64{10, 30, 60, 120, 300, 600, 1200, 1800, 3600},
+		},
+		[]string{"method"},
+	)
+
+	// BackupLastSuccess is the Unix time of the last successful backup (alert on its age)
+	BackupLastSuccess = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "temandifa_backup_last_success_timestamp_seconds",
+			Help: "Unix timestamp of the last successful database backup",
+		},
+	)
+
+	// BackupSizeBytes is the size of the last successful pg_dump archive
+	BackupSizeBytes = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "temandifa_backup_size_bytes",
+			Help: "Size of the last successful database backup archive in bytes",
+		},
+	)
+)
+
+var (
+	// RemoteConfigFetches counts reads of the remote config store by result
+	RemoteConfigFetches = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "temandifa_remote_config_fetches_total",
+			Help: "Total reads of the remote config store by provider and result",
+		},
+		[]string{"provider", "result"},
+	)
+
+	// RemoteConfigChanges counts applied remote config changes
+	RemoteConfigChanges = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "temandifa_remote_config_changes_total",
+			Help: "Total remote config changes applied by provider",
+		},
+		[]string{"provider"},
+	)
+)
+
+var (
+	// TLSCertificateExpiry exposes when the served certificate expires
+	TLSCertificateExpiry = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "temandifa_tls_certificate_expiry_timestamp_seconds",
+			Help: "Unix time at which the TLS certificate loaded from TLS_CERT_FILE expires",
+		},
+	)
+
+	// TLSCertificateReloads counts certificate reloads by result
+	TLSCertificateReloads = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "temandifa_tls_certificate_reloads_total",
+			Help: "Total TLS certificate reloads by result",
+		},
+		[]string{"result"},
+	)
+)
+
+var (
+	// TokenBlacklistEntries counts revoked access tokens held in Redis
+	TokenBlacklistEntries = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "temandifa_token_blacklist_entries",
+			Help: "Number of revoked access tokens in the Redis blacklist",
+		},
+	)
+
+	// TokenBlacklistMemory is the Redis memory used by the blacklist
+	TokenBlacklistMemory = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "temandifa_token_blacklist_memory_bytes",
+			Help: "Redis memory used by token blacklist entries (MEMORY USAGE sum)",
+		},
+	)
+)
+
+var (
+	// PushNotifications counts push notification deliveries by type
+	PushNotifications = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "temandifa_push_notifications_total",
+			Help: "Total push notification deliveries by notification type and result",
+		},
+		[]string{"type", "result"}, // result=sent/invalid_token/failed
+	)
+
+	// ContactMessages counts SMS and email deliveries to people outside the app
+	ContactMessages = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "temandifa_contact_messages_total",
+			Help: "Total SMS and email deliveries by message type, channel and result",
+		},
+		[]string{"type", "channel", "result"}, // channel=sms/email, result=sent/rejected/failed
+	)
+)
+
+var (
+	// NarrationSessions tracks open scene narration connections
+	NarrationSessions = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "temandifa_narration_sessions",
+			Help: "Number of open scene narration connections (WebSocket and gRPC)",
+		},
+	)
+
+	// NarrationFrames counts received narration frames by outcome
+	NarrationFrames = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "temandifa_narration_frames_total",
+			Help: "Total scene narration frames by result",
+		},
+		[]string{"result"}, // result=narrated/throttled/rejected
+	)
+)
+
+var (
+	// GRPCServerDuration tracks latency of calls to the mobile gRPC API
+	GRPCServerDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "temandifa_grpc_server_duration_seconds",
+			Help:    "Duration of incoming gRPC calls in seconds (streams: their lifetime)",
+			Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+		},
+		[]string{"method", "code"},
+	)
+)
+
+var (
+	// MalwareScans counts scanned uploads by outcome
+	MalwareScans = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "temandifa_malware_scans_total",
+			Help: "Total uploaded files scanned for malware by mode and result",
+		},
+		[]string{"mode", "result"}, // result=clean/infected/error/skipped
+	)
+)
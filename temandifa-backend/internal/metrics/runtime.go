@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// CachePendingOperations tracks async cache writes that have not finished yet.
+	// A steadily growing value points at goroutines piling up behind a slow Redis.
+	CachePendingOperations = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "temandifa_cache_pending_async_operations",
+			Help: "Number of in-flight asynchronous cache operations",
+		},
+	)
+)
+
+// RegisterRuntimeCollectors replaces the default Go collector with one that also
+// exports runtime/metrics for GC pauses, scheduler latency, and heap classes.
+// Goroutine count, memstats, and file descriptors (process collector) are kept.
+func RegisterRuntimeCollectors() {
+	prometheus.Unregister(collectors.NewGoCollector())
+	prometheus.MustRegister(collectors.NewGoCollector(
+		collectors.WithGoCollectorRuntimeMetrics(
+			collectors.MetricsGC,
+			collectors.MetricsMemory,
+			collectors.MetricsScheduler,
+		),
+	))
+
+	// The process collector (open/max fds, RSS, CPU) is registered by default;
+	// make sure it is present even if the default registry was altered.
+	if err := prometheus.Register(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{})); err != nil {
+		if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// Headers the app identifies its installation with
+const (
+	AppPlatformHeader = "X-App-Platform"
+	AppVersionHeader  = "X-App-Version"
+	DeviceIDHeader    = "X-Device-ID" // Binds refresh tokens to the device
+)
+
+// AppVersionGate answers 426 UPGRADE_REQUIRED to app versions below their
+// platform's minimum, with the store link so the app can prompt for the
+// update. Requests without the headers (web, scripts) pass, as do the
+// version manifest itself, health, the status page, announcements,
+// translation bundles and admin routes.
+func AppVersionGate(versions services.AppVersionService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		platform, version := c.GetHeader(AppPlatformHeader), c.GetHeader(AppVersionHeader)
+		if platform == "" || version == "" {
+			c.Next()
+			return
+		}
+
+		path := c.Request.URL.Path
+		if strings.HasSuffix(path, "/app/version") || strings.HasSuffix(path, "/health") || strings.HasSuffix(path, "/status") || strings.HasSuffix(path, "/announcements") || strings.Contains(path, "/i18n/") || strings.Contains(path, "/admin/") || strings.HasSuffix(path, "/admin") {
+			c.Next()
+			return
+		}
+
+		status := versions.Status(strings.ToLower(platform), version)
+		if status == nil || !status.UpdateRequired {
+			c.Next()
+			return
+		}
+		response.Error(c, http.StatusUpgradeRequired, response.ErrCodeUpgradeRequired, "This version of the app is no longer supported. Please update.", gin.H{
+			"min_version":    status.MinVersion,
+			"latest_version": status.LatestVersion,
+			"store_url":      status.StoreURL,
+		})
+		c.Abort()
+	}
+}
@@ -1,138 +1,142 @@
 package middleware
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 
+	apperrors "temandifa-backend/internal/errors"
 	"temandifa-backend/internal/logger"
 	"temandifa-backend/internal/models"
 	"temandifa-backend/internal/repositories"
+	"temandifa-backend/internal/response"
 	"temandifa-backend/internal/services"
 )
 
 // Auth validates JWT token and attaches user to context.
-// Uses dependency injection for userRepo, userCache, and tokenBlacklist.
-func Auth(jwtSecret string, userRepo repositories.UserRepository, userCache services.UserCacheService, tokenBlacklist *services.TokenBlacklist) gin.HandlerFunc {
-	secret := []byte(jwtSecret)
+// Tokens are verified by the token service so JWT_SECRET and JWT_SIGNING_KEYS rotations apply here too.
+func Auth(tokenService services.TokenService, userRepo repositories.UserRepository, userCache services.UserCacheService, tokenBlacklist *services.TokenBlacklist) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			logger.Debug("Missing authorization header", zap.String("path", c.Request.URL.Path))
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"error": gin.H{
-					"code":    "UNAUTHORIZED",
-					"message": "Authorization header required",
-				},
-			})
+			logger.Ctx(c).Debug("Missing authorization header", zap.String("path", c.Request.URL.Path))
+			response.Error(c, http.StatusUnauthorized, apperrors.ErrCodeUnauthorized, "Authorization header required")
+			c.Abort()
 			return
 		}
 
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 
 		if tokenBlacklist != nil && tokenBlacklist.IsBlacklisted(c.Request.Context(), tokenString) {
-			logger.Debug("Token is blacklisted", zap.String("path", c.Request.URL.Path))
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"error": gin.H{
-					"code":    "TOKEN_REVOKED",
-					"message": "Token has been revoked",
-				},
-			})
+			logger.Ctx(c).Debug("Token is blacklisted", zap.String("path", c.Request.URL.Path))
+			response.Error(c, http.StatusUnauthorized, apperrors.ErrCodeTokenRevoked, "Token has been revoked")
+			c.Abort()
 			return
 		}
 
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return secret, nil
-		})
-
-		if err != nil || !token.Valid {
-			logger.Debug("Invalid token", zap.Error(err))
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"error": gin.H{
-					"code":    "TOKEN_INVALID",
-					"message": "Invalid token",
-				},
-			})
+		claims, err := tokenService.ValidateAccessToken(tokenString)
+		if errors.Is(err, services.ErrTokenEpochRevoked) {
+			logger.Ctx(c).Debug("Token predates the last forced logout", zap.String("path", c.Request.URL.Path))
+			response.Error(c, http.StatusUnauthorized, apperrors.ErrCodeTokenRevoked, "Token has been revoked")
+			c.Abort()
+			return
+		}
+		if err != nil {
+			logger.Ctx(c).Debug("Invalid token", zap.Error(err))
+			response.Error(c, http.StatusUnauthorized, apperrors.ErrCodeTokenInvalid, "Invalid token")
+			c.Abort()
 			return
 		}
 
-		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			userId := uint(claims["sub"].(float64))
-
-			// Try to get user from cache first (using injected UserCacheService)
-			if userCache != nil {
-				cachedUser, err := userCache.GetCachedUser(c.Request.Context(), userId)
-				if err == nil && cachedUser != nil {
-					// Cache hit - create minimal user object
-					user := models.User{
-						Email:    cachedUser.Email,
-						FullName: cachedUser.FullName,
-						Role:     cachedUser.Role,
-					}
-					user.ID = cachedUser.ID
-					c.Set("user", user)
-					logger.Debug("User authenticated (cached)",
-						zap.Uint("user_id", user.ID),
-						zap.String("email", user.Email),
-					)
-					c.Next()
-					return
-				}
-
-				// Log cache errors (except not found)
-				if err != nil && err != redis.Nil {
-					logger.Debug("User cache error", zap.Error(err))
-				}
-			}
+		user := loadUser(c, claims.UserID, userRepo, userCache)
+		if user == nil {
+			logger.Ctx(c).Debug("User not found from token", zap.Uint("user_id", claims.UserID))
+			response.Error(c, http.StatusUnauthorized, apperrors.ErrCodeNotFound, "User not found")
+			c.Abort()
+			return
+		}
 
-			// Cache miss or no cache - fetch from database using repository
-			user, err := userRepo.FindByID(userId)
-			if err != nil || user == nil {
-				logger.Debug("User not found from token", zap.Uint("user_id", userId))
-				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-					"success": false,
-					"error": gin.H{
-						"code":    "NOT_FOUND",
-						"message": "User not found",
-					},
-				})
-				return
-			}
+		// Attach user to context
+		setAuthenticatedUser(c, *user, claims)
+		logger.Ctx(c).Debug("User authenticated",
+			zap.String("email", user.Email),
+		)
+		c.Next()
+	}
+}
 
-			// Cache the user for future requests (if cache available)
-			if userCache != nil {
-				if cacheErr := userCache.SetCachedUser(c.Request.Context(), user); cacheErr != nil {
-					logger.Debug("Failed to cache user", zap.Error(cacheErr))
-				}
+// loadUser returns the authenticated user from the cache, else the database
+// (caching it), nil when it doesn't exist
+func loadUser(c *gin.Context, userID uint, userRepo repositories.UserRepository, userCache services.UserCacheService) *models.User {
+	// Try to get user from cache first (using injected UserCacheService)
+	if userCache != nil {
+		cachedUser, err := userCache.GetCachedUser(c.Request.Context(), userID)
+		if err == nil && cachedUser != nil {
+			// Cache hit - create minimal user object
+			user := models.User{
+				Email:             cachedUser.Email,
+				FullName:          cachedUser.FullName,
+				ProfilePicture:    cachedUser.ProfilePicture,
+				PreferredLanguage: cachedUser.PreferredLanguage,
+				Role:              cachedUser.Role,
+				Plan:              cachedUser.Plan,
+				EmailVerifiedAt:   cachedUser.EmailVerifiedAt,
 			}
+			user.ID = cachedUser.ID
+			return &user
+		}
+
+		// Log cache errors (except not found)
+		if err != nil && err != redis.Nil {
+			logger.Ctx(c).Debug("User cache error", zap.Error(err))
+		}
+	}
+
+	// Cache miss or no cache - fetch from database using repository
+	user, err := userRepo.FindByID(c.Request.Context(), userID)
+	if err != nil || user == nil {
+		return nil
+	}
+
+	// Cache the user for future requests (if cache available)
+	if userCache != nil {
+		if cacheErr := userCache.SetCachedUser(c.Request.Context(), user); cacheErr != nil {
+			logger.Ctx(c).Debug("Failed to cache user", zap.Error(cacheErr))
+		}
+	}
+	return user
+}
+
+// sessionIDKey holds the session (refresh token ID) of the request's access token
+const sessionIDKey = "session_id"
+
+// SessionID returns the session the request's access token was issued with,
+// 0 when unknown
+func SessionID(c *gin.Context) uint {
+	return c.GetUint(sessionIDKey)
+}
+
+// setAuthenticatedUser attaches the user to the gin context and the user ID to
+// the request context so downstream logs and rate limiters can correlate it.
+// The token's role version counts only while the user still has that role.
+func setAuthenticatedUser(c *gin.Context, user models.User, claims *services.AccessClaims) {
+	c.Set("user", user)
+	c.Set(sessionIDKey, claims.SessionID)
+	if claims.Role == user.Role {
+		c.Set(roleVersionKey, claims.RoleVersion)
+	}
+	c.Set(string(logger.UserIDKey), user.ID)
+	ctx := logger.ContextWithUserID(c.Request.Context(), user.ID)
+	c.Request = c.Request.WithContext(services.ContextWithPlan(ctx, user.Plan))
 
-			// Attach user to context
-			c.Set("user", *user)
-			logger.Debug("User authenticated",
-				zap.Uint("user_id", user.ID),
-				zap.String("email", user.Email),
-			)
-			c.Next()
-		} else {
-			logger.Debug("Invalid token claims")
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"error": gin.H{
-					"code":    "TOKEN_INVALID",
-					"message": "Invalid token claims",
-				},
-			})
+	// Enrich the request-scoped logger so every later log line carries user_id
+	if log, ok := c.Get(string(logger.LoggerKey)); ok {
+		if requestLog, ok := log.(*zap.Logger); ok {
+			setRequestLogger(c, requestLog.With(zap.Uint("user_id", user.ID)))
 		}
 	}
 }
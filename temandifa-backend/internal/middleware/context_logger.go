@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/logger"
+)
+
+// ContextLogger stores a request-scoped logger with request_id (and trace_id
+// when tracing is enabled) pre-attached, so handlers and services can log via
+// logger.Ctx(ctx). Auth adds user_id once the caller is known.
+// Must run after RequestID and TraceContext.
+func ContextLogger(log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fields := []zap.Field{zap.String("request_id", logger.RequestIDFromContext(c))}
+		if traceID := logger.TraceIDFromContext(c); traceID != "" {
+			fields = append(fields, zap.String("trace_id", traceID))
+		}
+
+		setRequestLogger(c, log.With(fields...))
+		c.Next()
+	}
+}
+
+// setRequestLogger stores the logger on both the gin and request contexts
+func setRequestLogger(c *gin.Context, log *zap.Logger) {
+	c.Set(string(logger.LoggerKey), log)
+	c.Request = c.Request.WithContext(logger.WithLogger(c.Request.Context(), log))
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// RequireFeature hides a route behind a feature flag: callers the flag is off
+// for get 404, as if the route didn't exist. Place it after Auth so user and
+// organization overrides and percentage rollouts apply.
+func RequireFeature(flags services.FeatureFlagService, name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var subject services.FlagSubject
+		if user, ok := c.Get("user"); ok {
+			if u, ok := user.(models.User); ok {
+				subject = services.FlagSubject{UserID: u.ID, Email: u.Email}
+			}
+		}
+
+		if !flags.IsEnabled(c.Request.Context(), name, subject) {
+			response.NotFound(c, "Resource")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
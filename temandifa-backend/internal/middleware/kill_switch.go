@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// KillSwitch answers 503 FEATURE_DISABLED while an administrator has
+// switched operation off. Place it before the SLO, usage and timeout
+// middleware so disabled calls don't count against them.
+func KillSwitch(switches services.KillSwitchService, operation string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sw := switches.Disabled(operation)
+		if sw == nil {
+			c.Next()
+			return
+		}
+
+		message := "This feature is temporarily disabled"
+		if sw.Reason != "" {
+			message = sw.Reason
+		}
+		response.Error(c, http.StatusServiceUnavailable, response.ErrCodeFeatureDisabled, message, gin.H{"operation": operation})
+		c.Abort()
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/response"
+)
+
+// maintenanceRetryAfter is the Retry-After hint (seconds) sent while in maintenance
+const maintenanceRetryAfter = "120"
+
+// Maintenance answers 503 while maintenance mode is on. The health check and
+// admin routes stay reachable so operators can verify and finish the work,
+// the status page so it can report the maintenance, and announcements, the
+// app version manifest and translation bundles so the app can show the
+// maintenance notice or an update prompt.
+func Maintenance(state func() (enabled bool, message string)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		enabled, message := state()
+		if !enabled {
+			c.Next()
+			return
+		}
+
+		path := c.Request.URL.Path
+		if strings.HasSuffix(path, "/health") || strings.HasSuffix(path, "/status") || strings.HasSuffix(path, "/announcements") || strings.HasSuffix(path, "/app/version") || strings.Contains(path, "/i18n/") || strings.Contains(path, "/admin/") || strings.HasSuffix(path, "/admin") {
+			c.Next()
+			return
+		}
+
+		c.Header("Retry-After", maintenanceRetryAfter)
+		response.Error(c, http.StatusServiceUnavailable, apperrors.ErrCodeServiceUnavailable, message)
+		c.Abort()
+	}
+}
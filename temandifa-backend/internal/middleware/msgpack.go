@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"temandifa-backend/internal/negotiate"
+	"temandifa-backend/internal/response"
+)
+
+// MsgPackBody converts MessagePack request bodies (Content-Type
+// application/msgpack or application/x-msgpack) to JSON, so every handler
+// binding JSON accepts both. Responses follow the Accept header on their own.
+func MsgPackBody() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !negotiate.RequestIsMsgPack(c.Request) {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				response.Error(c, http.StatusRequestEntityTooLarge, response.ErrCodeFileTooLarge, "Request body too large")
+			} else {
+				response.BadRequest(c, "Failed to read request body")
+			}
+			c.Abort()
+			return
+		}
+		converted, err := negotiate.MsgPackToJSON(body)
+		if err != nil {
+			response.BadRequest(c, "Invalid MessagePack body")
+			c.Abort()
+			return
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(converted))
+		c.Request.ContentLength = int64(len(converted))
+		c.Request.Header.Set("Content-Type", negotiate.MIMEJSON)
+		c.Next()
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
+)
+
+// Quota counts each AI request against the daily quota of the user's plan
+// and answers 429 QUOTA_EXCEEDED once it is used up. Place it after the
+// kill switch so disabled calls don't count. Redis errors let the request
+// through, like the rate limiters.
+func Quota(plans services.PlanService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := c.Get("user")
+		if !ok {
+			c.Next()
+			return
+		}
+		current := user.(models.User)
+
+		allowance, allowed, err := plans.ConsumeQuota(c.Request.Context(), &current)
+		if err != nil {
+			logger.Ctx(c).Warn("Failed to count AI quota", zap.Error(err))
+			c.Next()
+			return
+		}
+
+		if allowance.Remaining != nil {
+			c.Header("X-Quota-Limit", strconv.Itoa(allowance.DailyAIQuota))
+			c.Header("X-Quota-Remaining", strconv.FormatInt(*allowance.Remaining, 10))
+			c.Header("X-Quota-Reset", strconv.FormatInt(allowance.ResetsAt.Unix(), 10))
+		}
+		if !allowed {
+			logger.Ctx(c).Info("Daily AI quota exceeded",
+				zap.String("plan", allowance.Plan),
+				zap.Int("quota", allowance.DailyAIQuota),
+			)
+			c.Header("Retry-After", fmt.Sprintf("%d", int(time.Until(allowance.ResetsAt).Seconds())+1))
+			response.Error(c, http.StatusTooManyRequests, response.ErrCodeQuotaExceeded, "Daily AI quota used up", allowance)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -2,19 +2,20 @@ package middleware
 
 import (
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 
+	"temandifa-backend/internal/logger"
 	"temandifa-backend/internal/models"
 	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
 )
 
-// Role constants
-const (
-	RoleUser  = "user"
-	RoleAdmin = "admin"
-)
+// roleVersionKey holds the role version of the request's access token
+const roleVersionKey = "role_version"
 
-// RequireRole creates a middleware that requires a specific role
-func RequireRole(role string) gin.HandlerFunc {
+// RequirePermission creates a middleware that requires the user's role to
+// grant permission. Must run after Auth.
+func RequirePermission(permissions services.PermissionService, permission string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get user from context (set by Auth middleware)
 		userInterface, exists := c.Get("user")
@@ -31,7 +32,15 @@ func RequireRole(role string) gin.HandlerFunc {
 			return
 		}
 
-		if user.Role != role {
+		allowed, err := permissions.HasPermission(c.Request.Context(), user.Role, c.GetUint(roleVersionKey), permission)
+		if err != nil {
+			logger.Ctx(c).Error("Failed to check permission", zap.String("permission", permission), zap.Error(err))
+			response.InternalError(c, "Failed to check permissions")
+			c.Abort()
+			return
+		}
+		if !allowed {
+			logger.Ctx(c).Debug("Permission denied", zap.String("role", user.Role), zap.String("permission", permission))
 			response.Forbidden(c, "Insufficient permissions")
 			c.Abort()
 			return
@@ -40,8 +49,3 @@ func RequireRole(role string) gin.HandlerFunc {
 		c.Next()
 	}
 }
-
-// AdminOnly is a convenience middleware for admin-only routes
-func AdminOnly() gin.HandlerFunc {
-	return RequireRole(RoleAdmin)
-}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"temandifa-backend/internal/dto"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/services"
+)
+
+// maxCaptureBodyBytes caps how much of each request/response body is captured
+const maxCaptureBodyBytes = 64 * 1024
+
+// cappedBuffer keeps the first limit bytes written to it and drops the rest
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - b.buf.Len(); remaining > 0 {
+		if len(p) > remaining {
+			b.buf.Write(p[:remaining])
+			b.truncated = true
+		} else {
+			b.buf.Write(p)
+		}
+	} else if len(p) > 0 {
+		b.truncated = true
+	}
+	return len(p), nil
+}
+
+// captureWriter tees the response body into a capped buffer
+type captureWriter struct {
+	gin.ResponseWriter
+	body *cappedBuffer
+}
+
+func (w *captureWriter) Write(b []byte) (int, error) {
+	_, _ = w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+	_, _ = w.body.Write([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+// captureBody tees a request body into a capped buffer as handlers read it
+type captureBody struct {
+	io.Reader
+	io.Closer
+}
+
+// RequestCapture records redacted request/response pairs matching the admin
+// capture rule (target user ID or request ID pattern). Bodies are only
+// buffered while a rule is active, so normal traffic pays a single cached check.
+func RequestCapture(captureService services.RequestCaptureService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !captureService.Active(c.Request.Context()) {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+
+		reqBody := &cappedBuffer{limit: maxCaptureBodyBytes}
+		if c.Request.Body != nil {
+			c.Request.Body = captureBody{
+				Reader: io.TeeReader(c.Request.Body, reqBody),
+				Closer: c.Request.Body,
+			}
+		}
+
+		respBody := &cappedBuffer{limit: maxCaptureBodyBytes}
+		c.Writer = &captureWriter{ResponseWriter: c.Writer, body: respBody}
+
+		c.Next()
+
+		// The user ID is only known once auth has run, so match afterwards
+		userID := c.GetUint(string(logger.UserIDKey))
+		requestID := logger.RequestIDFromContext(c)
+		if !captureService.ShouldCapture(c.Request.Context(), userID, requestID) {
+			return
+		}
+
+		exchange := dto.CapturedExchange{
+			RequestID:       requestID,
+			UserID:          userID,
+			Method:          c.Request.Method,
+			Path:            c.Request.URL.Path,
+			Query:           services.RedactQuery(c.Request.URL.RawQuery),
+			RequestHeaders:  services.RedactHeaders(c.Request.Header),
+			RequestBody:     services.RedactBody(c.ContentType(), reqBody.buf.Bytes(), reqBody.truncated),
+			Status:          c.Writer.Status(),
+			ResponseHeaders: services.RedactHeaders(c.Writer.Header()),
+			ResponseBody:    services.RedactBody(c.Writer.Header().Get("Content-Type"), respBody.buf.Bytes(), respBody.truncated),
+			LatencyMs:       time.Since(start).Milliseconds(),
+			CapturedAt:      start.UTC(),
+		}
+
+		go captureService.Store(context.WithoutCancel(c.Request.Context()), exchange)
+	}
+}
@@ -11,27 +11,32 @@ import (
 	"temandifa-backend/internal/logger"
 )
 
+// maxRequestIDLength caps client-supplied request IDs to keep logs and headers sane
+const maxRequestIDLength = 128
+
 // RequestID adds a unique request ID to each request for tracing
 // The ID is added to the context and response headers
-func RequestID() gin.HandlerFunc {
+func RequestID(log *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Check if client provided a request ID
 		requestID := c.GetHeader("X-Request-ID")
 
-		// Generate new ID if not provided
-		if requestID == "" {
+		// Generate new ID if not provided (or if the client sent something unreasonable)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
 			requestID = generateRequestID()
 		}
 
-		// Set request ID in context
-		c.Set("request_id", requestID)
+		// Set request ID in both the gin context and the request context so it
+		// reaches services, gRPC metadata, and error responses alike
+		c.Set(string(logger.RequestIDKey), requestID)
+		c.Request = c.Request.WithContext(logger.ContextWithRequestID(c.Request.Context(), requestID))
 
 		// Add to response headers
 		c.Header("X-Request-ID", requestID)
 
-		// Add to logger context for this request
-		logger.Debug("Request started",
-			zap.String("request_id", requestID),
+		// Runs before ContextLogger, so the request ID is added here
+		log := log.With(zap.String("request_id", requestID))
+		log.Debug("Request started",
 			zap.String("method", c.Request.Method),
 			zap.String("path", c.Request.URL.Path),
 			zap.String("client_ip", c.ClientIP()),
@@ -40,8 +45,7 @@ func RequestID() gin.HandlerFunc {
 		c.Next()
 
 		// Log request completion
-		logger.Debug("Request completed",
-			zap.String("request_id", requestID),
+		log.Debug("Request completed",
 			zap.Int("status", c.Writer.Status()),
 		)
 	}
@@ -29,12 +29,6 @@ func RequestLogger() gin.HandlerFunc {
 		path := c.Request.URL.Path
 		raw := c.Request.URL.RawQuery
 
-		// Get request ID
-		requestID := c.GetString("requestId")
-		if requestID == "" {
-			requestID = "unknown"
-		}
-
 		// Read request body for logging (only for debug)
 		var requestBody []byte
 		if c.Request.Body != nil {
@@ -55,9 +49,9 @@ func RequestLogger() gin.HandlerFunc {
 		// Calculate latency
 		latency := time.Since(start)
 
-		// Build log fields
+		// Build log fields; the request-scoped logger carries the request,
+		// trace and user IDs
 		fields := []zap.Field{
-			zap.String("request_id", requestID),
 			zap.String("method", c.Request.Method),
 			zap.String("path", path),
 			zap.Int("status", c.Writer.Status()),
@@ -71,25 +65,21 @@ func RequestLogger() gin.HandlerFunc {
 			fields = append(fields, zap.String("query", raw))
 		}
 
-		// Add user ID if authenticated
-		if userID, exists := c.Get("userId"); exists {
-			fields = append(fields, zap.Any("user_id", userID))
-		}
-
 		// Add error if present
 		if len(c.Errors) > 0 {
 			fields = append(fields, zap.String("errors", c.Errors.String()))
 		}
 
 		// Log based on status code
+		log := logger.Ctx(c)
 		status := c.Writer.Status()
 		switch {
 		case status >= 500:
-			logger.Error("Server error", fields...)
+			log.Error("Server error", fields...)
 		case status >= 400:
-			logger.Warn("Client error", fields...)
+			log.Warn("Client error", fields...)
 		default:
-			logger.Info("Request completed", fields...)
+			log.Info("Request completed", fields...)
 		}
 	}
 }
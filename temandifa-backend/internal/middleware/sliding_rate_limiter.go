@@ -9,52 +9,47 @@ import (
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 
+	apperrors "temandifa-backend/internal/errors"
 	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/metrics"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/response"
+	"temandifa-backend/internal/services"
 )
 
+// LimitFunc returns the current request limit and window; it is called on
+// every request so limits can change at runtime
+type LimitFunc func() (limit int, window time.Duration)
+
+// StaticLimit returns a LimitFunc with fixed values
+func StaticLimit(limit int, window time.Duration) LimitFunc {
+	return func() (int, time.Duration) { return limit, window }
+}
+
 // SlidingWindowRateLimiter implements a sliding window rate limiter using Redis sorted sets.
 // This provides more accurate rate limiting compared to fixed window by tracking
 // exact request timestamps within the window period.
-// limit: max requests allowed within the window
-// window: time window duration
-func SlidingWindowRateLimiter(rdb *redis.Client, limit int, window time.Duration) gin.HandlerFunc {
+// name: limiter name used in metrics (e.g. "general")
+// limits: max requests allowed within the window, and the window duration
+func SlidingWindowRateLimiter(rdb *redis.Client, name string, limits LimitFunc) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if rdb == nil {
 			c.Next() // Redis not connected, skip rate limiting
 			return
 		}
+		limit, window := limits()
 
 		ip := c.ClientIP()
-		key := fmt.Sprintf("sliding_rate:%s", ip)
+		key := services.IPRateLimitKey(ip)
 		now := time.Now()
-		windowStart := now.Add(-window)
-
-		// Use a pipeline for atomic operations
-		pipe := rdb.Pipeline()
-
-		// Remove old entries outside the window
-		pipe.ZRemRangeByScore(c, key, "0", fmt.Sprintf("%d", windowStart.UnixNano()))
-
-		// Add current request with current timestamp as score
-		pipe.ZAdd(c, key, redis.Z{
-			Score:  float64(now.UnixNano()),
-			Member: fmt.Sprintf("%d", now.UnixNano()),
-		})
 
-		// Count requests in current window
-		countCmd := pipe.ZCard(c, key)
-
-		// Set expiry on the key to auto-cleanup
-		pipe.Expire(c, key, window+time.Second)
-
-		_, err := pipe.Exec(c)
+		count, err := services.CountSlidingWindow(c, rdb, key, window, now)
 		if err != nil {
-			logger.Warn("Sliding rate limit Redis error", zap.Error(err), zap.String("ip", ip))
+			logger.Ctx(c).Warn("Sliding rate limit Redis error", zap.Error(err), zap.String("ip", ip))
 			c.Next()
 			return
 		}
 
-		count := countCmd.Val()
 		remaining := int64(limit) - count
 		if remaining < 0 {
 			remaining = 0
@@ -65,8 +60,11 @@ func SlidingWindowRateLimiter(rdb *redis.Client, limit int, window time.Duration
 		c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", remaining))
 		c.Header("X-RateLimit-Reset", fmt.Sprintf("%d", now.Add(window).Unix()))
 
+		metrics.RecordRateLimit(name, "ip", count, limit, count <= int64(limit))
+
 		if count > int64(limit) {
-			logger.Warn("Sliding rate limit exceeded",
+			logger.Ctx(c).Warn("Sliding rate limit exceeded",
+				zap.String("limiter", name),
 				zap.String("ip", ip),
 				zap.Int64("count", count),
 				zap.Int("limit", limit),
@@ -74,13 +72,8 @@ func SlidingWindowRateLimiter(rdb *redis.Client, limit int, window time.Duration
 
 			retryAfter := int(window.Seconds())
 			c.Header("Retry-After", fmt.Sprintf("%d", retryAfter))
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"success": false,
-				"error": gin.H{
-					"code":    "RATE_LIMITED",
-					"message": "Too many requests. Please try again later.",
-				},
-			})
+			response.Error(c, http.StatusTooManyRequests, apperrors.ErrCodeRateLimited, "Too many requests. Please try again later.")
+			c.Abort()
 			return
 		}
 
@@ -90,49 +83,60 @@ func SlidingWindowRateLimiter(rdb *redis.Client, limit int, window time.Duration
 
 // SlidingWindowRateLimiterByUser implements sliding window rate limiting by user ID or IP.
 // Authenticated users get their own quota, while unauthenticated users share IP-based limits.
-func SlidingWindowRateLimiterByUser(rdb *redis.Client, limit int, window time.Duration) gin.HandlerFunc {
+// plans (optional) scales the limit by the user's plan, then overrides
+// (optional) scales or lifts it for individual users. Requests with an API key
+// that has a rate limit of its own are limited per key and minute instead.
+func SlidingWindowRateLimiterByUser(rdb *redis.Client, name string, limits LimitFunc, plans services.PlanService, overrides services.RateLimitOverrideService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if rdb == nil {
 			c.Next()
 			return
 		}
+		limit, window := limits()
 
 		// Determine key based on authentication
 		var key string
 		var identifier string
-
-		if userID, exists := c.Get("user_id"); exists {
+		var scope string
+
+		if apiKey := requestAPIKey(c); apiKey != nil && apiKey.RateLimit > 0 {
+			limit, window = apiKey.RateLimit, time.Minute
+			key = fmt.Sprintf("sliding_rate:api_key:%d", apiKey.ID)
+			identifier = fmt.Sprintf("api_key:%d", apiKey.ID)
+			scope = "api_key"
+		} else if userID, exists := c.Get(string(logger.UserIDKey)); exists {
 			id := userID.(uint)
-			key = fmt.Sprintf("sliding_rate:user:%d", id)
+			if user, ok := c.Get("user"); ok && plans != nil {
+				limit = plans.RateLimit(user.(models.User).Plan, limit)
+			}
+			if overrides != nil {
+				var exempt bool
+				if limit, exempt = overrides.Adjust(id, limit); exempt {
+					c.Next()
+					return
+				}
+			}
+			key = services.UserRateLimitKey(id)
 			identifier = fmt.Sprintf("user:%d", id)
+			scope = "user"
 		} else {
 			ip := c.ClientIP()
 			key = fmt.Sprintf("sliding_rate:ip:%s", ip)
 			identifier = fmt.Sprintf("ip:%s", ip)
+			scope = "ip"
 		}
 
 		now := time.Now()
-		windowStart := now.Add(-window)
-
-		pipe := rdb.Pipeline()
-		pipe.ZRemRangeByScore(c, key, "0", fmt.Sprintf("%d", windowStart.UnixNano()))
-		pipe.ZAdd(c, key, redis.Z{
-			Score:  float64(now.UnixNano()),
-			Member: fmt.Sprintf("%d", now.UnixNano()),
-		})
-		countCmd := pipe.ZCard(c, key)
-		pipe.Expire(c, key, window+time.Second)
-
-		_, err := pipe.Exec(c)
+
+		count, err := services.CountSlidingWindow(c, rdb, key, window, now)
 		if err != nil {
-			logger.Warn("Sliding rate limit Redis error",
+			logger.Ctx(c).Warn("Sliding rate limit Redis error",
 				zap.Error(err),
 				zap.String("identifier", identifier))
 			c.Next()
 			return
 		}
 
-		count := countCmd.Val()
 		remaining := int64(limit) - count
 		if remaining < 0 {
 			remaining = 0
@@ -142,21 +146,19 @@ func SlidingWindowRateLimiterByUser(rdb *redis.Client, limit int, window time.Du
 		c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", remaining))
 		c.Header("X-RateLimit-Reset", fmt.Sprintf("%d", now.Add(window).Unix()))
 
+		metrics.RecordRateLimit(name, scope, count, limit, count <= int64(limit))
+
 		if count > int64(limit) {
-			logger.Warn("Sliding rate limit exceeded",
+			logger.Ctx(c).Warn("Sliding rate limit exceeded",
+				zap.String("limiter", name),
 				zap.String("identifier", identifier),
 				zap.Int64("count", count),
 				zap.Int("limit", limit),
 			)
 
 			c.Header("Retry-After", fmt.Sprintf("%d", int(window.Seconds())))
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"success": false,
-				"error": gin.H{
-					"code":    "RATE_LIMITED",
-					"message": "Too many requests. Please try again later.",
-				},
-			})
+			response.Error(c, http.StatusTooManyRequests, apperrors.ErrCodeRateLimited, "Too many requests. Please try again later.")
+			c.Abort()
 			return
 		}
 
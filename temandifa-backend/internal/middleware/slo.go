@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/metrics"
+)
+
+// SLO records availability and latency SLI events for an endpoint so
+// dashboards can show error-budget burn against the configured objectives
+func SLO(endpoint string, latencyThreshold time.Duration, availabilityTarget, latencyTarget float64) gin.HandlerFunc {
+	metrics.SLOObjective.WithLabelValues(endpoint, "availability").Set(availabilityTarget)
+	metrics.SLOObjective.WithLabelValues(endpoint, "latency").Set(latencyTarget)
+	metrics.SLOLatencyThreshold.WithLabelValues(endpoint).Set(latencyThreshold.Seconds())
+
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		metrics.RecordSLI(endpoint, c.Writer.Status(), time.Since(start).Seconds(), latencyThreshold.Seconds())
+	}
+}
+
+// ---- Per-Operation AI SLOs (Configurable) ----
+
+// DetectSLO returns an SLO middleware for object detection
+func DetectSLO(cfg *config.AIConfig) gin.HandlerFunc {
+	return SLO("detect", cfg.SLODetectLatency, cfg.SLOAvailabilityTarget, cfg.SLOLatencyTarget)
+}
+
+// OCRSLO returns an SLO middleware for OCR/text extraction
+func OCRSLO(cfg *config.AIConfig) gin.HandlerFunc {
+	return SLO("ocr", cfg.SLOOCRLatency, cfg.SLOAvailabilityTarget, cfg.SLOLatencyTarget)
+}
+
+// TranscribeSLO returns an SLO middleware for audio transcription
+func TranscribeSLO(cfg *config.AIConfig) gin.HandlerFunc {
+	return SLO("transcribe", cfg.SLOTranscribeLatency, cfg.SLOAvailabilityTarget, cfg.SLOLatencyTarget)
+}
+
+// VQASLO returns an SLO middleware for Visual Question Answering
+func VQASLO(cfg *config.AIConfig) gin.HandlerFunc {
+	return SLO("ask", cfg.SLOVQALatency, cfg.SLOAvailabilityTarget, cfg.SLOLatencyTarget)
+}
+
+// SimplifySLO returns an SLO middleware for text simplification
+func SimplifySLO(cfg *config.AIConfig) gin.HandlerFunc {
+	return SLO("simplify", cfg.SLOSimplifyLatency, cfg.SLOAvailabilityTarget, cfg.SLOLatencyTarget)
+}
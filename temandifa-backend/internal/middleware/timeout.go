@@ -8,6 +8,8 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"temandifa-backend/internal/config"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/response"
 )
 
 // Timeout creates a middleware that adds a timeout to the request context
@@ -34,13 +36,8 @@ func Timeout(timeout time.Duration) gin.HandlerFunc {
 			return
 		case <-ctx.Done():
 			// Timeout occurred
-			c.AbortWithStatusJSON(http.StatusGatewayTimeout, gin.H{
-				"success": false,
-				"error": gin.H{
-					"code":    "TIMEOUT",
-					"message": "The request took too long to process",
-				},
-			})
+			response.Error(c, http.StatusGatewayTimeout, apperrors.ErrCodeTimeout, "The request took too long to process")
+			c.Abort()
 			return
 		}
 	}
@@ -49,21 +46,26 @@ func Timeout(timeout time.Duration) gin.HandlerFunc {
 // ---- Per-Operation AI Timeouts (Configurable) ----
 
 // DetectTimeout returns a timeout middleware for object detection operations
-func DetectTimeout(cfg *config.Config) gin.HandlerFunc {
+func DetectTimeout(cfg *config.AIConfig) gin.HandlerFunc {
 	return Timeout(cfg.AIDetectTimeout)
 }
 
 // OCRTimeout returns a timeout middleware for OCR/text extraction operations
-func OCRTimeout(cfg *config.Config) gin.HandlerFunc {
+func OCRTimeout(cfg *config.AIConfig) gin.HandlerFunc {
 	return Timeout(cfg.AIOCRTimeout)
 }
 
 // TranscribeTimeout returns a timeout middleware for audio transcription operations
-func TranscribeTimeout(cfg *config.Config) gin.HandlerFunc {
+func TranscribeTimeout(cfg *config.AIConfig) gin.HandlerFunc {
 	return Timeout(cfg.AITranscribeTimeout)
 }
 
 // VQATimeout returns a timeout middleware for Visual Question Answering operations
-func VQATimeout(cfg *config.Config) gin.HandlerFunc {
+func VQATimeout(cfg *config.AIConfig) gin.HandlerFunc {
 	return Timeout(cfg.AIVQATimeout)
 }
+
+// SimplifyTimeout returns a timeout middleware for text simplification operations
+func SimplifyTimeout(cfg *config.AIConfig) gin.HandlerFunc {
+	return Timeout(cfg.AISimplifyTimeout)
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"temandifa-backend/internal/logger"
+)
+
+// TraceContext extracts the trace ID from a W3C traceparent header
+// ("00-<trace-id>-<parent-id>-<flags>") or starts a new trace when none is
+// present. The trace ID is added to the context, logs, error payloads, and
+// the X-Trace-ID response header so support can correlate user reports with
+// traces across the backend and AI Service.
+func TraceContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		traceID := parseTraceParent(c.GetHeader("traceparent"))
+		if traceID == "" {
+			traceID = generateTraceID()
+		}
+
+		c.Set(string(logger.TraceIDKey), traceID)
+		c.Request = c.Request.WithContext(logger.ContextWithTraceID(c.Request.Context(), traceID))
+		c.Header("X-Trace-ID", traceID)
+
+		c.Next()
+	}
+}
+
+// parseTraceParent returns the trace ID of a valid traceparent header, or ""
+func parseTraceParent(header string) string {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return ""
+	}
+
+	traceID := strings.ToLower(parts[1])
+	if len(traceID) != 32 || traceID == strings.Repeat("0", 32) {
+		return ""
+	}
+	if _, err := hex.DecodeString(traceID); err != nil {
+		return ""
+	}
+	return traceID
+}
+
+// generateTraceID creates a random 16-byte trace ID in W3C hex format
+func generateTraceID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
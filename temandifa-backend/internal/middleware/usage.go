@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/services"
+)
+
+// Usage accounts each authenticated request to an AI operation for the admin
+// usage analytics. Handlers mark cached answers with "X-Cache: HIT"; 5xx
+// responses count as errors. Rate-limited requests never reach it.
+func Usage(usage services.UsageService, operation string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		userID := c.GetUint(string(logger.UserIDKey))
+		if userID == 0 {
+			return
+		}
+		usage.Record(userID,
+			operation,
+			c.Writer.Header().Get("X-Cache") == "HIT",
+			c.Writer.Status() >= http.StatusInternalServerError,
+		)
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/response"
+)
+
+// RequireVerifiedEmail answers 403 EMAIL_NOT_VERIFIED to users who haven't
+// verified their email address yet when EMAIL_VERIFICATION_REQUIRED is set.
+// Place it after the auth middleware.
+func RequireVerifiedEmail(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !cfg.EmailVerificationRequired || c.MustGet("user").(models.User).EmailVerifiedAt != nil {
+			c.Next()
+			return
+		}
+		response.Error(c, http.StatusForbidden, response.ErrCodeEmailNotVerified,
+			"Please verify your email address first; POST /api/v1/verify-email/resend sends a new link")
+		c.Abort()
+	}
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Announcement kinds
+const (
+	AnnouncementInfo        = "info"
+	AnnouncementFeature     = "feature"
+	AnnouncementMaintenance = "maintenance"
+)
+
+// Announcement is an in-app notice shown from StartsAt until EndsAt (or
+// until deleted when EndsAt is nil)
+type Announcement struct {
+	ID           uint                      `gorm:"primaryKey" json:"id"`
+	Kind         string                    `gorm:"size:16;not null" json:"kind"`
+	StartsAt     time.Time                 `gorm:"not null" json:"starts_at"`
+	EndsAt       *time.Time                `json:"ends_at,omitempty"`
+	CreatedAt    time.Time                 `json:"created_at"`
+	UpdatedAt    time.Time                 `json:"updated_at"`
+	Translations []AnnouncementTranslation `gorm:"foreignKey:AnnouncementID" json:"translations"`
+}
+
+// Active reports whether the announcement is shown at t
+func (a *Announcement) Active(t time.Time) bool {
+	return !t.Before(a.StartsAt) && (a.EndsAt == nil || t.Before(*a.EndsAt))
+}
+
+// AnnouncementTranslation is an announcement's content in one locale
+type AnnouncementTranslation struct {
+	AnnouncementID uint   `gorm:"primaryKey;autoIncrement:false" json:"-"`
+	Locale         string `gorm:"primaryKey;size:16" json:"locale"`
+	Title          string `gorm:"size:200;not null" json:"title"`
+	Body           string `gorm:"not null" json:"body"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// APIKey is a long-lived key a partner app calls the AI endpoints with, on
+// behalf of its owner user (UserID). Scopes is a JSON array of the AI
+// operations it may use; only the SHA-256 hash of the key is stored, and the
+// key itself is only shown when it is created.
+type APIKey struct {
+	ID         uint64     `gorm:"primaryKey" json:"id"`
+	Name       string     `gorm:"size:100;not null" json:"name"`
+	UserID     uint       `gorm:"not null;index" json:"user_id"`
+	Prefix     string     `gorm:"size:16;not null" json:"prefix"`
+	KeyHash    string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	Scopes     string     `gorm:"type:text;not null;default:'[]'" json:"-"`
+	RateLimit  int        `gorm:"not null;default:0" json:"rate_limit"` // Requests per minute; 0 applies the owner's AI rate limit
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedBy  *uint      `json:"created_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// Expired reports whether the key expired by now
+func (k *APIKey) Expired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// AppVersion is the release manifest of the app on one platform (android or
+// ios). Versions are semantic versions without the "v" prefix, e.g. 2.4.0.
+type AppVersion struct {
+	Platform      string    `gorm:"primaryKey;size:16" json:"platform"`
+	MinVersion    string    `gorm:"size:32;not null" json:"min_version"`    // Older versions must update
+	LatestVersion string    `gorm:"size:32;not null" json:"latest_version"` // Older versions may update
+	StoreURL      string    `gorm:"not null;default:''" json:"store_url"`
+	Changelog     string    `gorm:"type:text;not null;default:''" json:"changelog"` // Of the latest version
+	UpdatedBy     *uint     `json:"updated_by,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// AuditLog records one administrative change
+type AuditLog struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	ActorID    *uint  `json:"actor_id,omitempty"` // nil for changes made outside a request
+	Action     string `gorm:"size:64;not null" json:"action"`
+	TargetType string `gorm:"size:32;not null" json:"target_type"`
+	TargetID   string `gorm:"size:64;not null" json:"target_id"`
+	Details    string `gorm:"not null" json:"details"` // JSON
+	// Before and After are JSON snapshots of the target; nil Before marks a
+	// creation, nil After a deletion, and both are nil for actions that
+	// don't change a stored target
+	Before    *string   `gorm:"column:before_state" json:"-"`
+	After     *string   `gorm:"column:after_state" json:"-"`
+	RequestID string    `gorm:"size:64;not null" json:"request_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
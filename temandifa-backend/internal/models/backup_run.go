@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Backup run statuses
+const (
+	BackupStatusRunning   = "running"
+	BackupStatusSucceeded = "succeeded"
+	BackupStatusFailed    = "failed"
+)
+
+// BackupRun records a single database backup attempt
+type BackupRun struct {
+	ID         uint64     `gorm:"primaryKey" json:"id"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Method     string     `gorm:"size:20;not null" json:"method"`
+	Status     string     `gorm:"size:20;not null;default:running" json:"status"`
+	Location   string     `json:"location"` // Object key (pg_dump) or snapshot ID
+	SizeBytes  int64      `json:"size_bytes"`
+	Error      string     `json:"error,omitempty"`
+}
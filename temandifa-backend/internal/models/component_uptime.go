@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// ComponentUptimeDaily counts the health check samples of one component on
+// one UTC day and how many of them were healthy
+type ComponentUptimeDaily struct {
+	Day            time.Time `gorm:"primaryKey;type:date" json:"day"`
+	Component      string    `gorm:"primaryKey;size:32" json:"component"`
+	Samples        int64     `gorm:"not null" json:"samples"`
+	HealthySamples int64     `gorm:"not null" json:"healthy_samples"`
+}
+
+// TableName keeps the singular table name
+func (ComponentUptimeDaily) TableName() string {
+	return "component_uptime_daily"
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Data export statuses
+const (
+	DataExportPending = "pending"
+	DataExportReady   = "ready"
+	DataExportFailed  = "failed"
+	DataExportExpired = "expired" // The archive was deleted after DATA_EXPORT_TTL
+)
+
+// DataExport is one archive of a user's personal data
+type DataExport struct {
+	ID          uint64     `gorm:"primaryKey" json:"id"`
+	UserID      uint       `gorm:"not null" json:"user_id"`
+	RequestedBy *uint      `json:"requested_by,omitempty"` // The administrator, or the user themselves
+	Status      string     `gorm:"size:20;not null;default:pending" json:"status"`
+	ObjectKey   string     `json:"-"`
+	SizeBytes   int64      `json:"size_bytes"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
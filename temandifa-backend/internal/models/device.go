@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Device platforms
+const (
+	PlatformAndroid = "android"
+	PlatformIOS     = "ios"
+	PlatformWeb     = "web"
+)
+
+// Device is an app installation a user signed in on, identified by the
+// app-generated DeviceID. Its Firebase Cloud Messaging token receives the
+// user's push notifications and moves to whoever registers it last; the
+// refresh tokens issued on the device are bound to it.
+type Device struct {
+	ID         uint64    `gorm:"primaryKey" json:"id"`
+	UserID     uint      `gorm:"not null;uniqueIndex:idx_devices_user_device" json:"-"`
+	DeviceID   string    `gorm:"size:128;not null;uniqueIndex:idx_devices_user_device" json:"device_id"`
+	Platform   string    `gorm:"size:16;not null" json:"platform"`
+	PushToken  *string   `gorm:"size:512;uniqueIndex" json:"-"`
+	AppVersion string    `gorm:"size:32;not null;default:''" json:"app_version"`
+	LastSeenAt time.Time `gorm:"not null" json:"last_seen_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
@@ -1,12 +1,43 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Consent states of an emergency contact
+const (
+	ConsentPending  = "pending"  // Asked (or not yet asked) to receive alerts
+	ConsentAccepted = "accepted" // Receives the user's emergency alerts
+	ConsentDeclined = "declined" // Refused; asked again only if the phone number or email changes
+)
 
 type EmergencyContact struct {
 	gorm.Model
 	UserID      uint   `json:"user_id" gorm:"index"`
 	Name        string `json:"name"`
 	PhoneNumber string `json:"phone_number"`
+	Email       string `json:"email" gorm:"size:255;not null;default:''"`
 	Relation    string `json:"relation"`   // e.g. "Orang Tua", "Saudara"
 	IsPrimary   bool   `json:"is_primary"` // To mark the main contact to call first
+
+	// Contacts only receive alerts once they agree to, answering the consent
+	// request sent to them with a one-time token
+	ConsentStatus      string     `json:"consent_status" gorm:"size:16;not null;default:'pending'"`
+	ConsentTokenHash   *string    `json:"-" gorm:"size:64;uniqueIndex:idx_emergency_contacts_consent_token"`
+	ConsentRequestedAt *time.Time `json:"consent_requested_at,omitempty"`
+	ConsentAnsweredAt  *time.Time `json:"consent_answered_at,omitempty"`
+}
+
+// EmergencyAlert is an alert a user sent to their emergency contacts, with
+// the context shared with them
+type EmergencyAlert struct {
+	ID               uint64    `gorm:"primaryKey" json:"id"`
+	UserID           uint      `gorm:"not null;index:idx_emergency_alerts_user_created,priority:1" json:"-"`
+	Latitude         *float64  `json:"latitude,omitempty"`
+	Longitude        *float64  `json:"longitude,omitempty"`
+	SceneDescription string    `gorm:"type:text;not null;default:''" json:"scene_description"`
+	ContactsNotified int       `gorm:"not null;default:0" json:"contacts_notified"`
+	CreatedAt        time.Time `gorm:"index:idx_emergency_alerts_user_created,priority:2" json:"created_at"`
 }
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Feature flag override subjects
+const (
+	FlagSubjectUser         = "user"         // Subject is the user ID
+	FlagSubjectOrganization = "organization" // Subject is the email domain, e.g. school.ac.id
+)
+
+// FeatureFlag is a persisted flag; when enabled it is on for
+// RolloutPercentage percent of users (stable per user)
+type FeatureFlag struct {
+	Name              string                `gorm:"primaryKey;size:64" json:"name"`
+	Description       string                `json:"description"`
+	Enabled           bool                  `gorm:"not null" json:"enabled"`
+	RolloutPercentage int                   `gorm:"not null" json:"rollout_percentage"`
+	CreatedAt         time.Time             `json:"created_at"`
+	UpdatedAt         time.Time             `json:"updated_at"`
+	Overrides         []FeatureFlagOverride `gorm:"foreignKey:FlagName;references:Name" json:"overrides"`
+}
+
+// FeatureFlagOverride forces a flag on or off for one user or organization
+type FeatureFlagOverride struct {
+	FlagName    string    `gorm:"primaryKey;size:64" json:"flag_name"`
+	SubjectType string    `gorm:"primaryKey;size:16" json:"subject_type"`
+	Subject     string    `gorm:"primaryKey;size:255" json:"subject"`
+	Enabled     bool      `gorm:"not null" json:"enabled"`
+	CreatedAt   time.Time `json:"created_at"`
+}
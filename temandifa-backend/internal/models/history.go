@@ -16,17 +16,24 @@ const (
 
 // History stores user activity records with AI features
 // Indexes:
-// - idx_history_user_created: composite index for efficient user history queries (UserID + CreatedAt DESC)
-// - idx_history_feature: index for filtering by feature type
+//   - idx_history_user_created: composite index for efficient user history queries (UserID + CreatedAt DESC)
+//   - idx_history_feature: index for filtering by feature type
+//   - idx_histories_user_client: unique (UserID, ClientID) so offline sync is idempotent
+//   - idx_histories_search: GIN index on the generated search_vector column
+//     (Postgres only, not mapped here; see HistoryRepository.Search)
 type History struct {
 	ID        uint           `gorm:"primaryKey" json:"id"`
 	CreatedAt time.Time      `json:"created_at" gorm:"index:idx_history_user_created,priority:2,sort:desc"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 
-	UserID      uint        `json:"user_id" gorm:"index:idx_history_user_created,priority:1;index:idx_history_user"`
+	UserID      uint        `json:"user_id" gorm:"index:idx_history_user_created,priority:1;index:idx_history_user;uniqueIndex:idx_histories_user_client,priority:1"`
 	User        User        `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
 	FeatureType FeatureType `json:"feature_type" gorm:"type:varchar(20);index:idx_history_feature"` // OBJECT, OCR, VOICE
 	InputSource string      `json:"input_source"`                                                   // URL or filename of image/audio
 	ResultText  string      `json:"result_text" gorm:"type:text"`                                   // Classification result or transcribed text
+	Version     uint        `json:"version" gorm:"not null;default:1"`                              // Optimistic lock, bumped on every update
+
+	// ClientID is generated by the device for entries recorded offline and synced later
+	ClientID *string `json:"client_id,omitempty" gorm:"type:uuid;uniqueIndex:idx_histories_user_client,priority:2"`
 }
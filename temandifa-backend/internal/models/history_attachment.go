@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Kinds of history attachments
+const (
+	AttachmentImage = "image"
+	AttachmentAudio = "audio"
+)
+
+// HistoryAttachment is an image or recording attached to a history entry.
+// The file lives in object storage under HISTORY_ATTACHMENT_PREFIX.
+type HistoryAttachment struct {
+	ID           uint64    `gorm:"primaryKey" json:"id"`
+	HistoryID    uint      `gorm:"not null;index" json:"history_id"`
+	UserID       uint      `gorm:"not null;index" json:"-"`
+	Kind         string    `gorm:"size:10;not null" json:"kind"` // image or audio
+	ContentType  string    `gorm:"size:100;not null" json:"content_type"`
+	SizeBytes    int64     `gorm:"not null" json:"size_bytes"`
+	Filename     string    `gorm:"not null;default:''" json:"filename"`
+	ObjectKey    string    `gorm:"not null" json:"-"`
+	ThumbnailKey string    `gorm:"not null;default:''" json:"-"`           // JPEG thumbnail of an image, if any
+	Waveform     string    `gorm:"type:text;not null;default:''" json:"-"` // JSON array of peaks 0-100 of a recording, if any
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Job run statuses
+const (
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+)
+
+// JobRun records one manually triggered background job
+type JobRun struct {
+	ID          uint64     `gorm:"primaryKey" json:"id"`
+	Name        string     `gorm:"size:32;not null" json:"name"`
+	Status      string     `gorm:"size:20;not null;default:running" json:"status"`
+	TriggeredBy *uint      `json:"triggered_by,omitempty"`
+	Result      string     `gorm:"not null" json:"result"` // JSON, job specific
+	Error       string     `json:"error,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
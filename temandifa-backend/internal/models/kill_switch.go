@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// KillSwitch disables one AI operation until it is removed
+type KillSwitch struct {
+	Operation  string    `gorm:"primaryKey;size:32" json:"operation"`
+	Reason     string    `gorm:"not null" json:"reason"`
+	DisabledBy *uint     `json:"disabled_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Notification is an entry of the in-app inbox: a copy of a push
+// notification, kept whether or not the push reached a device. A nil UserID
+// marks a broadcast (an announcement) shown to every user who signed up
+// before it.
+type Notification struct {
+	ID        uint64    `gorm:"primaryKey" json:"id"`
+	UserID    *uint     `gorm:"index" json:"-"`
+	Type      string    `gorm:"size:64;not null" json:"type"`
+	Title     string    `gorm:"size:200;not null" json:"title"`
+	Body      string    `gorm:"not null" json:"body"`
+	Data      string    `gorm:"type:text;not null" json:"-"` // JSON object of string values, as pushed
+	DedupKey  *string   `gorm:"size:128;uniqueIndex" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// ReadAt is when the user read it, loaded with the user's inbox
+	ReadAt *time.Time `gorm:"->;-:migration" json:"read_at"`
+}
+
+// NotificationRead records that a user read a notification
+type NotificationRead struct {
+	NotificationID uint64    `gorm:"primaryKey;autoIncrement:false"`
+	UserID         uint      `gorm:"primaryKey;autoIncrement:false"`
+	ReadAt         time.Time `gorm:"not null"`
+}
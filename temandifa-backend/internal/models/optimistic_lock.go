@@ -0,0 +1,36 @@
+package models
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// applyOptimisticLock makes an update of a loaded row conditional on its
+// version and bumps the version, so a concurrent writer's update matches no
+// rows instead of silently overwriting. Updates through a bare model
+// (version 0), e.g. Model(&User{}).Where(...), are not versioned.
+func applyOptimisticLock(tx *gorm.DB, version uint) {
+	if version == 0 {
+		return
+	}
+
+	tx.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "version"}, Value: version},
+	}})
+	if len(tx.Statement.Selects) > 0 {
+		tx.Statement.Selects = append(tx.Statement.Selects, "version")
+	}
+	tx.Statement.SetColumn("version", version+1)
+}
+
+// BeforeUpdate applies optimistic locking to user updates
+func (u *User) BeforeUpdate(tx *gorm.DB) error {
+	applyOptimisticLock(tx, u.Version)
+	return nil
+}
+
+// BeforeUpdate applies optimistic locking to history updates
+func (h *History) BeforeUpdate(tx *gorm.DB) error {
+	applyOptimisticLock(tx, h.Version)
+	return nil
+}
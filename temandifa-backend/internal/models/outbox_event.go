@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Outbox event statuses
+const (
+	OutboxStatusPending    = "pending"
+	OutboxStatusDispatched = "dispatched"
+	OutboxStatusFailed     = "failed" // Gave up after the maximum number of attempts
+)
+
+// OutboxEvent is an integration event stored in the same transaction as the
+// business change that produced it, then forwarded by the outbox dispatcher
+type OutboxEvent struct {
+	ID            uint64     `gorm:"primaryKey" json:"id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	EventType     string     `gorm:"size:100;not null" json:"event_type"`
+	AggregateType string     `gorm:"size:50;not null" json:"aggregate_type"`
+	AggregateID   string     `gorm:"size:100;not null" json:"aggregate_id"`
+	Payload       string     `gorm:"type:jsonb;not null" json:"payload"`
+	Status        string     `gorm:"size:20;not null;default:pending" json:"status"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	DispatchedAt  *time.Time `json:"dispatched_at,omitempty"`
+}
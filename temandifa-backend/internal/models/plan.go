@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Built-in subscription plans; users.plan names one of them
+const (
+	PlanFree        = "free"
+	PlanPlus        = "plus"
+	PlanInstitution = "institution"
+)
+
+// Plan is a subscription tier with its AI allowances. The set of plans is
+// fixed; administrators tune the allowances.
+type Plan struct {
+	Name                string    `gorm:"primaryKey;size:32" json:"name"`
+	Description         string    `json:"description"`
+	DailyAIQuota        int       `gorm:"not null" json:"daily_ai_quota"`        // AI requests per UTC day, 0 for unlimited
+	RateLimitMultiplier float64   `gorm:"not null" json:"rate_limit_multiplier"` // Scales the per-user AI rate limit
+	CacheTTLMultiplier  float64   `gorm:"not null" json:"cache_ttl_multiplier"`  // Scales the TTLs of cached AI results
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// Unlimited reports whether the plan has no daily AI quota
+func (p *Plan) Unlimited() bool {
+	return p.DailyAIQuota == 0
+}
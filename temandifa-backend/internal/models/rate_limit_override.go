@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// RateLimitOverride adjusts the per-user rate limit for one user: the limit
+// is multiplied by Multiplier, or not applied at all when Exempt
+type RateLimitOverride struct {
+	UserID     uint       `gorm:"primaryKey;autoIncrement:false" json:"user_id"`
+	Multiplier float64    `gorm:"not null" json:"multiplier"`
+	Exempt     bool       `gorm:"not null" json:"exempt"`
+	Reason     string     `json:"reason"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// Active reports whether the override applies at t
+func (o *RateLimitOverride) Active(t time.Time) bool {
+	return o.ExpiresAt == nil || t.Before(*o.ExpiresAt)
+}
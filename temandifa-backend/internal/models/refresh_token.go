@@ -18,6 +18,19 @@ type RefreshToken struct {
 	// Device info for tracking
 	UserAgent string `gorm:"size:500" json:"user_agent,omitempty"`
 	IPAddress string `gorm:"size:45" json:"ip_address,omitempty"`
+	DeviceID  string `gorm:"size:128;not null;default:''" json:"device_id,omitempty"` // Device the token is bound to, if any
+
+	// SignedInAt is when the session started; rotation carries it over, so
+	// CreatedAt is when the session was last refreshed
+	SignedInAt *time.Time `json:"signed_in_at,omitempty"`
+	// AccessTokenHash is the blacklist hash of the access token issued with
+	// this refresh token
+	AccessTokenHash string `gorm:"size:64;not null;default:''" json:"-"`
+	// FamilyID is the ID of the session's first token, shared by the tokens
+	// rotated from it; Rotated marks tokens revoked by a rotation, which are
+	// never presented again by their rightful holder
+	FamilyID uint `gorm:"index;not null;default:0" json:"-"`
+	Rotated  bool `gorm:"not null;default:false" json:"-"`
 
 	// Relations
 	User User `gorm:"foreignKey:UserID" json:"-"`
@@ -34,3 +47,22 @@ func (rt *RefreshToken) Revoke() {
 	rt.Revoked = true
 	rt.RevokedAt = &now
 }
+
+// Rotate marks the token as revoked by a rotation
+func (rt *RefreshToken) Rotate() {
+	rt.Revoke()
+	rt.Rotated = true
+}
+
+// TokenEpoch is the single-row generation of access tokens; tokens issued
+// under an older epoch are rejected
+type TokenEpoch struct {
+	ID        uint      `gorm:"primaryKey"`
+	Epoch     int64     `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+}
+
+// TableName keeps the singular table name
+func (TokenEpoch) TableName() string {
+	return "token_epoch"
+}
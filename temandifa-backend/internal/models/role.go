@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+// PermissionAll grants every permission
+const PermissionAll = "*"
+
+// Permissions granted through roles
+const (
+	PermUsersRead           = "users:read"
+	PermUsersManage         = "users:manage"
+	PermSessionsManage      = "sessions:manage"
+	PermRolesManage         = "roles:manage"
+	PermPlansManage         = "plans:manage"
+	PermFlagsManage         = "flags:manage"
+	PermAnnouncementsManage = "announcements:manage"
+	PermCacheManage         = "cache:manage"
+	PermCaptureManage       = "capture:manage"
+	PermBlacklistManage     = "blacklist:manage"
+	PermAnalyticsRead       = "analytics:read"
+	PermConfigRead          = "config:read"
+	PermSystemRead          = "system:read"
+	PermAuditRead           = "audit:read"
+	PermJobsRun             = "jobs:run"
+	PermDebug               = "debug:access"
+	PermAppVersionsManage   = "app_versions:manage"
+	PermTranslationsManage  = "translations:manage"
+	PermWebhooksManage      = "webhooks:manage"
+	PermAPIKeysManage       = "api_keys:manage"
+)
+
+// PermissionCatalog describes every permission that can be attached to a role
+var PermissionCatalog = map[string]string{
+	PermissionAll:           "Every permission",
+	PermUsersRead:           "Search users and preview purges",
+	PermUsersManage:         "Assign roles, plans and rate limit overrides",
+	PermSessionsManage:      "List and revoke login sessions",
+	PermRolesManage:         "Create, change and delete roles",
+	PermPlansManage:         "Change subscription plan allowances",
+	PermFlagsManage:         "Manage feature flags, overrides and AI kill switches",
+	PermAnnouncementsManage: "Manage in-app announcements",
+	PermCacheManage:         "Inspect and clear the AI response cache",
+	PermCaptureManage:       "Capture requests for debugging",
+	PermBlacklistManage:     "Inspect and prune the access token blacklist",
+	PermAnalyticsRead:       "Read usage analytics",
+	PermConfigRead:          "Read the effective configuration",
+	PermSystemRead:          "Read system information",
+	PermAuditRead:           "Read the admin audit log",
+	PermJobsRun:             "Run background jobs on demand",
+	PermDebug:               "Use the profiling endpoints",
+	PermAppVersionsManage:   "Set the minimum and latest app versions",
+	PermTranslationsManage:  "Upload and restore translation bundles",
+	PermWebhooksManage:      "Manage webhook subscriptions",
+	PermAPIKeysManage:       "Issue and revoke partner API keys",
+}
+
+// Role is a named set of permissions assigned to users through User.Role
+type Role struct {
+	Name        string           `gorm:"primaryKey;size:32" json:"name"`
+	Description string           `json:"description"`
+	System      bool             `gorm:"not null" json:"system"`            // built in, can't be deleted
+	Version     uint             `gorm:"not null;default:1" json:"version"` // Bumped when the permissions change
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+	Permissions []RolePermission `gorm:"foreignKey:RoleName;references:Name" json:"permissions"`
+}
+
+// RolePermission attaches one permission to a role
+type RolePermission struct {
+	RoleName   string `gorm:"primaryKey;size:32" json:"-"`
+	Permission string `gorm:"primaryKey;size:64" json:"permission"`
+}
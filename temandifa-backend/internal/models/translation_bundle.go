@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// TranslationBundle is one version of a locale's translations: app strings
+// and server messages, each a JSON object of keys to text. Checksum is the
+// SHA-256 of the content and makes up the bundle's ETag.
+type TranslationBundle struct {
+	ID            uint64    `gorm:"primaryKey" json:"id"`
+	Locale        string    `gorm:"size:16;not null;uniqueIndex:uq_translation_bundles_locale_version" json:"locale"` // BCP 47 tag, e.g. "id" or "en-US"
+	Version       int       `gorm:"not null;uniqueIndex:uq_translation_bundles_locale_version" json:"version"`
+	AppStrings    string    `gorm:"type:text;not null;default:'{}'" json:"-"`
+	ServerStrings string    `gorm:"type:text;not null;default:'{}'" json:"-"`
+	Checksum      string    `gorm:"size:64;not null" json:"checksum"`
+	CreatedBy     *uint     `json:"created_by,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
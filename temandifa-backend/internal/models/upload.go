@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Upload is a resumable (tus) upload. The received bytes are kept in object
+// storage, one object per PATCH request, until the upload expires.
+type Upload struct {
+	ID          string     `gorm:"primaryKey;size:36" json:"id"` // Random 128-bit hex, the last segment of the upload URL
+	UserID      uint       `gorm:"not null" json:"user_id"`
+	Length      int64      `gorm:"not null" json:"length"`                                // Upload-Length
+	Offset      int64      `gorm:"column:upload_offset;not null;default:0" json:"offset"` // Bytes received so far
+	Metadata    string     `gorm:"type:text;not null;default:''" json:"-"`                // Upload-Metadata as sent, echoed on HEAD
+	Filename    string     `gorm:"not null;default:''" json:"filename"`
+	Chunks      string     `gorm:"type:text;not null;default:''" json:"-"` // Names of the stored chunks in offset order, one per line
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   time.Time  `gorm:"not null" json:"expires_at"`
+}
+
+// Complete reports whether every byte has been received
+func (u *Upload) Complete() bool {
+	return u.Offset == u.Length
+}
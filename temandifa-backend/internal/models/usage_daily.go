@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// UsageDaily counts one user's AI requests for one operation on one UTC day
+type UsageDaily struct {
+	Day       time.Time `gorm:"primaryKey;type:date" json:"day"`
+	UserID    uint      `gorm:"primaryKey;autoIncrement:false" json:"user_id"`
+	Operation string    `gorm:"primaryKey;size:32" json:"operation"`
+	Requests  int64     `gorm:"not null" json:"requests"`
+	CacheHits int64     `gorm:"not null" json:"cache_hits"`
+	Errors    int64     `gorm:"not null" json:"errors"` // 5xx responses
+}
+
+// TableName keeps the singular table name
+func (UsageDaily) TableName() string {
+	return "usage_daily"
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// UsageReport is one user's usage over one UTC month (YYYY-MM). Operations
+// is a JSON object of the counters per AI operation; storage counts the
+// attachments created by the month's end that were still stored when the
+// report was generated.
+type UsageReport struct {
+	ID           uint64    `gorm:"primaryKey" json:"id"`
+	UserID       uint      `gorm:"not null" json:"user_id"`
+	Month        string    `gorm:"size:7;not null" json:"month"`
+	Plan         string    `gorm:"size:32;not null;default:''" json:"plan"` // The user's plan when generated
+	Requests     int64     `gorm:"not null" json:"requests"`
+	CacheHits    int64     `gorm:"not null" json:"cache_hits"`
+	Errors       int64     `gorm:"not null" json:"errors"`
+	Operations   string    `gorm:"type:text;not null;default:'{}'" json:"-"`
+	StorageBytes int64     `gorm:"not null" json:"storage_bytes"`
+	Attachments  int64     `gorm:"not null" json:"attachments"`
+	GeneratedAt  time.Time `gorm:"not null" json:"generated_at"`
+}
@@ -2,15 +2,32 @@ package models
 
 import "time"
 
+// User roles
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 // User represents the user entity
 type User struct {
-	ID             uint       `gorm:"primaryKey" json:"id"`
-	CreatedAt      time.Time  `json:"created_at"`
-	UpdatedAt      time.Time  `json:"updated_at"`
-	DeletedAt      *time.Time `gorm:"index" json:"deleted_at,omitempty"`
-	Email          string     `gorm:"uniqueIndex;not null" json:"email"`
-	Password       string     `json:"-"`
-	FullName       string     `json:"full_name"`
-	ProfilePicture string     `json:"profile_picture"`
-	Role           string     `gorm:"default:user" json:"role"`
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	DeletedAt         *time.Time `gorm:"index" json:"deleted_at,omitempty"`
+	Email             string     `gorm:"uniqueIndex;not null" json:"email"`
+	Password          string     `json:"-"`
+	FullName          string     `json:"full_name"`
+	ProfilePicture    string     `json:"profile_picture"`
+	PreferredLanguage string     `gorm:"size:16;not null;default:''" json:"preferred_language"` // BCP 47 tag; empty follows the device
+	Role              string     `gorm:"default:user" json:"role"`
+	Plan              string     `gorm:"default:free" json:"plan"`
+	Organization      string     `gorm:"size:64;not null;default:''" json:"organization"` // Set by the organization's identity provider; empty for others
+	EmailVerifiedAt   *time.Time `json:"email_verified_at"`                               // Nil until the user follows the link sent to their email
+	Version           uint       `gorm:"not null;default:1" json:"version"`               // Optimistic lock, bumped on every update
+
+	// Email address change: the address the user asked to change to, and
+	// which of the two addresses confirmed it so far
+	PendingEmail             string `gorm:"size:255;not null;default:''" json:"pending_email,omitempty"`
+	PendingEmailOldConfirmed bool   `gorm:"not null;default:false" json:"-"`
+	PendingEmailNewConfirmed bool   `gorm:"not null;default:false" json:"-"`
 }
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Sign-in providers
+const (
+	ProviderGoogle = "google"
+	ProviderApple  = "apple"
+
+	// ProviderOIDCPrefix starts the names of organizations' OpenID Connect
+	// providers, e.g. oidc:ui
+	ProviderOIDCPrefix = "oidc:"
+)
+
+// UserIdentity links a sign-in provider's account (its ID tokens' subject)
+// to a user
+type UserIdentity struct {
+	ID         uint64    `gorm:"primaryKey" json:"-"`
+	UserID     uint      `gorm:"not null" json:"-"`
+	Provider   string    `gorm:"size:64;not null" json:"provider"`
+	Subject    string    `gorm:"size:255;not null" json:"-"`
+	Email      string    `gorm:"size:255;not null" json:"email"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+}
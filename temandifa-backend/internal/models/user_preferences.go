@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Narration verbosity levels
+const (
+	VerbosityBrief    = "brief"    // Hazards and text only
+	VerbosityNormal   = "normal"   // Everything that comes into view
+	VerbosityDetailed = "detailed" // Like normal; the app adds descriptions
+)
+
+// UserPreferences are a user's accessibility preferences. TTS and haptics
+// settings are applied by the app; the OCR language and verbosity are the
+// defaults of AI requests that don't specify them.
+type UserPreferences struct {
+	UserID      uint      `gorm:"primaryKey;autoIncrement:false" json:"-"`
+	TTSVoice    string    `gorm:"column:tts_voice;size:64;not null;default:''" json:"tts_voice"` // Empty for the device's default voice
+	TTSSpeed    float64   `gorm:"column:tts_speed;not null;default:1" json:"tts_speed"`          // Speech rate multiplier
+	OCRLanguage string    `gorm:"column:ocr_language;size:8;not null;default:'en'" json:"ocr_language"`
+	Verbosity   string    `gorm:"size:16;not null;default:'normal'" json:"verbosity"`
+	Haptics     bool      `gorm:"not null;default:true" json:"haptics"`
+	UpdatedAt   time.Time `json:"updated_at,omitzero"` // Zero for the defaults
+}
+
+// DefaultUserPreferences are the preferences of users who haven't changed any
+func DefaultUserPreferences(userID uint) *UserPreferences {
+	return &UserPreferences{
+		UserID:      userID,
+		TTSSpeed:    1,
+		OCRLanguage: "en",
+		Verbosity:   VerbosityNormal,
+		Haptics:     true,
+	}
+}
+
+// TableName keeps the plural table name
+func (UserPreferences) TableName() string {
+	return "user_preferences"
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// UserSetting is one app setting synced across a user's devices. Vector is
+// the version vector (device ID to edit count, as JSON) of the edits the
+// value reflects; Seq orders the user's changes for delta syncs. Deleted
+// settings are kept as tombstones.
+type UserSetting struct {
+	UserID    uint      `gorm:"primaryKey;autoIncrement:false" json:"-"`
+	Key       string    `gorm:"primaryKey;size:64" json:"key"`
+	Value     string    `gorm:"type:text;not null" json:"value"`  // JSON
+	Vector    string    `gorm:"type:text;not null" json:"vector"` // JSON
+	Deleted   bool      `gorm:"not null;default:false" json:"deleted"`
+	Seq       int64     `gorm:"not null" json:"seq"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Verification token purposes
+const (
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeMagicLink         = "magic_link"
+	TokenPurposeEmailChange       = "email_change" // Sent to both the current and the new address
+)
+
+// VerificationToken is a single-use token sent to a user's email address,
+// stored as the SHA-256 hash of the token in the link. It proves the user
+// reads mail sent to Email, so it is only valid while that is still the
+// user's address.
+type VerificationToken struct {
+	ID        uint64    `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null"`
+	Purpose   string    `gorm:"size:32;not null"`
+	TokenHash string    `gorm:"size:64;not null;uniqueIndex"`
+	Email     string    `gorm:"size:255;not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
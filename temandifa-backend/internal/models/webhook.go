@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Webhook delivery statuses
+const (
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed" // Retried with the outbox event, if any
+)
+
+// WebhookSubscription is a third party's endpoint receiving integration
+// events of the subscribed types. EventTypes is a JSON array; Secret signs
+// the deliveries and is only shown when it is created.
+type WebhookSubscription struct {
+	ID         uint64    `gorm:"primaryKey" json:"id"`
+	Name       string    `gorm:"size:100;not null" json:"name"`
+	URL        string    `gorm:"size:2048;not null" json:"url"`
+	EventTypes string    `gorm:"type:text;not null;default:'[]'" json:"-"`
+	Secret     string    `gorm:"size:128;not null" json:"-"`
+	Active     bool      `gorm:"not null;default:true" json:"active"`
+	CreatedBy  *uint     `json:"created_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// WebhookDelivery records one delivery of an event to a subscription, with
+// the attempts made in a row; EventID is the outbox event, nil for test
+// deliveries
+type WebhookDelivery struct {
+	ID             uint64    `gorm:"primaryKey" json:"id"`
+	SubscriptionID uint64    `gorm:"not null;index:idx_webhook_deliveries_subscription,priority:1" json:"subscription_id"`
+	EventID        *uint64   `gorm:"index:idx_webhook_deliveries_event,priority:1" json:"event_id,omitempty"`
+	EventType      string    `gorm:"size:100;not null" json:"event_type"`
+	Status         string    `gorm:"size:16;not null" json:"status"`
+	Attempts       int       `gorm:"not null;default:0" json:"attempts"`
+	ResponseStatus int       `gorm:"not null;default:0" json:"response_status,omitempty"` // 0 when no response was received
+	Error          string    `gorm:"type:text;not null;default:''" json:"error,omitempty"`
+	DurationMS     int64     `gorm:"column:duration_ms;not null;default:0" json:"duration_ms"`
+	CreatedAt      time.Time `gorm:"index:idx_webhook_deliveries_created" json:"created_at"`
+}
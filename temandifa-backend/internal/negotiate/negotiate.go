@@ -0,0 +1,101 @@
+// Package negotiate renders response bodies as JSON or MessagePack, as the
+// request's Accept header prefers, and turns MessagePack request bodies into
+// JSON for the handlers' JSON binding.
+package negotiate
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"github.com/ugorji/go/codec"
+)
+
+var errTrailingData = errors.New("unexpected data after the MessagePack document")
+
+// Media types
+const (
+	MIMEJSON     = "application/json"
+	MIMEMsgPack  = "application/msgpack"
+	MIMEMsgPackX = "application/x-msgpack" // Pre-registration name, still sent by many clients
+)
+
+// msgpackHandle encodes structs by their json tags (omitempty included) and
+// times as the MessagePack timestamp extension
+var msgpackHandle = func() *codec.MsgpackHandle {
+	h := &codec.MsgpackHandle{WriteExt: true}
+	h.RawToString = true
+	h.MapType = reflect.TypeOf(map[string]any(nil))
+	h.TypeInfos = codec.NewTypeInfos([]string{"json"})
+	return h
+}()
+
+// WantsMsgPack reports whether the request's Accept header prefers
+// MessagePack over JSON. Without an Accept header, or with */*, it's JSON.
+func WantsMsgPack(c *gin.Context) bool {
+	format := c.NegotiateFormat(MIMEJSON, MIMEMsgPack, MIMEMsgPackX)
+	return format == MIMEMsgPack || format == MIMEMsgPackX
+}
+
+// Render writes data with status as MessagePack when the request prefers
+// it, otherwise as JSON
+func Render(c *gin.Context, status int, data any) {
+	c.Writer.Header().Add("Vary", "Accept")
+
+	var (
+		body        []byte
+		contentType string
+		err         error
+	)
+	if WantsMsgPack(c) {
+		contentType = MIMEMsgPack
+		err = codec.NewEncoderBytes(&body, msgpackHandle).Encode(data)
+	} else {
+		// goccy/go-json for performance
+		contentType = MIMEJSON + "; charset=utf-8"
+		body, err = json.Marshal(data)
+	}
+	if err != nil {
+		// Fall back to gin's JSON renderer (unlikely)
+		c.JSON(status, data)
+		return
+	}
+	c.Data(status, contentType, body)
+}
+
+// Abort renders data like Render and stops the handler chain
+func Abort(c *gin.Context, status int, data any) {
+	c.Abort()
+	Render(c, status, data)
+}
+
+// IsMsgPack reports whether a Content-Type is MessagePack
+func IsMsgPack(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	return mediaType == MIMEMsgPack || mediaType == MIMEMsgPackX
+}
+
+// MsgPackToJSON converts a MessagePack document to JSON. Binary values
+// become base64 strings and timestamps RFC 3339 strings, as in JSON bodies.
+func MsgPackToJSON(body []byte) ([]byte, error) {
+	var value any
+	dec := codec.NewDecoderBytes(body, msgpackHandle)
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	// Trailing bytes mean this isn't one MessagePack document
+	var extra any
+	if err := dec.Decode(&extra); err == nil {
+		return nil, errTrailingData
+	}
+	return json.Marshal(value)
+}
+
+// RequestIsMsgPack reports whether a request carries a MessagePack body
+func RequestIsMsgPack(r *http.Request) bool {
+	return r.Body != nil && r.Body != http.NoBody && IsMsgPack(r.Header.Get("Content-Type"))
+}
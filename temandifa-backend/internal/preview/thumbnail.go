@@ -0,0 +1,81 @@
+// Package preview makes small previews of attached media: JPEG thumbnails of
+// images and low-resolution waveforms of recordings.
+package preview
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+
+	// Decoders for image.Decode
+	_ "image/gif"
+	_ "image/png"
+)
+
+// ErrUnsupported is returned for media whose format can't be previewed
+var ErrUnsupported = errors.New("preview: unsupported format")
+
+// thumbnailQuality is the JPEG quality of thumbnails
+const thumbnailQuality = 75
+
+// maxSourcePixels bounds the images decoded for thumbnails (about a 40
+// megapixel photo), so a crafted header can't claim huge dimensions
+const maxSourcePixels = 40_000_000
+
+// Thumbnail scales an image (JPEG, PNG or GIF) down to fit maxSide pixels
+// and encodes it as JPEG. Smaller images keep their size.
+func Thumbnail(content []byte, maxSide int) ([]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return nil, ErrUnsupported
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxSourcePixels {
+		return nil, ErrUnsupported
+	}
+	src, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	if err := jpeg.Encode(&out, scaleDown(src, maxSide), &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// scaleDown resizes src to fit maxSide by averaging the source pixels each
+// thumbnail pixel covers. Transparent areas become white.
+func scaleDown(src image.Image, maxSide int) image.Image {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	tw, th := w, h
+	if w >= h && w > maxSide {
+		tw, th = maxSide, max(1, h*maxSide/w)
+	} else if h > w && h > maxSide {
+		tw, th = max(1, w*maxSide/h), maxSide
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for ty := range th {
+		y0, y1 := bounds.Min.Y+ty*h/th, bounds.Min.Y+max((ty+1)*h/th, ty*h/th+1)
+		for tx := range tw {
+			x0, x1 := bounds.Min.X+tx*w/tw, bounds.Min.X+max((tx+1)*w/tw, tx*w/tw+1)
+			var r, g, b, n uint64
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					pr, pg, pb, pa := src.At(x, y).RGBA()
+					// Composite over white
+					r += uint64(pr + (0xffff - pa))
+					g += uint64(pg + (0xffff - pa))
+					b += uint64(pb + (0xffff - pa))
+					n++
+				}
+			}
+			dst.Set(tx, ty, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: 0xffff})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,99 @@
+package preview
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// Waveform returns the peak amplitude (0-100) of points equal slices of a
+// WAV recording with 8, 16, 24 or 32-bit integer PCM samples, all channels
+// mixed. Compressed formats (MP3, Ogg, WebM, M4A) return ErrUnsupported.
+func Waveform(content []byte, points int) ([]int, error) {
+	format, data, ok := parseWAV(content)
+	if !ok || points <= 0 {
+		return nil, ErrUnsupported
+	}
+	width := int(format.bitsPerSample / 8)
+	frame := width * int(format.channels)
+	if frame == 0 {
+		return nil, ErrUnsupported
+	}
+	frames := len(data) / frame
+	if frames == 0 {
+		return nil, ErrUnsupported
+	}
+
+	peaks := make([]int, min(points, frames))
+	full := math.Ldexp(1, int(format.bitsPerSample)-1)
+	for i := range peaks {
+		start, end := i*frames/len(peaks), (i+1)*frames/len(peaks)
+		peak := 0.0
+		for s := start * frame; s < end*frame; s += width {
+			peak = max(peak, math.Abs(sample(data[s:s+width])))
+		}
+		peaks[i] = int(math.Round(min(peak/full, 1) * 100))
+	}
+	return peaks, nil
+}
+
+type wavFormat struct {
+	channels      uint16
+	bitsPerSample uint16
+}
+
+// parseWAV finds the fmt and data chunks of a RIFF/WAVE file with integer
+// PCM samples
+func parseWAV(content []byte) (wavFormat, []byte, bool) {
+	if len(content) < 12 || string(content[0:4]) != "RIFF" || string(content[8:12]) != "WAVE" {
+		return wavFormat{}, nil, false
+	}
+	var (
+		format    wavFormat
+		hasFormat bool
+	)
+	for pos := 12; pos+8 <= len(content); {
+		id := string(content[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(content[pos+4 : pos+8]))
+		body := content[pos+8:]
+		if size < len(body) {
+			body = body[:size]
+		}
+		switch id {
+		case "fmt ":
+			if len(body) < 16 {
+				return wavFormat{}, nil, false
+			}
+			// 1 is PCM; 0xFFFE (extensible) usually wraps PCM too
+			tag := binary.LittleEndian.Uint16(body[0:2])
+			format = wavFormat{
+				channels:      binary.LittleEndian.Uint16(body[2:4]),
+				bitsPerSample: binary.LittleEndian.Uint16(body[14:16]),
+			}
+			switch format.bitsPerSample {
+			case 8, 16, 24, 32:
+			default:
+				return wavFormat{}, nil, false
+			}
+			hasFormat = tag == 1 || tag == 0xFFFE
+		case "data":
+			return format, body, hasFormat
+		}
+		// Chunks are padded to an even size
+		pos += 8 + size + size%2
+	}
+	return wavFormat{}, nil, false
+}
+
+// sample decodes one little-endian PCM sample; 8-bit samples are unsigned
+func sample(b []byte) float64 {
+	switch len(b) {
+	case 1:
+		return float64(int(b[0]) - 128)
+	case 2:
+		return float64(int16(binary.LittleEndian.Uint16(b)))
+	case 3:
+		return float64(int32(uint32(b[0])<<8|uint32(b[1])<<16|uint32(b[2])<<24) >> 8)
+	default:
+		return float64(int32(binary.LittleEndian.Uint32(b)))
+	}
+}
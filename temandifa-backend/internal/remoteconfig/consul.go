@@ -0,0 +1,72 @@
+package remoteconfig
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// consulWait is how long a blocking query waits for a change
+const consulWait = "55s"
+
+// consulSource reads the prefix recursively with blocking queries, so changes
+// arrive as soon as Consul commits them
+type consulSource struct {
+	http   *http.Client
+	addr   string
+	prefix string
+	token  string
+}
+
+func (s *consulSource) Fetch(ctx context.Context, index uint64) (map[string]string, uint64, error) {
+	query := url.Values{"recurse": {"true"}}
+	if index > 0 {
+		query.Set("index", strconv.FormatUint(index, 10))
+		query.Set("wait", consulWait)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.addr+"/v1/kv/"+strings.TrimLeft(s.prefix, "/")+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if s.token != "" {
+		req.Header.Set("X-Consul-Token", s.token)
+	}
+
+	var pairs []struct {
+		Key   string  `json:"Key"`
+		Value *string `json:"Value"` // base64, null for empty values
+	}
+	header, err := doJSON(s.http, req, &pairs)
+	if err != nil && !errors.Is(err, errNotFound) {
+		return nil, 0, err
+	}
+
+	// Consul may reset the index (e.g. after a snapshot restore); starting
+	// over from 0 avoids blocking on an index that will never be reached
+	next, _ := strconv.ParseUint(header.Get("X-Consul-Index"), 10, 64)
+	if next < index {
+		next = 0
+	}
+
+	values := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		name := strings.TrimPrefix(strings.TrimPrefix(pair.Key, strings.TrimLeft(s.prefix, "/")), "/")
+		if name == "" || strings.HasSuffix(name, "/") {
+			continue // the prefix itself or a folder
+		}
+		value := ""
+		if pair.Value != nil {
+			decoded, err := base64.StdEncoding.DecodeString(*pair.Value)
+			if err != nil {
+				return nil, 0, err
+			}
+			value = string(decoded)
+		}
+		values[name] = strings.TrimSpace(value)
+	}
+	return values, next, nil
+}
@@ -0,0 +1,77 @@
+package remoteconfig
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// etcdSource polls the prefix through etcd's v3 JSON gateway
+type etcdSource struct {
+	http   *http.Client
+	addr   string
+	prefix string
+	token  string
+}
+
+func (s *etcdSource) Fetch(ctx context.Context, _ uint64) (map[string]string, uint64, error) {
+	body, err := json.Marshal(map[string]string{
+		"key":       base64.StdEncoding.EncodeToString([]byte(s.prefix)),
+		"range_end": base64.StdEncoding.EncodeToString(prefixEnd([]byte(s.prefix))),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.addr+"/v3/kv/range", bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", s.token)
+	}
+
+	var out struct {
+		KVs []struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		} `json:"kvs"`
+	}
+	if _, err := doJSON(s.http, req, &out); err != nil {
+		return nil, 0, err
+	}
+
+	values := make(map[string]string, len(out.KVs))
+	for _, kv := range out.KVs {
+		key, err := base64.StdEncoding.DecodeString(kv.Key)
+		if err != nil {
+			return nil, 0, err
+		}
+		value, err := base64.StdEncoding.DecodeString(kv.Value)
+		if err != nil {
+			return nil, 0, err
+		}
+		name := strings.TrimPrefix(strings.TrimPrefix(string(key), s.prefix), "/")
+		if name == "" {
+			continue
+		}
+		values[name] = strings.TrimSpace(string(value))
+	}
+	// Polled every interval; etcd's watch API needs a streaming gRPC connection
+	return values, 0, nil
+}
+
+// prefixEnd returns the smallest key greater than every key with prefix
+func prefixEnd(prefix []byte) []byte {
+	end := append([]byte(nil), prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return []byte{0} // empty prefix: the whole keyspace
+}
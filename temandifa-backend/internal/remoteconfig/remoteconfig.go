@@ -0,0 +1,229 @@
+// Package remoteconfig watches a key prefix in a remote KV store (Consul or
+// etcd) so a fleet of gateways picks up changed settings within seconds.
+// Keys below the prefix are named like their environment variables, e.g.
+// temandifa/config/RATE_LIMIT_REQUESTS.
+package remoteconfig
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/metrics"
+)
+
+// Options configures the remote store
+type Options struct {
+	Provider     string // none, consul or etcd
+	Addr         string // e.g. http://consul:8500 or http://etcd:2379
+	Prefix       string // key prefix, e.g. temandifa/config/
+	Token        string // Consul ACL token or etcd auth token
+	PollInterval time.Duration
+}
+
+// source fetches every key under the prefix. Sources that support blocking
+// reads return a non-zero index; passing it to the next call waits until the
+// data changes past it.
+type source interface {
+	Fetch(ctx context.Context, index uint64) (values map[string]string, next uint64, err error)
+}
+
+// ChangeHook receives the full set of accepted remote values after a change
+type ChangeHook func(values map[string]string)
+
+// Watcher keeps the latest accepted values of the watched keys. Keys that
+// accept reports false for (unknown keys, invalid values) are dropped.
+type Watcher struct {
+	provider string
+	source   source
+	accept   func(key, value string) bool
+	interval time.Duration
+	log      *zap.Logger
+
+	mu       sync.Mutex
+	values   map[string]string
+	rejected map[string]string // last rejected value per key, to log it once
+	hooks    []ChangeHook
+	stopChan chan struct{}
+}
+
+// NewWatcher returns a watcher for opts, or nil when the provider is none
+func NewWatcher(opts Options, accept func(key, value string) bool, log *zap.Logger) (*Watcher, error) {
+	client := &http.Client{Timeout: 70 * time.Second} // longer than the Consul blocking wait
+	addr := strings.TrimRight(opts.Addr, "/")
+
+	var src source
+	switch opts.Provider {
+	case "", "none":
+		return nil, nil
+	case "consul":
+		src = &consulSource{http: client, addr: addr, prefix: opts.Prefix, token: opts.Token}
+	case "etcd":
+		src = &etcdSource{http: client, addr: addr, prefix: opts.Prefix, token: opts.Token}
+	default:
+		return nil, fmt.Errorf("unknown remote config provider %q", opts.Provider)
+	}
+
+	return &Watcher{
+		provider: opts.Provider,
+		source:   src,
+		accept:   accept,
+		interval: opts.PollInterval,
+		log:      log,
+		values:   make(map[string]string),
+		rejected: make(map[string]string),
+		stopChan: make(chan struct{}),
+	}, nil
+}
+
+// Load fetches the current values once; call it before Start so the first
+// requests already see them
+func (w *Watcher) Load(ctx context.Context) error {
+	values, _, err := w.source.Fetch(ctx, 0)
+	if err != nil {
+		metrics.RemoteConfigFetches.WithLabelValues(w.provider, "error").Inc()
+		return err
+	}
+	metrics.RemoteConfigFetches.WithLabelValues(w.provider, "success").Inc()
+	w.apply(values)
+	return nil
+}
+
+// Values returns a copy of the accepted remote values
+func (w *Watcher) Values() map[string]string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	values := make(map[string]string, len(w.values))
+	for key, value := range w.values {
+		values[key] = value
+	}
+	return values
+}
+
+// OnChange registers fn to run whenever the accepted values change
+func (w *Watcher) OnChange(fn ChangeHook) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.hooks = append(w.hooks, fn)
+}
+
+// Start watches the store until Stop. Fetch errors keep the last values and
+// are retried after the poll interval.
+func (w *Watcher) Start() {
+	w.log.Info("Remote config watch started",
+		zap.String("provider", w.provider),
+		zap.Duration("poll_interval", w.interval),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-w.stopChan
+		cancel()
+	}()
+
+	go func() {
+		var index uint64
+		for {
+			values, next, err := w.source.Fetch(ctx, index)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				metrics.RemoteConfigFetches.WithLabelValues(w.provider, "error").Inc()
+				w.log.Warn("Failed to fetch remote config", zap.String("provider", w.provider), zap.Error(err))
+				index = 0
+			} else {
+				metrics.RemoteConfigFetches.WithLabelValues(w.provider, "success").Inc()
+				w.apply(values)
+				// Sources with blocking reads (Consul) return an index and
+				// wait for the next change themselves; the others are polled
+				if index = next; index > 0 {
+					continue
+				}
+			}
+
+			select {
+			case <-time.After(w.interval):
+			case <-w.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops watching
+func (w *Watcher) Stop() {
+	close(w.stopChan)
+}
+
+// apply filters fetched values and runs the hooks when the result changed
+func (w *Watcher) apply(fetched map[string]string) {
+	accepted := make(map[string]string, len(fetched))
+
+	w.mu.Lock()
+	for key, value := range fetched {
+		if !w.accept(key, value) {
+			if w.rejected[key] != value {
+				w.rejected[key] = value
+				w.log.Warn("Ignoring remote config key", zap.String("key", key), zap.String("value", value))
+			}
+			continue
+		}
+		delete(w.rejected, key)
+		accepted[key] = value
+	}
+
+	changed := len(accepted) != len(w.values)
+	for key, value := range accepted {
+		if old, ok := w.values[key]; !ok || old != value {
+			changed = true
+			w.log.Info("Remote config changed", zap.String("key", key), zap.String("value", value))
+		}
+	}
+	for key := range w.values {
+		if _, ok := accepted[key]; !ok {
+			w.log.Info("Remote config key removed, using local value", zap.String("key", key))
+		}
+	}
+	w.values = accepted
+	hooks := append([]ChangeHook(nil), w.hooks...)
+	w.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	metrics.RemoteConfigChanges.WithLabelValues(w.provider).Inc()
+	for _, fn := range hooks {
+		fn(w.Values())
+	}
+}
+
+// errNotFound means the prefix has no keys yet
+var errNotFound = errors.New("not found")
+
+func doJSON(client *http.Client, req *http.Request, out any) (http.Header, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return resp.Header, errNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.Header, json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/models"
+)
+
+// AnnouncementRepository persists announcements and their translations
+type AnnouncementRepository interface {
+	// List returns every announcement, newest start first
+	List(ctx context.Context) ([]models.Announcement, error)
+	// Unexpired returns the announcements not yet ended at t, including
+	// scheduled ones, ordered by start
+	Unexpired(ctx context.Context, t time.Time) ([]models.Announcement, error)
+	// Find returns nil when the announcement doesn't exist
+	Find(ctx context.Context, id uint) (*models.Announcement, error)
+	// Create inserts the announcement with its translations
+	Create(ctx context.Context, announcement *models.Announcement) error
+	// Update replaces the announcement and its translations, reporting
+	// whether it existed
+	Update(ctx context.Context, announcement *models.Announcement) (bool, error)
+	// Delete reports whether the announcement existed
+	Delete(ctx context.Context, id uint) (bool, error)
+}
+
+type announcementRepository struct {
+	db *gorm.DB
+}
+
+// NewAnnouncementRepository creates a new AnnouncementRepository
+func NewAnnouncementRepository(db *gorm.DB) AnnouncementRepository {
+	return &announcementRepository{db: db}
+}
+
+func preloadTranslations(db *gorm.DB) *gorm.DB {
+	return db.Preload("Translations", func(db *gorm.DB) *gorm.DB {
+		return db.Order("locale")
+	})
+}
+
+func (r *announcementRepository) List(ctx context.Context) ([]models.Announcement, error) {
+	var announcements []models.Announcement
+	err := preloadTranslations(database.Conn(ctx, r.db)).
+		Order("starts_at DESC, id DESC").
+		Find(&announcements).Error
+	return announcements, err
+}
+
+func (r *announcementRepository) Unexpired(ctx context.Context, t time.Time) ([]models.Announcement, error) {
+	var announcements []models.Announcement
+	err := preloadTranslations(database.Conn(ctx, r.db)).
+		Where("ends_at IS NULL OR ends_at > ?", t).
+		Order("starts_at, id").
+		Find(&announcements).Error
+	return announcements, err
+}
+
+func (r *announcementRepository) Find(ctx context.Context, id uint) (*models.Announcement, error) {
+	var announcement models.Announcement
+	if err := preloadTranslations(database.Conn(ctx, r.db)).Where("id = ?", id).First(&announcement).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &announcement, nil
+}
+
+func (r *announcementRepository) Create(ctx context.Context, announcement *models.Announcement) error {
+	return database.Conn(ctx, r.db).Create(announcement).Error
+}
+
+func (r *announcementRepository) Update(ctx context.Context, announcement *models.Announcement) (bool, error) {
+	var found bool
+	err := database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var existing models.Announcement
+		if err := tx.Where("id = ?", announcement.ID).First(&existing).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		found = true
+
+		announcement.CreatedAt = existing.CreatedAt
+		if err := tx.Omit("Translations").Save(announcement).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("announcement_id = ?", announcement.ID).Delete(&models.AnnouncementTranslation{}).Error; err != nil {
+			return err
+		}
+		for i := range announcement.Translations {
+			announcement.Translations[i].AnnouncementID = announcement.ID
+		}
+		return tx.Create(&announcement.Translations).Error
+	})
+	return found, err
+}
+
+func (r *announcementRepository) Delete(ctx context.Context, id uint) (bool, error) {
+	var deleted int64
+	err := database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// Explicit for SQLite, where ON DELETE CASCADE needs foreign_keys enabled
+		if err := tx.Where("announcement_id = ?", id).Delete(&models.AnnouncementTranslation{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id = ?", id).Delete(&models.Announcement{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	return deleted > 0, err
+}
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/models"
+)
+
+// APIKeyRepository persists partner API keys
+type APIKeyRepository interface {
+	List(ctx context.Context) ([]models.APIKey, error)
+	// Find returns nil when the key doesn't exist
+	Find(ctx context.Context, id uint64) (*models.APIKey, error)
+	// FindByHash returns nil when no key has the hash
+	FindByHash(ctx context.Context, keyHash string) (*models.APIKey, error)
+	Create(ctx context.Context, key *models.APIKey) error
+	Update(ctx context.Context, key *models.APIKey) error
+	// Delete reports false if the key didn't exist
+	Delete(ctx context.Context, id uint64) (bool, error)
+	// Touch records that the key was used at at
+	Touch(ctx context.Context, id uint64, at time.Time) error
+}
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository creates a new APIKeyRepository
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) List(ctx context.Context) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	err := database.Conn(ctx, r.db).Order("id").Find(&keys).Error
+	return keys, err
+}
+
+func (r *apiKeyRepository) Find(ctx context.Context, id uint64) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := database.Conn(ctx, r.db).First(&key, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepository) FindByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := database.Conn(ctx, r.db).Where("key_hash = ?", keyHash).First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	return database.Conn(ctx, r.db).Create(key).Error
+}
+
+func (r *apiKeyRepository) Update(ctx context.Context, key *models.APIKey) error {
+	return database.Conn(ctx, r.db).Save(key).Error
+}
+
+func (r *apiKeyRepository) Delete(ctx context.Context, id uint64) (bool, error) {
+	result := database.Conn(ctx, r.db).Delete(&models.APIKey{}, id)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *apiKeyRepository) Touch(ctx context.Context, id uint64, at time.Time) error {
+	return database.Conn(ctx, r.db).Model(&models.APIKey{}).Where("id = ?", id).
+		UpdateColumn("last_used_at", at).Error
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/models"
+)
+
+// AppVersionRepository persists the per-platform app version manifests
+type AppVersionRepository interface {
+	List(ctx context.Context) ([]models.AppVersion, error)
+	// Find returns nil when the platform has no manifest
+	Find(ctx context.Context, platform string) (*models.AppVersion, error)
+	// Save creates or replaces the platform's manifest
+	Save(ctx context.Context, version *models.AppVersion) error
+	// Delete reports whether the manifest existed
+	Delete(ctx context.Context, platform string) (bool, error)
+}
+
+type appVersionRepository struct {
+	db *gorm.DB
+}
+
+// NewAppVersionRepository creates a new AppVersionRepository
+func NewAppVersionRepository(db *gorm.DB) AppVersionRepository {
+	return &appVersionRepository{db: db}
+}
+
+func (r *appVersionRepository) List(ctx context.Context) ([]models.AppVersion, error) {
+	var versions []models.AppVersion
+	err := database.Conn(ctx, r.db).Order("platform").Find(&versions).Error
+	return versions, err
+}
+
+func (r *appVersionRepository) Find(ctx context.Context, platform string) (*models.AppVersion, error) {
+	var version models.AppVersion
+	if err := database.Conn(ctx, r.db).Where("platform = ?", platform).First(&version).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &version, nil
+}
+
+func (r *appVersionRepository) Save(ctx context.Context, version *models.AppVersion) error {
+	return database.Conn(ctx, r.db).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "platform"}},
+			DoUpdates: clause.AssignmentColumns([]string{"min_version", "latest_version", "store_url", "changelog", "updated_by", "updated_at"}),
+		}).
+		Create(version).Error
+}
+
+func (r *appVersionRepository) Delete(ctx context.Context, platform string) (bool, error) {
+	result := database.Conn(ctx, r.db).Where("platform = ?", platform).Delete(&models.AppVersion{})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/models"
+)
+
+// AuditRepository persists the admin audit log
+type AuditRepository interface {
+	// Create joins the transaction bound to ctx, if any
+	Create(ctx context.Context, entry *models.AuditLog) error
+	// Find returns nil when the entry doesn't exist
+	Find(ctx context.Context, id uint) (*models.AuditLog, error)
+}
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository creates a new AuditRepository
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) Create(ctx context.Context, entry *models.AuditLog) error {
+	return database.Conn(ctx, r.db).Create(entry).Error
+}
+
+func (r *auditRepository) Find(ctx context.Context, id uint) (*models.AuditLog, error) {
+	var entry models.AuditLog
+	if err := database.Conn(ctx, r.db).First(&entry, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
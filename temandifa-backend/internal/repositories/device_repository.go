@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/models"
+)
+
+// DeviceRepository persists the users' devices and their push tokens
+type DeviceRepository interface {
+	// Save registers the device for its user or updates it, taking its push
+	// token over from whichever device registered it before
+	Save(ctx context.Context, device *models.Device) error
+	// Find returns nil when the user has no such device
+	Find(ctx context.Context, userID uint, deviceID string) (*models.Device, error)
+	// FindByPushToken returns nil when no device of the user has the token
+	FindByPushToken(ctx context.Context, userID uint, token string) (*models.Device, error)
+	// ListPushTokens returns the push tokens of the user's devices
+	ListPushTokens(ctx context.Context, userID uint) ([]string, error)
+	// Delete reports whether the user had registered the device
+	Delete(ctx context.Context, userID uint, deviceID string) (bool, error)
+	// ClearPushToken removes the token from the user's device, reporting
+	// whether it had it
+	ClearPushToken(ctx context.Context, userID uint, token string) (bool, error)
+	// ClearPushTokens removes tokens regardless of their user
+	ClearPushTokens(ctx context.Context, tokens []string) error
+}
+
+type deviceRepository struct {
+	db *gorm.DB
+}
+
+// NewDeviceRepository creates a new DeviceRepository
+func NewDeviceRepository(db *gorm.DB) DeviceRepository {
+	return &deviceRepository{db: db}
+}
+
+func (r *deviceRepository) Save(ctx context.Context, device *models.Device) error {
+	return database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if device.PushToken != nil {
+			err := tx.Model(&models.Device{}).
+				Where("push_token = ? AND NOT (user_id = ? AND device_id = ?)", *device.PushToken, device.UserID, device.DeviceID).
+				Update("push_token", nil).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "device_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"platform", "push_token", "app_version", "last_seen_at", "updated_at"}),
+		}).Create(device).Error
+	})
+}
+
+// firstDevice returns nil when query matches no device
+func firstDevice(query *gorm.DB) (*models.Device, error) {
+	var device models.Device
+	if err := query.First(&device).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &device, nil
+}
+
+func (r *deviceRepository) Find(ctx context.Context, userID uint, deviceID string) (*models.Device, error) {
+	return firstDevice(database.Conn(ctx, r.db).Where("user_id = ? AND device_id = ?", userID, deviceID))
+}
+
+func (r *deviceRepository) FindByPushToken(ctx context.Context, userID uint, token string) (*models.Device, error) {
+	return firstDevice(database.Conn(ctx, r.db).Where("user_id = ? AND push_token = ?", userID, token))
+}
+
+func (r *deviceRepository) ListPushTokens(ctx context.Context, userID uint) ([]string, error) {
+	var tokens []string
+	err := database.Conn(ctx, r.db).Model(&models.Device{}).
+		Where("user_id = ? AND push_token IS NOT NULL", userID).
+		Order("id").
+		Pluck("push_token", &tokens).Error
+	return tokens, err
+}
+
+func (r *deviceRepository) Delete(ctx context.Context, userID uint, deviceID string) (bool, error) {
+	result := database.Conn(ctx, r.db).Where("user_id = ? AND device_id = ?", userID, deviceID).Delete(&models.Device{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *deviceRepository) ClearPushToken(ctx context.Context, userID uint, token string) (bool, error) {
+	result := database.Conn(ctx, r.db).Model(&models.Device{}).
+		Where("user_id = ? AND push_token = ?", userID, token).
+		Update("push_token", nil)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *deviceRepository) ClearPushTokens(ctx context.Context, tokens []string) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	return database.Conn(ctx, r.db).Model(&models.Device{}).
+		Where("push_token IN ?", tokens).
+		Update("push_token", nil).Error
+}
@@ -0,0 +1,151 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/models"
+)
+
+// EmergencyRepository persists the users' emergency contacts and the alerts
+// sent to them
+type EmergencyRepository interface {
+	// ListContacts returns the user's contacts, the primary one first
+	ListContacts(ctx context.Context, userID uint) ([]models.EmergencyContact, error)
+	// ListConsentingContacts returns the user's contacts who agreed to
+	// receive alerts
+	ListConsentingContacts(ctx context.Context, userID uint) ([]models.EmergencyContact, error)
+	CountContacts(ctx context.Context, userID uint) (int64, error)
+	// FindContact returns nil when the user has no such contact
+	FindContact(ctx context.Context, userID, contactID uint) (*models.EmergencyContact, error)
+	// FindContactByConsentToken returns nil when no contact was sent the
+	// token
+	FindContactByConsentToken(ctx context.Context, tokenHash string) (*models.EmergencyContact, error)
+	// SaveContact creates or updates the contact; a primary contact stops
+	// the user's other contacts from being primary
+	SaveContact(ctx context.Context, contact *models.EmergencyContact) error
+	// CountConsentRequestsSince counts the user's contacts, deleted ones
+	// included, last asked for consent since the given time
+	CountConsentRequestsSince(ctx context.Context, userID uint, since time.Time) (int64, error)
+	// DeleteContact reports whether the user had the contact
+	DeleteContact(ctx context.Context, userID, contactID uint) (bool, error)
+	CreateAlert(ctx context.Context, alert *models.EmergencyAlert) error
+	// SetContactsNotified records how many contacts the alert reached
+	SetContactsNotified(ctx context.Context, alertID uint64, count int) error
+	// CountAlertsSince counts the alerts the user sent since the given time.
+	// In a transaction it locks the user's row, so concurrent alerts of the
+	// user are counted one after the other.
+	CountAlertsSince(ctx context.Context, userID uint, since time.Time) (int64, error)
+}
+
+type emergencyRepository struct {
+	db *gorm.DB
+}
+
+// NewEmergencyRepository creates a new EmergencyRepository
+func NewEmergencyRepository(db *gorm.DB) EmergencyRepository {
+	return &emergencyRepository{db: db}
+}
+
+func (r *emergencyRepository) ListContacts(ctx context.Context, userID uint) ([]models.EmergencyContact, error) {
+	var contacts []models.EmergencyContact
+	err := database.Conn(ctx, r.db).
+		Where("user_id = ?", userID).
+		Order("is_primary DESC, id").
+		Find(&contacts).Error
+	return contacts, err
+}
+
+func (r *emergencyRepository) ListConsentingContacts(ctx context.Context, userID uint) ([]models.EmergencyContact, error) {
+	var contacts []models.EmergencyContact
+	err := database.Conn(ctx, r.db).
+		Where("user_id = ? AND consent_status = ?", userID, models.ConsentAccepted).
+		Order("is_primary DESC, id").
+		Find(&contacts).Error
+	return contacts, err
+}
+
+func (r *emergencyRepository) CountContacts(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := database.Conn(ctx, r.db).Model(&models.EmergencyContact{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// firstContact returns nil when query matches no contact
+func firstContact(query *gorm.DB) (*models.EmergencyContact, error) {
+	var contact models.EmergencyContact
+	if err := query.First(&contact).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &contact, nil
+}
+
+func (r *emergencyRepository) FindContact(ctx context.Context, userID, contactID uint) (*models.EmergencyContact, error) {
+	return firstContact(database.Conn(ctx, r.db).Where("user_id = ? AND id = ?", userID, contactID))
+}
+
+func (r *emergencyRepository) FindContactByConsentToken(ctx context.Context, tokenHash string) (*models.EmergencyContact, error) {
+	return firstContact(database.Conn(ctx, r.db).Where("consent_token_hash = ?", tokenHash))
+}
+
+func (r *emergencyRepository) SaveContact(ctx context.Context, contact *models.EmergencyContact) error {
+	return database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(contact).Error; err != nil {
+			return err
+		}
+		if !contact.IsPrimary {
+			return nil
+		}
+		return tx.Model(&models.EmergencyContact{}).
+			Where("user_id = ? AND id <> ? AND is_primary = ?", contact.UserID, contact.ID, true).
+			Update("is_primary", false).Error
+	})
+}
+
+func (r *emergencyRepository) CountConsentRequestsSince(ctx context.Context, userID uint, since time.Time) (int64, error) {
+	var count int64
+	err := database.Conn(ctx, r.db).Unscoped().Model(&models.EmergencyContact{}).
+		Where("user_id = ? AND consent_requested_at >= ?", userID, since).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *emergencyRepository) DeleteContact(ctx context.Context, userID, contactID uint) (bool, error) {
+	result := database.Conn(ctx, r.db).Where("user_id = ? AND id = ?", userID, contactID).Delete(&models.EmergencyContact{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *emergencyRepository) CreateAlert(ctx context.Context, alert *models.EmergencyAlert) error {
+	return database.Conn(ctx, r.db).Create(alert).Error
+}
+
+func (r *emergencyRepository) SetContactsNotified(ctx context.Context, alertID uint64, count int) error {
+	return database.Conn(ctx, r.db).Model(&models.EmergencyAlert{}).
+		Where("id = ?", alertID).
+		Update("contacts_notified", count).Error
+}
+
+func (r *emergencyRepository) CountAlertsSince(ctx context.Context, userID uint, since time.Time) (int64, error) {
+	var user models.User
+	err := database.Conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("id = ?", userID).
+		Take(&user).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err = database.Conn(ctx, r.db).Model(&models.EmergencyAlert{}).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/models"
+)
+
+// FeatureFlagRepository persists feature flags and their overrides
+type FeatureFlagRepository interface {
+	// List returns every flag with its overrides, ordered by name
+	List(ctx context.Context) ([]models.FeatureFlag, error)
+	// Find returns nil when the flag doesn't exist
+	Find(ctx context.Context, name string) (*models.FeatureFlag, error)
+	// Save creates the flag or updates its description, switch and rollout
+	Save(ctx context.Context, flag *models.FeatureFlag) error
+	// Delete removes the flag and its overrides, reporting whether it existed
+	Delete(ctx context.Context, name string) (bool, error)
+	// SaveOverride creates or updates an override
+	SaveOverride(ctx context.Context, override *models.FeatureFlagOverride) error
+	// DeleteOverride reports whether the override existed
+	DeleteOverride(ctx context.Context, name, subjectType, subject string) (bool, error)
+}
+
+type featureFlagRepository struct {
+	db *gorm.DB
+}
+
+// NewFeatureFlagRepository creates a new FeatureFlagRepository
+func NewFeatureFlagRepository(db *gorm.DB) FeatureFlagRepository {
+	return &featureFlagRepository{db: db}
+}
+
+func (r *featureFlagRepository) List(ctx context.Context) ([]models.FeatureFlag, error) {
+	var flags []models.FeatureFlag
+	err := database.Conn(ctx, r.db).
+		Preload("Overrides", func(db *gorm.DB) *gorm.DB {
+			return db.Order("subject_type, subject")
+		}).
+		Order("name").
+		Find(&flags).Error
+	return flags, err
+}
+
+func (r *featureFlagRepository) Find(ctx context.Context, name string) (*models.FeatureFlag, error) {
+	var flag models.FeatureFlag
+	err := database.Conn(ctx, r.db).Preload("Overrides").Where("name = ?", name).First(&flag).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &flag, nil
+}
+
+func (r *featureFlagRepository) Save(ctx context.Context, flag *models.FeatureFlag) error {
+	return database.Conn(ctx, r.db).
+		Omit("Overrides").
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"description", "enabled", "rollout_percentage", "updated_at"}),
+		}).
+		Create(flag).Error
+}
+
+func (r *featureFlagRepository) Delete(ctx context.Context, name string) (bool, error) {
+	var deleted int64
+	err := database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// Explicit for SQLite, where ON DELETE CASCADE needs foreign_keys enabled
+		if err := tx.Where("flag_name = ?", name).Delete(&models.FeatureFlagOverride{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("name = ?", name).Delete(&models.FeatureFlag{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	return deleted > 0, err
+}
+
+func (r *featureFlagRepository) SaveOverride(ctx context.Context, override *models.FeatureFlagOverride) error {
+	return database.Conn(ctx, r.db).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "flag_name"}, {Name: "subject_type"}, {Name: "subject"}},
+			DoUpdates: clause.AssignmentColumns([]string{"enabled"}),
+		}).
+		Create(override).Error
+}
+
+func (r *featureFlagRepository) DeleteOverride(ctx context.Context, name, subjectType, subject string) (bool, error) {
+	result := database.Conn(ctx, r.db).
+		Where("flag_name = ? AND subject_type = ? AND subject = ?", name, subjectType, subject).
+		Delete(&models.FeatureFlagOverride{})
+	return result.RowsAffected > 0, result.Error
+}
@@ -1,16 +1,44 @@
 package repositories
 
 import (
-	"temandifa-backend/internal/models"
+	"context"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/models"
 )
 
+// HistoryMatch is a history entry returned by full-text search
+type HistoryMatch struct {
+	models.History
+	// Rank orders matches by relevance (higher is better); always 0 on SQLite
+	Rank float64 `json:"rank" gorm:"column:rank"`
+}
+
+// historyInsertBatchSize keeps each INSERT well under Postgres' bind parameter limit
+const historyInsertBatchSize = 200
+
 type HistoryRepository interface {
-	Create(history *models.History) error
-	FindUserHistory(userID uint, limit, offset int) ([]models.History, int64, error)
-	DeleteByID(userID uint, historyID string) (int64, error)
-	DeleteAllByUserID(userID uint) (int64, error)
+	Create(ctx context.Context, history *models.History) error
+	// CreateBatch inserts histories in multi-row statements and returns how many
+	// were inserted. Entries whose (UserID, ClientID) already exists are skipped
+	// (and keep a zero ID), so retried offline syncs don't duplicate entries.
+	CreateBatch(ctx context.Context, histories []models.History) (int64, error)
+	// List returns the entries selected by spec and the total number of matches
+	List(ctx context.Context, spec *Spec) ([]models.History, int64, error)
+	// Search returns entries within spec whose text matches query (web search
+	// syntax: words, "quoted phrases", -excluded), most relevant first unless
+	// spec sets a sort order, and the total number of matches
+	Search(ctx context.Context, query string, spec *Spec) ([]HistoryMatch, int64, error)
+	// CountByFeature returns the number of a user's entries per feature type
+	CountByFeature(ctx context.Context, userID uint) (map[models.FeatureType]int64, error)
+	DeleteByID(ctx context.Context, userID uint, historyID string) (int64, error)
+	DeleteAllByUserID(ctx context.Context, userID uint) (int64, error)
+	// Update saves an entry owned by history.UserID if history.Version still
+	// matches the stored version; otherwise it returns a CONFLICT error
+	Update(ctx context.Context, history *models.History) error
 }
 
 type historyRepository struct {
@@ -21,33 +49,105 @@ func NewHistoryRepository(db *gorm.DB) HistoryRepository {
 	return &historyRepository{db: db}
 }
 
-func (r *historyRepository) Create(history *models.History) error {
-	return r.db.Create(history).Error
+func (r *historyRepository) Create(ctx context.Context, history *models.History) error {
+	return database.Conn(ctx, r.db).Create(history).Error
 }
 
-func (r *historyRepository) FindUserHistory(userID uint, limit, offset int) ([]models.History, int64, error) {
-	var histories []models.History
-	var total int64
+func (r *historyRepository) CreateBatch(ctx context.Context, histories []models.History) (int64, error) {
+	if len(histories) == 0 {
+		return 0, nil
+	}
+	result := database.Conn(ctx, r.db).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "client_id"}},
+			DoNothing: true,
+		}).
+		CreateInBatches(&histories, historyInsertBatchSize)
+	return result.RowsAffected, result.Error
+}
+
+func (r *historyRepository) List(ctx context.Context, spec *Spec) ([]models.History, int64, error) {
+	return findBySpec[models.History](database.Conn(ctx, r.db), spec)
+}
+
+func (r *historyRepository) CountByFeature(ctx context.Context, userID uint) (map[models.FeatureType]int64, error) {
+	var rows []struct {
+		FeatureType models.FeatureType
+		Count       int64
+	}
+	err := database.Conn(ctx, r.db).Model(&models.History{}).
+		Select("feature_type, COUNT(*) AS count").
+		Where("user_id = ?", userID).
+		Group("feature_type").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[models.FeatureType]int64, len(rows))
+	for _, row := range rows {
+		counts[row.FeatureType] = row.Count
+	}
+	return counts, nil
+}
+
+// historySearchQuery parses user input leniently: websearch_to_tsquery never
+// fails on unbalanced quotes or stray operators
+const historySearchQuery = "websearch_to_tsquery('simple', ?)"
 
-	// Count total records for this user
-	r.db.Model(&models.History{}).Where("user_id = ?", userID).Count(&total)
+func (r *historyRepository) Search(ctx context.Context, query string, spec *Spec) ([]HistoryMatch, int64, error) {
+	db := database.Conn(ctx, r.db)
+	if db.Dialector.Name() != "postgres" {
+		// No search_vector column; match substrings instead, newest first
+		spec.Search(query, "result_text", "input_source")
+		if !spec.Sorted() {
+			spec.OrderBy("created_at", true)
+		}
+		return searchHistories(db, spec, "histories.*, 0 AS rank")
+	}
 
-	// Fetch paginated results
-	result := r.db.Where("user_id = ?", userID).
-		Order("created_at DESC").
-		Limit(limit).
-		Offset(offset).
-		Find(&histories)
+	spec.Where("search_vector @@ "+historySearchQuery, query)
+	if !spec.Sorted() {
+		spec.OrderBy("rank", true).OrderBy("created_at", true)
+	}
+	return searchHistories(db, spec, "histories.*, ts_rank_cd(search_vector, "+historySearchQuery+") AS rank", query)
+}
+
+// searchHistories runs spec against histories, selecting columns (which must
+// produce a rank) for the page and counting all matches
+func searchHistories(db *gorm.DB, spec *Spec, columns string, args ...any) ([]HistoryMatch, int64, error) {
+	var matches []HistoryMatch
+	var total int64
 
-	return histories, total, result.Error
+	if err := db.Model(&models.History{}).Scopes(spec.filter).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := db.Model(&models.History{}).Select(columns, args...).Scopes(spec.apply).Find(&matches).Error; err != nil {
+		return nil, 0, err
+	}
+	return matches, total, nil
 }
 
-func (r *historyRepository) DeleteByID(userID uint, historyID string) (int64, error) {
-	result := r.db.Where("id = ? AND user_id = ?", historyID, userID).Delete(&models.History{})
+func (r *historyRepository) DeleteByID(ctx context.Context, userID uint, historyID string) (int64, error) {
+	result := database.Conn(ctx, r.db).Where("id = ? AND user_id = ?", historyID, userID).Delete(&models.History{})
 	return result.RowsAffected, result.Error
 }
 
-func (r *historyRepository) DeleteAllByUserID(userID uint) (int64, error) {
-	result := r.db.Where("user_id = ?", userID).Delete(&models.History{})
+func (r *historyRepository) DeleteAllByUserID(ctx context.Context, userID uint) (int64, error) {
+	result := database.Conn(ctx, r.db).Where("user_id = ?", userID).Delete(&models.History{})
 	return result.RowsAffected, result.Error
 }
+
+func (r *historyRepository) Update(ctx context.Context, history *models.History) error {
+	result := database.Conn(ctx, r.db).Model(history).
+		Where("user_id = ?", history.UserID).
+		Select("feature_type", "input_source", "result_text").
+		Updates(history)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return versionConflict(ctx, r.db, &models.History{}, "History entry", "id = ? AND user_id = ?", history.ID, history.UserID)
+	}
+	return nil
+}
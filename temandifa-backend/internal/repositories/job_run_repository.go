@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/models"
+)
+
+// JobRunRepository persists manually triggered job runs
+type JobRunRepository interface {
+	Create(ctx context.Context, run *models.JobRun) error
+	// Finish stores the run's outcome
+	Finish(ctx context.Context, run *models.JobRun) error
+	// Find returns nil when the run doesn't exist
+	Find(ctx context.Context, id uint64) (*models.JobRun, error)
+}
+
+type jobRunRepository struct {
+	db *gorm.DB
+}
+
+// NewJobRunRepository creates a new JobRunRepository
+func NewJobRunRepository(db *gorm.DB) JobRunRepository {
+	return &jobRunRepository{db: db}
+}
+
+func (r *jobRunRepository) Create(ctx context.Context, run *models.JobRun) error {
+	return database.Conn(ctx, r.db).Create(run).Error
+}
+
+func (r *jobRunRepository) Finish(ctx context.Context, run *models.JobRun) error {
+	return database.Conn(ctx, r.db).Model(run).
+		Select("status", "result", "error", "finished_at").
+		Updates(run).Error
+}
+
+func (r *jobRunRepository) Find(ctx context.Context, id uint64) (*models.JobRun, error) {
+	var run models.JobRun
+	err := database.Conn(ctx, r.db).First(&run, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &run, nil
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/models"
+)
+
+// KillSwitchRepository persists the AI operation kill switches
+type KillSwitchRepository interface {
+	List(ctx context.Context) ([]models.KillSwitch, error)
+	// Find returns nil when the operation isn't switched off
+	Find(ctx context.Context, operation string) (*models.KillSwitch, error)
+	// Save creates the switch or updates its reason
+	Save(ctx context.Context, sw *models.KillSwitch) error
+	// Delete reports whether the switch existed
+	Delete(ctx context.Context, operation string) (bool, error)
+}
+
+type killSwitchRepository struct {
+	db *gorm.DB
+}
+
+// NewKillSwitchRepository creates a new KillSwitchRepository
+func NewKillSwitchRepository(db *gorm.DB) KillSwitchRepository {
+	return &killSwitchRepository{db: db}
+}
+
+func (r *killSwitchRepository) List(ctx context.Context) ([]models.KillSwitch, error) {
+	var switches []models.KillSwitch
+	err := database.Conn(ctx, r.db).Order("operation").Find(&switches).Error
+	return switches, err
+}
+
+func (r *killSwitchRepository) Find(ctx context.Context, operation string) (*models.KillSwitch, error) {
+	var sw models.KillSwitch
+	if err := database.Conn(ctx, r.db).Where("operation = ?", operation).First(&sw).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &sw, nil
+}
+
+func (r *killSwitchRepository) Save(ctx context.Context, sw *models.KillSwitch) error {
+	return database.Conn(ctx, r.db).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "operation"}},
+			DoUpdates: clause.AssignmentColumns([]string{"reason"}),
+		}).
+		Create(sw).Error
+}
+
+func (r *killSwitchRepository) Delete(ctx context.Context, operation string) (bool, error) {
+	result := database.Conn(ctx, r.db).Where("operation = ?", operation).Delete(&models.KillSwitch{})
+	return result.RowsAffected > 0, result.Error
+}
@@ -5,4 +5,27 @@ import "go.uber.org/fx"
 var Module = fx.Options(
 	fx.Provide(NewUserRepository),
 	fx.Provide(NewHistoryRepository),
+	fx.Provide(NewFeatureFlagRepository),
+	fx.Provide(NewRateLimitOverrideRepository),
+	fx.Provide(NewSessionRepository),
+	fx.Provide(NewUsageRepository),
+	fx.Provide(NewAnnouncementRepository),
+	fx.Provide(NewRoleRepository),
+	fx.Provide(NewAuditRepository),
+	fx.Provide(NewKillSwitchRepository),
+	fx.Provide(NewJobRunRepository),
+	fx.Provide(NewPlanRepository),
+	fx.Provide(NewDeviceRepository),
+	fx.Provide(NewAppVersionRepository),
+	fx.Provide(NewNotificationRepository),
+	fx.Provide(NewUserSettingRepository),
+	fx.Provide(NewUserPreferencesRepository),
+	fx.Provide(NewEmergencyRepository),
+	fx.Provide(NewTranslationRepository),
+	fx.Provide(NewWebhookRepository),
+	fx.Provide(NewUptimeRepository),
+	fx.Provide(NewUsageReportRepository),
+	fx.Provide(NewVerificationTokenRepository),
+	fx.Provide(NewUserIdentityRepository),
+	fx.Provide(NewAPIKeyRepository),
 )
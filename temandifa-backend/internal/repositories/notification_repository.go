@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/models"
+)
+
+// NotificationRepository persists the in-app inbox and its read state
+type NotificationRepository interface {
+	// Create stores a notification; one whose DedupKey is already stored is
+	// skipped
+	Create(ctx context.Context, notification *models.Notification) error
+	// List returns a page of the user's inbox, newest first, with ReadAt set,
+	// and the number of notifications matching
+	List(ctx context.Context, userID uint, unreadOnly bool, limit, offset int) ([]models.Notification, int64, error)
+	// CountUnread returns how many notifications in the user's inbox are unread
+	CountUnread(ctx context.Context, userID uint) (int64, error)
+	// MarkRead marks a notification of the user's inbox read, reporting
+	// false when the inbox has no such notification
+	MarkRead(ctx context.Context, userID uint, id uint64) (bool, error)
+	// MarkAllRead marks the user's whole inbox read and returns how many
+	// notifications were unread
+	MarkAllRead(ctx context.Context, userID uint) (int64, error)
+	// DeleteBefore deletes the notifications created before cutoff
+	DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository creates a new NotificationRepository
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+// inboxOf restricts a notifications query to the user's inbox: their own
+// notifications and the broadcasts since they signed up
+func inboxOf(userID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("(notifications.user_id = ? OR (notifications.user_id IS NULL AND notifications.created_at >= (SELECT created_at FROM users WHERE id = ?)))", userID, userID)
+	}
+}
+
+// unreadBy restricts a notifications query to those the user hasn't read
+func unreadBy(userID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("NOT EXISTS (SELECT 1 FROM notification_reads WHERE notification_reads.notification_id = notifications.id AND notification_reads.user_id = ?)", userID)
+	}
+}
+
+func (r *notificationRepository) Create(ctx context.Context, notification *models.Notification) error {
+	db := database.Conn(ctx, r.db)
+	if notification.DedupKey != nil {
+		db = db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "dedup_key"}},
+			DoNothing: true,
+		})
+	}
+	return db.Create(notification).Error
+}
+
+func (r *notificationRepository) List(ctx context.Context, userID uint, unreadOnly bool, limit, offset int) ([]models.Notification, int64, error) {
+	query := database.Conn(ctx, r.db).Model(&models.Notification{}).Scopes(inboxOf(userID))
+	if unreadOnly {
+		query = query.Scopes(unreadBy(userID))
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var notifications []models.Notification
+	err := query.
+		Select("notifications.*, notification_reads.read_at").
+		Joins("LEFT JOIN notification_reads ON notification_reads.notification_id = notifications.id AND notification_reads.user_id = ?", userID).
+		Order("notifications.id DESC").
+		Limit(limit).Offset(offset).
+		Find(&notifications).Error
+	return notifications, total, err
+}
+
+func (r *notificationRepository) CountUnread(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := database.Conn(ctx, r.db).Model(&models.Notification{}).
+		Scopes(inboxOf(userID), unreadBy(userID)).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *notificationRepository) MarkRead(ctx context.Context, userID uint, id uint64) (bool, error) {
+	var count int64
+	err := database.Conn(ctx, r.db).Model(&models.Notification{}).
+		Scopes(inboxOf(userID)).
+		Where("notifications.id = ?", id).
+		Count(&count).Error
+	if err != nil || count == 0 {
+		return false, err
+	}
+	read := models.NotificationRead{NotificationID: id, UserID: userID, ReadAt: time.Now()}
+	err = database.Conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(&read).Error
+	return err == nil, err
+}
+
+func (r *notificationRepository) MarkAllRead(ctx context.Context, userID uint) (int64, error) {
+	unread := database.Conn(ctx, r.db).Model(&models.Notification{}).
+		Select("notifications.id, ?, ?", userID, time.Now()).
+		Scopes(inboxOf(userID), unreadBy(userID))
+	result := database.Conn(ctx, r.db).Exec("INSERT INTO notification_reads (notification_id, user_id, read_at) ?", unread)
+	return result.RowsAffected, result.Error
+}
+
+func (r *notificationRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := database.Conn(ctx, r.db).Where("created_at < ?", cutoff).Delete(&models.Notification{})
+	return result.RowsAffected, result.Error
+}
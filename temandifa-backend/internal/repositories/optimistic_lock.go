@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+
+	"temandifa-backend/internal/database"
+	apperrors "temandifa-backend/internal/errors"
+)
+
+// versionConflict explains an optimistic-locked update that matched no rows:
+// the row is either gone (NOT_FOUND) or was updated concurrently (CONFLICT
+// with the version now stored). The version is read from the primary, since
+// a lagging replica could report the stale one.
+func versionConflict(ctx context.Context, db *gorm.DB, model any, resource string, query string, args ...any) error {
+	var current struct{ Version uint }
+	err := database.Conn(ctx, db).Clauses(dbresolver.Write).Model(model).
+		Select("version").Where(query, args...).Take(&current).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperrors.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return apperrors.VersionConflict(resource, current.Version)
+}
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/models"
+)
+
+// PlanRepository persists the subscription plans
+type PlanRepository interface {
+	List(ctx context.Context) ([]models.Plan, error)
+	// Find returns nil when the plan doesn't exist
+	Find(ctx context.Context, name string) (*models.Plan, error)
+	// Update saves the plan's description and allowances, reporting whether
+	// it exists
+	Update(ctx context.Context, plan *models.Plan) (bool, error)
+}
+
+type planRepository struct {
+	db *gorm.DB
+}
+
+// NewPlanRepository creates a new PlanRepository
+func NewPlanRepository(db *gorm.DB) PlanRepository {
+	return &planRepository{db: db}
+}
+
+func (r *planRepository) List(ctx context.Context) ([]models.Plan, error) {
+	var plans []models.Plan
+	err := database.Conn(ctx, r.db).Order("name").Find(&plans).Error
+	return plans, err
+}
+
+func (r *planRepository) Find(ctx context.Context, name string) (*models.Plan, error) {
+	var plan models.Plan
+	if err := database.Conn(ctx, r.db).Where("name = ?", name).First(&plan).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (r *planRepository) Update(ctx context.Context, plan *models.Plan) (bool, error) {
+	result := database.Conn(ctx, r.db).Model(&models.Plan{}).
+		Where("name = ?", plan.Name).
+		Updates(map[string]any{
+			"description":           plan.Description,
+			"daily_ai_quota":        plan.DailyAIQuota,
+			"rate_limit_multiplier": plan.RateLimitMultiplier,
+			"cache_ttl_multiplier":  plan.CacheTTLMultiplier,
+		})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/models"
+)
+
+// RateLimitOverrideRepository persists per-user rate limit overrides
+type RateLimitOverrideRepository interface {
+	// List returns every override, expired ones included, ordered by user
+	List(ctx context.Context) ([]models.RateLimitOverride, error)
+	// Find returns nil when the user has no override
+	Find(ctx context.Context, userID uint) (*models.RateLimitOverride, error)
+	// Save creates or replaces the user's override
+	Save(ctx context.Context, override *models.RateLimitOverride) error
+	// Delete reports whether the user had an override
+	Delete(ctx context.Context, userID uint) (bool, error)
+}
+
+type rateLimitOverrideRepository struct {
+	db *gorm.DB
+}
+
+// NewRateLimitOverrideRepository creates a new RateLimitOverrideRepository
+func NewRateLimitOverrideRepository(db *gorm.DB) RateLimitOverrideRepository {
+	return &rateLimitOverrideRepository{db: db}
+}
+
+func (r *rateLimitOverrideRepository) List(ctx context.Context) ([]models.RateLimitOverride, error) {
+	var overrides []models.RateLimitOverride
+	err := database.Conn(ctx, r.db).Order("user_id").Find(&overrides).Error
+	return overrides, err
+}
+
+func (r *rateLimitOverrideRepository) Find(ctx context.Context, userID uint) (*models.RateLimitOverride, error) {
+	var override models.RateLimitOverride
+	if err := database.Conn(ctx, r.db).Where("user_id = ?", userID).First(&override).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &override, nil
+}
+
+func (r *rateLimitOverrideRepository) Save(ctx context.Context, override *models.RateLimitOverride) error {
+	return database.Conn(ctx, r.db).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"multiplier", "exempt", "reason", "expires_at", "updated_at"}),
+		}).
+		Create(override).Error
+}
+
+func (r *rateLimitOverrideRepository) Delete(ctx context.Context, userID uint) (bool, error) {
+	result := database.Conn(ctx, r.db).Where("user_id = ?", userID).Delete(&models.RateLimitOverride{})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/models"
+)
+
+// RoleRepository persists roles and their permissions. Every method joins
+// the transaction bound to ctx, if any.
+type RoleRepository interface {
+	// List returns every role with its permissions, ordered by name
+	List(ctx context.Context) ([]models.Role, error)
+	// Find returns nil when the role doesn't exist
+	Find(ctx context.Context, name string) (*models.Role, error)
+	Create(ctx context.Context, role *models.Role) error
+	// UpdateDescription reports whether the role exists
+	UpdateDescription(ctx context.Context, name, description string) (bool, error)
+	// AddPermissions attaches permissions, ignoring ones already attached
+	AddPermissions(ctx context.Context, name string, permissions []string) error
+	// RemovePermission reports whether the permission was attached
+	RemovePermission(ctx context.Context, name, permission string) (bool, error)
+	// BumpVersion increments the role's version after its permissions changed
+	BumpVersion(ctx context.Context, name string) error
+	// Delete removes the role and its permissions
+	Delete(ctx context.Context, name string) error
+	// UserIDs returns the IDs of users (soft-deleted ones included) with the role
+	UserIDs(ctx context.Context, name string) ([]uint, error)
+}
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+// NewRoleRepository creates a new RoleRepository
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+func preloadPermissions(db *gorm.DB) *gorm.DB {
+	return db.Preload("Permissions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("permission")
+	})
+}
+
+func (r *roleRepository) List(ctx context.Context) ([]models.Role, error) {
+	var roles []models.Role
+	err := preloadPermissions(database.Conn(ctx, r.db)).Order("name").Find(&roles).Error
+	return roles, err
+}
+
+func (r *roleRepository) Find(ctx context.Context, name string) (*models.Role, error) {
+	var role models.Role
+	err := preloadPermissions(database.Conn(ctx, r.db)).Where("name = ?", name).First(&role).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) Create(ctx context.Context, role *models.Role) error {
+	return database.Conn(ctx, r.db).Create(role).Error
+}
+
+func (r *roleRepository) UpdateDescription(ctx context.Context, name, description string) (bool, error) {
+	result := database.Conn(ctx, r.db).Model(&models.Role{}).
+		Where("name = ?", name).
+		Update("description", description)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *roleRepository) AddPermissions(ctx context.Context, name string, permissions []string) error {
+	if len(permissions) == 0 {
+		return nil
+	}
+	rows := make([]models.RolePermission, len(permissions))
+	for i, permission := range permissions {
+		rows[i] = models.RolePermission{RoleName: name, Permission: permission}
+	}
+	return database.Conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+}
+
+func (r *roleRepository) RemovePermission(ctx context.Context, name, permission string) (bool, error) {
+	result := database.Conn(ctx, r.db).
+		Where("role_name = ? AND permission = ?", name, permission).
+		Delete(&models.RolePermission{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *roleRepository) BumpVersion(ctx context.Context, name string) error {
+	return database.Conn(ctx, r.db).Model(&models.Role{}).
+		Where("name = ?", name).
+		UpdateColumn("version", gorm.Expr("version + 1")).Error
+}
+
+func (r *roleRepository) Delete(ctx context.Context, name string) error {
+	db := database.Conn(ctx, r.db)
+	// Explicit for SQLite, where ON DELETE CASCADE needs foreign_keys enabled
+	if err := db.Where("role_name = ?", name).Delete(&models.RolePermission{}).Error; err != nil {
+		return err
+	}
+	return db.Where("name = ?", name).Delete(&models.Role{}).Error
+}
+
+func (r *roleRepository) UserIDs(ctx context.Context, name string) ([]uint, error) {
+	var ids []uint
+	err := database.Conn(ctx, r.db).Model(&models.User{}).Where("role = ?", name).Pluck("id", &ids).Error
+	return ids, err
+}
@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/models"
+)
+
+// SessionRepository queries refresh tokens as login sessions across users
+type SessionRepository interface {
+	// List returns the page of refresh tokens selected by spec and the total
+	List(ctx context.Context, spec *Spec) ([]models.RefreshToken, int64, error)
+	// Revoke revokes every unrevoked refresh token matching spec's filters
+	Revoke(ctx context.Context, spec *Spec) (int64, error)
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository creates a new SessionRepository
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+func (r *sessionRepository) List(ctx context.Context, spec *Spec) ([]models.RefreshToken, int64, error) {
+	return findBySpec[models.RefreshToken](database.Conn(ctx, r.db), spec)
+}
+
+func (r *sessionRepository) Revoke(ctx context.Context, spec *Spec) (int64, error) {
+	result := database.Conn(ctx, r.db).
+		Model(&models.RefreshToken{}).
+		Scopes(spec.filter).
+		Where("revoked = ?", false).
+		Updates(map[string]interface{}{
+			"revoked":    true,
+			"revoked_at": time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Spec describes a list query (filters, sort order and pagination) so list
+// methods share one implementation instead of hand-rolling Where chains.
+// Builder methods return the Spec for chaining:
+//
+//	spec := NewSpec().
+//		Where("user_id = ?", userID).
+//		WhereIf(feature != "", "feature_type = ?", feature).
+//		OrderBy("created_at", true).
+//		Paginate(limit, offset)
+type Spec struct {
+	filters []specFilter
+	orders  []string
+	limit   int
+	offset  int
+}
+
+type specFilter struct {
+	query string
+	args  []any
+}
+
+// NewSpec creates an empty Spec (no filters, no limit)
+func NewSpec() *Spec {
+	return &Spec{}
+}
+
+// Where adds a condition; conditions are combined with AND
+func (s *Spec) Where(query string, args ...any) *Spec {
+	s.filters = append(s.filters, specFilter{query: query, args: args})
+	return s
+}
+
+// WhereIf adds the condition only when cond is true, for optional filters
+func (s *Spec) WhereIf(cond bool, query string, args ...any) *Spec {
+	if cond {
+		return s.Where(query, args...)
+	}
+	return s
+}
+
+// Search matches term case-insensitively as a substring of any of columns.
+// Columns must be trusted identifiers; an empty term adds no condition.
+// LOWER/LIKE is used instead of ILIKE so the query also runs on SQLite.
+func (s *Spec) Search(term string, columns ...string) *Spec {
+	term = strings.TrimSpace(term)
+	if term == "" || len(columns) == 0 {
+		return s
+	}
+
+	pattern := "%" + likeEscaper.Replace(strings.ToLower(term)) + "%"
+	conditions := make([]string, len(columns))
+	args := make([]any, len(columns))
+	for i, column := range columns {
+		conditions[i] = "LOWER(" + column + ") LIKE ? ESCAPE '\\'"
+		args[i] = pattern
+	}
+	return s.Where("("+strings.Join(conditions, " OR ")+")", args...)
+}
+
+// Prefix matches rows whose column starts with value (case-sensitive).
+// column must be a trusted identifier; an empty value adds no condition.
+func (s *Spec) Prefix(column, value string) *Spec {
+	if value == "" {
+		return s
+	}
+	return s.Where(column+" LIKE ? ESCAPE '\\'", likeEscaper.Replace(value)+"%")
+}
+
+// OrderBy appends a sort column. column must be a trusted identifier; use
+// SortBy for client-supplied sort parameters.
+func (s *Spec) OrderBy(column string, desc bool) *Spec {
+	if desc {
+		column += " DESC"
+	}
+	s.orders = append(s.orders, column)
+	return s
+}
+
+// SortBy applies a client sort parameter such as "-created_at,email" (a
+// leading "-" means descending). Fields are mapped to columns through
+// allowed; unknown fields are ignored so user input never reaches SQL.
+func (s *Spec) SortBy(sort string, allowed map[string]string) *Spec {
+	for _, field := range strings.Split(sort, ",") {
+		field = strings.TrimSpace(field)
+		desc := strings.HasPrefix(field, "-")
+		if column, ok := allowed[strings.TrimPrefix(field, "-")]; ok {
+			s.OrderBy(column, desc)
+		}
+	}
+	return s
+}
+
+// Sorted reports whether any sort order has been set
+func (s *Spec) Sorted() bool {
+	return len(s.orders) > 0
+}
+
+// Paginate limits the result window; limit <= 0 means no limit
+func (s *Spec) Paginate(limit, offset int) *Spec {
+	s.limit = limit
+	s.offset = offset
+	return s
+}
+
+// filter applies the conditions only, for counting
+func (s *Spec) filter(db *gorm.DB) *gorm.DB {
+	for _, f := range s.filters {
+		db = db.Where(f.query, f.args...)
+	}
+	return db
+}
+
+// apply applies conditions, sort order and pagination
+func (s *Spec) apply(db *gorm.DB) *gorm.DB {
+	db = s.filter(db)
+	for _, order := range s.orders {
+		db = db.Order(order)
+	}
+	if s.limit > 0 {
+		db = db.Limit(s.limit).Offset(s.offset)
+	}
+	return db
+}
+
+// findBySpec returns the page of T selected by spec and the total number of
+// matching rows (ignoring pagination)
+func findBySpec[T any](db *gorm.DB, spec *Spec) ([]T, int64, error) {
+	var items []T
+	var total int64
+
+	if err := db.Model(new(T)).Scopes(spec.filter).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := db.Scopes(spec.apply).Find(&items).Error; err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// likeEscaper escapes LIKE wildcards so search terms match literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
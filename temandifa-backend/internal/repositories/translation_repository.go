@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/models"
+)
+
+// TranslationRepository persists the versions of the translation bundles
+type TranslationRepository interface {
+	// LatestVersions returns the latest version of every locale, without
+	// its strings
+	LatestVersions(ctx context.Context) ([]models.TranslationBundle, error)
+	// Versions returns the versions of the locale, the latest first
+	Versions(ctx context.Context, locale string) ([]models.TranslationBundle, error)
+	// Find returns nil when the locale has no such version
+	Find(ctx context.Context, locale string, version int) (*models.TranslationBundle, error)
+	// Create stores the bundle as the locale's next version
+	Create(ctx context.Context, bundle *models.TranslationBundle) error
+	// Prune deletes all but the locale's keep latest versions
+	Prune(ctx context.Context, locale string, keep int) error
+	// DeleteLocale deletes every version of the locale, returning how many
+	DeleteLocale(ctx context.Context, locale string) (int64, error)
+}
+
+type translationRepository struct {
+	db *gorm.DB
+}
+
+// NewTranslationRepository creates a new TranslationRepository
+func NewTranslationRepository(db *gorm.DB) TranslationRepository {
+	return &translationRepository{db: db}
+}
+
+func (r *translationRepository) LatestVersions(ctx context.Context) ([]models.TranslationBundle, error) {
+	var bundles []models.TranslationBundle
+	err := database.Conn(ctx, r.db).
+		Select("id", "locale", "version", "checksum", "created_by", "created_at").
+		Where("version = (SELECT MAX(latest.version) FROM translation_bundles latest WHERE latest.locale = translation_bundles.locale)").
+		Order("locale").
+		Find(&bundles).Error
+	return bundles, err
+}
+
+func (r *translationRepository) Versions(ctx context.Context, locale string) ([]models.TranslationBundle, error) {
+	var bundles []models.TranslationBundle
+	err := database.Conn(ctx, r.db).Where("locale = ?", locale).Order("version DESC").Find(&bundles).Error
+	return bundles, err
+}
+
+func (r *translationRepository) Find(ctx context.Context, locale string, version int) (*models.TranslationBundle, error) {
+	var bundle models.TranslationBundle
+	if err := database.Conn(ctx, r.db).Where("locale = ? AND version = ?", locale, version).First(&bundle).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+func (r *translationRepository) Create(ctx context.Context, bundle *models.TranslationBundle) error {
+	return database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// Concurrent uploads of a locale fail on the unique version
+		err := tx.Model(&models.TranslationBundle{}).
+			Select("COALESCE(MAX(version), 0) + 1").
+			Where("locale = ?", bundle.Locale).
+			Scan(&bundle.Version).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(bundle).Error
+	})
+}
+
+func (r *translationRepository) Prune(ctx context.Context, locale string, keep int) error {
+	return database.Conn(ctx, r.db).
+		Where("locale = ? AND version <= (SELECT MAX(version) FROM translation_bundles WHERE locale = ?) - ?", locale, locale, keep).
+		Delete(&models.TranslationBundle{}).Error
+}
+
+func (r *translationRepository) DeleteLocale(ctx context.Context, locale string) (int64, error) {
+	result := database.Conn(ctx, r.db).Where("locale = ?", locale).Delete(&models.TranslationBundle{})
+	return result.RowsAffected, result.Error
+}
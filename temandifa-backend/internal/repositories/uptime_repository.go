@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/models"
+)
+
+// UptimeRepository records and reads health check samples per day and component
+type UptimeRepository interface {
+	// Add counts samples, healthy of which, in the row for day and component
+	Add(ctx context.Context, day time.Time, component string, samples, healthy int64) error
+	// Since returns every row from day from on, oldest first
+	Since(ctx context.Context, from time.Time) ([]models.ComponentUptimeDaily, error)
+}
+
+type uptimeRepository struct {
+	db *gorm.DB
+}
+
+// NewUptimeRepository creates a new UptimeRepository
+func NewUptimeRepository(db *gorm.DB) UptimeRepository {
+	return &uptimeRepository{db: db}
+}
+
+func (r *uptimeRepository) Add(ctx context.Context, day time.Time, component string, samples, healthy int64) error {
+	row := models.ComponentUptimeDaily{
+		Day:            day,
+		Component:      component,
+		Samples:        samples,
+		HealthySamples: healthy,
+	}
+	return database.Conn(ctx, r.db).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "day"}, {Name: "component"}},
+			DoUpdates: clause.Set{
+				{Column: clause.Column{Name: "samples"}, Value: gorm.Expr("component_uptime_daily.samples + ?", samples)},
+				{Column: clause.Column{Name: "healthy_samples"}, Value: gorm.Expr("component_uptime_daily.healthy_samples + ?", healthy)},
+			},
+		}).
+		Create(&row).Error
+}
+
+func (r *uptimeRepository) Since(ctx context.Context, from time.Time) ([]models.ComponentUptimeDaily, error) {
+	var rows []models.ComponentUptimeDaily
+	err := database.Conn(ctx, r.db).
+		Where("day >= ?", from).
+		Order("day, component").
+		Find(&rows).Error
+	return rows, err
+}
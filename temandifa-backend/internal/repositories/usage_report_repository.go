@@ -0,0 +1,141 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/models"
+)
+
+// usageReportBatchSize bounds the rows of one insert and the IDs of one
+// IN list, well below Postgres' parameter limit
+const usageReportBatchSize = 500
+
+// UsageReportWithEmail is a report with its user's email, empty for purged
+// users
+type UsageReportWithEmail struct {
+	models.UsageReport
+	Email string
+}
+
+// UserStorage is the attachment storage of one user
+type UserStorage struct {
+	UserID      uint
+	Bytes       int64
+	Attachments int64
+}
+
+// UsageReportRepository stores monthly usage reports and reads what they
+// are compiled from besides usage_daily
+type UsageReportRepository interface {
+	// Save creates the reports, replacing each user's report of the same month
+	Save(ctx context.Context, reports []models.UsageReport) error
+	// Find returns nil when the user has no report of month
+	Find(ctx context.Context, userID uint, month string) (*models.UsageReport, error)
+	// ListByUser returns the user's reports, the latest month first
+	ListByUser(ctx context.Context, userID uint) ([]models.UsageReport, error)
+	// Month returns a page of month's reports in user order
+	Month(ctx context.Context, month string, page, limit int) ([]UsageReportWithEmail, int64, error)
+	// Count counts month's reports
+	Count(ctx context.Context, month string) (int64, error)
+	// StorageByUser sums the stored attachments created before before per user
+	StorageByUser(ctx context.Context, before time.Time) ([]UserStorage, error)
+	// Plans returns the plan of each of the users, deleted ones included
+	Plans(ctx context.Context, userIDs []uint) (map[uint]string, error)
+}
+
+type usageReportRepository struct {
+	db *gorm.DB
+}
+
+// NewUsageReportRepository creates a new UsageReportRepository
+func NewUsageReportRepository(db *gorm.DB) UsageReportRepository {
+	return &usageReportRepository{db: db}
+}
+
+func (r *usageReportRepository) Save(ctx context.Context, reports []models.UsageReport) error {
+	if len(reports) == 0 {
+		return nil
+	}
+	return database.Conn(ctx, r.db).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}, {Name: "month"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"plan", "requests", "cache_hits", "errors", "operations", "storage_bytes", "attachments", "generated_at",
+			}),
+		}).
+		CreateInBatches(reports, usageReportBatchSize).Error
+}
+
+func (r *usageReportRepository) Find(ctx context.Context, userID uint, month string) (*models.UsageReport, error) {
+	var report models.UsageReport
+	err := database.Conn(ctx, r.db).Where("user_id = ? AND month = ?", userID, month).First(&report).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (r *usageReportRepository) ListByUser(ctx context.Context, userID uint) ([]models.UsageReport, error) {
+	var reports []models.UsageReport
+	err := database.Conn(ctx, r.db).Where("user_id = ?", userID).Order("month DESC").Find(&reports).Error
+	return reports, err
+}
+
+func (r *usageReportRepository) Month(ctx context.Context, month string, page, limit int) ([]UsageReportWithEmail, int64, error) {
+	var total int64
+	if err := database.Conn(ctx, r.db).Model(&models.UsageReport{}).Where("month = ?", month).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var reports []UsageReportWithEmail
+	// LEFT JOIN: purged users keep their reports but no longer have an email
+	err := database.Conn(ctx, r.db).Model(&models.UsageReport{}).
+		Select("usage_reports.*, COALESCE(users.email, '') AS email").
+		Joins("LEFT JOIN users ON users.id = usage_reports.user_id").
+		Where("usage_reports.month = ?", month).
+		Order("usage_reports.user_id").
+		Offset((page - 1) * limit).Limit(limit).
+		Scan(&reports).Error
+	return reports, total, err
+}
+
+func (r *usageReportRepository) Count(ctx context.Context, month string) (int64, error) {
+	var count int64
+	err := database.Conn(ctx, r.db).Model(&models.UsageReport{}).Where("month = ?", month).Count(&count).Error
+	return count, err
+}
+
+func (r *usageReportRepository) StorageByUser(ctx context.Context, before time.Time) ([]UserStorage, error) {
+	var rows []UserStorage
+	err := database.Conn(ctx, r.db).Model(&models.HistoryAttachment{}).
+		Select("user_id, SUM(size_bytes) AS bytes, COUNT(*) AS attachments").
+		Where("created_at < ?", before).
+		Group("user_id").
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *usageReportRepository) Plans(ctx context.Context, userIDs []uint) (map[uint]string, error) {
+	plans := make(map[uint]string, len(userIDs))
+	for start := 0; start < len(userIDs); start += usageReportBatchSize {
+		batch := userIDs[start:min(start+usageReportBatchSize, len(userIDs))]
+		var users []models.User
+		err := database.Conn(ctx, r.db).Unscoped().Select("id", "plan").Where("id IN ?", batch).Find(&users).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			plans[user.ID] = user.Plan
+		}
+	}
+	return plans, nil
+}
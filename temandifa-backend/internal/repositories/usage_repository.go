@@ -0,0 +1,143 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/models"
+)
+
+// UsageSummary is usage_daily summed per day, operation or user; only the
+// grouping field of the query that produced it is set
+type UsageSummary struct {
+	Day       time.Time
+	Operation string
+	UserID    uint
+	Email     string
+	Requests  int64
+	CacheHits int64
+	Errors    int64
+}
+
+// UsageRepository records and aggregates usage accounting
+type UsageRepository interface {
+	// Increment counts one request in the row for day, user and operation
+	Increment(ctx context.Context, day time.Time, userID uint, operation string, cacheHit, failed bool) error
+	// Daily sums usage per day in [from, to), oldest first
+	Daily(ctx context.Context, from, to time.Time) ([]UsageSummary, error)
+	// ByOperation sums usage per operation in [from, to)
+	ByOperation(ctx context.Context, from, to time.Time) ([]UsageSummary, error)
+	// UserDaily sums a user's usage per day and operation in [from, to),
+	// oldest first
+	UserDaily(ctx context.Context, userID uint, from, to time.Time) ([]UsageSummary, error)
+	// TopUsers returns the limit users with the most requests in [from, to)
+	TopUsers(ctx context.Context, from, to time.Time, limit int) ([]UsageSummary, error)
+	// UserOperations sums usage per user and operation in [from, to)
+	UserOperations(ctx context.Context, from, to time.Time) ([]UsageSummary, error)
+}
+
+type usageRepository struct {
+	db *gorm.DB
+}
+
+// NewUsageRepository creates a new UsageRepository
+func NewUsageRepository(db *gorm.DB) UsageRepository {
+	return &usageRepository{db: db}
+}
+
+func boolCount(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func (r *usageRepository) Increment(ctx context.Context, day time.Time, userID uint, operation string, cacheHit, failed bool) error {
+	row := models.UsageDaily{
+		Day:       day,
+		UserID:    userID,
+		Operation: operation,
+		Requests:  1,
+		CacheHits: boolCount(cacheHit),
+		Errors:    boolCount(failed),
+	}
+	return database.Conn(ctx, r.db).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "day"}, {Name: "user_id"}, {Name: "operation"}},
+			DoUpdates: clause.Set{
+				{Column: clause.Column{Name: "requests"}, Value: gorm.Expr("usage_daily.requests + 1")},
+				{Column: clause.Column{Name: "cache_hits"}, Value: gorm.Expr("usage_daily.cache_hits + ?", row.CacheHits)},
+				{Column: clause.Column{Name: "errors"}, Value: gorm.Expr("usage_daily.errors + ?", row.Errors)},
+			},
+		}).
+		Create(&row).Error
+}
+
+// sums are the aggregate columns shared by every summary query
+const usageSums = "SUM(requests) AS requests, SUM(cache_hits) AS cache_hits, SUM(errors) AS errors"
+
+func (r *usageRepository) Daily(ctx context.Context, from, to time.Time) ([]UsageSummary, error) {
+	var rows []UsageSummary
+	err := database.Conn(ctx, r.db).Model(&models.UsageDaily{}).
+		Select("day, "+usageSums).
+		Where("day >= ? AND day < ?", from, to).
+		Group("day").
+		Order("day").
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *usageRepository) ByOperation(ctx context.Context, from, to time.Time) ([]UsageSummary, error) {
+	var rows []UsageSummary
+	err := database.Conn(ctx, r.db).Model(&models.UsageDaily{}).
+		Select("operation, "+usageSums).
+		Where("day >= ? AND day < ?", from, to).
+		Group("operation").
+		Order("requests DESC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *usageRepository) UserDaily(ctx context.Context, userID uint, from, to time.Time) ([]UsageSummary, error) {
+	var rows []UsageSummary
+	err := database.Conn(ctx, r.db).Model(&models.UsageDaily{}).
+		Select("day, operation, "+usageSums).
+		Where("user_id = ? AND day >= ? AND day < ?", userID, from, to).
+		Group("day, operation").
+		Order("day").
+		Order("operation").
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *usageRepository) TopUsers(ctx context.Context, from, to time.Time, limit int) ([]UsageSummary, error) {
+	var rows []UsageSummary
+	// LEFT JOIN: purged users keep their usage but no longer have an email
+	err := database.Conn(ctx, r.db).Model(&models.UsageDaily{}).
+		Select("usage_daily.user_id, COALESCE(MAX(users.email), '') AS email, "+
+			"SUM(usage_daily.requests) AS requests, SUM(usage_daily.cache_hits) AS cache_hits, SUM(usage_daily.errors) AS errors").
+		Joins("LEFT JOIN users ON users.id = usage_daily.user_id").
+		Where("usage_daily.day >= ? AND usage_daily.day < ?", from, to).
+		Group("usage_daily.user_id").
+		Order("requests DESC").
+		Order("usage_daily.user_id").
+		Limit(limit).
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *usageRepository) UserOperations(ctx context.Context, from, to time.Time) ([]UsageSummary, error) {
+	var rows []UsageSummary
+	err := database.Conn(ctx, r.db).Model(&models.UsageDaily{}).
+		Select("user_id, operation, "+usageSums).
+		Where("day >= ? AND day < ?", from, to).
+		Group("user_id, operation").
+		Order("user_id").
+		Order("operation").
+		Scan(&rows).Error
+	return rows, err
+}
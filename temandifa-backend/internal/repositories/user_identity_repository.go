@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/models"
+)
+
+// UserIdentityRepository stores the sign-in providers linked to users
+type UserIdentityRepository interface {
+	// Find returns nil when no user linked the provider's subject
+	Find(ctx context.Context, provider, subject string) (*models.UserIdentity, error)
+	// Create joins the transaction bound to ctx, if any
+	Create(ctx context.Context, identity *models.UserIdentity) error
+	// Touch records a sign-in with the identity
+	Touch(ctx context.Context, id uint64, at time.Time) error
+}
+
+type userIdentityRepository struct {
+	db *gorm.DB
+}
+
+// NewUserIdentityRepository creates a new UserIdentityRepository
+func NewUserIdentityRepository(db *gorm.DB) UserIdentityRepository {
+	return &userIdentityRepository{db: db}
+}
+
+func (r *userIdentityRepository) Find(ctx context.Context, provider, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	err := database.Conn(ctx, r.db).Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *userIdentityRepository) Create(ctx context.Context, identity *models.UserIdentity) error {
+	return database.Conn(ctx, r.db).Create(identity).Error
+}
+
+func (r *userIdentityRepository) Touch(ctx context.Context, id uint64, at time.Time) error {
+	return database.Conn(ctx, r.db).Model(&models.UserIdentity{}).Where("id = ?", id).Update("last_used_at", at).Error
+}
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/models"
+)
+
+// UserPreferencesRepository persists users' accessibility preferences
+type UserPreferencesRepository interface {
+	// Find returns nil when the user never changed their preferences
+	Find(ctx context.Context, userID uint) (*models.UserPreferences, error)
+	// Save creates or replaces the user's preferences
+	Save(ctx context.Context, preferences *models.UserPreferences) error
+}
+
+type userPreferencesRepository struct {
+	db *gorm.DB
+}
+
+// NewUserPreferencesRepository creates a new UserPreferencesRepository
+func NewUserPreferencesRepository(db *gorm.DB) UserPreferencesRepository {
+	return &userPreferencesRepository{db: db}
+}
+
+func (r *userPreferencesRepository) Find(ctx context.Context, userID uint) (*models.UserPreferences, error) {
+	var preferences models.UserPreferences
+	if err := database.Conn(ctx, r.db).Where("user_id = ?", userID).First(&preferences).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &preferences, nil
+}
+
+func (r *userPreferencesRepository) Save(ctx context.Context, preferences *models.UserPreferences) error {
+	return database.Conn(ctx, r.db).Save(preferences).Error
+}
@@ -1,18 +1,36 @@
 package repositories
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	"gorm.io/gorm"
 
+	"temandifa-backend/internal/database"
 	"temandifa-backend/internal/models"
 )
 
 // UserRepository defines methods for interacting with user data
 type UserRepository interface {
-	Create(user *models.User) error
-	FindByEmail(email string) (*models.User, error)
-	FindByID(id uint) (*models.User, error)
+	// Create joins the transaction bound to ctx, if any
+	Create(ctx context.Context, user *models.User) error
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	// FindByID skips soft-deleted users
+	FindByID(ctx context.Context, id uint) (*models.User, error)
+	// List returns the users selected by spec (including soft-deleted ones
+	// unless spec excludes them) and the total number of matches
+	List(ctx context.Context, spec *Spec) ([]models.User, int64, error)
+	// Update saves profile fields if user.Version still matches the stored
+	// version; otherwise it returns a CONFLICT error with the current version
+	Update(ctx context.Context, user *models.User) error
+	// MarkEmailVerified records that the user verified email, unless that is
+	// no longer their address or it already was verified; it reports whether
+	// it did
+	MarkEmailVerified(ctx context.Context, userID uint, email string, at time.Time) (bool, error)
+	// SoftDelete marks the user deleted at at, unless it already is; it
+	// reports whether it did. The user purge job hard-deletes it later.
+	SoftDelete(ctx context.Context, userID uint, at time.Time) (bool, error)
 }
 
 type userRepository struct {
@@ -24,13 +42,13 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{db: db}
 }
 
-func (r *userRepository) Create(user *models.User) error {
-	return r.db.Create(user).Error
+func (r *userRepository) Create(ctx context.Context, user *models.User) error {
+	return database.Conn(ctx, r.db).Create(user).Error
 }
 
-func (r *userRepository) FindByEmail(email string) (*models.User, error) {
+func (r *userRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
-	err := r.db.Where("email = ?", email).First(&user).Error
+	err := database.Conn(ctx, r.db).Where("email = ?", email).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil // Return nil if not found, let service handle logic
@@ -40,9 +58,9 @@ func (r *userRepository) FindByEmail(email string) (*models.User, error) {
 	return &user, nil
 }
 
-func (r *userRepository) FindByID(id uint) (*models.User, error) {
+func (r *userRepository) FindByID(ctx context.Context, id uint) (*models.User, error) {
 	var user models.User
-	err := r.db.First(&user, id).Error
+	err := database.Conn(ctx, r.db).Where("deleted_at IS NULL").First(&user, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -51,3 +69,41 @@ func (r *userRepository) FindByID(id uint) (*models.User, error) {
 	}
 	return &user, nil
 }
+
+func (r *userRepository) List(ctx context.Context, spec *Spec) ([]models.User, int64, error) {
+	return findBySpec[models.User](database.Conn(ctx, r.db), spec)
+}
+
+func (r *userRepository) Update(ctx context.Context, user *models.User) error {
+	result := database.Conn(ctx, r.db).Model(user).
+		Select("full_name", "email", "profile_picture", "preferred_language", "role", "plan", "organization", "password",
+			"email_verified_at", "pending_email", "pending_email_old_confirmed", "pending_email_new_confirmed").
+		Updates(user)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return versionConflict(ctx, r.db, &models.User{}, "User", "id = ?", user.ID)
+	}
+	return nil
+}
+
+func (r *userRepository) MarkEmailVerified(ctx context.Context, userID uint, email string, at time.Time) (bool, error) {
+	result := database.Conn(ctx, r.db).Model(&models.User{}).
+		Where("id = ? AND email = ? AND email_verified_at IS NULL AND deleted_at IS NULL", userID, email).
+		Updates(map[string]any{
+			"email_verified_at": at,
+			"version":           gorm.Expr("version + 1"),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *userRepository) SoftDelete(ctx context.Context, userID uint, at time.Time) (bool, error) {
+	result := database.Conn(ctx, r.db).Model(&models.User{}).
+		Where("id = ? AND deleted_at IS NULL", userID).
+		Updates(map[string]any{
+			"deleted_at": at,
+			"version":    gorm.Expr("version + 1"),
+		})
+	return result.RowsAffected > 0, result.Error
+}
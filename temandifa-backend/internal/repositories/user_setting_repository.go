@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/models"
+)
+
+// UserSettingRepository persists the settings synced across users' devices
+type UserSettingRepository interface {
+	// Since returns the user's settings changed after seq, oldest change
+	// first
+	Since(ctx context.Context, userID uint, seq int64) ([]models.UserSetting, error)
+	// Find returns the user's settings (tombstones included) by key
+	Find(ctx context.Context, userID uint, keys []string) (map[string]models.UserSetting, error)
+	// CountLive returns how many settings of the user aren't deleted
+	CountLive(ctx context.Context, userID uint) (int64, error)
+	// LastSeq locks the user's settings until ctx's transaction ends and
+	// returns the seq of their latest change (0 before the first)
+	LastSeq(ctx context.Context, userID uint) (int64, error)
+	// Save creates or replaces a setting
+	Save(ctx context.Context, setting *models.UserSetting) error
+}
+
+type userSettingRepository struct {
+	db *gorm.DB
+}
+
+// NewUserSettingRepository creates a new UserSettingRepository
+func NewUserSettingRepository(db *gorm.DB) UserSettingRepository {
+	return &userSettingRepository{db: db}
+}
+
+func (r *userSettingRepository) Since(ctx context.Context, userID uint, seq int64) ([]models.UserSetting, error) {
+	var settings []models.UserSetting
+	err := database.Conn(ctx, r.db).
+		Where("user_id = ? AND seq > ?", userID, seq).
+		Order("seq").
+		Find(&settings).Error
+	return settings, err
+}
+
+func (r *userSettingRepository) Find(ctx context.Context, userID uint, keys []string) (map[string]models.UserSetting, error) {
+	byKey := make(map[string]models.UserSetting, len(keys))
+	if len(keys) == 0 {
+		return byKey, nil
+	}
+	var settings []models.UserSetting
+	err := database.Conn(ctx, r.db).
+		Where(`user_id = ? AND "key" IN ?`, userID, keys).
+		Find(&settings).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, setting := range settings {
+		byKey[setting.Key] = setting
+	}
+	return byKey, nil
+}
+
+func (r *userSettingRepository) CountLive(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := database.Conn(ctx, r.db).Model(&models.UserSetting{}).
+		Where("user_id = ? AND deleted = ?", userID, false).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *userSettingRepository) LastSeq(ctx context.Context, userID uint) (int64, error) {
+	// The user row serializes concurrent syncs, including a user's first
+	var user models.User
+	err := database.Conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("id = ?", userID).
+		Take(&user).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var seq int64
+	err = database.Conn(ctx, r.db).Model(&models.UserSetting{}).
+		Select("COALESCE(MAX(seq), 0)").
+		Where("user_id = ?", userID).
+		Scan(&seq).Error
+	return seq, err
+}
+
+func (r *userSettingRepository) Save(ctx context.Context, setting *models.UserSetting) error {
+	return database.Conn(ctx, r.db).Save(setting).Error
+}
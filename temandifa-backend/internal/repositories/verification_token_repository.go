@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/models"
+)
+
+// VerificationTokenRepository stores the single-use tokens sent to users'
+// email addresses
+type VerificationTokenRepository interface {
+	// Create joins the transaction bound to ctx, if any
+	Create(ctx context.Context, token *models.VerificationToken) error
+	// FindByHash returns nil when no token has the hash
+	FindByHash(ctx context.Context, tokenHash string) (*models.VerificationToken, error)
+	// MarkUsed uses the token up, reporting false when it already was
+	MarkUsed(ctx context.Context, id uint64, at time.Time) (bool, error)
+	// MarkAllUsed uses up the user's unused tokens of purpose
+	MarkAllUsed(ctx context.Context, userID uint, purpose string, at time.Time) (int64, error)
+	// CountSince counts the user's tokens of purpose created after since
+	CountSince(ctx context.Context, userID uint, purpose string, since time.Time) (int64, error)
+	// DeleteExpired deletes the tokens that expired before before
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+type verificationTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewVerificationTokenRepository creates a new VerificationTokenRepository
+func NewVerificationTokenRepository(db *gorm.DB) VerificationTokenRepository {
+	return &verificationTokenRepository{db: db}
+}
+
+func (r *verificationTokenRepository) Create(ctx context.Context, token *models.VerificationToken) error {
+	return database.Conn(ctx, r.db).Create(token).Error
+}
+
+func (r *verificationTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*models.VerificationToken, error) {
+	var token models.VerificationToken
+	err := database.Conn(ctx, r.db).Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *verificationTokenRepository) MarkUsed(ctx context.Context, id uint64, at time.Time) (bool, error) {
+	result := database.Conn(ctx, r.db).Model(&models.VerificationToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *verificationTokenRepository) MarkAllUsed(ctx context.Context, userID uint, purpose string, at time.Time) (int64, error) {
+	result := database.Conn(ctx, r.db).Model(&models.VerificationToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", at)
+	return result.RowsAffected, result.Error
+}
+
+func (r *verificationTokenRepository) CountSince(ctx context.Context, userID uint, purpose string, since time.Time) (int64, error) {
+	var count int64
+	err := database.Conn(ctx, r.db).Model(&models.VerificationToken{}).
+		Where("user_id = ? AND purpose = ? AND created_at > ?", userID, purpose, since).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *verificationTokenRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result := database.Conn(ctx, r.db).Where("expires_at < ?", before).Delete(&models.VerificationToken{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/models"
+)
+
+// WebhookRepository persists webhook subscriptions and their delivery logs
+type WebhookRepository interface {
+	List(ctx context.Context) ([]models.WebhookSubscription, error)
+	ListActive(ctx context.Context) ([]models.WebhookSubscription, error)
+	// Find returns nil when the subscription doesn't exist
+	Find(ctx context.Context, id uint64) (*models.WebhookSubscription, error)
+	Create(ctx context.Context, subscription *models.WebhookSubscription) error
+	Update(ctx context.Context, subscription *models.WebhookSubscription) error
+	// Delete deletes the subscription and its deliveries, reporting false
+	// if it didn't exist
+	Delete(ctx context.Context, id uint64) (bool, error)
+
+	CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	// Deliveries returns a page of the subscription's deliveries, the latest
+	// first, and their total
+	Deliveries(ctx context.Context, subscriptionID uint64, page, limit int) ([]models.WebhookDelivery, int64, error)
+	// Delivered returns the subscriptions the outbox event was delivered to
+	Delivered(ctx context.Context, eventID uint64) (map[uint64]bool, error)
+	// PurgeDeliveries deletes deliveries made before cutoff, returning how many
+	PurgeDeliveries(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository creates a new WebhookRepository
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) List(ctx context.Context) ([]models.WebhookSubscription, error) {
+	var subscriptions []models.WebhookSubscription
+	err := database.Conn(ctx, r.db).Order("id").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+func (r *webhookRepository) ListActive(ctx context.Context) ([]models.WebhookSubscription, error) {
+	var subscriptions []models.WebhookSubscription
+	err := database.Conn(ctx, r.db).Where("active = ?", true).Order("id").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+func (r *webhookRepository) Find(ctx context.Context, id uint64) (*models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+	if err := database.Conn(ctx, r.db).First(&subscription, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (r *webhookRepository) Create(ctx context.Context, subscription *models.WebhookSubscription) error {
+	return database.Conn(ctx, r.db).Create(subscription).Error
+}
+
+func (r *webhookRepository) Update(ctx context.Context, subscription *models.WebhookSubscription) error {
+	return database.Conn(ctx, r.db).Save(subscription).Error
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, id uint64) (bool, error) {
+	var deleted int64
+	err := database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// Also without foreign key enforcement (SQLite)
+		if err := tx.Where("subscription_id = ?", id).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.WebhookSubscription{}, id)
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	return deleted > 0, err
+}
+
+func (r *webhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return database.Conn(ctx, r.db).Create(delivery).Error
+}
+
+func (r *webhookRepository) Deliveries(ctx context.Context, subscriptionID uint64, page, limit int) ([]models.WebhookDelivery, int64, error) {
+	query := database.Conn(ctx, r.db).Model(&models.WebhookDelivery{}).Where("subscription_id = ?", subscriptionID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var deliveries []models.WebhookDelivery
+	err := query.Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&deliveries).Error
+	return deliveries, total, err
+}
+
+func (r *webhookRepository) Delivered(ctx context.Context, eventID uint64) (map[uint64]bool, error) {
+	var ids []uint64
+	err := database.Conn(ctx, r.db).Model(&models.WebhookDelivery{}).
+		Where("event_id = ? AND status = ?", eventID, models.WebhookDeliverySucceeded).
+		Distinct().Pluck("subscription_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	delivered := make(map[uint64]bool, len(ids))
+	for _, id := range ids {
+		delivered[id] = true
+	}
+	return delivered, nil
+}
+
+func (r *webhookRepository) PurgeDeliveries(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := database.Conn(ctx, r.db).Where("created_at < ?", cutoff).Delete(&models.WebhookDelivery{})
+	return result.RowsAffected, result.Error
+}
@@ -4,9 +4,10 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/goccy/go-json"
 
 	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/negotiate"
 )
 
 // ErrorCode is an alias to the centralized error code type
@@ -20,6 +21,7 @@ const (
 	ErrCodeInvalidToken       = apperrors.ErrCodeTokenInvalid
 	ErrCodeTokenExpired       = apperrors.ErrCodeTokenExpired
 	ErrCodeInvalidCredentials = apperrors.ErrCodeInvalidCredentials
+	ErrCodeEmailNotVerified   = apperrors.ErrCodeEmailNotVerified
 
 	// Validation errors
 	ErrCodeValidation   = apperrors.ErrCodeValidation
@@ -32,11 +34,14 @@ const (
 	ErrCodeConflict      = apperrors.ErrCodeConflict
 
 	// Rate limiting
-	ErrCodeRateLimited = apperrors.ErrCodeRateLimited
+	ErrCodeRateLimited   = apperrors.ErrCodeRateLimited
+	ErrCodeQuotaExceeded = apperrors.ErrCodeQuotaExceeded
 
 	// Server errors
 	ErrCodeInternal           = apperrors.ErrCodeInternal
 	ErrCodeServiceUnavailable = apperrors.ErrCodeServiceUnavailable
+	ErrCodeFeatureDisabled    = apperrors.ErrCodeFeatureDisabled
+	ErrCodeUpgradeRequired    = apperrors.ErrCodeUpgradeRequired
 	ErrCodeDatabaseError      = apperrors.ErrCodeDatabaseError
 	ErrCodeAIServiceError     = apperrors.ErrCodeAIServiceError
 
@@ -50,6 +55,7 @@ type ErrorResponse struct {
 	Success   bool      `json:"success"`
 	Error     ErrorInfo `json:"error"`
 	RequestID string    `json:"request_id,omitempty"`
+	TraceID   string    `json:"trace_id,omitempty"`
 }
 
 // ErrorInfo contains error details
@@ -70,25 +76,13 @@ type SuccessResponse struct {
 
 // getRequestID retrieves request ID from context
 func getRequestID(c *gin.Context) string {
-	if id, exists := c.Get("request_id"); exists {
-		return id.(string)
-	}
-	return ""
+	return logger.RequestIDFromContext(c)
 }
 
-// Internal helper for fast JSON rendering
-func renderJSON(c *gin.Context, status int, data any) {
-	c.Header("Content-Type", "application/json; charset=utf-8")
-
-	// Use goccy/go-json for performance
-	jsonBytes, err := json.Marshal(data)
-	if err != nil {
-		// Fallback to standard Gin JSON if marshaling fails (unlikely)
-		c.JSON(status, data)
-		return
-	}
-
-	c.Data(status, "application/json; charset=utf-8", jsonBytes)
+// Render sends data as JSON, or as MessagePack when the request's Accept
+// header prefers it. The helpers below wrap data in the envelope first.
+func Render(c *gin.Context, status int, data any) {
+	negotiate.Render(c, status, data)
 }
 
 // Error sends a standardized error response
@@ -96,6 +90,7 @@ func Error(c *gin.Context, status int, code ErrorCode, message string, details .
 	response := ErrorResponse{
 		Success:   false,
 		RequestID: getRequestID(c),
+		TraceID:   logger.TraceIDFromContext(c),
 		Error: ErrorInfo{
 			Code:    code,
 			Message: message,
@@ -106,7 +101,7 @@ func Error(c *gin.Context, status int, code ErrorCode, message string, details .
 		response.Error.Details = details[0]
 	}
 
-	renderJSON(c, status, response)
+	Render(c, status, response)
 }
 
 // Success sends a standardized success response
@@ -121,7 +116,7 @@ func Success(c *gin.Context, data any, message ...string) {
 		response.Message = message[0]
 	}
 
-	renderJSON(c, http.StatusOK, response)
+	Render(c, http.StatusOK, response)
 }
 
 // SuccessWithMeta sends a success response with metadata (for pagination)
@@ -133,7 +128,7 @@ func SuccessWithMeta(c *gin.Context, data any, meta any) {
 		RequestID: getRequestID(c),
 	}
 
-	renderJSON(c, http.StatusOK, response)
+	Render(c, http.StatusOK, response)
 }
 
 // Created sends a 201 response for resource creation
@@ -145,7 +140,19 @@ func Created(c *gin.Context, data any, message string) {
 		RequestID: getRequestID(c),
 	}
 
-	renderJSON(c, http.StatusCreated, response)
+	Render(c, http.StatusCreated, response)
+}
+
+// Accepted sends a 202 response for work that continues in the background
+func Accepted(c *gin.Context, data any, message string) {
+	response := SuccessResponse{
+		Success:   true,
+		Data:      data,
+		Message:   message,
+		RequestID: getRequestID(c),
+	}
+
+	Render(c, http.StatusAccepted, response)
 }
 
 // --- Convenience error functions ---
@@ -0,0 +1,77 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// clamdChunkSize is the size of the INSTREAM chunks sent to clamd
+const clamdChunkSize = 64 * 1024
+
+// clamAVScanner sends files to clamd with the INSTREAM command
+// (https://docs.clamav.net/manual/Usage/Scanning.html#clamd)
+type clamAVScanner struct {
+	network string // tcp or unix
+	address string
+}
+
+func newClamAVScanner(address string) *clamAVScanner {
+	network, addr, _ := strings.Cut(address, "://")
+	return &clamAVScanner{network: network, address: addr}
+}
+
+func (s *clamAVScanner) Scan(ctx context.Context, content []byte) (Verdict, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, s.network, s.address)
+	if err != nil {
+		return Verdict{}, fmt.Errorf("clamd: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	// zINSTREAM, then chunks prefixed by their big-endian length, then an
+	// empty chunk
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return Verdict{}, fmt.Errorf("clamd: %w", err)
+	}
+	size := make([]byte, 4)
+	for start := 0; start < len(content); start += clamdChunkSize {
+		chunk := content[start:min(start+clamdChunkSize, len(content))]
+		binary.BigEndian.PutUint32(size, uint32(len(chunk)))
+		if _, err := conn.Write(size); err != nil {
+			return Verdict{}, fmt.Errorf("clamd: %w", err)
+		}
+		if _, err := conn.Write(chunk); err != nil {
+			return Verdict{}, fmt.Errorf("clamd: %w", err)
+		}
+	}
+	if _, err := conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return Verdict{}, fmt.Errorf("clamd: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil {
+		return Verdict{}, fmt.Errorf("clamd: %w", err)
+	}
+	return parseClamdReply(strings.TrimRight(reply, "\x00\n"))
+}
+
+// parseClamdReply reads "stream: OK", "stream: <signature> FOUND" or
+// "<message> ERROR"
+func parseClamdReply(reply string) (Verdict, error) {
+	result := strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+	switch {
+	case result == "OK":
+		return Verdict{}, nil
+	case strings.HasSuffix(result, " FOUND"):
+		return Verdict{Infected: true, Signature: strings.TrimSuffix(result, " FOUND")}, nil
+	default:
+		return Verdict{}, fmt.Errorf("clamd: %s", result)
+	}
+}
@@ -0,0 +1,198 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/config"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/metrics"
+	"temandifa-backend/internal/storage"
+)
+
+// Background scanning in async mode
+const (
+	asyncWorkers   = 2
+	asyncQueueSize = 100
+)
+
+var errScanUnavailable = apperrors.NewAppError(apperrors.ErrCodeServiceUnavailable, "Uploads can't be checked for malware right now", http.StatusServiceUnavailable)
+
+// UploadGuard scans the files accepted by the upload validators (it's their
+// helpers.UploadScanner). Inline, infected files are rejected with
+// MALWARE_DETECTED; async, the request goes on and infected files are only
+// quarantined and reported. Either way infected files are copied to
+// MALWARE_QUARANTINE_PREFIX when there is object storage.
+type UploadGuard struct {
+	scanner  Scanner
+	store    storage.ObjectStore
+	mode     string
+	timeout  time.Duration
+	failOpen bool
+	prefix   string
+	log      *zap.Logger
+
+	mu      sync.RWMutex // Guards stopped, so nothing is queued after close
+	stopped bool
+	queue   chan asyncScan
+	wg      sync.WaitGroup
+}
+
+type asyncScan struct {
+	content  []byte
+	filename string
+}
+
+// NewUploadGuard creates the upload guard
+func NewUploadGuard(scanner Scanner, store storage.ObjectStore, uploads *config.UploadConfig, log *zap.Logger) *UploadGuard {
+	return &UploadGuard{
+		scanner:  scanner,
+		store:    store,
+		mode:     uploads.ScanMode,
+		timeout:  uploads.ScanTimeout,
+		failOpen: uploads.ScanFailOpen,
+		prefix:   uploads.QuarantinePrefix,
+		log:      log,
+		queue:    make(chan asyncScan, asyncQueueSize),
+	}
+}
+
+// Scan checks an upload; see UploadGuard
+func (g *UploadGuard) Scan(content []byte, filename, mimeType string) error {
+	if g.mode == ModeAsync {
+		g.enqueue(asyncScan{content: content, filename: filename})
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+	defer cancel()
+	verdict, err := g.scan(ctx, content, filename)
+	if err != nil {
+		if g.failOpen {
+			return nil
+		}
+		return errScanUnavailable
+	}
+	if verdict.Infected {
+		return apperrors.NewAppError(apperrors.ErrCodeMalwareDetected, "The file contains malware and was rejected", http.StatusUnprocessableEntity).
+			WithDetails(map[string]string{"signature": verdict.Signature})
+	}
+	return nil
+}
+
+// scan runs the scanner, recording the outcome and quarantining infected
+// content
+func (g *UploadGuard) scan(ctx context.Context, content []byte, filename string) (Verdict, error) {
+	verdict, err := g.scanner.Scan(ctx, content)
+	if err != nil {
+		metrics.MalwareScans.WithLabelValues(g.mode, "error").Inc()
+		g.log.Error("Malware scan failed", zap.String("filename", filename), zap.Error(err))
+		return verdict, err
+	}
+	if !verdict.Infected {
+		metrics.MalwareScans.WithLabelValues(g.mode, "clean").Inc()
+		return verdict, nil
+	}
+
+	metrics.MalwareScans.WithLabelValues(g.mode, "infected").Inc()
+	sum := sha256.Sum256(content)
+	digest := hex.EncodeToString(sum[:])
+	fields := []zap.Field{
+		zap.String("filename", filename),
+		zap.String("signature", verdict.Signature),
+		zap.String("sha256", digest),
+	}
+	if key, err := g.quarantine(ctx, content, filename, digest); err != nil {
+		g.log.Error("Failed to quarantine infected upload", append(fields, zap.Error(err))...)
+	} else if key != "" {
+		fields = append(fields, zap.String("quarantine_key", key))
+	}
+	g.log.Warn("Infected upload detected", fields...)
+	return verdict, nil
+}
+
+// quarantine stores infected content under the quarantine prefix, by date
+// and digest; it returns an empty key without object storage
+func (g *UploadGuard) quarantine(ctx context.Context, content []byte, filename, digest string) (string, error) {
+	if g.store == nil {
+		return "", nil
+	}
+	key := fmt.Sprintf("%s%s/%s-%s", g.prefix, time.Now().UTC().Format("2006/01/02"), digest, filename)
+	// Stored as opaque bytes so the file is never served as what it claims to be
+	_, err := g.store.Put(context.WithoutCancel(ctx), key, bytes.NewReader(content), int64(len(content)), "application/octet-stream")
+	return key, err
+}
+
+func (g *UploadGuard) enqueue(job asyncScan) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if g.stopped {
+		return
+	}
+	select {
+	case g.queue <- job:
+	default:
+		metrics.MalwareScans.WithLabelValues(g.mode, "skipped").Inc()
+		g.log.Warn("Malware scan queue full, upload not scanned", zap.String("filename", job.filename))
+	}
+}
+
+func (g *UploadGuard) worker() {
+	defer g.wg.Done()
+	for job := range g.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+		_, _ = g.scan(ctx, job.content, job.filename)
+		cancel()
+	}
+}
+
+// RegisterUploadGuard installs the guard in the upload validators when a
+// scanner is configured; in async mode, the scans still queued finish before
+// the application stops
+func RegisterUploadGuard(lc fx.Lifecycle, guard *UploadGuard) {
+	if guard.scanner == nil {
+		return
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if guard.mode == ModeAsync {
+				guard.wg.Add(asyncWorkers)
+				for range asyncWorkers {
+					go guard.worker()
+				}
+			}
+			helpers.SetUploadScanner(guard)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			helpers.SetUploadScanner(nil)
+			guard.mu.Lock()
+			guard.stopped = true
+			close(guard.queue)
+			guard.mu.Unlock()
+			done := make(chan struct{})
+			go func() {
+				guard.wg.Wait()
+				close(done)
+			}()
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return errors.New("malware scans still running at shutdown")
+			}
+		},
+	})
+}
@@ -0,0 +1,52 @@
+// Package scanner checks uploaded files for malware (ClamAV) and keeps the
+// infected ones in quarantine.
+package scanner
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/config"
+)
+
+// Scanner drivers
+const (
+	DriverNone   = "none"
+	DriverClamAV = "clamav"
+)
+
+// Scan modes
+const (
+	ModeInline = "inline" // Uploads wait for the scan; infected ones are rejected
+	ModeAsync  = "async"  // Uploads are scanned in the background after they're used
+)
+
+// Module exports the scanner and the upload guard for Fx. The scanner is nil
+// when MALWARE_SCAN_DRIVER is "none".
+var Module = fx.Options(
+	fx.Provide(NewScanner, NewUploadGuard),
+)
+
+// Verdict is the result of scanning one file
+type Verdict struct {
+	Infected  bool
+	Signature string // Name of the malware found
+}
+
+// Scanner checks content for malware
+type Scanner interface {
+	Scan(ctx context.Context, content []byte) (Verdict, error)
+}
+
+// NewScanner creates the scanner selected by MALWARE_SCAN_DRIVER
+func NewScanner(uploads *config.UploadConfig, log *zap.Logger) Scanner {
+	switch uploads.ScanDriver {
+	case DriverClamAV:
+		log.Info("Malware scanning: ClamAV")
+		return newClamAVScanner(uploads.ClamAVAddress)
+	default:
+		return nil
+	}
+}
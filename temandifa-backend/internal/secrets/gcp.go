@@ -0,0 +1,74 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// gcpMetadataTokenURL issues access tokens for the instance's service account
+const gcpMetadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+
+// gcpBackend accesses Secret Manager versions with GCP_ACCESS_TOKEN or, when
+// unset, a token from the metadata server (GCE, GKE, Cloud Run)
+type gcpBackend struct {
+	http  *http.Client
+	token string
+}
+
+func (b *gcpBackend) Fetch(ctx context.Context, path string) (string, error) {
+	path = strings.Trim(path, "/")
+	if !strings.HasPrefix(path, "projects/") || !strings.Contains(path, "/secrets/") {
+		return "", errors.New("path must be projects/<project>/secrets/<secret>[/versions/<version>]")
+	}
+	if !strings.Contains(path, "/versions/") {
+		path += "/versions/latest"
+	}
+
+	token, err := b.accessToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://secretmanager.googleapis.com/v1/"+path+":access", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	var out struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := doJSON(b.http, req, &out); err != nil {
+		return "", err
+	}
+	data, err := base64.StdEncoding.DecodeString(out.Payload.Data)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (b *gcpBackend) accessToken(ctx context.Context) (string, error) {
+	if b.token != "" {
+		return b.token, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, gcpMetadataTokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	var out struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := doJSON(b.http, req, &out); err != nil {
+		return "", errors.New("no GCP_ACCESS_TOKEN and metadata server unavailable: " + err.Error())
+	}
+	return out.AccessToken, nil
+}
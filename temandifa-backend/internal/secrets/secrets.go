@@ -0,0 +1,151 @@
+// Package secrets resolves secret references in configuration values, such
+// as JWT_SECRET=vault:kv/temandifa#jwt, from external secret managers.
+//
+// A reference is "<scheme>:<path>[#field]". When field is given the secret
+// must be a JSON object and the field's value is returned.
+//
+//	vault:<mount>/<path>#field              HashiCorp Vault KV v2
+//	awssm:<secret-id>[#field]               AWS Secrets Manager
+//	gcpsm:projects/<p>/secrets/<s>[#field]  GCP Secret Manager (latest version
+//	                                        unless /versions/<v> is given)
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Reference schemes
+const (
+	SchemeVault = "vault"
+	SchemeAWS   = "awssm"
+	SchemeGCP   = "gcpsm"
+)
+
+// requestTimeout bounds a single call to a secret manager
+const requestTimeout = 10 * time.Second
+
+// Backend fetches the raw value of a secret
+type Backend interface {
+	Fetch(ctx context.Context, path string) (string, error)
+}
+
+// Options configures the backends. A backend whose settings are missing
+// fails the references that use it, not the others.
+type Options struct {
+	VaultAddr      string
+	VaultToken     string
+	VaultNamespace string
+
+	AWSRegion          string
+	AWSAccessKeyID     string
+	AWSSecretAccessKey string
+	AWSSessionToken    string
+
+	// GCPAccessToken is used when set; otherwise a token is requested from
+	// the GCE/GKE metadata server
+	GCPAccessToken string
+
+	HTTPClient *http.Client
+}
+
+// Resolver resolves references through the backend named by their scheme
+type Resolver struct {
+	backends map[string]Backend
+}
+
+// NewResolver creates a resolver with the Vault, AWS and GCP backends
+func NewResolver(opts Options) *Resolver {
+	client := opts.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: requestTimeout}
+	}
+	return &Resolver{
+		backends: map[string]Backend{
+			SchemeVault: &vaultBackend{http: client, addr: strings.TrimRight(opts.VaultAddr, "/"), token: opts.VaultToken, namespace: opts.VaultNamespace},
+			SchemeAWS:   &awsBackend{http: client, region: opts.AWSRegion, accessKeyID: opts.AWSAccessKeyID, secretAccessKey: opts.AWSSecretAccessKey, sessionToken: opts.AWSSessionToken},
+			SchemeGCP:   &gcpBackend{http: client, token: opts.GCPAccessToken},
+		},
+	}
+}
+
+// IsRef reports whether value is a secret reference
+func IsRef(value string) bool {
+	scheme, _, ok := strings.Cut(value, ":")
+	if !ok {
+		return false
+	}
+	switch scheme {
+	case SchemeVault, SchemeAWS, SchemeGCP:
+		return true
+	}
+	return false
+}
+
+// Resolve fetches the secret a reference points to
+func (r *Resolver) Resolve(ctx context.Context, ref string) (string, error) {
+	scheme, rest, _ := strings.Cut(ref, ":")
+	backend, ok := r.backends[scheme]
+	if !ok {
+		return "", fmt.Errorf("unknown secret scheme %q", scheme)
+	}
+	path, field, _ := strings.Cut(rest, "#")
+	if path == "" {
+		return "", fmt.Errorf("%s: empty secret path", scheme)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	raw, err := backend.Fetch(ctx, path)
+	if err != nil {
+		return "", fmt.Errorf("%s:%s: %w", scheme, path, err)
+	}
+	if field == "" {
+		return raw, nil
+	}
+
+	value, err := jsonField(raw, field)
+	if err != nil {
+		return "", fmt.Errorf("%s:%s: %w", scheme, path, err)
+	}
+	return value, nil
+}
+
+// jsonField extracts a string or scalar field from a JSON object
+func jsonField(raw, field string) (string, error) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(raw), &object); err != nil {
+		return "", fmt.Errorf("secret is not a JSON object, cannot select #%s", field)
+	}
+	value, ok := object[field]
+	if !ok {
+		return "", fmt.Errorf("field %q not found", field)
+	}
+
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		return s, nil
+	}
+	return string(value), nil
+}
+
+// doJSON sends req and decodes a JSON response into out
+func doJSON(client *http.Client, req *http.Request, out any) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
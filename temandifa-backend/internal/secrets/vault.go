@@ -0,0 +1,51 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// vaultBackend reads Vault KV v2 secrets with a token (VAULT_TOKEN)
+type vaultBackend struct {
+	http      *http.Client
+	addr      string
+	token     string
+	namespace string
+}
+
+// Fetch returns the secret's data as a JSON object; references select a
+// key with #field. path is "<mount>/<secret path>".
+func (b *vaultBackend) Fetch(ctx context.Context, path string) (string, error) {
+	if b.addr == "" || b.token == "" {
+		return "", errors.New("VAULT_ADDR and VAULT_TOKEN are required")
+	}
+	mount, secretPath, ok := strings.Cut(strings.Trim(path, "/"), "/")
+	if !ok {
+		return "", errors.New("path must be <mount>/<secret path>")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.addr+"/v1/"+mount+"/data/"+secretPath, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", b.token)
+	if b.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", b.namespace)
+	}
+
+	var body struct {
+		Data struct {
+			Data json.RawMessage `json:"data"`
+		} `json:"data"`
+	}
+	if err := doJSON(b.http, req, &body); err != nil {
+		return "", err
+	}
+	if len(body.Data.Data) == 0 {
+		return "", errors.New("secret has no data")
+	}
+	return string(body.Data.Data), nil
+}
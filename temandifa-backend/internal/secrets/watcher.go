@@ -0,0 +1,112 @@
+package secrets
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ChangeHook receives the new value of a rotated secret
+type ChangeHook func(value string)
+
+// Watcher re-resolves secret references periodically and runs the hooks
+// registered for a key when its value changes. Keys without hooks keep their
+// startup value until restart; the watcher logs that a restart is needed.
+type Watcher struct {
+	resolver *Resolver
+	refs     map[string]string // config key -> reference
+	interval time.Duration
+	log      *zap.Logger
+
+	mu       sync.Mutex
+	values   map[string]string
+	hooks    map[string][]ChangeHook
+	stopChan chan struct{}
+}
+
+// NewWatcher creates a watcher for refs, whose resolved startup values are
+// given in values
+func NewWatcher(resolver *Resolver, refs, values map[string]string, interval time.Duration, log *zap.Logger) *Watcher {
+	current := make(map[string]string, len(values))
+	for key, value := range values {
+		current[key] = value
+	}
+	return &Watcher{
+		resolver: resolver,
+		refs:     refs,
+		interval: interval,
+		log:      log,
+		values:   current,
+		hooks:    make(map[string][]ChangeHook),
+		stopChan: make(chan struct{}),
+	}
+}
+
+// OnChange registers fn to run when the secret behind config key changes.
+// It does nothing for keys that aren't secret references.
+func (w *Watcher) OnChange(key string, fn ChangeHook) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.hooks[key] = append(w.hooks[key], fn)
+}
+
+// Start refreshes on every interval. It is a no-op without references or
+// when the interval is zero.
+func (w *Watcher) Start() {
+	if len(w.refs) == 0 || w.interval <= 0 {
+		return
+	}
+	w.log.Info("Secret refresh started",
+		zap.Int("secrets", len(w.refs)),
+		zap.Duration("interval", w.interval),
+	)
+
+	ticker := time.NewTicker(w.interval)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				w.Refresh(context.Background())
+			case <-w.stopChan:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the periodic refresh
+func (w *Watcher) Stop() {
+	close(w.stopChan)
+}
+
+// Refresh re-resolves every reference once. Failures keep the previous value.
+func (w *Watcher) Refresh(ctx context.Context) {
+	for key, ref := range w.refs {
+		value, err := w.resolver.Resolve(ctx, ref)
+		if err != nil {
+			w.log.Warn("Failed to refresh secret", zap.String("key", key), zap.Error(err))
+			continue
+		}
+
+		w.mu.Lock()
+		changed := w.values[key] != value
+		w.values[key] = value
+		hooks := append([]ChangeHook(nil), w.hooks[key]...)
+		w.mu.Unlock()
+
+		if !changed {
+			continue
+		}
+		if len(hooks) == 0 {
+			w.log.Warn("Secret changed; restart to apply it", zap.String("key", key))
+			continue
+		}
+		w.log.Info("Secret changed, applying", zap.String("key", key))
+		for _, fn := range hooks {
+			fn(value)
+		}
+	}
+}
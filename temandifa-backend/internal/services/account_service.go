@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/repositories"
+)
+
+// OutboxUserDeleted tells integrations to erase what they hold of the user
+const OutboxUserDeleted = "user.deleted"
+
+var (
+	errAccountNotFound      = apperrors.NewAppError(apperrors.ErrCodeNotFound, "User not found", http.StatusNotFound)
+	errAccountPasswordWrong = apperrors.NewAppError(apperrors.ErrCodeInvalidCredentials, "Incorrect password", http.StatusUnauthorized)
+	errAccountNoPassword    = apperrors.NewAppError(apperrors.ErrCodeInvalidInput, "This account has no password; set one with forgot-password", http.StatusBadRequest)
+)
+
+// AccountService lets users change their password and delete their own
+// account
+type AccountService interface {
+	// ChangePassword replaces the user's password after checking the current
+	// one, and signs out every session but sessionID, the caller's
+	ChangePassword(ctx context.Context, userID, sessionID uint, currentPassword, newPassword string) error
+	// Delete soft-deletes the user, signs it out everywhere (accessToken, the
+	// caller's, included) and purges its history right away. The user purge
+	// job hard-deletes the rest after USER_PURGE_GRACE_PERIOD.
+	Delete(ctx context.Context, userID uint, password, accessToken string) (*dto.AccountDeletion, error)
+}
+
+type accountService struct {
+	users         repositories.UserRepository
+	history       HistoryService
+	tokens        TokenService
+	blacklist     *TokenBlacklist
+	userCache     UserCacheService
+	outbox        OutboxService
+	notifications NotificationService
+	txManager     database.TxManager
+	cfg           *config.Config
+}
+
+// NewAccountService creates a new AccountService
+func NewAccountService(users repositories.UserRepository, history HistoryService, tokens TokenService, blacklist *TokenBlacklist, userCache UserCacheService, outbox OutboxService, notifications NotificationService, txManager database.TxManager, cfg *config.Config) AccountService {
+	return &accountService{
+		users:         users,
+		history:       history,
+		tokens:        tokens,
+		blacklist:     blacklist,
+		userCache:     userCache,
+		outbox:        outbox,
+		notifications: notifications,
+		txManager:     txManager,
+		cfg:           cfg,
+	}
+}
+
+func (s *accountService) ChangePassword(ctx context.Context, userID, sessionID uint, currentPassword, newPassword string) error {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return apperrors.Database(err)
+	}
+	if user == nil {
+		return errAccountNotFound
+	}
+	// Accounts that only sign in with a provider or a link prove access to
+	// their email instead, through a reset
+	if user.Password == "" {
+		return errAccountNoPassword
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(currentPassword)) != nil {
+		return errAccountPasswordWrong
+	}
+	if issues := helpers.ValidatePasswordStrength(newPassword); len(issues) > 0 {
+		return apperrors.ValidationWithDetails("Password does not meet security requirements", issues)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(newPassword)) == nil {
+		return apperrors.ValidationWithDetails("Password unchanged", map[string]string{"new_password": "must differ from the current password"})
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+	user.Password = string(hashedPassword)
+	if err := s.users.Update(ctx, user); err != nil {
+		if _, ok := apperrors.AsAppError(err); ok {
+			return err
+		}
+		return apperrors.Database(err)
+	}
+
+	// Whoever knew the old password is signed out everywhere else
+	log := logger.Ctx(ctx).With(zap.Uint("user_id", user.ID))
+	revoked, err := s.tokens.RevokeOtherUserTokens(ctx, user.ID, sessionID)
+	if err != nil {
+		log.Error("Failed to sign out other sessions after password change", zap.Error(err))
+	}
+	if err := s.userCache.InvalidateUserCache(ctx, user.ID); err != nil {
+		log.Debug("Failed to invalidate user cache", zap.Error(err))
+	}
+	log.Info("Password changed", zap.Int64("sessions_revoked", revoked))
+
+	s.notifications.NotifyUser(ctx, user.ID, Notification{
+		Type:  NotificationPasswordChanged,
+		Title: "Your password was changed",
+		Body:  "Your TemanDifa password was changed and your other devices were signed out. If this wasn't you, reset your password and contact support right away.",
+	})
+	return nil
+}
+
+func (s *accountService) Delete(ctx context.Context, userID uint, password, accessToken string) (*dto.AccountDeletion, error) {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.Database(err)
+	}
+	if user == nil {
+		return nil, errAccountNotFound
+	}
+	// A stolen access token alone mustn't be enough to delete the account
+	if user.Password != "" && bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+		return nil, errAccountPasswordWrong
+	}
+
+	now := time.Now()
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		deleted, err := s.users.SoftDelete(ctx, user.ID, now)
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			return errAccountNotFound
+		}
+		return s.outbox.Enqueue(ctx, OutboxUserDeleted, "user", strconv.FormatUint(uint64(user.ID), 10), map[string]any{
+			"user_id":    user.ID,
+			"deleted_at": now,
+		})
+	})
+	if err != nil {
+		if _, ok := apperrors.AsAppError(err); ok {
+			return nil, err
+		}
+		return nil, apperrors.Database(err)
+	}
+
+	// The account is deleted from here on, so failures below are only logged:
+	// the auth middleware already rejects the user, and the purge job removes
+	// whatever is left
+	log := logger.Ctx(ctx).With(zap.Uint("user_id", user.ID))
+	if err := s.tokens.RevokeAllUserTokens(user.ID); err != nil {
+		log.Error("Failed to revoke tokens of deleted account", zap.Error(err))
+	}
+	if accessToken != "" {
+		if err := s.blacklist.Add(ctx, accessToken, AccessTokenDuration); err != nil {
+			log.Warn("Failed to blacklist access token of deleted account", zap.Error(err))
+		}
+	}
+	if err := s.userCache.InvalidateUserCache(ctx, user.ID); err != nil {
+		log.Debug("Failed to invalidate user cache", zap.Error(err))
+	}
+	cleared, err := s.history.ClearUserHistory(ctx, user.ID)
+	if err != nil {
+		log.Error("Failed to purge history of deleted account", zap.Error(err))
+	}
+	log.Info("Account deleted", zap.Int64("history_cleared", cleared))
+
+	return &dto.AccountDeletion{
+		DeletedAt:  now,
+		PurgeAfter: now.Add(s.cfg.UserPurgeGracePeriod),
+	}, nil
+}
@@ -4,16 +4,16 @@ import (
 	"context"
 	"fmt"
 	"time"
+	"unicode/utf8"
 
 	"github.com/goccy/go-json"
 	"github.com/sony/gobreaker"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/status"
 
-	"temandifa-backend/internal/cache"
 	"temandifa-backend/internal/clients"
-	"temandifa-backend/internal/logger"
-	"temandifa-backend/internal/metrics"
+	"temandifa-backend/internal/config"
+	apperrors "temandifa-backend/internal/errors"
 )
 
 type AIService interface {
@@ -21,39 +21,28 @@ type AIService interface {
 	ExtractText(ctx context.Context, fileContent []byte, filename string, lang string) (interface{}, bool, error)
 	TranscribeAudio(ctx context.Context, fileContent []byte, filename string) (interface{}, bool, error)
 	VisualQuestionAnswering(ctx context.Context, fileContent []byte, filename string, question string) (interface{}, bool, error)
+	// SimplifyText rewrites text at an easy reading level and chunks it for TTS
+	// playback; text longer than SIMPLIFY_MAX_CHARS is a validation error
+	SimplifyText(ctx context.Context, text string, lang string, level string) (interface{}, bool, error)
 }
 
 type aiService struct {
 	grpcClient   *clients.AIClient
 	cacheService CacheService
+	cacheTTLs    *config.CacheConfig
+	aiCfg        *config.AIConfig
+	plans        PlanService
+	log          *zap.Logger
 	// Separate circuit breakers per operation for fault isolation
 	detectCB     *gobreaker.CircuitBreaker
 	ocrCB        *gobreaker.CircuitBreaker
 	transcribeCB *gobreaker.CircuitBreaker
 	vqaCB        *gobreaker.CircuitBreaker
-}
-
-// onCircuitStateChange handles circuit breaker state changes with logging and metrics
-func onCircuitStateChange(name string, from gobreaker.State, to gobreaker.State) {
-	logger.Warn("Circuit Breaker state changed",
-		zap.String("name", name),
-		zap.String("from", from.String()),
-		zap.String("to", to.String()),
-	)
-	// Update Prometheus metrics
-	// State mapping: Closed=0, Open=1, HalfOpen=2
-	stateValue := 0
-	switch to {
-	case gobreaker.StateOpen:
-		stateValue = 1
-	case gobreaker.StateHalfOpen:
-		stateValue = 2
-	}
-	metrics.UpdateCircuitBreakerState(name, stateValue)
+	simplifyCB   *gobreaker.CircuitBreaker
 }
 
 // newCircuitBreaker creates a circuit breaker with standard settings
-func newCircuitBreaker(name string) *gobreaker.CircuitBreaker {
+func newCircuitBreaker(name string, monitor *circuitBreakerMonitor) *gobreaker.CircuitBreaker {
 	return gobreaker.NewCircuitBreaker(gobreaker.Settings{
 		Name:        name,
 		MaxRequests: 3,                // Max requests in half-open state
@@ -61,21 +50,37 @@ func newCircuitBreaker(name string) *gobreaker.CircuitBreaker {
 		Timeout:     30 * time.Second, // Wait time before half-open
 		ReadyToTrip: func(counts gobreaker.Counts) bool {
 			failureRatio := float64(counts.TotalFailures) / float64(counts.Requests)
-			return counts.Requests >= 5 && failureRatio >= 0.6
+			trip := counts.Requests >= 5 && failureRatio >= 0.6
+			if trip {
+				monitor.recordTrip(name, counts)
+			}
+			return trip
 		},
-		OnStateChange: onCircuitStateChange,
+		IsSuccessful: func(err error) bool {
+			if err != nil {
+				monitor.recordError(name, err)
+			}
+			return err == nil
+		},
+		OnStateChange: monitor.onStateChange,
 	})
 }
 
-func NewAIService(grpcClient *clients.AIClient, cacheService CacheService) AIService {
+func NewAIService(grpcClient *clients.AIClient, cacheService CacheService, cacheCfg *config.CacheConfig, aiCfg *config.AIConfig, plans PlanService, alertNotifier AlertNotifier, log *zap.Logger) AIService {
+	monitor := newCircuitBreakerMonitor(alertNotifier, log)
 	return &aiService{
 		grpcClient:   grpcClient,
 		cacheService: cacheService,
+		cacheTTLs:    cacheCfg,
+		aiCfg:        aiCfg,
+		plans:        plans,
+		log:          log,
 		// Create separate circuit breakers for each operation type
-		detectCB:     newCircuitBreaker("ai-detect"),
-		ocrCB:        newCircuitBreaker("ai-ocr"),
-		transcribeCB: newCircuitBreaker("ai-transcribe"),
-		vqaCB:        newCircuitBreaker("ai-vqa"),
+		detectCB:     newCircuitBreaker("ai-detect", monitor),
+		ocrCB:        newCircuitBreaker("ai-ocr", monitor),
+		transcribeCB: newCircuitBreaker("ai-transcribe", monitor),
+		vqaCB:        newCircuitBreaker("ai-vqa", monitor),
+		simplifyCB:   newCircuitBreaker("ai-simplify", monitor),
 	}
 }
 
@@ -109,7 +114,7 @@ func (s *aiService) DetectObjects(ctx context.Context, fileContent []byte, filen
 		if err == nil {
 			// Create a detached context for async cache set
 			detachedCtx := context.WithoutCancel(ctx)
-			s.cacheService.SetAsync(detachedCtx, cacheKey, jsonBytes, cache.Config.DetectionTTL)
+			s.cacheService.SetAsync(detachedCtx, cacheKey, jsonBytes, s.plans.CacheTTL(ctx, s.cacheTTLs.DetectionTTL))
 		}
 	}()
 
@@ -145,7 +150,7 @@ func (s *aiService) ExtractText(ctx context.Context, fileContent []byte, filenam
 		jsonBytes, err := json.Marshal(result)
 		if err == nil {
 			detachedCtx := context.WithoutCancel(ctx)
-			s.cacheService.SetAsync(detachedCtx, cacheKey, jsonBytes, cache.Config.OCRTTL)
+			s.cacheService.SetAsync(detachedCtx, cacheKey, jsonBytes, s.plans.CacheTTL(ctx, s.cacheTTLs.OCRTTL))
 		}
 	}()
 
@@ -181,7 +186,7 @@ func (s *aiService) TranscribeAudio(ctx context.Context, fileContent []byte, fil
 		jsonBytes, err := json.Marshal(result)
 		if err == nil {
 			detachedCtx := context.WithoutCancel(ctx)
-			s.cacheService.SetAsync(detachedCtx, cacheKey, jsonBytes, cache.Config.TranscriptionTTL)
+			s.cacheService.SetAsync(detachedCtx, cacheKey, jsonBytes, s.plans.CacheTTL(ctx, s.cacheTTLs.TranscriptionTTL))
 		}
 	}()
 
@@ -193,16 +198,12 @@ func (s *aiService) handleError(err error) error {
 		return err
 	}
 
-	st, ok := status.FromError(err)
-	if ok {
-		logger.Error("AI Service gRPC failed",
-			zap.String("code", st.Code().String()),
-			zap.String("message", st.Message()),
-		)
+	// gRPC failures are already logged with method and code by the client interceptors
+	if st, ok := status.FromError(err); ok {
 		return fmt.Errorf("AI Service error: %s", st.Message())
 	}
 
-	logger.Error("AI Service call failed", zap.Error(err))
+	s.log.Error("AI Service call failed", zap.Error(err))
 	return err
 }
 
@@ -235,7 +236,55 @@ func (s *aiService) VisualQuestionAnswering(ctx context.Context, fileContent []b
 		jsonBytes, err := json.Marshal(result)
 		if err == nil {
 			detachedCtx := context.WithoutCancel(ctx)
-			s.cacheService.SetAsync(detachedCtx, cacheKey, jsonBytes, cache.Config.VQATTL)
+			s.cacheService.SetAsync(detachedCtx, cacheKey, jsonBytes, s.plans.CacheTTL(ctx, s.cacheTTLs.VQATTL))
+		}
+	}()
+
+	return result, false, nil
+}
+
+func (s *aiService) SimplifyText(ctx context.Context, text string, lang string, level string) (interface{}, bool, error) {
+	if utf8.RuneCountInString(text) > s.aiCfg.SimplifyMaxChars {
+		return nil, false, apperrors.ValidationWithDetails("Text too long", map[string]string{
+			"text": fmt.Sprintf("must be at most %d characters", s.aiCfg.SimplifyMaxChars),
+		})
+	}
+
+	cacheKey := s.cacheService.GenerateKey("simplify", []byte(lang+"\x00"+level+"\x00"+text))
+	if result, hit := s.cacheService.Get(ctx, cacheKey); hit {
+		var cachedData interface{}
+		if err := json.Unmarshal(result, &cachedData); err == nil {
+			return cachedData, true, nil
+		}
+	}
+
+	simplified := false
+	result, err := s.simplifyCB.Execute(func() (interface{}, error) {
+		resp, err := s.grpcClient.SimplifyText(ctx, text, lang, level, s.aiCfg.SimplifyChunkChars)
+		if err != nil {
+			return nil, err
+		}
+		if !resp.Success {
+			return nil, fmt.Errorf("simplification error: %s", resp.Message)
+		}
+		simplified = resp.Simplified
+		return resp, nil
+	})
+
+	if err != nil {
+		return nil, false, s.handleError(err)
+	}
+
+	// Text the AI Service could only chunk is retried on the next request
+	if !simplified {
+		return result, false, nil
+	}
+
+	go func() {
+		jsonBytes, err := json.Marshal(result)
+		if err == nil {
+			detachedCtx := context.WithoutCancel(ctx)
+			s.cacheService.SetAsync(detachedCtx, cacheKey, jsonBytes, s.plans.CacheTTL(ctx, s.cacheTTLs.SimplifyTTL))
 		}
 	}()
 
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/logger"
+)
+
+// alertSendTimeout bounds a single webhook delivery
+const alertSendTimeout = 10 * time.Second
+
+// Alert is an operational alert delivered to configured webhooks
+type Alert struct {
+	Key       string         `json:"key"` // Alerts with the same key share a cooldown
+	Title     string         `json:"title"`
+	Severity  string         `json:"severity"` // warning, critical
+	Source    string         `json:"source"`
+	Details   map[string]any `json:"details,omitempty"`
+	Timestamp string         `json:"timestamp"`
+}
+
+// AlertNotifier sends operational alerts to webhook and Slack endpoints
+type AlertNotifier interface {
+	// Notify delivers the alert asynchronously. It returns false when the alert
+	// was suppressed by the cooldown or no endpoints are configured.
+	Notify(alert Alert) bool
+}
+
+type alertNotifier struct {
+	webhookURLs []string
+	slackURL    string
+	secret      string
+	cooldown    time.Duration
+	client      *http.Client
+	log         *zap.Logger
+
+	mu       sync.Mutex
+	lastSent map[string]time.Time
+}
+
+// NewAlertNotifier creates an alert notifier from configuration
+func NewAlertNotifier(cfg *config.Config, log *zap.Logger) AlertNotifier {
+	return &alertNotifier{
+		webhookURLs: cfg.AlertWebhookURLs,
+		slackURL:    cfg.AlertSlackWebhookURL,
+		secret:      cfg.AlertWebhookSecret,
+		cooldown:    cfg.AlertCooldown,
+		client:      &http.Client{Timeout: alertSendTimeout},
+		log:         log,
+		lastSent:    make(map[string]time.Time),
+	}
+}
+
+func (n *alertNotifier) Notify(alert Alert) bool {
+	if len(n.webhookURLs) == 0 && n.slackURL == "" {
+		return false
+	}
+	if !n.allow(alert.Key, time.Now()) {
+		n.log.Debug("Alert suppressed by cooldown", zap.String("key", alert.Key))
+		return false
+	}
+
+	if alert.Timestamp == "" {
+		alert.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	go n.deliver(alert)
+	return true
+}
+
+// allow enforces the per-key cooldown to prevent alert storms
+func (n *alertNotifier) allow(key string, now time.Time) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if last, ok := n.lastSent[key]; ok && now.Sub(last) < n.cooldown {
+		return false
+	}
+	n.lastSent[key] = now
+	return true
+}
+
+// deliver sends the alert to every configured endpoint
+func (n *alertNotifier) deliver(alert Alert) {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		n.log.Error("Failed to marshal alert", zap.Error(err))
+		return
+	}
+
+	for _, url := range n.webhookURLs {
+		if err := n.post(url, body, true); err != nil {
+			n.log.Error("Failed to deliver alert webhook",
+				zap.String("key", alert.Key),
+				zap.Error(err),
+			)
+		}
+	}
+
+	if n.slackURL != "" {
+		slackBody, _ := json.Marshal(map[string]string{"text": formatSlackAlert(alert)})
+		if err := n.post(n.slackURL, slackBody, false); err != nil {
+			n.log.Error("Failed to deliver Slack alert",
+				zap.String("key", alert.Key),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// post sends a JSON body; generic webhooks are signed with HMAC-SHA256 over
+// "<timestamp>.<body>" so receivers can verify origin and reject replays
+func (n *alertNotifier) post(url string, body []byte, sign bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), alertSendTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if sign && n.secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set("X-Temandifa-Timestamp", timestamp)
+		req.Header.Set("X-Temandifa-Signature", "sha256="+signPayload(n.secret, timestamp, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// signPayload computes the hex HMAC-SHA256 signature of a timestamped payload
+func signPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// formatSlackAlert renders an alert as a Slack message
+func formatSlackAlert(alert Alert) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, ":rotating_light: *[%s] %s*\n", strings.ToUpper(alert.Severity), alert.Title)
+	fmt.Fprintf(&sb, "Source: `%s` at %s", alert.Source, alert.Timestamp)
+	keys := make([]string, 0, len(alert.Details))
+	for key := range alert.Details {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&sb, "\n• *%s*: %v", key, alert.Details[key])
+	}
+	return sb.String()
+}
+
+// maxAlertStackBytes bounds stack traces included in panic alerts
+const maxAlertStackBytes = 4096
+
+// NewPanicReporter forwards recovered panics as alerts. Panics are grouped by
+// fingerprint, so a recurring bug alerts once per cooldown instead of per request.
+func NewPanicReporter(notifier AlertNotifier) logger.PanicReporter {
+	return func(report logger.PanicReport) {
+		stack := report.Stack
+		if len(stack) > maxAlertStackBytes {
+			stack = stack[:maxAlertStackBytes] + "\n...[truncated]"
+		}
+
+		notifier.Notify(Alert{
+			Key:      "panic:" + report.Fingerprint,
+			Title:    "Panic in " + report.Method + " " + report.Route,
+			Severity: "critical",
+			Source:   "temandifa-backend",
+			Details: map[string]any{
+				"fingerprint": report.Fingerprint,
+				"panic":       report.Value,
+				"request_id":  report.RequestID,
+				"path":        report.Path,
+				"stack":       stack,
+			},
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"golang.org/x/text/language"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+)
+
+// defaultAnnouncementLocale is shown when none of the caller's locales has a
+// translation (the app's primary audience is Indonesian)
+var defaultAnnouncementLocale = language.Indonesian
+
+// Audit actions and target type of announcement management
+const (
+	auditTargetAnnouncement  = "announcement"
+	auditAnnouncementCreated = "announcement.created"
+	auditAnnouncementUpdated = "announcement.updated"
+	auditAnnouncementDeleted = "announcement.deleted"
+)
+
+// announcementPushWindow is how long after an announcement starts it is
+// still pushed to devices; older ones, e.g. found on first startup, are not
+const announcementPushWindow = time.Hour
+
+// AnnouncementService manages in-app announcements and serves the active ones
+// in the caller's language
+type AnnouncementService interface {
+	// Active returns the announcements shown now, each in the best match for
+	// the caller's preferred locales (most preferred first)
+	Active(preferred []language.Tag) []dto.Announcement
+	List(ctx context.Context) ([]models.Announcement, error)
+	Create(ctx context.Context, announcement *models.Announcement) error
+	// Update and Delete report false when the announcement doesn't exist
+	Update(ctx context.Context, announcement *models.Announcement) (bool, error)
+	Delete(ctx context.Context, id uint) (bool, error)
+	// Refresh reloads the unexpired announcements and pushes the ones that
+	// just started; other instances pick up changes within
+	// ANNOUNCEMENTS_REFRESH_INTERVAL
+	Refresh(ctx context.Context) error
+}
+
+// announcementState is an unexpired announcement with a matcher over its
+// translations' locales
+type announcementState struct {
+	announcement models.Announcement
+	matcher      language.Matcher
+}
+
+type announcementService struct {
+	repo          repositories.AnnouncementRepository
+	audit         AuditService
+	notifications NotificationService
+	tx            database.TxManager
+	announcements atomic.Pointer[[]announcementState]
+}
+
+// NewAnnouncementService creates a new AnnouncementService; announcements are
+// loaded by RegisterAnnouncementRefresh
+func NewAnnouncementService(repo repositories.AnnouncementRepository, audit AuditService, notifications NotificationService, tx database.TxManager) AnnouncementService {
+	s := &announcementService{repo: repo, audit: audit, notifications: notifications, tx: tx}
+	s.announcements.Store(&[]announcementState{})
+	return s
+}
+
+func (s *announcementService) Active(preferred []language.Tag) []dto.Announcement {
+	now := time.Now()
+	active := make([]dto.Announcement, 0)
+	for _, state := range *s.announcements.Load() {
+		a := state.announcement
+		if !a.Active(now) {
+			continue
+		}
+		_, index, _ := state.matcher.Match(preferred...)
+		translation := a.Translations[index]
+		active = append(active, dto.Announcement{
+			ID:       a.ID,
+			Kind:     a.Kind,
+			Locale:   translation.Locale,
+			Title:    translation.Title,
+			Body:     translation.Body,
+			StartsAt: a.StartsAt,
+			EndsAt:   a.EndsAt,
+		})
+	}
+	return active
+}
+
+func (s *announcementService) List(ctx context.Context) ([]models.Announcement, error) {
+	return s.repo.List(ctx)
+}
+
+// validate normalizes kind, start and locales and checks the window
+func (s *announcementService) validate(announcement *models.Announcement) error {
+	if announcement.Kind == "" {
+		announcement.Kind = models.AnnouncementInfo
+	}
+	if announcement.StartsAt.IsZero() {
+		announcement.StartsAt = time.Now()
+	}
+	if announcement.EndsAt != nil && !announcement.EndsAt.After(announcement.StartsAt) {
+		return apperrors.ValidationWithDetails("Invalid schedule", map[string]string{"ends_at": "must be after starts_at"})
+	}
+
+	seen := make(map[string]bool, len(announcement.Translations))
+	for i, translation := range announcement.Translations {
+		tag, err := language.Parse(translation.Locale)
+		if err != nil {
+			return apperrors.ValidationWithDetails("Invalid locale", map[string]string{"translations": "locale " + translation.Locale + " is not a BCP 47 language tag"})
+		}
+		locale := tag.String()
+		if seen[locale] {
+			return apperrors.ValidationWithDetails("Duplicate locale", map[string]string{"translations": "locale " + locale + " appears more than once"})
+		}
+		seen[locale] = true
+		announcement.Translations[i].Locale = locale
+	}
+	return nil
+}
+
+func (s *announcementService) Create(ctx context.Context, announcement *models.Announcement) error {
+	if err := s.validate(announcement); err != nil {
+		return err
+	}
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, announcement); err != nil {
+			return err
+		}
+		return s.audit.RecordChange(ctx, auditAnnouncementCreated, auditTargetAnnouncement, announcementID(announcement.ID), nil, announcement)
+	})
+	if err != nil {
+		return err
+	}
+	return s.Refresh(ctx)
+}
+
+func announcementID(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+func (s *announcementService) Update(ctx context.Context, announcement *models.Announcement) (bool, error) {
+	if err := s.validate(announcement); err != nil {
+		return false, err
+	}
+	var found bool
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.repo.Find(ctx, announcement.ID)
+		if err != nil || before == nil {
+			return err
+		}
+		if found, err = s.repo.Update(ctx, announcement); err != nil {
+			return err
+		}
+		after, err := s.repo.Find(ctx, announcement.ID)
+		if err != nil {
+			return err
+		}
+		return s.audit.RecordChange(ctx, auditAnnouncementUpdated, auditTargetAnnouncement, announcementID(announcement.ID), before, after)
+	})
+	if err != nil || !found {
+		return false, err
+	}
+	return true, s.Refresh(ctx)
+}
+
+func (s *announcementService) Delete(ctx context.Context, id uint) (bool, error) {
+	var deleted bool
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.repo.Find(ctx, id)
+		if err != nil || before == nil {
+			return err
+		}
+		if deleted, err = s.repo.Delete(ctx, id); err != nil || !deleted {
+			return err
+		}
+		return s.audit.RecordChange(ctx, auditAnnouncementDeleted, auditTargetAnnouncement, announcementID(id), before, nil)
+	})
+	if err != nil || !deleted {
+		return false, err
+	}
+	return true, s.Refresh(ctx)
+}
+
+func (s *announcementService) Refresh(ctx context.Context) error {
+	list, err := s.repo.Unexpired(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	states := make([]announcementState, 0, len(list))
+	for _, announcement := range list {
+		if len(announcement.Translations) == 0 {
+			continue
+		}
+		// The matcher falls back to its first tag, so put the default
+		// locale's translation (if any) first
+		for i, translation := range announcement.Translations {
+			if tag, err := language.Parse(translation.Locale); err == nil && tag == defaultAnnouncementLocale {
+				announcement.Translations[0], announcement.Translations[i] = announcement.Translations[i], announcement.Translations[0]
+				break
+			}
+		}
+		tags := make([]language.Tag, len(announcement.Translations))
+		for i, translation := range announcement.Translations {
+			tags[i] = language.Make(translation.Locale)
+		}
+		states = append(states, announcementState{announcement: announcement, matcher: language.NewMatcher(tags)})
+	}
+	s.announcements.Store(&states)
+
+	now := time.Now()
+	for _, state := range states {
+		a := state.announcement
+		if a.Active(now) && now.Sub(a.StartsAt) < announcementPushWindow {
+			s.notifications.BroadcastOnce(ctx, "announcement:"+announcementID(a.ID), TopicAnnouncements, announcementNotification(a))
+		}
+	}
+	return nil
+}
+
+// RegisterAnnouncementRefresh loads the announcements on startup and reloads
+// them every ANNOUNCEMENTS_REFRESH_INTERVAL
+func RegisterAnnouncementRefresh(lc fx.Lifecycle, announcements AnnouncementService, cfg *config.Config, log *zap.Logger) {
+	stopChan := make(chan struct{})
+
+	refresh := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := announcements.Refresh(ctx); err != nil {
+			log.Warn("Failed to refresh announcements", zap.Error(err))
+		}
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			refresh()
+
+			ticker := time.NewTicker(cfg.AnnouncementsRefreshInterval)
+			go func() {
+				for {
+					select {
+					case <-ticker.C:
+						refresh()
+					case <-stopChan:
+						ticker.Stop()
+						return
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stopChan)
+			return nil
+		},
+	})
+}
@@ -0,0 +1,338 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+)
+
+// Audit actions and target type of API key management
+const (
+	auditTargetAPIKey  = "api_key"
+	auditAPIKeyCreated = "api_key.created"
+	auditAPIKeyUpdated = "api_key.updated"
+	auditAPIKeyDeleted = "api_key.deleted"
+)
+
+const (
+	// apiKeyPrefix starts every API key, so leaked keys are easy to spot
+	apiKeyPrefix = "tdk_"
+	// apiKeyBytes is the entropy of generated keys
+	apiKeyBytes = 32
+	// apiKeyDisplayLength is how much of a key is kept to tell keys apart
+	apiKeyDisplayLength = 12
+	// apiKeyCacheTTL bounds how long another server keeps accepting a key
+	// after it was changed or deleted
+	apiKeyCacheTTL = 30 * time.Second
+	// apiKeyTouchInterval throttles the last_used_at updates of a key
+	apiKeyTouchInterval = time.Minute
+)
+
+// APIKeyScopes are the AI operations API keys can be scoped to
+var APIKeyScopes = config.AIOperations
+
+// APIKeyService manages the long-lived keys partner apps call the AI
+// endpoints with instead of access tokens. A key acts as its owner user,
+// whose plan quotas apply, limited to its scopes and its own rate limit.
+type APIKeyService interface {
+	List(ctx context.Context) ([]dto.APIKey, error)
+	// Get returns nil when the key doesn't exist
+	Get(ctx context.Context, id uint64) (*dto.APIKey, error)
+	// Create returns the key, the only time it is shown
+	Create(ctx context.Context, input dto.CreateAPIKeyRequest) (*dto.APIKey, error)
+	// Update returns nil when the key doesn't exist
+	Update(ctx context.Context, id uint64, input dto.UpdateAPIKeyRequest) (*dto.APIKey, error)
+	// Delete revokes the key, reporting false when it doesn't exist
+	Delete(ctx context.Context, id uint64) (bool, error)
+	// Authenticate returns the key, nil when it is unknown or expired. Keys
+	// are cached for apiKeyCacheTTL, and their use is recorded.
+	Authenticate(ctx context.Context, key string) (*models.APIKey, error)
+}
+
+// cachedAPIKey is a key as loaded at loadedAt
+type cachedAPIKey struct {
+	key      *models.APIKey
+	loadedAt time.Time
+}
+
+type apiKeyService struct {
+	repo  repositories.APIKeyRepository
+	users repositories.UserRepository
+	audit AuditService
+	tx    database.TxManager
+
+	mu   sync.Mutex
+	keys map[string]*cachedAPIKey // By key hash
+}
+
+// NewAPIKeyService creates a new APIKeyService
+func NewAPIKeyService(repo repositories.APIKeyRepository, users repositories.UserRepository, audit AuditService, tx database.TxManager) APIKeyService {
+	return &apiKeyService{
+		repo:  repo,
+		users: users,
+		audit: audit,
+		tx:    tx,
+		keys:  make(map[string]*cachedAPIKey),
+	}
+}
+
+// apiKeyHash is the stored form of an API key
+func apiKeyHash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// newAPIKey generates a key
+func newAPIKey() (string, error) {
+	b := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return apiKeyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// apiKeyScopes decodes a key's scopes
+func apiKeyScopes(key *models.APIKey) []string {
+	var scopes []string
+	_ = json.Unmarshal([]byte(key.Scopes), &scopes)
+	return scopes
+}
+
+// APIKeyHasScope reports whether the key may use the AI operation scope
+func APIKeyHasScope(key *models.APIKey, scope string) bool {
+	return slices.Contains(apiKeyScopes(key), scope)
+}
+
+func toAPIKey(key *models.APIKey) dto.APIKey {
+	return dto.APIKey{
+		ID:         key.ID,
+		Name:       key.Name,
+		UserID:     key.UserID,
+		Prefix:     key.Prefix,
+		Scopes:     apiKeyScopes(key),
+		RateLimit:  key.RateLimit,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedBy:  key.CreatedBy,
+		CreatedAt:  key.CreatedAt,
+		UpdatedAt:  key.UpdatedAt,
+	}
+}
+
+// validateAPIKey checks the scopes and expiry, returning the scopes sorted
+// and deduplicated as JSON
+func validateAPIKey(scopes []string, expiresAt *time.Time) (string, error) {
+	scopes = slices.Clone(scopes)
+	for _, scope := range scopes {
+		if !slices.Contains(APIKeyScopes, scope) {
+			return "", apperrors.ValidationWithDetails("Unknown scope", map[string]string{
+				"scopes": fmt.Sprintf("%s is not one of %s", scope, strings.Join(APIKeyScopes, ", ")),
+			})
+		}
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return "", apperrors.ValidationWithDetails("Invalid expiry", map[string]string{"expires_at": "must be in the future"})
+	}
+	slices.Sort(scopes)
+	encoded, err := json.Marshal(slices.Compact(scopes))
+	return string(encoded), err
+}
+
+// forget drops the cached keys, so changes apply on this server at once
+func (s *apiKeyService) forget() {
+	s.mu.Lock()
+	clear(s.keys)
+	s.mu.Unlock()
+}
+
+func (s *apiKeyService) List(ctx context.Context) ([]dto.APIKey, error) {
+	keys, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.APIKey, len(keys))
+	for i := range keys {
+		result[i] = toAPIKey(&keys[i])
+	}
+	return result, nil
+}
+
+func (s *apiKeyService) Get(ctx context.Context, id uint64) (*dto.APIKey, error) {
+	key, err := s.repo.Find(ctx, id)
+	if err != nil || key == nil {
+		return nil, err
+	}
+	result := toAPIKey(key)
+	return &result, nil
+}
+
+func (s *apiKeyService) Create(ctx context.Context, input dto.CreateAPIKeyRequest) (*dto.APIKey, error) {
+	scopes, err := validateAPIKey(input.Scopes, input.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	owner, err := s.users.FindByID(ctx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if owner == nil {
+		return nil, apperrors.ValidationWithDetails("Unknown user", map[string]string{"user_id": "no such user"})
+	}
+	secret, err := newAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key := &models.APIKey{
+		Name:      input.Name,
+		UserID:    owner.ID,
+		Prefix:    secret[:apiKeyDisplayLength],
+		KeyHash:   apiKeyHash(secret),
+		Scopes:    scopes,
+		RateLimit: input.RateLimit,
+		ExpiresAt: input.ExpiresAt,
+	}
+	if actorID := logger.UserIDFromContext(ctx); actorID != 0 {
+		key.CreatedBy = &actorID
+	}
+
+	var result dto.APIKey
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, key); err != nil {
+			return err
+		}
+		result = toAPIKey(key)
+		return s.audit.RecordChange(ctx, auditAPIKeyCreated, auditTargetAPIKey, strconv.FormatUint(key.ID, 10), nil, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Key = secret
+	return &result, nil
+}
+
+func (s *apiKeyService) Update(ctx context.Context, id uint64, input dto.UpdateAPIKeyRequest) (*dto.APIKey, error) {
+	scopes, err := validateAPIKey(input.Scopes, input.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *dto.APIKey
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		key, err := s.repo.Find(ctx, id)
+		if err != nil || key == nil {
+			return err
+		}
+		before := toAPIKey(key)
+
+		key.Name = input.Name
+		key.Scopes = scopes
+		key.RateLimit = input.RateLimit
+		key.ExpiresAt = input.ExpiresAt
+		if err := s.repo.Update(ctx, key); err != nil {
+			return err
+		}
+
+		after := toAPIKey(key)
+		result = &after
+		return s.audit.RecordChange(ctx, auditAPIKeyUpdated, auditTargetAPIKey, strconv.FormatUint(id, 10), before, after)
+	})
+	if err != nil || result == nil {
+		return nil, err
+	}
+	s.forget()
+	return result, nil
+}
+
+func (s *apiKeyService) Delete(ctx context.Context, id uint64) (bool, error) {
+	var deleted bool
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		key, err := s.repo.Find(ctx, id)
+		if err != nil || key == nil {
+			return err
+		}
+		if deleted, err = s.repo.Delete(ctx, id); err != nil || !deleted {
+			return err
+		}
+		return s.audit.RecordChange(ctx, auditAPIKeyDeleted, auditTargetAPIKey, strconv.FormatUint(id, 10), toAPIKey(key), nil)
+	})
+	if deleted {
+		s.forget()
+	}
+	return deleted, err
+}
+
+func (s *apiKeyService) Authenticate(ctx context.Context, secret string) (*models.APIKey, error) {
+	if !strings.HasPrefix(secret, apiKeyPrefix) {
+		return nil, nil
+	}
+	hash := apiKeyHash(secret)
+
+	s.mu.Lock()
+	cached, ok := s.keys[hash]
+	s.mu.Unlock()
+	if !ok || time.Since(cached.loadedAt) >= apiKeyCacheTTL {
+		// Unknown keys aren't cached, so guessing can't fill the cache
+		key, err := s.repo.FindByHash(ctx, hash)
+		if err != nil || key == nil {
+			return nil, err
+		}
+		cached = &cachedAPIKey{key: key, loadedAt: time.Now()}
+		s.mu.Lock()
+		s.keys[hash] = cached
+		s.mu.Unlock()
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	key := *cached.key
+	s.mu.Unlock()
+	if key.Expired(now) {
+		return nil, nil
+	}
+	s.touch(ctx, cached, now)
+	return &key, nil
+}
+
+// touch records the use of a key, at most once per apiKeyTouchInterval
+func (s *apiKeyService) touch(ctx context.Context, cached *cachedAPIKey, now time.Time) {
+	s.mu.Lock()
+	key := cached.key
+	stale := key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval
+	if stale {
+		// Copied, since requests share the cached key
+		touched := *key
+		touched.LastUsedAt = &now
+		cached.key = &touched
+	}
+	s.mu.Unlock()
+	if !stale {
+		return
+	}
+
+	go func() {
+		if err := s.repo.Touch(context.WithoutCancel(ctx), key.ID, now); err != nil {
+			logger.Ctx(ctx).Debug("Failed to record API key use", zap.Uint64("api_key_id", key.ID), zap.Error(err))
+		}
+	}()
+}
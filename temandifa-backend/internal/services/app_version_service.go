@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"golang.org/x/mod/semver"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+)
+
+// Audit actions and target type of app version changes
+const (
+	auditTargetAppVersion  = "app_version"
+	auditAppVersionSaved   = "app_version.updated"
+	auditAppVersionDeleted = "app_version.deleted"
+)
+
+// AppPlatforms are the platforms with an app version manifest
+var AppPlatforms = []string{models.PlatformAndroid, models.PlatformIOS}
+
+// AppVersionService manages the minimum and latest app version per platform.
+// The app shows an update prompt from them, and the version gate turns away
+// versions below the minimum. Other instances pick up changes within
+// FLAGS_REFRESH_INTERVAL.
+type AppVersionService interface {
+	// Status returns the platform's manifest, compared against version when
+	// it is a valid version; nil when the platform has none
+	Status(platform, version string) *dto.AppVersionStatus
+	// List returns the manifests of every platform that has one
+	List() []models.AppVersion
+	// Save creates or replaces the manifest of platform
+	Save(ctx context.Context, version *models.AppVersion) (*models.AppVersion, error)
+	// Delete removes the manifest of platform, reporting false if it had none
+	Delete(ctx context.Context, platform string) (bool, error)
+	Refresh(ctx context.Context) error
+}
+
+type appVersionService struct {
+	repo     repositories.AppVersionRepository
+	audit    AuditService
+	tx       database.TxManager
+	versions atomic.Pointer[map[string]models.AppVersion]
+}
+
+// NewAppVersionService creates a new AppVersionService; manifests are loaded
+// by RegisterAppVersionRefresh
+func NewAppVersionService(repo repositories.AppVersionRepository, audit AuditService, tx database.TxManager) AppVersionService {
+	s := &appVersionService{
+		repo:  repo,
+		audit: audit,
+		tx:    tx,
+	}
+	s.versions.Store(&map[string]models.AppVersion{})
+	return s
+}
+
+// semverOf returns the version in x/mod/semver form ("v" prefixed), or ""
+// when it isn't a semantic version
+func semverOf(version string) string {
+	v := "v" + strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if !semver.IsValid(v) {
+		return ""
+	}
+	return v
+}
+
+func (s *appVersionService) Status(platform, version string) *dto.AppVersionStatus {
+	manifest, ok := (*s.versions.Load())[platform]
+	if !ok {
+		return nil
+	}
+
+	status := &dto.AppVersionStatus{
+		Platform:      manifest.Platform,
+		MinVersion:    manifest.MinVersion,
+		LatestVersion: manifest.LatestVersion,
+		StoreURL:      manifest.StoreURL,
+		Changelog:     manifest.Changelog,
+		UpdatedAt:     manifest.UpdatedAt,
+	}
+	if current := semverOf(version); current != "" {
+		status.CurrentVersion = version
+		status.UpdateRequired = semver.Compare(current, semverOf(manifest.MinVersion)) < 0
+		status.UpdateAvailable = semver.Compare(current, semverOf(manifest.LatestVersion)) < 0
+	}
+	return status
+}
+
+func (s *appVersionService) List() []models.AppVersion {
+	versions := *s.versions.Load()
+	result := make([]models.AppVersion, 0, len(versions))
+	for _, platform := range AppPlatforms {
+		if version, ok := versions[platform]; ok {
+			result = append(result, version)
+		}
+	}
+	return result
+}
+
+// validatePlatform rejects anything but a platform with app releases
+func validatePlatform(platform string) error {
+	if slices.Contains(AppPlatforms, platform) {
+		return nil
+	}
+	return apperrors.ValidationWithDetails("Unknown platform", map[string]string{"platform": "must be one of android, ios"})
+}
+
+// validateAppVersion checks the versions and normalizes them, e.g. v2.1 to
+// 2.1.0
+func validateAppVersion(version *models.AppVersion) error {
+	if err := validatePlatform(version.Platform); err != nil {
+		return err
+	}
+	minVersion, latestVersion := semverOf(version.MinVersion), semverOf(version.LatestVersion)
+	details := map[string]string{}
+	if minVersion == "" {
+		details["min_version"] = "must be a semantic version, e.g. 2.1.0"
+	}
+	if latestVersion == "" {
+		details["latest_version"] = "must be a semantic version, e.g. 2.4.0"
+	}
+	if len(details) == 0 && semver.Compare(minVersion, latestVersion) > 0 {
+		details["min_version"] = "must not be above latest_version"
+	}
+	if len(details) > 0 {
+		return apperrors.ValidationWithDetails("Invalid version", details)
+	}
+	version.MinVersion = strings.TrimPrefix(semver.Canonical(minVersion), "v")
+	version.LatestVersion = strings.TrimPrefix(semver.Canonical(latestVersion), "v")
+	return nil
+}
+
+func (s *appVersionService) Save(ctx context.Context, version *models.AppVersion) (*models.AppVersion, error) {
+	if err := validateAppVersion(version); err != nil {
+		return nil, err
+	}
+
+	version.UpdatedAt = time.Now()
+	if actorID := logger.UserIDFromContext(ctx); actorID != 0 {
+		version.UpdatedBy = &actorID
+	}
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.repo.Find(ctx, version.Platform)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.Save(ctx, version); err != nil {
+			return err
+		}
+		return s.audit.RecordChange(ctx, auditAppVersionSaved, auditTargetAppVersion, version.Platform, before, version)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return version, s.Refresh(ctx)
+}
+
+func (s *appVersionService) Delete(ctx context.Context, platform string) (bool, error) {
+	if err := validatePlatform(platform); err != nil {
+		return false, err
+	}
+
+	var deleted bool
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.repo.Find(ctx, platform)
+		if err != nil {
+			return err
+		}
+		if deleted, err = s.repo.Delete(ctx, platform); err != nil || !deleted {
+			return err
+		}
+		return s.audit.RecordChange(ctx, auditAppVersionDeleted, auditTargetAppVersion, platform, before, nil)
+	})
+	if err != nil || !deleted {
+		return false, err
+	}
+	return true, s.Refresh(ctx)
+}
+
+func (s *appVersionService) Refresh(ctx context.Context) error {
+	versions, err := s.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	byPlatform := make(map[string]models.AppVersion, len(versions))
+	for _, version := range versions {
+		byPlatform[version.Platform] = version
+	}
+	s.versions.Store(&byPlatform)
+	return nil
+}
+
+// RegisterAppVersionRefresh loads the app version manifests on startup and
+// reloads them with the feature flags, every FLAGS_REFRESH_INTERVAL
+func RegisterAppVersionRefresh(lc fx.Lifecycle, versions AppVersionService, cfg *config.Config, log *zap.Logger) {
+	stopChan := make(chan struct{})
+
+	refresh := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := versions.Refresh(ctx); err != nil {
+			log.Warn("Failed to refresh app versions", zap.Error(err))
+		}
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			refresh()
+
+			ticker := time.NewTicker(cfg.FeatureFlagsRefreshInterval)
+			go func() {
+				for {
+					select {
+					case <-ticker.C:
+						refresh()
+					case <-stopChan:
+						ticker.Stop()
+						return
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stopChan)
+			return nil
+		},
+	})
+}
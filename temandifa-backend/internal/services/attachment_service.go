@@ -0,0 +1,285 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/database"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/preview"
+	"temandifa-backend/internal/storage"
+)
+
+// Attachment previews
+const (
+	thumbnailMaxSide = 256 // pixels
+	waveformPoints   = 64
+)
+
+// AttachmentService keeps the images and recordings attached to history
+// entries: the files live in object storage under HISTORY_ATTACHMENT_PREFIX
+// (<prefix><user>/<entry>/...), one row each in history_attachments. Images
+// get a JPEG thumbnail stored next to them and recordings a waveform, so
+// lists can show previews without downloading the files.
+type AttachmentService interface {
+	// Attach stores a validated file (kind is image or audio) with the user's
+	// history entry
+	Attach(ctx context.Context, userID uint, historyID string, kind string, file *helpers.UploadedFile) (*models.HistoryAttachment, error)
+	// List returns the attachments of the user's history entry, oldest first
+	List(ctx context.Context, userID uint, historyID string) ([]models.HistoryAttachment, error)
+	// ListForEntries returns the attachments of the user's entries by entry
+	// ID, oldest first
+	ListForEntries(ctx context.Context, userID uint, historyIDs []uint) (map[uint][]models.HistoryAttachment, error)
+	// Get returns an attachment by ID, or nil when it or its history entry
+	// was deleted
+	Get(ctx context.Context, id uint64) (*models.HistoryAttachment, error)
+	// Delete removes one attachment; false when there was none
+	Delete(ctx context.Context, userID uint, historyID string, attachmentID uint64) (bool, error)
+	// DeleteForHistory removes the attachments of a history entry
+	DeleteForHistory(ctx context.Context, userID uint, historyID string) error
+	// DeleteForUser removes all of a user's attachments, including files left
+	// behind by entries already purged
+	DeleteForUser(ctx context.Context, userID uint) error
+}
+
+type attachmentService struct {
+	db          *gorm.DB
+	store       storage.ObjectStore
+	prefix      string
+	maxPerEntry int
+}
+
+// NewAttachmentService creates a new AttachmentService
+func NewAttachmentService(db *gorm.DB, store storage.ObjectStore, cfg *config.Config) AttachmentService {
+	return &attachmentService{
+		db:          db,
+		store:       store,
+		prefix:      cfg.HistoryAttachmentPrefix,
+		maxPerEntry: cfg.HistoryAttachmentMaxPerEntry,
+	}
+}
+
+var errHistoryEntryNotFound = apperrors.NewAppError(apperrors.ErrCodeNotFound, "History entry not found", http.StatusNotFound)
+
+// entry returns the ID of the user's (not deleted) history entry
+func (s *attachmentService) entry(ctx context.Context, userID uint, historyID string) (uint, error) {
+	id, err := strconv.ParseUint(historyID, 10, 64)
+	if err != nil {
+		return 0, errHistoryEntryNotFound
+	}
+	var count int64
+	err = database.Conn(ctx, s.db).Model(&models.History{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	if count == 0 {
+		return 0, errHistoryEntryNotFound
+	}
+	return uint(id), nil
+}
+
+func (s *attachmentService) userPrefix(userID uint) string {
+	return fmt.Sprintf("%s%d/", s.prefix, userID)
+}
+
+func (s *attachmentService) entryPrefix(userID, historyID uint) string {
+	return fmt.Sprintf("%s%d/", s.userPrefix(userID), historyID)
+}
+
+func (s *attachmentService) Attach(ctx context.Context, userID uint, historyID string, kind string, file *helpers.UploadedFile) (*models.HistoryAttachment, error) {
+	if s.store == nil {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeServiceUnavailable, "History attachments need object storage", http.StatusServiceUnavailable)
+	}
+	entryID, err := s.entry(ctx, userID, historyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var count int64
+	err = database.Conn(ctx, s.db).Model(&models.HistoryAttachment{}).
+		Where("history_id = ?", entryID).
+		Count(&count).Error
+	if err != nil {
+		return nil, err
+	}
+	if count >= int64(s.maxPerEntry) {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeConflict, fmt.Sprintf("A history entry can have at most %d attachments", s.maxPerEntry), http.StatusConflict)
+	}
+
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	key := s.entryPrefix(userID, entryID) + hex.EncodeToString(b)
+	size, err := s.store.Put(ctx, key, bytes.NewReader(file.Content), file.Size, file.MimeType)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment := &models.HistoryAttachment{
+		HistoryID:   entryID,
+		UserID:      userID,
+		Kind:        kind,
+		ContentType: file.MimeType,
+		SizeBytes:   size,
+		Filename:    file.Filename,
+		ObjectKey:   key,
+	}
+	s.addPreview(ctx, attachment, file.Content)
+	if err := database.Conn(ctx, s.db).Create(attachment).Error; err != nil {
+		cleanup := context.WithoutCancel(ctx)
+		_ = s.store.Delete(cleanup, key)
+		if attachment.ThumbnailKey != "" {
+			_ = s.store.Delete(cleanup, attachment.ThumbnailKey)
+		}
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// addPreview stores a thumbnail of an image or computes the waveform of a
+// recording. Previews are optional: formats that can't be read (WebP images,
+// compressed audio) and failures leave the attachment without one.
+func (s *attachmentService) addPreview(ctx context.Context, attachment *models.HistoryAttachment, content []byte) {
+	switch attachment.Kind {
+	case models.AttachmentImage:
+		thumbnail, err := preview.Thumbnail(content, thumbnailMaxSide)
+		if err != nil {
+			if !errors.Is(err, preview.ErrUnsupported) {
+				logger.Ctx(ctx).Warn("Failed to make attachment thumbnail", zap.Error(err))
+			}
+			return
+		}
+		key := attachment.ObjectKey + ".thumb.jpg"
+		if _, err := s.store.Put(ctx, key, bytes.NewReader(thumbnail), int64(len(thumbnail)), "image/jpeg"); err != nil {
+			logger.Ctx(ctx).Warn("Failed to store attachment thumbnail", zap.Error(err))
+			return
+		}
+		attachment.ThumbnailKey = key
+	case models.AttachmentAudio:
+		peaks, err := preview.Waveform(content, waveformPoints)
+		if err != nil {
+			return
+		}
+		encoded, _ := json.Marshal(peaks)
+		attachment.Waveform = string(encoded)
+	}
+}
+
+func (s *attachmentService) List(ctx context.Context, userID uint, historyID string) ([]models.HistoryAttachment, error) {
+	entryID, err := s.entry(ctx, userID, historyID)
+	if err != nil {
+		return nil, err
+	}
+	attachments := []models.HistoryAttachment{}
+	err = database.Conn(ctx, s.db).
+		Where("history_id = ?", entryID).
+		Order("id").
+		Find(&attachments).Error
+	return attachments, err
+}
+
+func (s *attachmentService) ListForEntries(ctx context.Context, userID uint, historyIDs []uint) (map[uint][]models.HistoryAttachment, error) {
+	byEntry := make(map[uint][]models.HistoryAttachment)
+	if len(historyIDs) == 0 {
+		return byEntry, nil
+	}
+	var attachments []models.HistoryAttachment
+	err := database.Conn(ctx, s.db).
+		Where("user_id = ? AND history_id IN ?", userID, historyIDs).
+		Order("id").
+		Find(&attachments).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, attachment := range attachments {
+		byEntry[attachment.HistoryID] = append(byEntry[attachment.HistoryID], attachment)
+	}
+	return byEntry, nil
+}
+
+// find returns nil when the user's entry has no such attachment
+func (s *attachmentService) find(ctx context.Context, userID uint, historyID string, attachmentID uint64) (*models.HistoryAttachment, error) {
+	entryID, err := s.entry(ctx, userID, historyID)
+	if err != nil {
+		return nil, err
+	}
+	var attachment models.HistoryAttachment
+	err = database.Conn(ctx, s.db).
+		Where("id = ? AND history_id = ?", attachmentID, entryID).
+		Limit(1).Find(&attachment).Error
+	if err != nil || attachment.ID == 0 {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (s *attachmentService) Get(ctx context.Context, id uint64) (*models.HistoryAttachment, error) {
+	var attachment models.HistoryAttachment
+	err := database.Conn(ctx, s.db).
+		Joins("JOIN histories ON histories.id = history_attachments.history_id AND histories.deleted_at IS NULL").
+		Where("history_attachments.id = ?", id).
+		Limit(1).Find(&attachment).Error
+	if err != nil || attachment.ID == 0 {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (s *attachmentService) Delete(ctx context.Context, userID uint, historyID string, attachmentID uint64) (bool, error) {
+	attachment, err := s.find(ctx, userID, historyID, attachmentID)
+	if err != nil || attachment == nil {
+		return false, err
+	}
+	if s.store != nil {
+		for _, key := range []string{attachment.ObjectKey, attachment.ThumbnailKey} {
+			if key == "" {
+				continue
+			}
+			if err := s.store.Delete(ctx, key); err != nil {
+				return false, err
+			}
+		}
+	}
+	return true, database.Conn(ctx, s.db).Delete(attachment).Error
+}
+
+func (s *attachmentService) DeleteForHistory(ctx context.Context, userID uint, historyID string) error {
+	id, err := strconv.ParseUint(historyID, 10, 64)
+	if err != nil {
+		return nil
+	}
+	if s.store != nil {
+		if _, err := storage.DeletePrefix(ctx, s.store, s.entryPrefix(userID, uint(id))); err != nil {
+			return err
+		}
+	}
+	return database.Conn(ctx, s.db).
+		Where("history_id = ? AND user_id = ?", id, userID).
+		Delete(&models.HistoryAttachment{}).Error
+}
+
+func (s *attachmentService) DeleteForUser(ctx context.Context, userID uint) error {
+	if s.store != nil {
+		if _, err := storage.DeletePrefix(ctx, s.store, s.userPrefix(userID)); err != nil {
+			return err
+		}
+	}
+	return database.Conn(ctx, s.db).
+		Where("user_id = ?", userID).
+		Delete(&models.HistoryAttachment{}).Error
+}
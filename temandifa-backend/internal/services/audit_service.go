@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"temandifa-backend/internal/dto"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+)
+
+// AuditService records administrative changes. The actor and request ID
+// come from the request context set by the auth and request ID middleware.
+type AuditService interface {
+	// Record stores one change; call it inside the change's transaction so
+	// the entry is written if and only if the change is
+	Record(ctx context.Context, action, targetType, targetID string, details any) error
+	// RecordChange is Record for changes to a stored target, keeping JSON
+	// snapshots of it before and after the change. Pass a nil before for a
+	// creation and a nil after for a deletion.
+	RecordChange(ctx context.Context, action, targetType, targetID string, before, after any) error
+	// Find returns the entry with the field changes between its snapshots,
+	// or nil when it doesn't exist
+	Find(ctx context.Context, id uint) (*dto.AuditEntry, error)
+}
+
+type auditService struct {
+	repo repositories.AuditRepository
+}
+
+// NewAuditService creates a new AuditService
+func NewAuditService(repo repositories.AuditRepository) AuditService {
+	return &auditService{repo: repo}
+}
+
+func (s *auditService) Record(ctx context.Context, action, targetType, targetID string, details any) error {
+	entry, err := s.entry(ctx, action, targetType, targetID, details)
+	if err != nil {
+		return err
+	}
+	return s.repo.Create(ctx, entry)
+}
+
+func (s *auditService) RecordChange(ctx context.Context, action, targetType, targetID string, before, after any) error {
+	entry, err := s.entry(ctx, action, targetType, targetID, map[string]any{})
+	if err != nil {
+		return err
+	}
+	if entry.Before, err = snapshot(before); err != nil {
+		return err
+	}
+	if entry.After, err = snapshot(after); err != nil {
+		return err
+	}
+	return s.repo.Create(ctx, entry)
+}
+
+func (s *auditService) entry(ctx context.Context, action, targetType, targetID string, details any) (*models.AuditLog, error) {
+	encoded, err := json.Marshal(details)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := models.AuditLog{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Details:    string(encoded),
+		RequestID:  logger.RequestIDFromContext(ctx),
+	}
+	if actorID := logger.UserIDFromContext(ctx); actorID != 0 {
+		entry.ActorID = &actorID
+	}
+	return &entry, nil
+}
+
+// snapshot encodes a target; nil (including a nil pointer) means there is
+// no target on that side of the change
+func snapshot(target any) (*string, error) {
+	encoded, err := json.Marshal(target)
+	if err != nil || string(encoded) == "null" {
+		return nil, err
+	}
+	state := string(encoded)
+	return &state, nil
+}
+
+func (s *auditService) Find(ctx context.Context, id uint) (*dto.AuditEntry, error) {
+	entry, err := s.repo.Find(ctx, id)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+
+	result := &dto.AuditEntry{
+		ID:         entry.ID,
+		ActorID:    entry.ActorID,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		RequestID:  entry.RequestID,
+		CreatedAt:  entry.CreatedAt,
+		Changes:    []dto.AuditChange{},
+	}
+	// Decoded rather than passed through as raw JSON so MessagePack responses
+	// carry them as maps too
+	if entry.Details != "" {
+		if err := json.Unmarshal([]byte(entry.Details), &result.Details); err != nil {
+			return nil, err
+		}
+	}
+	if entry.Before == nil && entry.After == nil {
+		return result, nil
+	}
+
+	if entry.Before != nil {
+		if err := json.Unmarshal([]byte(*entry.Before), &result.Before); err != nil {
+			return nil, err
+		}
+	}
+	if entry.After != nil {
+		if err := json.Unmarshal([]byte(*entry.After), &result.After); err != nil {
+			return nil, err
+		}
+	}
+	diffSnapshots("", result.Before, entry.Before != nil, result.After, entry.After != nil, &result.Changes)
+	return result, nil
+}
+
+// diffSnapshots appends the differences between two decoded JSON values.
+// Objects are compared key by key; anything else, arrays included, is
+// compared as a whole so a reordered list reads as one change.
+func diffSnapshots(path string, before any, hasBefore bool, after any, hasAfter bool, changes *[]dto.AuditChange) {
+	beforeObj, beforeIsObj := before.(map[string]any)
+	afterObj, afterIsObj := after.(map[string]any)
+	if (beforeIsObj || !hasBefore) && (afterIsObj || !hasAfter) {
+		keys := make([]string, 0, len(beforeObj)+len(afterObj))
+		for key := range beforeObj {
+			keys = append(keys, key)
+		}
+		for key := range afterObj {
+			if _, ok := beforeObj[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			b, bok := beforeObj[key]
+			a, aok := afterObj[key]
+			child := key
+			if path != "" {
+				child = path + "." + key
+			}
+			diffSnapshots(child, b, bok, a, aok, changes)
+		}
+		return
+	}
+
+	switch {
+	case !hasBefore:
+		*changes = append(*changes, dto.AuditChange{Path: path, Kind: dto.AuditChangeAdded, After: after})
+	case !hasAfter:
+		*changes = append(*changes, dto.AuditChange{Path: path, Kind: dto.AuditChangeRemoved, Before: before})
+	case !reflect.DeepEqual(before, after):
+		*changes = append(*changes, dto.AuditChange{Path: path, Kind: dto.AuditChangeChanged, Before: before, After: after})
+	}
+}
@@ -1,8 +1,12 @@
 package services
 
 import (
+	"context"
+	"strconv"
+
 	"golang.org/x/crypto/bcrypt"
 
+	"temandifa-backend/internal/database"
 	"temandifa-backend/internal/dto"
 	apperrors "temandifa-backend/internal/errors"
 	"temandifa-backend/internal/helpers"
@@ -12,32 +16,45 @@ import (
 
 // AuthService handles authentication logic
 type AuthService interface {
-	Register(input dto.RegisterRequest) (*models.User, error)
-	Login(input dto.LoginRequest, userAgent, ipAddress string) (*dto.LoginResponse, error)
+	Register(ctx context.Context, input dto.RegisterRequest) (*models.User, error)
+	// Login signs the user in; deviceID (X-Device-ID, may be empty) binds the
+	// refresh token to the device. Too many failed logins of an email address
+	// or IP address lock them out for a while.
+	Login(ctx context.Context, input dto.LoginRequest, userAgent, ipAddress, deviceID string) (*dto.LoginResponse, error)
 }
 
 type authService struct {
-	userRepo     repositories.UserRepository
-	tokenService TokenService
+	userRepo      repositories.UserRepository
+	tokenService  TokenService
+	txManager     database.TxManager
+	outbox        OutboxService
+	notifications NotificationService
+	verification  EmailVerificationService
+	lockout       LoginLockoutService
 }
 
 // NewAuthService creates a new AuthService
-func NewAuthService(userRepo repositories.UserRepository, tokenService TokenService) AuthService {
+func NewAuthService(userRepo repositories.UserRepository, tokenService TokenService, txManager database.TxManager, outbox OutboxService, notifications NotificationService, verification EmailVerificationService, lockout LoginLockoutService) AuthService {
 	return &authService{
-		userRepo:     userRepo,
-		tokenService: tokenService,
+		userRepo:      userRepo,
+		tokenService:  tokenService,
+		txManager:     txManager,
+		outbox:        outbox,
+		notifications: notifications,
+		verification:  verification,
+		lockout:       lockout,
 	}
 }
 
 // Register creates a new user account
-func (s *authService) Register(input dto.RegisterRequest) (*models.User, error) {
+func (s *authService) Register(ctx context.Context, input dto.RegisterRequest) (*models.User, error) {
 	// Validate password strength
 	if issues := helpers.ValidatePasswordStrength(input.Password); len(issues) > 0 {
 		return nil, apperrors.ValidationWithDetails("Password does not meet security requirements", issues)
 	}
 
 	// Check if user exists
-	existingUser, err := s.userRepo.FindByEmail(input.Email)
+	existingUser, err := s.userRepo.FindByEmail(ctx, input.Email)
 	if err != nil {
 		return nil, apperrors.Database(err)
 	}
@@ -57,35 +74,70 @@ func (s *authService) Register(input dto.RegisterRequest) (*models.User, error)
 		Password: string(hashedPassword),
 	}
 
-	if err := s.userRepo.Create(user); err != nil {
+	// The user, its email verification token and its user.registered
+	// integration event are committed together
+	var verificationToken string
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Create(ctx, user); err != nil {
+			return err
+		}
+		if verificationToken, err = s.verification.Issue(ctx, user); err != nil {
+			return err
+		}
+		return s.outbox.Enqueue(ctx, OutboxUserRegistered, "user", strconv.FormatUint(uint64(user.ID), 10), map[string]any{
+			"user_id":   user.ID,
+			"email":     user.Email,
+			"full_name": user.FullName,
+		})
+	})
+	if err != nil {
 		return nil, apperrors.Database(err)
 	}
 
+	// Registration doesn't wait for the mail server; a lost link can be resent
+	go s.verification.Send(context.WithoutCancel(ctx), user, verificationToken)
+
 	// Clear password for response
 	user.Password = ""
 	return user, nil
 }
 
 // Login authenticates a user and returns tokens
-func (s *authService) Login(input dto.LoginRequest, userAgent, ipAddress string) (*dto.LoginResponse, error) {
-	user, err := s.userRepo.FindByEmail(input.Email)
+func (s *authService) Login(ctx context.Context, input dto.LoginRequest, userAgent, ipAddress, deviceID string) (*dto.LoginResponse, error) {
+	// A locked out login is refused before the password is checked
+	if err := s.lockout.Check(ctx, input.Email, ipAddress); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, input.Email)
 	if err != nil {
 		return nil, apperrors.Database(err)
 	}
-	if user == nil {
-		return nil, apperrors.ErrInvalidCredentials
+	// Soft-deleted accounts can't sign in while they wait to be purged.
+	// Unknown addresses count too, so lockouts don't tell which exist.
+	if user == nil || user.DeletedAt != nil {
+		return nil, s.loginFailed(ctx, input.Email, ipAddress)
 	}
 
 	cmpErr := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.Password))
 	if cmpErr != nil {
-		return nil, apperrors.ErrInvalidCredentials
+		return nil, s.loginFailed(ctx, input.Email, ipAddress)
 	}
+	s.lockout.RecordSuccess(ctx, input.Email)
 
-	tokenPair, err := s.tokenService.GenerateTokenPair(user, userAgent, ipAddress)
+	tokenPair, err := s.tokenService.GenerateTokenPair(ctx, user, userAgent, ipAddress, deviceID)
 	if err != nil {
 		return nil, err
 	}
 
+	// Devices already signed in learn about the new session
+	s.notifications.NotifyUser(ctx, user.ID, Notification{
+		Type:  NotificationNewSignIn,
+		Title: "New sign-in to your account",
+		Body:  "If this wasn't you, contact support right away.",
+		Data:  map[string]string{"user_agent": userAgent, "ip_address": ipAddress},
+	})
+
 	return &dto.LoginResponse{
 		TokenResponse: dto.TokenResponse{
 			AccessToken:  tokenPair.AccessToken,
@@ -94,9 +146,18 @@ func (s *authService) Login(input dto.LoginRequest, userAgent, ipAddress string)
 			TokenType:    tokenPair.TokenType,
 		},
 		User: dto.UserInfo{
-			ID:       user.ID,
-			FullName: user.FullName,
-			Email:    user.Email,
+			ID:            user.ID,
+			FullName:      user.FullName,
+			Email:         user.Email,
+			EmailVerified: user.EmailVerifiedAt != nil,
 		},
 	}, nil
 }
+
+// loginFailed counts a failed login, returning the error to answer it with
+func (s *authService) loginFailed(ctx context.Context, email, ipAddress string) error {
+	if err := s.lockout.RecordFailure(ctx, email, ipAddress); err != nil {
+		return err
+	}
+	return apperrors.ErrInvalidCredentials
+}
@@ -0,0 +1,393 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/dto"
+	"temandifa-backend/internal/metrics"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/storage"
+)
+
+// Backup methods
+const (
+	BackupMethodPgDump   = "pg_dump"
+	BackupMethodSnapshot = "snapshot"
+)
+
+// backupLockKey is the Postgres advisory lock held while a backup runs, so
+// only one instance backs up per schedule tick
+const backupLockKey = 72_616_001
+
+// backupStderrLimit bounds how much pg_dump stderr is kept for error messages
+const backupStderrLimit = 4096
+
+// ErrBackupInProgress is returned when another instance holds the backup lock
+var ErrBackupInProgress = errors.New("a backup is already running")
+
+// BackupService runs logical backups and reports on the latest one
+type BackupService interface {
+	// Run performs a backup now; it returns ErrBackupInProgress if another
+	// instance is already running one
+	Run(ctx context.Context) (*models.BackupRun, error)
+	// LastSuccessful returns the most recent successful run, or nil
+	LastSuccessful(ctx context.Context) (*models.BackupRun, error)
+}
+
+type backupService struct {
+	db    *gorm.DB
+	store storage.ObjectStore
+	cfg   *config.Config
+	http  *http.Client
+	log   *zap.Logger
+}
+
+// NewBackupService creates a new BackupService
+func NewBackupService(db *gorm.DB, store storage.ObjectStore, cfg *config.Config, log *zap.Logger) BackupService {
+	return &backupService{
+		db:    db,
+		store: store,
+		cfg:   cfg,
+		http:  &http.Client{Timeout: time.Minute},
+		log:   log,
+	}
+}
+
+func (s *backupService) Run(ctx context.Context) (*models.BackupRun, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.BackupTimeout)
+	defer cancel()
+
+	// The advisory lock is session-scoped, so pin one primary connection for
+	// the whole run (bypassing GORM, whose replica routing would swap it out)
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", backupLockKey).Scan(&locked); err != nil {
+		return nil, err
+	}
+	if !locked {
+		return nil, ErrBackupInProgress
+	}
+	defer func() {
+		_, _ = conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", backupLockKey)
+	}()
+
+	run := s.execute(ctx)
+	if run.Status != models.BackupStatusSucceeded {
+		return run, errors.New(run.Error)
+	}
+	return run, nil
+}
+
+// execute performs and records a single backup run
+func (s *backupService) execute(ctx context.Context) *models.BackupRun {
+	run := &models.BackupRun{
+		StartedAt: time.Now(),
+		Method:    s.cfg.BackupMethod,
+		Status:    models.BackupStatusRunning,
+	}
+	if err := s.db.WithContext(ctx).Create(run).Error; err != nil {
+		s.log.Warn("Failed to record backup start", zap.Error(err))
+	}
+	s.log.Info("Database backup started", zap.String("method", run.Method))
+
+	var err error
+	switch run.Method {
+	case BackupMethodSnapshot:
+		run.Location, err = s.snapshot(ctx, run.StartedAt)
+	default:
+		run.Location, run.SizeBytes, err = s.dump(ctx, run.StartedAt)
+	}
+
+	finished := time.Now()
+	run.FinishedAt = &finished
+	duration := finished.Sub(run.StartedAt)
+	result := "success"
+	if err != nil {
+		run.Status = models.BackupStatusFailed
+		run.Error = err.Error()
+		result = "failure"
+		s.log.Error("Database backup failed", zap.String("method", run.Method), zap.Duration("duration", duration), zap.Error(err))
+	} else {
+		run.Status = models.BackupStatusSucceeded
+		metrics.BackupLastSuccess.Set(float64(finished.Unix()))
+		metrics.BackupSizeBytes.Set(float64(run.SizeBytes))
+		s.log.Info("Database backup completed",
+			zap.String("method", run.Method),
+			zap.String("location", run.Location),
+			zap.Int64("size_bytes", run.SizeBytes),
+			zap.Duration("duration", duration),
+		)
+	}
+	metrics.BackupRuns.WithLabelValues(run.Method, result).Inc()
+	metrics.BackupDuration.WithLabelValues(run.Method).Observe(duration.Seconds())
+
+	// Record the outcome even if the run context expired
+	if err := s.db.Save(run).Error; err != nil {
+		s.log.Warn("Failed to record backup result", zap.Error(err))
+	}
+
+	if run.Status == models.BackupStatusSucceeded && run.Method == BackupMethodPgDump {
+		s.rotate(context.WithoutCancel(ctx))
+	}
+	return run
+}
+
+// dump streams a custom-format pg_dump archive into object storage
+func (s *backupService) dump(ctx context.Context, startedAt time.Time) (string, int64, error) {
+	if s.store == nil {
+		return "", 0, errors.New("object storage is not configured")
+	}
+
+	key := s.cfg.BackupPrefix + "temandifa-" + startedAt.UTC().Format("20060102T150405Z") + ".dump"
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, s.cfg.BackupPgDumpPath,
+		"--format=custom", "--no-owner", "--no-privileges",
+		"--dbname="+s.cfg.DatabaseDSN,
+	)
+	stderr := &limitedBuffer{limit: backupStderrLimit}
+	cmd.Stderr = stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", 0, err
+	}
+	if err := cmd.Start(); err != nil {
+		return "", 0, fmt.Errorf("start pg_dump: %w", err)
+	}
+
+	size, putErr := s.store.Put(ctx, key, stdout, -1, "application/octet-stream")
+	if putErr != nil {
+		cancel() // Stop pg_dump; nothing is reading its output anymore
+	}
+	waitErr := cmd.Wait()
+
+	switch {
+	case putErr != nil:
+		return "", 0, fmt.Errorf("upload backup: %w", putErr)
+	case waitErr != nil:
+		// Don't keep a truncated archive around
+		_ = s.store.Delete(context.WithoutCancel(ctx), key)
+		return "", 0, fmt.Errorf("pg_dump: %w: %s", waitErr, strings.TrimSpace(stderr.String()))
+	}
+	return key, size, nil
+}
+
+// snapshot asks the managed database provider to take a snapshot
+func (s *backupService) snapshot(ctx context.Context, startedAt time.Time) (string, error) {
+	label := "temandifa-" + startedAt.UTC().Format("20060102T150405Z")
+	body, err := json.Marshal(map[string]string{"label": label})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.BackupSnapshotURL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.cfg.BackupSnapshotToken != "" {
+		req.Header.Set("Authorization", "Bearer "+s.cfg.BackupSnapshotToken)
+	}
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("snapshot API returned %s", resp.Status)
+	}
+
+	// Prefer the provider's snapshot ID when it returns one
+	var result struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&result); err == nil && result.ID != "" {
+		return result.ID, nil
+	}
+	return label, nil
+}
+
+// rotate deletes pg_dump archives beyond the retention count (oldest first)
+func (s *backupService) rotate(ctx context.Context) {
+	objects, err := s.store.List(ctx, s.cfg.BackupPrefix)
+	if err != nil {
+		s.log.Warn("Failed to list backups for rotation", zap.Error(err))
+		return
+	}
+	if len(objects) <= s.cfg.BackupRetentionCount {
+		return
+	}
+
+	// Keys embed a sortable UTC timestamp
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key > objects[j].Key })
+	for _, obj := range objects[s.cfg.BackupRetentionCount:] {
+		if err := s.store.Delete(ctx, obj.Key); err != nil {
+			s.log.Warn("Failed to delete expired backup", zap.String("key", obj.Key), zap.Error(err))
+			continue
+		}
+		s.log.Info("Expired backup deleted", zap.String("key", obj.Key))
+	}
+}
+
+func (s *backupService) LastSuccessful(ctx context.Context) (*models.BackupRun, error) {
+	var run models.BackupRun
+	err := s.db.WithContext(ctx).
+		Where("status = ?", models.BackupStatusSucceeded).
+		Order("finished_at DESC").
+		First(&run).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// backupHealthCheck reports unhealthy when the last successful backup is
+// missing or older than maxAge
+func backupHealthCheck(backups BackupService, maxAge time.Duration) ComponentCheck {
+	return func(ctx context.Context) dto.HealthCheck {
+		run, err := backups.LastSuccessful(ctx)
+		if err != nil {
+			return dto.HealthCheck{Status: HealthStatusUnknown, Message: err.Error()}
+		}
+		if run == nil || run.FinishedAt == nil {
+			return dto.HealthCheck{Status: HealthStatusUnhealthy, Message: "no successful backup yet"}
+		}
+
+		metrics.BackupLastSuccess.Set(float64(run.FinishedAt.Unix()))
+		age := time.Since(*run.FinishedAt).Round(time.Minute)
+		message := fmt.Sprintf("last backup %s ago (%s)", age, run.Location)
+		if age > maxAge {
+			return dto.HealthCheck{Status: HealthStatusUnhealthy, Message: message}
+		}
+		return dto.HealthCheck{Status: HealthStatusHealthy, Message: message}
+	}
+}
+
+// BackupJob runs backups on the configured cron schedule
+type BackupJob struct {
+	backups  BackupService
+	schedule cron.Schedule
+	log      *zap.Logger
+	stopChan chan struct{}
+}
+
+// NewBackupJob creates a new backup job; the schedule was validated with the config
+func NewBackupJob(backups BackupService, cfg *config.Config, log *zap.Logger) (*BackupJob, error) {
+	schedule, err := cron.ParseStandard(cfg.BackupSchedule)
+	if err != nil {
+		return nil, err
+	}
+	return &BackupJob{
+		backups:  backups,
+		schedule: schedule,
+		log:      log,
+		stopChan: make(chan struct{}),
+	}, nil
+}
+
+// Start begins the backup schedule
+func (j *BackupJob) Start() {
+	next := j.schedule.Next(time.Now().UTC())
+	j.log.Info("Backup job started", zap.Time("next_run", next))
+
+	go func() {
+		timer := time.NewTimer(time.Until(next))
+		for {
+			select {
+			case <-timer.C:
+				j.runBackup()
+				timer.Reset(time.Until(j.schedule.Next(time.Now().UTC())))
+			case <-j.stopChan:
+				timer.Stop()
+				j.log.Info("Backup job stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the backup schedule
+func (j *BackupJob) Stop() {
+	close(j.stopChan)
+}
+
+func (j *BackupJob) runBackup() {
+	if _, err := j.backups.Run(context.Background()); errors.Is(err, ErrBackupInProgress) {
+		j.log.Info("Skipping scheduled backup: another instance is running it")
+	}
+}
+
+// RegisterBackupJob registers the backup schedule and its health check when
+// backups are enabled
+func RegisterBackupJob(lc fx.Lifecycle, backups BackupService, hc *HealthChecker, cfg *config.Config, log *zap.Logger) error {
+	if !cfg.BackupEnabled {
+		return nil
+	}
+
+	job, err := NewBackupJob(backups, cfg, log)
+	if err != nil {
+		return err
+	}
+	hc.Register("backup", false, backupHealthCheck(backups, cfg.BackupMaxAge))
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			job.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			job.Stop()
+			return nil
+		},
+	})
+	return nil
+}
+
+// limitedBuffer keeps the first limit bytes written to it
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); room > 0 {
+		b.Buffer.Write(p[:min(len(p), room)])
+	}
+	return len(p), nil
+}
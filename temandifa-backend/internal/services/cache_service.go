@@ -2,18 +2,36 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"net/http"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
 	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/metrics"
 )
 
+const (
+	// cacheInvalidationKeyPrefix prefixes the confirm tokens of previewed
+	// pattern invalidations
+	cacheInvalidationKeyPrefix = "cache:invalidate:"
+	// cacheInvalidationTokenTTL is how long a preview's confirm token stays valid
+	cacheInvalidationTokenTTL = 5 * time.Minute
+)
+
+// aiCachePrefixes are the key prefixes of cached AI results; invalidation
+// patterns must stay within one of them
+var aiCachePrefixes = []string{"detect:", "ocr:", "transcribe:", "vqa:"}
+
 // CacheService defines the interface for caching operations
 type CacheService interface {
 	Get(ctx context.Context, key string) ([]byte, bool)
@@ -23,20 +41,30 @@ type CacheService interface {
 	SetJSON(ctx context.Context, key string, data interface{}, ttl time.Duration) error
 	Delete(ctx context.Context, key string) error
 	ClearByPrefix(ctx context.Context, prefix string) (int64, error)
+	// PreviewInvalidation counts the AI cache entries matching a glob
+	// pattern and issues a confirm token for deleting them
+	PreviewInvalidation(ctx context.Context, pattern string) (*dto.CacheInvalidationPreview, error)
+	// Invalidate deletes the entries matching a previewed pattern; the
+	// confirm token is single use
+	Invalidate(ctx context.Context, pattern, confirmToken string) (int64, error)
 	GetStats(ctx context.Context) map[string]interface{}
 	GenerateKey(prefix string, data []byte) string
-	WaitForCompletion()
+	// WaitForCompletion waits up to timeout for pending SetAsync writes and
+	// reports whether they all finished
+	WaitForCompletion(timeout time.Duration) bool
 }
 
 type redisCacheService struct {
 	client *redis.Client
+	log    *zap.Logger
 	wg     sync.WaitGroup
 }
 
 // NewCacheService creates a new Redis-based cache service
-func NewCacheService(client *redis.Client) CacheService {
+func NewCacheService(client *redis.Client, log *zap.Logger) CacheService {
 	return &redisCacheService{
 		client: client,
+		log:    log,
 	}
 }
 
@@ -55,7 +83,7 @@ func (s *redisCacheService) Get(ctx context.Context, key string) ([]byte, bool)
 		return nil, false
 	}
 
-	logger.Debug("Cache hit", zap.String("key", key))
+	logger.Ctx(ctx).Debug("Cache hit", zap.String("key", key))
 	return data, true
 }
 
@@ -66,18 +94,20 @@ func (s *redisCacheService) Set(ctx context.Context, key string, data []byte, tt
 
 	err := s.client.Set(ctx, key, data, ttl).Err()
 	if err != nil {
-		logger.Warn("Failed to set cache", zap.String("key", key), zap.Error(err))
+		logger.Ctx(ctx).Warn("Failed to set cache", zap.String("key", key), zap.Error(err))
 		return err
 	}
 
-	logger.Debug("Cache set", zap.String("key", key), zap.Duration("ttl", ttl))
+	logger.Ctx(ctx).Debug("Cache set", zap.String("key", key), zap.Duration("ttl", ttl))
 	return nil
 }
 
 func (s *redisCacheService) SetAsync(ctx context.Context, key string, data []byte, ttl time.Duration) {
 	s.wg.Add(1)
+	metrics.CachePendingOperations.Inc()
 	go func() {
 		defer s.wg.Done()
+		defer metrics.CachePendingOperations.Dec()
 		detachedCtx := context.WithoutCancel(ctx)
 		_ = s.Set(detachedCtx, key, data, ttl)
 	}()
@@ -91,7 +121,7 @@ func (s *redisCacheService) GetJSON(ctx context.Context, key string, dest interf
 
 	err := json.Unmarshal(data, dest)
 	if err != nil {
-		logger.Warn("Failed to unmarshal cached data", zap.String("key", key), zap.Error(err))
+		logger.Ctx(ctx).Warn("Failed to unmarshal cached data", zap.String("key", key), zap.Error(err))
 		return false
 	}
 	return true
@@ -116,22 +146,29 @@ func (s *redisCacheService) ClearByPrefix(ctx context.Context, prefix string) (i
 	if s.client == nil {
 		return 0, nil
 	}
+	return s.scanKeys(ctx, prefix+"*", func(keys []string) (int64, error) {
+		return s.client.Del(ctx, keys...).Result()
+	})
+}
 
+// scanKeys walks the keys matching pattern in batches and sums what fn
+// returns for each batch
+func (s *redisCacheService) scanKeys(ctx context.Context, pattern string, fn func(keys []string) (int64, error)) (int64, error) {
 	var cursor uint64
-	var deleted int64
+	var total int64
 
 	for {
-		keys, nextCursor, err := s.client.Scan(ctx, cursor, prefix+"*", 100).Result()
+		keys, nextCursor, err := s.client.Scan(ctx, cursor, pattern, 100).Result()
 		if err != nil {
-			return deleted, err
+			return total, err
 		}
 
 		if len(keys) > 0 {
-			n, err := s.client.Del(ctx, keys...).Result()
+			n, err := fn(keys)
 			if err != nil {
-				return deleted, err
+				return total, err
 			}
-			deleted += n
+			total += n
 		}
 
 		cursor = nextCursor
@@ -139,7 +176,73 @@ func (s *redisCacheService) ClearByPrefix(ctx context.Context, prefix string) (i
 			break
 		}
 	}
-	return deleted, nil
+	return total, nil
+}
+
+// validateInvalidationPattern rejects patterns reaching outside the AI
+// result cache, such as "*", which would also match sessions and counters
+func validateInvalidationPattern(pattern string) error {
+	for _, prefix := range aiCachePrefixes {
+		if strings.HasPrefix(pattern, prefix) {
+			return nil
+		}
+	}
+	return apperrors.ValidationWithDetails("Invalid pattern", map[string]string{
+		"pattern": "must start with one of " + strings.Join(aiCachePrefixes, ", "),
+	})
+}
+
+func (s *redisCacheService) PreviewInvalidation(ctx context.Context, pattern string) (*dto.CacheInvalidationPreview, error) {
+	if err := validateInvalidationPattern(pattern); err != nil {
+		return nil, err
+	}
+	if s.client == nil {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeServiceUnavailable, "Cache invalidation needs Redis", http.StatusServiceUnavailable)
+	}
+
+	matched, err := s.scanKeys(ctx, pattern, func(keys []string) (int64, error) {
+		return int64(len(keys)), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	token := hex.EncodeToString(b)
+	if err := s.client.Set(ctx, cacheInvalidationKeyPrefix+token, pattern, cacheInvalidationTokenTTL).Err(); err != nil {
+		return nil, err
+	}
+
+	return &dto.CacheInvalidationPreview{
+		Pattern:      pattern,
+		Matched:      matched,
+		ConfirmToken: token,
+		ExpiresAt:    time.Now().Add(cacheInvalidationTokenTTL),
+	}, nil
+}
+
+func (s *redisCacheService) Invalidate(ctx context.Context, pattern, confirmToken string) (int64, error) {
+	if err := validateInvalidationPattern(pattern); err != nil {
+		return 0, err
+	}
+	if s.client == nil {
+		return 0, apperrors.NewAppError(apperrors.ErrCodeServiceUnavailable, "Cache invalidation needs Redis", http.StatusServiceUnavailable)
+	}
+
+	previewed, err := s.client.GetDel(ctx, cacheInvalidationKeyPrefix+confirmToken).Result()
+	if err != nil && err != redis.Nil {
+		return 0, err
+	}
+	if err == redis.Nil || previewed != pattern {
+		return 0, apperrors.ValidationWithDetails("Invalid confirm token", map[string]string{
+			"confirm_token": "expired, already used or issued for another pattern; preview the pattern again",
+		})
+	}
+
+	return s.scanKeys(ctx, pattern, func(keys []string) (int64, error) {
+		return s.client.Del(ctx, keys...).Result()
+	})
 }
 
 func (s *redisCacheService) GetStats(ctx context.Context) map[string]interface{} {
@@ -158,7 +261,7 @@ func (s *redisCacheService) GetStats(ctx context.Context) map[string]interface{}
 	return stats
 }
 
-func (s *redisCacheService) WaitForCompletion() {
+func (s *redisCacheService) WaitForCompletion(timeout time.Duration) bool {
 	done := make(chan struct{})
 	go func() {
 		s.wg.Wait()
@@ -167,8 +270,10 @@ func (s *redisCacheService) WaitForCompletion() {
 
 	select {
 	case <-done:
-		logger.Info("All cache operations completed")
-	case <-time.After(5 * time.Second):
-		logger.Warn("Timeout waiting for cache operations")
+		s.log.Info("All cache operations completed")
+		return true
+	case <-time.After(timeout):
+		s.log.Warn("Timeout waiting for cache operations", zap.Duration("timeout", timeout))
+		return false
 	}
 }
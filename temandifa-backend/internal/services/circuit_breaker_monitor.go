@@ -0,0 +1,108 @@
+package services
+
+import (
+	"sync"
+
+	"github.com/sony/gobreaker"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/metrics"
+)
+
+// Error samples kept per breaker for alert context
+const (
+	breakerErrorSamples   = 5
+	breakerErrorSampleLen = 200
+)
+
+// circuitBreakerMonitor tracks failure counts and recent errors of circuit
+// breakers so that state changes can be logged, exported, and alerted on.
+// gobreaker resets its counts before OnStateChange runs, so the counts that
+// tripped the breaker are captured in ReadyToTrip.
+type circuitBreakerMonitor struct {
+	notifier AlertNotifier
+	log      *zap.Logger
+
+	mu           sync.Mutex
+	tripCounts   map[string]gobreaker.Counts
+	errorSamples map[string][]string
+}
+
+func newCircuitBreakerMonitor(notifier AlertNotifier, log *zap.Logger) *circuitBreakerMonitor {
+	return &circuitBreakerMonitor{
+		notifier:     notifier,
+		log:          log,
+		tripCounts:   make(map[string]gobreaker.Counts),
+		errorSamples: make(map[string][]string),
+	}
+}
+
+// recordError keeps the most recent error messages for a breaker
+func (m *circuitBreakerMonitor) recordError(name string, err error) {
+	msg := err.Error()
+	if len(msg) > breakerErrorSampleLen {
+		msg = msg[:breakerErrorSampleLen]
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	samples := append(m.errorSamples[name], msg)
+	if len(samples) > breakerErrorSamples {
+		samples = samples[len(samples)-breakerErrorSamples:]
+	}
+	m.errorSamples[name] = samples
+}
+
+// recordTrip stores the counts that caused a breaker to trip
+func (m *circuitBreakerMonitor) recordTrip(name string, counts gobreaker.Counts) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tripCounts[name] = counts
+}
+
+// onStateChange handles circuit breaker state changes with logging, metrics, and alerts
+func (m *circuitBreakerMonitor) onStateChange(name string, from gobreaker.State, to gobreaker.State) {
+	m.log.Warn("Circuit Breaker state changed",
+		zap.String("name", name),
+		zap.String("from", from.String()),
+		zap.String("to", to.String()),
+	)
+	// Update Prometheus metrics
+	// State mapping: Closed=0, Open=1, HalfOpen=2
+	stateValue := 0
+	switch to {
+	case gobreaker.StateOpen:
+		stateValue = 1
+	case gobreaker.StateHalfOpen:
+		stateValue = 2
+	}
+	metrics.UpdateCircuitBreakerState(name, stateValue)
+
+	if to == gobreaker.StateOpen {
+		m.alertOpen(name, from)
+	}
+}
+
+// alertOpen notifies on-call that a breaker opened
+func (m *circuitBreakerMonitor) alertOpen(name string, from gobreaker.State) {
+	m.mu.Lock()
+	counts := m.tripCounts[name]
+	samples := append([]string(nil), m.errorSamples[name]...)
+	m.mu.Unlock()
+
+	m.notifier.Notify(Alert{
+		Key:      "circuit_breaker_open:" + name,
+		Title:    "Circuit breaker " + name + " opened",
+		Severity: "critical",
+		Source:   "temandifa-backend",
+		Details: map[string]any{
+			"breaker":              name,
+			"from_state":           from.String(),
+			"requests":             counts.Requests,
+			"total_failures":       counts.TotalFailures,
+			"consecutive_failures": counts.ConsecutiveFailures,
+			"recent_errors":        samples,
+		},
+	})
+}
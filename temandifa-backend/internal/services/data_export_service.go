@@ -0,0 +1,399 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/database"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/storage"
+)
+
+// OutboxDataExportReady is sent when an export archive can be downloaded
+const OutboxDataExportReady = "user.data_export_ready"
+
+// auditDataExportRequested is the audit action of an export request
+const auditDataExportRequested = "user.data_export_requested"
+
+// dataExportPurgeInterval controls how often expired archives are deleted
+const dataExportPurgeInterval = time.Hour
+
+// DataExportService assembles a user's personal data (profile, history,
+// sessions, usage, emergency contacts and alerts, calls, preferences and
+// synced settings) into a zip archive in object storage. Archives are built
+// in the background; a user.data_export_ready outbox event and a push
+// notification announce each finished one.
+type DataExportService interface {
+	// Request starts an export of the user's data, or returns nil when the
+	// user doesn't exist. Soft-deleted users can still be exported.
+	Request(ctx context.Context, userID uint) (*models.DataExport, error)
+	// Find returns nil when the user has no such export
+	Find(ctx context.Context, userID uint, id uint64) (*models.DataExport, error)
+	// Open returns a ready export's archive; the caller closes it. The
+	// export is nil when the user has no such export.
+	Open(ctx context.Context, userID uint, id uint64) (io.ReadCloser, *models.DataExport, error)
+	// PurgeExpired deletes archives older than DATA_EXPORT_TTL and returns
+	// how many were deleted
+	PurgeExpired(ctx context.Context) (int, error)
+	// Wait blocks until exports in progress finish or ctx is done
+	Wait(ctx context.Context) error
+}
+
+type dataExportService struct {
+	db            *gorm.DB
+	store         storage.ObjectStore
+	outbox        OutboxService
+	audit         AuditService
+	notifications NotificationService
+	tx            database.TxManager
+	prefix        string
+	ttl           time.Duration
+	log           *zap.Logger
+	wg            sync.WaitGroup
+}
+
+// NewDataExportService creates a new DataExportService
+func NewDataExportService(db *gorm.DB, store storage.ObjectStore, outbox OutboxService, audit AuditService, notifications NotificationService, tx database.TxManager, cfg *config.Config, log *zap.Logger) DataExportService {
+	return &dataExportService{
+		db:            db,
+		store:         store,
+		outbox:        outbox,
+		audit:         audit,
+		notifications: notifications,
+		tx:            tx,
+		prefix:        cfg.DataExportPrefix,
+		ttl:           cfg.DataExportTTL,
+		log:           log,
+	}
+}
+
+func (s *dataExportService) Request(ctx context.Context, userID uint) (*models.DataExport, error) {
+	if s.store == nil {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeServiceUnavailable, "Data exports need object storage", http.StatusServiceUnavailable)
+	}
+
+	var user models.User
+	if err := database.Conn(ctx, s.db).First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var pending models.DataExport
+	err := database.Conn(ctx, s.db).
+		Where("user_id = ? AND status = ?", userID, models.DataExportPending).
+		Limit(1).Find(&pending).Error
+	if err != nil {
+		return nil, err
+	}
+	if pending.ID != 0 {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeConflict, "An export is already in progress", http.StatusConflict).
+			WithDetails(map[string]uint64{"export_id": pending.ID})
+	}
+
+	export := &models.DataExport{UserID: userID, Status: models.DataExportPending}
+	if actorID := logger.UserIDFromContext(ctx); actorID != 0 {
+		export.RequestedBy = &actorID
+	}
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := database.Conn(ctx, s.db).Create(export).Error; err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, auditDataExportRequested, auditTargetUser, strconv.FormatUint(uint64(userID), 10), map[string]uint64{"export_id": export.ID})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	requested := *export
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.build(context.WithoutCancel(ctx), export)
+	}()
+	return &requested, nil
+}
+
+// build writes the archive, stores it and records the outcome
+func (s *dataExportService) build(ctx context.Context, export *models.DataExport) {
+	log := s.log.With(zap.Uint64("export_id", export.ID), zap.Uint("user_id", export.UserID))
+	start := time.Now()
+
+	key := fmt.Sprintf("%s%d/%d.zip", s.prefix, export.UserID, export.ID)
+	size, err := s.writeArchive(ctx, export, key)
+
+	now := time.Now()
+	export.CompletedAt = &now
+	if err != nil {
+		export.Status = models.DataExportFailed
+		export.Error = err.Error()
+		log.Error("Data export failed", zap.Error(err))
+		if err := database.Conn(ctx, s.db).Save(export).Error; err != nil {
+			log.Warn("Failed to record data export outcome", zap.Error(err))
+		}
+		return
+	}
+
+	expiresAt := now.Add(s.ttl)
+	export.Status = models.DataExportReady
+	export.ObjectKey = key
+	export.SizeBytes = size
+	export.ExpiresAt = &expiresAt
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := database.Conn(ctx, s.db).Save(export).Error; err != nil {
+			return err
+		}
+		return s.outbox.Enqueue(ctx, OutboxDataExportReady, "user", strconv.FormatUint(uint64(export.UserID), 10), map[string]any{
+			"user_id":    export.UserID,
+			"export_id":  export.ID,
+			"size_bytes": export.SizeBytes,
+			"expires_at": expiresAt,
+		})
+	})
+	if err != nil {
+		log.Error("Failed to record data export outcome", zap.Error(err))
+		return
+	}
+	log.Info("Data export ready", zap.Int64("size_bytes", size), zap.Duration("duration", time.Since(start)))
+
+	s.notifications.NotifyUser(ctx, export.UserID, Notification{
+		Type:  NotificationDataExportReady,
+		Title: "Your data export is ready",
+		Body:  "Download it from the app before " + expiresAt.UTC().Format("2 Jan 2006 15:04 MST") + ".",
+		Data:  map[string]string{"export_id": strconv.FormatUint(export.ID, 10)},
+	})
+}
+
+// writeArchive collects the user's data into a temporary zip file and uploads it
+func (s *dataExportService) writeArchive(ctx context.Context, export *models.DataExport, key string) (int64, error) {
+	tmp, err := os.CreateTemp("", "data-export-*.zip")
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	archive := zip.NewWriter(tmp)
+	if err := s.writeUserData(ctx, archive, export); err != nil {
+		return 0, err
+	}
+	if err := archive.Close(); err != nil {
+		return 0, err
+	}
+
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return s.store.Put(ctx, key, tmp, size, "application/zip")
+}
+
+// writeUserData adds one JSON file per kind of personal data. Soft-deleted
+// rows are included: they are still held until purged.
+func (s *dataExportService) writeUserData(ctx context.Context, archive *zip.Writer, export *models.DataExport) error {
+	db := s.db.WithContext(ctx).Unscoped().Session(&gorm.Session{})
+	userID := export.UserID
+
+	var user models.User
+	if err := db.First(&user, userID).Error; err != nil {
+		return err
+	}
+	var history []models.History
+	if err := db.Where("user_id = ?", userID).Order("created_at").Find(&history).Error; err != nil {
+		return err
+	}
+	var sessions []models.RefreshToken
+	if err := db.Where("user_id = ?", userID).Order("created_at").Find(&sessions).Error; err != nil {
+		return err
+	}
+	var usage []models.UsageDaily
+	if err := db.Where("user_id = ?", userID).Order("day, operation").Find(&usage).Error; err != nil {
+		return err
+	}
+	var contacts []models.EmergencyContact
+	if err := db.Where("user_id = ?", userID).Order("id").Find(&contacts).Error; err != nil {
+		return err
+	}
+	var alerts []models.EmergencyAlert
+	if err := db.Where("user_id = ?", userID).Order("id").Find(&alerts).Error; err != nil {
+		return err
+	}
+	var calls []models.CallLog
+	if err := db.Where("caller_id = ? OR receiver_id = ?", userID, userID).Order("start_time").Find(&calls).Error; err != nil {
+		return err
+	}
+	var preferences []models.UserPreferences
+	if err := db.Where("user_id = ?", userID).Find(&preferences).Error; err != nil {
+		return err
+	}
+	var settings []models.UserSetting
+	if err := db.Where("user_id = ? AND deleted = ?", userID, false).Order(`"key"`).Find(&settings).Error; err != nil {
+		return err
+	}
+
+	files := []struct {
+		name string
+		data any
+	}{
+		{"export.json", map[string]any{"export_id": export.ID, "user_id": userID, "generated_at": time.Now().UTC()}},
+		{"profile.json", user},
+		{"history.json", history},
+		{"sessions.json", sessions},
+		{"usage.json", usage},
+		{"emergency_contacts.json", contacts},
+		{"emergency_alerts.json", alerts},
+		{"calls.json", calls},
+		{"preferences.json", preferences},
+		{"settings.json", settings},
+	}
+	for _, file := range files {
+		w, err := archive.Create(file.name)
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			return fmt.Errorf("%s: %w", file.name, err)
+		}
+	}
+	return nil
+}
+
+func (s *dataExportService) Find(ctx context.Context, userID uint, id uint64) (*models.DataExport, error) {
+	var export models.DataExport
+	err := database.Conn(ctx, s.db).Where("id = ? AND user_id = ?", id, userID).First(&export).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (s *dataExportService) Open(ctx context.Context, userID uint, id uint64) (io.ReadCloser, *models.DataExport, error) {
+	export, err := s.Find(ctx, userID, id)
+	if err != nil || export == nil {
+		return nil, nil, err
+	}
+	if export.Status != models.DataExportReady || s.store == nil {
+		return nil, nil, apperrors.NewAppError(apperrors.ErrCodeConflict, "Export is not available for download", http.StatusConflict).
+			WithDetails(map[string]string{"status": export.Status})
+	}
+
+	archive, err := s.store.Get(ctx, export.ObjectKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return archive, export, nil
+}
+
+func (s *dataExportService) PurgeExpired(ctx context.Context) (int, error) {
+	if s.store == nil {
+		return 0, nil
+	}
+
+	// Go by the stored objects rather than the rows, so archives of purged
+	// users are deleted too
+	objects, err := s.store.List(ctx, s.prefix)
+	if err != nil {
+		return 0, err
+	}
+	cutoff := time.Now().Add(-s.ttl)
+	deleted := 0
+	for _, obj := range objects {
+		if obj.LastModified.After(cutoff) {
+			continue
+		}
+		if err := s.store.Delete(ctx, obj.Key); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+
+	err = s.db.WithContext(ctx).Model(&models.DataExport{}).
+		Where("status = ? AND expires_at < ?", models.DataExportReady, time.Now()).
+		Update("status", models.DataExportExpired).Error
+	return deleted, err
+}
+
+func (s *dataExportService) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RegisterDataExportJobs deletes expired export archives hourly and lets
+// exports in progress finish on shutdown
+func RegisterDataExportJobs(lc fx.Lifecycle, exports DataExportService, log *zap.Logger) {
+	stopChan := make(chan struct{})
+
+	purge := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		deleted, err := exports.PurgeExpired(ctx)
+		if err != nil {
+			log.Warn("Failed to purge expired data exports", zap.Int("deleted", deleted), zap.Error(err))
+			return
+		}
+		if deleted > 0 {
+			log.Info("Expired data exports purged", zap.Int("deleted", deleted))
+		}
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			ticker := time.NewTicker(dataExportPurgeInterval)
+			go func() {
+				for {
+					select {
+					case <-ticker.C:
+						purge()
+					case <-stopChan:
+						ticker.Stop()
+						return
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stopChan)
+			if err := exports.Wait(ctx); err != nil {
+				log.Warn("Data exports still running at shutdown", zap.Error(err))
+			}
+			return nil
+		},
+	})
+}
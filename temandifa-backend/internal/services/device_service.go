@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+)
+
+// DeviceService keeps the app installations users sign in on. A device
+// carries the push token notifications go to, and the refresh tokens issued
+// with its X-Device-ID are bound to it, so unregistering a device signs it
+// out.
+type DeviceService interface {
+	// Register registers the user's device or updates it
+	Register(ctx context.Context, userID uint, input dto.RegisterDeviceRequest) (*models.Device, error)
+	// Unregister removes the user's device and revokes its refresh tokens,
+	// reporting false when there was neither
+	Unregister(ctx context.Context, userID uint, deviceID string) (bool, error)
+}
+
+type deviceService struct {
+	repo   repositories.DeviceRepository
+	tokens TokenService
+	tx     database.TxManager
+}
+
+// NewDeviceService creates a new DeviceService
+func NewDeviceService(repo repositories.DeviceRepository, tokens TokenService, tx database.TxManager) DeviceService {
+	return &deviceService{repo: repo, tokens: tokens, tx: tx}
+}
+
+func (s *deviceService) Register(ctx context.Context, userID uint, input dto.RegisterDeviceRequest) (*models.Device, error) {
+	if !helpers.ValidDeviceID(input.DeviceID) {
+		return nil, apperrors.ValidationWithDetails("Invalid device ID", map[string]string{"device_id": "may only contain letters, digits and . _ : -"})
+	}
+
+	device := &models.Device{
+		UserID:     userID,
+		DeviceID:   input.DeviceID,
+		Platform:   input.Platform,
+		AppVersion: input.AppVersion,
+		LastSeenAt: time.Now(),
+	}
+	if input.PushToken != "" {
+		device.PushToken = &input.PushToken
+	}
+	if err := s.repo.Save(ctx, device); err != nil {
+		return nil, err
+	}
+	return s.repo.Find(ctx, userID, input.DeviceID)
+}
+
+func (s *deviceService) Unregister(ctx context.Context, userID uint, deviceID string) (bool, error) {
+	var found bool
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		deleted, err := s.repo.Delete(ctx, userID, deviceID)
+		if err != nil {
+			return err
+		}
+		revoked, err := s.tokens.RevokeDeviceTokens(ctx, userID, deviceID)
+		if err != nil {
+			return err
+		}
+		found = deleted || revoked > 0
+		return nil
+	})
+	return found, err
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+)
+
+const (
+	// NotificationEmailChange is the type of email change confirmation
+	// messages
+	NotificationEmailChange = "account.email_change"
+	// NotificationEmailChanged tells the user's devices and former address
+	// that the account's email address changed
+	NotificationEmailChanged = "security.email_changed"
+)
+
+var errEmailChangeLinkInvalid = apperrors.NewAppError(apperrors.ErrCodeTokenInvalid, "The confirmation link is invalid or has expired", http.StatusBadRequest)
+
+// EmailChangeService changes users' email addresses once both the current
+// and the new address confirmed, so neither a stolen session nor a typo can
+// move an account to an address its owner doesn't read
+type EmailChangeService interface {
+	// Request holds newEmail as the user's pending address and emails
+	// confirmation links to both addresses. Accounts with a password confirm
+	// with it. A request replaces the pending one and voids its links.
+	Request(ctx context.Context, userID uint, newEmail, password string) error
+	// Confirm uses the token of a confirmation link up. Once both addresses
+	// confirmed, the address changes and every session is signed out.
+	Confirm(ctx context.Context, token string) (*dto.EmailChangeConfirmation, error)
+}
+
+type emailChangeService struct {
+	repo          repositories.VerificationTokenRepository
+	users         repositories.UserRepository
+	tokens        TokenService
+	notifications NotificationService
+	txManager     database.TxManager
+	userCache     UserCacheService
+	cfg           *config.AuthConfig
+	confirmURL    string
+}
+
+// NewEmailChangeService creates a new EmailChangeService
+func NewEmailChangeService(repo repositories.VerificationTokenRepository, users repositories.UserRepository, tokens TokenService, notifications NotificationService, txManager database.TxManager, userCache UserCacheService, cfg *config.Config) EmailChangeService {
+	return &emailChangeService{
+		repo:          repo,
+		users:         users,
+		tokens:        tokens,
+		notifications: notifications,
+		txManager:     txManager,
+		userCache:     userCache,
+		cfg:           &cfg.AuthConfig,
+		confirmURL:    cfg.PublicBaseURL + "/api/v1/auth/change-email/confirm?token=",
+	}
+}
+
+func (s *emailChangeService) Request(ctx context.Context, userID uint, newEmail, password string) error {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return apperrors.Database(err)
+	}
+	if user == nil {
+		return errAccountNotFound
+	}
+	if user.Password != "" && bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+		return errAccountPasswordWrong
+	}
+	if newEmail == user.Email {
+		return apperrors.ValidationWithDetails("Email unchanged", map[string]string{"new_email": "is already the account's address"})
+	}
+	taken, err := s.users.FindByEmail(ctx, newEmail)
+	if err != nil {
+		return apperrors.Database(err)
+	}
+	if taken != nil {
+		return apperrors.AlreadyExists("email")
+	}
+
+	now := time.Now()
+	// Every request sends two links
+	sent, err := s.repo.CountSince(ctx, user.ID, models.TokenPurposeEmailChange, now.Add(-s.cfg.EmailChangeWindow))
+	if err != nil {
+		return apperrors.Database(err)
+	}
+	if sent >= int64(2*s.cfg.EmailChangeLimit) {
+		return apperrors.NewAppError(apperrors.ErrCodeRateLimited, "Too many email change requests, please try again later", http.StatusTooManyRequests)
+	}
+
+	var oldToken, newToken string
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		user.PendingEmail = newEmail
+		user.PendingEmailOldConfirmed = false
+		user.PendingEmailNewConfirmed = false
+		if err := s.users.Update(ctx, user); err != nil {
+			return err
+		}
+		if _, err := s.repo.MarkAllUsed(ctx, user.ID, models.TokenPurposeEmailChange, now); err != nil {
+			return err
+		}
+		if oldToken, err = issueVerificationToken(ctx, s.repo, user, models.TokenPurposeEmailChange, s.cfg.EmailChangeTTL); err != nil {
+			return err
+		}
+		newToken, err = issueVerificationTokenFor(ctx, s.repo, user, newEmail, models.TokenPurposeEmailChange, s.cfg.EmailChangeTTL)
+		return err
+	})
+	if err != nil {
+		if _, ok := apperrors.AsAppError(err); ok {
+			return err
+		}
+		return apperrors.Database(err)
+	}
+
+	logger.Ctx(ctx).Info("Email change requested", zap.Uint("user_id", user.ID))
+	go s.send(context.WithoutCancel(ctx), *user, oldToken, newToken)
+	return nil
+}
+
+// send emails the confirmation links to the current and the pending address
+func (s *emailChangeService) send(ctx context.Context, user models.User, oldToken, newToken string) {
+	validity := linkValidity(s.cfg.EmailChangeTTL)
+	channels := s.notifications.MessageRecipient(ctx, Recipient{Email: user.Email}, Notification{
+		Type:  NotificationEmailChange,
+		Title: "Confirm the change of your TemanDifa email address",
+		Body: fmt.Sprintf("Hi %s,\n\nSomeone, hopefully you, asked to change the email address of your TemanDifa account to %s. "+
+			"To confirm, open this link within %s:\n%s\n\nThe address only changes once the link sent to %s is opened too. "+
+			"If you didn't ask for this, don't open the link, and change your password.",
+			user.FullName, user.PendingEmail, validity, s.confirmURL+oldToken, user.PendingEmail),
+	})
+	if len(channels) == 0 {
+		logger.Ctx(ctx).Warn("Email change confirmation not sent to the current address", zap.Uint("user_id", user.ID))
+	}
+
+	channels = s.notifications.MessageRecipient(ctx, Recipient{Email: user.PendingEmail}, Notification{
+		Type:  NotificationEmailChange,
+		Title: "Confirm your new TemanDifa email address",
+		Body: fmt.Sprintf("Hi %s,\n\nTo make %s the email address of your TemanDifa account, open this link within %s:\n%s\n\n"+
+			"The address only changes once the link sent to your current address is opened too. If you didn't ask for this, you can ignore this email.",
+			user.FullName, user.PendingEmail, validity, s.confirmURL+newToken),
+	})
+	if len(channels) == 0 {
+		logger.Ctx(ctx).Warn("Email change confirmation not sent to the new address", zap.Uint("user_id", user.ID))
+	}
+}
+
+func (s *emailChangeService) Confirm(ctx context.Context, token string) (*dto.EmailChangeConfirmation, error) {
+	var user *models.User
+	var previousEmail string
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		now := time.Now()
+		stored, err := useVerificationToken(ctx, s.repo, token, models.TokenPurposeEmailChange, now)
+		if err != nil {
+			return apperrors.Database(err)
+		}
+		if stored == nil {
+			return errEmailChangeLinkInvalid
+		}
+		user, err = s.users.FindByID(ctx, stored.UserID)
+		if err != nil {
+			return apperrors.Database(err)
+		}
+		if user == nil || user.PendingEmail == "" {
+			return errEmailChangeLinkInvalid
+		}
+
+		// Links of an earlier request, or sent before the address changed,
+		// match neither address
+		switch stored.Email {
+		case user.Email:
+			user.PendingEmailOldConfirmed = true
+		case user.PendingEmail:
+			user.PendingEmailNewConfirmed = true
+		default:
+			return errEmailChangeLinkInvalid
+		}
+
+		if user.PendingEmailOldConfirmed && user.PendingEmailNewConfirmed {
+			// Someone may have taken the address since the request
+			taken, err := s.users.FindByEmail(ctx, user.PendingEmail)
+			if err != nil {
+				return apperrors.Database(err)
+			}
+			if taken != nil {
+				return apperrors.AlreadyExists("email")
+			}
+
+			previousEmail = user.Email
+			user.Email = user.PendingEmail
+			// The new address is verified by its link
+			user.EmailVerifiedAt = &now
+			user.PendingEmail = ""
+			user.PendingEmailOldConfirmed = false
+			user.PendingEmailNewConfirmed = false
+			// The other address's link, if it's still unused, is void now
+			if _, err := s.repo.MarkAllUsed(ctx, user.ID, models.TokenPurposeEmailChange, now); err != nil {
+				return err
+			}
+		}
+		return s.users.Update(ctx, user)
+	})
+	if err != nil {
+		if _, ok := apperrors.AsAppError(err); ok {
+			return nil, err
+		}
+		return nil, apperrors.Database(err)
+	}
+
+	result := &dto.EmailChangeConfirmation{UserID: user.ID, Email: user.Email, PendingEmail: user.PendingEmail, Changed: previousEmail != ""}
+	if !result.Changed {
+		return result, nil
+	}
+
+	// Sessions were signed in as the old address
+	log := logger.Ctx(ctx).With(zap.Uint("user_id", user.ID))
+	revoked, err := s.tokens.RevokeOtherUserTokens(ctx, user.ID, 0)
+	if err != nil {
+		log.Error("Failed to sign out after email change", zap.Error(err))
+	}
+	if err := s.userCache.InvalidateUserCache(ctx, user.ID); err != nil {
+		log.Debug("Failed to invalidate user cache", zap.Error(err))
+	}
+	log.Info("Email changed", zap.Int64("sessions_revoked", revoked))
+
+	changed := Notification{
+		Type:  NotificationEmailChanged,
+		Title: "Your email address was changed",
+		Body:  fmt.Sprintf("The email address of your TemanDifa account is now %s, and every device was signed out. If this wasn't you, contact support right away.", user.Email),
+	}
+	s.notifications.NotifyUser(ctx, user.ID, changed)
+	s.notifications.MessageRecipient(ctx, Recipient{Email: previousEmail}, changed)
+	return result, nil
+}
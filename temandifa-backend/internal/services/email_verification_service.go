@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+)
+
+const (
+	// NotificationEmailVerification is the type of verification link messages
+	NotificationEmailVerification = "account.email_verification"
+
+	// A user may ask for a new verification link once a minute and a few
+	// times a day, so resending can't be used to flood an inbox
+	verificationResendInterval = time.Minute
+	verificationLinksPerDay    = 5
+)
+
+var errVerificationLinkInvalid = apperrors.NewAppError(apperrors.ErrCodeTokenInvalid, "The verification link is invalid or has expired", http.StatusBadRequest)
+
+// EmailVerificationService issues the signed single-use links that verify
+// users' email addresses and checks them
+type EmailVerificationService interface {
+	// Issue stores a new verification token for the user's email, joining the
+	// transaction bound to ctx, and returns the token
+	Issue(ctx context.Context, user *models.User) (string, error)
+	// Send emails the verification link of token to the user, reporting
+	// whether it was delivered
+	Send(ctx context.Context, user *models.User, token string) bool
+	// Resend issues and sends a new link unless the user is verified or asked
+	// too often
+	Resend(ctx context.Context, user models.User) error
+	// Verify uses the token up and marks its email verified
+	Verify(ctx context.Context, token string) (*dto.EmailVerificationResponse, error)
+	// CleanupExpired deletes expired tokens and returns how many
+	CleanupExpired(ctx context.Context) (int64, error)
+}
+
+type emailVerificationService struct {
+	repo          repositories.VerificationTokenRepository
+	users         repositories.UserRepository
+	notifications NotificationService
+	txManager     database.TxManager
+	userCache     UserCacheService
+	ttl           time.Duration
+	verifyURL     string
+}
+
+// NewEmailVerificationService creates a new EmailVerificationService
+func NewEmailVerificationService(repo repositories.VerificationTokenRepository, users repositories.UserRepository, notifications NotificationService, txManager database.TxManager, userCache UserCacheService, cfg *config.Config) EmailVerificationService {
+	return &emailVerificationService{
+		repo:          repo,
+		users:         users,
+		notifications: notifications,
+		txManager:     txManager,
+		userCache:     userCache,
+		ttl:           cfg.EmailVerificationTTL,
+		verifyURL:     cfg.PublicBaseURL + "/api/v1/verify-email?token=",
+	}
+}
+
+func (s *emailVerificationService) Issue(ctx context.Context, user *models.User) (string, error) {
+	return issueVerificationToken(ctx, s.repo, user, models.TokenPurposeEmailVerification, s.ttl)
+}
+
+func (s *emailVerificationService) Send(ctx context.Context, user *models.User, token string) bool {
+	channels := s.notifications.MessageRecipient(ctx, Recipient{Email: user.Email}, Notification{
+		Type:  NotificationEmailVerification,
+		Title: "Verify your TemanDifa email address",
+		Body: fmt.Sprintf("Hi %s,\n\nTo verify the email address of your TemanDifa account, open this link within %s:\n%s\n\n"+
+			"If you didn't create an account, you can ignore this email.", user.FullName, linkValidity(s.ttl), s.verifyURL+token),
+	})
+	if len(channels) == 0 {
+		logger.Ctx(ctx).Warn("Verification email not sent", zap.Uint("user_id", user.ID))
+		return false
+	}
+	return true
+}
+
+// linkValidity words how long a link stays valid, e.g. "48 hours"
+func linkValidity(ttl time.Duration) string {
+	if ttl >= time.Hour {
+		return fmt.Sprintf("%d hours", int(ttl.Hours()))
+	}
+	return fmt.Sprintf("%d minutes", max(int(ttl.Minutes()), 1))
+}
+
+func (s *emailVerificationService) Resend(ctx context.Context, user models.User) error {
+	if user.EmailVerifiedAt != nil {
+		return apperrors.NewAppError(apperrors.ErrCodeConflict, "Email address is already verified", http.StatusConflict)
+	}
+
+	now := time.Now()
+	recent, err := s.repo.CountSince(ctx, user.ID, models.TokenPurposeEmailVerification, now.Add(-verificationResendInterval))
+	if err != nil {
+		return apperrors.Database(err)
+	}
+	today, err := s.repo.CountSince(ctx, user.ID, models.TokenPurposeEmailVerification, now.Add(-24*time.Hour))
+	if err != nil {
+		return apperrors.Database(err)
+	}
+	if recent > 0 || today >= verificationLinksPerDay {
+		return apperrors.NewAppError(apperrors.ErrCodeRateLimited, "A verification email was sent recently, please check your inbox", http.StatusTooManyRequests)
+	}
+
+	token, err := s.Issue(ctx, &user)
+	if err != nil {
+		return apperrors.Database(err)
+	}
+	if !s.Send(ctx, &user, token) {
+		return apperrors.NewAppError(apperrors.ErrCodeExternalService, "The verification email could not be sent, please try again later", http.StatusServiceUnavailable)
+	}
+	return nil
+}
+
+func (s *emailVerificationService) Verify(ctx context.Context, token string) (*dto.EmailVerificationResponse, error) {
+	var result *dto.EmailVerificationResponse
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		now := time.Now()
+		stored, err := useVerificationToken(ctx, s.repo, token, models.TokenPurposeEmailVerification, now)
+		if err != nil {
+			return apperrors.Database(err)
+		}
+		if stored == nil {
+			return errVerificationLinkInvalid
+		}
+
+		verified, err := s.users.MarkEmailVerified(ctx, stored.UserID, stored.Email, now)
+		if err != nil {
+			return apperrors.Database(err)
+		}
+		if !verified {
+			// Verified through another link already, or the account's email
+			// changed since the link was sent
+			user, err := s.users.FindByID(ctx, stored.UserID)
+			if err != nil {
+				return apperrors.Database(err)
+			}
+			if user == nil || user.Email != stored.Email || user.EmailVerifiedAt == nil {
+				return errVerificationLinkInvalid
+			}
+			now = *user.EmailVerifiedAt
+		}
+
+		result = &dto.EmailVerificationResponse{UserID: stored.UserID, Email: stored.Email, EmailVerifiedAt: now}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Cached users carry the verification state the AI endpoints check
+	if err := s.userCache.InvalidateUserCache(ctx, result.UserID); err != nil {
+		logger.Ctx(ctx).Debug("Failed to invalidate user cache", zap.Uint("user_id", result.UserID), zap.Error(err))
+	}
+	return result, nil
+}
+
+func (s *emailVerificationService) CleanupExpired(ctx context.Context) (int64, error) {
+	return s.repo.DeleteExpired(ctx, time.Now())
+}
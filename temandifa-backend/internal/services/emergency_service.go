@@ -0,0 +1,386 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+)
+
+const (
+	// consentResendInterval is how long a user waits before asking a contact
+	// again
+	consentResendInterval = 24 * time.Hour
+	// consentRequestsPerDay caps the consent requests a user sends per day,
+	// counting those to contacts they deleted since
+	consentRequestsPerDay = 10
+	// lastSceneMaxAge is how recent an object detection must be to stand in
+	// for the scene description of an alert
+	lastSceneMaxAge = 15 * time.Minute
+)
+
+var (
+	errNoConsentingContacts = apperrors.NewAppError(apperrors.ErrCodeConflict, "No emergency contact has agreed to receive alerts", http.StatusConflict)
+	errConsentDeclined      = apperrors.NewAppError(apperrors.ErrCodeConflict, "The contact declined to receive alerts", http.StatusConflict)
+	errConsentAccepted      = apperrors.NewAppError(apperrors.ErrCodeConflict, "The contact already agreed to receive alerts", http.StatusConflict)
+	errNoContactChannel     = apperrors.ValidationWithDetails("Validation failed", map[string]string{"phone_number": "A phone number or an email address is required"})
+)
+
+// EmergencyService manages the users' emergency contacts and sends their
+// emergency alerts by SMS and email (through NotificationService). Contacts
+// are asked whether they agree to receive alerts and only those who agreed
+// receive any; the link in the request stays valid so they can withdraw
+// later. Alerts are limited to EMERGENCY_ALERT_LIMIT per
+// EMERGENCY_ALERT_WINDOW.
+type EmergencyService interface {
+	ListContacts(ctx context.Context, userID uint) ([]models.EmergencyContact, error)
+	// CreateContact adds a contact and sends them a consent request
+	CreateContact(ctx context.Context, user models.User, input dto.EmergencyContactRequest) (*dto.EmergencyContactResult, error)
+	// UpdateContact replaces a contact; a new phone number or email address
+	// needs the contact's consent again. Returns nil when the user has no
+	// such contact.
+	UpdateContact(ctx context.Context, user models.User, contactID uint, input dto.EmergencyContactRequest) (*dto.EmergencyContactResult, error)
+	// DeleteContact reports whether the user had the contact
+	DeleteContact(ctx context.Context, userID, contactID uint) (bool, error)
+	// RequestConsent asks a contact who hasn't answered again, at most once
+	// per day. Returns nil when the user has no such contact.
+	RequestConsent(ctx context.Context, user models.User, contactID uint) (*dto.EmergencyContactResult, error)
+	// Consent returns the consent request sent with the token, nil when
+	// there is none
+	Consent(ctx context.Context, token string) (*dto.EmergencyConsent, error)
+	// AnswerConsent records a contact's answer; it returns nil when there is
+	// no consent request with the token
+	AnswerConsent(ctx context.Context, token string, accept bool) (*dto.EmergencyConsent, error)
+	// SendAlert sends the user's context to the contacts who agreed to
+	// receive alerts
+	SendAlert(ctx context.Context, user models.User, input dto.EmergencyAlertRequest) (*dto.EmergencyAlertResult, error)
+}
+
+type emergencyService struct {
+	repo          repositories.EmergencyRepository
+	users         repositories.UserRepository
+	history       repositories.HistoryRepository
+	notifications NotificationService
+	tx            database.TxManager
+	consentURL    string
+	maxContacts   int
+	alertLimit    int
+	alertWindow   time.Duration
+}
+
+// NewEmergencyService creates a new EmergencyService
+func NewEmergencyService(repo repositories.EmergencyRepository, users repositories.UserRepository, history repositories.HistoryRepository, notifications NotificationService, tx database.TxManager, cfg *config.Config) EmergencyService {
+	return &emergencyService{
+		repo:          repo,
+		users:         users,
+		history:       history,
+		notifications: notifications,
+		tx:            tx,
+		consentURL:    cfg.PublicBaseURL + "/api/v1/emergency-contacts/consent/",
+		maxContacts:   cfg.EmergencyMaxContacts,
+		alertLimit:    cfg.EmergencyAlertLimit,
+		alertWindow:   cfg.EmergencyAlertWindow,
+	}
+}
+
+func (s *emergencyService) ListContacts(ctx context.Context, userID uint) ([]models.EmergencyContact, error) {
+	return s.repo.ListContacts(ctx, userID)
+}
+
+func (s *emergencyService) CreateContact(ctx context.Context, user models.User, input dto.EmergencyContactRequest) (*dto.EmergencyContactResult, error) {
+	if input.PhoneNumber == "" && input.Email == "" {
+		return nil, errNoContactChannel
+	}
+	count, err := s.repo.CountContacts(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= int64(s.maxContacts) {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeConflict, fmt.Sprintf("A user can have at most %d emergency contacts", s.maxContacts), http.StatusConflict)
+	}
+
+	contact := &models.EmergencyContact{UserID: user.ID, ConsentStatus: models.ConsentPending}
+	applyContactInput(contact, input)
+	if err := s.repo.SaveContact(ctx, contact); err != nil {
+		return nil, err
+	}
+	return s.sendConsentRequest(ctx, user, contact)
+}
+
+func (s *emergencyService) UpdateContact(ctx context.Context, user models.User, contactID uint, input dto.EmergencyContactRequest) (*dto.EmergencyContactResult, error) {
+	if input.PhoneNumber == "" && input.Email == "" {
+		return nil, errNoContactChannel
+	}
+	contact, err := s.repo.FindContact(ctx, user.ID, contactID)
+	if err != nil || contact == nil {
+		return nil, err
+	}
+
+	reachedElsewhere := contact.PhoneNumber != input.PhoneNumber || contact.Email != input.Email
+	applyContactInput(contact, input)
+	if !reachedElsewhere {
+		if err := s.repo.SaveContact(ctx, contact); err != nil {
+			return nil, err
+		}
+		return &dto.EmergencyContactResult{EmergencyContact: *contact, ConsentRequestChannels: []string{}}, nil
+	}
+
+	// The consent was given for the old phone number and email address
+	contact.ConsentStatus = models.ConsentPending
+	contact.ConsentTokenHash = nil
+	contact.ConsentAnsweredAt = nil
+	if err := s.repo.SaveContact(ctx, contact); err != nil {
+		return nil, err
+	}
+	return s.sendConsentRequest(ctx, user, contact)
+}
+
+// applyContactInput copies the request's fields to the contact
+func applyContactInput(contact *models.EmergencyContact, input dto.EmergencyContactRequest) {
+	contact.Name = input.Name
+	contact.PhoneNumber = input.PhoneNumber
+	contact.Email = input.Email
+	contact.Relation = input.Relation
+	contact.IsPrimary = input.IsPrimary
+}
+
+func (s *emergencyService) DeleteContact(ctx context.Context, userID, contactID uint) (bool, error) {
+	return s.repo.DeleteContact(ctx, userID, contactID)
+}
+
+func (s *emergencyService) RequestConsent(ctx context.Context, user models.User, contactID uint) (*dto.EmergencyContactResult, error) {
+	contact, err := s.repo.FindContact(ctx, user.ID, contactID)
+	if err != nil || contact == nil {
+		return nil, err
+	}
+	switch contact.ConsentStatus {
+	case models.ConsentAccepted:
+		return nil, errConsentAccepted
+	case models.ConsentDeclined:
+		return nil, errConsentDeclined
+	}
+	if contact.ConsentRequestedAt != nil && time.Since(*contact.ConsentRequestedAt) < consentResendInterval {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeRateLimited, "The contact was asked less than a day ago", http.StatusTooManyRequests)
+	}
+	return s.sendConsentRequest(ctx, user, contact)
+}
+
+// sendConsentRequest sends the contact a new consent link, unless the user
+// sent too many consent requests today
+func (s *emergencyService) sendConsentRequest(ctx context.Context, user models.User, contact *models.EmergencyContact) (*dto.EmergencyContactResult, error) {
+	result := &dto.EmergencyContactResult{ConsentRequestChannels: []string{}}
+	sent, err := s.repo.CountConsentRequestsSince(ctx, user.ID, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	if sent >= consentRequestsPerDay {
+		logger.Ctx(ctx).Warn("Consent request not sent: daily limit reached", zap.Uint("contact_id", contact.ID))
+		result.EmergencyContact = *contact
+		return result, nil
+	}
+
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	hash := consentTokenHash(token)
+	requestedAt := contact.ConsentRequestedAt
+	now := time.Now()
+	contact.ConsentTokenHash = &hash
+	contact.ConsentRequestedAt = &now
+	if err := s.repo.SaveContact(ctx, contact); err != nil {
+		return nil, err
+	}
+
+	channels := s.notifications.MessageRecipient(ctx, recipientOf(contact), Notification{
+		Type:  NotificationConsentRequest,
+		Title: user.FullName + " wants you as their emergency contact",
+		Body: fmt.Sprintf("%s added you as an emergency contact on TemanDifa. To receive their emergency alerts by SMS or email, answer here: %s\n"+
+			"You won't receive any alert unless you agree.", user.FullName, s.consentURL+token),
+	})
+	if len(channels) == 0 {
+		// Nothing was sent, so the user may try again right away
+		contact.ConsentRequestedAt = requestedAt
+		if err := s.repo.SaveContact(ctx, contact); err != nil {
+			return nil, err
+		}
+	}
+	result.EmergencyContact = *contact
+	result.ConsentRequestChannels = channels
+	return result, nil
+}
+
+// consentTokenHash is the stored form of a consent token
+func consentTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// recipientOf addresses messages to the contact
+func recipientOf(contact *models.EmergencyContact) Recipient {
+	return Recipient{PhoneNumber: contact.PhoneNumber, Email: contact.Email}
+}
+
+func (s *emergencyService) Consent(ctx context.Context, token string) (*dto.EmergencyConsent, error) {
+	contact, err := s.repo.FindContactByConsentToken(ctx, consentTokenHash(token))
+	if err != nil || contact == nil {
+		return nil, err
+	}
+	return s.consentOf(ctx, contact)
+}
+
+func (s *emergencyService) AnswerConsent(ctx context.Context, token string, accept bool) (*dto.EmergencyConsent, error) {
+	contact, err := s.repo.FindContactByConsentToken(ctx, consentTokenHash(token))
+	if err != nil || contact == nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	contact.ConsentStatus = models.ConsentDeclined
+	if accept {
+		contact.ConsentStatus = models.ConsentAccepted
+	}
+	contact.ConsentAnsweredAt = &now
+	if err := s.repo.SaveContact(ctx, contact); err != nil {
+		return nil, err
+	}
+	logger.Ctx(ctx).Info("Emergency contact answered consent request",
+		zap.Uint("contact_id", contact.ID),
+		zap.String("consent_status", contact.ConsentStatus),
+	)
+	return s.consentOf(ctx, contact)
+}
+
+// consentOf describes the contact's consent request
+func (s *emergencyService) consentOf(ctx context.Context, contact *models.EmergencyContact) (*dto.EmergencyConsent, error) {
+	user, err := s.users.FindByID(ctx, contact.UserID)
+	if err != nil || user == nil {
+		return nil, err
+	}
+	return &dto.EmergencyConsent{
+		RequestedBy:   user.FullName,
+		ContactName:   contact.Name,
+		ConsentStatus: contact.ConsentStatus,
+	}, nil
+}
+
+func (s *emergencyService) SendAlert(ctx context.Context, user models.User, input dto.EmergencyAlertRequest) (*dto.EmergencyAlertResult, error) {
+	contacts, err := s.repo.ListConsentingContacts(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(contacts) == 0 {
+		return nil, errNoConsentingContacts
+	}
+
+	alert := &models.EmergencyAlert{
+		UserID:           user.ID,
+		Latitude:         input.Latitude,
+		Longitude:        input.Longitude,
+		SceneDescription: strings.TrimSpace(input.SceneDescription),
+	}
+	if alert.SceneDescription == "" {
+		alert.SceneDescription = s.lastScene(ctx, user.ID)
+	}
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		sent, err := s.repo.CountAlertsSince(ctx, user.ID, time.Now().Add(-s.alertWindow))
+		if err != nil {
+			return err
+		}
+		if sent >= int64(s.alertLimit) {
+			return apperrors.NewAppError(apperrors.ErrCodeRateLimited, fmt.Sprintf("At most %d emergency alerts can be sent per %s", s.alertLimit, s.alertWindow), http.StatusTooManyRequests)
+		}
+		return s.repo.CreateAlert(ctx, alert)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	notification := s.alertNotification(user, alert, strings.TrimSpace(input.Message))
+	result := &dto.EmergencyAlertResult{AlertID: alert.ID, Deliveries: make([]dto.EmergencyAlertDelivery, len(contacts))}
+	var wg sync.WaitGroup
+	for i := range contacts {
+		contact := &contacts[i]
+		result.Deliveries[i] = dto.EmergencyAlertDelivery{ContactID: contact.ID, Name: contact.Name}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result.Deliveries[i].Channels = s.notifications.MessageRecipient(ctx, recipientOf(contact), notification)
+		}()
+	}
+	wg.Wait()
+
+	for _, delivery := range result.Deliveries {
+		if len(delivery.Channels) > 0 {
+			result.ContactsNotified++
+		}
+	}
+	if err := s.repo.SetContactsNotified(context.WithoutCancel(ctx), alert.ID, result.ContactsNotified); err != nil {
+		logger.Ctx(ctx).Warn("Failed to record emergency alert deliveries", zap.Uint64("alert_id", alert.ID), zap.Error(err))
+	}
+	logger.Ctx(ctx).Info("Emergency alert sent",
+		zap.Uint64("alert_id", alert.ID),
+		zap.Int("contacts", len(contacts)),
+		zap.Int("contacts_notified", result.ContactsNotified),
+	)
+	return result, nil
+}
+
+// lastScene returns the user's latest object detection if it is recent
+// enough, otherwise nothing
+func (s *emergencyService) lastScene(ctx context.Context, userID uint) string {
+	spec := repositories.NewSpec().
+		Where("user_id = ?", userID).
+		Where("feature_type = ?", models.FeatureObject).
+		Where("created_at >= ?", time.Now().Add(-lastSceneMaxAge)).
+		OrderBy("created_at", true).
+		Paginate(1, 0)
+	entries, _, err := s.history.List(ctx, spec)
+	if err != nil {
+		logger.Ctx(ctx).Warn("Failed to load the last scene for an emergency alert", zap.Error(err))
+		return ""
+	}
+	if len(entries) == 0 {
+		return ""
+	}
+	return entries[0].ResultText
+}
+
+// alertNotification is the text of an alert to the contacts
+func (s *emergencyService) alertNotification(user models.User, alert *models.EmergencyAlert, message string) Notification {
+	var body strings.Builder
+	fmt.Fprintf(&body, "EMERGENCY: %s sent an emergency alert from TemanDifa and may need help.", user.FullName)
+	if message != "" {
+		fmt.Fprintf(&body, "\nMessage: %s", message)
+	}
+	if alert.Latitude != nil && alert.Longitude != nil {
+		fmt.Fprintf(&body, "\nLocation: https://maps.google.com/?q=%.6f,%.6f", *alert.Latitude, *alert.Longitude)
+	}
+	if alert.SceneDescription != "" {
+		fmt.Fprintf(&body, "\nAround them: %s", alert.SceneDescription)
+	}
+	fmt.Fprintf(&body, "\nSent at %s", alert.CreatedAt.UTC().Format("2006-01-02 15:04 UTC"))
+
+	return Notification{
+		Type:  NotificationEmergencyAlert,
+		Title: "Emergency alert from " + user.FullName,
+		Body:  body.String(),
+	}
+}
@@ -0,0 +1,314 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/metrics"
+)
+
+// Product analytics event types
+const (
+	EventAIRequestCompleted = "ai_request_completed"
+	EventHistoryCreated     = "history_created"
+	EventLogin              = "login"
+)
+
+// Supported event sinks (EVENTS_SINK)
+const (
+	EventSinkNone = "none"
+	EventSinkLog  = "log"
+	EventSinkHTTP = "http"
+)
+
+const (
+	// eventSendTimeout bounds a single batch delivery attempt
+	eventSendTimeout = 10 * time.Second
+	// eventDrainTimeout bounds flushing buffered events on shutdown
+	eventDrainTimeout = 5 * time.Second
+)
+
+// eventRetryConfig retries batch delivery briefly; the buffer keeps filling meanwhile
+var eventRetryConfig = helpers.RetryConfig{
+	MaxRetries:     2,
+	InitialBackoff: 200 * time.Millisecond,
+	MaxBackoff:     2 * time.Second,
+	Multiplier:     2.0,
+	Jitter:         0.25,
+}
+
+// Event is a structured product usage event
+type Event struct {
+	ID         string         `json:"id"`
+	Type       string         `json:"type"`
+	UserID     uint           `json:"user_id,omitempty"`
+	RequestID  string         `json:"request_id,omitempty"`
+	Timestamp  time.Time      `json:"timestamp"`
+	Properties map[string]any `json:"properties,omitempty"`
+}
+
+// EventSink publishes batches of events to an analytics backend
+type EventSink interface {
+	Name() string
+	Publish(ctx context.Context, events []Event) error
+}
+
+// EventService buffers usage events and publishes them in batches so
+// analytics never depends on scraping logs or slows down requests
+type EventService interface {
+	// Emit enqueues an event without blocking. When the buffer is full the
+	// event is dropped (and counted) rather than applying backpressure to requests.
+	Emit(ctx context.Context, event Event)
+	Start()
+	Stop()
+}
+
+type eventService struct {
+	sink          EventSink
+	queue         chan Event
+	batchSize     int
+	flushInterval time.Duration
+	log           *zap.Logger
+
+	stopChan chan struct{}
+	doneChan chan struct{}
+}
+
+// NewEventService creates an event service publishing to the configured sink
+func NewEventService(cfg *config.Config, log *zap.Logger) EventService {
+	return &eventService{
+		sink:          newEventSink(cfg, log),
+		queue:         make(chan Event, cfg.EventsBufferSize),
+		batchSize:     cfg.EventsBatchSize,
+		flushInterval: cfg.EventsFlushInterval,
+		log:           log,
+		stopChan:      make(chan struct{}),
+		doneChan:      make(chan struct{}),
+	}
+}
+
+// newEventSink builds the sink selected by EVENTS_SINK; nil disables events
+func newEventSink(cfg *config.Config, log *zap.Logger) EventSink {
+	switch cfg.EventsSink {
+	case EventSinkLog:
+		return &logEventSink{log: log}
+	case EventSinkHTTP:
+		return &httpEventSink{
+			url:    cfg.EventsHTTPURL,
+			token:  cfg.EventsHTTPToken,
+			client: &http.Client{Timeout: eventSendTimeout},
+		}
+	default:
+		return nil
+	}
+}
+
+func (s *eventService) Emit(ctx context.Context, event Event) {
+	if s.sink == nil {
+		return
+	}
+
+	if event.ID == "" {
+		event.ID = newEventID()
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+	if event.UserID == 0 {
+		event.UserID = logger.UserIDFromContext(ctx)
+	}
+	if event.RequestID == "" {
+		event.RequestID = logger.RequestIDFromContext(ctx)
+	}
+
+	select {
+	case s.queue <- event:
+		metrics.EventsEmitted.WithLabelValues(event.Type, "queued").Inc()
+	default:
+		metrics.EventsEmitted.WithLabelValues(event.Type, "dropped").Inc()
+		logger.Ctx(ctx).Debug("Event buffer full, dropping event", zap.String("type", event.Type))
+	}
+	metrics.EventQueueDepth.Set(float64(len(s.queue)))
+}
+
+// Start launches the batching worker
+func (s *eventService) Start() {
+	if s.sink == nil {
+		close(s.doneChan)
+		return
+	}
+
+	s.log.Info("Event pipeline started",
+		zap.String("sink", s.sink.Name()),
+		zap.Int("buffer_size", cap(s.queue)),
+		zap.Int("batch_size", s.batchSize),
+		zap.Duration("flush_interval", s.flushInterval),
+	)
+	go s.run()
+}
+
+// Stop flushes buffered events and stops the worker
+func (s *eventService) Stop() {
+	close(s.stopChan)
+	<-s.doneChan
+}
+
+// run collects events into batches, flushing when a batch fills or the interval elapses
+func (s *eventService) run() {
+	defer close(s.doneChan)
+
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Event, 0, s.batchSize)
+	for {
+		select {
+		case event := <-s.queue:
+			batch = append(batch, event)
+			if len(batch) >= s.batchSize {
+				batch = s.flush(context.Background(), batch)
+			}
+		case <-ticker.C:
+			batch = s.flush(context.Background(), batch)
+		case <-s.stopChan:
+			s.drain(batch)
+			return
+		}
+	}
+}
+
+// drain publishes everything still buffered, bounded by eventDrainTimeout
+func (s *eventService) drain(batch []Event) {
+	ctx, cancel := context.WithTimeout(context.Background(), eventDrainTimeout)
+	defer cancel()
+
+	for {
+		select {
+		case event := <-s.queue:
+			batch = append(batch, event)
+			if len(batch) >= s.batchSize {
+				batch = s.flush(ctx, batch)
+			}
+		default:
+			s.flush(ctx, batch)
+			return
+		}
+	}
+}
+
+// flush publishes a batch and returns the emptied slice for reuse
+func (s *eventService) flush(ctx context.Context, batch []Event) []Event {
+	metrics.EventQueueDepth.Set(float64(len(s.queue)))
+	if len(batch) == 0 {
+		return batch
+	}
+
+	err := helpers.WithRetry(ctx, eventRetryConfig, "publish_events", func() error {
+		return s.sink.Publish(ctx, batch)
+	})
+	if err != nil {
+		metrics.EventsPublished.WithLabelValues(s.sink.Name(), "failure").Add(float64(len(batch)))
+		s.log.Warn("Failed to publish events",
+			zap.String("sink", s.sink.Name()),
+			zap.Int("count", len(batch)),
+			zap.Error(err),
+		)
+	} else {
+		metrics.EventsPublished.WithLabelValues(s.sink.Name(), "success").Add(float64(len(batch)))
+	}
+	return batch[:0]
+}
+
+// RegisterEventService registers the event pipeline with fx lifecycle
+func RegisterEventService(lc fx.Lifecycle, events EventService) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			events.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			events.Stop()
+			return nil
+		},
+	})
+}
+
+// newEventID returns a random 128-bit hex identifier for deduplication downstream
+func newEventID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ---- Sinks ----
+
+// logEventSink writes events as structured log lines (useful for local development)
+type logEventSink struct {
+	log *zap.Logger
+}
+
+func (s *logEventSink) Name() string { return EventSinkLog }
+
+func (s *logEventSink) Publish(_ context.Context, events []Event) error {
+	for _, event := range events {
+		s.log.Info("Analytics event",
+			zap.String("event_id", event.ID),
+			zap.String("event_type", event.Type),
+			zap.Uint("user_id", event.UserID),
+			zap.String("request_id", event.RequestID),
+			zap.Time("timestamp", event.Timestamp),
+			zap.Any("properties", event.Properties),
+		)
+	}
+	return nil
+}
+
+// httpEventSink POSTs batches as {"events": [...]} to a collector endpoint
+type httpEventSink struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func (s *httpEventSink) Name() string { return EventSinkHTTP }
+
+func (s *httpEventSink) Publish(ctx context.Context, events []Event) error {
+	body, err := json.Marshal(map[string][]Event{"events": events})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
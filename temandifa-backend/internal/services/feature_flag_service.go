@@ -0,0 +1,321 @@
+package services
+
+import (
+	"context"
+	"hash/fnv"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/database"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+)
+
+// Audit actions and target type of feature flag management
+const (
+	auditTargetFeatureFlag   = "feature_flag"
+	auditFlagSaved           = "feature_flag.saved"
+	auditFlagDeleted         = "feature_flag.deleted"
+	auditFlagOverrideSet     = "feature_flag.override_set"
+	auditFlagOverrideDeleted = "feature_flag.override_deleted"
+)
+
+// flagNamePattern restricts flag names so they map to FEATURE_<NAME> keys
+var flagNamePattern = regexp.MustCompile(`^[a-z0-9_]{1,64}$`)
+
+// FlagSubject identifies who a flag is evaluated for. A zero UserID means an
+// anonymous caller, who only gets flags rolled out to 100%.
+type FlagSubject struct {
+	UserID uint
+	Email  string
+}
+
+// Organization returns the subject's organization, its email domain
+func (s FlagSubject) Organization() string {
+	_, domain, ok := strings.Cut(s.Email, "@")
+	if !ok {
+		return ""
+	}
+	return strings.ToLower(domain)
+}
+
+// FeatureFlagService evaluates and manages feature flags. A flag is decided,
+// in order, by a user override, an organization override, the FEATURE_<NAME>
+// config value (environment or remote store), then the persisted flag's
+// switch and rollout percentage.
+type FeatureFlagService interface {
+	IsEnabled(ctx context.Context, name string, subject FlagSubject) bool
+	// Evaluate returns every known flag's value for subject
+	Evaluate(ctx context.Context, subject FlagSubject) map[string]bool
+	List(ctx context.Context) ([]models.FeatureFlag, error)
+	Save(ctx context.Context, flag *models.FeatureFlag) error
+	// Delete, SetOverride and DeleteOverride report false when the flag (or
+	// override) doesn't exist
+	Delete(ctx context.Context, name string) (bool, error)
+	SetOverride(ctx context.Context, override *models.FeatureFlagOverride) (bool, error)
+	DeleteOverride(ctx context.Context, name, subjectType, subject string) (bool, error)
+	// Refresh reloads the persisted flags; other instances pick up changes
+	// within FLAGS_REFRESH_INTERVAL
+	Refresh(ctx context.Context) error
+}
+
+// flagState is a persisted flag with its overrides indexed by subject
+type flagState struct {
+	flag          models.FeatureFlag
+	users         map[string]bool
+	organizations map[string]bool
+}
+
+type featureFlagService struct {
+	repo    repositories.FeatureFlagRepository
+	dynamic *config.Dynamic
+	audit   AuditService
+	tx      database.TxManager
+	flags   atomic.Pointer[map[string]*flagState]
+}
+
+// NewFeatureFlagService creates a new FeatureFlagService; flags are loaded
+// by RegisterFeatureFlagRefresh
+func NewFeatureFlagService(repo repositories.FeatureFlagRepository, dynamic *config.Dynamic, audit AuditService, tx database.TxManager) FeatureFlagService {
+	s := &featureFlagService{
+		repo:    repo,
+		dynamic: dynamic,
+		audit:   audit,
+		tx:      tx,
+	}
+	s.flags.Store(&map[string]*flagState{})
+	return s
+}
+
+func (s *featureFlagService) IsEnabled(ctx context.Context, name string, subject FlagSubject) bool {
+	name = strings.ToLower(name)
+	state := (*s.flags.Load())[name]
+
+	if state != nil && subject.UserID != 0 {
+		if enabled, ok := state.users[strconv.FormatUint(uint64(subject.UserID), 10)]; ok {
+			return enabled
+		}
+	}
+	if state != nil {
+		if enabled, ok := state.organizations[subject.Organization()]; ok && subject.Organization() != "" {
+			return enabled
+		}
+	}
+	if enabled, ok := s.dynamic.FeatureOverride(name); ok {
+		return enabled
+	}
+	if state == nil || !state.flag.Enabled {
+		return false
+	}
+	return inRollout(name, subject.UserID, state.flag.RolloutPercentage)
+}
+
+// inRollout buckets users by a hash of flag and user ID, so each flag's
+// rollout reaches a stable but different subset of users
+func inRollout(name string, userID uint, percentage int) bool {
+	if percentage >= 100 {
+		return true
+	}
+	if userID == 0 || percentage <= 0 {
+		return false
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name + ":" + strconv.FormatUint(uint64(userID), 10)))
+	return int(h.Sum32()%100) < percentage
+}
+
+func (s *featureFlagService) Evaluate(ctx context.Context, subject FlagSubject) map[string]bool {
+	flags := *s.flags.Load()
+	result := make(map[string]bool, len(flags))
+	for name := range flags {
+		result[name] = s.IsEnabled(ctx, name, subject)
+	}
+	for _, name := range s.dynamic.FeatureNames() {
+		if _, ok := result[name]; !ok {
+			result[name] = s.IsEnabled(ctx, name, subject)
+		}
+	}
+	return result
+}
+
+func (s *featureFlagService) List(ctx context.Context) ([]models.FeatureFlag, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *featureFlagService) Save(ctx context.Context, flag *models.FeatureFlag) error {
+	flag.Name = strings.ToLower(flag.Name)
+	if !flagNamePattern.MatchString(flag.Name) {
+		return apperrors.ValidationWithDetails("Invalid flag name", map[string]string{"name": "lower-case letters, digits and underscores (max 64)"})
+	}
+	if flag.RolloutPercentage < 0 || flag.RolloutPercentage > 100 {
+		return apperrors.ValidationWithDetails("Invalid rollout percentage", map[string]string{"rollout_percentage": "must be between 0 and 100"})
+	}
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		return s.change(ctx, auditFlagSaved, flag.Name, func() (bool, error) {
+			return true, s.repo.Save(ctx, flag)
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return s.Refresh(ctx)
+}
+
+// change runs apply, a change to the named flag or its overrides, and
+// audits it with the flag before and after unless apply reports that
+// nothing changed. Call it inside a transaction.
+func (s *featureFlagService) change(ctx context.Context, action, name string, apply func() (bool, error)) error {
+	before, err := s.repo.Find(ctx, name)
+	if err != nil {
+		return err
+	}
+	if changed, err := apply(); err != nil || !changed {
+		return err
+	}
+	after, err := s.repo.Find(ctx, name)
+	if err != nil {
+		return err
+	}
+	return s.audit.RecordChange(ctx, action, auditTargetFeatureFlag, name, before, after)
+}
+
+func (s *featureFlagService) Delete(ctx context.Context, name string) (bool, error) {
+	name = strings.ToLower(name)
+	var deleted bool
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		return s.change(ctx, auditFlagDeleted, name, func() (bool, error) {
+			var err error
+			deleted, err = s.repo.Delete(ctx, name)
+			return deleted, err
+		})
+	})
+	if err != nil || !deleted {
+		return false, err
+	}
+	return true, s.Refresh(ctx)
+}
+
+func (s *featureFlagService) SetOverride(ctx context.Context, override *models.FeatureFlagOverride) (bool, error) {
+	override.FlagName = strings.ToLower(override.FlagName)
+	switch override.SubjectType {
+	case models.FlagSubjectUser:
+		if _, err := strconv.ParseUint(override.Subject, 10, 64); err != nil {
+			return false, apperrors.ValidationWithDetails("Invalid override subject", map[string]string{"subject": "must be a user ID"})
+		}
+	case models.FlagSubjectOrganization:
+		override.Subject = strings.ToLower(strings.TrimPrefix(override.Subject, "@"))
+		if override.Subject == "" || !strings.Contains(override.Subject, ".") {
+			return false, apperrors.ValidationWithDetails("Invalid override subject", map[string]string{"subject": "must be an email domain"})
+		}
+	default:
+		return false, apperrors.ValidationWithDetails("Invalid override subject type", map[string]string{"subject_type": "must be user or organization"})
+	}
+
+	var found bool
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		return s.change(ctx, auditFlagOverrideSet, override.FlagName, func() (bool, error) {
+			flag, err := s.repo.Find(ctx, override.FlagName)
+			if err != nil || flag == nil {
+				return false, err
+			}
+			found = true
+			return true, s.repo.SaveOverride(ctx, override)
+		})
+	})
+	if err != nil || !found {
+		return false, err
+	}
+	return true, s.Refresh(ctx)
+}
+
+func (s *featureFlagService) DeleteOverride(ctx context.Context, name, subjectType, subject string) (bool, error) {
+	if subjectType == models.FlagSubjectOrganization {
+		subject = strings.ToLower(strings.TrimPrefix(subject, "@"))
+	}
+	name = strings.ToLower(name)
+	var deleted bool
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		return s.change(ctx, auditFlagOverrideDeleted, name, func() (bool, error) {
+			var err error
+			deleted, err = s.repo.DeleteOverride(ctx, name, subjectType, subject)
+			return deleted, err
+		})
+	})
+	if err != nil || !deleted {
+		return false, err
+	}
+	return true, s.Refresh(ctx)
+}
+
+func (s *featureFlagService) Refresh(ctx context.Context) error {
+	flags, err := s.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	states := make(map[string]*flagState, len(flags))
+	for _, flag := range flags {
+		state := &flagState{
+			flag:          flag,
+			users:         make(map[string]bool),
+			organizations: make(map[string]bool),
+		}
+		for _, override := range flag.Overrides {
+			switch override.SubjectType {
+			case models.FlagSubjectUser:
+				state.users[override.Subject] = override.Enabled
+			case models.FlagSubjectOrganization:
+				state.organizations[override.Subject] = override.Enabled
+			}
+		}
+		states[flag.Name] = state
+	}
+	s.flags.Store(&states)
+	return nil
+}
+
+// RegisterFeatureFlagRefresh loads the persisted flags on startup and reloads
+// them every FLAGS_REFRESH_INTERVAL
+func RegisterFeatureFlagRefresh(lc fx.Lifecycle, flags FeatureFlagService, cfg *config.Config, log *zap.Logger) {
+	stopChan := make(chan struct{})
+
+	refresh := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := flags.Refresh(ctx); err != nil {
+			log.Warn("Failed to refresh feature flags", zap.Error(err))
+		}
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			refresh()
+
+			ticker := time.NewTicker(cfg.FeatureFlagsRefreshInterval)
+			go func() {
+				for {
+					select {
+					case <-ticker.C:
+						refresh()
+					case <-stopChan:
+						ticker.Stop()
+						return
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stopChan)
+			return nil
+		},
+	})
+}
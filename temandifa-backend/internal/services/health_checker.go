@@ -0,0 +1,604 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/dto"
+	"temandifa-backend/internal/metrics"
+	"temandifa-backend/internal/repositories"
+)
+
+// Component health statuses
+const (
+	HealthStatusHealthy       = "healthy"
+	HealthStatusUnhealthy     = "unhealthy"
+	HealthStatusNotConfigured = "not_configured"
+	HealthStatusUnknown       = "unknown"
+)
+
+// componentCheckTimeout bounds a single component check
+const componentCheckTimeout = 5 * time.Second
+
+// APIComponent is the pseudo-component whose uptime samples record whether
+// the instance could serve requests, i.e. was ready
+const APIComponent = "api"
+
+// recoveryMinBackoff is the first retry delay after a component turns
+// unhealthy; it doubles up to the check interval
+const recoveryMinBackoff = time.Second
+
+// ComponentCheck performs a single health check for a component
+type ComponentCheck func(ctx context.Context) dto.HealthCheck
+
+// RecoverFunc tries to restore an unhealthy component, e.g. by reconnecting
+type RecoverFunc func(ctx context.Context) error
+
+// componentState holds the cached status and sample history of a component
+type componentState struct {
+	name     string
+	critical bool
+	check    ComponentCheck
+	recover  RecoverFunc
+
+	recovering bool
+
+	current        dto.HealthCheck
+	lastChecked    time.Time
+	lastTransition time.Time
+	history        []dto.HealthSample // ring buffer
+	next           int
+	filled         bool
+}
+
+// HealthChecker periodically checks dependencies in the background and serves
+// cached results, so health endpoints respond instantly without pinging
+// Postgres, Redis, and the AI Service on every request.
+type HealthChecker struct {
+	mu          sync.RWMutex
+	components  []*componentState
+	log         *zap.Logger
+	interval    time.Duration
+	historySize int
+	stopChan    chan struct{}
+	draining    atomic.Bool
+
+	// Uptime samples of the interval checks not stored yet, per UTC day and
+	// component; kept when storing fails, e.g. while Postgres is down
+	uptime        repositories.UptimeRepository
+	uptimeMu      sync.Mutex
+	uptimePending map[uptimeKey]uptimeCount
+	uptimeFailing bool
+}
+
+type uptimeKey struct {
+	day       time.Time
+	component string
+}
+
+type uptimeCount struct {
+	samples int64
+	healthy int64
+}
+
+// NewHealthChecker creates a health checker with the standard components registered
+func NewHealthChecker(db *gorm.DB, rdb *redis.Client, uptime repositories.UptimeRepository, cfg *config.Config, log *zap.Logger) *HealthChecker {
+	hc := &HealthChecker{
+		log:           log,
+		interval:      cfg.HealthCheckInterval,
+		historySize:   cfg.HealthHistorySize,
+		stopChan:      make(chan struct{}),
+		uptime:        uptime,
+		uptimePending: make(map[uptimeKey]uptimeCount),
+	}
+
+	// PostgreSQL is critical - the API cannot serve requests without it
+	hc.Register("postgres", true, postgresCheck(db))
+	hc.SetRecovery("postgres", postgresRecovery(db, cfg.DBMaxIdleConns))
+	// Not ready until the schema has every migration this build ships with,
+	// e.g. while another replica holds the migration lock
+	hc.Register("migrations", true, migrationsCheck(db, database.MigrationsDirFor(cfg.DBDriver), log))
+	// Redis and the AI Service degrade functionality but don't take the API down
+	hc.Register("redis", false, redisCheck(rdb))
+	hc.Register("ai_service", false, aiServiceHTTPCheck(cfg.AIServiceURL))
+
+	return hc
+}
+
+// Register adds a component to be checked. Critical components determine readiness.
+func (hc *HealthChecker) Register(name string, critical bool, check ComponentCheck) {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+
+	hc.components = append(hc.components, &componentState{
+		name:     name,
+		critical: critical,
+		check:    check,
+		current:  dto.HealthCheck{Status: HealthStatusUnknown, Message: "not checked yet"},
+		history:  make([]dto.HealthSample, hc.historySize),
+	})
+}
+
+// SetRecovery attaches a recovery action to a registered component. While the
+// component is unhealthy the checker retries recover and the check with
+// exponential backoff instead of waiting for the next interval, so readiness
+// flips back as soon as the dependency is reachable again.
+func (hc *HealthChecker) SetRecovery(name string, recover RecoverFunc) {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+
+	for _, comp := range hc.components {
+		if comp.name == name {
+			comp.recover = recover
+			return
+		}
+	}
+}
+
+// Start runs an initial check and then re-checks on every interval
+func (hc *HealthChecker) Start() {
+	hc.log.Info("Health checker started",
+		zap.Duration("interval", hc.interval),
+		zap.Int("history_size", hc.historySize),
+	)
+
+	hc.CheckNow(context.Background())
+
+	ticker := time.NewTicker(hc.interval)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				hc.CheckNow(context.Background())
+			case <-hc.stopChan:
+				ticker.Stop()
+				hc.log.Info("Health checker stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the background checks
+func (hc *HealthChecker) Stop() {
+	close(hc.stopChan)
+}
+
+// CheckNow checks all components concurrently, updates the cached state and
+// stores the results as uptime samples. Recovery retries aren't sampled, so
+// every instance samples once per interval however long an outage lasts.
+func (hc *HealthChecker) CheckNow(ctx context.Context) {
+	hc.mu.RLock()
+	components := append([]*componentState(nil), hc.components...)
+	hc.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, comp := range components {
+		wg.Add(1)
+		go func(comp *componentState) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, componentCheckTimeout)
+			result := comp.check(checkCtx)
+			cancel()
+
+			hc.record(comp, result, time.Now())
+		}(comp)
+	}
+	wg.Wait()
+
+	hc.sampleUptime(time.Now())
+	hc.flushUptime(ctx)
+}
+
+// sampleUptime counts the current status of every checked component, and of
+// the API, towards today's uptime. Unconfigured components aren't counted,
+// nor is the API while draining.
+func (hc *HealthChecker) sampleUptime(now time.Time) {
+	day := now.UTC().Truncate(24 * time.Hour)
+
+	statuses := make(map[string]bool, len(hc.components)+1)
+	draining := hc.draining.Load()
+	ready := !draining
+	hc.mu.RLock()
+	for _, comp := range hc.components {
+		healthy := comp.current.Status == HealthStatusHealthy
+		if comp.critical && !healthy {
+			ready = false
+		}
+		if healthy || comp.current.Status == HealthStatusUnhealthy {
+			statuses[comp.name] = healthy
+		}
+	}
+	hc.mu.RUnlock()
+	if !draining {
+		statuses[APIComponent] = ready
+	}
+
+	hc.uptimeMu.Lock()
+	defer hc.uptimeMu.Unlock()
+	for component, healthy := range statuses {
+		key := uptimeKey{day: day, component: component}
+		count := hc.uptimePending[key]
+		count.samples++
+		if healthy {
+			count.healthy++
+		}
+		hc.uptimePending[key] = count
+	}
+}
+
+// flushUptime stores the pending uptime samples, keeping those it couldn't
+// for the next check
+func (hc *HealthChecker) flushUptime(ctx context.Context) {
+	if hc.uptime == nil {
+		return
+	}
+
+	hc.uptimeMu.Lock()
+	defer hc.uptimeMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, componentCheckTimeout)
+	defer cancel()
+
+	for key, count := range hc.uptimePending {
+		if err := hc.uptime.Add(ctx, key.day, key.component, count.samples, count.healthy); err != nil {
+			if !hc.uptimeFailing {
+				hc.log.Warn("Failed to store uptime samples, keeping them for the next check", zap.Error(err))
+				hc.uptimeFailing = true
+			}
+			return
+		}
+		delete(hc.uptimePending, key)
+	}
+	if hc.uptimeFailing {
+		hc.log.Info("Stored pending uptime samples")
+		hc.uptimeFailing = false
+	}
+}
+
+// record stores a check result, tracking transitions and history
+func (hc *HealthChecker) record(comp *componentState, result dto.HealthCheck, now time.Time) {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+
+	if result.Status != comp.current.Status {
+		if !comp.lastChecked.IsZero() {
+			hc.log.Warn("Component health changed",
+				zap.String("component", comp.name),
+				zap.String("from", comp.current.Status),
+				zap.String("to", result.Status),
+				zap.String("message", result.Message),
+			)
+		}
+		comp.lastTransition = now
+	}
+
+	comp.current = result
+	comp.lastChecked = now
+
+	if len(comp.history) > 0 {
+		comp.history[comp.next] = dto.HealthSample{
+			Status:    result.Status,
+			LatencyMs: result.LatencyMs,
+			CheckedAt: now.Format(time.RFC3339),
+		}
+		comp.next = (comp.next + 1) % len(comp.history)
+		if comp.next == 0 {
+			comp.filled = true
+		}
+	}
+
+	healthy := 0.0
+	if result.Status == HealthStatusHealthy {
+		healthy = 1
+	}
+	metrics.ComponentHealth.WithLabelValues(comp.name).Set(healthy)
+
+	if result.Status == HealthStatusUnhealthy && comp.recover != nil && !comp.recovering {
+		comp.recovering = true
+		go hc.recoverComponent(comp)
+	}
+}
+
+// recoverComponent retries recovery with backoff until the component is
+// healthy again or the checker stops
+func (hc *HealthChecker) recoverComponent(comp *componentState) {
+	defer func() {
+		hc.mu.Lock()
+		comp.recovering = false
+		hc.mu.Unlock()
+	}()
+
+	backoff := recoveryMinBackoff
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-time.After(backoff):
+		case <-hc.stopChan:
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), componentCheckTimeout)
+		err := comp.recover(ctx)
+		result := comp.check(ctx)
+		cancel()
+
+		if result.Status == HealthStatusHealthy {
+			metrics.HealthRecoveryAttempts.WithLabelValues(comp.name, "recovered").Inc()
+			hc.log.Info("Component recovered",
+				zap.String("component", comp.name),
+				zap.Int("attempts", attempt),
+			)
+			hc.record(comp, result, time.Now())
+			return
+		}
+
+		metrics.HealthRecoveryAttempts.WithLabelValues(comp.name, "failed").Inc()
+		hc.log.Warn("Component recovery failed",
+			zap.String("component", comp.name),
+			zap.Int("attempt", attempt),
+			zap.Duration("retry_in", min(backoff*2, hc.interval)),
+			zap.NamedError("recover_error", err),
+			zap.String("message", result.Message),
+		)
+		hc.record(comp, result, time.Now())
+		backoff = min(backoff*2, hc.interval)
+	}
+}
+
+// Snapshot returns the cached status of every component.
+// History (oldest first) is included when withHistory is true.
+func (hc *HealthChecker) Snapshot(withHistory bool) map[string]dto.HealthCheck {
+	hc.mu.RLock()
+	defer hc.mu.RUnlock()
+
+	checks := make(map[string]dto.HealthCheck, len(hc.components))
+	for _, comp := range hc.components {
+		check := comp.current
+		check.Critical = comp.critical
+		if !comp.lastChecked.IsZero() {
+			check.LastChecked = comp.lastChecked.Format(time.RFC3339)
+		}
+		if !comp.lastTransition.IsZero() {
+			check.LastTransition = comp.lastTransition.Format(time.RFC3339)
+		}
+		if withHistory {
+			check.History = comp.orderedHistory()
+		}
+		checks[comp.name] = check
+	}
+	return checks
+}
+
+// Ready reports whether all critical components are healthy and the server
+// isn't draining
+func (hc *HealthChecker) Ready() bool {
+	if hc.draining.Load() {
+		return false
+	}
+
+	hc.mu.RLock()
+	defer hc.mu.RUnlock()
+
+	for _, comp := range hc.components {
+		if comp.critical && comp.current.Status != HealthStatusHealthy {
+			return false
+		}
+	}
+	return true
+}
+
+// StartDraining makes Ready report false from now on, so load balancers stop
+// sending traffic before the server shuts down
+func (hc *HealthChecker) StartDraining() {
+	hc.draining.Store(true)
+}
+
+// Draining reports whether shutdown has begun
+func (hc *HealthChecker) Draining() bool {
+	return hc.draining.Load()
+}
+
+// Degraded reports whether any component is unhealthy
+func (hc *HealthChecker) Degraded() bool {
+	hc.mu.RLock()
+	defer hc.mu.RUnlock()
+
+	for _, comp := range hc.components {
+		if comp.current.Status == HealthStatusUnhealthy {
+			return true
+		}
+	}
+	return false
+}
+
+// orderedHistory returns the ring buffer contents from oldest to newest
+func (comp *componentState) orderedHistory() []dto.HealthSample {
+	if !comp.filled {
+		return append([]dto.HealthSample(nil), comp.history[:comp.next]...)
+	}
+	ordered := make([]dto.HealthSample, 0, len(comp.history))
+	ordered = append(ordered, comp.history[comp.next:]...)
+	return append(ordered, comp.history[:comp.next]...)
+}
+
+// RegisterHealthChecker registers the background health checker with fx lifecycle
+func RegisterHealthChecker(lc fx.Lifecycle, hc *HealthChecker) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			hc.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			hc.Stop()
+			return nil
+		},
+	})
+}
+
+// ---- Component checks ----
+
+// postgresCheck pings the database and reports latency
+func postgresCheck(db *gorm.DB) ComponentCheck {
+	return func(ctx context.Context) dto.HealthCheck {
+		if db == nil {
+			return dto.HealthCheck{
+				Status:  HealthStatusUnhealthy,
+				Message: "connection not initialized",
+			}
+		}
+
+		start := time.Now()
+		sqlDB, err := db.DB()
+		if err == nil {
+			err = sqlDB.PingContext(ctx)
+		}
+		if err != nil {
+			return dto.HealthCheck{
+				Status:    HealthStatusUnhealthy,
+				LatencyMs: time.Since(start).Milliseconds(),
+				Message:   err.Error(),
+			}
+		}
+		return dto.HealthCheck{
+			Status:    HealthStatusHealthy,
+			LatencyMs: time.Since(start).Milliseconds(),
+		}
+	}
+}
+
+// migrationsCheck compares the applied schema version with the latest
+// migration on disk. A newer schema is fine: during a rolling deploy old
+// instances keep serving after new ones have migrated.
+func migrationsCheck(db *gorm.DB, dir string, log *zap.Logger) ComponentCheck {
+	expected, err := database.LatestMigration(dir)
+	if err != nil {
+		log.Warn("Failed to read migrations directory", zap.String("dir", dir), zap.Error(err))
+	}
+
+	return func(ctx context.Context) dto.HealthCheck {
+		if db == nil {
+			return dto.HealthCheck{
+				Status:  HealthStatusUnhealthy,
+				Message: "connection not initialized",
+			}
+		}
+
+		start := time.Now()
+		sqlDB, err := db.DB()
+		if err != nil {
+			return dto.HealthCheck{Status: HealthStatusUnhealthy, Message: err.Error()}
+		}
+		version, dirty, err := database.SchemaVersion(ctx, sqlDB)
+		latency := time.Since(start).Milliseconds()
+
+		switch {
+		case err != nil:
+			return dto.HealthCheck{Status: HealthStatusUnhealthy, LatencyMs: latency, Message: err.Error()}
+		case dirty:
+			return dto.HealthCheck{
+				Status:    HealthStatusUnhealthy,
+				LatencyMs: latency,
+				Message:   fmt.Sprintf("schema version %d is dirty", version),
+			}
+		case version < expected:
+			return dto.HealthCheck{
+				Status:    HealthStatusUnhealthy,
+				LatencyMs: latency,
+				Message:   fmt.Sprintf("schema at version %d, expected %d", version, expected),
+			}
+		}
+		return dto.HealthCheck{Status: HealthStatusHealthy, LatencyMs: latency}
+	}
+}
+
+// postgresRecovery drops pooled connections, which may point at a restarted or
+// failed-over server, so the next queries dial fresh ones
+func postgresRecovery(db *gorm.DB, maxIdleConns int) RecoverFunc {
+	return func(ctx context.Context) error {
+		if db == nil {
+			return nil
+		}
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return database.ResetPool(ctx, sqlDB, maxIdleConns)
+	}
+}
+
+// redisCheck pings Redis and reports latency
+func redisCheck(rdb *redis.Client) ComponentCheck {
+	return func(ctx context.Context) dto.HealthCheck {
+		if rdb == nil {
+			return dto.HealthCheck{
+				Status:  HealthStatusNotConfigured,
+				Message: "Redis not connected",
+			}
+		}
+
+		start := time.Now()
+		if _, err := rdb.Ping(ctx).Result(); err != nil {
+			return dto.HealthCheck{
+				Status:    HealthStatusUnhealthy,
+				LatencyMs: time.Since(start).Milliseconds(),
+				Message:   err.Error(),
+			}
+		}
+		return dto.HealthCheck{
+			Status:    HealthStatusHealthy,
+			LatencyMs: time.Since(start).Milliseconds(),
+		}
+	}
+}
+
+// aiServiceHTTPCheck calls the AI Service HTTP health endpoint and reports latency
+func aiServiceHTTPCheck(baseURL string) ComponentCheck {
+	client := &http.Client{Timeout: componentCheckTimeout}
+
+	return func(ctx context.Context) dto.HealthCheck {
+		start := time.Now()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/health", nil)
+		if err != nil {
+			return dto.HealthCheck{
+				Status:  HealthStatusUnhealthy,
+				Message: err.Error(),
+			}
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return dto.HealthCheck{
+				Status:    HealthStatusUnhealthy,
+				LatencyMs: time.Since(start).Milliseconds(),
+				Message:   err.Error(),
+			}
+		}
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+
+		if resp.StatusCode != http.StatusOK {
+			return dto.HealthCheck{
+				Status:    HealthStatusUnhealthy,
+				LatencyMs: time.Since(start).Milliseconds(),
+				Message:   "HTTP " + resp.Status,
+			}
+		}
+		return dto.HealthCheck{
+			Status:    HealthStatusHealthy,
+			LatencyMs: time.Since(start).Milliseconds(),
+		}
+	}
+}
@@ -1,45 +1,75 @@
 package services
 
 import (
+	"context"
+
+	"temandifa-backend/internal/dto"
 	"temandifa-backend/internal/models"
 	"temandifa-backend/internal/repositories"
 )
 
 type HistoryService interface {
-	CreateHistory(history models.History) (models.History, error)
-	GetUserHistory(userID uint, page, limit int) ([]models.History, int64, error)
-	DeleteHistory(userID uint, historyID string) (bool, error)
-	ClearUserHistory(userID uint) (int64, error)
+	CreateHistory(ctx context.Context, history models.History) (models.History, error)
+	GetUserHistory(ctx context.Context, userID uint, page, limit int) ([]models.History, int64, error)
+	// FindUserHistory is GetUserHistory narrowed by filter, newest first
+	FindUserHistory(ctx context.Context, userID uint, filter dto.HistoryFilter, page, limit int) ([]models.History, int64, error)
+	DeleteHistory(ctx context.Context, userID uint, historyID string) (bool, error)
+	ClearUserHistory(ctx context.Context, userID uint) (int64, error)
 }
 
 type historyService struct {
 	historyRepo repositories.HistoryRepository
+	attachments AttachmentService
 }
 
-func NewHistoryService(historyRepo repositories.HistoryRepository) HistoryService {
+func NewHistoryService(historyRepo repositories.HistoryRepository, attachments AttachmentService) HistoryService {
 	return &historyService{
 		historyRepo: historyRepo,
+		attachments: attachments,
 	}
 }
 
-func (s *historyService) CreateHistory(history models.History) (models.History, error) {
-	err := s.historyRepo.Create(&history)
+func (s *historyService) CreateHistory(ctx context.Context, history models.History) (models.History, error) {
+	err := s.historyRepo.Create(ctx, &history)
 	return history, err
 }
 
-func (s *historyService) GetUserHistory(userID uint, page, limit int) ([]models.History, int64, error) {
-	offset := (page - 1) * limit
-	return s.historyRepo.FindUserHistory(userID, limit, offset)
+func (s *historyService) GetUserHistory(ctx context.Context, userID uint, page, limit int) ([]models.History, int64, error) {
+	spec := repositories.NewSpec().
+		Where("user_id = ?", userID).
+		OrderBy("created_at", true).
+		Paginate(limit, (page-1)*limit)
+	return s.historyRepo.List(ctx, spec)
+}
+
+func (s *historyService) FindUserHistory(ctx context.Context, userID uint, filter dto.HistoryFilter, page, limit int) ([]models.History, int64, error) {
+	spec := repositories.NewSpec().
+		Where("user_id = ?", userID).
+		WhereIf(filter.FeatureType != "", "feature_type = ?", filter.FeatureType).
+		WhereIf(filter.CreatedAfter != nil, "created_at >= ?", filter.CreatedAfter).
+		WhereIf(filter.CreatedBefore != nil, "created_at < ?", filter.CreatedBefore).
+		Search(filter.Search, "result_text", "input_source").
+		OrderBy("created_at", true).
+		Paginate(limit, (page-1)*limit)
+	return s.historyRepo.List(ctx, spec)
 }
 
-func (s *historyService) DeleteHistory(userID uint, historyID string) (bool, error) {
-	rowsAffected, err := s.historyRepo.DeleteByID(userID, historyID)
+// DeleteHistory removes the entry's attachments first, so a failure leaves the
+// entry in place to delete again
+func (s *historyService) DeleteHistory(ctx context.Context, userID uint, historyID string) (bool, error) {
+	if err := s.attachments.DeleteForHistory(ctx, userID, historyID); err != nil {
+		return false, err
+	}
+	rowsAffected, err := s.historyRepo.DeleteByID(ctx, userID, historyID)
 	if err != nil {
 		return false, err
 	}
 	return rowsAffected > 0, nil
 }
 
-func (s *historyService) ClearUserHistory(userID uint) (int64, error) {
-	return s.historyRepo.DeleteAllByUserID(userID)
+func (s *historyService) ClearUserHistory(ctx context.Context, userID uint) (int64, error) {
+	if err := s.attachments.DeleteForUser(ctx, userID); err != nil {
+		return 0, err
+	}
+	return s.historyRepo.DeleteAllByUserID(ctx, userID)
 }
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/dto"
+	"temandifa-backend/internal/repositories"
+)
+
+// inboxPurgeInterval is how often notifications older than
+// NOTIFICATION_RETENTION are deleted
+const inboxPurgeInterval = 24 * time.Hour
+
+// InboxService serves the users' in-app notification inbox, which
+// NotificationService fills with everything it pushes: their own
+// notifications (finished data exports, new sign-ins) and announcements.
+// Notifications are kept for NOTIFICATION_RETENTION.
+type InboxService interface {
+	// List returns a page of the user's inbox, newest first, and the number
+	// of notifications matching
+	List(ctx context.Context, userID uint, unreadOnly bool, page, limit int) ([]dto.InboxNotification, int64, error)
+	// UnreadCount returns how many notifications in the user's inbox are unread
+	UnreadCount(ctx context.Context, userID uint) (int64, error)
+	// MarkRead reports false when the user's inbox has no such notification
+	MarkRead(ctx context.Context, userID uint, id uint64) (bool, error)
+	// MarkAllRead returns how many notifications were unread
+	MarkAllRead(ctx context.Context, userID uint) (int64, error)
+	// Purge deletes the notifications older than NOTIFICATION_RETENTION
+	Purge(ctx context.Context) (int64, error)
+}
+
+type inboxService struct {
+	repo      repositories.NotificationRepository
+	retention time.Duration
+}
+
+// NewInboxService creates a new InboxService
+func NewInboxService(repo repositories.NotificationRepository, cfg *config.Config) InboxService {
+	return &inboxService{repo: repo, retention: cfg.NotificationRetention}
+}
+
+func (s *inboxService) List(ctx context.Context, userID uint, unreadOnly bool, page, limit int) ([]dto.InboxNotification, int64, error) {
+	notifications, total, err := s.repo.List(ctx, userID, unreadOnly, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	results := make([]dto.InboxNotification, 0, len(notifications))
+	for _, notification := range notifications {
+		result := dto.InboxNotification{
+			ID:        notification.ID,
+			Type:      notification.Type,
+			Title:     notification.Title,
+			Body:      notification.Body,
+			Data:      map[string]string{},
+			CreatedAt: notification.CreatedAt,
+			ReadAt:    notification.ReadAt,
+		}
+		_ = json.Unmarshal([]byte(notification.Data), &result.Data)
+		results = append(results, result)
+	}
+	return results, total, nil
+}
+
+func (s *inboxService) UnreadCount(ctx context.Context, userID uint) (int64, error) {
+	return s.repo.CountUnread(ctx, userID)
+}
+
+func (s *inboxService) MarkRead(ctx context.Context, userID uint, id uint64) (bool, error) {
+	return s.repo.MarkRead(ctx, userID, id)
+}
+
+func (s *inboxService) MarkAllRead(ctx context.Context, userID uint) (int64, error) {
+	return s.repo.MarkAllRead(ctx, userID)
+}
+
+func (s *inboxService) Purge(ctx context.Context) (int64, error) {
+	return s.repo.DeleteBefore(ctx, time.Now().Add(-s.retention))
+}
+
+// RegisterInboxPurge deletes expired notifications daily
+func RegisterInboxPurge(lc fx.Lifecycle, inbox InboxService, log *zap.Logger) {
+	stopChan := make(chan struct{})
+
+	purge := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		deleted, err := inbox.Purge(ctx)
+		if err != nil {
+			log.Warn("Failed to purge expired notifications", zap.Error(err))
+			return
+		}
+		if deleted > 0 {
+			log.Info("Expired notifications purged", zap.Int64("deleted", deleted))
+		}
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			ticker := time.NewTicker(inboxPurgeInterval)
+			go func() {
+				for {
+					select {
+					case <-ticker.C:
+						purge()
+					case <-stopChan:
+						ticker.Stop()
+						return
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stopChan)
+			return nil
+		},
+	})
+}
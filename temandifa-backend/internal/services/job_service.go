@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/database"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+)
+
+// Jobs that can be triggered manually
+const (
+	JobTokenCleanup = "token_cleanup"
+	JobUserPurge    = "user_purge"
+	JobInboxPurge   = "inbox_purge"
+	JobUsageReports = "usage_reports" // Regenerates last month's
+	JobBackup       = "backup"        // Only when BACKUP_ENABLED
+)
+
+// Audit action and target type of manual job runs
+const (
+	auditTargetJob    = "job"
+	auditJobTriggered = "job.triggered"
+)
+
+// job runs one background job and returns its job-specific result
+type job func(ctx context.Context) (any, error)
+
+// JobService runs the scheduled background jobs on demand. Runs continue
+// in the background after the request returns; their outcome is recorded
+// in job_runs.
+type JobService interface {
+	// Names lists the jobs that can be triggered
+	Names() []string
+	// Run starts the named job and returns its running record, or nil when
+	// there is no such job. A job already running on this instance is a
+	// conflict.
+	Run(ctx context.Context, name string) (*models.JobRun, error)
+	// Find returns nil when the run doesn't exist
+	Find(ctx context.Context, id uint64) (*models.JobRun, error)
+	// Wait blocks until runs in progress finish or ctx is done
+	Wait(ctx context.Context) error
+}
+
+type jobService struct {
+	jobs    map[string]job
+	repo    repositories.JobRunRepository
+	audit   AuditService
+	tx      database.TxManager
+	log     *zap.Logger
+	mu      sync.Mutex
+	running map[string]bool
+	wg      sync.WaitGroup
+}
+
+// NewJobService creates a new JobService
+func NewJobService(
+	tokenService TokenService,
+	purgeService UserPurgeService,
+	backups BackupService,
+	inbox InboxService,
+	reports UsageReportService,
+	repo repositories.JobRunRepository,
+	audit AuditService,
+	tx database.TxManager,
+	cfg *config.Config,
+	log *zap.Logger,
+) JobService {
+	jobs := map[string]job{
+		JobTokenCleanup: func(ctx context.Context) (any, error) {
+			count, err := tokenService.CleanupExpiredTokens()
+			return map[string]int64{"tokens_removed": count}, err
+		},
+		JobUserPurge: func(ctx context.Context) (any, error) {
+			count, err := purgeService.PurgeExpired(ctx)
+			return map[string]int{"users_purged": count}, err
+		},
+		JobInboxPurge: func(ctx context.Context) (any, error) {
+			count, err := inbox.Purge(ctx)
+			return map[string]int64{"notifications_removed": count}, err
+		},
+		JobUsageReports: func(ctx context.Context) (any, error) {
+			month := PreviousMonth()
+			count, err := reports.Generate(ctx, month)
+			return map[string]any{"month": month, "reports": count}, err
+		},
+	}
+	if cfg.BackupEnabled {
+		jobs[JobBackup] = func(ctx context.Context) (any, error) {
+			run, err := backups.Run(ctx)
+			if run == nil {
+				return nil, err
+			}
+			return map[string]any{"backup_run_id": run.ID, "location": run.Location, "size_bytes": run.SizeBytes}, err
+		}
+	}
+
+	return &jobService{
+		jobs:    jobs,
+		repo:    repo,
+		audit:   audit,
+		tx:      tx,
+		log:     log,
+		running: make(map[string]bool),
+	}
+}
+
+func (s *jobService) Names() []string {
+	names := make([]string, 0, len(s.jobs))
+	for name := range s.jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// claim marks name as running, reporting false if it already is
+func (s *jobService) claim(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running[name] {
+		return false
+	}
+	s.running[name] = true
+	return true
+}
+
+func (s *jobService) release(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.running, name)
+}
+
+func (s *jobService) Run(ctx context.Context, name string) (*models.JobRun, error) {
+	fn, ok := s.jobs[name]
+	if !ok {
+		return nil, nil
+	}
+	if !s.claim(name) {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeConflict, "Job is already running", http.StatusConflict)
+	}
+
+	run := &models.JobRun{
+		Name:      name,
+		Status:    models.JobStatusRunning,
+		Result:    "{}",
+		StartedAt: time.Now(),
+	}
+	if actorID := logger.UserIDFromContext(ctx); actorID != 0 {
+		run.TriggeredBy = &actorID
+	}
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, run); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, auditJobTriggered, auditTargetJob, name, map[string]uint64{"run_id": run.ID})
+	})
+	if err != nil {
+		s.release(name)
+		return nil, err
+	}
+
+	started := *run
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer s.release(name)
+		// Detached from the request, but keeps its request ID for the logs
+		s.execute(context.WithoutCancel(ctx), fn, run)
+	}()
+	return &started, nil
+}
+
+// execute runs fn and records its outcome on run
+func (s *jobService) execute(ctx context.Context, fn job, run *models.JobRun) {
+	log := s.log.With(zap.String("job", run.Name), zap.Uint64("run_id", run.ID), zap.String("request_id", logger.RequestIDFromContext(ctx)))
+	log.Info("Manual job started")
+
+	result, err := fn(ctx)
+
+	finished := time.Now()
+	run.FinishedAt = &finished
+	run.Status = models.JobStatusSucceeded
+	if err != nil {
+		run.Status = models.JobStatusFailed
+		run.Error = err.Error()
+	}
+	if result != nil {
+		if encoded, marshalErr := json.Marshal(result); marshalErr == nil {
+			run.Result = string(encoded)
+		}
+	}
+
+	if err != nil {
+		log.Error("Manual job failed", zap.Duration("duration", finished.Sub(run.StartedAt)), zap.Error(err))
+	} else {
+		log.Info("Manual job completed", zap.Duration("duration", finished.Sub(run.StartedAt)), zap.String("result", run.Result))
+	}
+	if err := s.repo.Finish(ctx, run); err != nil {
+		log.Warn("Failed to record job outcome", zap.Error(err))
+	}
+}
+
+func (s *jobService) Find(ctx context.Context, id uint64) (*models.JobRun, error) {
+	return s.repo.Find(ctx, id)
+}
+
+func (s *jobService) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RegisterJobService lets manual job runs in progress finish on shutdown
+func RegisterJobService(lc fx.Lifecycle, jobs JobService, log *zap.Logger) {
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			if err := jobs.Wait(ctx); err != nil {
+				log.Warn("Manual jobs still running at shutdown", zap.Error(err))
+			}
+			return nil
+		},
+	})
+}
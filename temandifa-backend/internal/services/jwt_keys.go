@@ -0,0 +1,95 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/dto"
+)
+
+// minRSAKeyBits is the smallest RSA signing key accepted
+const minRSAKeyBits = 2048
+
+// jwtPrivateKey is an asymmetric signing key and its published public key
+type jwtPrivateKey struct {
+	signer crypto.Signer
+	public dto.JWK
+}
+
+// loadJWTPrivateKeys reads the PEM private keys of algorithm (RS256 or
+// EdDSA), returning them by kid with their public keys in order
+func loadJWTPrivateKeys(algorithm string, keys []config.JWTKey) (map[string]*jwtPrivateKey, []dto.JWK, error) {
+	byID := make(map[string]*jwtPrivateKey, len(keys))
+	public := make([]dto.JWK, 0, len(keys))
+	for _, key := range keys {
+		pem, err := os.ReadFile(key.File)
+		if err != nil {
+			return nil, nil, fmt.Errorf("JWT key %s: %w", key.ID, err)
+		}
+
+		var loaded *jwtPrivateKey
+		switch algorithm {
+		case config.JWTAlgRS256:
+			private, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+			if err != nil {
+				return nil, nil, fmt.Errorf("JWT key %s: %w", key.ID, err)
+			}
+			if private.N.BitLen() < minRSAKeyBits {
+				return nil, nil, fmt.Errorf("JWT key %s: RSA keys must have at least %d bits", key.ID, minRSAKeyBits)
+			}
+			loaded = &jwtPrivateKey{signer: private, public: dto.JWK{
+				KeyType: "RSA",
+				N:       base64.RawURLEncoding.EncodeToString(private.N.Bytes()),
+				E:       base64.RawURLEncoding.EncodeToString(big.NewInt(int64(private.E)).Bytes()),
+			}}
+		case config.JWTAlgEdDSA:
+			parsed, err := jwt.ParseEdPrivateKeyFromPEM(pem)
+			if err != nil {
+				return nil, nil, fmt.Errorf("JWT key %s: %w", key.ID, err)
+			}
+			private, ok := parsed.(ed25519.PrivateKey)
+			if !ok {
+				return nil, nil, fmt.Errorf("JWT key %s: not an Ed25519 key", key.ID)
+			}
+			loaded = &jwtPrivateKey{signer: private, public: dto.JWK{
+				KeyType: "OKP",
+				Curve:   "Ed25519",
+				X:       base64.RawURLEncoding.EncodeToString(private.Public().(ed25519.PublicKey)),
+			}}
+		default:
+			return nil, nil, fmt.Errorf("JWT_ALGORITHM %s has no private keys", algorithm)
+		}
+
+		loaded.public.KeyID = key.ID
+		loaded.public.Use = "sig"
+		loaded.public.Algorithm = algorithm
+		byID[key.ID] = loaded
+		public = append(public, loaded.public)
+	}
+	return byID, public, nil
+}
+
+// verificationKey returns the public key of key for tokens signed with
+// method, failing when the key is of another kind
+func (key *jwtPrivateKey) verificationKey(method jwt.SigningMethod) (crypto.PublicKey, error) {
+	public := key.signer.Public()
+	switch method.(type) {
+	case *jwt.SigningMethodRSA:
+		if rsaKey, ok := public.(*rsa.PublicKey); ok {
+			return rsaKey, nil
+		}
+	case *jwt.SigningMethodEd25519:
+		if edKey, ok := public.(ed25519.PublicKey); ok {
+			return edKey, nil
+		}
+	}
+	return nil, fmt.Errorf("key %s doesn't sign %s tokens", key.public.KeyID, method.Alg())
+}
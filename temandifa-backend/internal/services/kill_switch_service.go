@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+)
+
+// Audit actions and target type of kill switch changes
+const (
+	auditTargetKillSwitch = "kill_switch"
+	auditKillSwitchOn     = "kill_switch.disabled"
+	auditKillSwitchOff    = "kill_switch.enabled"
+)
+
+// KillSwitchService switches individual AI operations off, e.g. during an
+// AI provider outage, so requests fail fast instead of waiting on the
+// circuit breaker. Other instances pick up changes within
+// FLAGS_REFRESH_INTERVAL.
+type KillSwitchService interface {
+	// Disabled returns the switch that disables operation, or nil
+	Disabled(operation string) *models.KillSwitch
+	// List returns every AI operation's status, in config.AIOperations order
+	List() []dto.KillSwitchStatus
+	// Disable switches operation off, or updates the reason if it already is
+	Disable(ctx context.Context, operation, reason string) (*models.KillSwitch, error)
+	// Enable switches operation back on, reporting false if it wasn't off
+	Enable(ctx context.Context, operation string) (bool, error)
+	Refresh(ctx context.Context) error
+}
+
+type killSwitchService struct {
+	repo     repositories.KillSwitchRepository
+	audit    AuditService
+	tx       database.TxManager
+	switches atomic.Pointer[map[string]models.KillSwitch]
+}
+
+// NewKillSwitchService creates a new KillSwitchService; switches are loaded
+// by RegisterKillSwitchRefresh
+func NewKillSwitchService(repo repositories.KillSwitchRepository, audit AuditService, tx database.TxManager) KillSwitchService {
+	s := &killSwitchService{
+		repo:  repo,
+		audit: audit,
+		tx:    tx,
+	}
+	s.switches.Store(&map[string]models.KillSwitch{})
+	return s
+}
+
+func (s *killSwitchService) Disabled(operation string) *models.KillSwitch {
+	sw, ok := (*s.switches.Load())[operation]
+	if !ok {
+		return nil
+	}
+	return &sw
+}
+
+func (s *killSwitchService) List() []dto.KillSwitchStatus {
+	switches := *s.switches.Load()
+	result := make([]dto.KillSwitchStatus, 0, len(config.AIOperations))
+	for _, operation := range config.AIOperations {
+		status := dto.KillSwitchStatus{Operation: operation}
+		if sw, ok := switches[operation]; ok {
+			status.Disabled = true
+			status.Reason = sw.Reason
+			status.DisabledBy = sw.DisabledBy
+			status.DisabledAt = &sw.CreatedAt
+		}
+		result = append(result, status)
+	}
+	return result
+}
+
+// validateOperation rejects anything but a known AI operation
+func validateOperation(operation string) error {
+	if !slices.Contains(config.AIOperations, operation) {
+		return apperrors.ValidationWithDetails("Unknown operation", map[string]string{"operation": "must be one of detect, ocr, transcribe, ask, simplify"})
+	}
+	return nil
+}
+
+func (s *killSwitchService) Disable(ctx context.Context, operation, reason string) (*models.KillSwitch, error) {
+	if err := validateOperation(operation); err != nil {
+		return nil, err
+	}
+
+	sw := models.KillSwitch{Operation: operation, Reason: reason}
+	if actorID := logger.UserIDFromContext(ctx); actorID != 0 {
+		sw.DisabledBy = &actorID
+	}
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.repo.Find(ctx, operation)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.Save(ctx, &sw); err != nil {
+			return err
+		}
+		after, err := s.repo.Find(ctx, operation)
+		if err != nil {
+			return err
+		}
+		return s.audit.RecordChange(ctx, auditKillSwitchOn, auditTargetKillSwitch, operation, before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return s.Disabled(operation), nil
+}
+
+func (s *killSwitchService) Enable(ctx context.Context, operation string) (bool, error) {
+	if err := validateOperation(operation); err != nil {
+		return false, err
+	}
+
+	var deleted bool
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.repo.Find(ctx, operation)
+		if err != nil {
+			return err
+		}
+		if deleted, err = s.repo.Delete(ctx, operation); err != nil || !deleted {
+			return err
+		}
+		return s.audit.RecordChange(ctx, auditKillSwitchOff, auditTargetKillSwitch, operation, before, nil)
+	})
+	if err != nil || !deleted {
+		return false, err
+	}
+	return true, s.Refresh(ctx)
+}
+
+func (s *killSwitchService) Refresh(ctx context.Context) error {
+	switches, err := s.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	byOperation := make(map[string]models.KillSwitch, len(switches))
+	for _, sw := range switches {
+		byOperation[sw.Operation] = sw
+	}
+	s.switches.Store(&byOperation)
+	return nil
+}
+
+// RegisterKillSwitchRefresh loads the kill switches on startup and reloads
+// them with the feature flags, every FLAGS_REFRESH_INTERVAL
+func RegisterKillSwitchRefresh(lc fx.Lifecycle, switches KillSwitchService, cfg *config.Config, log *zap.Logger) {
+	stopChan := make(chan struct{})
+
+	refresh := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := switches.Refresh(ctx); err != nil {
+			log.Warn("Failed to refresh kill switches", zap.Error(err))
+		}
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			refresh()
+
+			ticker := time.NewTicker(cfg.FeatureFlagsRefreshInterval)
+			go func() {
+				for {
+					select {
+					case <-ticker.C:
+						refresh()
+					case <-stopChan:
+						ticker.Stop()
+						return
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stopChan)
+			return nil
+		},
+	})
+}
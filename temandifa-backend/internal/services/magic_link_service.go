@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+)
+
+// NotificationMagicLink is the type of sign-in link messages
+const NotificationMagicLink = "account.magic_link"
+
+var errMagicLinkInvalid = apperrors.NewAppError(apperrors.ErrCodeTokenInvalid, "The sign-in link is invalid or has expired", http.StatusBadRequest)
+
+// MagicLinkService signs users in without a password, through short-lived,
+// single-use links emailed to the account's address
+type MagicLinkService interface {
+	// Request emails a sign-in link when an account has the address. It
+	// answers the same whether or not one does, except when the address
+	// asked too often.
+	Request(ctx context.Context, email string) error
+	// SignIn uses the link's token up and signs its account in; deviceID
+	// binds the refresh token like Login
+	SignIn(ctx context.Context, token, userAgent, ipAddress, deviceID string) (*dto.LoginResponse, error)
+}
+
+type magicLinkService struct {
+	repo          repositories.VerificationTokenRepository
+	users         repositories.UserRepository
+	tokens        TokenService
+	notifications NotificationService
+	txManager     database.TxManager
+	userCache     UserCacheService
+	rdb           *redis.Client
+	cfg           *config.AuthConfig
+	linkURL       string
+}
+
+// NewMagicLinkService creates a new MagicLinkService
+func NewMagicLinkService(repo repositories.VerificationTokenRepository, users repositories.UserRepository, tokens TokenService, notifications NotificationService, txManager database.TxManager, userCache UserCacheService, rdb *redis.Client, cfg *config.Config) MagicLinkService {
+	linkURL := cfg.MagicLinkURL
+	if linkURL == "" {
+		linkURL = cfg.PublicBaseURL + "/api/v1/auth/magic-link/verify?token="
+	}
+	return &magicLinkService{
+		repo:          repo,
+		users:         users,
+		tokens:        tokens,
+		notifications: notifications,
+		txManager:     txManager,
+		userCache:     userCache,
+		rdb:           rdb,
+		cfg:           &cfg.AuthConfig,
+		linkURL:       linkURL,
+	}
+}
+
+// allow counts a sign-in link request of email, reporting whether it is
+// within MAGIC_LINK_LIMIT. Addresses of no account count too, so the limit
+// doesn't tell which have one.
+func (s *magicLinkService) allow(ctx context.Context, email string) bool {
+	if s.rdb == nil {
+		return true
+	}
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	key := "magic_link:" + hex.EncodeToString(sum[:])
+	pipe := s.rdb.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.ExpireNX(ctx, key, s.cfg.MagicLinkWindow)
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Ctx(ctx).Warn("Failed to count sign-in link request", zap.Error(err))
+		return true
+	}
+	return incr.Val() <= int64(s.cfg.MagicLinkLimit)
+}
+
+func (s *magicLinkService) Request(ctx context.Context, email string) error {
+	if !s.allow(ctx, email) {
+		return apperrors.NewAppError(apperrors.ErrCodeRateLimited, "Too many sign-in link requests, please try again later", http.StatusTooManyRequests)
+	}
+
+	user, err := s.users.FindByEmail(ctx, email)
+	if err != nil {
+		return apperrors.Database(err)
+	}
+	// Soft-deleted accounts can't sign in while they wait to be purged
+	if user == nil || user.DeletedAt != nil {
+		return nil
+	}
+
+	// Without Redis the stored tokens bound the emails an account receives
+	if s.rdb == nil {
+		sent, err := s.repo.CountSince(ctx, user.ID, models.TokenPurposeMagicLink, time.Now().Add(-s.cfg.MagicLinkWindow))
+		if err != nil {
+			return apperrors.Database(err)
+		}
+		if sent >= int64(s.cfg.MagicLinkLimit) {
+			logger.Ctx(ctx).Info("Sign-in link not sent: limit reached", zap.Uint("user_id", user.ID))
+			return nil
+		}
+	}
+
+	token, err := issueVerificationToken(ctx, s.repo, user, models.TokenPurposeMagicLink, s.cfg.MagicLinkTTL)
+	if err != nil {
+		return apperrors.Database(err)
+	}
+
+	// Sent in the background, so the response time doesn't tell either
+	go s.send(context.WithoutCancel(ctx), user, token)
+	return nil
+}
+
+func (s *magicLinkService) send(ctx context.Context, user *models.User, token string) {
+	channels := s.notifications.MessageRecipient(ctx, Recipient{Email: user.Email}, Notification{
+		Type:  NotificationMagicLink,
+		Title: "Sign in to TemanDifa",
+		Body: fmt.Sprintf("Hi %s,\n\nTo sign in to TemanDifa without your password, open this link within %s:\n%s\n\n"+
+			"The link works once. If you didn't ask to sign in, you can ignore this email.",
+			user.FullName, linkValidity(s.cfg.MagicLinkTTL), s.linkURL+token),
+	})
+	if len(channels) == 0 {
+		logger.Ctx(ctx).Warn("Sign-in link email not sent", zap.Uint("user_id", user.ID))
+	}
+}
+
+func (s *magicLinkService) SignIn(ctx context.Context, token, userAgent, ipAddress, deviceID string) (*dto.LoginResponse, error) {
+	var user *models.User
+	verified := false
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		now := time.Now()
+		stored, err := useVerificationToken(ctx, s.repo, token, models.TokenPurposeMagicLink, now)
+		if err != nil {
+			return apperrors.Database(err)
+		}
+		if stored == nil {
+			return errMagicLinkInvalid
+		}
+
+		// The token only proves access to the address it was sent to
+		user, err = s.users.FindByID(ctx, stored.UserID)
+		if err != nil {
+			return apperrors.Database(err)
+		}
+		if user == nil || user.DeletedAt != nil || user.Email != stored.Email {
+			return errMagicLinkInvalid
+		}
+
+		// Which also verifies the address, and voids the other sign-in links
+		if verified, err = s.users.MarkEmailVerified(ctx, user.ID, user.Email, now); err != nil {
+			return apperrors.Database(err)
+		}
+		if verified {
+			user.EmailVerifiedAt = &now
+		}
+		if _, err := s.repo.MarkAllUsed(ctx, user.ID, models.TokenPurposeMagicLink, now); err != nil {
+			return apperrors.Database(err)
+		}
+		return nil
+	})
+	if err != nil {
+		if _, ok := apperrors.AsAppError(err); ok {
+			return nil, err
+		}
+		return nil, apperrors.Database(err)
+	}
+
+	if verified {
+		if err := s.userCache.InvalidateUserCache(ctx, user.ID); err != nil {
+			logger.Ctx(ctx).Debug("Failed to invalidate user cache", zap.Uint("user_id", user.ID), zap.Error(err))
+		}
+	}
+
+	tokenPair, err := s.tokens.GenerateTokenPair(ctx, user, userAgent, ipAddress, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Devices already signed in learn about the new session
+	s.notifications.NotifyUser(ctx, user.ID, Notification{
+		Type:  NotificationNewSignIn,
+		Title: "New sign-in to your account",
+		Body:  "If this wasn't you, contact support right away.",
+		Data:  map[string]string{"user_agent": userAgent, "ip_address": ipAddress, "method": "magic_link"},
+	})
+
+	return &dto.LoginResponse{
+		TokenResponse: dto.TokenResponse{
+			AccessToken:  tokenPair.AccessToken,
+			RefreshToken: tokenPair.RefreshToken,
+			ExpiresAt:    tokenPair.ExpiresAt,
+			TokenType:    tokenPair.TokenType,
+		},
+		User: dto.UserInfo{
+			ID:            user.ID,
+			FullName:      user.FullName,
+			Email:         user.Email,
+			EmailVerified: user.EmailVerifiedAt != nil,
+		},
+	}, nil
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/storage"
+)
+
+// mediaKeyLabel derives the media URL key from JWT_SECRET (or the oldest
+// JWT_SIGNING_KEYS key) when MEDIA_URL_SECRET is empty, so the two keys differ
+const mediaKeyLabel = "temandifa media urls"
+
+// MediaService hands out short-lived signed URLs for stored media. With a
+// store that presigns (S3) the URLs point at the bucket; otherwise they point
+// at the API (/api/v1/media/...) and carry an HMAC-SHA256 signature. Deleting
+// a history entry revokes its URLs: the attachments' objects and rows are
+// deleted, so neither kind of URL finds them any more.
+type MediaService interface {
+	// AttachmentURL signs a URL that downloads attachment for MEDIA_URL_TTL
+	AttachmentURL(ctx context.Context, attachment *models.HistoryAttachment) (dto.SignedURL, error)
+	// OpenAttachment checks an API-served URL's expiry and signature and
+	// returns the attachment and its content; the caller closes it. The
+	// attachment is nil when it (or its history entry) was deleted.
+	OpenAttachment(ctx context.Context, attachmentID uint64, expires, signature string) (*models.HistoryAttachment, io.ReadCloser, error)
+	// ThumbnailURL signs a URL that downloads the thumbnail of an image
+	// attachment; nil when it has none
+	ThumbnailURL(ctx context.Context, attachment *models.HistoryAttachment) (*dto.SignedURL, error)
+	// OpenThumbnail is OpenAttachment for thumbnail URLs; the content is a JPEG
+	OpenThumbnail(ctx context.Context, attachmentID uint64, expires, signature string) (io.ReadCloser, error)
+}
+
+type mediaService struct {
+	attachments AttachmentService
+	store       storage.ObjectStore
+	key         []byte
+	ttl         time.Duration
+	baseURL     string
+}
+
+// NewMediaService creates a new MediaService
+func NewMediaService(attachments AttachmentService, store storage.ObjectStore, cfg *config.Config) MediaService {
+	key := []byte(cfg.MediaURLSecret)
+	if len(key) == 0 {
+		// Config validation requires MEDIA_URL_SECRET when there may be no
+		// HMAC secret to derive it from
+		secret := cfg.JWTSecret
+		if secret == "" && len(cfg.JWTSigningKeys) > 0 {
+			secret = cfg.JWTSigningKeys[0].Secret
+		}
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(mediaKeyLabel))
+		key = mac.Sum(nil)
+	}
+	return &mediaService{
+		attachments: attachments,
+		store:       store,
+		key:         key,
+		ttl:         cfg.MediaURLTTL,
+		baseURL:     cfg.MediaURLBaseURL,
+	}
+}
+
+var errMediaLink = apperrors.NewAppError(apperrors.ErrCodeForbidden, "The link is invalid or has expired", http.StatusForbidden)
+
+func (s *mediaService) AttachmentURL(ctx context.Context, attachment *models.HistoryAttachment) (dto.SignedURL, error) {
+	return s.signedURL(ctx, "attachment", attachment.ID, attachment.ObjectKey,
+		fmt.Sprintf("/api/v1/media/attachments/%d", attachment.ID))
+}
+
+func (s *mediaService) ThumbnailURL(ctx context.Context, attachment *models.HistoryAttachment) (*dto.SignedURL, error) {
+	if attachment.ThumbnailKey == "" {
+		return nil, nil
+	}
+	signed, err := s.signedURL(ctx, "thumbnail", attachment.ID, attachment.ThumbnailKey,
+		fmt.Sprintf("/api/v1/media/attachments/%d/thumbnail", attachment.ID))
+	if err != nil {
+		return nil, err
+	}
+	return &signed, nil
+}
+
+// signedURL presigns the object at key, or signs the API path that serves
+// the kind of media with id
+func (s *mediaService) signedURL(ctx context.Context, kind string, id uint64, key, path string) (dto.SignedURL, error) {
+	expiresAt := time.Now().Add(s.ttl).Truncate(time.Second)
+	if presigner, ok := s.store.(storage.Presigner); ok {
+		signed, err := presigner.PresignGet(ctx, key, s.ttl)
+		if err != nil {
+			return dto.SignedURL{}, err
+		}
+		return dto.SignedURL{URL: signed, ExpiresAt: expiresAt}, nil
+	}
+
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	query := url.Values{
+		"expires":   {expires},
+		"signature": {s.sign(kind, id, expires)},
+	}
+	return dto.SignedURL{URL: s.baseURL + path + "?" + query.Encode(), ExpiresAt: expiresAt}, nil
+}
+
+// signature authenticates a URL to the kind of media with id, valid until
+// the Unix time expires
+func (s *mediaService) signature(kind string, id uint64, expires string) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	fmt.Fprintf(mac, "%s:%d:%s", kind, id, expires)
+	return mac.Sum(nil)
+}
+
+func (s *mediaService) sign(kind string, id uint64, expires string) string {
+	return base64.RawURLEncoding.EncodeToString(s.signature(kind, id, expires))
+}
+
+// verify checks a signature made by sign and that it hasn't expired
+func (s *mediaService) verify(kind string, id uint64, expires, signature string) bool {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return false
+	}
+	given, err := base64.RawURLEncoding.DecodeString(signature)
+	return err == nil && hmac.Equal(given, s.signature(kind, id, expires))
+}
+
+func (s *mediaService) OpenAttachment(ctx context.Context, attachmentID uint64, expires, signature string) (*models.HistoryAttachment, io.ReadCloser, error) {
+	return s.open(ctx, "attachment", attachmentID, expires, signature, func(attachment *models.HistoryAttachment) string {
+		return attachment.ObjectKey
+	})
+}
+
+func (s *mediaService) OpenThumbnail(ctx context.Context, attachmentID uint64, expires, signature string) (io.ReadCloser, error) {
+	_, content, err := s.open(ctx, "thumbnail", attachmentID, expires, signature, func(attachment *models.HistoryAttachment) string {
+		return attachment.ThumbnailKey
+	})
+	return content, err
+}
+
+// open verifies a URL signed for the kind of media and opens the object that
+// key picks from the attachment. The content is nil when there is none.
+func (s *mediaService) open(ctx context.Context, kind string, attachmentID uint64, expires, signature string, key func(*models.HistoryAttachment) string) (*models.HistoryAttachment, io.ReadCloser, error) {
+	if !s.verify(kind, attachmentID, expires, signature) {
+		return nil, nil, errMediaLink
+	}
+	if s.store == nil {
+		return nil, nil, nil
+	}
+	attachment, err := s.attachments.Get(ctx, attachmentID)
+	if err != nil || attachment == nil || key(attachment) == "" {
+		return nil, nil, err
+	}
+	content, err := s.store.Get(ctx, key(attachment))
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, content, nil
+}
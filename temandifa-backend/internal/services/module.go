@@ -3,7 +3,7 @@ package services
 import "go.uber.org/fx"
 
 var Module = fx.Options(
-	// Provide services
+	// Provide services (constructors return their interfaces directly)
 	fx.Provide(
 		NewAuthService,
 		NewAIService,
@@ -12,12 +12,53 @@ var Module = fx.Options(
 		NewCacheService,
 		NewUserCacheService,
 		NewTokenBlacklist,
+		NewHealthChecker,
+		NewAlertNotifier,
+		NewRequestCaptureService,
+		NewEventService,
+		NewOutboxService,
+		NewOutboxDispatcher,
+		NewUserPurgeService,
+		NewUserAdminService,
+		NewBackupService,
+		NewFeatureFlagService,
+		NewRateLimitOverrideService,
+		NewSessionAdminService,
+		NewUsageService,
+		NewAnnouncementService,
+		NewSystemService,
+		NewAuditService,
+		NewRoleService,
+		NewKillSwitchService,
+		NewJobService,
+		NewDataExportService,
+		NewPlanService,
+		NewNotificationService,
+		NewNarrationService,
+		NewUploadService,
+		NewAttachmentService,
+		NewMediaService,
+		NewAppVersionService,
+		NewDeviceService,
+		NewInboxService,
+		NewSettingsSyncService,
+		NewPreferencesService,
+		NewEmergencyService,
+		NewTranslationService,
+		NewRateLimitStatusService,
+		NewWebhookService,
+		NewStatusService,
+		NewUsageReportService,
+		NewEmailVerificationService,
+		NewPasswordResetService,
+		NewOAuthService,
+		NewAccountService,
+		NewUserService,
+		NewPermissionService,
+		NewSessionService,
+		NewLoginLockoutService,
+		NewMagicLinkService,
+		NewAPIKeyService,
+		NewEmailChangeService,
 	),
-	// Bind interfaces
-	fx.Provide(func(s *authService) AuthService { return s }),
-	fx.Provide(func(s *aiService) AIService { return s }),
-	fx.Provide(func(s *historyService) HistoryService { return s }),
-	// TokenService is already provided as interface by NewTokenService
-	fx.Provide(func(s *redisCacheService) CacheService { return s }),
-	fx.Provide(func(s *userCacheService) UserCacheService { return s }),
 )
@@ -0,0 +1,507 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/metrics"
+	"temandifa-backend/internal/models"
+)
+
+// hazardLabels are detected objects narrated with high priority
+var hazardLabels = map[string]bool{
+	"car": true, "bus": true, "truck": true, "motorcycle": true, "bicycle": true,
+	"train": true, "traffic light": true, "stop sign": true, "fire hydrant": true,
+}
+
+// Errors NarrationStream.Receive returns for a message it rejects; the
+// session answers with an error event and continues
+var (
+	// ErrNarrationFrameTooLarge is a frame over the transport's size limit
+	ErrNarrationFrameTooLarge = errors.New("narration frame too large")
+	// ErrNarrationInvalidMessage is a message that is neither a frame nor config
+	ErrNarrationInvalidMessage = errors.New("invalid narration message")
+)
+
+// NarrationInput is a message from a narration client: settings for the
+// following frames, a camera frame, or both
+type NarrationInput struct {
+	Config *dto.NarrationConfig
+	Frame  []byte
+}
+
+// NarrationStream is a narration client's connection, over WebSocket or gRPC
+type NarrationStream interface {
+	// Receive blocks until the next input and returns io.EOF once the
+	// client is done. It must return when the caller closes the connection.
+	Receive() (NarrationInput, error)
+	// Send writes one event; calls are never concurrent
+	Send(event dto.NarrationEvent) error
+}
+
+// NarrationService turns a stream of camera frames into narration: objects
+// and text that came into view since the previous frame
+type NarrationService interface {
+	// Serve narrates the stream's frames until the client leaves or a limit
+	// ends the session, which is reported in a final error event. The
+	// caller closes the connection afterwards.
+	Serve(ctx context.Context, user models.User, stream NarrationStream)
+	// Close ends open sessions with SERVICE_UNAVAILABLE
+	Close()
+}
+
+type narrationService struct {
+	ai           AIService
+	plans        PlanService
+	killSwitches KillSwitchService
+	usage        UsageService
+	preferences  PreferencesService
+	cfg          *config.AIConfig
+	uploads      *config.UploadConfig
+	log          *zap.Logger
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// NewNarrationService creates a new NarrationService
+func NewNarrationService(ai AIService, plans PlanService, killSwitches KillSwitchService, usage UsageService, preferences PreferencesService, cfg *config.AIConfig, uploads *config.UploadConfig, log *zap.Logger) NarrationService {
+	return &narrationService{
+		ai:           ai,
+		plans:        plans,
+		killSwitches: killSwitches,
+		usage:        usage,
+		preferences:  preferences,
+		cfg:          cfg,
+		uploads:      uploads,
+		log:          log,
+		closed:       make(chan struct{}),
+	}
+}
+
+func (s *narrationService) Close() {
+	s.closeOnce.Do(func() { close(s.closed) })
+}
+
+// Serve runs one session: this goroutine accepts or drops frames, one
+// goroutine receives, one narrates the accepted frame and one sends events,
+// high priority first
+func (s *narrationService) Serve(parent context.Context, user models.User, stream NarrationStream) {
+	log := logger.Ctx(parent)
+	limit := s.uploads.For(config.UploadOpDetect)
+
+	metrics.NarrationSessions.Inc()
+	defer metrics.NarrationSessions.Dec()
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(parent), s.cfg.NarrateMaxDuration)
+	defer cancel()
+
+	var (
+		fatalMu sync.Mutex
+		fatal   *dto.NarrationEvent
+	)
+	fail := func(event dto.NarrationEvent) {
+		fatalMu.Lock()
+		if fatal == nil {
+			fatal = &event
+		}
+		fatalMu.Unlock()
+		cancel()
+	}
+
+	high := make(chan dto.NarrationEvent, 16)
+	low := make(chan dto.NarrationEvent, 32)
+	senderDone := make(chan struct{})
+	go func() {
+		defer close(senderDone)
+		for {
+			var event dto.NarrationEvent
+			select {
+			case event = <-high:
+			default:
+				select {
+				case event = <-high:
+				case event = <-low:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err := stream.Send(event); err != nil {
+				cancel()
+				return
+			}
+		}
+	}()
+	// emit queues an event; when the client can't keep up, stale events are
+	// dropped rather than delaying newer ones
+	emit := func(event dto.NarrationEvent) {
+		queue := low
+		if event.Priority == dto.NarrationPriorityHigh || event.Type == dto.NarrationEventError {
+			queue = high
+		}
+		select {
+		case queue <- event:
+		default:
+		}
+	}
+
+	// Receive in the background so idle and session limits apply while the
+	// client is silent
+	type received struct {
+		input NarrationInput
+		err   error
+	}
+	inputs := make(chan received)
+	go func() {
+		for {
+			input, err := stream.Receive()
+			select {
+			case inputs <- received{input, err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil && !rejectedNarrationInput(err) {
+				return
+			}
+		}
+	}()
+
+	minInterval := s.cfg.NarrateMinFrameInterval
+	emit(dto.NarrationEvent{
+		Type:               dto.NarrationEventReady,
+		MinFrameIntervalMs: minInterval.Milliseconds(),
+		MaxFrames:          s.cfg.NarrateMaxFrames,
+		MaxDurationSeconds: int64(s.cfg.NarrateMaxDuration.Seconds()),
+		MaxFrameBytes:      limit.MaxSize,
+	})
+	log.Info("Narration session started")
+
+	session := &narrationSession{service: s, user: user, lastLabels: map[string]bool{}}
+	if preferences, err := s.preferences.Get(ctx, user.ID); err != nil {
+		log.Warn("Failed to load preferences, narrating with the defaults", zap.Error(err))
+	} else {
+		session.language = preferences.OCRLanguage
+		session.brief = preferences.Verbosity == models.VerbosityBrief
+	}
+	var (
+		processing   sync.WaitGroup
+		busy         atomic.Bool
+		seq          int
+		lastAccepted time.Time
+		lastNotice   time.Time
+		lang         string
+		readText     = true
+		shutdown     bool
+	)
+	idle := time.NewTimer(s.cfg.NarrateIdleTimeout)
+	defer idle.Stop()
+
+loop:
+	for {
+		var in received
+		select {
+		case in = <-inputs:
+		case <-idle.C:
+			fail(dto.NarrationEvent{Type: dto.NarrationEventError, Code: string(apperrors.ErrCodeTimeout), Message: "No frames received"})
+			break loop
+		case <-s.closed:
+			shutdown = true
+			break loop
+		case <-ctx.Done():
+			break loop
+		}
+		idle.Reset(s.cfg.NarrateIdleTimeout)
+
+		switch {
+		case errors.Is(in.err, ErrNarrationFrameTooLarge):
+			metrics.NarrationFrames.WithLabelValues("rejected").Inc()
+			emit(dto.NarrationEvent{Type: dto.NarrationEventError, Code: string(apperrors.ErrCodeFileTooLarge), Message: "Frame exceeds max_frame_bytes"})
+			continue
+		case errors.Is(in.err, ErrNarrationInvalidMessage):
+			emit(dto.NarrationEvent{Type: dto.NarrationEventError, Code: string(apperrors.ErrCodeInvalidInput), Message: `Text messages must be {"type":"config",...}`})
+			continue
+		}
+		if in.err != nil {
+			if !errors.Is(in.err, io.EOF) {
+				log.Debug("Narration stream receive failed", zap.Error(in.err))
+			}
+			break
+		}
+
+		if cfg := in.input.Config; cfg != nil {
+			lang = cfg.Language
+			if cfg.Text != nil {
+				readText = *cfg.Text
+			}
+		}
+		frame := in.input.Frame
+		if frame == nil {
+			continue
+		}
+
+		if int64(len(frame)) > limit.MaxSize {
+			metrics.NarrationFrames.WithLabelValues("rejected").Inc()
+			emit(dto.NarrationEvent{Type: dto.NarrationEventError, Code: string(apperrors.ErrCodeFileTooLarge), Message: "Frame exceeds max_frame_bytes"})
+			continue
+		}
+		if mimeType := http.DetectContentType(frame); !limit.AllowedTypes[mimeType] {
+			metrics.NarrationFrames.WithLabelValues("rejected").Inc()
+			emit(dto.NarrationEvent{Type: dto.NarrationEventError, Code: string(apperrors.ErrCodeInvalidFileType), Message: mimeType + " frames are not allowed"})
+			continue
+		}
+
+		now := time.Now()
+		if wait := minInterval - now.Sub(lastAccepted); busy.Load() || wait > 0 {
+			metrics.NarrationFrames.WithLabelValues("throttled").Inc()
+			if now.Sub(lastNotice) >= minInterval {
+				lastNotice = now
+				emit(dto.NarrationEvent{Type: dto.NarrationEventThrottled, RetryAfterMs: max(wait, minInterval).Milliseconds()})
+			}
+			continue
+		}
+
+		if seq >= s.cfg.NarrateMaxFrames {
+			fail(dto.NarrationEvent{Type: dto.NarrationEventError, Code: string(apperrors.ErrCodeQuotaExceeded), Message: "Frame limit of this connection reached"})
+			break
+		}
+		seq++
+		lastAccepted = now
+		busy.Store(true)
+		metrics.NarrationFrames.WithLabelValues("narrated").Inc()
+
+		processing.Add(1)
+		go func(frame []byte, seq int, lang string, readText bool) {
+			defer processing.Done()
+			defer busy.Store(false)
+			if err := session.process(ctx, frame, seq, lang, readText, emit); err != nil {
+				event := dto.NarrationEvent{Type: dto.NarrationEventError, Frame: seq, Code: string(apperrors.ErrCodeInternal), Message: "Narration failed"}
+				if appErr, ok := apperrors.AsAppError(err); ok {
+					event.Code, event.Message = string(appErr.Code), appErr.Message
+				} else {
+					log.Error("Narration failed", zap.Error(err))
+				}
+				fail(event)
+			}
+		}(frame, seq, lang, readText)
+	}
+
+	// Stop narrating and sending, then report why the session ended
+	timedOut := errors.Is(ctx.Err(), context.DeadlineExceeded)
+	cancel()
+	processing.Wait()
+	<-senderDone
+
+	switch {
+	case shutdown:
+		_ = stream.Send(dto.NarrationEvent{Type: dto.NarrationEventError, Code: string(apperrors.ErrCodeServiceUnavailable), Message: "Server is restarting, reconnect"})
+	case fatal != nil:
+		_ = stream.Send(*fatal)
+	case timedOut:
+		_ = stream.Send(dto.NarrationEvent{Type: dto.NarrationEventError, Code: string(apperrors.ErrCodeTimeout), Message: "Time limit of this connection reached"})
+	}
+	log.Info("Narration session ended", zap.Int("frames", seq))
+}
+
+// rejectedNarrationInput reports whether a receive error only rejects one message
+func rejectedNarrationInput(err error) bool {
+	return errors.Is(err, ErrNarrationFrameTooLarge) || errors.Is(err, ErrNarrationInvalidMessage)
+}
+
+// narrationSession narrates one connection's frames. Frames are processed
+// one at a time.
+type narrationSession struct {
+	service *narrationService
+	user    models.User
+
+	// From the user's preferences: the OCR language of frames sent without
+	// one, and whether to leave out objects other than hazards
+	language string
+	brief    bool
+
+	// What was narrated last, so unchanged scenes stay quiet
+	mu         sync.Mutex
+	lastLabels map[string]bool
+	lastText   string
+}
+
+// RegisterNarrationService ends narration sessions on shutdown so their
+// connections don't hold up the servers' graceful stop
+func RegisterNarrationService(lc fx.Lifecycle, narration NarrationService) {
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			narration.Close()
+			return nil
+		},
+	})
+}
+
+// process runs detection and, when readText is set, OCR on the frame
+// concurrently, emitting events as each finishes. Operations switched off
+// by a kill switch are skipped. It fails with QUOTA_EXCEEDED once the
+// plan's daily quota is used up, and with FEATURE_DISABLED when every
+// requested operation is switched off.
+func (n *narrationSession) process(ctx context.Context, frame []byte, seq int, lang string, readText bool, emit func(dto.NarrationEvent)) error {
+	s := n.service
+
+	operations := []string{config.UploadOpDetect}
+	if readText {
+		operations = append(operations, config.UploadOpOCR)
+	}
+	var enabled []string
+	for _, operation := range operations {
+		if s.killSwitches.Disabled(operation) == nil {
+			enabled = append(enabled, operation)
+		}
+	}
+	if len(enabled) == 0 {
+		return apperrors.NewAppError(apperrors.ErrCodeFeatureDisabled, "Scene narration is temporarily disabled", http.StatusServiceUnavailable)
+	}
+
+	// Each operation counts as one AI request, like the REST endpoints
+	for range enabled {
+		allowance, allowed, err := s.plans.ConsumeQuota(ctx, &n.user)
+		if err != nil {
+			s.log.Warn("Failed to count AI quota", zap.Uint("user_id", n.user.ID), zap.Error(err))
+			break
+		}
+		if !allowed {
+			return apperrors.NewAppError(apperrors.ErrCodeQuotaExceeded, "Daily AI quota used up", http.StatusTooManyRequests).WithDetails(allowance)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, operation := range enabled {
+		wg.Add(1)
+		go func(operation string) {
+			defer wg.Done()
+			switch operation {
+			case config.UploadOpDetect:
+				n.detect(ctx, frame, seq, emit)
+			case config.UploadOpOCR:
+				n.readText(ctx, frame, seq, lang, emit)
+			}
+		}(operation)
+	}
+	wg.Wait()
+	return nil
+}
+
+// detect emits hazards and other objects that weren't in the previous frame
+func (n *narrationSession) detect(ctx context.Context, frame []byte, seq int, emit func(dto.NarrationEvent)) {
+	s := n.service
+	opCtx, cancel := context.WithTimeout(ctx, s.cfg.AIDetectTimeout)
+	defer cancel()
+
+	result, fromCache, err := s.ai.DetectObjects(opCtx, frame, "frame.jpg")
+	s.usage.Record(n.user.ID, config.UploadOpDetect, fromCache, err != nil)
+	if err != nil {
+		emit(narrationAIError(seq, err))
+		return
+	}
+
+	var parsed struct {
+		Objects []dto.NarrationObject `json:"objects"`
+	}
+	if data, err := json.Marshal(result); err == nil {
+		_ = json.Unmarshal(data, &parsed)
+	}
+
+	// Keep the most confident detection of each label
+	best := make(map[string]dto.NarrationObject, len(parsed.Objects))
+	for _, object := range parsed.Objects {
+		if current, ok := best[object.Label]; !ok || object.Confidence > current.Confidence {
+			best[object.Label] = object
+		}
+	}
+
+	n.mu.Lock()
+	var hazards, others []dto.NarrationObject
+	for label, object := range best {
+		if n.lastLabels[label] {
+			continue
+		}
+		if hazardLabels[label] {
+			hazards = append(hazards, object)
+		} else {
+			others = append(others, object)
+		}
+	}
+	n.lastLabels = make(map[string]bool, len(best))
+	for label := range best {
+		n.lastLabels[label] = true
+	}
+	n.mu.Unlock()
+
+	if len(hazards) > 0 {
+		emit(dto.NarrationEvent{Type: dto.NarrationEventObjects, Priority: dto.NarrationPriorityHigh, Frame: seq, Objects: byConfidence(hazards)})
+	}
+	if len(others) > 0 && !n.brief {
+		emit(dto.NarrationEvent{Type: dto.NarrationEventObjects, Priority: dto.NarrationPriorityLow, Frame: seq, Objects: byConfidence(others)})
+	}
+}
+
+// readText emits the frame's text unless it is the text narrated last
+func (n *narrationSession) readText(ctx context.Context, frame []byte, seq int, lang string, emit func(dto.NarrationEvent)) {
+	s := n.service
+	opCtx, cancel := context.WithTimeout(ctx, s.cfg.AIOCRTimeout)
+	defer cancel()
+
+	if lang == "" {
+		lang = n.language
+	}
+	result, fromCache, err := s.ai.ExtractText(opCtx, frame, "frame.jpg", lang)
+	s.usage.Record(n.user.ID, config.UploadOpOCR, fromCache, err != nil)
+	if err != nil {
+		emit(narrationAIError(seq, err))
+		return
+	}
+
+	var parsed struct {
+		FullText string `json:"full_text"`
+	}
+	if data, err := json.Marshal(result); err == nil {
+		_ = json.Unmarshal(data, &parsed)
+	}
+	text := strings.Join(strings.Fields(parsed.FullText), " ")
+
+	n.mu.Lock()
+	changed := text != n.lastText
+	n.lastText = text
+	n.mu.Unlock()
+
+	if changed && text != "" {
+		emit(dto.NarrationEvent{Type: dto.NarrationEventText, Priority: dto.NarrationPriorityNormal, Frame: seq, Text: text})
+	}
+}
+
+// byConfidence sorts objects most confident first
+func byConfidence(objects []dto.NarrationObject) []dto.NarrationObject {
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Confidence > objects[j].Confidence })
+	return objects
+}
+
+// narrationAIError reports a failed AI call; the session continues with the
+// next frame
+func narrationAIError(seq int, err error) dto.NarrationEvent {
+	return dto.NarrationEvent{
+		Type:    dto.NarrationEventError,
+		Frame:   seq,
+		Code:    string(apperrors.ErrCodeAIServiceError),
+		Message: err.Error(),
+	}
+}
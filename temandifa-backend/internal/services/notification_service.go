@@ -0,0 +1,410 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/clients"
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/metrics"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+)
+
+// Push notification types, sent in the "type" data field so the app can
+// route and localize them
+const (
+	NotificationDataExportReady = "data_export_ready"
+	NotificationNewSignIn       = "security.new_sign_in"
+	NotificationAnnouncement    = "announcement"
+	NotificationEmergencyAlert  = "emergency.alert"
+	NotificationConsentRequest  = "emergency.consent_request"
+)
+
+// Channels of messages to people outside the app
+const (
+	ChannelSMS   = "sms"
+	ChannelEmail = "email"
+)
+
+// TopicAnnouncements is the FCM topic announcement pushes are sent to; the
+// app subscribes every device to it
+const TopicAnnouncements = "announcements"
+
+const (
+	// notificationClaimPrefix prefixes the Redis keys claiming one-off pushes
+	// so only one instance sends them
+	notificationClaimPrefix = "push:sent:"
+	// notificationClaimTTL outlives the window in which a push can be claimed
+	notificationClaimTTL = 7 * 24 * time.Hour
+)
+
+// notificationRetryConfig retries transient FCM failures (network, quota,
+// server errors); invalid tokens and rejected messages are not retried
+var notificationRetryConfig = helpers.RetryConfig{
+	MaxRetries:     3,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     10 * time.Second,
+	Multiplier:     2.0,
+	Jitter:         0.25,
+}
+
+// Notification is a push notification. Title and body are shown by the OS
+// when the app is in the background; Data reaches the app. Sent to a
+// Recipient, the title is the email subject and the body the text.
+type Notification struct {
+	Type  string
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// Recipient is someone outside the app, reached by SMS and/or email
+type Recipient struct {
+	PhoneNumber string // E.164
+	Email       string
+}
+
+// smsSender sends text messages to phone numbers
+type smsSender interface {
+	Send(ctx context.Context, to, body string) error
+}
+
+// logSMSSender logs text messages instead of sending them (SMS_DRIVER=log)
+type logSMSSender struct {
+	log *zap.Logger
+}
+
+func (s logSMSSender) Send(_ context.Context, to, body string) error {
+	s.log.Info("SMS (not sent, SMS_DRIVER=log)", zap.String("to", to), zap.String("body", body))
+	return nil
+}
+
+// NotificationService sends push notifications to the push tokens of the
+// users' devices through Firebase Cloud Messaging and keeps them in the
+// in-app inbox (see InboxService), so users who miss a push still see them.
+// Sending happens in the background and never fails the caller. Without
+// FCM_CREDENTIALS_FILE tokens are still stored and notifications still reach
+// the inbox, but nothing is pushed.
+type NotificationService interface {
+	// RegisterToken registers a push token without a device ID (the
+	// deprecated /me/push-tokens API): it updates the user's device that has
+	// the token, or registers a device named after the token
+	RegisterToken(ctx context.Context, userID uint, token, platform string) error
+	// UnregisterToken removes the token from the user's device, reporting
+	// whether it had it
+	UnregisterToken(ctx context.Context, userID uint, token string) (bool, error)
+	// NotifyUser adds the notification to the user's inbox and pushes it to
+	// every device of the user
+	NotifyUser(ctx context.Context, userID uint, notification Notification)
+	// BroadcastOnce adds the notification to every user's inbox and pushes
+	// it to a topic, unless a notification with the same key was already
+	// sent, by this or another instance
+	BroadcastOnce(ctx context.Context, key, topic string, notification Notification)
+	// MessageRecipient sends the notification's title and body by SMS and
+	// email, to whichever of them the recipient has and is configured
+	// (SMS_DRIVER, SMTP_HOST), retrying transient failures. It waits for the
+	// deliveries and returns the channels that succeeded.
+	MessageRecipient(ctx context.Context, recipient Recipient, notification Notification) []string
+	// Wait blocks until pushes in progress finish or ctx is done
+	Wait(ctx context.Context) error
+}
+
+type notificationService struct {
+	repo  repositories.DeviceRepository
+	inbox repositories.NotificationRepository
+	fcm   *clients.FCMClient
+	sms   smsSender
+	email *clients.SMTPClient
+	rdb   *redis.Client
+	log   *zap.Logger
+	wg    sync.WaitGroup
+
+	// claimed records one-off pushes when Redis is not configured
+	claimed sync.Map
+}
+
+// NewNotificationService creates a new NotificationService, sending through
+// FCM when FCM_CREDENTIALS_FILE is set, SMS with SMS_DRIVER and email when
+// SMTP_HOST is set
+func NewNotificationService(repo repositories.DeviceRepository, inbox repositories.NotificationRepository, rdb *redis.Client, cfg *config.Config, log *zap.Logger) NotificationService {
+	s := &notificationService{repo: repo, inbox: inbox, rdb: rdb, log: log}
+
+	switch cfg.SMSDriver {
+	case "twilio":
+		s.sms = clients.NewTwilioClient(cfg.TwilioAccountSID, cfg.TwilioAuthToken, cfg.TwilioFromNumber)
+	case "log":
+		s.sms = logSMSSender{log: log}
+	}
+	if cfg.SMTPHost != "" {
+		email, err := clients.NewSMTPClient(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom)
+		if err != nil {
+			log.Error("Email disabled: invalid SMTP configuration", zap.Error(err))
+		} else {
+			s.email = email
+		}
+	}
+
+	if cfg.FCMCredentialsFile == "" {
+		log.Info("Push notifications disabled: FCM_CREDENTIALS_FILE not set")
+		return s
+	}
+
+	fcm, err := clients.NewFCMClient(cfg.FCMCredentialsFile, cfg.FCMProjectID)
+	if err != nil {
+		log.Error("Push notifications disabled: invalid FCM configuration", zap.Error(err))
+		return s
+	}
+	s.fcm = fcm
+	return s
+}
+
+func (s *notificationService) RegisterToken(ctx context.Context, userID uint, token, platform string) error {
+	existing, err := s.repo.FindByPushToken(ctx, userID, token)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256([]byte(token))
+	device := &models.Device{
+		UserID:     userID,
+		DeviceID:   "push-token-" + hex.EncodeToString(sum[:8]),
+		Platform:   platform,
+		PushToken:  &token,
+		LastSeenAt: time.Now(),
+	}
+	if existing != nil {
+		device.DeviceID = existing.DeviceID
+		device.AppVersion = existing.AppVersion
+	}
+	return s.repo.Save(ctx, device)
+}
+
+func (s *notificationService) UnregisterToken(ctx context.Context, userID uint, token string) (bool, error) {
+	return s.repo.ClearPushToken(ctx, userID, token)
+}
+
+func (s *notificationService) NotifyUser(ctx context.Context, userID uint, notification Notification) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ctx := context.WithoutCancel(ctx)
+		log := s.log.With(zap.Uint("user_id", userID), zap.String("type", notification.Type))
+
+		if err := s.inbox.Create(ctx, inboxEntry(&userID, nil, notification)); err != nil {
+			log.Warn("Failed to add notification to inbox", zap.Error(err))
+		}
+		if s.fcm == nil {
+			return
+		}
+
+		tokens, err := s.repo.ListPushTokens(ctx, userID)
+		if err != nil {
+			log.Warn("Failed to load push tokens", zap.Error(err))
+			return
+		}
+
+		var invalid []string
+		for _, token := range tokens {
+			if errors.Is(s.send(ctx, log, clients.FCMMessage{Token: token}, notification), clients.ErrFCMTokenInvalid) {
+				invalid = append(invalid, token)
+			}
+		}
+		if err := s.repo.ClearPushTokens(ctx, invalid); err != nil {
+			log.Warn("Failed to delete invalid push tokens", zap.Error(err))
+		}
+	}()
+}
+
+func (s *notificationService) BroadcastOnce(ctx context.Context, key, topic string, notification Notification) {
+	// The inbox entry is deduplicated by the database
+	if err := s.inbox.Create(ctx, inboxEntry(nil, &key, notification)); err != nil {
+		s.log.Warn("Failed to add notification to inbox", zap.String("key", key), zap.Error(err))
+	}
+	if s.fcm == nil {
+		return
+	}
+
+	claimed, err := s.claim(ctx, key)
+	if err != nil {
+		s.log.Warn("Failed to claim push notification", zap.String("key", key), zap.Error(err))
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		log := s.log.With(zap.String("topic", topic), zap.String("type", notification.Type))
+		_ = s.send(context.WithoutCancel(ctx), log, clients.FCMMessage{Topic: topic}, notification)
+	}()
+}
+
+// inboxEntry is the inbox copy of a notification for the user, or for
+// everyone when userID is nil
+func inboxEntry(userID *uint, dedupKey *string, notification Notification) *models.Notification {
+	data := notification.Data
+	if data == nil {
+		data = map[string]string{}
+	}
+	encoded, _ := json.Marshal(data)
+	return &models.Notification{
+		UserID:   userID,
+		Type:     notification.Type,
+		Title:    notification.Title,
+		Body:     notification.Body,
+		Data:     string(encoded),
+		DedupKey: dedupKey,
+	}
+}
+
+// claim reports whether this instance is the first to claim the key
+func (s *notificationService) claim(ctx context.Context, key string) (bool, error) {
+	if s.rdb == nil {
+		_, loaded := s.claimed.LoadOrStore(key, struct{}{})
+		return !loaded, nil
+	}
+	return s.rdb.SetNX(ctx, notificationClaimPrefix+key, 1, notificationClaimTTL).Result()
+}
+
+// send delivers one message, retrying transient failures, and returns the
+// last error
+func (s *notificationService) send(ctx context.Context, log *zap.Logger, msg clients.FCMMessage, notification Notification) error {
+	msg.Title = notification.Title
+	msg.Body = notification.Body
+	msg.Data = map[string]string{"type": notification.Type}
+	for key, value := range notification.Data {
+		msg.Data[key] = value
+	}
+
+	var permanent error
+	err := helpers.WithRetry(ctx, notificationRetryConfig, "fcm_send", func() error {
+		err := s.fcm.Send(ctx, msg)
+		if errors.Is(err, clients.ErrFCMTokenInvalid) || errors.Is(err, clients.ErrFCMRejected) {
+			permanent = err
+			return nil
+		}
+		return err
+	})
+	if err == nil {
+		err = permanent
+	}
+
+	switch {
+	case err == nil:
+		metrics.PushNotifications.WithLabelValues(notification.Type, "sent").Inc()
+	case errors.Is(err, clients.ErrFCMTokenInvalid):
+		metrics.PushNotifications.WithLabelValues(notification.Type, "invalid_token").Inc()
+		log.Info("Push token no longer valid", zap.Error(err))
+	default:
+		metrics.PushNotifications.WithLabelValues(notification.Type, "failed").Inc()
+		log.Warn("Failed to send push notification", zap.Error(err))
+	}
+	return err
+}
+
+func (s *notificationService) MessageRecipient(ctx context.Context, recipient Recipient, notification Notification) []string {
+	log := s.log.With(zap.String("type", notification.Type))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var delivered []string
+	deliver := func(channel string, send func() error) {
+		defer wg.Done()
+		var permanent error
+		err := helpers.WithRetry(ctx, notificationRetryConfig, channel+"_send", func() error {
+			err := send()
+			if errors.Is(err, clients.ErrSMSRejected) || errors.Is(err, clients.ErrEmailRejected) {
+				permanent = err
+				return nil
+			}
+			return err
+		})
+		if err == nil {
+			err = permanent
+		}
+
+		switch {
+		case err == nil:
+			metrics.ContactMessages.WithLabelValues(notification.Type, channel, "sent").Inc()
+			mu.Lock()
+			delivered = append(delivered, channel)
+			mu.Unlock()
+		case permanent != nil:
+			metrics.ContactMessages.WithLabelValues(notification.Type, channel, "rejected").Inc()
+			log.Info("Message rejected", zap.String("channel", channel), zap.Error(err))
+		default:
+			metrics.ContactMessages.WithLabelValues(notification.Type, channel, "failed").Inc()
+			log.Warn("Failed to send message", zap.String("channel", channel), zap.Error(err))
+		}
+	}
+
+	if s.sms != nil && recipient.PhoneNumber != "" {
+		wg.Add(1)
+		go deliver(ChannelSMS, func() error {
+			return s.sms.Send(ctx, recipient.PhoneNumber, notification.Body)
+		})
+	}
+	if s.email != nil && recipient.Email != "" {
+		wg.Add(1)
+		go deliver(ChannelEmail, func() error {
+			return s.email.Send(ctx, recipient.Email, notification.Title, notification.Body)
+		})
+	}
+	wg.Wait()
+	sort.Strings(delivered)
+	return delivered
+}
+
+func (s *notificationService) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// announcementNotification is the push of an announcement in its default
+// locale (the first translation, see announcementService.Refresh)
+func announcementNotification(announcement models.Announcement) Notification {
+	translation := announcement.Translations[0]
+	return Notification{
+		Type:  NotificationAnnouncement,
+		Title: translation.Title,
+		Body:  translation.Body,
+		Data: map[string]string{
+			"announcement_id": strconv.FormatUint(uint64(announcement.ID), 10),
+			"kind":            announcement.Kind,
+		},
+	}
+}
+
+// RegisterNotificationService lets pushes in progress finish on shutdown
+func RegisterNotificationService(lc fx.Lifecycle, notifications NotificationService, log *zap.Logger) {
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			if err := notifications.Wait(ctx); err != nil {
+				log.Warn("Push notifications still sending at shutdown", zap.Error(err))
+			}
+			return nil
+		},
+	})
+}
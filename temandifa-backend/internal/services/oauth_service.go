@@ -0,0 +1,366 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/clients"
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+)
+
+// idTokenVerifier verifies a sign-in provider's ID tokens
+type idTokenVerifier interface {
+	Verify(ctx context.Context, rawToken string) (*clients.IDTokenClaims, error)
+}
+
+var errOIDCSignInInvalid = apperrors.NewAppError(apperrors.ErrCodeTokenInvalid, "The sign-in attempt is invalid or has expired, please start again", http.StatusBadRequest)
+
+// OAuthService signs users in with the ID tokens the Google and Apple SDKs
+// give the app, and through organizations' OpenID Connect providers. First
+// sign-ins create the user, or link the provider to the account with the
+// same email when the provider verified it.
+type OAuthService interface {
+	// SignIn exchanges an ID token of provider (models.ProviderGoogle or
+	// models.ProviderApple) for a token pair, like Login
+	SignIn(ctx context.Context, provider string, input dto.OAuthSignInRequest, userAgent, ipAddress, deviceID string) (*dto.LoginResponse, error)
+	// OIDCAuthURL starts a sign-in at the organization's provider (its
+	// OIDC_PROVIDERS name). It returns the provider's sign-in page and the
+	// binding the browser must keep to finish the sign-in.
+	OIDCAuthURL(ctx context.Context, provider string) (authURL, binding string, err error)
+	// OIDCSignIn finishes a sign-in at the organization's provider with the
+	// code and state it sent back and the browser's binding, like SignIn.
+	// The user joins the provider's organization.
+	OIDCSignIn(ctx context.Context, provider, code, state, binding, userAgent, ipAddress, deviceID string) (*dto.LoginResponse, error)
+}
+
+// oidcProvider is an organization's identity provider
+type oidcProvider struct {
+	config.OIDCProvider
+	client      *clients.OIDCClient
+	redirectURI string
+}
+
+type oauthService struct {
+	users         repositories.UserRepository
+	identities    repositories.UserIdentityRepository
+	tokenService  TokenService
+	txManager     database.TxManager
+	outbox        OutboxService
+	notifications NotificationService
+	userCache     UserCacheService
+	verifiers     map[string]idTokenVerifier
+	oidc          map[string]*oidcProvider
+}
+
+// NewOAuthService creates a new OAuthService for the providers with client
+// IDs configured and the OIDC_PROVIDERS
+func NewOAuthService(users repositories.UserRepository, identities repositories.UserIdentityRepository, tokenService TokenService, txManager database.TxManager, outbox OutboxService, notifications NotificationService, userCache UserCacheService, cfg *config.Config) OAuthService {
+	verifiers := make(map[string]idTokenVerifier)
+	if len(cfg.GoogleClientIDs) > 0 {
+		verifiers[models.ProviderGoogle] = clients.NewGoogleIDTokenVerifier(cfg.GoogleClientIDs)
+	}
+	if len(cfg.AppleClientIDs) > 0 {
+		verifiers[models.ProviderApple] = clients.NewAppleIDTokenVerifier(cfg.AppleClientIDs)
+	}
+	oidc := make(map[string]*oidcProvider, len(cfg.OIDCProviders))
+	for _, provider := range cfg.OIDCProviders {
+		oidc[provider.Name] = &oidcProvider{
+			OIDCProvider: provider,
+			client:       clients.NewOIDCClient(provider.Issuer, provider.ClientID, provider.ClientSecret),
+			redirectURI:  cfg.PublicBaseURL + "/api/v1/auth/oidc/" + provider.Name + "/callback",
+		}
+	}
+	return &oauthService{
+		users:         users,
+		identities:    identities,
+		tokenService:  tokenService,
+		txManager:     txManager,
+		outbox:        outbox,
+		notifications: notifications,
+		userCache:     userCache,
+		verifiers:     verifiers,
+		oidc:          oidc,
+	}
+}
+
+func (s *oauthService) SignIn(ctx context.Context, provider string, input dto.OAuthSignInRequest, userAgent, ipAddress, deviceID string) (*dto.LoginResponse, error) {
+	verifier, ok := s.verifiers[provider]
+	if !ok {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeFeatureDisabled, "This sign-in method is not available", http.StatusServiceUnavailable)
+	}
+
+	claims, err := verifier.Verify(ctx, input.IDToken)
+	if err != nil {
+		return nil, idTokenError(ctx, provider, err)
+	}
+	return s.signIn(ctx, provider, claims, input.FullName, nil, userAgent, ipAddress, deviceID)
+}
+
+// idTokenError answers a failed ID token verification
+func idTokenError(ctx context.Context, provider string, err error) error {
+	if errors.Is(err, clients.ErrIDTokenInvalid) {
+		logger.Ctx(ctx).Debug("ID token rejected", zap.String("provider", provider), zap.Error(err))
+		return apperrors.NewAppError(apperrors.ErrCodeTokenInvalid, "Invalid ID token", http.StatusUnauthorized)
+	}
+	logger.Ctx(ctx).Error("Failed to verify ID token", zap.String("provider", provider), zap.Error(err))
+	return apperrors.NewAppError(apperrors.ErrCodeExternalService, "Sign-in is temporarily unavailable", http.StatusServiceUnavailable)
+}
+
+// oidcBindingSecrets derives a sign-in's state, nonce and PKCE challenge from
+// the binding the browser keeps, which is also the PKCE verifier; nothing
+// about pending sign-ins is stored
+func oidcBindingSecrets(binding string) (state, nonce, challenge string) {
+	derive := func(value string) string {
+		sum := sha256.Sum256([]byte(value))
+		return base64.RawURLEncoding.EncodeToString(sum[:])
+	}
+	return derive("state:" + binding), derive("nonce:" + binding), derive(binding)
+}
+
+func (s *oauthService) OIDCAuthURL(ctx context.Context, provider string) (string, string, error) {
+	p, ok := s.oidc[provider]
+	if !ok {
+		return "", "", apperrors.NewAppError(apperrors.ErrCodeNotFound, "Unknown sign-in provider", http.StatusNotFound)
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	binding := base64.RawURLEncoding.EncodeToString(b)
+	state, nonce, challenge := oidcBindingSecrets(binding)
+
+	authURL, err := p.client.AuthCodeURL(ctx, p.redirectURI, state, nonce, challenge)
+	if err != nil {
+		logger.Ctx(ctx).Error("Failed to reach OIDC provider", zap.String("provider", provider), zap.Error(err))
+		return "", "", apperrors.NewAppError(apperrors.ErrCodeExternalService, "Sign-in is temporarily unavailable", http.StatusServiceUnavailable)
+	}
+	return authURL, binding, nil
+}
+
+func (s *oauthService) OIDCSignIn(ctx context.Context, provider, code, state, binding, userAgent, ipAddress, deviceID string) (*dto.LoginResponse, error) {
+	p, ok := s.oidc[provider]
+	if !ok {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeNotFound, "Unknown sign-in provider", http.StatusNotFound)
+	}
+
+	// The state proves the sign-in was started in this browser
+	expectedState, nonce, _ := oidcBindingSecrets(binding)
+	if binding == "" || code == "" || subtle.ConstantTimeCompare([]byte(state), []byte(expectedState)) != 1 {
+		return nil, errOIDCSignInInvalid
+	}
+
+	identityProvider := models.ProviderOIDCPrefix + p.Name
+	claims, err := p.client.Exchange(ctx, code, p.redirectURI, binding)
+	if err != nil {
+		return nil, idTokenError(ctx, identityProvider, err)
+	}
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return nil, errOIDCSignInInvalid
+	}
+
+	// The provider vouches for the addresses of its organization's domains,
+	// and only those may sign in with it
+	if len(p.EmailDomains) > 0 {
+		_, domain, _ := strings.Cut(strings.ToLower(claims.Email), "@")
+		if !slices.Contains(p.EmailDomains, domain) {
+			return nil, apperrors.NewAppError(apperrors.ErrCodeForbidden, "This email address doesn't belong to the organization", http.StatusForbidden)
+		}
+		claims.EmailVerified = true
+	}
+	return s.signIn(ctx, identityProvider, claims, "", &p.OIDCProvider, userAgent, ipAddress, deviceID)
+}
+
+// signIn signs in the user of the provider's verified claims, creating or
+// linking them on the first sign-in; org is the organization the user joins
+func (s *oauthService) signIn(ctx context.Context, provider string, claims *clients.IDTokenClaims, fullName string, org *config.OIDCProvider, userAgent, ipAddress, deviceID string) (*dto.LoginResponse, error) {
+	var user *models.User
+	var created, passwordCleared, joined bool
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		now := time.Now()
+		identity, err := s.identities.Find(ctx, provider, claims.Subject)
+		if err != nil {
+			return err
+		}
+		if identity != nil {
+			if user, err = s.users.FindByID(ctx, identity.UserID); err != nil {
+				return err
+			}
+			// Soft-deleted accounts can't sign in while they wait to be purged
+			if user == nil {
+				return apperrors.ErrInvalidCredentials
+			}
+			if err := s.identities.Touch(ctx, identity.ID, now); err != nil {
+				return err
+			}
+			joined, err = s.joinOrganization(ctx, user, org)
+			return err
+		}
+
+		// Accounts are only linked to, or created for, addresses the provider
+		// verified
+		if claims.Email == "" || !claims.EmailVerified {
+			return apperrors.NewAppError(apperrors.ErrCodeEmailNotVerified, "The email address of this account is not verified by the provider", http.StatusForbidden)
+		}
+		if user, err = s.users.FindByEmail(ctx, claims.Email); err != nil {
+			return err
+		}
+		switch {
+		case user != nil && user.DeletedAt != nil:
+			return apperrors.ErrInvalidCredentials
+		case user != nil:
+			if joined, err = s.joinOrganization(ctx, user, org); err != nil {
+				return err
+			}
+			if user.EmailVerifiedAt == nil {
+				// Whoever registered the unverified address may not own it,
+				// so the password set then stops working
+				user.Password = ""
+				if err := s.users.Update(ctx, user); err != nil {
+					return err
+				}
+				if _, err := s.users.MarkEmailVerified(ctx, user.ID, user.Email, now); err != nil {
+					return err
+				}
+				user.EmailVerifiedAt = &now
+				passwordCleared = true
+			}
+		default:
+			user = &models.User{
+				FullName:        signInName(claims, fullName),
+				Email:           claims.Email,
+				EmailVerifiedAt: &now,
+			}
+			// Provisioned straight into the organization
+			if org != nil {
+				user.Organization = org.Organization
+				user.Plan = org.Plan
+			}
+			if err := s.users.Create(ctx, user); err != nil {
+				return err
+			}
+			payload := map[string]any{
+				"user_id":   user.ID,
+				"email":     user.Email,
+				"full_name": user.FullName,
+				"provider":  provider,
+			}
+			if org != nil {
+				payload["organization"] = org.Organization
+			}
+			if err := s.outbox.Enqueue(ctx, OutboxUserRegistered, "user", strconv.FormatUint(uint64(user.ID), 10), payload); err != nil {
+				return err
+			}
+			created = true
+		}
+
+		return s.identities.Create(ctx, &models.UserIdentity{
+			UserID:     user.ID,
+			Provider:   provider,
+			Subject:    claims.Subject,
+			Email:      claims.Email,
+			CreatedAt:  now,
+			LastUsedAt: now,
+		})
+	})
+	if err != nil {
+		if _, ok := apperrors.AsAppError(err); ok {
+			return nil, err
+		}
+		return nil, apperrors.Database(err)
+	}
+
+	if joined {
+		if err := s.userCache.InvalidateUserCache(ctx, user.ID); err != nil {
+			logger.Ctx(ctx).Debug("Failed to invalidate user cache", zap.Uint("user_id", user.ID), zap.Error(err))
+		}
+	}
+
+	if passwordCleared {
+		if err := s.tokenService.RevokeAllUserTokens(user.ID); err != nil {
+			logger.Ctx(ctx).Error("Failed to sign out sessions of the unverified account", zap.Uint("user_id", user.ID), zap.Error(err))
+		}
+	}
+
+	tokenPair, err := s.tokenService.GenerateTokenPair(ctx, user, userAgent, ipAddress, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !created {
+		s.notifications.NotifyUser(ctx, user.ID, Notification{
+			Type:  NotificationNewSignIn,
+			Title: "New sign-in to your account",
+			Body:  "If this wasn't you, contact support right away.",
+			Data:  map[string]string{"user_agent": userAgent, "ip_address": ipAddress, "provider": provider},
+		})
+	}
+
+	return &dto.LoginResponse{
+		TokenResponse: dto.TokenResponse{
+			AccessToken:  tokenPair.AccessToken,
+			RefreshToken: tokenPair.RefreshToken,
+			ExpiresAt:    tokenPair.ExpiresAt,
+			TokenType:    tokenPair.TokenType,
+		},
+		User: dto.UserInfo{
+			ID:            user.ID,
+			FullName:      user.FullName,
+			Email:         user.Email,
+			EmailVerified: user.EmailVerifiedAt != nil,
+		},
+		NewUser: created,
+	}, nil
+}
+
+// joinOrganization records that the user belongs to org, whose provider
+// signed them in, and moves users on the free plan to its plan; it reports
+// whether the user changed. A nil org changes nothing.
+func (s *oauthService) joinOrganization(ctx context.Context, user *models.User, org *config.OIDCProvider) (bool, error) {
+	if org == nil {
+		return false, nil
+	}
+	changed := false
+	if user.Organization != org.Organization {
+		user.Organization = org.Organization
+		changed = true
+	}
+	if org.Plan != "" && user.Plan == models.PlanFree {
+		user.Plan = org.Plan
+		changed = true
+	}
+	if !changed {
+		return false, nil
+	}
+	return true, s.users.Update(ctx, user)
+}
+
+// signInName names a new user: the provider's profile name, else the one the
+// app sent, else the email's local part
+func signInName(claims *clients.IDTokenClaims, fullName string) string {
+	if name := strings.TrimSpace(claims.Name); name != "" {
+		return name
+	}
+	if name := strings.TrimSpace(fullName); name != "" {
+		return name
+	}
+	local, _, _ := strings.Cut(claims.Email, "@")
+	return local
+}
@@ -0,0 +1,335 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/metrics"
+	"temandifa-backend/internal/models"
+)
+
+// Integration event types written to the outbox
+const (
+	OutboxUserRegistered = "user.registered"
+)
+
+const (
+	// outboxLease is how long a claimed event is hidden from other dispatchers;
+	// events of a crashed dispatcher become due again once it expires
+	outboxLease = 2 * time.Minute
+	// outboxMaxBackoff caps the delay between delivery attempts
+	outboxMaxBackoff = time.Hour
+	// outboxPurgeInterval controls how often dispatched events are purged
+	outboxPurgeInterval = time.Hour
+	// outboxSendTimeout bounds a single delivery
+	outboxSendTimeout = 10 * time.Second
+)
+
+// OutboxService records integration events. Enqueue must be called with the
+// context of the surrounding TxManager transaction so the event is committed
+// (or rolled back) together with the business change.
+type OutboxService interface {
+	Enqueue(ctx context.Context, eventType, aggregateType, aggregateID string, payload any) error
+}
+
+type outboxService struct {
+	db *gorm.DB
+}
+
+// NewOutboxService creates a new OutboxService
+func NewOutboxService(db *gorm.DB) OutboxService {
+	return &outboxService{db: db}
+}
+
+func (s *outboxService) Enqueue(ctx context.Context, eventType, aggregateType, aggregateID string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	event := models.OutboxEvent{
+		EventType:     eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       string(data),
+		Status:        models.OutboxStatusPending,
+		NextAttemptAt: time.Now(),
+	}
+	return database.Conn(ctx, s.db).Create(&event).Error
+}
+
+// OutboxPublisher delivers a single outbox event. Deliveries may repeat, so
+// receivers should deduplicate on the event ID.
+type OutboxPublisher interface {
+	Name() string
+	Publish(ctx context.Context, event models.OutboxEvent) error
+}
+
+// OutboxDispatcher periodically forwards pending outbox events to publishers,
+// retrying failures with exponential backoff (at-least-once delivery)
+type OutboxDispatcher struct {
+	db          *gorm.DB
+	publishers  []OutboxPublisher
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+	retention   time.Duration
+	log         *zap.Logger
+	stopChan    chan struct{}
+}
+
+// NewOutboxDispatcher creates the dispatcher with publishers from
+// configuration, followed by the webhook subscriptions
+func NewOutboxDispatcher(db *gorm.DB, cfg *config.Config, webhooks WebhookService, log *zap.Logger) *OutboxDispatcher {
+	var publishers []OutboxPublisher
+	client := &http.Client{Timeout: outboxSendTimeout}
+	for _, url := range cfg.OutboxWebhookURLs {
+		publishers = append(publishers, &webhookOutboxPublisher{
+			url:    url,
+			secret: cfg.OutboxWebhookSecret,
+			client: client,
+		})
+	}
+	// Last, as it records its deliveries and skips them when the event is
+	// retried
+	publishers = append(publishers, webhooks)
+
+	return &OutboxDispatcher{
+		db:          db,
+		publishers:  publishers,
+		interval:    cfg.OutboxPollInterval,
+		batchSize:   cfg.OutboxBatchSize,
+		maxAttempts: cfg.OutboxMaxAttempts,
+		retention:   cfg.OutboxRetention,
+		log:         log,
+		stopChan:    make(chan struct{}),
+	}
+}
+
+// Start begins polling for pending events
+func (d *OutboxDispatcher) Start() {
+	d.log.Info("Outbox dispatcher started",
+		zap.Int("publishers", len(d.publishers)),
+		zap.Duration("interval", d.interval),
+	)
+
+	ticker := time.NewTicker(d.interval)
+	purgeTicker := time.NewTicker(outboxPurgeInterval)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				d.dispatch()
+			case <-purgeTicker.C:
+				d.purge()
+			case <-d.stopChan:
+				ticker.Stop()
+				purgeTicker.Stop()
+				d.log.Info("Outbox dispatcher stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the dispatcher; claimed but undelivered events are retried after their lease
+func (d *OutboxDispatcher) Stop() {
+	close(d.stopChan)
+}
+
+// dispatch claims a batch of due events and delivers them
+func (d *OutboxDispatcher) dispatch() {
+	ctx := context.Background()
+
+	events, err := d.claim(ctx)
+	if err != nil {
+		d.log.Error("Failed to claim outbox events", zap.Error(err))
+		return
+	}
+
+	for _, event := range events {
+		d.deliver(ctx, event)
+	}
+
+	var pending int64
+	if err := d.db.Model(&models.OutboxEvent{}).Where("status = ?", models.OutboxStatusPending).Count(&pending).Error; err == nil {
+		metrics.OutboxPending.Set(float64(pending))
+	}
+}
+
+// claim leases due events so concurrent dispatchers (other instances) skip them
+func (d *OutboxDispatcher) claim(ctx context.Context) ([]models.OutboxEvent, error) {
+	// SQLite (local development) serializes writers and has no row locks
+	skipLocked := "FOR UPDATE SKIP LOCKED"
+	if d.db.Dialector.Name() != "postgres" {
+		skipLocked = ""
+	}
+
+	var events []models.OutboxEvent
+	err := d.db.WithContext(ctx).Raw(`
+		UPDATE outbox_events SET next_attempt_at = ?, attempts = attempts + 1
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY id
+			LIMIT ?
+			`+skipLocked+`
+		)
+		RETURNING *`,
+		time.Now().Add(outboxLease), models.OutboxStatusPending, time.Now(), d.batchSize,
+	).Scan(&events).Error
+	return events, err
+}
+
+// deliver publishes an event to every publisher and records the outcome
+func (d *OutboxDispatcher) deliver(ctx context.Context, event models.OutboxEvent) {
+	var deliveryErr error
+	for _, publisher := range d.publishers {
+		if err := publisher.Publish(ctx, event); err != nil {
+			deliveryErr = fmt.Errorf("%s: %w", publisher.Name(), err)
+			break
+		}
+	}
+
+	updates := map[string]any{}
+	switch {
+	case deliveryErr == nil:
+		now := time.Now()
+		updates["status"] = models.OutboxStatusDispatched
+		updates["dispatched_at"] = &now
+		updates["last_error"] = ""
+		metrics.OutboxEvents.WithLabelValues(event.EventType, "dispatched").Inc()
+
+	case event.Attempts >= d.maxAttempts:
+		updates["status"] = models.OutboxStatusFailed
+		updates["last_error"] = deliveryErr.Error()
+		metrics.OutboxEvents.WithLabelValues(event.EventType, "failed").Inc()
+		d.log.Error("Outbox event delivery abandoned",
+			zap.Uint64("event_id", event.ID),
+			zap.String("event_type", event.EventType),
+			zap.Int("attempts", event.Attempts),
+			zap.Error(deliveryErr),
+		)
+
+	default:
+		updates["next_attempt_at"] = time.Now().Add(outboxBackoff(event.Attempts))
+		updates["last_error"] = deliveryErr.Error()
+		metrics.OutboxEvents.WithLabelValues(event.EventType, "retry").Inc()
+		d.log.Warn("Outbox event delivery failed, will retry",
+			zap.Uint64("event_id", event.ID),
+			zap.String("event_type", event.EventType),
+			zap.Int("attempts", event.Attempts),
+			zap.Error(deliveryErr),
+		)
+	}
+
+	if err := d.db.WithContext(ctx).Model(&models.OutboxEvent{}).Where("id = ?", event.ID).Updates(updates).Error; err != nil {
+		// The lease expires and the event is delivered again
+		d.log.Error("Failed to update outbox event", zap.Uint64("event_id", event.ID), zap.Error(err))
+	}
+}
+
+// purge deletes dispatched events older than the retention period
+func (d *OutboxDispatcher) purge() {
+	result := d.db.Where("status = ? AND dispatched_at < ?", models.OutboxStatusDispatched, time.Now().Add(-d.retention)).
+		Delete(&models.OutboxEvent{})
+	if result.Error != nil {
+		d.log.Error("Outbox purge failed", zap.Error(result.Error))
+		return
+	}
+	if result.RowsAffected > 0 {
+		d.log.Info("Outbox purge completed", zap.Int64("events_removed", result.RowsAffected))
+	}
+}
+
+// outboxBackoff returns the retry delay after the given number of attempts
+func outboxBackoff(attempts int) time.Duration {
+	backoff := time.Duration(math.Pow(2, float64(attempts))) * time.Second
+	if backoff <= 0 || backoff > outboxMaxBackoff {
+		return outboxMaxBackoff
+	}
+	return backoff
+}
+
+// RegisterOutboxDispatcher registers the outbox dispatcher with fx lifecycle
+func RegisterOutboxDispatcher(lc fx.Lifecycle, dispatcher *OutboxDispatcher) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			dispatcher.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			dispatcher.Stop()
+			return nil
+		},
+	})
+}
+
+// webhookOutboxPublisher POSTs events signed like alert webhooks
+// (X-Temandifa-Signature over "<timestamp>.<body>")
+type webhookOutboxPublisher struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+func (p *webhookOutboxPublisher) Name() string { return "webhook:" + p.url }
+
+// outboxEventBody is the JSON body webhooks receive for an event
+func outboxEventBody(event models.OutboxEvent) ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"id":             event.ID,
+		"type":           event.EventType,
+		"aggregate_type": event.AggregateType,
+		"aggregate_id":   event.AggregateID,
+		"created_at":     event.CreatedAt,
+		"data":           json.RawMessage(event.Payload),
+	})
+}
+
+func (p *webhookOutboxPublisher) Publish(ctx context.Context, event models.OutboxEvent) error {
+	body, err := outboxEventBody(event)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, outboxSendTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Temandifa-Event-ID", strconv.FormatUint(event.ID, 10))
+	if p.secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set("X-Temandifa-Timestamp", timestamp)
+		req.Header.Set("X-Temandifa-Signature", "sha256="+signPayload(p.secret, timestamp, body))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
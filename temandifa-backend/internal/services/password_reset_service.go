@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/database"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+)
+
+const (
+	// NotificationPasswordReset is the type of password reset messages
+	NotificationPasswordReset = "account.password_reset"
+	// NotificationPasswordChanged tells the user's devices their password
+	// was reset or changed
+	NotificationPasswordChanged = "security.password_changed"
+)
+
+var errResetTokenInvalid = apperrors.NewAppError(apperrors.ErrCodeTokenInvalid, "The reset token is invalid or has expired", http.StatusBadRequest)
+
+// PasswordResetService resets forgotten passwords with short-lived,
+// single-use tokens emailed to the account's address
+type PasswordResetService interface {
+	// Request emails a reset token when an account has the address. It
+	// answers the same whether or not one does, except when the address
+	// asked too often.
+	Request(ctx context.Context, email string) error
+	// Reset sets the password of the token's account and signs it out
+	// everywhere
+	Reset(ctx context.Context, token, password string) error
+}
+
+type passwordResetService struct {
+	repo          repositories.VerificationTokenRepository
+	users         repositories.UserRepository
+	tokens        TokenService
+	notifications NotificationService
+	txManager     database.TxManager
+	userCache     UserCacheService
+	rdb           *redis.Client
+	cfg           *config.AuthConfig
+}
+
+// NewPasswordResetService creates a new PasswordResetService
+func NewPasswordResetService(repo repositories.VerificationTokenRepository, users repositories.UserRepository, tokens TokenService, notifications NotificationService, txManager database.TxManager, userCache UserCacheService, rdb *redis.Client, cfg *config.AuthConfig) PasswordResetService {
+	return &passwordResetService{
+		repo:          repo,
+		users:         users,
+		tokens:        tokens,
+		notifications: notifications,
+		txManager:     txManager,
+		userCache:     userCache,
+		rdb:           rdb,
+		cfg:           cfg,
+	}
+}
+
+// passwordResetKey names the counter of an email address' reset requests,
+// hashed so the addresses don't end up in Redis
+func passwordResetKey(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return "password_reset:" + hex.EncodeToString(sum[:])
+}
+
+// allow counts a reset request of email, reporting whether it is within
+// PASSWORD_RESET_LIMIT. Addresses of no account count too, so the limit
+// doesn't tell which have one.
+func (s *passwordResetService) allow(ctx context.Context, email string) bool {
+	if s.rdb == nil {
+		return true
+	}
+	key := passwordResetKey(email)
+	pipe := s.rdb.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.ExpireNX(ctx, key, s.cfg.PasswordResetWindow)
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Ctx(ctx).Warn("Failed to count password reset request", zap.Error(err))
+		return true
+	}
+	return incr.Val() <= int64(s.cfg.PasswordResetLimit)
+}
+
+func (s *passwordResetService) Request(ctx context.Context, email string) error {
+	if !s.allow(ctx, email) {
+		return apperrors.NewAppError(apperrors.ErrCodeRateLimited, "Too many password reset requests, please try again later", http.StatusTooManyRequests)
+	}
+
+	user, err := s.users.FindByEmail(ctx, email)
+	if err != nil {
+		return apperrors.Database(err)
+	}
+	// Soft-deleted accounts can't sign in, so they can't reset either
+	if user == nil || user.DeletedAt != nil {
+		return nil
+	}
+
+	// Without Redis the stored tokens bound the emails an account receives
+	if s.rdb == nil {
+		sent, err := s.repo.CountSince(ctx, user.ID, models.TokenPurposePasswordReset, time.Now().Add(-s.cfg.PasswordResetWindow))
+		if err != nil {
+			return apperrors.Database(err)
+		}
+		if sent >= int64(s.cfg.PasswordResetLimit) {
+			logger.Ctx(ctx).Info("Password reset not sent: limit reached", zap.Uint("user_id", user.ID))
+			return nil
+		}
+	}
+
+	token, err := issueVerificationToken(ctx, s.repo, user, models.TokenPurposePasswordReset, s.cfg.PasswordResetTTL)
+	if err != nil {
+		return apperrors.Database(err)
+	}
+
+	// Sent in the background, so the response time doesn't tell either
+	go s.send(context.WithoutCancel(ctx), user, token)
+	return nil
+}
+
+func (s *passwordResetService) send(ctx context.Context, user *models.User, token string) {
+	instructions := "enter this code in the TemanDifa app: " + token
+	if s.cfg.PasswordResetURL != "" {
+		instructions = "open this link: " + s.cfg.PasswordResetURL + token
+	}
+	channels := s.notifications.MessageRecipient(ctx, Recipient{Email: user.Email}, Notification{
+		Type:  NotificationPasswordReset,
+		Title: "Reset your TemanDifa password",
+		Body: fmt.Sprintf("Hi %s,\n\nTo choose a new password within %s, %s\n\n"+
+			"If you didn't ask to reset your password, you can ignore this email; your password stays the same.",
+			user.FullName, linkValidity(s.cfg.PasswordResetTTL), instructions),
+	})
+	if len(channels) == 0 {
+		logger.Ctx(ctx).Warn("Password reset email not sent", zap.Uint("user_id", user.ID))
+	}
+}
+
+func (s *passwordResetService) Reset(ctx context.Context, token, password string) error {
+	if issues := helpers.ValidatePasswordStrength(password); len(issues) > 0 {
+		return apperrors.ValidationWithDetails("Password does not meet security requirements", issues)
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return apperrors.Internal(err)
+	}
+
+	var user *models.User
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		now := time.Now()
+		stored, err := useVerificationToken(ctx, s.repo, token, models.TokenPurposePasswordReset, now)
+		if err != nil {
+			return apperrors.Database(err)
+		}
+		if stored == nil {
+			return errResetTokenInvalid
+		}
+
+		// The token only proves access to the address it was sent to
+		user, err = s.users.FindByID(ctx, stored.UserID)
+		if err != nil {
+			return apperrors.Database(err)
+		}
+		if user == nil || user.Email != stored.Email {
+			return errResetTokenInvalid
+		}
+
+		user.Password = string(hashedPassword)
+		if err := s.users.Update(ctx, user); err != nil {
+			return err
+		}
+		// Which also verifies the address, and voids the other reset tokens
+		if _, err := s.users.MarkEmailVerified(ctx, user.ID, user.Email, now); err != nil {
+			return apperrors.Database(err)
+		}
+		if _, err := s.repo.MarkAllUsed(ctx, user.ID, models.TokenPurposePasswordReset, now); err != nil {
+			return apperrors.Database(err)
+		}
+		return nil
+	})
+	if err != nil {
+		if _, ok := apperrors.AsAppError(err); ok {
+			return err
+		}
+		return apperrors.Database(err)
+	}
+
+	log := logger.Ctx(ctx).With(zap.Uint("user_id", user.ID))
+	if err := s.tokens.RevokeAllUserTokens(user.ID); err != nil {
+		log.Error("Failed to sign out after password reset", zap.Error(err))
+	}
+	if err := s.userCache.InvalidateUserCache(ctx, user.ID); err != nil {
+		log.Debug("Failed to invalidate user cache", zap.Error(err))
+	}
+	log.Info("Password reset")
+
+	s.notifications.NotifyUser(ctx, user.ID, Notification{
+		Type:  NotificationPasswordChanged,
+		Title: "Your password was changed",
+		Body:  "Your TemanDifa password was reset and every device was signed out. If this wasn't you, contact support right away.",
+	})
+	return nil
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+)
+
+// permissionCacheTTL bounds how long another server's permission changes go
+// unnoticed for tokens issued before them
+const permissionCacheTTL = 30 * time.Second
+
+// PermissionService answers permission checks from the roles' permissions,
+// cached in memory. Access tokens carry the version of their user's role, so
+// a token issued after a change makes every server reload the role.
+type PermissionService interface {
+	// HasPermission reports whether role grants permission. Cached
+	// permissions older than minVersion are reloaded first.
+	HasPermission(ctx context.Context, role string, minVersion uint, permission string) (bool, error)
+	// RoleVersion returns the current version of role, 0 when it doesn't
+	// exist
+	RoleVersion(ctx context.Context, role string) (uint, error)
+	// Invalidate drops the cached permissions of role
+	Invalidate(role string)
+}
+
+// cachedRole is a role's permissions as loaded at loadedAt
+type cachedRole struct {
+	version     uint
+	permissions map[string]bool
+	loadedAt    time.Time
+}
+
+type permissionService struct {
+	repo repositories.RoleRepository
+
+	mu    sync.Mutex
+	roles map[string]*cachedRole
+}
+
+// NewPermissionService creates a new PermissionService
+func NewPermissionService(repo repositories.RoleRepository) PermissionService {
+	return &permissionService{repo: repo, roles: make(map[string]*cachedRole)}
+}
+
+// role returns the role's permissions, loading them when they aren't
+// cached, are older than minVersion or have expired
+func (s *permissionService) role(ctx context.Context, name string, minVersion uint) (*cachedRole, error) {
+	s.mu.Lock()
+	cached, ok := s.roles[name]
+	s.mu.Unlock()
+	if ok && cached.version >= minVersion && time.Since(cached.loadedAt) < permissionCacheTTL {
+		return cached, nil
+	}
+
+	role, err := s.repo.Find(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	// Unknown roles grant nothing
+	cached = &cachedRole{permissions: make(map[string]bool), loadedAt: time.Now()}
+	if role != nil {
+		cached.version = role.Version
+		for _, permission := range role.Permissions {
+			cached.permissions[permission.Permission] = true
+		}
+	}
+
+	s.mu.Lock()
+	s.roles[name] = cached
+	s.mu.Unlock()
+	return cached, nil
+}
+
+func (s *permissionService) HasPermission(ctx context.Context, role string, minVersion uint, permission string) (bool, error) {
+	cached, err := s.role(ctx, role, minVersion)
+	if err != nil {
+		return false, err
+	}
+	return cached.permissions[models.PermissionAll] || cached.permissions[permission], nil
+}
+
+func (s *permissionService) RoleVersion(ctx context.Context, role string) (uint, error) {
+	cached, err := s.role(ctx, role, 0)
+	if err != nil {
+		return 0, err
+	}
+	return cached.version, nil
+}
+
+func (s *permissionService) Invalidate(role string) {
+	s.mu.Lock()
+	delete(s.roles, role)
+	s.mu.Unlock()
+}
@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+)
+
+// Audit actions and target type of plan management
+const (
+	auditTargetPlan       = "plan"
+	auditPlanUpdated      = "plan.updated"
+	auditUserPlanAssigned = "user.plan_assigned"
+)
+
+const (
+	// quotaKeyPrefix prefixes the Redis counters of AI requests per user and
+	// UTC day
+	quotaKeyPrefix = "quota:ai:"
+	// maxPlanMultiplier bounds plan multipliers like rate limit overrides
+	maxPlanMultiplier = 100
+)
+
+type planContextKey struct{}
+
+// ContextWithPlan returns a copy of ctx carrying the authenticated user's
+// plan, which scales the TTLs of the AI results cached for the request
+func ContextWithPlan(ctx context.Context, plan string) context.Context {
+	return context.WithValue(ctx, planContextKey{}, plan)
+}
+
+// PlanService manages the subscription plans and enforces their daily AI
+// quotas. Quotas are counted in Redis and not enforced without it. Other
+// instances pick up plan changes within FLAGS_REFRESH_INTERVAL.
+type PlanService interface {
+	List(ctx context.Context) ([]models.Plan, error)
+	// Plan returns the named plan; users without a known plan get the free plan
+	Plan(name string) models.Plan
+	// Update returns nil when the plan doesn't exist
+	Update(ctx context.Context, plan *models.Plan) (*models.Plan, error)
+	// AssignPlan returns nil when the user doesn't exist
+	AssignPlan(ctx context.Context, userID uint, plan string) (*models.User, error)
+	// ConsumeQuota counts one AI request against the user's daily quota and
+	// reports false, without counting it, when the quota is used up
+	ConsumeQuota(ctx context.Context, user *models.User) (*dto.PlanAllowance, bool, error)
+	Allowance(ctx context.Context, user *models.User) (*dto.PlanAllowance, error)
+	// RateLimit scales the per-user AI rate limit for the plan
+	RateLimit(plan string, limit int) int
+	// CacheTTL scales an AI result cache TTL for the plan carried by ctx
+	CacheTTL(ctx context.Context, ttl time.Duration) time.Duration
+	Refresh(ctx context.Context) error
+}
+
+type planService struct {
+	repo      repositories.PlanRepository
+	userRepo  repositories.UserRepository
+	userCache UserCacheService
+	audit     AuditService
+	tx        database.TxManager
+	rdb       *redis.Client
+	log       *zap.Logger
+	plans     atomic.Pointer[map[string]models.Plan]
+}
+
+// NewPlanService creates a new PlanService; plans are loaded by
+// RegisterPlanRefresh
+func NewPlanService(repo repositories.PlanRepository, userRepo repositories.UserRepository, userCache UserCacheService, audit AuditService, tx database.TxManager, rdb *redis.Client, log *zap.Logger) PlanService {
+	s := &planService{
+		repo:      repo,
+		userRepo:  userRepo,
+		userCache: userCache,
+		audit:     audit,
+		tx:        tx,
+		rdb:       rdb,
+		log:       log,
+	}
+	s.plans.Store(&map[string]models.Plan{})
+	return s
+}
+
+func (s *planService) List(ctx context.Context) ([]models.Plan, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *planService) Plan(name string) models.Plan {
+	plans := *s.plans.Load()
+	if plan, ok := plans[name]; ok {
+		return plan
+	}
+	if plan, ok := plans[models.PlanFree]; ok {
+		return plan
+	}
+	// Not loaded yet: no quota and unscaled limits
+	return models.Plan{Name: models.PlanFree, RateLimitMultiplier: 1, CacheTTLMultiplier: 1}
+}
+
+func (s *planService) Update(ctx context.Context, plan *models.Plan) (*models.Plan, error) {
+	if plan.RateLimitMultiplier > maxPlanMultiplier || plan.CacheTTLMultiplier > maxPlanMultiplier {
+		return nil, apperrors.ValidationWithDetails("Invalid multiplier", map[string]string{"multiplier": "must be at most 100"})
+	}
+
+	var updated *models.Plan
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.repo.Find(ctx, plan.Name)
+		if err != nil || before == nil {
+			return err
+		}
+		if _, err := s.repo.Update(ctx, plan); err != nil {
+			return err
+		}
+		if updated, err = s.repo.Find(ctx, plan.Name); err != nil {
+			return err
+		}
+		return s.audit.RecordChange(ctx, auditPlanUpdated, auditTargetPlan, plan.Name, before, updated)
+	})
+	if err != nil || updated == nil {
+		return nil, err
+	}
+	return updated, s.Refresh(ctx)
+}
+
+func (s *planService) AssignPlan(ctx context.Context, userID uint, planName string) (*models.User, error) {
+	var user *models.User
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		plan, err := s.repo.Find(ctx, planName)
+		if err != nil {
+			return err
+		}
+		if plan == nil {
+			return apperrors.ValidationWithDetails("Unknown plan", map[string]string{"plan": planName + " does not exist"})
+		}
+
+		user, err = s.userRepo.FindByID(ctx, userID)
+		if err != nil || user == nil {
+			return err
+		}
+		previous := user.Plan
+		if previous == planName {
+			return nil
+		}
+		user.Plan = planName
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		return s.audit.RecordChange(ctx, auditUserPlanAssigned, auditTargetUser, strconv.FormatUint(uint64(userID), 10),
+			map[string]string{"plan": previous}, map[string]string{"plan": planName})
+	})
+	if err != nil || user == nil {
+		return nil, err
+	}
+	if err := s.userCache.InvalidateUserCache(ctx, userID); err != nil {
+		s.log.Warn("Failed to invalidate cached user", zap.Uint("user_id", userID), zap.Error(err))
+	}
+	return user, nil
+}
+
+// quotaDay returns the current UTC day and when it ends
+func quotaDay() (string, time.Time) {
+	now := time.Now().UTC()
+	return now.Format(dayLayout), now.Truncate(24 * time.Hour).Add(24 * time.Hour)
+}
+
+func quotaKey(userID uint, day string) string {
+	return fmt.Sprintf("%s%d:%s", quotaKeyPrefix, userID, day)
+}
+
+// allowance reports plan's quota with used requests counted
+func allowance(plan models.Plan, used int64, resetsAt time.Time) *dto.PlanAllowance {
+	result := &dto.PlanAllowance{
+		Plan:         plan.Name,
+		DailyAIQuota: plan.DailyAIQuota,
+		Used:         used,
+		ResetsAt:     resetsAt,
+	}
+	if !plan.Unlimited() {
+		remaining := max(int64(plan.DailyAIQuota)-used, 0)
+		result.Remaining = &remaining
+	}
+	return result
+}
+
+func (s *planService) ConsumeQuota(ctx context.Context, user *models.User) (*dto.PlanAllowance, bool, error) {
+	plan := s.Plan(user.Plan)
+	day, resetsAt := quotaDay()
+	if s.rdb == nil {
+		return allowance(plan, 0, resetsAt), true, nil
+	}
+
+	key := quotaKey(user.ID, day)
+	pipe := s.rdb.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.ExpireAt(ctx, key, resetsAt.Add(time.Hour))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, false, err
+	}
+
+	used := incr.Val()
+	if !plan.Unlimited() && used > int64(plan.DailyAIQuota) {
+		// Rejected requests don't count
+		if err := s.rdb.Decr(ctx, key).Err(); err != nil {
+			s.log.Warn("Failed to uncount rejected AI request", zap.Uint("user_id", user.ID), zap.Error(err))
+		}
+		return allowance(plan, used-1, resetsAt), false, nil
+	}
+	return allowance(plan, used, resetsAt), true, nil
+}
+
+func (s *planService) Allowance(ctx context.Context, user *models.User) (*dto.PlanAllowance, error) {
+	plan := s.Plan(user.Plan)
+	day, resetsAt := quotaDay()
+	if s.rdb == nil {
+		return allowance(plan, 0, resetsAt), nil
+	}
+
+	used, err := s.rdb.Get(ctx, quotaKey(user.ID, day)).Int64()
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+	return allowance(plan, used, resetsAt), nil
+}
+
+func (s *planService) RateLimit(plan string, limit int) int {
+	return int(math.Ceil(float64(limit) * s.Plan(plan).RateLimitMultiplier))
+}
+
+func (s *planService) CacheTTL(ctx context.Context, ttl time.Duration) time.Duration {
+	name, ok := ctx.Value(planContextKey{}).(string)
+	if !ok {
+		return ttl
+	}
+	return time.Duration(float64(ttl) * s.Plan(name).CacheTTLMultiplier)
+}
+
+func (s *planService) Refresh(ctx context.Context) error {
+	list, err := s.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	plans := make(map[string]models.Plan, len(list))
+	for _, plan := range list {
+		plans[plan.Name] = plan
+	}
+	s.plans.Store(&plans)
+	return nil
+}
+
+// RegisterPlanRefresh loads the plans on startup and reloads them with the
+// feature flags, every FLAGS_REFRESH_INTERVAL
+func RegisterPlanRefresh(lc fx.Lifecycle, plans PlanService, cfg *config.Config, log *zap.Logger) {
+	stopChan := make(chan struct{})
+
+	refresh := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := plans.Refresh(ctx); err != nil {
+			log.Warn("Failed to refresh plans", zap.Error(err))
+		}
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			refresh()
+
+			ticker := time.NewTicker(cfg.FeatureFlagsRefreshInterval)
+			go func() {
+				for {
+					select {
+					case <-ticker.C:
+						refresh()
+					case <-stopChan:
+						ticker.Stop()
+						return
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stopChan)
+			return nil
+		},
+	})
+}
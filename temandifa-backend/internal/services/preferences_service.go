@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/dto"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+)
+
+// PreferencesService keeps users' accessibility preferences. AI requests
+// that leave out the OCR language use the user's, and narration follows
+// their verbosity.
+type PreferencesService interface {
+	// Get returns the user's preferences, the defaults if they never
+	// changed any
+	Get(ctx context.Context, userID uint) (*models.UserPreferences, error)
+	// Update changes the preferences set in input
+	Update(ctx context.Context, userID uint, input dto.UpdatePreferencesRequest) (*models.UserPreferences, error)
+	// OCRLanguage returns the user's OCR language, or the default one when
+	// their preferences can't be read
+	OCRLanguage(ctx context.Context, userID uint) string
+}
+
+type preferencesService struct {
+	repo repositories.UserPreferencesRepository
+}
+
+// NewPreferencesService creates a new PreferencesService
+func NewPreferencesService(repo repositories.UserPreferencesRepository) PreferencesService {
+	return &preferencesService{repo: repo}
+}
+
+func (s *preferencesService) Get(ctx context.Context, userID uint) (*models.UserPreferences, error) {
+	preferences, err := s.repo.Find(ctx, userID)
+	if err != nil || preferences != nil {
+		return preferences, err
+	}
+	return models.DefaultUserPreferences(userID), nil
+}
+
+func (s *preferencesService) Update(ctx context.Context, userID uint, input dto.UpdatePreferencesRequest) (*models.UserPreferences, error) {
+	preferences, err := s.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if input.TTSVoice != nil {
+		preferences.TTSVoice = *input.TTSVoice
+	}
+	if input.TTSSpeed != nil {
+		preferences.TTSSpeed = *input.TTSSpeed
+	}
+	if input.OCRLanguage != nil {
+		preferences.OCRLanguage = *input.OCRLanguage
+	}
+	if input.Verbosity != nil {
+		preferences.Verbosity = *input.Verbosity
+	}
+	if input.Haptics != nil {
+		preferences.Haptics = *input.Haptics
+	}
+	if err := s.repo.Save(ctx, preferences); err != nil {
+		return nil, err
+	}
+	return preferences, nil
+}
+
+func (s *preferencesService) OCRLanguage(ctx context.Context, userID uint) string {
+	preferences, err := s.Get(ctx, userID)
+	if err != nil {
+		logger.Ctx(ctx).Warn("Failed to load preferences, using the default OCR language", zap.Error(err))
+		return models.DefaultUserPreferences(userID).OCRLanguage
+	}
+	return preferences.OCRLanguage
+}
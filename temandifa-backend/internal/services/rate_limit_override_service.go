@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/database"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+)
+
+// maxRateLimitMultiplier bounds overrides so a typo can't disable the limit;
+// use Exempt for that
+const maxRateLimitMultiplier = 100
+
+// Audit actions of rate limit override management; the target is the user
+const (
+	auditRateLimitOverrideSet     = "rate_limit_override.set"
+	auditRateLimitOverrideDeleted = "rate_limit_override.deleted"
+)
+
+// RateLimitOverrideService manages per-user adjustments of the per-user (AI)
+// rate limit, e.g. for testers or institutional accounts
+type RateLimitOverrideService interface {
+	// Adjust applies the user's active override to limit; exempt users
+	// aren't rate limited at all
+	Adjust(userID uint, limit int) (adjusted int, exempt bool)
+	List(ctx context.Context) ([]models.RateLimitOverride, error)
+	// Set and Delete report false when the user (or override) doesn't exist
+	Set(ctx context.Context, override *models.RateLimitOverride) (bool, error)
+	Delete(ctx context.Context, userID uint) (bool, error)
+	// Refresh reloads the overrides; other instances pick up changes within
+	// RATE_LIMIT_OVERRIDES_REFRESH_INTERVAL
+	Refresh(ctx context.Context) error
+}
+
+type rateLimitOverrideService struct {
+	repo      repositories.RateLimitOverrideRepository
+	userRepo  repositories.UserRepository
+	audit     AuditService
+	tx        database.TxManager
+	overrides atomic.Pointer[map[uint]models.RateLimitOverride]
+}
+
+// NewRateLimitOverrideService creates a new RateLimitOverrideService;
+// overrides are loaded by RegisterRateLimitOverrideRefresh
+func NewRateLimitOverrideService(repo repositories.RateLimitOverrideRepository, userRepo repositories.UserRepository, audit AuditService, tx database.TxManager) RateLimitOverrideService {
+	s := &rateLimitOverrideService{
+		repo:     repo,
+		userRepo: userRepo,
+		audit:    audit,
+		tx:       tx,
+	}
+	s.overrides.Store(&map[uint]models.RateLimitOverride{})
+	return s
+}
+
+func (s *rateLimitOverrideService) Adjust(userID uint, limit int) (int, bool) {
+	override, ok := (*s.overrides.Load())[userID]
+	if !ok || !override.Active(time.Now()) {
+		return limit, false
+	}
+	if override.Exempt {
+		return limit, true
+	}
+	return int(math.Ceil(float64(limit) * override.Multiplier)), false
+}
+
+func (s *rateLimitOverrideService) List(ctx context.Context) ([]models.RateLimitOverride, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *rateLimitOverrideService) Set(ctx context.Context, override *models.RateLimitOverride) (bool, error) {
+	if override.Multiplier <= 0 || override.Multiplier > maxRateLimitMultiplier {
+		return false, apperrors.ValidationWithDetails("Invalid multiplier", map[string]string{"multiplier": "must be greater than 0 and at most 100"})
+	}
+	if override.ExpiresAt != nil && !override.ExpiresAt.After(time.Now()) {
+		return false, apperrors.ValidationWithDetails("Invalid expiry", map[string]string{"expires_at": "must be in the future"})
+	}
+
+	var found bool
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err := s.userRepo.FindByID(ctx, override.UserID)
+		if err != nil || user == nil {
+			return err
+		}
+		found = true
+		before, err := s.repo.Find(ctx, override.UserID)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.Save(ctx, override); err != nil {
+			return err
+		}
+		after, err := s.repo.Find(ctx, override.UserID)
+		if err != nil {
+			return err
+		}
+		return s.audit.RecordChange(ctx, auditRateLimitOverrideSet, auditTargetUser, strconv.FormatUint(uint64(override.UserID), 10), before, after)
+	})
+	if err != nil || !found {
+		return false, err
+	}
+	return true, s.Refresh(ctx)
+}
+
+func (s *rateLimitOverrideService) Delete(ctx context.Context, userID uint) (bool, error) {
+	var deleted bool
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.repo.Find(ctx, userID)
+		if err != nil || before == nil {
+			return err
+		}
+		if deleted, err = s.repo.Delete(ctx, userID); err != nil || !deleted {
+			return err
+		}
+		return s.audit.RecordChange(ctx, auditRateLimitOverrideDeleted, auditTargetUser, strconv.FormatUint(uint64(userID), 10), before, nil)
+	})
+	if err != nil || !deleted {
+		return false, err
+	}
+	return true, s.Refresh(ctx)
+}
+
+func (s *rateLimitOverrideService) Refresh(ctx context.Context) error {
+	list, err := s.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	overrides := make(map[uint]models.RateLimitOverride, len(list))
+	for _, override := range list {
+		overrides[override.UserID] = override
+	}
+	s.overrides.Store(&overrides)
+	return nil
+}
+
+// RegisterRateLimitOverrideRefresh loads the overrides on startup and reloads
+// them every RATE_LIMIT_OVERRIDES_REFRESH_INTERVAL
+func RegisterRateLimitOverrideRefresh(lc fx.Lifecycle, overrides RateLimitOverrideService, cfg *config.Config, log *zap.Logger) {
+	stopChan := make(chan struct{})
+
+	refresh := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := overrides.Refresh(ctx); err != nil {
+			log.Warn("Failed to refresh rate limit overrides", zap.Error(err))
+		}
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			refresh()
+
+			ticker := time.NewTicker(cfg.RateLimitOverridesRefreshInterval)
+			go func() {
+				for {
+					select {
+					case <-ticker.C:
+						refresh()
+					case <-stopChan:
+						ticker.Stop()
+						return
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stopChan)
+			return nil
+		},
+	})
+}
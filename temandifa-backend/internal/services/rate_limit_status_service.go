@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/dto"
+	"temandifa-backend/internal/models"
+)
+
+// IPRateLimitKey names the sorted set of the general (per IP) sliding window
+// rate limiter
+func IPRateLimitKey(ip string) string {
+	return "sliding_rate:" + ip
+}
+
+// UserRateLimitKey names the sorted set of the per-user sliding window rate
+// limiter
+func UserRateLimitKey(userID uint) string {
+	return fmt.Sprintf("sliding_rate:user:%d", userID)
+}
+
+// RateLimitStatusService reports where users stand with the rate limiters
+// and their plan's quota, so the app can pace expensive requests instead of
+// running into 429s. It only reads the counters the middlewares keep.
+type RateLimitStatusService interface {
+	// Status reports the limits of user calling from ip
+	Status(ctx context.Context, user *models.User, ip string) (*dto.RateLimits, error)
+}
+
+type rateLimitStatusService struct {
+	rdb       *redis.Client
+	dynamic   *config.Dynamic
+	plans     PlanService
+	overrides RateLimitOverrideService
+}
+
+func NewRateLimitStatusService(rdb *redis.Client, dynamic *config.Dynamic, plans PlanService, overrides RateLimitOverrideService) RateLimitStatusService {
+	return &rateLimitStatusService{
+		rdb:       rdb,
+		dynamic:   dynamic,
+		plans:     plans,
+		overrides: overrides,
+	}
+}
+
+func (s *rateLimitStatusService) Status(ctx context.Context, user *models.User, ip string) (*dto.RateLimits, error) {
+	if s.rdb == nil {
+		return &dto.RateLimits{Limits: []dto.LimitStatus{}}, nil
+	}
+	now := time.Now()
+
+	limit, window := s.dynamic.RateLimit()
+	general, err := s.slidingWindow(ctx, IPRateLimitKey(ip), limit, window, now)
+	if err != nil {
+		return nil, err
+	}
+	general.Name, general.Scope = dto.LimitGeneral, "ip"
+
+	// Scaled like SlidingWindowRateLimiterByUser does
+	limit, window = s.dynamic.AIRateLimit()
+	limit, exempt := s.overrides.Adjust(user.ID, s.plans.RateLimit(user.Plan, limit))
+	ai := &dto.LimitStatus{WindowSeconds: int(window.Seconds()), Exempt: true}
+	if !exempt {
+		if ai, err = s.slidingWindow(ctx, UserRateLimitKey(user.ID), limit, window, now); err != nil {
+			return nil, err
+		}
+	}
+	ai.Name, ai.Scope = dto.LimitAI, "user"
+
+	allowance, err := s.plans.Allowance(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	quota := dto.LimitStatus{
+		Name:          dto.LimitAIQuota,
+		Scope:         "user",
+		Limit:         allowance.DailyAIQuota,
+		WindowSeconds: int((24 * time.Hour).Seconds()),
+		Used:          allowance.Used,
+		Remaining:     allowance.Remaining,
+		ResetsAt:      &allowance.ResetsAt,
+	}
+	if quota.Remaining != nil && *quota.Remaining == 0 {
+		quota.RetryAt = &allowance.ResetsAt
+	}
+
+	return &dto.RateLimits{
+		Enforced: true,
+		Limits:   []dto.LimitStatus{*general, *ai, quota},
+	}, nil
+}
+
+// slidingWindow counts the requests of a sliding window rate limiter's
+// sorted set, whose scores are request times in Unix nanoseconds. Entries the
+// limiter hasn't pruned yet are left out.
+func (s *rateLimitStatusService) slidingWindow(ctx context.Context, key string, limit int, window time.Duration, now time.Time) (*dto.LimitStatus, error) {
+	windowStart := "(" + strconv.FormatInt(now.Add(-window).UnixNano(), 10)
+
+	pipe := s.rdb.Pipeline()
+	countCmd := pipe.ZCount(ctx, key, windowStart, "+inf")
+	newestCmd := pipe.ZRevRangeWithScores(ctx, key, 0, 0)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	count := countCmd.Val()
+	remaining := max(int64(limit)-count, 0)
+	status := &dto.LimitStatus{
+		Limit:         limit,
+		WindowSeconds: int(window.Seconds()),
+		Used:          count,
+		Remaining:     &remaining,
+	}
+	if count == 0 {
+		return status, nil
+	}
+
+	expiry := func(z []redis.Z) *time.Time {
+		at := time.Unix(0, int64(z[0].Score)).Add(window)
+		return &at
+	}
+	if newest := newestCmd.Val(); len(newest) > 0 {
+		status.ResetsAt = expiry(newest)
+	}
+	if remaining == 0 && limit > 0 {
+		// Rejected requests are counted too; a request is allowed again once
+		// all but limit-1 of the counted ones have left the window
+		blocking, err := s.rdb.ZRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{
+			Min:    windowStart,
+			Max:    "+inf",
+			Offset: count - int64(limit),
+			Count:  1,
+		}).Result()
+		if err != nil {
+			return nil, err
+		}
+		if len(blocking) > 0 {
+			status.RetryAt = expiry(blocking)
+		}
+	}
+	return status, nil
+}
@@ -0,0 +1,350 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/dto"
+	"temandifa-backend/internal/logger"
+)
+
+const (
+	captureRuleKey    = "capture:rule"
+	captureRecordsKey = "capture:records"
+
+	// captureRuleCacheTTL controls how often instances re-read the rule from Redis
+	captureRuleCacheTTL = 5 * time.Second
+	// captureMaxRecords bounds the number of stored exchanges
+	captureMaxRecords = 200
+	// captureRecordTTL is how long captured exchanges are kept after the last capture
+	captureRecordTTL = 24 * time.Hour
+
+	// DefaultCaptureDuration applies when a rule doesn't specify one
+	DefaultCaptureDuration = 30 * time.Minute
+
+	redactedValue = "[REDACTED]"
+)
+
+// sensitiveFields are JSON/form fields and headers whose values are never stored
+var sensitiveFields = map[string]bool{
+	"password":         true,
+	"new_password":     true,
+	"current_password": true,
+	"token":            true,
+	"access_token":     true,
+	"refresh_token":    true,
+	"id_token":         true,
+	"code":             true, // OAuth authorization codes
+	"state":            true,
+	"signature":        true, // signed media URLs
+	"secret":           true,
+	"api_key":          true,
+	"authorization":    true,
+	"cookie":           true,
+	"set-cookie":       true,
+	"x-api-key":        true,
+}
+
+// RequestCaptureService records full request/response pairs for a targeted
+// user or request ID pattern so hard-to-reproduce mobile bugs can be inspected
+type RequestCaptureService interface {
+	SetRule(ctx context.Context, rule dto.CaptureRule) error
+	GetRule(ctx context.Context) (*dto.CaptureRule, error)
+	ClearRule(ctx context.Context) error
+	// Active reports cheaply (from a short-lived local cache) whether any rule is enabled
+	Active(ctx context.Context) bool
+	// ShouldCapture applies the rule's target and sample rate to a finished request
+	ShouldCapture(ctx context.Context, userID uint, requestID string) bool
+	Store(ctx context.Context, exchange dto.CapturedExchange)
+	ListRecords(ctx context.Context, limit int) ([]dto.CapturedExchange, error)
+	ClearRecords(ctx context.Context) error
+}
+
+type requestCaptureService struct {
+	client *redis.Client
+
+	mu       sync.Mutex
+	rule     *dto.CaptureRule
+	pattern  *regexp.Regexp
+	loadedAt time.Time
+}
+
+// NewRequestCaptureService creates a Redis-backed request capture service
+func NewRequestCaptureService(client *redis.Client) RequestCaptureService {
+	return &requestCaptureService{client: client}
+}
+
+func (s *requestCaptureService) SetRule(ctx context.Context, rule dto.CaptureRule) error {
+	if s.client == nil {
+		return fmt.Errorf("redis not available")
+	}
+	if rule.UserID == 0 && rule.RequestIDPattern == "" {
+		return fmt.Errorf("user_id or request_id_pattern is required")
+	}
+	if _, err := regexp.Compile(rule.RequestIDPattern); err != nil {
+		return fmt.Errorf("invalid request_id_pattern: %w", err)
+	}
+
+	data, err := json.Marshal(rule)
+	if err != nil {
+		return err
+	}
+	if err := s.client.Set(ctx, captureRuleKey, data, time.Until(rule.ExpiresAt)).Err(); err != nil {
+		return err
+	}
+
+	s.invalidate()
+	logger.Ctx(ctx).Info("Request capture enabled",
+		zap.Uint("target_user_id", rule.UserID),
+		zap.String("request_id_pattern", rule.RequestIDPattern),
+		zap.Float64("sample_rate", rule.SampleRate),
+		zap.Time("expires_at", rule.ExpiresAt),
+		zap.Uint("created_by", rule.CreatedBy),
+	)
+	return nil
+}
+
+func (s *requestCaptureService) GetRule(ctx context.Context) (*dto.CaptureRule, error) {
+	if s.client == nil {
+		return nil, nil
+	}
+
+	data, err := s.client.Get(ctx, captureRuleKey).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rule dto.CaptureRule
+	if err := json.Unmarshal(data, &rule); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (s *requestCaptureService) ClearRule(ctx context.Context) error {
+	if s.client == nil {
+		return nil
+	}
+	if err := s.client.Del(ctx, captureRuleKey).Err(); err != nil {
+		return err
+	}
+	s.invalidate()
+	logger.Ctx(ctx).Info("Request capture disabled")
+	return nil
+}
+
+func (s *requestCaptureService) Active(ctx context.Context) bool {
+	rule, _ := s.cachedRule(ctx)
+	return rule != nil
+}
+
+func (s *requestCaptureService) ShouldCapture(ctx context.Context, userID uint, requestID string) bool {
+	rule, pattern := s.cachedRule(ctx)
+	if rule == nil {
+		return false
+	}
+
+	matched := false
+	if rule.UserID != 0 && rule.UserID == userID {
+		matched = true
+	}
+	if pattern != nil && pattern.MatchString(requestID) {
+		matched = true
+	}
+	if !matched {
+		return false
+	}
+
+	return rule.SampleRate >= 1 || rand.Float64() < rule.SampleRate
+}
+
+func (s *requestCaptureService) Store(ctx context.Context, exchange dto.CapturedExchange) {
+	if s.client == nil {
+		return
+	}
+
+	data, err := json.Marshal(exchange)
+	if err != nil {
+		logger.Ctx(ctx).Warn("Failed to marshal captured exchange", zap.Error(err))
+		return
+	}
+
+	pipe := s.client.TxPipeline()
+	pipe.LPush(ctx, captureRecordsKey, data)
+	pipe.LTrim(ctx, captureRecordsKey, 0, captureMaxRecords-1)
+	pipe.Expire(ctx, captureRecordsKey, captureRecordTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Ctx(ctx).Warn("Failed to store captured exchange", zap.Error(err))
+	}
+}
+
+func (s *requestCaptureService) ListRecords(ctx context.Context, limit int) ([]dto.CapturedExchange, error) {
+	if s.client == nil {
+		return []dto.CapturedExchange{}, nil
+	}
+
+	items, err := s.client.LRange(ctx, captureRecordsKey, 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]dto.CapturedExchange, 0, len(items))
+	for _, item := range items {
+		var exchange dto.CapturedExchange
+		if err := json.Unmarshal([]byte(item), &exchange); err == nil {
+			records = append(records, exchange)
+		}
+	}
+	return records, nil
+}
+
+func (s *requestCaptureService) ClearRecords(ctx context.Context) error {
+	if s.client == nil {
+		return nil
+	}
+	return s.client.Del(ctx, captureRecordsKey).Err()
+}
+
+// cachedRule returns the active rule, re-reading Redis at most every captureRuleCacheTTL
+func (s *requestCaptureService) cachedRule(ctx context.Context) (*dto.CaptureRule, *regexp.Regexp) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if time.Since(s.loadedAt) > captureRuleCacheTTL {
+		s.loadedAt = time.Now()
+		s.rule, s.pattern = nil, nil
+
+		rule, err := s.GetRule(ctx)
+		if err != nil {
+			logger.Ctx(ctx).Debug("Failed to load capture rule", zap.Error(err))
+		}
+		if rule != nil {
+			s.rule = rule
+			if rule.RequestIDPattern != "" {
+				s.pattern, _ = regexp.Compile(rule.RequestIDPattern)
+			}
+		}
+	}
+
+	if s.rule != nil && time.Now().After(s.rule.ExpiresAt) {
+		return nil, nil
+	}
+	return s.rule, s.pattern
+}
+
+// invalidate forces the next check to re-read the rule
+func (s *requestCaptureService) invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loadedAt = time.Time{}
+}
+
+// ---- Redaction ----
+
+// RedactHeaders flattens headers, replacing credentials with a placeholder
+func RedactHeaders(header http.Header) map[string]string {
+	result := make(map[string]string, len(header))
+	for name, values := range header {
+		if sensitiveFields[strings.ToLower(name)] {
+			result[name] = redactedValue
+			continue
+		}
+		result[name] = strings.Join(values, ", ")
+	}
+	return result
+}
+
+// RedactBody renders a captured body for storage. JSON and form bodies have
+// sensitive fields redacted; binary payloads (images, audio) are summarized.
+func RedactBody(contentType string, body []byte, truncated bool) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	suffix := ""
+	if truncated {
+		suffix = " [truncated]"
+	}
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		var payload any
+		if err := json.Unmarshal(body, &payload); err != nil {
+			// Truncated or malformed JSON can't be safely redacted
+			return fmt.Sprintf("[%d bytes of unparseable JSON omitted]", len(body))
+		}
+		redacted, _ := json.Marshal(redactValue(payload))
+		return string(redacted) + suffix
+
+	case mediaType == "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return fmt.Sprintf("[%d bytes of unparseable form data omitted]", len(body))
+		}
+		return redactValues(values) + suffix
+
+	case strings.HasPrefix(mediaType, "text/"):
+		return string(body) + suffix
+
+	default:
+		return fmt.Sprintf("[%d bytes of %s omitted]", len(body), mediaType)
+	}
+}
+
+// RedactQuery renders a captured query string with sensitive parameters
+// (tokens of emailed links, OAuth codes) redacted
+func RedactQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return fmt.Sprintf("[%d bytes of unparseable query omitted]", len(rawQuery))
+	}
+	return redactValues(values)
+}
+
+// redactValues encodes form or query values with sensitive fields replaced
+func redactValues(values url.Values) string {
+	for key := range values {
+		if sensitiveFields[strings.ToLower(key)] {
+			values.Set(key, redactedValue)
+		}
+	}
+	return values.Encode()
+}
+
+// redactValue walks decoded JSON and replaces sensitive fields
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, inner := range v {
+			if sensitiveFields[strings.ToLower(key)] {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactValue(inner)
+			}
+		}
+	case []any:
+		for i, inner := range v {
+			v[i] = redactValue(inner)
+		}
+	}
+	return value
+}
@@ -0,0 +1,38 @@
+package services
+
+import "testing"
+
+func TestRedactQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{name: "empty", query: "", want: ""},
+		{name: "nothing sensitive", query: "page=2&q=buku", want: "page=2&q=buku"},
+		{
+			name:  "OAuth callback",
+			query: "code=4%2F0Ab&state=xyz&scope=email",
+			want:  "code=%5BREDACTED%5D&scope=email&state=%5BREDACTED%5D",
+		},
+		{
+			name:  "signed media URL",
+			query: "expires=1760000000&signature=abc123",
+			want:  "expires=1760000000&signature=%5BREDACTED%5D",
+		},
+		{
+			name:  "tokens in any case and repeated",
+			query: "Token=a&token=b&refresh_token=c",
+			want:  "Token=%5BREDACTED%5D&refresh_token=%5BREDACTED%5D&token=%5BREDACTED%5D",
+		},
+		{name: "unparseable", query: "a=%zz", want: "[5 bytes of unparseable query omitted]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RedactQuery(tt.query); got != tt.want {
+				t.Errorf("RedactQuery(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/database"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+)
+
+// roleNamePattern restricts role names to short lower-case identifiers
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,31}$`)
+
+// Audit actions and target type of role management
+const (
+	auditTargetRole        = "role"
+	auditTargetUser        = "user"
+	auditRoleCreated       = "role.created"
+	auditRoleUpdated       = "role.updated"
+	auditRoleDeleted       = "role.deleted"
+	auditPermissionsAdded  = "role.permissions_added"
+	auditPermissionRemoved = "role.permission_removed"
+	auditUserRoleAssigned  = "user.role_assigned"
+)
+
+// RoleService manages roles, their permissions and role assignment. Every
+// change is audited in the same transaction, and users whose effective
+// permissions change are dropped from the user cache afterwards. Permission
+// changes bump the role's version, which new access tokens carry.
+type RoleService interface {
+	ListRoles(ctx context.Context) ([]models.Role, error)
+	CreateRole(ctx context.Context, role *models.Role) error
+	// UpdateRole, AddPermissions and RemovePermission return nil (or false)
+	// when the role doesn't exist
+	UpdateRole(ctx context.Context, name, description string) (*models.Role, error)
+	AddPermissions(ctx context.Context, name string, permissions []string) (*models.Role, error)
+	RemovePermission(ctx context.Context, name, permission string) (bool, error)
+	// DeleteRole refuses built-in roles and roles still assigned to users
+	DeleteRole(ctx context.Context, name string) (bool, error)
+	// AssignRole returns nil when the user doesn't exist
+	AssignRole(ctx context.Context, userID uint, role string) (*models.User, error)
+}
+
+type roleService struct {
+	repo        repositories.RoleRepository
+	userRepo    repositories.UserRepository
+	userCache   UserCacheService
+	permissions PermissionService
+	audit       AuditService
+	tx          database.TxManager
+	log         *zap.Logger
+}
+
+// NewRoleService creates a new RoleService
+func NewRoleService(repo repositories.RoleRepository, userRepo repositories.UserRepository, userCache UserCacheService, permissions PermissionService, audit AuditService, tx database.TxManager, log *zap.Logger) RoleService {
+	return &roleService{
+		repo:        repo,
+		userRepo:    userRepo,
+		userCache:   userCache,
+		permissions: permissions,
+		audit:       audit,
+		tx:          tx,
+		log:         log,
+	}
+}
+
+// errRoleConflict reports a change that would break role invariants
+func errRoleConflict(message string) error {
+	return apperrors.NewAppError(apperrors.ErrCodeConflict, message, http.StatusConflict)
+}
+
+// validatePermissions rejects permissions missing from the catalog
+func validatePermissions(permissions []string) error {
+	for _, permission := range permissions {
+		if _, ok := models.PermissionCatalog[permission]; !ok {
+			return apperrors.ValidationWithDetails("Unknown permission", map[string]string{"permissions": permission + " is not a known permission"})
+		}
+	}
+	return nil
+}
+
+func (s *roleService) ListRoles(ctx context.Context) ([]models.Role, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *roleService) CreateRole(ctx context.Context, role *models.Role) error {
+	if !roleNamePattern.MatchString(role.Name) {
+		return apperrors.ValidationWithDetails("Invalid role name", map[string]string{"name": "must be 2-32 lower-case letters, digits, - or _, starting with a letter"})
+	}
+	permissions := make([]string, len(role.Permissions))
+	for i, permission := range role.Permissions {
+		permissions[i] = permission.Permission
+	}
+	if err := validatePermissions(permissions); err != nil {
+		return err
+	}
+
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		existing, err := s.repo.Find(ctx, role.Name)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return apperrors.AlreadyExists("Role")
+		}
+		role.System = false
+		if err := s.repo.Create(ctx, role); err != nil {
+			return err
+		}
+		return s.audit.RecordChange(ctx, auditRoleCreated, auditTargetRole, role.Name, nil, role)
+	})
+	if err == nil {
+		// Drops a deleted role of the same name, cached as granting nothing
+		s.permissions.Invalidate(role.Name)
+	}
+	return err
+}
+
+func (s *roleService) UpdateRole(ctx context.Context, name, description string) (*models.Role, error) {
+	var role *models.Role
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.repo.Find(ctx, name)
+		if err != nil || before == nil {
+			return err
+		}
+		if _, err := s.repo.UpdateDescription(ctx, name, description); err != nil {
+			return err
+		}
+		if role, err = s.repo.Find(ctx, name); err != nil {
+			return err
+		}
+		return s.audit.RecordChange(ctx, auditRoleUpdated, auditTargetRole, name, before, role)
+	})
+	return role, err
+}
+
+func (s *roleService) AddPermissions(ctx context.Context, name string, permissions []string) (*models.Role, error) {
+	if err := validatePermissions(permissions); err != nil {
+		return nil, err
+	}
+
+	var role *models.Role
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.repo.Find(ctx, name)
+		if err != nil || before == nil {
+			return err
+		}
+		if err := s.repo.AddPermissions(ctx, name, permissions); err != nil {
+			return err
+		}
+		if err := s.repo.BumpVersion(ctx, name); err != nil {
+			return err
+		}
+		if role, err = s.repo.Find(ctx, name); err != nil {
+			return err
+		}
+		return s.audit.RecordChange(ctx, auditPermissionsAdded, auditTargetRole, name, before, role)
+	})
+	if err != nil || role == nil {
+		return nil, err
+	}
+	s.invalidateRoleUsers(ctx, name)
+	return role, nil
+}
+
+func (s *roleService) RemovePermission(ctx context.Context, name, permission string) (bool, error) {
+	// Administrators must not be able to lock everyone out
+	if name == models.RoleAdmin && permission == models.PermissionAll {
+		return false, errRoleConflict("The admin role always has every permission")
+	}
+
+	var removed bool
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.repo.Find(ctx, name)
+		if err != nil || before == nil {
+			return err
+		}
+		removed, err = s.repo.RemovePermission(ctx, name, permission)
+		if err != nil || !removed {
+			return err
+		}
+		if err := s.repo.BumpVersion(ctx, name); err != nil {
+			return err
+		}
+		after, err := s.repo.Find(ctx, name)
+		if err != nil {
+			return err
+		}
+		return s.audit.RecordChange(ctx, auditPermissionRemoved, auditTargetRole, name, before, after)
+	})
+	if err != nil || !removed {
+		return false, err
+	}
+	s.invalidateRoleUsers(ctx, name)
+	return true, nil
+}
+
+func (s *roleService) DeleteRole(ctx context.Context, name string) (bool, error) {
+	var found bool
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		role, err := s.repo.Find(ctx, name)
+		if err != nil || role == nil {
+			return err
+		}
+		found = true
+		if role.System {
+			return errRoleConflict("Built-in roles can't be deleted")
+		}
+		users, err := s.repo.UserIDs(ctx, name)
+		if err != nil {
+			return err
+		}
+		if len(users) > 0 {
+			return apperrors.NewAppError(apperrors.ErrCodeConflict, "Role is still assigned to users", http.StatusConflict).
+				WithDetails(map[string]int{"users": len(users)})
+		}
+		if err := s.repo.Delete(ctx, name); err != nil {
+			return err
+		}
+		return s.audit.RecordChange(ctx, auditRoleDeleted, auditTargetRole, name, role, nil)
+	})
+	if err == nil && found {
+		s.permissions.Invalidate(name)
+	}
+	return found, err
+}
+
+func (s *roleService) AssignRole(ctx context.Context, userID uint, roleName string) (*models.User, error) {
+	// Administrators must not be able to lock themselves out
+	if userID == logger.UserIDFromContext(ctx) {
+		return nil, errRoleConflict("You can't change your own role")
+	}
+
+	var user *models.User
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		role, err := s.repo.Find(ctx, roleName)
+		if err != nil {
+			return err
+		}
+		if role == nil {
+			return apperrors.ValidationWithDetails("Unknown role", map[string]string{"role": roleName + " does not exist"})
+		}
+
+		user, err = s.userRepo.FindByID(ctx, userID)
+		if err != nil || user == nil {
+			return err
+		}
+		previous := user.Role
+		if previous == roleName {
+			return nil
+		}
+		user.Role = roleName
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		return s.audit.RecordChange(ctx, auditUserRoleAssigned, auditTargetUser, strconv.FormatUint(uint64(userID), 10),
+			map[string]string{"role": previous}, map[string]string{"role": roleName})
+	})
+	if err != nil || user == nil {
+		return nil, err
+	}
+	if err := s.userCache.InvalidateUserCache(ctx, userID); err != nil {
+		s.log.Warn("Failed to invalidate cached user", zap.Uint("user_id", userID), zap.Error(err))
+	}
+	return user, nil
+}
+
+// invalidateRoleUsers drops the role's permissions and its users from the
+// caches so their next request loads the changed permissions
+func (s *roleService) invalidateRoleUsers(ctx context.Context, name string) {
+	s.permissions.Invalidate(name)
+	ids, err := s.repo.UserIDs(ctx, name)
+	if err != nil {
+		s.log.Warn("Failed to list users of role for cache invalidation", zap.String("role", name), zap.Error(err))
+		return
+	}
+	for _, id := range ids {
+		if err := s.userCache.InvalidateUserCache(ctx, id); err != nil {
+			s.log.Warn("Failed to invalidate cached user", zap.Uint("user_id", id), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+
+	"temandifa-backend/internal/dto"
+	"temandifa-backend/internal/repositories"
+)
+
+// maxUserSessions caps the sessions listed to a user
+const maxUserSessions = 100
+
+// SessionService lets users see where they are signed in. A session is an
+// active refresh token; rotation replaces it with a new one that keeps the
+// sign-in time.
+type SessionService interface {
+	// ListSessions returns the user's active sessions, the most recently
+	// used first; currentID marks the caller's own
+	ListSessions(ctx context.Context, userID, currentID uint) ([]dto.Session, error)
+	// RevokeSession signs the user out of session id, reporting false when
+	// the user has no such active session
+	RevokeSession(ctx context.Context, userID, id uint) (bool, error)
+}
+
+type sessionService struct {
+	repo   repositories.SessionRepository
+	tokens TokenService
+}
+
+// NewSessionService creates a new SessionService
+func NewSessionService(repo repositories.SessionRepository, tokens TokenService) SessionService {
+	return &sessionService{repo: repo, tokens: tokens}
+}
+
+func (s *sessionService) ListSessions(ctx context.Context, userID, currentID uint) ([]dto.Session, error) {
+	spec := activeSessions(dto.SessionFilter{UserID: userID}).
+		OrderBy("created_at", true).
+		OrderBy("id", true).
+		Paginate(maxUserSessions, 0)
+	tokens, _, err := s.repo.List(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]dto.Session, len(tokens))
+	for i, token := range tokens {
+		signedInAt := token.CreatedAt
+		if token.SignedInAt != nil {
+			signedInAt = *token.SignedInAt
+		}
+		sessions[i] = dto.Session{
+			ID:         token.ID,
+			DeviceID:   token.DeviceID,
+			IPAddress:  token.IPAddress,
+			UserAgent:  token.UserAgent,
+			CreatedAt:  signedInAt,
+			LastUsedAt: token.CreatedAt,
+			ExpiresAt:  token.ExpiresAt,
+			Current:    token.ID == currentID,
+		}
+	}
+	return sessions, nil
+}
+
+func (s *sessionService) RevokeSession(ctx context.Context, userID, id uint) (bool, error) {
+	return s.tokens.RevokeTokenByID(ctx, userID, id)
+}
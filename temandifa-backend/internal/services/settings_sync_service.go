@@ -0,0 +1,277 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+)
+
+// Synced settings limits
+const (
+	maxSyncedSettings    = 200  // live settings per user
+	maxSettingValueBytes = 4096 // compacted JSON
+	maxVectorEntries     = 32   // devices that edited one setting
+)
+
+// settingKeyPattern allows dotted lower-case keys, e.g. tts.speed
+var settingKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,63}$`)
+
+// SettingsSyncService syncs app settings (accessibility preferences and
+// the like) across a user's devices. Every edit carries a version vector:
+// a device increments its own entry of the vector it last synced, so the
+// server can tell a newer edit (applied), an older one (stale) and one made
+// concurrently, e.g. offline on two devices. Concurrent edits are merged
+// deterministically: the edit with the greater vector total wins, ties
+// going to the greater value byte-wise, and the setting keeps the merge of
+// both vectors so the next edit on either device supersedes it.
+type SettingsSyncService interface {
+	// Pull returns the user's settings changed after the cursor since
+	Pull(ctx context.Context, userID uint, since int64) (*dto.SettingsSync, error)
+	// Push applies a device's changes and returns their outcomes along with
+	// the settings changed after the cursor since, the pushed ones included
+	Push(ctx context.Context, userID uint, since int64, changes []dto.SettingChange) (*dto.SettingsSyncResult, error)
+}
+
+type settingsSyncService struct {
+	repo repositories.UserSettingRepository
+	tx   database.TxManager
+}
+
+// NewSettingsSyncService creates a new SettingsSyncService
+func NewSettingsSyncService(repo repositories.UserSettingRepository, tx database.TxManager) SettingsSyncService {
+	return &settingsSyncService{repo: repo, tx: tx}
+}
+
+// versionVector maps device IDs to the number of edits made on them
+type versionVector map[string]int64
+
+// Orderings of two version vectors
+const (
+	vectorEqual = iota
+	vectorBefore
+	vectorAfter
+	vectorConcurrent
+)
+
+// compare orders v against other
+func (v versionVector) compare(other versionVector) int {
+	var less, greater bool
+	for device, count := range v {
+		if count > other[device] {
+			greater = true
+		} else if count < other[device] {
+			less = true
+		}
+	}
+	for device, count := range other {
+		if _, ok := v[device]; !ok && count > 0 {
+			less = true
+		}
+	}
+	switch {
+	case less && greater:
+		return vectorConcurrent
+	case less:
+		return vectorBefore
+	case greater:
+		return vectorAfter
+	default:
+		return vectorEqual
+	}
+}
+
+// merge returns the entry-wise maximum of v and other
+func (v versionVector) merge(other versionVector) versionVector {
+	merged := make(versionVector, len(v)+len(other))
+	for device, count := range v {
+		merged[device] = count
+	}
+	for device, count := range other {
+		if count > merged[device] {
+			merged[device] = count
+		}
+	}
+	return merged
+}
+
+func (v versionVector) total() int64 {
+	var total int64
+	for _, count := range v {
+		total += count
+	}
+	return total
+}
+
+// syncedSetting is the API view of a stored setting
+func syncedSetting(setting models.UserSetting) dto.SyncedSetting {
+	result := dto.SyncedSetting{
+		Key:       setting.Key,
+		Value:     json.RawMessage(setting.Value),
+		Deleted:   setting.Deleted,
+		Seq:       setting.Seq,
+		UpdatedAt: setting.UpdatedAt,
+	}
+	_ = json.Unmarshal([]byte(setting.Vector), &result.Vector)
+	return result
+}
+
+func (s *settingsSyncService) Pull(ctx context.Context, userID uint, since int64) (*dto.SettingsSync, error) {
+	settings, err := s.repo.Since(ctx, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	delta := &dto.SettingsSync{Settings: make([]dto.SyncedSetting, 0, len(settings)), Cursor: since}
+	for _, setting := range settings {
+		delta.Settings = append(delta.Settings, syncedSetting(setting))
+		delta.Cursor = setting.Seq
+	}
+	return delta, nil
+}
+
+// validateChanges checks the changes and compacts their values
+func validateChanges(changes []dto.SettingChange) error {
+	details := map[string]string{}
+	seen := make(map[string]bool, len(changes))
+	for i := range changes {
+		change := &changes[i]
+		field := fmt.Sprintf("changes[%d]", i)
+		switch {
+		case !settingKeyPattern.MatchString(change.Key):
+			details[field+".key"] = "must be lower-case letters, digits and . _ -, starting with a letter or digit"
+			continue
+		case seen[change.Key]:
+			details[field+".key"] = "is changed twice"
+			continue
+		}
+		seen[change.Key] = true
+
+		if len(change.Vector) == 0 || len(change.Vector) > maxVectorEntries {
+			details[field+".vector"] = fmt.Sprintf("must have 1 to %d entries", maxVectorEntries)
+		}
+		for device, count := range change.Vector {
+			if !helpers.ValidDeviceID(device) || count < 1 {
+				details[field+".vector"] = "must map device IDs to positive edit counts"
+				break
+			}
+		}
+
+		if change.Deleted {
+			change.Value = json.RawMessage("null")
+			continue
+		}
+		var compacted bytes.Buffer
+		if len(change.Value) == 0 || bytes.Equal(change.Value, []byte("null")) || json.Compact(&compacted, change.Value) != nil {
+			details[field+".value"] = "is required unless deleted"
+			continue
+		}
+		if compacted.Len() > maxSettingValueBytes {
+			details[field+".value"] = fmt.Sprintf("must be at most %d bytes of JSON", maxSettingValueBytes)
+			continue
+		}
+		change.Value = compacted.Bytes()
+	}
+	if len(details) > 0 {
+		return apperrors.ValidationWithDetails("Invalid setting changes", details)
+	}
+	return nil
+}
+
+func (s *settingsSyncService) Push(ctx context.Context, userID uint, since int64, changes []dto.SettingChange) (*dto.SettingsSyncResult, error) {
+	if err := validateChanges(changes); err != nil {
+		return nil, err
+	}
+	keys := make([]string, len(changes))
+	for i, change := range changes {
+		keys[i] = change.Key
+	}
+
+	results := make([]dto.SettingChangeResult, 0, len(changes))
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		seq, err := s.repo.LastSeq(ctx, userID)
+		if err != nil {
+			return err
+		}
+		existing, err := s.repo.Find(ctx, userID, keys)
+		if err != nil {
+			return err
+		}
+		live, err := s.repo.CountLive(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		for _, change := range changes {
+			vector := versionVector(change.Vector)
+			current, found := existing[change.Key]
+			var currentVector versionVector
+			if found {
+				_ = json.Unmarshal([]byte(current.Vector), &currentVector)
+			}
+
+			status := dto.SettingApplied
+			winner := models.UserSetting{UserID: userID, Key: change.Key, Value: string(change.Value), Deleted: change.Deleted}
+			ordering := vectorAfter
+			if found {
+				ordering = vector.compare(currentVector)
+				if ordering == vectorEqual && (current.Value != winner.Value || current.Deleted != winner.Deleted) {
+					ordering = vectorConcurrent
+				}
+			}
+			switch ordering {
+			case vectorEqual:
+				// A retried push
+				results = append(results, dto.SettingChangeResult{Key: change.Key, Status: status, Setting: syncedSetting(current)})
+				continue
+			case vectorBefore:
+				results = append(results, dto.SettingChangeResult{Key: change.Key, Status: dto.SettingStale, Setting: syncedSetting(current)})
+				continue
+			case vectorConcurrent:
+				status = dto.SettingMerged
+				currentTotal, total := currentVector.total(), vector.total()
+				if currentTotal > total || (currentTotal == total && current.Value > winner.Value) {
+					winner.Value, winner.Deleted = current.Value, current.Deleted
+				}
+				vector = vector.merge(currentVector)
+			}
+
+			wasLive, isLive := found && !current.Deleted, !winner.Deleted
+			if isLive && !wasLive {
+				live++
+				if live > maxSyncedSettings {
+					return apperrors.NewAppError(apperrors.ErrCodeConflict, fmt.Sprintf("A user can have at most %d settings", maxSyncedSettings), http.StatusConflict)
+				}
+			} else if wasLive && !isLive {
+				live--
+			}
+
+			encoded, _ := json.Marshal(vector)
+			winner.Vector = string(encoded)
+			seq++
+			winner.Seq = seq
+			if err := s.repo.Save(ctx, &winner); err != nil {
+				return err
+			}
+			results = append(results, dto.SettingChangeResult{Key: change.Key, Status: status, Setting: syncedSetting(winner)})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	delta, err := s.Pull(ctx, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.SettingsSyncResult{Results: results, SettingsSync: *delta}, nil
+}
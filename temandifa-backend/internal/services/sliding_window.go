@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// CountSlidingWindow records a request at now in a sliding window rate
+// limiter's sorted set and returns the requests within window, this one
+// included. The REST middleware and the gRPC interceptors count in the same
+// sets, so both APIs share a limit.
+func CountSlidingWindow(ctx context.Context, rdb *redis.Client, key string, window time.Duration, now time.Time) (int64, error) {
+	pipe := rdb.Pipeline()
+
+	// Remove old entries outside the window
+	pipe.ZRemRangeByScore(ctx, key, "0", strconv.FormatInt(now.Add(-window).UnixNano(), 10))
+
+	// Add current request with current timestamp as score
+	pipe.ZAdd(ctx, key, redis.Z{
+		Score:  float64(now.UnixNano()),
+		Member: strconv.FormatInt(now.UnixNano(), 10),
+	})
+
+	// Count requests in current window
+	countCmd := pipe.ZCard(ctx, key)
+
+	// Set expiry on the key to auto-cleanup
+	pipe.Expire(ctx, key, window+time.Second)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return countCmd.Val(), nil
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/dto"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+)
+
+// Overall statuses of the public status page
+const (
+	ServiceStatusOperational = "operational"
+	ServiceStatusDegraded    = "degraded"
+	ServiceStatusMajorOutage = "major_outage"
+	ServiceStatusMaintenance = "maintenance"
+)
+
+// Component statuses of the public status page
+const (
+	ComponentStatusOperational   = "operational"
+	ComponentStatusOutage        = "outage"
+	ComponentStatusNotConfigured = "not_configured"
+	ComponentStatusUnknown       = "unknown"
+)
+
+// statusUptimeDays is the window the status page reports uptime over
+const statusUptimeDays = 90
+
+// statusUptimeCacheTTL bounds how often the public, unauthenticated status
+// page reads the uptime samples
+const statusUptimeCacheTTL = time.Minute
+
+// statusComponents are the components shown on the status page in order,
+// with the health checker component each one is; the API has none and is up
+// when every critical component is
+var statusComponents = []struct{ name, checked string }{
+	{"api", ""},
+	{"database", "postgres"},
+	{"redis", "redis"},
+	{"ai_service", "ai_service"},
+}
+
+// StatusService builds the public status page from the background health
+// checker: current component health without error details, ongoing
+// incidents and uptime from the samples it stores
+type StatusService interface {
+	// Status reports the current status; it never fails, uptime is left out
+	// when the samples can't be read
+	Status(ctx context.Context) *dto.ServiceStatus
+}
+
+type statusService struct {
+	checker *HealthChecker
+	uptime  repositories.UptimeRepository
+	dynamic *config.Dynamic
+	log     *zap.Logger
+
+	mu         sync.Mutex
+	cachedAt   time.Time
+	cachedFrom time.Time
+	cached     []models.ComponentUptimeDaily
+}
+
+func NewStatusService(checker *HealthChecker, uptime repositories.UptimeRepository, dynamic *config.Dynamic, log *zap.Logger) StatusService {
+	return &statusService{
+		checker: checker,
+		uptime:  uptime,
+		dynamic: dynamic,
+		log:     log,
+	}
+}
+
+func (s *statusService) Status(ctx context.Context) *dto.ServiceStatus {
+	now := time.Now().UTC()
+	from := now.Truncate(24*time.Hour).AddDate(0, 0, -(statusUptimeDays - 1))
+	rows := s.uptimeSince(ctx, from, now)
+
+	// Sum samples per component and day
+	type sums struct{ samples, healthy int64 }
+	perDay := make(map[string]map[string]sums)
+	for _, row := range rows {
+		day := row.Day.UTC().Format(time.DateOnly)
+		if perDay[row.Component] == nil {
+			perDay[row.Component] = make(map[string]sums)
+		}
+		sum := perDay[row.Component][day]
+		sum.samples += row.Samples
+		sum.healthy += row.HealthySamples
+		perDay[row.Component][day] = sum
+	}
+
+	checks := s.checker.Snapshot(false)
+	status := &dto.ServiceStatus{
+		Status:     ServiceStatusOperational,
+		UpdatedAt:  now,
+		Components: make([]dto.ComponentStatus, 0, len(statusComponents)),
+		Incidents:  []dto.StatusIncident{},
+	}
+
+	for _, component := range statusComponents {
+		var current string
+		var since *time.Time
+		if component.checked == "" {
+			current, since = apiStatus(checks)
+		} else {
+			current, since = componentStatus(checks[component.checked])
+		}
+
+		uptimeKey := component.checked
+		if uptimeKey == "" {
+			uptimeKey = APIComponent
+		}
+		days := make([]dto.UptimeDay, 0, statusUptimeDays)
+		var total sums
+		for day := from; !day.After(now); day = day.AddDate(0, 0, 1) {
+			key := day.Format(time.DateOnly)
+			sum := perDay[uptimeKey][key]
+			total.samples += sum.samples
+			total.healthy += sum.healthy
+			days = append(days, dto.UptimeDay{Day: key, UptimePercent: uptimePercent(sum.samples, sum.healthy)})
+		}
+
+		status.Components = append(status.Components, dto.ComponentStatus{
+			Name:          component.name,
+			Status:        current,
+			UptimePercent: uptimePercent(total.samples, total.healthy),
+			Days:          days,
+		})
+
+		if current == ComponentStatusOutage {
+			status.Incidents = append(status.Incidents, dto.StatusIncident{
+				Component: component.name,
+				Kind:      ComponentStatusOutage,
+				Since:     since,
+			})
+			if component.checked == "" {
+				status.Status = ServiceStatusMajorOutage
+			} else if status.Status == ServiceStatusOperational {
+				status.Status = ServiceStatusDegraded
+			}
+		}
+	}
+
+	if enabled, message := s.dynamic.Maintenance(); enabled {
+		status.Status = ServiceStatusMaintenance
+		status.Incidents = append(status.Incidents, dto.StatusIncident{
+			Component: "api",
+			Kind:      ServiceStatusMaintenance,
+			Message:   message,
+		})
+	}
+	return status
+}
+
+// uptimeSince returns the uptime samples from day from on, cached for
+// statusUptimeCacheTTL. When they can't be read the previous ones are
+// served, or none.
+func (s *statusService) uptimeSince(ctx context.Context, from, now time.Time) []models.ComponentUptimeDaily {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.cachedAt) < statusUptimeCacheTTL && s.cachedFrom.Equal(from) {
+		return s.cached
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, componentCheckTimeout)
+	defer cancel()
+
+	rows, err := s.uptime.Since(ctx, from)
+	// Retried after the TTL either way, so an outage doesn't cost every request a query
+	s.cachedAt = now
+	if err != nil {
+		s.log.Warn("Failed to load uptime samples for the status page", zap.Error(err))
+		return s.cached
+	}
+	s.cachedFrom = from
+	s.cached = rows
+	return rows
+}
+
+// apiStatus is operational when every critical component is healthy and an
+// outage when one isn't, since the earliest of their transitions
+func apiStatus(checks map[string]dto.HealthCheck) (string, *time.Time) {
+	current := ComponentStatusOperational
+	var since *time.Time
+	for _, check := range checks {
+		if !check.Critical {
+			continue
+		}
+		switch check.Status {
+		case HealthStatusHealthy:
+		case HealthStatusUnhealthy:
+			current = ComponentStatusOutage
+			if transition := transitionTime(check); transition != nil && (since == nil || transition.Before(*since)) {
+				since = transition
+			}
+		default:
+			if current == ComponentStatusOperational {
+				current = ComponentStatusUnknown
+			}
+		}
+	}
+	return current, since
+}
+
+// componentStatus maps a health check to the status page, with the time of
+// the transition to an outage
+func componentStatus(check dto.HealthCheck) (string, *time.Time) {
+	switch check.Status {
+	case HealthStatusHealthy:
+		return ComponentStatusOperational, nil
+	case HealthStatusUnhealthy:
+		return ComponentStatusOutage, transitionTime(check)
+	case HealthStatusNotConfigured:
+		return ComponentStatusNotConfigured, nil
+	}
+	return ComponentStatusUnknown, nil
+}
+
+func transitionTime(check dto.HealthCheck) *time.Time {
+	transition, err := time.Parse(time.RFC3339, check.LastTransition)
+	if err != nil {
+		return nil
+	}
+	return &transition
+}
+
+// uptimePercent is the share of healthy samples rounded to two decimals; nil
+// without samples
+func uptimePercent(samples, healthy int64) *float64 {
+	if samples == 0 {
+		return nil
+	}
+	percent := math.Round(float64(healthy)/float64(samples)*10000) / 100
+	return &percent
+}
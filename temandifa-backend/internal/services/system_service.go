@@ -0,0 +1,115 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/dto"
+)
+
+// systemVersionTimeout bounds each backing service version query, so a hung
+// dependency doesn't stall triage
+const systemVersionTimeout = 2 * time.Second
+
+// unavailableVersion is reported for services that didn't answer
+const unavailableVersion = "unavailable"
+
+// SystemService reports what is running on this instance
+type SystemService interface {
+	// Info describes the instance; build holds the ldflags build variables
+	Info(ctx context.Context, build dto.BuildInfo) dto.SystemInfo
+}
+
+type systemService struct {
+	db        *gorm.DB
+	rdb       *redis.Client
+	cfg       *config.Config
+	startedAt time.Time
+}
+
+// NewSystemService creates a new SystemService; uptime counts from here,
+// which is during application startup
+func NewSystemService(db *gorm.DB, rdb *redis.Client, cfg *config.Config) SystemService {
+	return &systemService{db: db, rdb: rdb, cfg: cfg, startedAt: time.Now()}
+}
+
+func (s *systemService) Info(ctx context.Context, build dto.BuildInfo) dto.SystemInfo {
+	uptime := time.Since(s.startedAt)
+	info := dto.SystemInfo{
+		Build:         build,
+		GoVersion:     runtime.Version(),
+		InstanceID:    s.cfg.InstanceID,
+		Profile:       s.cfg.AppEnv,
+		StartedAt:     s.startedAt,
+		Uptime:        uptime.Truncate(time.Second).String(),
+		UptimeSeconds: int64(uptime.Seconds()),
+		Goroutines:    runtime.NumGoroutine(),
+		Services: map[string]string{
+			s.cfg.DBDriver: s.databaseVersion(ctx),
+			"redis":        s.redisVersion(ctx),
+		},
+		Modules: map[string]string{},
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range bi.Deps {
+			if dep.Replace != nil {
+				dep = dep.Replace
+			}
+			info.Modules[dep.Path] = dep.Version
+		}
+		// Builds without ldflags still carry the VCS revision
+		if info.Build.Commit == "" || info.Build.Commit == "unknown" {
+			for _, setting := range bi.Settings {
+				if setting.Key == "vcs.revision" {
+					info.Build.Commit = setting.Value
+				}
+			}
+		}
+	}
+	return info
+}
+
+func (s *systemService) databaseVersion(ctx context.Context) string {
+	ctx, cancel := context.WithTimeout(ctx, systemVersionTimeout)
+	defer cancel()
+
+	query := "SHOW server_version"
+	if s.cfg.DBDriver == database.DriverSQLite {
+		query = "SELECT sqlite_version()"
+	}
+	var version string
+	if err := s.db.WithContext(ctx).Raw(query).Scan(&version).Error; err != nil || version == "" {
+		return unavailableVersion
+	}
+	return version
+}
+
+func (s *systemService) redisVersion(ctx context.Context) string {
+	if s.rdb == nil {
+		return unavailableVersion
+	}
+	ctx, cancel := context.WithTimeout(ctx, systemVersionTimeout)
+	defer cancel()
+
+	server, err := s.rdb.Info(ctx, "server").Result()
+	if err != nil {
+		return unavailableVersion
+	}
+	scanner := bufio.NewScanner(strings.NewReader(server))
+	for scanner.Scan() {
+		if version, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "redis_version:"); ok {
+			return version
+		}
+	}
+	return unavailableVersion
+}
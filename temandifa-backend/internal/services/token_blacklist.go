@@ -4,14 +4,40 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"go.uber.org/fx"
 	"go.uber.org/zap"
 
+	"temandifa-backend/internal/config"
 	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/metrics"
 )
 
+// blacklistScanCount is the SCAN batch size; each batch is followed by one
+// pipelined round trip, so Redis is never blocked for long
+const blacklistScanCount = 500
+
+// BlacklistStats summarizes the token blacklist
+type BlacklistStats struct {
+	Entries     int64 `json:"entries"`
+	MemoryBytes int64 `json:"memory_bytes"` // sum of MEMORY USAGE
+	Expired     int64 `json:"expired"`      // entries whose token can no longer be used
+	WithoutTTL  int64 `json:"without_ttl"`  // entries Redis will never evict on its own
+}
+
+// BlacklistEntry is one revoked access token, identified by its hash
+type BlacklistEntry struct {
+	TokenHash     string     `json:"token_hash"`
+	BlacklistedAt *time.Time `json:"blacklisted_at,omitempty"` // unknown for entries written by older versions
+	ExpiresIn     string     `json:"expires_in,omitempty"`     // until Redis evicts the entry; empty without a TTL
+	Expired       bool       `json:"expired"`
+}
+
 // TokenBlacklist manages revoked access tokens in Redis
 type TokenBlacklist struct {
 	prefix string
@@ -26,27 +52,42 @@ func NewTokenBlacklist(client *redis.Client) *TokenBlacklist {
 	}
 }
 
-// Add adds a token to the blacklist with TTL matching remaining expiry
+// blacklistHash identifies a token in the blacklist (raw tokens aren't stored)
+func blacklistHash(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// Add adds a token to the blacklist with TTL matching remaining expiry.
+// The value is the time of revocation, so entries can be listed and aged.
 func (tb *TokenBlacklist) Add(ctx context.Context, token string, expiry time.Duration) error {
+	return tb.AddHash(ctx, blacklistHash(token), expiry)
+}
+
+// AddHash blacklists the token with the given hash, for callers that only
+// stored the hash
+func (tb *TokenBlacklist) AddHash(ctx context.Context, tokenHash string, expiry time.Duration) error {
 	if tb.client == nil {
-		logger.Warn("Token blacklist: Redis not available")
+		logger.Ctx(ctx).Warn("Token blacklist: Redis not available")
+		return nil
+	}
+	// An expired token is rejected anyway; a zero TTL would keep the key forever
+	if expiry <= 0 {
 		return nil
 	}
 
-	// Hash the token for storage (don't store raw tokens)
-	hash := sha256.Sum256([]byte(token))
-	key := tb.prefix + hex.EncodeToString(hash[:])
+	key := tb.prefix + tokenHash
 
-	err := tb.client.Set(ctx, key, "1", expiry).Err()
+	err := tb.client.Set(ctx, key, strconv.FormatInt(time.Now().Unix(), 10), expiry).Err()
 	if err != nil {
-		logger.Error("Failed to blacklist token",
+		logger.Ctx(ctx).Error("Failed to blacklist token",
 			zap.String("key", key[:16]+"..."),
 			zap.Error(err),
 		)
 		return err
 	}
 
-	logger.Debug("Token blacklisted",
+	logger.Ctx(ctx).Debug("Token blacklisted",
 		zap.String("key", key[:16]+"..."),
 		zap.Duration("ttl", expiry),
 	)
@@ -59,12 +100,11 @@ func (tb *TokenBlacklist) IsBlacklisted(ctx context.Context, token string) bool
 		return false
 	}
 
-	hash := sha256.Sum256([]byte(token))
-	key := tb.prefix + hex.EncodeToString(hash[:])
+	key := tb.prefix + blacklistHash(token)
 
 	exists, err := tb.client.Exists(ctx, key).Result()
 	if err != nil {
-		logger.Warn("Failed to check token blacklist",
+		logger.Ctx(ctx).Warn("Failed to check token blacklist",
 			zap.Error(err),
 		)
 		return false
@@ -79,8 +119,221 @@ func (tb *TokenBlacklist) Remove(ctx context.Context, token string) error {
 		return nil
 	}
 
-	hash := sha256.Sum256([]byte(token))
-	key := tb.prefix + hex.EncodeToString(hash[:])
+	return tb.client.Del(ctx, tb.prefix+blacklistHash(token)).Err()
+}
+
+// scannedEntry is a blacklist key with the fields a scan fetched for it
+type scannedEntry struct {
+	key           string
+	blacklistedAt time.Time // zero when unknown
+	ttl           time.Duration
+	memory        int64
+}
+
+// expired reports whether the revoked token has outlived any access token,
+// so the entry no longer protects anything
+func (e scannedEntry) expired(now time.Time) bool {
+	return !e.blacklistedAt.IsZero() && now.Sub(e.blacklistedAt) > AccessTokenDuration
+}
+
+// scan walks the blacklist with SCAN, fetching each batch's values and TTLs
+// (and memory usage when withMemory) in one pipeline, and calls fn per batch
+func (tb *TokenBlacklist) scan(ctx context.Context, withMemory bool, fn func([]scannedEntry) error) error {
+	iter := tb.client.Scan(ctx, 0, tb.prefix+"*", blacklistScanCount).Iterator()
+	keys := make([]string, 0, blacklistScanCount)
+
+	flush := func() error {
+		if len(keys) == 0 {
+			return nil
+		}
+		pipe := tb.client.Pipeline()
+		values := make([]*redis.StringCmd, len(keys))
+		ttls := make([]*redis.DurationCmd, len(keys))
+		memory := make([]*redis.IntCmd, len(keys))
+		for i, key := range keys {
+			values[i] = pipe.Get(ctx, key)
+			ttls[i] = pipe.TTL(ctx, key)
+			if withMemory {
+				memory[i] = pipe.MemoryUsage(ctx, key)
+			}
+		}
+		// Keys that expired between SCAN and the pipeline answer redis.Nil
+		if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+			return err
+		}
+
+		entries := make([]scannedEntry, 0, len(keys))
+		for i, key := range keys {
+			value, err := values[i].Result()
+			if err != nil {
+				continue
+			}
+			entry := scannedEntry{key: key, ttl: ttls[i].Val()}
+			if unix, err := strconv.ParseInt(value, 10, 64); err == nil && unix > 1 {
+				entry.blacklistedAt = time.Unix(unix, 0)
+			}
+			if withMemory {
+				entry.memory = memory[i].Val()
+			}
+			entries = append(entries, entry)
+		}
+		keys = keys[:0]
+		return fn(entries)
+	}
+
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) == blacklistScanCount {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	return flush()
+}
+
+// Stats counts the blacklist with SCAN (never KEYS, which blocks Redis) and
+// updates the blacklist gauges
+func (tb *TokenBlacklist) Stats(ctx context.Context) (BlacklistStats, error) {
+	var stats BlacklistStats
+	if tb.client == nil {
+		return stats, nil
+	}
+
+	now := time.Now()
+	err := tb.scan(ctx, true, func(entries []scannedEntry) error {
+		for _, entry := range entries {
+			stats.Entries++
+			stats.MemoryBytes += entry.memory
+			if entry.expired(now) {
+				stats.Expired++
+			}
+			if entry.ttl < 0 {
+				stats.WithoutTTL++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return stats, err
+	}
+
+	metrics.TokenBlacklistEntries.Set(float64(stats.Entries))
+	metrics.TokenBlacklistMemory.Set(float64(stats.MemoryBytes))
+	return stats, nil
+}
+
+// Recent returns up to limit entries, most recently blacklisted first
+func (tb *TokenBlacklist) Recent(ctx context.Context, limit int) ([]BlacklistEntry, error) {
+	if tb.client == nil {
+		return []BlacklistEntry{}, nil
+	}
+
+	var all []scannedEntry
+	err := tb.scan(ctx, false, func(entries []scannedEntry) error {
+		all = append(all, entries...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].blacklistedAt.After(all[j].blacklistedAt)
+	})
+	if len(all) > limit {
+		all = all[:limit]
+	}
+
+	now := time.Now()
+	recent := make([]BlacklistEntry, len(all))
+	for i, entry := range all {
+		recent[i] = BlacklistEntry{
+			TokenHash: strings.TrimPrefix(entry.key, tb.prefix),
+			Expired:   entry.expired(now),
+		}
+		if !entry.blacklistedAt.IsZero() {
+			at := entry.blacklistedAt
+			recent[i].BlacklistedAt = &at
+		}
+		if entry.ttl > 0 {
+			recent[i].ExpiresIn = entry.ttl.Truncate(time.Second).String()
+		}
+	}
+	return recent, nil
+}
+
+// ClearExpired deletes entries whose token has outlived any access token.
+// Entries of unknown age without a TTL get one of AccessTokenDuration, so
+// they are evicted once every token they could belong to has expired.
+func (tb *TokenBlacklist) ClearExpired(ctx context.Context) (int64, error) {
+	if tb.client == nil {
+		return 0, nil
+	}
+
+	var cleared int64
+	now := time.Now()
+	err := tb.scan(ctx, false, func(entries []scannedEntry) error {
+		var expired []string
+		pipe := tb.client.Pipeline()
+		for _, entry := range entries {
+			switch {
+			case entry.expired(now):
+				expired = append(expired, entry.key)
+			case entry.blacklistedAt.IsZero() && entry.ttl < 0:
+				pipe.Expire(ctx, entry.key, AccessTokenDuration)
+			}
+		}
+		if len(expired) > 0 {
+			pipe.Unlink(ctx, expired...)
+		}
+		if pipe.Len() == 0 {
+			return nil
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return err
+		}
+		cleared += int64(len(expired))
+		return nil
+	})
+	return cleared, err
+}
+
+// RegisterTokenBlacklistStats refreshes the blacklist gauges every
+// BLACKLIST_STATS_INTERVAL
+func RegisterTokenBlacklistStats(lc fx.Lifecycle, blacklist *TokenBlacklist, cfg *config.Config, log *zap.Logger) {
+	stopChan := make(chan struct{})
+
+	refresh := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if _, err := blacklist.Stats(ctx); err != nil {
+			log.Debug("Failed to collect token blacklist stats", zap.Error(err))
+		}
+	}
 
-	return tb.client.Del(ctx, key).Err()
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			ticker := time.NewTicker(cfg.BlacklistStatsInterval)
+			go func() {
+				for {
+					select {
+					case <-ticker.C:
+						refresh()
+					case <-stopChan:
+						ticker.Stop()
+						return
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stopChan)
+			return nil
+		},
+	})
 }
@@ -6,21 +6,23 @@ import (
 
 	"go.uber.org/fx"
 	"go.uber.org/zap"
-
-	"temandifa-backend/internal/logger"
 )
 
 // TokenCleanupJob handles periodic cleanup of expired tokens
 type TokenCleanupJob struct {
 	tokenService TokenService
+	verification EmailVerificationService
+	log          *zap.Logger
 	interval     time.Duration
 	stopChan     chan struct{}
 }
 
 // NewTokenCleanupJob creates a new token cleanup job
-func NewTokenCleanupJob(tokenService TokenService) *TokenCleanupJob {
+func NewTokenCleanupJob(tokenService TokenService, verification EmailVerificationService, log *zap.Logger) *TokenCleanupJob {
 	return &TokenCleanupJob{
 		tokenService: tokenService,
+		verification: verification,
+		log:          log,
 		interval:     24 * time.Hour, // Run daily
 		stopChan:     make(chan struct{}),
 	}
@@ -28,7 +30,7 @@ func NewTokenCleanupJob(tokenService TokenService) *TokenCleanupJob {
 
 // Start begins the cleanup job
 func (j *TokenCleanupJob) Start() {
-	logger.Info("Token cleanup job started",
+	j.log.Info("Token cleanup job started",
 		zap.Duration("interval", j.interval),
 	)
 
@@ -44,7 +46,7 @@ func (j *TokenCleanupJob) Start() {
 				j.runCleanup()
 			case <-j.stopChan:
 				ticker.Stop()
-				logger.Info("Token cleanup job stopped")
+				j.log.Info("Token cleanup job stopped")
 				return
 			}
 		}
@@ -59,19 +61,32 @@ func (j *TokenCleanupJob) Stop() {
 func (j *TokenCleanupJob) runCleanup() {
 	count, err := j.tokenService.CleanupExpiredTokens()
 	if err != nil {
-		logger.Error("Token cleanup failed", zap.Error(err))
+		j.log.Error("Token cleanup failed", zap.Error(err))
+		return
+	}
+	if count > 0 {
+		j.log.Info("Token cleanup completed",
+			zap.Int64("tokens_removed", count),
+		)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	count, err = j.verification.CleanupExpired(ctx)
+	if err != nil {
+		j.log.Error("Verification token cleanup failed", zap.Error(err))
 		return
 	}
 	if count > 0 {
-		logger.Info("Token cleanup completed",
+		j.log.Info("Verification token cleanup completed",
 			zap.Int64("tokens_removed", count),
 		)
 	}
 }
 
 // RegisterTokenCleanupJob registers the cleanup job with fx lifecycle
-func RegisterTokenCleanupJob(lc fx.Lifecycle, tokenService TokenService) {
-	job := NewTokenCleanupJob(tokenService)
+func RegisterTokenCleanupJob(lc fx.Lifecycle, tokenService TokenService, verification EmailVerificationService, log *zap.Logger) {
+	job := NewTokenCleanupJob(tokenService, verification, log)
 
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
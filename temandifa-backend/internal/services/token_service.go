@@ -1,19 +1,26 @@
 package services
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/base64"
 	"errors"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/fx"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/dto"
 	apperrors "temandifa-backend/internal/errors"
-	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/metrics"
 	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/secrets"
 )
 
 // Token configuration
@@ -23,6 +30,22 @@ const (
 	RefreshTokenLength   = 64                 // bytes
 )
 
+// ErrTokenEpochRevoked is returned for access tokens issued before the last
+// forced logout
+var ErrTokenEpochRevoked = errors.New("token issued before the last forced logout")
+
+// AccessClaims are the claims of a valid access token
+type AccessClaims struct {
+	UserID uint
+	// SessionID is the refresh token issued with the access token; 0 for
+	// tokens issued before sessions were tracked
+	SessionID uint
+	// Role and RoleVersion are the user's role and its version when the
+	// token was issued; tokens issued before roles had versions have neither
+	Role        string
+	RoleVersion uint
+}
+
 // TokenPair contains both access and refresh tokens
 type TokenPair struct {
 	AccessToken  string    `json:"access_token"`
@@ -31,72 +54,274 @@ type TokenPair struct {
 	TokenType    string    `json:"token_type"`
 }
 
-// TokenService handles JWT and refresh token operations
+// TokenService handles JWT and refresh token operations. Refresh tokens
+// issued with a device ID (X-Device-ID) are bound to the device: only one is
+// active per user and device, and refreshing one without the same device ID
+// revokes it.
 type TokenService interface {
-	GenerateTokenPair(user *models.User, userAgent, ipAddress string) (*TokenPair, error)
-	RefreshAccessToken(refreshTokenString, userAgent, ipAddress string) (*TokenPair, error)
-	ValidateAccessToken(tokenString string) (uint, error)
+	GenerateTokenPair(ctx context.Context, user *models.User, userAgent, ipAddress, deviceID string) (*TokenPair, error)
+	RefreshAccessToken(ctx context.Context, refreshTokenString, userAgent, ipAddress, deviceID string) (*TokenPair, error)
+	ValidateAccessToken(tokenString string) (*AccessClaims, error)
 	RevokeRefreshToken(tokenString string) error
 	RevokeAllUserTokens(userID uint) error
+	// RevokeTokenByID revokes the user's session (refresh token) id and
+	// blacklists the access token issued with it. It reports false when the
+	// user has no such active session.
+	RevokeTokenByID(ctx context.Context, userID, id uint) (bool, error)
+	// RevokeOtherUserTokens revokes the user's sessions but keepID (0 keeps
+	// none) and blacklists the access tokens issued with them, returning how
+	// many were revoked
+	RevokeOtherUserTokens(ctx context.Context, userID, keepID uint) (int64, error)
+	// RevokeDeviceTokens revokes the refresh tokens bound to the user's
+	// device, within ctx's transaction if any
+	RevokeDeviceTokens(ctx context.Context, userID uint, deviceID string) (int64, error)
 	CleanupExpiredTokens() (int64, error)
+	// BumpEpoch increments the stored token epoch, within ctx's transaction
+	// if any, and returns it. It takes effect on RefreshEpoch.
+	BumpEpoch(ctx context.Context) (int64, error)
+	// RefreshEpoch loads the stored token epoch
+	RefreshEpoch(ctx context.Context) error
+	// JWKS returns the public keys of the asymmetric signing keys, for other
+	// services verifying access tokens; empty with HS256
+	JWKS() dto.JWKS
+}
+
+// errUnknownSigningKey rejects access tokens whose kid names no active key
+var errUnknownSigningKey = errors.New("unknown signing key")
+
+// jwtKeys holds the signing keys by kid and the one new tokens are signed
+// with, and JWT_SECRET for tokens without a kid: the current secret and, for
+// AccessTokenDuration after a rotation, the previous one so tokens issued
+// before the rotation stay valid
+type jwtKeys struct {
+	signingID string // Empty signs with current, without a kid
+	byID      map[string][]byte
+
+	current       []byte
+	previous      []byte
+	previousUntil time.Time
+}
+
+// newJWTKeys indexes the signing keys, signing with signingID or else the
+// newest key
+func newJWTKeys(keys []config.JWTKey, signingID string) *jwtKeys {
+	k := &jwtKeys{byID: make(map[string][]byte, len(keys))}
+	for _, key := range keys {
+		k.byID[key.ID] = []byte(key.Secret)
+	}
+	k.signingID = signingID
+	if k.signingID == "" && len(keys) > 0 {
+		k.signingID = keys[len(keys)-1].ID
+	}
+	return k
+}
+
+// sign signs claims with the signing key, naming it in the kid header
+func (k *jwtKeys) sign(claims jwt.MapClaims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	if k.signingID == "" {
+		return token.SignedString(k.current)
+	}
+	token.Header["kid"] = k.signingID
+	return token.SignedString(k.byID[k.signingID])
 }
 
 type tokenService struct {
-	db        *gorm.DB
-	jwtSecret []byte
+	db          *gorm.DB
+	txManager   database.TxManager
+	permissions PermissionService
+	blacklist   *TokenBlacklist
+	keys        atomic.Pointer[jwtKeys]
+	// With RS256 or EdDSA, new tokens are signed with the private key
+	// privateSigningID; the HMAC keys then only verify older tokens
+	method           jwt.SigningMethod
+	privateKeys      map[string]*jwtPrivateKey
+	privateSigningID string
+	jwks             dto.JWKS
+	epoch            atomic.Int64
+	cleanupRetention time.Duration
+	cleanupBatchSize int
+	reuseGrace       time.Duration
+	log              *zap.Logger
 }
 
 // NewTokenService creates a new token service
-func NewTokenService(db *gorm.DB, cfg *config.Config) TokenService {
-	if len(cfg.JWTSecret) < 32 {
-		logger.Fatal("JWT_SECRET must be at least 32 characters")
+func NewTokenService(db *gorm.DB, txManager database.TxManager, permissions PermissionService, blacklist *TokenBlacklist, cfg *config.AuthConfig, secretWatcher *secrets.Watcher, log *zap.Logger) TokenService {
+	asymmetric := cfg.JWTAlgorithm == config.JWTAlgRS256 || cfg.JWTAlgorithm == config.JWTAlgEdDSA
+	if !asymmetric && cfg.JWTSecret == "" && len(cfg.JWTSigningKeys) == 0 {
+		log.Fatal("JWT_SECRET or JWT_SIGNING_KEYS is required")
+	}
+	if cfg.JWTSecret != "" && len(cfg.JWTSecret) < 32 {
+		log.Fatal("JWT_SECRET must be at least 32 characters")
+	}
+	// With an asymmetric algorithm JWT_SIGNING_KEY_ID names a private key
+	hmacSigningID := cfg.JWTSigningKeyID
+	if asymmetric {
+		hmacSigningID = ""
+	}
+	if err := config.ValidateJWTKeys(cfg.JWTSigningKeys, hmacSigningID); err != nil {
+		log.Fatal("Invalid JWT signing keys", zap.Error(err))
+	}
+	ts := &tokenService{
+		db:               db,
+		txManager:        txManager,
+		permissions:      permissions,
+		blacklist:        blacklist,
+		cleanupRetention: cfg.TokenCleanupRetention,
+		cleanupBatchSize: cfg.TokenCleanupBatchSize,
+		reuseGrace:       cfg.RefreshTokenReuseGrace,
+		log:              log,
+	}
+	keys := newJWTKeys(cfg.JWTSigningKeys, hmacSigningID)
+	keys.current = []byte(cfg.JWTSecret)
+	ts.keys.Store(keys)
+
+	ts.method = jwt.SigningMethodHS256
+	ts.jwks.Keys = []dto.JWK{}
+	if asymmetric {
+		privateKeys, public, err := loadJWTPrivateKeys(cfg.JWTAlgorithm, cfg.JWTPrivateKeys)
+		if err != nil {
+			log.Fatal("Failed to load JWT_PRIVATE_KEYS", zap.Error(err))
+		}
+		ts.method = jwt.GetSigningMethod(cfg.JWTAlgorithm)
+		ts.privateKeys = privateKeys
+		ts.privateSigningID = cfg.JWTSigningKeyID
+		if ts.privateSigningID == "" {
+			ts.privateSigningID = cfg.JWTPrivateKeys[len(cfg.JWTPrivateKeys)-1].ID
+		}
+		ts.jwks.Keys = public
+		log.Info("Signing access tokens with a private key",
+			zap.String("algorithm", cfg.JWTAlgorithm),
+			zap.String("signing_kid", ts.privateSigningID),
+		)
+	}
+
+	// Pick up JWT_SECRET and JWT_SIGNING_KEYS rotations when they are secret
+	// manager references
+	secretWatcher.OnChange("JWT_SECRET", ts.rotateSecret)
+	secretWatcher.OnChange("JWT_SIGNING_KEYS", func(value string) {
+		ts.rotateSigningKeys(value, hmacSigningID)
+	})
+	return ts
+}
+
+// rotateSigningKeys replaces the signing keys with those of value, a
+// JWT_SIGNING_KEYS list. Tokens signed with a key no longer listed are
+// rejected at once, so retired keys stay listed until their tokens expire.
+func (ts *tokenService) rotateSigningKeys(value, signingID string) {
+	list := config.ParseJWTKeys(value)
+	if err := config.ValidateJWTKeys(list, signingID); err != nil {
+		ts.log.Error("Ignoring rotated JWT_SIGNING_KEYS", zap.Error(err))
+		return
 	}
-	return &tokenService{
-		db:        db,
-		jwtSecret: []byte(cfg.JWTSecret),
+	old := ts.keys.Load()
+	if len(list) == 0 && len(old.current) == 0 {
+		ts.log.Error("Ignoring empty rotated JWT_SIGNING_KEYS without JWT_SECRET")
+		return
 	}
+	keys := newJWTKeys(list, signingID)
+	keys.current, keys.previous, keys.previousUntil = old.current, old.previous, old.previousUntil
+	ts.keys.Store(keys)
+	ts.log.Info("JWT signing keys rotated", zap.String("signing_kid", keys.signingID), zap.Int("keys", len(list)))
 }
 
-// GenerateTokenPair creates a new access/refresh token pair
-func (ts *tokenService) GenerateTokenPair(user *models.User, userAgent, ipAddress string) (*TokenPair, error) {
-	// Generate access token (JWT)
-	accessTokenExpiry := time.Now().Add(AccessTokenDuration)
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"sub":  user.ID,
-		"exp":  accessTokenExpiry.Unix(),
-		"iat":  time.Now().Unix(),
-		"type": "access",
+// rotateSecret signs new tokens with secret while still accepting the
+// previous secret until the access tokens it signed have expired
+func (ts *tokenService) rotateSecret(secret string) {
+	if len(secret) < 32 {
+		ts.log.Error("Ignoring rotated JWT_SECRET shorter than 32 characters")
+		return
+	}
+	old := ts.keys.Load()
+	ts.keys.Store(&jwtKeys{
+		signingID:     old.signingID,
+		byID:          old.byID,
+		current:       []byte(secret),
+		previous:      old.current,
+		previousUntil: time.Now().Add(AccessTokenDuration),
 	})
+	ts.log.Info("JWT secret rotated")
+}
 
-	accessTokenString, err := accessToken.SignedString(ts.jwtSecret)
+// GenerateTokenPair creates a new access/refresh token pair. With a device
+// ID, the refresh token replaces the ones previously issued on the device.
+func (ts *tokenService) GenerateTokenPair(ctx context.Context, user *models.User, userAgent, ipAddress, deviceID string) (*TokenPair, error) {
+	return ts.issueTokenPair(ctx, user, userAgent, ipAddress, deviceID, time.Now(), 0)
+}
+
+// issueTokenPair creates a token pair for a session started at signedInAt,
+// whose refresh token joins familyID (0 starts a family). The refresh token
+// is stored first, so the access token can name it.
+func (ts *tokenService) issueTokenPair(ctx context.Context, user *models.User, userAgent, ipAddress, deviceID string, signedInAt time.Time, familyID uint) (*TokenPair, error) {
+	// The role version makes servers with older cached permissions reload them
+	roleVersion, err := ts.permissions.RoleVersion(ctx, user.Role)
 	if err != nil {
-		logger.Error("Failed to sign access token", zap.Error(err))
-		return nil, apperrors.Internal(err)
+		ts.log.Error("Failed to load role version", zap.String("role", user.Role), zap.Error(err))
+		return nil, apperrors.Database(err)
 	}
 
 	// Generate refresh token (random bytes)
 	refreshTokenBytes := make([]byte, RefreshTokenLength)
 	if _, err := rand.Read(refreshTokenBytes); err != nil {
-		logger.Error("Failed to generate refresh token", zap.Error(err))
+		ts.log.Error("Failed to generate refresh token", zap.Error(err))
 		return nil, apperrors.Internal(err)
 	}
 	refreshTokenString := base64.URLEncoding.EncodeToString(refreshTokenBytes)
 
 	// Store refresh token in database
 	refreshToken := models.RefreshToken{
-		UserID:    user.ID,
-		Token:     refreshTokenString,
-		ExpiresAt: time.Now().Add(RefreshTokenDuration),
-		UserAgent: userAgent,
-		IPAddress: ipAddress,
+		UserID:     user.ID,
+		Token:      refreshTokenString,
+		ExpiresAt:  time.Now().Add(RefreshTokenDuration),
+		UserAgent:  userAgent,
+		IPAddress:  ipAddress,
+		DeviceID:   deviceID,
+		SignedInAt: &signedInAt,
+		FamilyID:   familyID,
 	}
 
-	if err := ts.db.Create(&refreshToken).Error; err != nil {
-		logger.Error("Failed to store refresh token", zap.Error(err))
+	if deviceID != "" {
+		if _, err := ts.RevokeDeviceTokens(ctx, user.ID, deviceID); err != nil {
+			ts.log.Error("Failed to revoke the device's refresh tokens", zap.Error(err))
+			return nil, apperrors.Database(err)
+		}
+	}
+	if err := database.Conn(ctx, ts.db).Create(&refreshToken).Error; err != nil {
+		ts.log.Error("Failed to store refresh token", zap.Error(err))
 		return nil, apperrors.Database(err)
 	}
 
-	logger.Debug("Token pair generated",
+	// Generate access token (JWT), bound to the session of the refresh token
+	accessTokenExpiry := time.Now().Add(AccessTokenDuration)
+	accessTokenString, err := ts.sign(jwt.MapClaims{
+		"sub":   user.ID,
+		"exp":   accessTokenExpiry.Unix(),
+		"iat":   time.Now().Unix(),
+		"type":  "access",
+		"epoch": ts.epoch.Load(),
+		"role":  user.Role,
+		"rv":    roleVersion,
+		"sid":   refreshToken.ID,
+	})
+	if err != nil {
+		ts.log.Error("Failed to sign access token", zap.Error(err))
+		return nil, apperrors.Internal(err)
+	}
+	// Kept so revoking the session can blacklist the access token too
+	if refreshToken.FamilyID == 0 {
+		refreshToken.FamilyID = refreshToken.ID
+	}
+	err = database.Conn(ctx, ts.db).Model(&refreshToken).
+		UpdateColumns(map[string]interface{}{
+			"access_token_hash": blacklistHash(accessTokenString),
+			"family_id":         refreshToken.FamilyID,
+		}).Error
+	if err != nil {
+		ts.log.Error("Failed to store access token hash", zap.Error(err))
+		return nil, apperrors.Database(err)
+	}
+
+	ts.log.Debug("Token pair generated",
 		zap.Uint("user_id", user.ID),
 		zap.Time("access_expires", accessTokenExpiry),
 	)
@@ -109,67 +334,174 @@ func (ts *tokenService) GenerateTokenPair(user *models.User, userAgent, ipAddres
 	}, nil
 }
 
-// RefreshAccessToken validates refresh token and generates new token pair
-func (ts *tokenService) RefreshAccessToken(refreshTokenString, userAgent, ipAddress string) (*TokenPair, error) {
-	// Find refresh token
-	var refreshToken models.RefreshToken
-	err := ts.db.Where("token = ?", refreshTokenString).
-		Preload("User").
-		First(&refreshToken).Error
-
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			logger.Debug("Refresh token not found")
-			return nil, apperrors.ErrTokenExpired
+// RefreshAccessToken validates refresh token and generates new token pair.
+// Revoking the old token and storing the new one happen in one transaction,
+// and the old token's row is locked so concurrent refreshes can't both rotate it.
+// A token bound to a device is revoked when presented without its device ID,
+// as it may have been copied off the device. A token already rotated is a
+// copy too, once past the reuse grace: its whole family is revoked.
+func (ts *tokenService) RefreshAccessToken(ctx context.Context, refreshTokenString, userAgent, ipAddress, deviceID string) (*TokenPair, error) {
+	var pair *TokenPair
+	var wrongDevice bool
+	var reused *models.RefreshToken
+	err := ts.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		tx := database.Conn(ctx, ts.db)
+
+		// Find refresh token
+		var refreshToken models.RefreshToken
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token = ?", refreshTokenString).
+			First(&refreshToken).Error
+
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				ts.log.Debug("Refresh token not found")
+				return apperrors.ErrTokenExpired
+			}
+			ts.log.Error("Database error finding refresh token", zap.Error(err))
+			return apperrors.Database(err)
 		}
-		logger.Error("Database error finding refresh token", zap.Error(err))
-		return nil, apperrors.Database(err)
-	}
 
-	// Validate token
-	if !refreshToken.IsValid() {
-		logger.Debug("Refresh token expired or revoked",
-			zap.Uint("token_id", refreshToken.ID),
-			zap.Bool("revoked", refreshToken.Revoked),
-		)
-		return nil, apperrors.ErrTokenRevoked
-	}
+		// Validate token
+		if !refreshToken.IsValid() {
+			if refreshToken.Rotated && refreshToken.RevokedAt != nil && time.Since(*refreshToken.RevokedAt) > ts.reuseGrace {
+				reused = &refreshToken
+				return apperrors.ErrTokenRevoked
+			}
+			ts.log.Debug("Refresh token expired or revoked",
+				zap.Uint("token_id", refreshToken.ID),
+				zap.Bool("revoked", refreshToken.Revoked),
+			)
+			return apperrors.ErrTokenRevoked
+		}
+		if refreshToken.DeviceID != "" && refreshToken.DeviceID != deviceID {
+			ts.log.Warn("Refresh token presented from another device",
+				zap.Uint("token_id", refreshToken.ID),
+				zap.Uint("user_id", refreshToken.UserID),
+			)
+			wrongDevice = true
+			return apperrors.ErrTokenRevoked
+		}
 
-	// Revoke old token (token rotation)
-	refreshToken.Revoke()
-	if err := ts.db.Save(&refreshToken).Error; err != nil {
-		logger.Error("Failed to revoke old refresh token", zap.Error(err))
-	}
+		var user models.User
+		if err := tx.First(&user, refreshToken.UserID).Error; err != nil {
+			ts.log.Error("Failed to load refresh token owner", zap.Error(err))
+			return apperrors.Database(err)
+		}
 
-	// Generate new token pair
-	return ts.GenerateTokenPair(&refreshToken.User, userAgent, ipAddress)
-}
+		// Revoke old token (token rotation)
+		refreshToken.Rotate()
+		if err := tx.Save(&refreshToken).Error; err != nil {
+			ts.log.Error("Failed to revoke old refresh token", zap.Error(err))
+			return apperrors.Database(err)
+		}
 
-// ValidateAccessToken validates an access token and returns user ID
-func (ts *tokenService) ValidateAccessToken(tokenString string) (uint, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
+		// Generate new token pair, continuing the session
+		signedInAt := refreshToken.CreatedAt
+		if refreshToken.SignedInAt != nil {
+			signedInAt = *refreshToken.SignedInAt
+		}
+		familyID := refreshToken.FamilyID
+		if familyID == 0 {
+			familyID = refreshToken.ID
 		}
-		return ts.jwtSecret, nil
+		pair, err = ts.issueTokenPair(ctx, &user, userAgent, ipAddress, deviceID, signedInAt, familyID)
+		return err
 	})
+	if wrongDevice {
+		if revokeErr := ts.RevokeRefreshToken(refreshTokenString); revokeErr != nil {
+			ts.log.Error("Failed to revoke refresh token", zap.Error(revokeErr))
+		}
+	}
+	if reused != nil {
+		ts.revokeFamily(ctx, reused, userAgent, ipAddress)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pair, nil
+}
+
+// ValidateAccessToken validates an access token and returns its claims
+func (ts *tokenService) ValidateAccessToken(tokenString string) (*AccessClaims, error) {
+	keys := ts.keys.Load()
+	token, err := ts.parseAccessToken(tokenString, keys, keys.current)
+	if errors.Is(err, jwt.ErrSignatureInvalid) && token != nil && token.Header["kid"] == nil &&
+		keys.previous != nil && time.Now().Before(keys.previousUntil) {
+		token, err = ts.parseAccessToken(tokenString, keys, keys.previous)
+	}
 
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
 
 		if tokenType, exists := claims["type"]; exists && tokenType != "access" {
-			return 0, errors.New("invalid token type")
+			return nil, errors.New("invalid token type")
+		}
+
+		// Tokens without an epoch were issued before the first forced logout
+		epoch, _ := claims["epoch"].(float64)
+		if int64(epoch) < ts.epoch.Load() {
+			return nil, ErrTokenEpochRevoked
 		}
 
 		if userID, ok := claims["sub"].(float64); ok {
-			return uint(userID), nil
+			role, _ := claims["role"].(string)
+			roleVersion, _ := claims["rv"].(float64)
+			sessionID, _ := claims["sid"].(float64)
+			return &AccessClaims{
+				UserID:      uint(userID),
+				SessionID:   uint(sessionID),
+				Role:        role,
+				RoleVersion: uint(roleVersion),
+			}, nil
 		}
 	}
 
-	return 0, errors.New("invalid token claims")
+	return nil, errors.New("invalid token claims")
+}
+
+// sign signs new access tokens with the private signing key, or the HMAC
+// keys with HS256
+func (ts *tokenService) sign(claims jwt.MapClaims) (string, error) {
+	if ts.privateKeys == nil {
+		return ts.keys.Load().sign(claims)
+	}
+	token := jwt.NewWithClaims(ts.method, claims)
+	token.Header["kid"] = ts.privateSigningID
+	return token.SignedString(ts.privateKeys[ts.privateSigningID].signer)
+}
+
+// parseAccessToken verifies a token with the key its kid names, or with
+// secret when it has no kid. HMAC tokens are only checked against HMAC keys
+// and the others against private keys, so a public key can't be used as an
+// HMAC secret.
+func (ts *tokenService) parseAccessToken(tokenString string, keys *jwtKeys, secret []byte) (*jwt.Token, error) {
+	return jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		kid, named := token.Header["kid"]
+		id, _ := kid.(string)
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			private, ok := ts.privateKeys[id]
+			if !ok {
+				return nil, errUnknownSigningKey
+			}
+			return private.verificationKey(token.Method)
+		}
+
+		if !named {
+			if len(secret) == 0 {
+				return nil, errUnknownSigningKey
+			}
+			return secret, nil
+		}
+		key, ok := keys.byID[id]
+		if !ok {
+			return nil, errUnknownSigningKey
+		}
+		return key, nil
+	}, jwt.WithValidMethods([]string{config.JWTAlgHS256, config.JWTAlgRS256, config.JWTAlgEdDSA}))
 }
 
 // RevokeRefreshToken revokes a specific refresh token
@@ -196,26 +528,268 @@ func (ts *tokenService) RevokeAllUserTokens(userID uint) error {
 			"revoked_at": time.Now(),
 		})
 
-	logger.Info("Revoked all user tokens",
+	ts.log.Info("Revoked all user tokens",
 		zap.Uint("user_id", userID),
 		zap.Int64("count", result.RowsAffected),
 	)
 	return result.Error
 }
 
-// CleanupExpiredTokens removes old expired tokens from database
+func (ts *tokenService) RevokeTokenByID(ctx context.Context, userID, id uint) (bool, error) {
+	var refreshToken models.RefreshToken
+	err := ts.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		tx := database.Conn(ctx, ts.db)
+		// Only the owner's active sessions; others look like missing ones
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ? AND revoked = ? AND expires_at > ?", id, userID, false, time.Now()).
+			First(&refreshToken).Error
+		if err != nil {
+			return err
+		}
+		refreshToken.Revoke()
+		return tx.Model(&refreshToken).Updates(map[string]interface{}{
+			"revoked":    true,
+			"revoked_at": refreshToken.RevokedAt,
+		}).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, apperrors.Database(err)
+	}
+
+	// The access token issued with it is valid for the rest of its lifetime
+	if refreshToken.AccessTokenHash != "" && ts.blacklist != nil {
+		remaining := time.Until(refreshToken.CreatedAt.Add(AccessTokenDuration))
+		if err := ts.blacklist.AddHash(ctx, refreshToken.AccessTokenHash, remaining); err != nil {
+			ts.log.Warn("Failed to blacklist the session's access token", zap.Uint("session_id", id), zap.Error(err))
+		}
+	}
+	ts.log.Info("Session revoked", zap.Uint("user_id", userID), zap.Uint("session_id", id))
+	return true, nil
+}
+
+func (ts *tokenService) RevokeOtherUserTokens(ctx context.Context, userID, keepID uint) (int64, error) {
+	var revoked []models.RefreshToken
+	err := ts.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		tx := database.Conn(ctx, ts.db)
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND id <> ? AND revoked = ?", userID, keepID, false).
+			Find(&revoked).Error
+		if err != nil || len(revoked) == 0 {
+			return err
+		}
+
+		ids := make([]uint, len(revoked))
+		for i, token := range revoked {
+			ids[i] = token.ID
+		}
+		return tx.Model(&models.RefreshToken{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"revoked":    true,
+				"revoked_at": time.Now(),
+			}).Error
+	})
+	if err != nil {
+		return 0, apperrors.Database(err)
+	}
+
+	ts.blacklistAccessTokens(ctx, revoked)
+	ts.log.Info("Revoked other user tokens",
+		zap.Uint("user_id", userID),
+		zap.Uint("kept_session_id", keepID),
+		zap.Int("count", len(revoked)),
+	)
+	return int64(len(revoked)), nil
+}
+
+// blacklistAccessTokens blacklists the access tokens issued with the
+// refresh tokens, like RevokeTokenByID; tokens of sessions that expired long
+// ago get no blacklist entry
+func (ts *tokenService) blacklistAccessTokens(ctx context.Context, tokens []models.RefreshToken) {
+	if ts.blacklist == nil {
+		return
+	}
+	for _, token := range tokens {
+		if token.AccessTokenHash == "" {
+			continue
+		}
+		remaining := time.Until(token.CreatedAt.Add(AccessTokenDuration))
+		if err := ts.blacklist.AddHash(ctx, token.AccessTokenHash, remaining); err != nil {
+			ts.log.Warn("Failed to blacklist the session's access token", zap.Uint("session_id", token.ID), zap.Error(err))
+		}
+	}
+}
+
+// revokeFamily revokes the session of reused, a rotated refresh token
+// presented again: whoever holds the family's live token (the thief or the
+// user) must sign in again. The access tokens issued in the family that may
+// still be valid are blacklisted.
+func (ts *tokenService) revokeFamily(ctx context.Context, reused *models.RefreshToken, userAgent, ipAddress string) {
+	familyID := reused.FamilyID
+	if familyID == 0 {
+		familyID = reused.ID
+	}
+	metrics.RefreshTokenReuse.Inc()
+
+	var family []models.RefreshToken
+	err := ts.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		tx := database.Conn(ctx, ts.db)
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("family_id = ? AND (revoked = ? OR created_at > ?)", familyID, false, time.Now().Add(-AccessTokenDuration)).
+			Find(&family).Error
+		if err != nil || len(family) == 0 {
+			return err
+		}
+		return tx.Model(&models.RefreshToken{}).
+			Where("family_id = ? AND revoked = ?", familyID, false).
+			Updates(map[string]interface{}{
+				"revoked":    true,
+				"revoked_at": time.Now(),
+			}).Error
+	})
+	if err != nil {
+		ts.log.Error("Failed to revoke the session of a reused refresh token",
+			zap.Uint("user_id", reused.UserID),
+			zap.Uint("family_id", familyID),
+			zap.Error(err),
+		)
+		return
+	}
+	ts.blacklistAccessTokens(ctx, family)
+
+	ts.log.Warn("Rotated refresh token reused; session revoked",
+		zap.Uint("user_id", reused.UserID),
+		zap.Uint("family_id", familyID),
+		zap.Uint("token_id", reused.ID),
+		zap.Timep("rotated_at", reused.RevokedAt),
+		zap.String("ip", ipAddress),
+		zap.String("user_agent", userAgent),
+	)
+}
+
+func (ts *tokenService) RevokeDeviceTokens(ctx context.Context, userID uint, deviceID string) (int64, error) {
+	result := database.Conn(ctx, ts.db).Model(&models.RefreshToken{}).
+		Where("user_id = ? AND device_id = ? AND revoked = ?", userID, deviceID, false).
+		Updates(map[string]interface{}{
+			"revoked":    true,
+			"revoked_at": time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// CleanupExpiredTokens hard-deletes tokens that expired or were revoked more
+// than the retention period ago. Rows are removed in small batches (each its
+// own statement) so the cleanup never holds long locks on the hot table; the
+// expires_at and partial revoked_at indexes keep each batch lookup cheap.
 func (ts *tokenService) CleanupExpiredTokens() (int64, error) {
-	// Delete tokens that expired more than 7 days ago
-	cutoff := time.Now().Add(-7 * 24 * time.Hour)
-	result := ts.db.Where("expires_at < ?", cutoff).Delete(&models.RefreshToken{})
+	cutoff := time.Now().Add(-ts.cleanupRetention)
+	batch := ts.db.Model(&models.RefreshToken{}).Unscoped().
+		Select("id").
+		Where("expires_at < ? OR (revoked = ? AND revoked_at < ?)", cutoff, true, cutoff).
+		Limit(ts.cleanupBatchSize)
+
+	var total int64
+	for {
+		result := ts.db.Unscoped().Where("id IN (?)", batch).Delete(&models.RefreshToken{})
+		if result.Error != nil {
+			ts.log.Error("Failed to cleanup expired tokens",
+				zap.Int64("deleted", total),
+				zap.Error(result.Error),
+			)
+			return total, result.Error
+		}
 
-	if result.Error != nil {
-		logger.Error("Failed to cleanup expired tokens", zap.Error(result.Error))
-		return 0, result.Error
+		total += result.RowsAffected
+		if result.RowsAffected < int64(ts.cleanupBatchSize) {
+			break
+		}
 	}
 
-	if result.RowsAffected > 0 {
-		logger.Info("Cleaned up expired tokens", zap.Int64("count", result.RowsAffected))
+	if total > 0 {
+		ts.log.Info("Cleaned up expired tokens", zap.Int64("count", total))
 	}
-	return result.RowsAffected, nil
+	return total, nil
+}
+
+func (ts *tokenService) BumpEpoch(ctx context.Context) (int64, error) {
+	db := database.Conn(ctx, ts.db)
+	err := db.Model(&models.TokenEpoch{}).
+		Where("id = ?", 1).
+		Updates(map[string]interface{}{
+			"epoch":      gorm.Expr("epoch + 1"),
+			"updated_at": time.Now(),
+		}).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var stored models.TokenEpoch
+	if err := db.First(&stored, 1).Error; err != nil {
+		return 0, err
+	}
+	return stored.Epoch, nil
+}
+
+func (ts *tokenService) RefreshEpoch(ctx context.Context) error {
+	var stored models.TokenEpoch
+	if err := database.Conn(ctx, ts.db).First(&stored, 1).Error; err != nil {
+		return err
+	}
+	if previous := ts.epoch.Swap(stored.Epoch); previous != stored.Epoch {
+		ts.log.Warn("Token epoch changed, older access tokens are rejected",
+			zap.Int64("previous", previous),
+			zap.Int64("epoch", stored.Epoch),
+		)
+	}
+	return nil
+}
+
+// RegisterTokenEpochSync loads the token epoch on startup and reloads it when
+// the database reports a change and every TOKEN_EPOCH_REFRESH_INTERVAL
+func RegisterTokenEpochSync(lc fx.Lifecycle, listener database.ChangeListener, tokenService TokenService, cfg *config.Config, log *zap.Logger) {
+	refresh := func(ctx context.Context) {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		if err := tokenService.RefreshEpoch(ctx); err != nil {
+			log.Warn("Failed to refresh token epoch", zap.Error(err))
+		}
+	}
+	listener.Subscribe(database.EntityTokenEpoch, func(ctx context.Context, n database.Notification) {
+		refresh(ctx)
+	})
+
+	stopChan := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			// Serving with a stale epoch would accept tokens a forced logout revoked
+			if err := tokenService.RefreshEpoch(ctx); err != nil {
+				return err
+			}
+
+			ticker := time.NewTicker(cfg.EpochRefreshInterval)
+			go func() {
+				for {
+					select {
+					case <-ticker.C:
+						refresh(context.Background())
+					case <-stopChan:
+						ticker.Stop()
+						return
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stopChan)
+			return nil
+		},
+	})
+}
+
+func (ts *tokenService) JWKS() dto.JWKS {
+	return ts.jwks
 }
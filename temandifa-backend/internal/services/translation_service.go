@@ -0,0 +1,393 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"golang.org/x/text/language"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+)
+
+// Audit actions and target type of translation changes
+const (
+	auditTargetTranslationBundle = "translation_bundle"
+	auditTranslationUploaded     = "translation_bundle.uploaded"
+	auditTranslationDeleted      = "translation_bundle.deleted"
+)
+
+const (
+	// translationVersionsKept is how many versions of a locale are kept to
+	// fetch or restore
+	translationVersionsKept = 20
+	// maxTranslationStrings caps the strings of a bundle (app and server)
+	maxTranslationStrings = 10000
+	// maxTranslationLength caps one translated string, in bytes
+	maxTranslationLength = 4096
+)
+
+// translationKeyPattern matches the keys of translated strings, e.g.
+// "home.title" or "RATE_LIMITED"
+var translationKeyPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,127}$`)
+
+// TranslationService serves versioned translation bundles of app strings
+// and server messages, so copy can be fixed without an app release. Each
+// upload adds a version of the locale; the latest versions are kept in
+// memory and other instances pick up uploads within FLAGS_REFRESH_INTERVAL.
+type TranslationService interface {
+	// Bundle returns the latest bundle of the best match for locale among
+	// the locales with one, nil when none matches
+	Bundle(locale language.Tag) *dto.TranslationBundle
+	// BundleVersion returns a version of the best match for locale, nil
+	// when there is no such version
+	BundleVersion(ctx context.Context, locale language.Tag, version int) (*dto.TranslationBundle, error)
+	// Locales lists the latest version of every locale
+	Locales() []dto.TranslationBundleVersion
+	// Versions lists the kept versions of a locale, the latest first
+	Versions(ctx context.Context, locale string) ([]dto.TranslationBundleVersion, error)
+	// Upload stores a new version of a locale's bundle
+	Upload(ctx context.Context, locale string, input dto.UploadTranslationBundleRequest) (*dto.TranslationBundleVersion, error)
+	// Restore stores a copy of an older version as the locale's latest;
+	// nil when there is no such version
+	Restore(ctx context.Context, locale string, version int) (*dto.TranslationBundleVersion, error)
+	// Delete removes every version of a locale, reporting false if it had
+	// none
+	Delete(ctx context.Context, locale string) (bool, error)
+	Refresh(ctx context.Context) error
+}
+
+// translationCatalog is the latest bundle of every locale
+type translationCatalog struct {
+	bundles  map[string]*dto.TranslationBundle // by locale
+	versions []dto.TranslationBundleVersion
+	tags     []language.Tag
+	matcher  language.Matcher
+}
+
+type translationService struct {
+	repo    repositories.TranslationRepository
+	audit   AuditService
+	tx      database.TxManager
+	catalog atomic.Pointer[translationCatalog]
+}
+
+// NewTranslationService creates a new TranslationService; bundles are
+// loaded by RegisterTranslationRefresh
+func NewTranslationService(repo repositories.TranslationRepository, audit AuditService, tx database.TxManager) TranslationService {
+	s := &translationService{repo: repo, audit: audit, tx: tx}
+	s.catalog.Store(&translationCatalog{bundles: map[string]*dto.TranslationBundle{}})
+	return s
+}
+
+// match returns the locale with a bundle that best matches tag, "" when
+// none does
+func (c *translationCatalog) match(tag language.Tag) string {
+	if len(c.tags) == 0 {
+		return ""
+	}
+	_, index, confidence := c.matcher.Match(tag)
+	if confidence == language.No {
+		return ""
+	}
+	return c.tags[index].String()
+}
+
+func (s *translationService) Bundle(locale language.Tag) *dto.TranslationBundle {
+	catalog := s.catalog.Load()
+	return catalog.bundles[catalog.match(locale)]
+}
+
+func (s *translationService) BundleVersion(ctx context.Context, locale language.Tag, version int) (*dto.TranslationBundle, error) {
+	catalog := s.catalog.Load()
+	matched := catalog.match(locale)
+	if latest := catalog.bundles[matched]; latest != nil && latest.Version == version {
+		return latest, nil
+	}
+	if matched == "" {
+		return nil, nil
+	}
+	bundle, err := s.repo.Find(ctx, matched, version)
+	if err != nil || bundle == nil {
+		return nil, err
+	}
+	return decodeTranslationBundle(bundle)
+}
+
+func (s *translationService) Locales() []dto.TranslationBundleVersion {
+	return s.catalog.Load().versions
+}
+
+func (s *translationService) Versions(ctx context.Context, locale string) ([]dto.TranslationBundleVersion, error) {
+	locale, err := normalizeLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	bundles, err := s.repo.Versions(ctx, locale)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]dto.TranslationBundleVersion, 0, len(bundles))
+	for i := range bundles {
+		decoded, err := decodeTranslationBundle(&bundles[i])
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, translationVersionOf(&bundles[i], decoded))
+	}
+	return versions, nil
+}
+
+// normalizeLocale canonicalizes a BCP 47 tag, e.g. en-us to en-US
+func normalizeLocale(locale string) (string, error) {
+	tag, err := language.Parse(locale)
+	if err != nil || tag == language.Und {
+		return "", apperrors.ValidationWithDetails("Invalid locale", map[string]string{"locale": "must be a BCP 47 language tag, e.g. id or en-US"})
+	}
+	return tag.String(), nil
+}
+
+// validateTranslations checks the keys and lengths of a bundle's strings
+func validateTranslations(input dto.UploadTranslationBundleRequest) error {
+	if len(input.App)+len(input.Server) > maxTranslationStrings {
+		return apperrors.ValidationWithDetails("Too many strings", map[string]string{"app": fmt.Sprintf("a bundle can have at most %d strings", maxTranslationStrings)})
+	}
+	for field, values := range map[string]map[string]string{"app": input.App, "server": input.Server} {
+		for key, value := range values {
+			if !translationKeyPattern.MatchString(key) {
+				return apperrors.ValidationWithDetails("Invalid key", map[string]string{field: "key " + strconv.Quote(key) + " must be 1-128 letters, digits, '.', '_' or '-'"})
+			}
+			if len(value) > maxTranslationLength {
+				return apperrors.ValidationWithDetails("String too long", map[string]string{field: fmt.Sprintf("%s is longer than %d bytes", key, maxTranslationLength)})
+			}
+		}
+	}
+	return nil
+}
+
+func (s *translationService) Upload(ctx context.Context, locale string, input dto.UploadTranslationBundleRequest) (*dto.TranslationBundleVersion, error) {
+	locale, err := normalizeLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateTranslations(input); err != nil {
+		return nil, err
+	}
+	if input.App == nil {
+		input.App = map[string]string{}
+	}
+	if input.Server == nil {
+		input.Server = map[string]string{}
+	}
+	return s.store(ctx, locale, input.App, input.Server)
+}
+
+func (s *translationService) Restore(ctx context.Context, locale string, version int) (*dto.TranslationBundleVersion, error) {
+	locale, err := normalizeLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	bundle, err := s.repo.Find(ctx, locale, version)
+	if err != nil || bundle == nil {
+		return nil, err
+	}
+	decoded, err := decodeTranslationBundle(bundle)
+	if err != nil {
+		return nil, err
+	}
+	return s.store(ctx, locale, decoded.App, decoded.Server)
+}
+
+// store adds a version of the locale's bundle, prunes old ones and reloads
+// the catalog
+func (s *translationService) store(ctx context.Context, locale string, app, server map[string]string) (*dto.TranslationBundleVersion, error) {
+	// Maps are encoded with sorted keys, so equal content has equal checksums
+	appJSON, err := json.Marshal(app)
+	if err != nil {
+		return nil, err
+	}
+	serverJSON, err := json.Marshal(server)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.New()
+	sum.Write(appJSON)
+	sum.Write([]byte{0})
+	sum.Write(serverJSON)
+
+	bundle := &models.TranslationBundle{
+		Locale:        locale,
+		AppStrings:    string(appJSON),
+		ServerStrings: string(serverJSON),
+		Checksum:      hex.EncodeToString(sum.Sum(nil)),
+		CreatedAt:     time.Now(),
+	}
+	if actorID := logger.UserIDFromContext(ctx); actorID != 0 {
+		bundle.CreatedBy = &actorID
+	}
+	version := translationVersionOf(bundle, &dto.TranslationBundle{App: app, Server: server})
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var before any
+		if latest := s.catalog.Load().bundles[locale]; latest != nil {
+			before = map[string]int{"version": latest.Version, "app_strings": len(latest.App), "server_strings": len(latest.Server)}
+		}
+		if err := s.repo.Create(ctx, bundle); err != nil {
+			return err
+		}
+		if err := s.repo.Prune(ctx, locale, translationVersionsKept); err != nil {
+			return err
+		}
+		version.Version = bundle.Version
+		after := map[string]int{"version": version.Version, "app_strings": version.AppStrings, "server_strings": version.ServerStrings}
+		return s.audit.RecordChange(ctx, auditTranslationUploaded, auditTargetTranslationBundle, locale, before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &version, s.Refresh(ctx)
+}
+
+func (s *translationService) Delete(ctx context.Context, locale string) (bool, error) {
+	locale, err := normalizeLocale(locale)
+	if err != nil {
+		return false, err
+	}
+
+	var deleted int64
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if deleted, err = s.repo.DeleteLocale(ctx, locale); err != nil || deleted == 0 {
+			return err
+		}
+		return s.audit.RecordChange(ctx, auditTranslationDeleted, auditTargetTranslationBundle, locale, map[string]int64{"versions": deleted}, nil)
+	})
+	if err != nil || deleted == 0 {
+		return false, err
+	}
+	return true, s.Refresh(ctx)
+}
+
+func (s *translationService) Refresh(ctx context.Context) error {
+	latest, err := s.repo.LatestVersions(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Only changed locales are loaded again
+	previous := s.catalog.Load()
+	catalog := &translationCatalog{
+		bundles:  make(map[string]*dto.TranslationBundle, len(latest)),
+		versions: make([]dto.TranslationBundleVersion, 0, len(latest)),
+		tags:     make([]language.Tag, 0, len(latest)),
+	}
+	for i := range latest {
+		meta := &latest[i]
+		bundle := previous.bundles[meta.Locale]
+		if bundle == nil || bundle.Version != meta.Version {
+			stored, err := s.repo.Find(ctx, meta.Locale, meta.Version)
+			if err != nil {
+				return err
+			}
+			if stored == nil {
+				continue // deleted in the meantime
+			}
+			if bundle, err = decodeTranslationBundle(stored); err != nil {
+				return err
+			}
+		}
+		catalog.bundles[meta.Locale] = bundle
+		catalog.versions = append(catalog.versions, translationVersionOf(meta, bundle))
+		catalog.tags = append(catalog.tags, language.Make(meta.Locale))
+	}
+	catalog.matcher = language.NewMatcher(catalog.tags)
+	s.catalog.Store(catalog)
+	return nil
+}
+
+// decodeTranslationBundle parses a stored bundle's strings
+func decodeTranslationBundle(bundle *models.TranslationBundle) (*dto.TranslationBundle, error) {
+	decoded := &dto.TranslationBundle{
+		Locale:    bundle.Locale,
+		Version:   bundle.Version,
+		UpdatedAt: bundle.CreatedAt,
+		ETag:      translationETag(bundle),
+	}
+	if err := json.Unmarshal([]byte(bundle.AppStrings), &decoded.App); err != nil {
+		return nil, fmt.Errorf("translation bundle %s v%d: %w", bundle.Locale, bundle.Version, err)
+	}
+	if err := json.Unmarshal([]byte(bundle.ServerStrings), &decoded.Server); err != nil {
+		return nil, fmt.Errorf("translation bundle %s v%d: %w", bundle.Locale, bundle.Version, err)
+	}
+	return decoded, nil
+}
+
+// translationETag is a strong validator of a bundle version; restored
+// content gets a new one because its version differs
+func translationETag(bundle *models.TranslationBundle) string {
+	return fmt.Sprintf(`"%s-%d-%.16s"`, bundle.Locale, bundle.Version, bundle.Checksum)
+}
+
+// translationVersionOf describes a bundle version
+func translationVersionOf(bundle *models.TranslationBundle, decoded *dto.TranslationBundle) dto.TranslationBundleVersion {
+	return dto.TranslationBundleVersion{
+		Locale:        bundle.Locale,
+		Version:       bundle.Version,
+		AppStrings:    len(decoded.App),
+		ServerStrings: len(decoded.Server),
+		Checksum:      bundle.Checksum,
+		CreatedBy:     bundle.CreatedBy,
+		CreatedAt:     bundle.CreatedAt,
+	}
+}
+
+// RegisterTranslationRefresh loads the translation bundles on startup and
+// reloads changed ones with the feature flags, every FLAGS_REFRESH_INTERVAL
+func RegisterTranslationRefresh(lc fx.Lifecycle, translations TranslationService, cfg *config.Config, log *zap.Logger) {
+	stopChan := make(chan struct{})
+
+	refresh := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := translations.Refresh(ctx); err != nil {
+			log.Warn("Failed to refresh translation bundles", zap.Error(err))
+		}
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			refresh()
+
+			ticker := time.NewTicker(cfg.FeatureFlagsRefreshInterval)
+			go func() {
+				for {
+					select {
+					case <-ticker.C:
+						refresh()
+					case <-stopChan:
+						ticker.Stop()
+						return
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stopChan)
+			return nil
+		},
+	})
+}
@@ -0,0 +1,325 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/database"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/storage"
+)
+
+// uploadPurgeInterval controls how often expired uploads are deleted
+const uploadPurgeInterval = time.Hour
+
+// UploadService keeps resumable (tus) uploads: audio recordings too long to
+// send in one request are uploaded in chunks, each stored as an object under
+// RESUMABLE_UPLOAD_PREFIX, and transcribed once complete. Uploads are
+// limited to UPLOAD_TRANSCRIBE_MAX_SIZE and expire after RESUMABLE_UPLOAD_TTL.
+type UploadService interface {
+	// MaxSize is the largest Upload-Length accepted
+	MaxSize() int64
+	// Create starts an upload of length bytes. metadata is the tus
+	// Upload-Metadata header; its filename entry names the upload.
+	Create(ctx context.Context, userID uint, length int64, metadata string) (*models.Upload, error)
+	// Find returns nil when the user has no such upload or it expired
+	Find(ctx context.Context, userID uint, id string) (*models.Upload, error)
+	// Append stores r, which holds the bytes from offset on, and returns the
+	// upload with its new offset. size is -1 when unknown. The offset must be
+	// the upload's current one.
+	Append(ctx context.Context, upload *models.Upload, offset int64, r io.Reader, size int64) (*models.Upload, error)
+	// Read returns a complete upload's content. The upload is nil when the
+	// user has no such upload.
+	Read(ctx context.Context, userID uint, id string) (*models.Upload, []byte, error)
+	// Delete removes an upload and its bytes; false when there was none
+	Delete(ctx context.Context, userID uint, id string) (bool, error)
+	// PurgeExpired deletes expired uploads and returns how many objects were
+	// deleted
+	PurgeExpired(ctx context.Context) (int, error)
+}
+
+type uploadService struct {
+	db      *gorm.DB
+	store   storage.ObjectStore
+	prefix  string
+	ttl     time.Duration
+	maxSize int64
+}
+
+// NewUploadService creates a new UploadService
+func NewUploadService(db *gorm.DB, store storage.ObjectStore, cfg *config.Config, uploads *config.UploadConfig) UploadService {
+	return &uploadService{
+		db:      db,
+		store:   store,
+		prefix:  cfg.ResumableUploadPrefix,
+		ttl:     cfg.ResumableUploadTTL,
+		maxSize: uploads.For(config.UploadOpTranscribe).MaxSize,
+	}
+}
+
+// errUploadOffset reports a PATCH that doesn't continue where the upload is
+var errUploadOffset = apperrors.NewAppError(apperrors.ErrCodeConflict, "Upload-Offset does not match the upload's offset", http.StatusConflict)
+
+func (s *uploadService) MaxSize() int64 {
+	return s.maxSize
+}
+
+func (s *uploadService) Create(ctx context.Context, userID uint, length int64, metadata string) (*models.Upload, error) {
+	if s.store == nil {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeServiceUnavailable, "Resumable uploads need object storage", http.StatusServiceUnavailable)
+	}
+	if length <= 0 {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeValidation, "Upload-Length must be positive", http.StatusBadRequest)
+	}
+	if length > s.maxSize {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeFileTooLarge, fmt.Sprintf("Upload-Length exceeds the maximum of %d bytes", s.maxSize), http.StatusRequestEntityTooLarge)
+	}
+	values, err := parseUploadMetadata(metadata)
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeInvalidFormat, err.Error(), http.StatusBadRequest)
+	}
+
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	upload := &models.Upload{
+		ID:        hex.EncodeToString(b),
+		UserID:    userID,
+		Length:    length,
+		Metadata:  metadata,
+		Filename:  values["filename"],
+		ExpiresAt: time.Now().Add(s.ttl),
+	}
+	if err := database.Conn(ctx, s.db).Create(upload).Error; err != nil {
+		return nil, err
+	}
+	return upload, nil
+}
+
+// parseUploadMetadata decodes a tus Upload-Metadata header: comma-separated
+// pairs of a key and an optional base64 value
+func parseUploadMetadata(header string) (map[string]string, error) {
+	values := map[string]string{}
+	if strings.TrimSpace(header) == "" {
+		return values, nil
+	}
+	for _, pair := range strings.Split(header, ",") {
+		key, encoded, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key == "" {
+			return nil, errors.New("Upload-Metadata has an empty key")
+		}
+		value, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("Upload-Metadata value of %q is not base64", key)
+		}
+		values[key] = string(value)
+	}
+	return values, nil
+}
+
+func (s *uploadService) Find(ctx context.Context, userID uint, id string) (*models.Upload, error) {
+	var upload models.Upload
+	err := database.Conn(ctx, s.db).
+		Where("id = ? AND user_id = ? AND expires_at > ?", id, userID, time.Now()).
+		Limit(1).Find(&upload).Error
+	if err != nil || upload.ID == "" {
+		return nil, err
+	}
+	return &upload, nil
+}
+
+// newChunk names the object holding the bytes of one PATCH from offset on.
+// Names are unique so a PATCH losing a race never overwrites the winner's.
+func newChunk(offset int64) string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("%020d-%s", offset, hex.EncodeToString(b))
+}
+
+func (s *uploadService) chunkKey(id, chunk string) string {
+	return s.prefix + id + "/" + chunk
+}
+
+func (s *uploadService) Append(ctx context.Context, upload *models.Upload, offset int64, r io.Reader, size int64) (*models.Upload, error) {
+	if offset != upload.Offset {
+		return nil, errUploadOffset
+	}
+	remaining := upload.Length - offset
+	if size > remaining {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeFileTooLarge, "The chunk exceeds Upload-Length", http.StatusRequestEntityTooLarge)
+	}
+	if size == 0 || remaining == 0 {
+		return upload, nil
+	}
+
+	// A body cut off by the network is not stored, so the client resumes
+	// from the start of the chunk
+	chunk := newChunk(offset)
+	key := s.chunkKey(upload.ID, chunk)
+	written, err := s.store.Put(ctx, key, io.LimitReader(r, remaining), size, "application/offset+octet-stream")
+	if err != nil {
+		return nil, err
+	}
+	discard := func() { _ = s.store.Delete(context.WithoutCancel(ctx), key) }
+	if size < 0 {
+		if n, _ := r.Read(make([]byte, 1)); n > 0 {
+			discard()
+			return nil, apperrors.NewAppError(apperrors.ErrCodeFileTooLarge, "The chunk exceeds Upload-Length", http.StatusRequestEntityTooLarge)
+		}
+	}
+	if written == 0 {
+		discard()
+		return upload, nil
+	}
+
+	updated := *upload
+	updated.Offset += written
+	updated.Chunks += chunk + "\n"
+	updates := map[string]any{
+		"upload_offset": updated.Offset,
+		"chunks":        gorm.Expr("chunks || ?", chunk+"\n"),
+		"updated_at":    time.Now(),
+	}
+	if updated.Complete() {
+		now := time.Now()
+		updated.CompletedAt = &now
+		updates["completed_at"] = now
+	}
+	// Only one of concurrent PATCHes at the same offset wins
+	result := database.Conn(ctx, s.db).Model(&models.Upload{}).
+		Where("id = ? AND upload_offset = ?", upload.ID, offset).
+		Updates(updates)
+	if result.Error != nil || result.RowsAffected == 0 {
+		discard()
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		return nil, errUploadOffset
+	}
+	return &updated, nil
+}
+
+func (s *uploadService) Read(ctx context.Context, userID uint, id string) (*models.Upload, []byte, error) {
+	upload, err := s.Find(ctx, userID, id)
+	if err != nil || upload == nil {
+		return nil, nil, err
+	}
+	if !upload.Complete() {
+		return nil, nil, apperrors.NewAppError(apperrors.ErrCodeConflict, "The upload is not complete", http.StatusConflict).
+			WithDetails(map[string]int64{"offset": upload.Offset, "length": upload.Length})
+	}
+
+	var content bytes.Buffer
+	content.Grow(int(upload.Length))
+	for _, chunk := range strings.Fields(upload.Chunks) {
+		if err := s.readChunk(ctx, s.chunkKey(upload.ID, chunk), &content); err != nil {
+			return nil, nil, fmt.Errorf("upload %s: %w", upload.ID, err)
+		}
+	}
+	if int64(content.Len()) != upload.Length {
+		return nil, nil, fmt.Errorf("upload %s: stored %d bytes of %d", upload.ID, content.Len(), upload.Length)
+	}
+	return upload, content.Bytes(), nil
+}
+
+func (s *uploadService) readChunk(ctx context.Context, key string, dst *bytes.Buffer) error {
+	r, err := s.store.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = r.Close() }()
+	_, err = dst.ReadFrom(r)
+	return err
+}
+
+func (s *uploadService) Delete(ctx context.Context, userID uint, id string) (bool, error) {
+	upload, err := s.Find(ctx, userID, id)
+	if err != nil || upload == nil {
+		return false, err
+	}
+	if _, err := storage.DeletePrefix(ctx, s.store, s.prefix+upload.ID+"/"); err != nil {
+		return false, err
+	}
+	return true, database.Conn(ctx, s.db).Delete(&models.Upload{}, "id = ?", upload.ID).Error
+}
+
+func (s *uploadService) PurgeExpired(ctx context.Context) (int, error) {
+	if s.store == nil {
+		return 0, nil
+	}
+
+	// Every chunk is written before its upload expires, so chunks older than
+	// the TTL belong to expired uploads (or to deleted users)
+	objects, err := s.store.List(ctx, s.prefix)
+	if err != nil {
+		return 0, err
+	}
+	cutoff := time.Now().Add(-s.ttl)
+	deleted := 0
+	for _, obj := range objects {
+		if obj.LastModified.After(cutoff) {
+			continue
+		}
+		if err := s.store.Delete(ctx, obj.Key); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+
+	err = s.db.WithContext(ctx).Where("expires_at < ?", time.Now()).Delete(&models.Upload{}).Error
+	return deleted, err
+}
+
+// RegisterUploadJobs deletes expired uploads hourly
+func RegisterUploadJobs(lc fx.Lifecycle, uploads UploadService, log *zap.Logger) {
+	stopChan := make(chan struct{})
+
+	purge := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		deleted, err := uploads.PurgeExpired(ctx)
+		if err != nil {
+			log.Warn("Failed to purge expired uploads", zap.Int("deleted", deleted), zap.Error(err))
+			return
+		}
+		if deleted > 0 {
+			log.Info("Expired uploads purged", zap.Int("deleted_objects", deleted))
+		}
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			ticker := time.NewTicker(uploadPurgeInterval)
+			go func() {
+				for {
+					select {
+					case <-ticker.C:
+						purge()
+					case <-stopChan:
+						ticker.Stop()
+						return
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stopChan)
+			return nil
+		},
+	})
+}
@@ -0,0 +1,374 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+)
+
+const (
+	monthLayout = "2006-01"
+
+	// usageReportInterval controls how often the job checks whether last
+	// month's reports still need generating
+	usageReportInterval = time.Hour
+	// usageReportExportPage is how many reports a billing export reads at once
+	usageReportExportPage = 500
+)
+
+// reportOperation are the counters of one AI operation stored in a report
+type reportOperation struct {
+	Requests  int64 `json:"requests"`
+	CacheHits int64 `json:"cache_hits"`
+	Errors    int64 `json:"errors"`
+}
+
+// UsageReportService compiles per-user monthly usage reports (AI calls by
+// operation, cache hits and attachment storage) once a UTC month has ended.
+// Users download their own; administrators export a month's for billing.
+type UsageReportService interface {
+	// Generate compiles every user's report of month (YYYY-MM), which must
+	// have ended, replacing existing ones, and returns how many it stored
+	Generate(ctx context.Context, month string) (int, error)
+	// GeneratePending generates last month's reports unless they exist
+	GeneratePending(ctx context.Context) (int, error)
+	// List returns the user's reports, the latest month first
+	List(ctx context.Context, userID uint) ([]dto.UsageReport, error)
+	// Find returns nil when the user has no report of month
+	Find(ctx context.Context, userID uint, month string) (*dto.UsageReport, error)
+	// Month returns a page of month's reports with the users' emails
+	Month(ctx context.Context, month string, page, limit int) ([]dto.UsageReport, int64, error)
+	// ExportMonth writes every report of month with the users' emails as CSV
+	ExportMonth(ctx context.Context, month string, w io.Writer) error
+}
+
+type usageReportService struct {
+	repo  repositories.UsageReportRepository
+	usage repositories.UsageRepository
+}
+
+// NewUsageReportService creates a new UsageReportService
+func NewUsageReportService(repo repositories.UsageReportRepository, usage repositories.UsageRepository) UsageReportService {
+	return &usageReportService{repo: repo, usage: usage}
+}
+
+// ParseMonth validates a YYYY-MM month and returns its first day in UTC
+func ParseMonth(month string) (time.Time, error) {
+	from, err := time.Parse(monthLayout, month)
+	if err != nil {
+		return time.Time{}, apperrors.ValidationWithDetails("Invalid month", map[string]string{"month": "must be a month (YYYY-MM)"})
+	}
+	return from, nil
+}
+
+func (s *usageReportService) Generate(ctx context.Context, month string) (int, error) {
+	from, err := ParseMonth(month)
+	if err != nil {
+		return 0, err
+	}
+	to := from.AddDate(0, 1, 0)
+	if to.After(time.Now()) {
+		return 0, apperrors.ValidationWithDetails("Invalid month", map[string]string{"month": "must have ended"})
+	}
+
+	usage, err := s.usage.UserOperations(ctx, from, to)
+	if err != nil {
+		return 0, err
+	}
+	storage, err := s.repo.StorageByUser(ctx, to)
+	if err != nil {
+		return 0, err
+	}
+
+	// Every user who used the AI or stored attachments gets a report
+	reports := make(map[uint]*models.UsageReport)
+	operations := make(map[uint]map[string]reportOperation)
+	report := func(userID uint) *models.UsageReport {
+		if reports[userID] == nil {
+			reports[userID] = &models.UsageReport{UserID: userID, Month: month}
+			operations[userID] = make(map[string]reportOperation)
+		}
+		return reports[userID]
+	}
+	for _, row := range usage {
+		r := report(row.UserID)
+		r.Requests += row.Requests
+		r.CacheHits += row.CacheHits
+		r.Errors += row.Errors
+		operations[row.UserID][row.Operation] = reportOperation{Requests: row.Requests, CacheHits: row.CacheHits, Errors: row.Errors}
+	}
+	for _, row := range storage {
+		r := report(row.UserID)
+		r.StorageBytes = row.Bytes
+		r.Attachments = row.Attachments
+	}
+
+	userIDs := make([]uint, 0, len(reports))
+	for userID := range reports {
+		userIDs = append(userIDs, userID)
+	}
+	slices.Sort(userIDs)
+	plans, err := s.repo.Plans(ctx, userIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	rows := make([]models.UsageReport, 0, len(reports))
+	for _, userID := range userIDs {
+		r := reports[userID]
+		encoded, err := json.Marshal(operations[userID])
+		if err != nil {
+			return 0, err
+		}
+		r.Operations = string(encoded)
+		r.Plan = plans[userID]
+		r.GeneratedAt = now
+		rows = append(rows, *r)
+	}
+	if err := s.repo.Save(ctx, rows); err != nil {
+		return 0, err
+	}
+	return len(rows), nil
+}
+
+// PreviousMonth is last UTC month (YYYY-MM)
+func PreviousMonth() string {
+	now := time.Now().UTC()
+	return now.AddDate(0, 0, -now.Day()).Format(monthLayout)
+}
+
+func (s *usageReportService) GeneratePending(ctx context.Context) (int, error) {
+	month := PreviousMonth()
+	count, err := s.repo.Count(ctx, month)
+	if err != nil || count > 0 {
+		return 0, err
+	}
+	return s.Generate(ctx, month)
+}
+
+func (s *usageReportService) List(ctx context.Context, userID uint) ([]dto.UsageReport, error) {
+	rows, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	reports := make([]dto.UsageReport, len(rows))
+	for i, row := range rows {
+		reports[i] = toUsageReportDTO(row, "")
+	}
+	return reports, nil
+}
+
+func (s *usageReportService) Find(ctx context.Context, userID uint, month string) (*dto.UsageReport, error) {
+	if _, err := ParseMonth(month); err != nil {
+		return nil, err
+	}
+	row, err := s.repo.Find(ctx, userID, month)
+	if err != nil || row == nil {
+		return nil, err
+	}
+	report := toUsageReportDTO(*row, "")
+	return &report, nil
+}
+
+func (s *usageReportService) Month(ctx context.Context, month string, page, limit int) ([]dto.UsageReport, int64, error) {
+	if _, err := ParseMonth(month); err != nil {
+		return nil, 0, err
+	}
+	rows, total, err := s.repo.Month(ctx, month, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	reports := make([]dto.UsageReport, len(rows))
+	for i, row := range rows {
+		reports[i] = toUsageReportDTO(row.UsageReport, row.Email)
+	}
+	return reports, total, nil
+}
+
+func (s *usageReportService) ExportMonth(ctx context.Context, month string, w io.Writer) error {
+	if _, err := ParseMonth(month); err != nil {
+		return err
+	}
+
+	out := csv.NewWriter(w)
+	if err := out.Write(usageReportCSVHeader(true)); err != nil {
+		return err
+	}
+	for page := 1; ; page++ {
+		rows, _, err := s.repo.Month(ctx, month, page, usageReportExportPage)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if err := out.Write(usageReportCSVRecord(toUsageReportDTO(row.UsageReport, row.Email), true)); err != nil {
+				return err
+			}
+		}
+		if len(rows) < usageReportExportPage {
+			break
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// WriteUsageReportsCSV writes reports as CSV, with the users' emails when
+// withEmail is set
+func WriteUsageReportsCSV(w io.Writer, reports []dto.UsageReport, withEmail bool) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(usageReportCSVHeader(withEmail)); err != nil {
+		return err
+	}
+	for _, report := range reports {
+		if err := out.Write(usageReportCSVRecord(report, withEmail)); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// usageReportCSVHeader names the CSV columns: the totals, then requests and
+// cache hits of every AI operation, so the columns don't depend on usage
+func usageReportCSVHeader(withEmail bool) []string {
+	header := []string{"month", "user_id"}
+	if withEmail {
+		header = append(header, "email")
+	}
+	header = append(header, "plan", "requests", "cache_hits", "cache_hit_rate", "errors")
+	for _, operation := range config.AIOperations {
+		header = append(header, operation+"_requests", operation+"_cache_hits")
+	}
+	return append(header, "storage_bytes", "attachments", "generated_at")
+}
+
+func usageReportCSVRecord(report dto.UsageReport, withEmail bool) []string {
+	record := []string{report.Month, strconv.FormatUint(uint64(report.UserID), 10)}
+	if withEmail {
+		record = append(record, report.Email)
+	}
+	record = append(record,
+		report.Plan,
+		strconv.FormatInt(report.Requests, 10),
+		strconv.FormatInt(report.CacheHits, 10),
+		strconv.FormatFloat(report.CacheHitRate, 'f', 4, 64),
+		strconv.FormatInt(report.Errors, 10),
+	)
+	for _, operation := range config.AIOperations {
+		var stats dto.UsageStats
+		for _, usage := range report.Operations {
+			if usage.Operation == operation {
+				stats = usage.UsageStats
+			}
+		}
+		record = append(record, strconv.FormatInt(stats.Requests, 10), strconv.FormatInt(stats.CacheHits, 10))
+	}
+	return append(record,
+		strconv.FormatInt(report.StorageBytes, 10),
+		strconv.FormatInt(report.Attachments, 10),
+		report.GeneratedAt.UTC().Format(time.RFC3339),
+	)
+}
+
+// toUsageReportDTO lists every AI operation, in their usual order, then any
+// other operation the report has
+func toUsageReportDTO(row models.UsageReport, email string) dto.UsageReport {
+	var stored map[string]reportOperation
+	_ = json.Unmarshal([]byte(row.Operations), &stored)
+
+	names := append([]string(nil), config.AIOperations...)
+	var others []string
+	for name := range stored {
+		if !slices.Contains(names, name) {
+			others = append(others, name)
+		}
+	}
+	sort.Strings(others)
+
+	operations := make([]dto.OperationUsage, 0, len(names)+len(others))
+	for _, name := range append(names, others...) {
+		counters := stored[name]
+		operations = append(operations, dto.OperationUsage{
+			Operation: name,
+			UsageStats: usageStats(repositories.UsageSummary{
+				Requests:  counters.Requests,
+				CacheHits: counters.CacheHits,
+				Errors:    counters.Errors,
+			}),
+		})
+	}
+
+	return dto.UsageReport{
+		Month:  row.Month,
+		UserID: row.UserID,
+		Email:  email,
+		Plan:   row.Plan,
+		UsageStats: usageStats(repositories.UsageSummary{
+			Requests:  row.Requests,
+			CacheHits: row.CacheHits,
+			Errors:    row.Errors,
+		}),
+		Operations:   operations,
+		StorageBytes: row.StorageBytes,
+		Attachments:  row.Attachments,
+		GeneratedAt:  row.GeneratedAt,
+	}
+}
+
+// RegisterUsageReportJob generates last month's usage reports shortly after
+// startup and the month's end. Instances racing for a month store the same
+// reports.
+func RegisterUsageReportJob(lc fx.Lifecycle, reports UsageReportService, log *zap.Logger) {
+	stopChan := make(chan struct{})
+
+	generate := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+		count, err := reports.GeneratePending(ctx)
+		if err != nil {
+			log.Error("Failed to generate usage reports", zap.Error(err))
+			return
+		}
+		if count > 0 {
+			log.Info("Usage reports generated", zap.Int("reports", count))
+		}
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			ticker := time.NewTicker(usageReportInterval)
+			go func() {
+				generate()
+				for {
+					select {
+					case <-ticker.C:
+						generate()
+					case <-stopChan:
+						ticker.Stop()
+						return
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stopChan)
+			return nil
+		},
+	})
+}
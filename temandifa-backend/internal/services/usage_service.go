@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/repositories"
+)
+
+const (
+	dayLayout = "2006-01-02"
+
+	// defaultAnalyticsDays is the range reported when none is given
+	defaultAnalyticsDays = 30
+	// maxAnalyticsDays bounds a range so one request can't scan years of rows
+	maxAnalyticsDays = 366
+	// defaultTopUsers is how many users TopUsers reports by default
+	defaultTopUsers = 10
+)
+
+// UsageService accounts AI requests per user, operation and UTC day and
+// reports aggregates to operators. Aggregates are cached for
+// CACHE_ANALYTICS_TTL, so today's numbers may lag by that much.
+type UsageService interface {
+	// Record counts one request in the background; failures are only logged
+	Record(userID uint, operation string, cacheHit, failed bool)
+	Daily(ctx context.Context, query dto.AnalyticsQuery) ([]dto.DailyUsage, error)
+	Operations(ctx context.Context, query dto.AnalyticsQuery) ([]dto.OperationUsage, error)
+	TopUsers(ctx context.Context, query dto.AnalyticsQuery) ([]dto.UserUsage, error)
+}
+
+type usageService struct {
+	repo  repositories.UsageRepository
+	cache CacheService
+	ttl   time.Duration
+	log   *zap.Logger
+}
+
+// NewUsageService creates a new UsageService
+func NewUsageService(repo repositories.UsageRepository, cache CacheService, cfg *config.CacheConfig, log *zap.Logger) UsageService {
+	return &usageService{repo: repo, cache: cache, ttl: cfg.AnalyticsTTL, log: log}
+}
+
+func (s *usageService) Record(userID uint, operation string, cacheHit, failed bool) {
+	day := time.Now().UTC().Truncate(24 * time.Hour)
+	go func() {
+		// Detached from the request, which has already been answered
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.repo.Increment(ctx, day, userID, operation, cacheHit, failed); err != nil {
+			s.log.Warn("Failed to record usage",
+				zap.Uint("user_id", userID),
+				zap.String("operation", operation),
+				zap.Error(err),
+			)
+		}
+	}()
+}
+
+// usageRange resolves the query's days to [from, to) in UTC
+func usageRange(query dto.AnalyticsQuery) (time.Time, time.Time, error) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	to := today
+	if query.To != "" {
+		parsed, err := time.Parse(dayLayout, query.To)
+		if err != nil {
+			return time.Time{}, time.Time{}, apperrors.ValidationWithDetails("Invalid date range", map[string]string{"to": "must be a date (YYYY-MM-DD)"})
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, 1-defaultAnalyticsDays)
+	if query.From != "" {
+		parsed, err := time.Parse(dayLayout, query.From)
+		if err != nil {
+			return time.Time{}, time.Time{}, apperrors.ValidationWithDetails("Invalid date range", map[string]string{"from": "must be a date (YYYY-MM-DD)"})
+		}
+		from = parsed
+	}
+
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, apperrors.ValidationWithDetails("Invalid date range", map[string]string{"to": "must not be before from"})
+	}
+	if to.Sub(from) >= maxAnalyticsDays*24*time.Hour {
+		return time.Time{}, time.Time{}, apperrors.ValidationWithDetails("Invalid date range", map[string]string{"from": fmt.Sprintf("range must not exceed %d days", maxAnalyticsDays)})
+	}
+	return from, to.AddDate(0, 0, 1), nil
+}
+
+func usageStats(row repositories.UsageSummary) dto.UsageStats {
+	stats := dto.UsageStats{
+		Requests:  row.Requests,
+		CacheHits: row.CacheHits,
+		Errors:    row.Errors,
+	}
+	if row.Requests > 0 {
+		stats.CacheHitRate = float64(row.CacheHits) / float64(row.Requests)
+	}
+	return stats
+}
+
+// cachedReport serves the report named key for [from, to) from the cache, or
+// builds it with load and caches it
+func cachedReport[T any](ctx context.Context, s *usageService, key string, from, to time.Time, load func() ([]T, error)) ([]T, error) {
+	key = fmt.Sprintf("analytics:%s:%s:%s", key, from.Format(dayLayout), to.Format(dayLayout))
+
+	var report []T
+	if s.cache.GetJSON(ctx, key, &report) {
+		return report, nil
+	}
+	report, err := load()
+	if err != nil {
+		return nil, err
+	}
+	_ = s.cache.SetJSON(ctx, key, report, s.ttl)
+	return report, nil
+}
+
+func (s *usageService) Daily(ctx context.Context, query dto.AnalyticsQuery) ([]dto.DailyUsage, error) {
+	from, to, err := usageRange(query)
+	if err != nil {
+		return nil, err
+	}
+	return cachedReport(ctx, s, "daily", from, to, func() ([]dto.DailyUsage, error) {
+		rows, err := s.repo.Daily(ctx, from, to)
+		if err != nil {
+			return nil, err
+		}
+		report := make([]dto.DailyUsage, len(rows))
+		for i, row := range rows {
+			report[i] = dto.DailyUsage{Day: row.Day.UTC().Format(dayLayout), UsageStats: usageStats(row)}
+		}
+		return report, nil
+	})
+}
+
+func (s *usageService) Operations(ctx context.Context, query dto.AnalyticsQuery) ([]dto.OperationUsage, error) {
+	from, to, err := usageRange(query)
+	if err != nil {
+		return nil, err
+	}
+	return cachedReport(ctx, s, "operations", from, to, func() ([]dto.OperationUsage, error) {
+		rows, err := s.repo.ByOperation(ctx, from, to)
+		if err != nil {
+			return nil, err
+		}
+		report := make([]dto.OperationUsage, len(rows))
+		for i, row := range rows {
+			report[i] = dto.OperationUsage{Operation: row.Operation, UsageStats: usageStats(row)}
+		}
+		return report, nil
+	})
+}
+
+func (s *usageService) TopUsers(ctx context.Context, query dto.AnalyticsQuery) ([]dto.UserUsage, error) {
+	from, to, err := usageRange(query)
+	if err != nil {
+		return nil, err
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultTopUsers
+	}
+	return cachedReport(ctx, s, fmt.Sprintf("top-users:%d", limit), from, to, func() ([]dto.UserUsage, error) {
+		rows, err := s.repo.TopUsers(ctx, from, to, limit)
+		if err != nil {
+			return nil, err
+		}
+		report := make([]dto.UserUsage, len(rows))
+		for i, row := range rows {
+			report[i] = dto.UserUsage{UserID: row.UserID, Email: row.Email, UsageStats: usageStats(row)}
+		}
+		return report, nil
+	})
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+
+	"temandifa-backend/internal/dto"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+)
+
+// userSortFields maps sortable API fields to user columns
+var userSortFields = map[string]string{
+	"id":         "id",
+	"email":      "email",
+	"full_name":  "full_name",
+	"role":       "role",
+	"created_at": "created_at",
+	"deleted_at": "deleted_at",
+}
+
+// UserAdminService provides user management for administrators
+type UserAdminService interface {
+	SearchUsers(ctx context.Context, query dto.UserSearchQuery, page, limit int) ([]models.User, int64, error)
+}
+
+type userAdminService struct {
+	userRepo repositories.UserRepository
+}
+
+// NewUserAdminService creates a new UserAdminService
+func NewUserAdminService(userRepo repositories.UserRepository) UserAdminService {
+	return &userAdminService{userRepo: userRepo}
+}
+
+func (s *userAdminService) SearchUsers(ctx context.Context, query dto.UserSearchQuery, page, limit int) ([]models.User, int64, error) {
+	spec := repositories.NewSpec().
+		Search(query.Query, "email", "full_name").
+		WhereIf(query.Role != "", "role = ?", query.Role)
+
+	switch query.Status {
+	case dto.UserStatusDeleted:
+		spec.Where("deleted_at IS NOT NULL")
+	case dto.UserStatusAll:
+	default:
+		spec.Where("deleted_at IS NULL")
+	}
+
+	spec.SortBy(query.Sort, userSortFields)
+	if !spec.Sorted() {
+		spec.OrderBy("created_at", true)
+	}
+	// Tie-breaker keeps pages stable when the sort column has duplicates
+	spec.OrderBy("id", false)
+
+	return s.userRepo.List(ctx, spec.Paginate(limit, (page-1)*limit))
+}
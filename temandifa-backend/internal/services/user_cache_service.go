@@ -9,23 +9,27 @@ import (
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/database"
 	"temandifa-backend/internal/logger"
 	"temandifa-backend/internal/models"
 )
 
 const (
-	// UserCacheTTL is the time-to-live for cached user data
-	UserCacheTTL = 5 * time.Minute
 	// UserCachePrefix is the Redis key prefix for user cache
 	UserCachePrefix = "user:"
 )
 
 // CachedUser contains the minimal user data needed for auth
 type CachedUser struct {
-	ID       uint   `json:"id"`
-	Email    string `json:"email"`
-	FullName string `json:"full_name"`
-	Role     string `json:"role"`
+	ID                uint       `json:"id"`
+	Email             string     `json:"email"`
+	FullName          string     `json:"full_name"`
+	ProfilePicture    string     `json:"profile_picture,omitempty"`
+	PreferredLanguage string     `json:"preferred_language,omitempty"`
+	Role              string     `json:"role"`
+	Plan              string     `json:"plan"`
+	EmailVerifiedAt   *time.Time `json:"email_verified_at,omitempty"`
 }
 
 // UserCacheService handles user caching operations
@@ -33,15 +37,19 @@ type UserCacheService interface {
 	GetCachedUser(ctx context.Context, userID uint) (*CachedUser, error)
 	SetCachedUser(ctx context.Context, user *models.User) error
 	InvalidateUserCache(ctx context.Context, userID uint) error
+	// InvalidateAll removes every cached user
+	InvalidateAll(ctx context.Context) error
 }
 
 type userCacheService struct {
 	client *redis.Client
+	ttl    time.Duration
 }
 
-// NewUserCacheService creates a new UserCacheService with Redis client
-func NewUserCacheService(client *redis.Client) UserCacheService {
-	return &userCacheService{client: client}
+// NewUserCacheService creates a new UserCacheService with Redis client;
+// entries live for CACHE_USER_TTL
+func NewUserCacheService(client *redis.Client, cacheCfg *config.CacheConfig) UserCacheService {
+	return &userCacheService{client: client, ttl: cacheCfg.UserTTL}
 }
 
 // GetCachedUser retrieves a user from cache by ID
@@ -61,7 +69,7 @@ func (s *userCacheService) GetCachedUser(ctx context.Context, userID uint) (*Cac
 		return nil, err
 	}
 
-	logger.Debug("User cache hit", zap.Uint("user_id", userID))
+	logger.Ctx(ctx).Debug("User cache hit", zap.Uint("user_id", userID))
 	return &user, nil
 }
 
@@ -72,10 +80,14 @@ func (s *userCacheService) SetCachedUser(ctx context.Context, user *models.User)
 	}
 
 	cached := CachedUser{
-		ID:       user.ID,
-		Email:    user.Email,
-		FullName: user.FullName,
-		Role:     user.Role,
+		ID:                user.ID,
+		Email:             user.Email,
+		FullName:          user.FullName,
+		ProfilePicture:    user.ProfilePicture,
+		PreferredLanguage: user.PreferredLanguage,
+		Role:              user.Role,
+		Plan:              user.Plan,
+		EmailVerifiedAt:   user.EmailVerifiedAt,
 	}
 
 	data, err := json.Marshal(cached)
@@ -84,7 +96,7 @@ func (s *userCacheService) SetCachedUser(ctx context.Context, user *models.User)
 	}
 
 	key := fmt.Sprintf("%s%d", UserCachePrefix, user.ID)
-	return s.client.Set(ctx, key, data, UserCacheTTL).Err()
+	return s.client.Set(ctx, key, data, s.ttl).Err()
 }
 
 // InvalidateUserCache removes a user from cache
@@ -96,3 +108,50 @@ func (s *userCacheService) InvalidateUserCache(ctx context.Context, userID uint)
 	key := fmt.Sprintf("%s%d", UserCachePrefix, userID)
 	return s.client.Del(ctx, key).Err()
 }
+
+// InvalidateAll removes every cached user
+func (s *userCacheService) InvalidateAll(ctx context.Context) error {
+	if s.client == nil {
+		return nil
+	}
+
+	iter := s.client.Scan(ctx, 0, UserCachePrefix+"*", 500).Iterator()
+	keys := make([]string, 0, 500)
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) == cap(keys) {
+			if err := s.client.Unlink(ctx, keys...).Err(); err != nil {
+				return err
+			}
+			keys = keys[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	if len(keys) > 0 {
+		return s.client.Unlink(ctx, keys...).Err()
+	}
+	return nil
+}
+
+// RegisterUserCacheInvalidation drops cached users when the database reports
+// a change, so role changes and deletions take effect on every instance
+// without waiting for CACHE_USER_TTL
+func RegisterUserCacheInvalidation(listener database.ChangeListener, userCache UserCacheService, log *zap.Logger) {
+	listener.Subscribe(database.EntityUser, func(ctx context.Context, n database.Notification) {
+		var err error
+		if n.Op == database.OpResync {
+			err = userCache.InvalidateAll(ctx)
+		} else {
+			err = userCache.InvalidateUserCache(ctx, n.ID)
+		}
+		if err != nil {
+			log.Warn("Failed to invalidate user cache",
+				zap.Uint("user_id", n.ID),
+				zap.String("op", n.Op),
+				zap.Error(err),
+			)
+		}
+	})
+}
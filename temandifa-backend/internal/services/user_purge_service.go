@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/dto"
+	"temandifa-backend/internal/metrics"
+	"temandifa-backend/internal/models"
+)
+
+// UserPurgeService hard-deletes users whose soft deletion is older than the
+// grace period, together with the data they own
+type UserPurgeService interface {
+	// Preview lists up to limit users the next run would purge, without deleting anything
+	Preview(ctx context.Context, limit int) (*dto.UserPurgePreview, error)
+	// PurgeExpired deletes all users past the grace period and returns how many were purged
+	PurgeExpired(ctx context.Context) (int, error)
+}
+
+type userPurgeService struct {
+	db          *gorm.DB
+	txManager   database.TxManager
+	attachments AttachmentService
+	gracePeriod time.Duration
+	batchSize   int
+	log         *zap.Logger
+}
+
+// NewUserPurgeService creates a new UserPurgeService
+func NewUserPurgeService(db *gorm.DB, txManager database.TxManager, attachments AttachmentService, cfg *config.Config, log *zap.Logger) UserPurgeService {
+	return &userPurgeService{
+		db:          db,
+		txManager:   txManager,
+		attachments: attachments,
+		gracePeriod: cfg.UserPurgeGracePeriod,
+		batchSize:   cfg.UserPurgeBatchSize,
+		log:         log,
+	}
+}
+
+// userOwnedTables are hard-deleted with their user. Call logs are kept for the
+// other participant; their foreign keys are set to NULL by the database.
+var userOwnedTables = []struct {
+	name  string
+	model any
+}{
+	{"histories", &models.History{}},
+	{"refresh_tokens", &models.RefreshToken{}},
+	{"emergency_contacts", &models.EmergencyContact{}},
+	{"verification_tokens", &models.VerificationToken{}},
+	{"user_identities", &models.UserIdentity{}},
+	{"api_keys", &models.APIKey{}},
+}
+
+func (s *userPurgeService) cutoff() time.Time {
+	return time.Now().Add(-s.gracePeriod)
+}
+
+func (s *userPurgeService) Preview(ctx context.Context, limit int) (*dto.UserPurgePreview, error) {
+	cutoff := s.cutoff()
+	db := s.db.WithContext(ctx)
+
+	var total int64
+	if err := db.Model(&models.User{}).Where("deleted_at < ?", cutoff).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var users []models.User
+	if err := db.Where("deleted_at < ?", cutoff).Order("deleted_at").Limit(limit).Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	preview := &dto.UserPurgePreview{
+		GracePeriod: s.gracePeriod.String(),
+		Cutoff:      cutoff,
+		Total:       total,
+		Candidates:  make([]dto.UserPurgeCandidate, 0, len(users)),
+	}
+	for _, user := range users {
+		candidate := dto.UserPurgeCandidate{
+			UserID:    user.ID,
+			Email:     user.Email,
+			DeletedAt: *user.DeletedAt,
+		}
+		counts := []*int64{&candidate.Histories, &candidate.RefreshTokens, &candidate.EmergencyContacts, &candidate.VerificationTokens, &candidate.UserIdentities}
+		for i, table := range userOwnedTables {
+			if err := db.Unscoped().Model(table.model).Where("user_id = ?", user.ID).Count(counts[i]).Error; err != nil {
+				return nil, err
+			}
+		}
+		preview.Candidates = append(preview.Candidates, candidate)
+	}
+	return preview, nil
+}
+
+func (s *userPurgeService) PurgeExpired(ctx context.Context) (int, error) {
+	cutoff := s.cutoff()
+	purged := 0
+
+	for {
+		var ids []uint
+		err := s.db.WithContext(ctx).Model(&models.User{}).
+			Where("deleted_at < ?", cutoff).
+			Order("id").
+			Limit(s.batchSize).
+			Pluck("id", &ids).Error
+		if err != nil {
+			return purged, err
+		}
+		if len(ids) == 0 {
+			return purged, nil
+		}
+
+		for _, id := range ids {
+			if err := s.purgeUser(ctx, id); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+	}
+}
+
+// purgeUser deletes one user and everything it owns atomically. History
+// attachments are deleted first, since their files live outside the database.
+func (s *userPurgeService) purgeUser(ctx context.Context, userID uint) error {
+	deleted := make(map[string]int64, len(userOwnedTables))
+
+	if err := s.attachments.DeleteForUser(ctx, userID); err != nil {
+		s.log.Error("Failed to purge user", zap.Uint("user_id", userID), zap.Error(err))
+		return err
+	}
+
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		tx := database.Conn(ctx, s.db)
+		for _, table := range userOwnedTables {
+			result := tx.Unscoped().Where("user_id = ?", userID).Delete(table.model)
+			if result.Error != nil {
+				return result.Error
+			}
+			deleted[table.name] = result.RowsAffected
+		}
+		return tx.Delete(&models.User{}, userID).Error
+	})
+	if err != nil {
+		s.log.Error("Failed to purge user", zap.Uint("user_id", userID), zap.Error(err))
+		return err
+	}
+
+	metrics.UsersPurged.Inc()
+	for table, count := range deleted {
+		metrics.PurgedRows.WithLabelValues(table).Add(float64(count))
+	}
+	s.log.Info("User purged",
+		zap.Uint("user_id", userID),
+		zap.Int64("histories", deleted["histories"]),
+		zap.Int64("refresh_tokens", deleted["refresh_tokens"]),
+		zap.Int64("emergency_contacts", deleted["emergency_contacts"]),
+	)
+	return nil
+}
+
+// UserPurgeJob periodically purges users past the deletion grace period
+type UserPurgeJob struct {
+	purgeService UserPurgeService
+	log          *zap.Logger
+	interval     time.Duration
+	stopChan     chan struct{}
+}
+
+// NewUserPurgeJob creates a new user purge job
+func NewUserPurgeJob(purgeService UserPurgeService, cfg *config.Config, log *zap.Logger) *UserPurgeJob {
+	return &UserPurgeJob{
+		purgeService: purgeService,
+		log:          log,
+		interval:     cfg.UserPurgeInterval,
+		stopChan:     make(chan struct{}),
+	}
+}
+
+// Start begins the purge job
+func (j *UserPurgeJob) Start() {
+	j.log.Info("User purge job started", zap.Duration("interval", j.interval))
+
+	ticker := time.NewTicker(j.interval)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				j.runPurge()
+			case <-j.stopChan:
+				ticker.Stop()
+				j.log.Info("User purge job stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the purge job
+func (j *UserPurgeJob) Stop() {
+	close(j.stopChan)
+}
+
+func (j *UserPurgeJob) runPurge() {
+	count, err := j.purgeService.PurgeExpired(context.Background())
+	if err != nil {
+		j.log.Error("User purge failed", zap.Int("users_purged", count), zap.Error(err))
+		return
+	}
+	if count > 0 {
+		j.log.Info("User purge completed", zap.Int("users_purged", count))
+	}
+}
+
+// RegisterUserPurgeJob registers the purge job with fx lifecycle
+func RegisterUserPurgeJob(lc fx.Lifecycle, purgeService UserPurgeService, cfg *config.Config, log *zap.Logger) {
+	job := NewUserPurgeJob(purgeService, cfg, log)
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			job.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			job.Stop()
+			return nil
+		},
+	})
+}
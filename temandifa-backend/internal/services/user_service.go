@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.uber.org/zap"
+	"golang.org/x/text/language"
+
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+)
+
+// UserService serves users their own profile
+type UserService interface {
+	// GetProfile returns the user's profile with their plan allowance
+	GetProfile(ctx context.Context, userID uint) (*dto.Me, error)
+	// UpdateProfile changes the fields set in input and drops the cached user
+	UpdateProfile(ctx context.Context, userID uint, input dto.UpdateProfileRequest) (*dto.Me, error)
+}
+
+type userService struct {
+	users     repositories.UserRepository
+	plans     PlanService
+	userCache UserCacheService
+}
+
+// NewUserService creates a new UserService
+func NewUserService(users repositories.UserRepository, plans PlanService, userCache UserCacheService) UserService {
+	return &userService{users: users, plans: plans, userCache: userCache}
+}
+
+// find loads the user from the database; the cached one lacks fields
+func (s *userService) find(ctx context.Context, userID uint) (*models.User, error) {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.Database(err)
+	}
+	if user == nil {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeNotFound, "User not found", http.StatusNotFound)
+	}
+	return user, nil
+}
+
+func (s *userService) GetProfile(ctx context.Context, userID uint) (*dto.Me, error) {
+	user, err := s.find(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.profile(ctx, user)
+}
+
+func (s *userService) UpdateProfile(ctx context.Context, userID uint, input dto.UpdateProfileRequest) (*dto.Me, error) {
+	user, err := s.find(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if input.FullName != nil {
+		fullName := strings.TrimSpace(*input.FullName)
+		if len(fullName) < 2 {
+			return nil, apperrors.ValidationWithDetails("Invalid name", map[string]string{"full_name": "must have at least 2 characters"})
+		}
+		user.FullName = fullName
+	}
+	if input.PreferredLanguage != nil {
+		user.PreferredLanguage = ""
+		if *input.PreferredLanguage != "" {
+			tag, err := language.Parse(*input.PreferredLanguage)
+			if err != nil || tag == language.Und {
+				return nil, apperrors.ValidationWithDetails("Invalid language", map[string]string{"preferred_language": "must be a BCP 47 language tag, e.g. id or en-US"})
+			}
+			user.PreferredLanguage = tag.String()
+		}
+	}
+	if input.ProfilePicture != nil {
+		if *input.ProfilePicture != "" {
+			u, err := url.Parse(*input.ProfilePicture)
+			if err != nil || u.Scheme != "https" || u.Host == "" {
+				return nil, apperrors.ValidationWithDetails("Invalid avatar", map[string]string{"profile_picture": "must be an https URL"})
+			}
+		}
+		user.ProfilePicture = *input.ProfilePicture
+	}
+
+	if err := s.users.Update(ctx, user); err != nil {
+		if _, ok := apperrors.AsAppError(err); ok {
+			return nil, err
+		}
+		return nil, apperrors.Database(err)
+	}
+	if err := s.userCache.InvalidateUserCache(ctx, user.ID); err != nil {
+		logger.Ctx(ctx).Debug("Failed to invalidate user cache", zap.Uint("user_id", user.ID), zap.Error(err))
+	}
+	return s.profile(ctx, user)
+}
+
+func (s *userService) profile(ctx context.Context, user *models.User) (*dto.Me, error) {
+	allowance, err := s.plans.Allowance(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.Me{
+		ID:                user.ID,
+		Email:             user.Email,
+		FullName:          user.FullName,
+		ProfilePicture:    user.ProfilePicture,
+		PreferredLanguage: user.PreferredLanguage,
+		Role:              user.Role,
+		Organization:      user.Organization,
+		CreatedAt:         user.CreatedAt,
+		EmailVerifiedAt:   user.EmailVerifiedAt,
+		PendingEmail:      user.PendingEmail,
+		Plan:              *allowance,
+	}, nil
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+)
+
+// verificationTokenHash is the stored form of a verification token
+func verificationTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// issueVerificationToken stores a new token of purpose for the user's email,
+// joining the transaction bound to ctx, and returns it
+func issueVerificationToken(ctx context.Context, repo repositories.VerificationTokenRepository, user *models.User, purpose string, ttl time.Duration) (string, error) {
+	return issueVerificationTokenFor(ctx, repo, user, user.Email, purpose, ttl)
+}
+
+// issueVerificationTokenFor is issueVerificationToken for another address of
+// the user's, e.g. one they want to change to
+func issueVerificationTokenFor(ctx context.Context, repo repositories.VerificationTokenRepository, user *models.User, email, purpose string, ttl time.Duration) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	now := time.Now()
+	err := repo.Create(ctx, &models.VerificationToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: verificationTokenHash(token),
+		Email:     email,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// useVerificationToken uses the token of purpose up, returning nil when it is
+// unknown, used or expired
+func useVerificationToken(ctx context.Context, repo repositories.VerificationTokenRepository, token, purpose string, now time.Time) (*models.VerificationToken, error) {
+	if token == "" {
+		return nil, nil
+	}
+	stored, err := repo.FindByHash(ctx, verificationTokenHash(token))
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil || stored.Purpose != purpose || stored.UsedAt != nil || now.After(stored.ExpiresAt) {
+		return nil, nil
+	}
+
+	used, err := repo.MarkUsed(ctx, stored.ID, now)
+	if err != nil || !used {
+		return nil, err
+	}
+	return stored, nil
+}
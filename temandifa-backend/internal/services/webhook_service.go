@@ -0,0 +1,526 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/config"
+	"temandifa-backend/internal/database"
+	"temandifa-backend/internal/dto"
+	apperrors "temandifa-backend/internal/errors"
+	"temandifa-backend/internal/helpers"
+	"temandifa-backend/internal/logger"
+	"temandifa-backend/internal/models"
+	"temandifa-backend/internal/repositories"
+)
+
+// Audit actions and target type of webhook subscription management
+const (
+	auditTargetWebhook  = "webhook_subscription"
+	auditWebhookCreated = "webhook_subscription.created"
+	auditWebhookUpdated = "webhook_subscription.updated"
+	auditWebhookDeleted = "webhook_subscription.deleted"
+)
+
+// WebhookTestEvent is the type of test deliveries
+const WebhookTestEvent = "webhook.test"
+
+const (
+	// webhookSendTimeout bounds a single delivery attempt
+	webhookSendTimeout = 5 * time.Second
+	// webhookSecretBytes is the entropy of generated signing secrets
+	webhookSecretBytes = 32
+	// webhookPurgeInterval controls how often old delivery logs are deleted
+	webhookPurgeInterval = time.Hour
+)
+
+// WebhookEventTypes are the outbox events third parties can subscribe to
+var WebhookEventTypes = []string{OutboxUserRegistered, OutboxDataExportReady, OutboxUserDeleted}
+
+// webhookRetry retries a failed delivery a few times right away; after that
+// the outbox retries the event with its own backoff
+var webhookRetry = helpers.RetryConfig{
+	MaxRetries:     2,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     2 * time.Second,
+	Multiplier:     2.0,
+	Jitter:         0.25,
+}
+
+// WebhookService manages third parties' webhook subscriptions and delivers
+// outbox events to them. Deliveries are signed like the outbox webhooks
+// (X-Temandifa-Signature over "<timestamp>.<body>") with the subscription's
+// own secret and logged; an event is retried until every subscription got it.
+type WebhookService interface {
+	OutboxPublisher
+
+	List(ctx context.Context) ([]dto.WebhookSubscription, error)
+	// Get returns nil when the subscription doesn't exist
+	Get(ctx context.Context, id uint64) (*dto.WebhookSubscription, error)
+	// Create returns the subscription with its signing secret
+	Create(ctx context.Context, input dto.CreateWebhookRequest) (*dto.WebhookSubscription, error)
+	// Update returns nil when the subscription doesn't exist; the secret is
+	// included when rotated
+	Update(ctx context.Context, id uint64, input dto.UpdateWebhookRequest) (*dto.WebhookSubscription, error)
+	// Delete reports false when the subscription doesn't exist
+	Delete(ctx context.Context, id uint64) (bool, error)
+	// Deliveries returns a page of the subscription's delivery log, the
+	// latest first
+	Deliveries(ctx context.Context, id uint64, page, limit int) ([]models.WebhookDelivery, int64, error)
+	// Test sends a webhook.test event to the subscription, active or not;
+	// nil when it doesn't exist
+	Test(ctx context.Context, id uint64) (*models.WebhookDelivery, error)
+	// Purge deletes delivery logs older than WEBHOOK_DELIVERY_RETENTION
+	Purge(ctx context.Context) (int64, error)
+}
+
+type webhookService struct {
+	repo   repositories.WebhookRepository
+	audit  AuditService
+	tx     database.TxManager
+	client *http.Client
+	cfg    *config.Config
+	log    *zap.Logger
+}
+
+// NewWebhookService creates a new WebhookService
+func NewWebhookService(repo repositories.WebhookRepository, audit AuditService, tx database.TxManager, cfg *config.Config, log *zap.Logger) WebhookService {
+	return &webhookService{
+		repo:   repo,
+		audit:  audit,
+		tx:     tx,
+		client: newWebhookClient(),
+		cfg:    cfg,
+		log:    log,
+	}
+}
+
+// webhookEventTypes decodes a subscription's event types
+func webhookEventTypes(subscription *models.WebhookSubscription) []string {
+	var types []string
+	_ = json.Unmarshal([]byte(subscription.EventTypes), &types)
+	return types
+}
+
+func toWebhookSubscription(subscription *models.WebhookSubscription) dto.WebhookSubscription {
+	return dto.WebhookSubscription{
+		ID:         subscription.ID,
+		Name:       subscription.Name,
+		URL:        subscription.URL,
+		EventTypes: webhookEventTypes(subscription),
+		Active:     subscription.Active,
+		CreatedBy:  subscription.CreatedBy,
+		CreatedAt:  subscription.CreatedAt,
+		UpdatedAt:  subscription.UpdatedAt,
+	}
+}
+
+// validateWebhook checks the endpoint and event types, returning the event
+// types sorted and deduplicated as JSON
+func (s *webhookService) validateWebhook(rawURL string, eventTypes []string) (string, error) {
+	endpoint, err := url.Parse(rawURL)
+	if err != nil || endpoint.Host == "" || (endpoint.Scheme != "https" && endpoint.Scheme != "http") {
+		return "", apperrors.ValidationWithDetails("Invalid URL", map[string]string{"url": "must be an http(s) URL"})
+	}
+	if endpoint.Scheme != "https" && s.cfg.AppEnv == config.EnvProd {
+		return "", apperrors.ValidationWithDetails("Invalid URL", map[string]string{"url": "must use https"})
+	}
+	// Hostnames are checked again when dialing, after DNS resolution
+	host := endpoint.Hostname()
+	if ip := net.ParseIP(host); (ip != nil && blockedWebhookIP(ip)) || strings.EqualFold(host, "localhost") {
+		return "", apperrors.ValidationWithDetails("Invalid URL", map[string]string{"url": "must not point to a private or local address"})
+	}
+
+	types := slices.Clone(eventTypes)
+	for _, eventType := range types {
+		if !slices.Contains(WebhookEventTypes, eventType) {
+			return "", apperrors.ValidationWithDetails("Unknown event type", map[string]string{
+				"event_types": fmt.Sprintf("%s is not one of %s", eventType, strings.Join(WebhookEventTypes, ", ")),
+			})
+		}
+	}
+	slices.Sort(types)
+	encoded, err := json.Marshal(slices.Compact(types))
+	return string(encoded), err
+}
+
+// newWebhookSecret generates a signing secret
+func newWebhookSecret() (string, error) {
+	secret := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(secret), nil
+}
+
+func (s *webhookService) List(ctx context.Context) ([]dto.WebhookSubscription, error) {
+	subscriptions, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.WebhookSubscription, len(subscriptions))
+	for i := range subscriptions {
+		result[i] = toWebhookSubscription(&subscriptions[i])
+	}
+	return result, nil
+}
+
+func (s *webhookService) Get(ctx context.Context, id uint64) (*dto.WebhookSubscription, error) {
+	subscription, err := s.repo.Find(ctx, id)
+	if err != nil || subscription == nil {
+		return nil, err
+	}
+	result := toWebhookSubscription(subscription)
+	return &result, nil
+}
+
+func (s *webhookService) Create(ctx context.Context, input dto.CreateWebhookRequest) (*dto.WebhookSubscription, error) {
+	eventTypes, err := s.validateWebhook(input.URL, input.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	subscription := &models.WebhookSubscription{
+		Name:       input.Name,
+		URL:        input.URL,
+		EventTypes: eventTypes,
+		Secret:     secret,
+		Active:     input.Active == nil || *input.Active,
+	}
+	if actorID := logger.UserIDFromContext(ctx); actorID != 0 {
+		subscription.CreatedBy = &actorID
+	}
+
+	var result dto.WebhookSubscription
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, subscription); err != nil {
+			return err
+		}
+		result = toWebhookSubscription(subscription)
+		return s.audit.RecordChange(ctx, auditWebhookCreated, auditTargetWebhook, strconv.FormatUint(subscription.ID, 10), nil, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Secret = secret
+	return &result, nil
+}
+
+func (s *webhookService) Update(ctx context.Context, id uint64, input dto.UpdateWebhookRequest) (*dto.WebhookSubscription, error) {
+	eventTypes, err := s.validateWebhook(input.URL, input.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+	var secret string
+	if input.RotateSecret {
+		if secret, err = newWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	var result *dto.WebhookSubscription
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		subscription, err := s.repo.Find(ctx, id)
+		if err != nil || subscription == nil {
+			return err
+		}
+		before := toWebhookSubscription(subscription)
+
+		subscription.Name = input.Name
+		subscription.URL = input.URL
+		subscription.EventTypes = eventTypes
+		subscription.Active = input.Active
+		if secret != "" {
+			subscription.Secret = secret
+		}
+		if err := s.repo.Update(ctx, subscription); err != nil {
+			return err
+		}
+
+		after := toWebhookSubscription(subscription)
+		result = &after
+		// The secret itself is never recorded
+		var changes any = after
+		if secret != "" {
+			changes = struct {
+				dto.WebhookSubscription
+				SecretRotated bool `json:"secret_rotated"`
+			}{after, true}
+		}
+		return s.audit.RecordChange(ctx, auditWebhookUpdated, auditTargetWebhook, strconv.FormatUint(id, 10), before, changes)
+	})
+	if err != nil || result == nil {
+		return nil, err
+	}
+	result.Secret = secret
+	return result, nil
+}
+
+func (s *webhookService) Delete(ctx context.Context, id uint64) (bool, error) {
+	var deleted bool
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		subscription, err := s.repo.Find(ctx, id)
+		if err != nil || subscription == nil {
+			return err
+		}
+		if deleted, err = s.repo.Delete(ctx, id); err != nil || !deleted {
+			return err
+		}
+		return s.audit.RecordChange(ctx, auditWebhookDeleted, auditTargetWebhook, strconv.FormatUint(id, 10), toWebhookSubscription(subscription), nil)
+	})
+	return deleted, err
+}
+
+func (s *webhookService) Deliveries(ctx context.Context, id uint64, page, limit int) ([]models.WebhookDelivery, int64, error) {
+	return s.repo.Deliveries(ctx, id, page, limit)
+}
+
+func (s *webhookService) Test(ctx context.Context, id uint64) (*models.WebhookDelivery, error) {
+	subscription, err := s.repo.Find(ctx, id)
+	if err != nil || subscription == nil {
+		return nil, err
+	}
+
+	body, err := outboxEventBody(models.OutboxEvent{
+		EventType:     WebhookTestEvent,
+		AggregateType: auditTargetWebhook,
+		AggregateID:   strconv.FormatUint(id, 10),
+		CreatedAt:     time.Now(),
+		Payload:       `{"message":"Test delivery"}`,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.deliver(ctx, subscription, nil, WebhookTestEvent, body), nil
+}
+
+func (s *webhookService) Name() string { return "webhook subscriptions" }
+
+// Publish delivers an outbox event to the active subscriptions of its type
+// that haven't received it yet, failing if any of them didn't
+func (s *webhookService) Publish(ctx context.Context, event models.OutboxEvent) error {
+	subscriptions, err := s.repo.ListActive(ctx)
+	if err != nil {
+		return err
+	}
+	subscriptions = slices.DeleteFunc(subscriptions, func(subscription models.WebhookSubscription) bool {
+		return !slices.Contains(webhookEventTypes(&subscription), event.EventType)
+	})
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	delivered, err := s.repo.Delivered(ctx, event.ID)
+	if err != nil {
+		return err
+	}
+	body, err := outboxEventBody(event)
+	if err != nil {
+		return err
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed []string
+	)
+	for i := range subscriptions {
+		subscription := &subscriptions[i]
+		if delivered[subscription.ID] {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if delivery := s.deliver(ctx, subscription, &event.ID, event.EventType, body); delivery.Status != models.WebhookDeliverySucceeded {
+				mu.Lock()
+				failed = append(failed, strconv.FormatUint(subscription.ID, 10))
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(failed) > 0 {
+		slices.Sort(failed)
+		return fmt.Errorf("delivery to subscriptions %s failed", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// deliver sends an event to a subscription, retrying failures a few times,
+// and logs the delivery
+func (s *webhookService) deliver(ctx context.Context, subscription *models.WebhookSubscription, eventID *uint64, eventType string, body []byte) *models.WebhookDelivery {
+	delivery := &models.WebhookDelivery{
+		SubscriptionID: subscription.ID,
+		EventID:        eventID,
+		EventType:      eventType,
+		Status:         models.WebhookDeliverySucceeded,
+	}
+
+	start := time.Now()
+	err := helpers.WithRetry(ctx, webhookRetry, "webhook delivery", func() error {
+		delivery.Attempts++
+		var err error
+		delivery.ResponseStatus, err = s.post(ctx, subscription, eventID, eventType, body)
+		return err
+	})
+	delivery.DurationMS = time.Since(start).Milliseconds()
+	if err != nil {
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.Error = err.Error()
+		s.log.Warn("Webhook delivery failed",
+			zap.Uint64("subscription_id", subscription.ID),
+			zap.String("event_type", eventType),
+			zap.Int("attempts", delivery.Attempts),
+			zap.Error(err),
+		)
+	}
+
+	if err := s.repo.CreateDelivery(ctx, delivery); err != nil {
+		// Without the log entry a successful delivery repeats with the event
+		s.log.Error("Failed to log webhook delivery", zap.Uint64("subscription_id", subscription.ID), zap.Error(err))
+	}
+	return delivery
+}
+
+// errBlockedWebhookAddress rejects deliveries to internal addresses
+var errBlockedWebhookAddress = errors.New("webhook address is private or local")
+
+// cgnatRange is the carrier-grade NAT range (RFC 6598), not covered by
+// net.IP.IsPrivate
+var cgnatRange = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// blockedWebhookIP reports whether an address is internal to the deployment:
+// loopback, private, link-local (cloud metadata included) or unspecified
+func blockedWebhookIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() || cgnatRange.Contains(ip)
+}
+
+// newWebhookClient returns a client that only connects to public addresses,
+// checked on the resolved IP so DNS can't point a subscription inside, and
+// doesn't follow redirects, which would escape the check
+func newWebhookClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: webhookSendTimeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || blockedWebhookIP(ip) {
+				return errBlockedWebhookAddress
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   webhookSendTimeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// post sends one delivery attempt, returning the response status if any
+func (s *webhookService) post(ctx context.Context, subscription *models.WebhookSubscription, eventID *uint64, eventType string, body []byte) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, webhookSendTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Temandifa-Event-Type", eventType)
+	if eventID != nil {
+		req.Header.Set("X-Temandifa-Event-ID", strconv.FormatUint(*eventID, 10))
+	}
+	req.Header.Set("X-Temandifa-Timestamp", timestamp)
+	req.Header.Set("X-Temandifa-Signature", "sha256="+signPayload(subscription.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+func (s *webhookService) Purge(ctx context.Context) (int64, error) {
+	return s.repo.PurgeDeliveries(ctx, time.Now().Add(-s.cfg.WebhookDeliveryRetention))
+}
+
+// RegisterWebhookDeliveryPurge deletes old webhook delivery logs hourly
+func RegisterWebhookDeliveryPurge(lc fx.Lifecycle, webhooks WebhookService, log *zap.Logger) {
+	stopChan := make(chan struct{})
+
+	purge := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		deleted, err := webhooks.Purge(ctx)
+		if err != nil {
+			log.Warn("Failed to purge webhook deliveries", zap.Error(err))
+			return
+		}
+		if deleted > 0 {
+			log.Info("Webhook deliveries purged", zap.Int64("deleted", deleted))
+		}
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			ticker := time.NewTicker(webhookPurgeInterval)
+			go func() {
+				for {
+					select {
+					case <-ticker.C:
+						purge()
+					case <-stopChan:
+						ticker.Stop()
+						return
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stopChan)
+			return nil
+		},
+	})
+}
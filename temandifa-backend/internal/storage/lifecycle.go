@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/config"
+)
+
+// incompleteUploadDays is how long the parts of an interrupted multipart
+// upload are kept
+const incompleteUploadDays = 1
+
+// LifecycleRule expires the objects under Prefix ExpireDays after they were
+// written
+type LifecycleRule struct {
+	ID         string
+	Prefix     string
+	ExpireDays int
+}
+
+// bucketManager is implemented by stores that can create and configure their
+// bucket
+type bucketManager interface {
+	manageBucket(ctx context.Context, rules []LifecycleRule) error
+}
+
+// LifecycleRules returns the expiry rules for temporary objects. Export
+// archives and resumable upload chunks are deleted on time by the purge jobs;
+// the rules keep them a day longer and catch whatever a purge missed.
+// Prefixes left empty get no rule, since that would expire the whole bucket.
+func LifecycleRules(cfg *config.Config) []LifecycleRule {
+	candidates := []LifecycleRule{
+		{ID: "expire-data-exports", Prefix: cfg.DataExportPrefix, ExpireDays: expireDays(cfg.DataExportTTL)},
+		{ID: "expire-resumable-uploads", Prefix: cfg.ResumableUploadPrefix, ExpireDays: expireDays(cfg.ResumableUploadTTL)},
+	}
+	var rules []LifecycleRule
+	for _, rule := range candidates {
+		if rule.Prefix != "" {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// expireDays rounds ttl up to whole days, plus one
+func expireDays(ttl time.Duration) int {
+	const day = 24 * time.Hour
+	return int((ttl+day-1)/day) + 1
+}
+
+// RegisterBucketLifecycle creates the S3 bucket if it's missing and installs
+// LifecycleRules at startup, when OBJECT_STORAGE_MANAGE_BUCKET is set. It's
+// opt-in because a bucket's lifecycle configuration is replaced as a whole,
+// dropping rules managed elsewhere (e.g. by Terraform).
+func RegisterBucketLifecycle(lc fx.Lifecycle, store ObjectStore, cfg *config.Config, log *zap.Logger) {
+	manager, ok := store.(bucketManager)
+	if !ok || !cfg.ObjectStorageManageBucket {
+		return
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			rules := LifecycleRules(cfg)
+			if err := manager.manageBucket(ctx, rules); err != nil {
+				// Not fatal: the purge jobs still delete temporary objects
+				log.Warn("Failed to configure object storage bucket", zap.Error(err))
+				return nil
+			}
+			log.Info("Object storage bucket configured",
+				zap.String("bucket", cfg.ObjectStorageBucket),
+				zap.Int("lifecycle_rules", len(rules)))
+			return nil
+		},
+	})
+}
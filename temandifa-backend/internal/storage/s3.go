@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+
+	"temandifa-backend/internal/config"
+)
+
+// s3Store stores objects in an S3-compatible bucket (AWS S3, MinIO, R2, ...)
+type s3Store struct {
+	client *minio.Client
+	bucket string
+	region string
+}
+
+func newS3Store(cfg *config.Config) (*s3Store, error) {
+	client, err := minio.New(cfg.ObjectStorageEndpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.ObjectStorageAccessKey, cfg.ObjectStorageSecretKey, ""),
+		Secure: cfg.ObjectStorageUseSSL,
+		Region: cfg.ObjectStorageRegion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &s3Store{client: client, bucket: cfg.ObjectStorageBucket, region: cfg.ObjectStorageRegion}, nil
+}
+
+func (s *s3Store) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (int64, error) {
+	info, err := s.client.PutObject(ctx, s.bucket, key, r, size, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("put %s: %w", key, err)
+	}
+	return info.Size, nil
+}
+
+func (s *s3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	obj, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("get %s: %w", key, err)
+	}
+	// GetObject is lazy: Stat makes the request so a missing key fails here
+	if _, err := obj.Stat(); err != nil {
+		_ = obj.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("get %s: %w", key, err)
+	}
+	return obj, nil
+}
+
+func (s *s3Store) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	obj, err := s.client.StatObject(ctx, s.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return ObjectInfo{}, ErrObjectNotFound
+		}
+		return ObjectInfo{}, fmt.Errorf("stat %s: %w", key, err)
+	}
+	return ObjectInfo{Key: obj.Key, Size: obj.Size, LastModified: obj.LastModified, ContentType: obj.ContentType}, nil
+}
+
+func (s *s3Store) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	for obj := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if obj.Err != nil {
+			return nil, obj.Err
+		}
+		objects = append(objects, ObjectInfo{Key: obj.Key, Size: obj.Size, LastModified: obj.LastModified})
+	}
+	return objects, nil
+}
+
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
+
+func (s *s3Store) PresignGet(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	u, err := s.client.PresignedGetObject(ctx, s.bucket, key, expiry, nil)
+	if err != nil {
+		return "", fmt.Errorf("presign %s: %w", key, err)
+	}
+	return u.String(), nil
+}
+
+func (s *s3Store) PresignPut(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	u, err := s.client.PresignedPutObject(ctx, s.bucket, key, expiry)
+	if err != nil {
+		return "", fmt.Errorf("presign %s: %w", key, err)
+	}
+	return u.String(), nil
+}
+
+// manageBucket creates the bucket when it's missing and replaces its
+// lifecycle configuration with rules
+func (s *s3Store) manageBucket(ctx context.Context, rules []LifecycleRule) error {
+	exists, err := s.client.BucketExists(ctx, s.bucket)
+	if err != nil {
+		return fmt.Errorf("bucket %s: %w", s.bucket, err)
+	}
+	if !exists {
+		if err := s.client.MakeBucket(ctx, s.bucket, minio.MakeBucketOptions{Region: s.region}); err != nil {
+			return fmt.Errorf("create bucket %s: %w", s.bucket, err)
+		}
+	}
+
+	config := lifecycle.NewConfiguration()
+	for _, rule := range rules {
+		config.Rules = append(config.Rules, lifecycle.Rule{
+			ID:         rule.ID,
+			Status:     "Enabled",
+			RuleFilter: lifecycle.Filter{Prefix: rule.Prefix},
+			Expiration: lifecycle.Expiration{Days: lifecycle.ExpirationDays(rule.ExpireDays)},
+		})
+	}
+	// Multipart uploads interrupted by a crash would otherwise be kept (and
+	// billed) forever
+	config.Rules = append(config.Rules, lifecycle.Rule{
+		ID:     "abort-incomplete-multipart-uploads",
+		Status: "Enabled",
+		AbortIncompleteMultipartUpload: lifecycle.AbortIncompleteMultipartUpload{
+			DaysAfterInitiation: lifecycle.ExpirationDays(incompleteUploadDays),
+		},
+	})
+	if err := s.client.SetBucketLifecycle(ctx, s.bucket, config); err != nil {
+		return fmt.Errorf("bucket %s lifecycle: %w", s.bucket, err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// Package storage provides object storage (S3/MinIO or a local directory)
+// for backups, data export archives, resumable uploads and history media
+// attachments.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"temandifa-backend/internal/config"
+)
+
+// Object storage drivers
+const (
+	DriverNone       = "none"
+	DriverFilesystem = "filesystem"
+	DriverS3         = "s3"
+)
+
+// Module exports the object store for Fx. The store is nil when
+// OBJECT_STORAGE_DRIVER is "none"; consumers must check before use.
+var Module = fx.Options(
+	fx.Provide(NewObjectStore),
+)
+
+// ErrObjectNotFound is returned by Get and Stat for a missing key
+var ErrObjectNotFound = errors.New("object not found")
+
+// ObjectInfo describes a stored object
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+	ContentType  string // Set by Stat; empty when the driver doesn't keep it
+}
+
+// ObjectStore is a minimal S3-style blob store
+type ObjectStore interface {
+	// Put streams r to key. size may be -1 when unknown. Returns the bytes stored.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (int64, error)
+	// Get opens key for reading; the caller closes it
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Stat describes key without reading it
+	Stat(ctx context.Context, key string) (ObjectInfo, error)
+	// List returns the objects whose key starts with prefix
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Presigner is implemented by stores that can hand out time-limited URLs,
+// so clients transfer objects directly instead of through the API
+type Presigner interface {
+	// PresignGet returns a URL that downloads key until expiry
+	PresignGet(ctx context.Context, key string, expiry time.Duration) (string, error)
+	// PresignPut returns a URL that uploads key (with HTTP PUT) until expiry
+	PresignPut(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
+
+// NewObjectStore creates the object store selected by OBJECT_STORAGE_DRIVER
+func NewObjectStore(cfg *config.Config, log *zap.Logger) (ObjectStore, error) {
+	switch cfg.ObjectStorageDriver {
+	case DriverS3:
+		store, err := newS3Store(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("object storage: %w", err)
+		}
+		log.Info("Object storage: S3")
+		return store, nil
+	case DriverFilesystem:
+		log.Info("Object storage: filesystem")
+		return newFilesystemStore(cfg.ObjectStorageDir)
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"context"
+	"io"
+)
+
+// Open opens key for reading along with its description; the caller closes
+// the reader
+func Open(ctx context.Context, store ObjectStore, key string) (io.ReadCloser, ObjectInfo, error) {
+	info, err := store.Stat(ctx, key)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	r, err := store.Get(ctx, key)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	return r, info, nil
+}
+
+// DeletePrefix deletes every object whose key starts with prefix and returns
+// how many were deleted
+func DeletePrefix(ctx context.Context, store ObjectStore, prefix string) (int, error) {
+	objects, err := store.List(ctx, prefix)
+	if err != nil {
+		return 0, err
+	}
+	deleted := 0
+	for _, obj := range objects {
+		if err := store.Delete(ctx, obj.Key); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
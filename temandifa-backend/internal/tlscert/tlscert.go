@@ -0,0 +1,234 @@
+// Package tlscert supplies certificates for serving TLS directly, either from
+// a certificate/key pair on disk that is reloaded when the files change (for
+// cert-manager or certbot renewals) or from an ACME CA via autocert.
+package tlscert
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+
+	"temandifa-backend/internal/metrics"
+)
+
+// TLS modes
+const (
+	ModeOff  = "off"
+	ModeFile = "file"
+	ModeACME = "acme"
+)
+
+// Options configures where certificates come from
+type Options struct {
+	Mode           string        // off, file or acme
+	CertFile       string        // PEM certificate chain (file mode)
+	KeyFile        string        // PEM private key (file mode)
+	ReloadInterval time.Duration // how often the files are checked for changes
+	ACMEDomains    []string      // host names certificates may be issued for
+	ACMEEmail      string        // contact address registered with the CA
+	ACMECacheDir   string        // where issued certificates and the account key are kept
+	ACMEDirectory  string        // CA directory URL; empty means Let's Encrypt
+	HTTPSPort      string        // port plain HTTP requests are redirected to
+}
+
+// Provider hands certificates to the server's tls.Config
+type Provider struct {
+	tlsConfig   *tls.Config
+	httpHandler http.Handler
+	reloader    *reloader
+}
+
+// New returns a provider for opts, or nil when TLS is off. In file mode the
+// pair must load at startup; later failed reloads keep serving the old one.
+func New(opts Options, log *zap.Logger) (*Provider, error) {
+	redirect := redirectHTTPS(opts.HTTPSPort)
+	switch opts.Mode {
+	case "", ModeOff:
+		return nil, nil
+	case ModeFile:
+		r := &reloader{certFile: opts.CertFile, keyFile: opts.KeyFile, interval: opts.ReloadInterval, log: log}
+		if err := r.load(); err != nil {
+			return nil, err
+		}
+		return &Provider{
+			tlsConfig: &tls.Config{
+				MinVersion:     tls.VersionTLS12,
+				GetCertificate: r.getCertificate,
+			},
+			httpHandler: redirect,
+			reloader:    r,
+		}, nil
+	case ModeACME:
+		if err := os.MkdirAll(opts.ACMECacheDir, 0o700); err != nil {
+			return nil, fmt.Errorf("create ACME cache dir: %w", err)
+		}
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			Cache:      autocert.DirCache(opts.ACMECacheDir),
+			HostPolicy: autocert.HostWhitelist(opts.ACMEDomains...),
+			Email:      opts.ACMEEmail,
+		}
+		if opts.ACMEDirectory != "" {
+			manager.Client = &acme.Client{DirectoryURL: opts.ACMEDirectory}
+		}
+		// autocert renews certificates ahead of expiry on its own
+		tlsConfig := manager.TLSConfig()
+		tlsConfig.MinVersion = tls.VersionTLS12
+		return &Provider{
+			tlsConfig:   tlsConfig,
+			httpHandler: manager.HTTPHandler(redirect),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown TLS mode %q", opts.Mode)
+	}
+}
+
+// TLSConfig returns the config to serve with
+func (p *Provider) TLSConfig() *tls.Config {
+	return p.tlsConfig
+}
+
+// HTTPHandler serves the plain HTTP port: it redirects to HTTPS and, in ACME
+// mode, answers HTTP-01 challenges. Without a plain HTTP port ACME falls back
+// to TLS-ALPN-01 on the TLS port.
+func (p *Provider) HTTPHandler() http.Handler {
+	return p.httpHandler
+}
+
+// redirectHTTPS sends plain HTTP requests to the same URL over HTTPS on port
+func redirectHTTPS(port string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+		if port != "" && port != "443" {
+			host = net.JoinHostPort(host, port)
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusFound)
+	})
+}
+
+// Start begins watching the certificate files (file mode)
+func (p *Provider) Start() {
+	if p.reloader != nil {
+		p.reloader.start()
+	}
+}
+
+// Stop stops watching the certificate files
+func (p *Provider) Stop() {
+	if p.reloader != nil {
+		p.reloader.stop()
+	}
+}
+
+// reloader serves a certificate/key pair and swaps it when either file's
+// modification time changes
+type reloader struct {
+	certFile string
+	keyFile  string
+	interval time.Duration
+	log      *zap.Logger
+
+	cert     atomic.Pointer[tls.Certificate]
+	modTimes [2]time.Time
+	stopOnce sync.Once
+	stopChan chan struct{}
+}
+
+func (r *reloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.cert.Load(), nil
+}
+
+// modified reports the files' modification times
+func (r *reloader) modified() ([2]time.Time, error) {
+	var times [2]time.Time
+	for i, path := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			return times, err
+		}
+		times[i] = info.ModTime()
+	}
+	return times, nil
+}
+
+func (r *reloader) load() error {
+	times, err := r.modified()
+	if err != nil {
+		return fmt.Errorf("stat TLS certificate: %w", err)
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("load TLS certificate: %w", err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("parse TLS certificate: %w", err)
+	}
+	cert.Leaf = leaf
+
+	r.cert.Store(&cert)
+	r.modTimes = times
+	metrics.TLSCertificateExpiry.Set(float64(leaf.NotAfter.Unix()))
+	r.log.Info("TLS certificate loaded",
+		zap.Strings("dns_names", leaf.DNSNames),
+		zap.Time("not_after", leaf.NotAfter),
+	)
+	return nil
+}
+
+// check reloads the pair if either file changed since the last load. While a
+// renewal is half written the pair may not match; the next check retries.
+func (r *reloader) check() {
+	times, err := r.modified()
+	if err != nil {
+		metrics.TLSCertificateReloads.WithLabelValues("error").Inc()
+		r.log.Warn("TLS certificate files unavailable, keeping current certificate", zap.Error(err))
+		return
+	}
+	if times == r.modTimes {
+		return
+	}
+	if err := r.load(); err != nil {
+		metrics.TLSCertificateReloads.WithLabelValues("error").Inc()
+		r.log.Warn("TLS certificate reload failed, keeping current certificate", zap.Error(err))
+		return
+	}
+	metrics.TLSCertificateReloads.WithLabelValues("success").Inc()
+}
+
+func (r *reloader) start() {
+	r.stopChan = make(chan struct{})
+	ticker := time.NewTicker(r.interval)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				r.check()
+			case <-r.stopChan:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
+
+func (r *reloader) stop() {
+	r.stopOnce.Do(func() {
+		if r.stopChan != nil {
+			close(r.stopChan)
+		}
+	})
+}
@@ -0,0 +1,3 @@
+-- Optimistic locking: updates must match the version the client last read
+ALTER TABLE users ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE histories ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,30 @@
+-- Publish user changes on the temandifa_changes channel so every instance can
+-- drop cached copies immediately (see database.ChangeListener)
+CREATE OR REPLACE FUNCTION notify_user_change() RETURNS trigger AS $$
+DECLARE
+    row_id BIGINT;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        row_id := OLD.id;
+    ELSE
+        -- Only fields that end up in caches (profile, role, deletion) matter
+        IF (NEW.email, NEW.full_name, NEW.role, NEW.deleted_at)
+            IS NOT DISTINCT FROM (OLD.email, OLD.full_name, OLD.role, OLD.deleted_at) THEN
+            RETURN NULL;
+        END IF;
+        row_id := NEW.id;
+    END IF;
+
+    PERFORM pg_notify('temandifa_changes', json_build_object(
+        'entity', 'user',
+        'id', row_id,
+        'op', lower(TG_OP)
+    )::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS users_notify_change ON users;
+CREATE TRIGGER users_notify_change
+    AFTER UPDATE OR DELETE ON users
+    FOR EACH ROW EXECUTE FUNCTION notify_user_change();
@@ -0,0 +1,14 @@
+-- One row per scheduled backup attempt; the latest successful row drives the
+-- backup health check on every instance
+CREATE TABLE IF NOT EXISTS backup_runs (
+    id BIGSERIAL PRIMARY KEY,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMP WITH TIME ZONE,
+    method VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'running',
+    location TEXT NOT NULL DEFAULT '',
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_backup_runs_succeeded ON backup_runs(finished_at) WHERE status = 'succeeded';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_histories_search;
+ALTER TABLE histories DROP COLUMN IF EXISTS search_vector;
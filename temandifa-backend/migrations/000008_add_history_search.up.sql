@@ -0,0 +1,12 @@
+-- Full-text search over history results. The generated column keeps the
+-- vector in sync without a trigger; 'simple' is used because entries mix
+-- Indonesian and English, so language-specific stemming would miss matches.
+-- Adding a stored generated column rewrites the table, so apply during a
+-- low-traffic window on large installations.
+ALTER TABLE histories ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple'::regconfig, coalesce(result_text, '')), 'A') ||
+        setweight(to_tsvector('simple'::regconfig, coalesce(input_source, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_histories_search ON histories USING GIN (search_vector);
@@ -0,0 +1,6 @@
+-- Identifier generated on the device for entries recorded offline. Syncing
+-- the same entry twice is a no-op thanks to the unique index; NULLs (entries
+-- created online) never conflict.
+ALTER TABLE histories ADD COLUMN client_id UUID;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_histories_user_client ON histories(user_id, client_id);
@@ -0,0 +1,19 @@
+-- Feature flags: a global switch with a percentage rollout, plus per-user and
+-- per-organization (email domain) overrides that win over the rollout
+CREATE TABLE IF NOT EXISTS feature_flags (
+    name VARCHAR(64) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    rollout_percentage SMALLINT NOT NULL DEFAULT 100 CHECK (rollout_percentage BETWEEN 0 AND 100),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS feature_flag_overrides (
+    flag_name VARCHAR(64) NOT NULL REFERENCES feature_flags(name) ON DELETE CASCADE,
+    subject_type VARCHAR(16) NOT NULL CHECK (subject_type IN ('user', 'organization')),
+    subject VARCHAR(255) NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (flag_name, subject_type, subject)
+);
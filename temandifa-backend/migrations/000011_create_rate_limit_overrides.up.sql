@@ -0,0 +1,11 @@
+-- Per-user rate limit overrides: a multiplier on the per-user limit or a full
+-- exemption (testers, institutional accounts), optionally expiring
+CREATE TABLE IF NOT EXISTS rate_limit_overrides (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    multiplier NUMERIC(6, 2) NOT NULL DEFAULT 1 CHECK (multiplier > 0),
+    exempt BOOLEAN NOT NULL DEFAULT FALSE,
+    reason TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
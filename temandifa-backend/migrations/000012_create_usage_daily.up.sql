@@ -0,0 +1,13 @@
+-- Usage accounting: AI requests per UTC day, user and operation. Rows are
+-- aggregates, so they are kept when the user is purged.
+CREATE TABLE IF NOT EXISTS usage_daily (
+    day DATE NOT NULL,
+    user_id INTEGER NOT NULL,
+    operation VARCHAR(32) NOT NULL,
+    requests BIGINT NOT NULL DEFAULT 0,
+    cache_hits BIGINT NOT NULL DEFAULT 0,
+    errors BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, user_id, operation)
+);
+
+CREATE INDEX IF NOT EXISTS idx_usage_daily_user_day ON usage_daily(user_id, day);
@@ -0,0 +1,21 @@
+-- In-app announcements (maintenance notices, new features) shown from
+-- starts_at until ends_at, with one title/body per locale
+CREATE TABLE IF NOT EXISTS announcements (
+    id BIGSERIAL PRIMARY KEY,
+    kind VARCHAR(16) NOT NULL DEFAULT 'info' CHECK (kind IN ('info', 'feature', 'maintenance')),
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    ends_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (ends_at IS NULL OR ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_announcements_ends_at ON announcements(ends_at);
+
+CREATE TABLE IF NOT EXISTS announcement_translations (
+    announcement_id BIGINT NOT NULL REFERENCES announcements(id) ON DELETE CASCADE,
+    locale VARCHAR(16) NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    body TEXT NOT NULL,
+    PRIMARY KEY (announcement_id, locale)
+);
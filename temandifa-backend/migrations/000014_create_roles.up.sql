@@ -0,0 +1,23 @@
+-- Roles and the permissions attached to them. users.role names a role; the
+-- built-in (system) roles can't be deleted. "*" grants every permission.
+CREATE TABLE IF NOT EXISTS roles (
+    name VARCHAR(32) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    system BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_name VARCHAR(32) NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+    permission VARCHAR(64) NOT NULL,
+    PRIMARY KEY (role_name, permission)
+);
+
+INSERT INTO roles (name, description, system) VALUES
+    ('user', 'App users', TRUE),
+    ('admin', 'Full administrative access', TRUE)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_name, permission) VALUES ('admin', '*')
+ON CONFLICT DO NOTHING;
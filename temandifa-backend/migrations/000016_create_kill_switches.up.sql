@@ -0,0 +1,8 @@
+-- AI operations switched off by an administrator; a row means the operation
+-- is disabled
+CREATE TABLE IF NOT EXISTS kill_switches (
+    operation VARCHAR(32) PRIMARY KEY,
+    reason TEXT NOT NULL DEFAULT '',
+    disabled_by BIGINT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,14 @@
+-- Background jobs triggered by an administrator; the row is polled for the
+-- outcome since the run outlives the request that started it
+CREATE TABLE IF NOT EXISTS job_runs (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(32) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'running',
+    triggered_by BIGINT,
+    result TEXT NOT NULL DEFAULT '{}',
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_name_started_at ON job_runs(name, started_at);
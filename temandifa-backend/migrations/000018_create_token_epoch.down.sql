@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS token_epoch_notify_change ON token_epoch;
+DROP FUNCTION IF EXISTS notify_token_epoch_change();
+DROP TABLE IF EXISTS token_epoch;
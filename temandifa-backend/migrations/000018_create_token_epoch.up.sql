@@ -0,0 +1,26 @@
+-- Generation of access tokens: tokens carrying an older epoch are rejected,
+-- so bumping it logs every user out. Changes are published on the
+-- temandifa_changes channel so every instance applies them at once.
+CREATE TABLE IF NOT EXISTS token_epoch (
+    id SMALLINT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    epoch BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO token_epoch (id, epoch) VALUES (1, 0) ON CONFLICT DO NOTHING;
+
+CREATE OR REPLACE FUNCTION notify_token_epoch_change() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('temandifa_changes', json_build_object(
+        'entity', 'token_epoch',
+        'id', NEW.epoch,
+        'op', 'update'
+    )::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS token_epoch_notify_change ON token_epoch;
+CREATE TRIGGER token_epoch_notify_change
+    AFTER UPDATE ON token_epoch
+    FOR EACH ROW EXECUTE FUNCTION notify_token_epoch_change();
@@ -0,0 +1,6 @@
+-- JSON snapshots of the target before and after the change, from which the
+-- audit detail view derives its field diff. NULL before means the target was
+-- created, NULL after that it was deleted; entries for actions that don't
+-- change a stored target have neither.
+ALTER TABLE audit_logs ADD COLUMN before_state TEXT;
+ALTER TABLE audit_logs ADD COLUMN after_state TEXT;
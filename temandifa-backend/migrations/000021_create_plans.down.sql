@@ -0,0 +1,2 @@
+ALTER TABLE users DROP COLUMN plan;
+DROP TABLE IF EXISTS plans;
@@ -0,0 +1,21 @@
+-- Subscription plans. users.plan names a plan; the set of plans is fixed,
+-- administrators tune their allowances. A daily_ai_quota of 0 means
+-- unlimited; the multipliers scale the AI rate limit and the AI result
+-- cache TTLs for the plan's users.
+CREATE TABLE IF NOT EXISTS plans (
+    name VARCHAR(32) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    daily_ai_quota INTEGER NOT NULL DEFAULT 0 CHECK (daily_ai_quota >= 0),
+    rate_limit_multiplier NUMERIC(6, 2) NOT NULL DEFAULT 1 CHECK (rate_limit_multiplier > 0),
+    cache_ttl_multiplier NUMERIC(6, 2) NOT NULL DEFAULT 1 CHECK (cache_ttl_multiplier > 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO plans (name, description, daily_ai_quota, rate_limit_multiplier, cache_ttl_multiplier) VALUES
+    ('free', 'Default plan', 100, 1, 1),
+    ('plus', 'Paid individual plan', 1000, 2, 2),
+    ('institution', 'Schools, foundations and other organizations', 0, 5, 4)
+ON CONFLICT (name) DO NOTHING;
+
+ALTER TABLE users ADD COLUMN plan VARCHAR(32) NOT NULL DEFAULT 'free' REFERENCES plans(name);
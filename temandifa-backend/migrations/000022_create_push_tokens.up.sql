@@ -0,0 +1,13 @@
+-- Firebase Cloud Messaging registration tokens of the users' devices. A token
+-- moves to whoever signs in on the device last; tokens FCM reports as
+-- unregistered are deleted.
+CREATE TABLE IF NOT EXISTS push_tokens (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token VARCHAR(512) NOT NULL UNIQUE,
+    platform VARCHAR(16) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_push_tokens_user_id ON push_tokens(user_id);
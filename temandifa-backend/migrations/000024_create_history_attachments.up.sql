@@ -0,0 +1,16 @@
+-- Images and recordings attached to history entries. The files live in object
+-- storage; rows and objects are deleted with their entry.
+CREATE TABLE IF NOT EXISTS history_attachments (
+    id BIGSERIAL PRIMARY KEY,
+    history_id BIGINT NOT NULL REFERENCES histories(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(10) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    filename TEXT NOT NULL DEFAULT '',
+    object_key TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_history_attachments_history_id ON history_attachments(history_id);
+CREATE INDEX IF NOT EXISTS idx_history_attachments_user_id ON history_attachments(user_id);
@@ -0,0 +1,2 @@
+ALTER TABLE history_attachments DROP COLUMN IF EXISTS waveform;
+ALTER TABLE history_attachments DROP COLUMN IF EXISTS thumbnail_key;
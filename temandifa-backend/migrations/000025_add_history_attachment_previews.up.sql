@@ -0,0 +1,4 @@
+-- Previews of attachments: thumbnails of images (objects next to the file)
+-- and waveforms of recordings
+ALTER TABLE history_attachments ADD COLUMN IF NOT EXISTS thumbnail_key TEXT NOT NULL DEFAULT '';
+ALTER TABLE history_attachments ADD COLUMN IF NOT EXISTS waveform TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,11 @@
+-- Minimum and latest app version per platform, read by the app's update
+-- prompt and the version gate
+CREATE TABLE IF NOT EXISTS app_versions (
+    platform VARCHAR(16) PRIMARY KEY CHECK (platform IN ('android', 'ios')),
+    min_version VARCHAR(32) NOT NULL,
+    latest_version VARCHAR(32) NOT NULL,
+    store_url TEXT NOT NULL DEFAULT '',
+    changelog TEXT NOT NULL DEFAULT '',
+    updated_by BIGINT,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
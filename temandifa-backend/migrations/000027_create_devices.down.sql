@@ -0,0 +1,20 @@
+DROP INDEX IF EXISTS idx_refresh_tokens_user_device;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS device_id;
+
+CREATE TABLE IF NOT EXISTS push_tokens (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token VARCHAR(512) NOT NULL UNIQUE,
+    platform VARCHAR(16) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_push_tokens_user_id ON push_tokens(user_id);
+
+INSERT INTO push_tokens (user_id, token, platform, created_at, updated_at)
+SELECT user_id, push_token, platform, created_at, updated_at
+FROM devices
+WHERE push_token IS NOT NULL;
+
+DROP TABLE IF EXISTS devices;
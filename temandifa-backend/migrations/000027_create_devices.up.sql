@@ -0,0 +1,25 @@
+-- App installations the users signed in on, replacing push_tokens. A device
+-- has at most one push token, which moves to whoever registers it last, and
+-- the refresh tokens issued on it are bound to its device_id.
+CREATE TABLE IF NOT EXISTS devices (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    device_id VARCHAR(128) NOT NULL,
+    platform VARCHAR(16) NOT NULL,
+    push_token VARCHAR(512) UNIQUE,
+    app_version VARCHAR(32) NOT NULL DEFAULT '',
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, device_id)
+);
+
+-- Tokens registered before devices existed become devices of their own
+INSERT INTO devices (user_id, device_id, platform, push_token, last_seen_at, created_at, updated_at)
+SELECT user_id, 'push-token-' || id, platform, token, updated_at, created_at, updated_at
+FROM push_tokens;
+
+DROP TABLE IF EXISTS push_tokens;
+
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS device_id VARCHAR(128) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_device ON refresh_tokens(user_id, device_id) WHERE revoked = FALSE;
@@ -0,0 +1,17 @@
+-- App settings synced across a user's devices (GET/PUT /me/settings/sync).
+-- Each setting carries the version vector of the edits it reflects; seq
+-- orders the user's changes so devices can fetch only what changed since
+-- their last sync. Deleted settings stay as tombstones so the deletion
+-- reaches every device.
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    key VARCHAR(64) NOT NULL,
+    value TEXT NOT NULL DEFAULT 'null',
+    vector TEXT NOT NULL DEFAULT '{}',
+    deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    seq BIGINT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_settings_user_seq ON user_settings(user_id, seq);
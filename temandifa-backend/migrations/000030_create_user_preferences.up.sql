@@ -0,0 +1,11 @@
+-- Accessibility preferences; AI endpoints use them as defaults for
+-- parameters the request leaves out. Users without a row have the defaults.
+CREATE TABLE IF NOT EXISTS user_preferences (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    tts_voice VARCHAR(64) NOT NULL DEFAULT '',
+    tts_speed REAL NOT NULL DEFAULT 1.0,
+    ocr_language VARCHAR(8) NOT NULL DEFAULT 'en',
+    verbosity VARCHAR(16) NOT NULL DEFAULT 'normal',
+    haptics BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS emergency_alerts;
+DROP INDEX IF EXISTS idx_emergency_contacts_consent_token;
+ALTER TABLE emergency_contacts DROP COLUMN IF EXISTS consent_answered_at;
+ALTER TABLE emergency_contacts DROP COLUMN IF EXISTS consent_requested_at;
+ALTER TABLE emergency_contacts DROP COLUMN IF EXISTS consent_token_hash;
+ALTER TABLE emergency_contacts DROP COLUMN IF EXISTS consent_status;
+ALTER TABLE emergency_contacts DROP COLUMN IF EXISTS email;
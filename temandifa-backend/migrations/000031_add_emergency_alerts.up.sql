@@ -0,0 +1,24 @@
+-- Emergency contacts get an email address and must agree to receive alerts:
+-- they answer a consent request sent to them with a one-time token (stored
+-- hashed). Existing contacts start out pending.
+ALTER TABLE emergency_contacts ADD COLUMN IF NOT EXISTS email VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE emergency_contacts ADD COLUMN IF NOT EXISTS consent_status VARCHAR(16) NOT NULL DEFAULT 'pending';
+ALTER TABLE emergency_contacts ADD COLUMN IF NOT EXISTS consent_token_hash VARCHAR(64);
+ALTER TABLE emergency_contacts ADD COLUMN IF NOT EXISTS consent_requested_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE emergency_contacts ADD COLUMN IF NOT EXISTS consent_answered_at TIMESTAMP WITH TIME ZONE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_emergency_contacts_consent_token ON emergency_contacts(consent_token_hash);
+
+-- Alerts sent with POST /emergency, kept for rate limiting and the user's
+-- records
+CREATE TABLE IF NOT EXISTS emergency_alerts (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    scene_description TEXT NOT NULL DEFAULT '',
+    contacts_notified INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_emergency_alerts_user_created ON emergency_alerts(user_id, created_at);
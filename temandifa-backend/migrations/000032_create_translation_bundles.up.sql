@@ -0,0 +1,14 @@
+-- Versioned translations of app strings and server messages per locale,
+-- uploaded by administrators; the app fetches the latest version of its
+-- locale. Each upload adds a version, older ones are pruned.
+CREATE TABLE IF NOT EXISTS translation_bundles (
+    id BIGSERIAL PRIMARY KEY,
+    locale VARCHAR(16) NOT NULL,
+    version INTEGER NOT NULL,
+    app_strings TEXT NOT NULL DEFAULT '{}',
+    server_strings TEXT NOT NULL DEFAULT '{}',
+    checksum VARCHAR(64) NOT NULL,
+    created_by BIGINT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_translation_bundles_locale_version UNIQUE (locale, version)
+);
@@ -0,0 +1,9 @@
+-- Background health check samples per UTC day and component, counted by
+-- every instance, from which the public status page computes uptime.
+CREATE TABLE IF NOT EXISTS component_uptime_daily (
+    day DATE NOT NULL,
+    component VARCHAR(32) NOT NULL,
+    samples BIGINT NOT NULL DEFAULT 0,
+    healthy_samples BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, component)
+);
@@ -0,0 +1,20 @@
+-- Monthly usage reports: a user's AI requests per operation, cache hits and
+-- stored attachments over one UTC month, compiled once the month has ended.
+-- They back the user's report downloads and billing exports, so like
+-- usage_daily they are kept when the user is purged.
+CREATE TABLE IF NOT EXISTS usage_reports (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    month CHAR(7) NOT NULL,
+    plan VARCHAR(32) NOT NULL DEFAULT '',
+    requests BIGINT NOT NULL DEFAULT 0,
+    cache_hits BIGINT NOT NULL DEFAULT 0,
+    errors BIGINT NOT NULL DEFAULT 0,
+    operations TEXT NOT NULL DEFAULT '{}',
+    storage_bytes BIGINT NOT NULL DEFAULT 0,
+    attachments BIGINT NOT NULL DEFAULT 0,
+    generated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_usage_reports_user_month UNIQUE (user_id, month)
+);
+
+CREATE INDEX IF NOT EXISTS idx_usage_reports_month ON usage_reports(month, user_id);
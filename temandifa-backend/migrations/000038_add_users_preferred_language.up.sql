@@ -0,0 +1,3 @@
+-- The language the user chose for the app and the messages sent to them, as
+-- a BCP 47 tag; empty follows the device's language
+ALTER TABLE users ADD COLUMN IF NOT EXISTS preferred_language VARCHAR(16) NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+-- Bumped whenever a role's permissions change. Access tokens carry the
+-- version of their user's role (rv), so servers holding older cached
+-- permissions reload them.
+ALTER TABLE roles ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
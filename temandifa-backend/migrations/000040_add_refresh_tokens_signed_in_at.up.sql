@@ -0,0 +1,5 @@
+-- When the login session of a refresh token started. Rotation copies it to
+-- the new token, so created_at is when the session was last refreshed.
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS signed_in_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE refresh_tokens SET signed_in_at = created_at WHERE signed_in_at IS NULL;
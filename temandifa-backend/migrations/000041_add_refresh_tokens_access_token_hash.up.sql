@@ -0,0 +1,3 @@
+-- SHA-256 (hex) of the access token issued with the refresh token, so
+-- revoking the session can blacklist it too
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS access_token_hash CHAR(64) NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+DELETE FROM user_identities WHERE provider LIKE 'oidc:%';
+ALTER TABLE user_identities ALTER COLUMN provider TYPE VARCHAR(16);
+ALTER TABLE users DROP COLUMN IF EXISTS organization;
@@ -0,0 +1,5 @@
+-- Institutional sign-in. organization is the organization whose OpenID
+-- Connect provider signed the user in (empty for everyone else); the
+-- providers' identities are named oidc:<provider>, longer than google/apple.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS organization VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE user_identities ALTER COLUMN provider TYPE VARCHAR(64);
@@ -0,0 +1,6 @@
+-- Email address changes. pending_email is the address the user asked to
+-- change to (empty when none is pending); the change is made once the links
+-- sent to the current and the pending address have both been opened.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pending_email VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pending_email_old_confirmed BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pending_email_new_confirmed BOOLEAN NOT NULL DEFAULT FALSE;
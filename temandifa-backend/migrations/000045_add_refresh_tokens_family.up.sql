@@ -0,0 +1,10 @@
+-- Refresh tokens rotated from one another share the family_id of the
+-- session's first token. rotated marks tokens revoked by a rotation: one
+-- presented again was copied, and its whole family is revoked.
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS family_id BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS rotated BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Existing tokens start families of their own
+UPDATE refresh_tokens SET family_id = id WHERE family_id = 0;
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens(family_id);
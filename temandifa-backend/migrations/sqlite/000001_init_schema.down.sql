@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS call_logs;
+DROP TABLE IF EXISTS histories;
+DROP TABLE IF EXISTS emergency_contacts;
+DROP TABLE IF EXISTS users;
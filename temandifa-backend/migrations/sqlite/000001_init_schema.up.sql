@@ -0,0 +1,86 @@
+-- SQLite variant of the initial schema (local development and tests).
+-- Keep in sync with ../000001_init_schema.up.sql.
+
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    email VARCHAR(255) NOT NULL,
+    password VARCHAR(255),
+    full_name VARCHAR(255),
+    profile_picture VARCHAR(255),
+    role VARCHAR(255) DEFAULT 'user'
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users(email);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_user_role ON users(role);
+
+CREATE TABLE IF NOT EXISTS emergency_contacts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    user_id INTEGER REFERENCES users(id) ON UPDATE CASCADE ON DELETE SET NULL,
+    name VARCHAR(255),
+    phone_number VARCHAR(255),
+    relation VARCHAR(255),
+    is_primary BOOLEAN
+);
+
+CREATE INDEX IF NOT EXISTS idx_emergency_contacts_user_id ON emergency_contacts(user_id);
+CREATE INDEX IF NOT EXISTS idx_emergency_contacts_deleted_at ON emergency_contacts(deleted_at);
+
+CREATE TABLE IF NOT EXISTS histories (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    user_id INTEGER REFERENCES users(id) ON UPDATE CASCADE ON DELETE SET NULL,
+    feature_type VARCHAR(20),
+    input_source VARCHAR(255),
+    result_text TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_histories_user_id ON histories(user_id);
+CREATE INDEX IF NOT EXISTS idx_histories_deleted_at ON histories(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_history_feature ON histories(feature_type);
+CREATE INDEX IF NOT EXISTS idx_history_user_created ON histories(user_id, created_at);
+
+CREATE TABLE IF NOT EXISTS call_logs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    caller_id INTEGER REFERENCES users(id) ON UPDATE CASCADE ON DELETE SET NULL,
+    receiver_id INTEGER REFERENCES users(id) ON UPDATE CASCADE ON DELETE SET NULL,
+    room_id VARCHAR(255),
+    status VARCHAR(20) DEFAULT 'INITIATED',
+    start_time DATETIME,
+    end_time DATETIME,
+    duration INTEGER
+);
+
+CREATE INDEX IF NOT EXISTS idx_call_logs_caller_id ON call_logs(caller_id);
+CREATE INDEX IF NOT EXISTS idx_call_logs_receiver_id ON call_logs(receiver_id);
+CREATE INDEX IF NOT EXISTS idx_call_logs_room_id ON call_logs(room_id);
+CREATE INDEX IF NOT EXISTS idx_call_logs_deleted_at ON call_logs(deleted_at);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    token VARCHAR(512) NOT NULL,
+    expires_at DATETIME NOT NULL,
+    revoked BOOLEAN DEFAULT FALSE,
+    revoked_at DATETIME,
+    user_agent VARCHAR(500),
+    ip_address VARCHAR(45)
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens(user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refresh_tokens_token ON refresh_tokens(token);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_deleted_at ON refresh_tokens(deleted_at);
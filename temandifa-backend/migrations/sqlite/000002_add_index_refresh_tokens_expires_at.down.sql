@@ -0,0 +1,2 @@
+-- Drop index if exists to rollback changes
+DROP INDEX IF EXISTS idx_refresh_tokens_expires_at;
@@ -0,0 +1 @@
+-- LISTEN/NOTIFY is Postgres-only; kept so versions line up with ../
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_refresh_tokens_user_active;
+DROP INDEX IF EXISTS idx_refresh_tokens_revoked_at;
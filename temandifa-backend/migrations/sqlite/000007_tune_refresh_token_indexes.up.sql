@@ -0,0 +1,8 @@
+-- Revoked tokens are cleaned up once revoked_at passes the retention window.
+-- The partial index only covers revoked rows, so it stays small even though
+-- every rotation revokes a token.
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_revoked_at ON refresh_tokens(revoked_at) WHERE revoked = TRUE;
+
+-- RevokeAllUserTokens only touches live tokens; this replaces scanning every
+-- token the user ever had through idx_refresh_tokens_user_id
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_active ON refresh_tokens(user_id) WHERE revoked = FALSE;
@@ -0,0 +1 @@
+-- Full-text search is Postgres-only; kept so versions line up with ../
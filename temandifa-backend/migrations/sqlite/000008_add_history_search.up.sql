@@ -0,0 +1,2 @@
+-- Full-text search is Postgres-only (HistoryRepository.Search falls back to
+-- LIKE on SQLite); kept so versions line up with ../
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_histories_user_client;
+ALTER TABLE histories DROP COLUMN client_id;
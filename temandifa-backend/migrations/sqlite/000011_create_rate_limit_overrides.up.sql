@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS rate_limit_overrides (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    multiplier REAL NOT NULL DEFAULT 1 CHECK (multiplier > 0),
+    exempt BOOLEAN NOT NULL DEFAULT FALSE,
+    reason TEXT NOT NULL DEFAULT '',
+    expires_at DATETIME,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS usage_daily (
+    day DATE NOT NULL,
+    user_id INTEGER NOT NULL,
+    operation VARCHAR(32) NOT NULL,
+    requests INTEGER NOT NULL DEFAULT 0,
+    cache_hits INTEGER NOT NULL DEFAULT 0,
+    errors INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, user_id, operation)
+);
+
+CREATE INDEX IF NOT EXISTS idx_usage_daily_user_day ON usage_daily(user_id, day);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS announcements (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind VARCHAR(16) NOT NULL DEFAULT 'info' CHECK (kind IN ('info', 'feature', 'maintenance')),
+    starts_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ends_at DATETIME,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_announcements_ends_at ON announcements(ends_at);
+
+CREATE TABLE IF NOT EXISTS announcement_translations (
+    announcement_id INTEGER NOT NULL REFERENCES announcements(id) ON DELETE CASCADE,
+    locale VARCHAR(16) NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    body TEXT NOT NULL,
+    PRIMARY KEY (announcement_id, locale)
+);
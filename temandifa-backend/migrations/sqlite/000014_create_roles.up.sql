@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS roles (
+    name VARCHAR(32) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    system BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_name VARCHAR(32) NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+    permission VARCHAR(64) NOT NULL,
+    PRIMARY KEY (role_name, permission)
+);
+
+INSERT OR IGNORE INTO roles (name, description, system) VALUES
+    ('user', 'App users', TRUE),
+    ('admin', 'Full administrative access', TRUE);
+
+INSERT OR IGNORE INTO role_permissions (role_name, permission) VALUES ('admin', '*');
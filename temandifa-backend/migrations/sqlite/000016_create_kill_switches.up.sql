@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS kill_switches (
+    operation VARCHAR(32) PRIMARY KEY,
+    reason TEXT NOT NULL DEFAULT '',
+    disabled_by INTEGER,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
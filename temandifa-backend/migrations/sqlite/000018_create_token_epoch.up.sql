@@ -0,0 +1,8 @@
+-- No NOTIFY on SQLite: instances reload the epoch every TOKEN_EPOCH_REFRESH_INTERVAL
+CREATE TABLE IF NOT EXISTS token_epoch (
+    id INTEGER PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    epoch BIGINT NOT NULL DEFAULT 0,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT OR IGNORE INTO token_epoch (id, epoch) VALUES (1, 0);
@@ -0,0 +1,2 @@
+ALTER TABLE audit_logs DROP COLUMN after_state;
+ALTER TABLE audit_logs DROP COLUMN before_state;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS plans (
+    name VARCHAR(32) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    daily_ai_quota INTEGER NOT NULL DEFAULT 0 CHECK (daily_ai_quota >= 0),
+    rate_limit_multiplier REAL NOT NULL DEFAULT 1 CHECK (rate_limit_multiplier > 0),
+    cache_ttl_multiplier REAL NOT NULL DEFAULT 1 CHECK (cache_ttl_multiplier > 0),
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT OR IGNORE INTO plans (name, description, daily_ai_quota, rate_limit_multiplier, cache_ttl_multiplier) VALUES
+    ('free', 'Default plan', 100, 1, 1),
+    ('plus', 'Paid individual plan', 1000, 2, 2),
+    ('institution', 'Schools, foundations and other organizations', 0, 5, 4);
+
+ALTER TABLE users ADD COLUMN plan VARCHAR(32) NOT NULL DEFAULT 'free';
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS history_attachments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    history_id INTEGER NOT NULL REFERENCES histories(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(10) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes INTEGER NOT NULL,
+    filename TEXT NOT NULL DEFAULT '',
+    object_key TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_history_attachments_history_id ON history_attachments(history_id);
+CREATE INDEX IF NOT EXISTS idx_history_attachments_user_id ON history_attachments(user_id);
@@ -0,0 +1,2 @@
+ALTER TABLE history_attachments DROP COLUMN waveform;
+ALTER TABLE history_attachments DROP COLUMN thumbnail_key;
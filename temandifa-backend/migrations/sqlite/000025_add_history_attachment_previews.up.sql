@@ -0,0 +1,2 @@
+ALTER TABLE history_attachments ADD COLUMN thumbnail_key TEXT NOT NULL DEFAULT '';
+ALTER TABLE history_attachments ADD COLUMN waveform TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS app_versions (
+    platform VARCHAR(16) PRIMARY KEY CHECK (platform IN ('android', 'ios')),
+    min_version VARCHAR(32) NOT NULL,
+    latest_version VARCHAR(32) NOT NULL,
+    store_url TEXT NOT NULL DEFAULT '',
+    changelog TEXT NOT NULL DEFAULT '',
+    updated_by INTEGER,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
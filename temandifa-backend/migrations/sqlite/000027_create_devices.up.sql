@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS devices (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    device_id VARCHAR(128) NOT NULL,
+    platform VARCHAR(16) NOT NULL,
+    push_token VARCHAR(512) UNIQUE,
+    app_version VARCHAR(32) NOT NULL DEFAULT '',
+    last_seen_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, device_id)
+);
+
+INSERT INTO devices (user_id, device_id, platform, push_token, last_seen_at, created_at, updated_at)
+SELECT user_id, 'push-token-' || id, platform, token, updated_at, created_at, updated_at
+FROM push_tokens;
+
+DROP TABLE IF EXISTS push_tokens;
+
+ALTER TABLE refresh_tokens ADD COLUMN device_id VARCHAR(128) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_device ON refresh_tokens(user_id, device_id) WHERE revoked = FALSE;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    key VARCHAR(64) NOT NULL,
+    value TEXT NOT NULL DEFAULT 'null',
+    vector TEXT NOT NULL DEFAULT '{}',
+    deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    seq INTEGER NOT NULL,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_settings_user_seq ON user_settings(user_id, seq);
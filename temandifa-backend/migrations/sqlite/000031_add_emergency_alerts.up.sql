@@ -0,0 +1,19 @@
+ALTER TABLE emergency_contacts ADD COLUMN email VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE emergency_contacts ADD COLUMN consent_status VARCHAR(16) NOT NULL DEFAULT 'pending';
+ALTER TABLE emergency_contacts ADD COLUMN consent_token_hash VARCHAR(64);
+ALTER TABLE emergency_contacts ADD COLUMN consent_requested_at DATETIME;
+ALTER TABLE emergency_contacts ADD COLUMN consent_answered_at DATETIME;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_emergency_contacts_consent_token ON emergency_contacts(consent_token_hash);
+
+CREATE TABLE IF NOT EXISTS emergency_alerts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    latitude REAL,
+    longitude REAL,
+    scene_description TEXT NOT NULL DEFAULT '',
+    contacts_notified INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_emergency_alerts_user_created ON emergency_alerts(user_id, created_at);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS translation_bundles (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    locale VARCHAR(16) NOT NULL,
+    version INTEGER NOT NULL,
+    app_strings TEXT NOT NULL DEFAULT '{}',
+    server_strings TEXT NOT NULL DEFAULT '{}',
+    checksum VARCHAR(64) NOT NULL,
+    created_by INTEGER,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (locale, version)
+);
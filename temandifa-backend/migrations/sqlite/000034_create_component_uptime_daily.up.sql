@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS component_uptime_daily (
+    day DATE NOT NULL,
+    component VARCHAR(32) NOT NULL,
+    samples INTEGER NOT NULL DEFAULT 0,
+    healthy_samples INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, component)
+);
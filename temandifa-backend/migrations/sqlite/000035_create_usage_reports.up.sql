@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS usage_reports (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    month CHAR(7) NOT NULL,
+    plan VARCHAR(32) NOT NULL DEFAULT '',
+    requests INTEGER NOT NULL DEFAULT 0,
+    cache_hits INTEGER NOT NULL DEFAULT 0,
+    errors INTEGER NOT NULL DEFAULT 0,
+    operations TEXT NOT NULL DEFAULT '{}',
+    storage_bytes INTEGER NOT NULL DEFAULT 0,
+    attachments INTEGER NOT NULL DEFAULT 0,
+    generated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, month)
+);
+
+CREATE INDEX IF NOT EXISTS idx_usage_reports_month ON usage_reports(month, user_id);
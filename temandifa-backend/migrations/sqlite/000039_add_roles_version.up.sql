@@ -0,0 +1 @@
+ALTER TABLE roles ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,3 @@
+ALTER TABLE refresh_tokens ADD COLUMN signed_in_at DATETIME;
+
+UPDATE refresh_tokens SET signed_in_at = created_at WHERE signed_in_at IS NULL;
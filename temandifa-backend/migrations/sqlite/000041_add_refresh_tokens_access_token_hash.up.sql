@@ -0,0 +1 @@
+ALTER TABLE refresh_tokens ADD COLUMN access_token_hash CHAR(64) NOT NULL DEFAULT '';
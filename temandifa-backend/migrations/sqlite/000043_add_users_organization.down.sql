@@ -0,0 +1,2 @@
+DELETE FROM user_identities WHERE provider LIKE 'oidc:%';
+ALTER TABLE users DROP COLUMN organization;
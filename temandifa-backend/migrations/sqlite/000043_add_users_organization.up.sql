@@ -0,0 +1,3 @@
+-- SQLite doesn't enforce VARCHAR lengths, so user_identities.provider needs
+-- no change
+ALTER TABLE users ADD COLUMN organization VARCHAR(64) NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+ALTER TABLE users DROP COLUMN pending_email_new_confirmed;
+ALTER TABLE users DROP COLUMN pending_email_old_confirmed;
+ALTER TABLE users DROP COLUMN pending_email;
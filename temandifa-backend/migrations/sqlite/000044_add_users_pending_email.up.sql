@@ -0,0 +1,3 @@
+ALTER TABLE users ADD COLUMN pending_email VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN pending_email_old_confirmed BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN pending_email_new_confirmed BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_refresh_tokens_family_id;
+ALTER TABLE refresh_tokens DROP COLUMN rotated;
+ALTER TABLE refresh_tokens DROP COLUMN family_id;
@@ -0,0 +1,6 @@
+ALTER TABLE refresh_tokens ADD COLUMN family_id INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE refresh_tokens ADD COLUMN rotated BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE refresh_tokens SET family_id = id WHERE family_id = 0;
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens(family_id);